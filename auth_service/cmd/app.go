@@ -4,13 +4,23 @@ import (
 	"apple_backend/auth_service/internal/config"
 	authhttp "apple_backend/auth_service/internal/delivery/http"
 	authmw "apple_backend/auth_service/internal/delivery/middlewares"
+	"apple_backend/auth_service/internal/infrastructure/mailer"
+	"apple_backend/auth_service/internal/infrastructure/sms"
 	"apple_backend/auth_service/internal/repository"
 	"apple_backend/auth_service/internal/usecase"
+	"apple_backend/pkg/csrf"
+	"apple_backend/pkg/guestcart"
+	"apple_backend/pkg/jwtkeys"
 	"apple_backend/pkg/logger"
+	"apple_backend/pkg/oidc"
+	"apple_backend/pkg/passcheck"
+	"apple_backend/pkg/passhash"
 	"context"
 	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -23,6 +33,97 @@ func csrfHandler(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+func newMailer(conf *config.Config) (usecase.Mailer, error) {
+	switch conf.MailDriver {
+	case "smtp":
+		return mailer.NewSMTPMailer(conf.SMTPHost, conf.SMTPPort, conf.SMTPUser, conf.SMTPPassword, conf.MailFrom), nil
+	case "file":
+		return mailer.NewFileMailerFromPath(conf.MailFile, conf.MailFrom)
+	case "stdout":
+		return mailer.NewFileMailer(os.Stdout, conf.MailFrom), nil
+	default:
+		return nil, fmt.Errorf("unknown MAIL_DRIVER %q", conf.MailDriver)
+	}
+}
+
+// newSMSSender возвращает nil, если вход по телефону выключен
+func newSMSSender(conf *config.Config) (usecase.SMSSender, error) {
+	switch conf.SMSDriver {
+	case "none":
+		return nil, nil
+	case "file":
+		return sms.NewFileSenderFromPath(conf.SMSFile)
+	case "stdout":
+		return sms.NewFileSender(os.Stdout), nil
+	default:
+		return nil, fmt.Errorf("unknown SMS_DRIVER %q", conf.SMSDriver)
+	}
+}
+
+func newExternalProviders(conf *config.Config) (map[string]usecase.ExternalProvider, error) {
+	providers := make(map[string]usecase.ExternalProvider, len(conf.OIDCProviders))
+	for _, p := range conf.OIDCProviders {
+		if p.Issuer == "" || p.ClientID == "" {
+			return nil, fmt.Errorf("OIDC provider %q: issuer and client id are required", p.Name)
+		}
+		providers[p.Name] = oidc.NewProvider(oidc.Config{
+			Issuer:       p.Issuer,
+			ClientID:     p.ClientID,
+			ClientSecret: p.ClientSecret,
+			RedirectURL:  p.RedirectURL,
+			Scopes:       p.Scopes,
+		})
+	}
+	return providers, nil
+}
+
+func newKeySet(conf *config.Config) (*jwtkeys.KeySet, error) {
+	if conf.JWTKeysDir == "" {
+		log.Println("JWT_KEYS_DIR is not set, using ephemeral signing key")
+		return jwtkeys.GenerateKeySet()
+	}
+	return jwtkeys.LoadKeySet(conf.JWTKeysDir, conf.JWTActiveKID)
+}
+
+// newPasswordHasher хэширует новые пароли выбранным алгоритмом, второй
+// алгоритм остается для проверки уже сохраненных хэшей.
+func newPasswordHasher(conf *config.Config) (*passhash.Hasher, error) {
+	argon := passhash.NewArgon2id(passhash.Argon2idParams{
+		Memory:      uint32(conf.Argon2MemoryKiB),
+		Iterations:  uint32(conf.Argon2Iterations),
+		Parallelism: uint8(conf.Argon2Parallelism),
+	})
+	bcrypt := passhash.NewBcrypt(conf.BcryptCost)
+	switch conf.PasswordHashAlgorithm {
+	case "argon2id":
+		return passhash.New(argon, bcrypt), nil
+	case "bcrypt":
+		return passhash.New(bcrypt, argon), nil
+	default:
+		return nil, fmt.Errorf("unknown PASSWORD_HASH_ALGORITHM %q", conf.PasswordHashAlgorithm)
+	}
+}
+
+type cleaner interface {
+	CleanupThrottle(ctx context.Context) error
+	CleanupPhoneCodes(ctx context.Context) error
+}
+
+// cleanupThrottle раз в час удаляет устаревшие счетчики попыток входа и
+// истекшие коды из SMS
+func cleanupThrottle(uc cleaner) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+	for range ticker.C {
+		if err := uc.CleanupThrottle(context.Background()); err != nil {
+			log.Println("throttle cleanup failed:", err)
+		}
+		if err := uc.CleanupPhoneCodes(context.Background()); err != nil {
+			log.Println("phone codes cleanup failed:", err)
+		}
+	}
+}
+
 func Run() {
 	conf := config.LoadConfig()
 
@@ -33,21 +134,85 @@ func Run() {
 	defer dbPool.Close()
 
 	repo := repository.NewAuthRepoPostgres(dbPool)
-	uc := usecase.NewAuthUseCase(repo, conf.SecretKeyStr())
+	sessionRepo := repository.NewSessionRepoPostgres(dbPool)
+	mail, err := newMailer(conf)
+	if err != nil {
+		log.Fatal(err)
+	}
+	keys, err := newKeySet(conf)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Println("Signing tokens with key", keys.ActiveKID())
+	passwords, err := newPasswordHasher(conf)
+	if err != nil {
+		log.Fatal(err)
+	}
+	smsSender, err := newSMSSender(conf)
+	if err != nil {
+		log.Fatal(err)
+	}
+	externalProviders, err := newExternalProviders(conf)
+	if err != nil {
+		log.Fatal(err)
+	}
+	throttleRepo := repository.NewThrottleRepoPostgres(dbPool)
+	uc := usecase.NewAuthUseCase(repo, sessionRepo, throttleRepo, mail, keys, usecase.Config{
+		AppURL:         conf.AppURL,
+		TwoFactorRoles: conf.TwoFactorRoles,
+		TOTPIssuer:     conf.TOTPIssuer,
+		TOTPKey:        conf.TOTPEncryptionKey,
+		Passwords:      passwords,
+
+		BreachedPasswords: passcheck.NewBreachList(conf.BreachedPasswordsDir),
+		PasswordMinScore:  conf.PasswordMinScore,
+		SMS:               smsSender,
+		ExternalProviders: externalProviders,
+		Events:            repository.NewAuthEventRepoPostgres(dbPool),
+
+		Credentials: repository.NewCredentialsRepoPostgres(dbPool),
+		TwoFactor:   repository.NewTwoFactorRepoPostgres(dbPool),
+		Roles:       repository.NewRoleRepoPostgres(dbPool),
+		Phones:      repository.NewPhoneRepoPostgres(dbPool),
+		External:    repository.NewExternalRepoPostgres(dbPool),
+		APIKeys:     repository.NewAPIKeyRepoPostgres(dbPool),
+	})
+
+	if _, err := uc.EncryptTOTPSecrets(context.Background()); err != nil {
+		log.Fatal(err)
+	}
+	if conf.BootstrapAdminEmail != "" {
+		if err := uc.BootstrapAdmin(context.Background(), conf.BootstrapAdminEmail); err != nil {
+			log.Fatal(err)
+		}
+	}
+	go cleanupThrottle(uc)
+
+	csrfProtector := csrf.New(conf.CSRFSecret, csrf.OptionsFromEnv())
 
 	authMux := http.NewServeMux()
 	authMux.Handle("/csrf", http.HandlerFunc(csrfHandler))
-	authhttp.NewAuthRouter(authMux, "/auth", uc)
+	guestCarts := authhttp.NewGuestCarts(guestcart.New(conf.GuestCartSecret), guestcart.NewPostgresMerger(dbPool))
+	authhttp.NewAuthRouter(authMux, "/auth", uc, csrfProtector, guestCarts)
 
-	authHandler := authmw.CSRFTokenMiddleware(
-		authmw.CSRFMiddleware(authMux),
-	)
+	authHandler := csrfProtector.Middleware(authMux)
 
 	mainMux := http.NewServeMux()
 	mainMux.Handle("/api/v0/", http.StripPrefix("/api/v0", authHandler))
+	mainMux.Handle("/.well-known/jwks.json", authhttp.NewJWKSHandler(keys))
+	if conf.IntrospectSecret != "" {
+		// регистрируется мимо CSRF-цепочки: клиенты — сервисы, а не браузер
+		mainMux.Handle("/api/v0/auth/introspect", authhttp.NewIntrospectHandler(uc, conf.IntrospectSecret))
+	} else {
+		log.Println("INTROSPECT_SECRET is not set, token introspection is disabled")
+	}
 
+	proxies, err := authmw.ParseTrustedProxies(conf.TrustedProxies)
+	if err != nil {
+		log.Fatal(err)
+	}
 	handler := authmw.CorsMiddleware(
-		authmw.AccessLog(logger.Global(), mainMux),
+		authmw.AccessLog(logger.Global(), proxies, mainMux),
 	)
 
 	addr := fmt.Sprintf("0.0.0.0:%s", conf.AppPortStr())
@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 )
 
@@ -15,27 +16,150 @@ type Config struct {
 
 	AppPort string
 
-	SecretKey      string
+	// TrustedProxies — IP и подсети обратных прокси из TRUSTED_PROXIES. Только
+	// от них принимаются X-Forwarded-For и X-Real-IP; пустой — заголовки
+	// игнорируются, клиентом считается адрес соединения.
+	TrustedProxies []string
+
 	CSRFSecret     string
 	AllowedOrigins string
 	CookieSecure   bool
 	CookieSameSite string
+
+	// JWTKeysDir — каталог с закрытыми ключами подписи <kid>.pem. Если не
+	// задан, при старте генерируется временный ключ (только для разработки).
+	JWTKeysDir string
+	// JWTActiveKID — ключ, которым подписываются новые токены. Остальные
+	// ключи каталога только публикуются в JWKS для проверки.
+	JWTActiveKID string
+
+	// TwoFactorRoles — роли, которым вход разрешен только со вторым фактором
+	TwoFactorRoles []string
+	TOTPIssuer     string
+	// TOTPEncryptionKey — ключ, которым секреты TOTP шифруются в базе. При
+	// смене ключа включенная 2FA перестанет работать.
+	TOTPEncryptionKey string
+
+	// BootstrapAdminEmail — аккаунт, которому при старте выдается роль admin
+	BootstrapAdminEmail string
+
+	// IntrospectSecret — общий с другими сервисами секрет для
+	// POST /auth/introspect. Пустой — эндпоинт выключен.
+	IntrospectSecret string
+
+	// GuestCartSecret — общий со store_service ключ подписи cookie гостевой
+	// корзины, которая сливается с корзиной пользователя при входе
+	GuestCartSecret string
+
+	// PasswordHashAlgorithm — алгоритм для новых хэшей паролей: argon2id
+	// или bcrypt. Хэши другого алгоритма продолжают проверяться и
+	// пересчитываются при входе.
+	PasswordHashAlgorithm string
+	Argon2MemoryKiB       int
+	Argon2Iterations      int
+	Argon2Parallelism     int
+	BcryptCost            int
+
+	// PasswordMinScore — минимальная оценка стойкости нового пароля (0..4)
+	PasswordMinScore int
+	// BreachedPasswordsDir — каталог range-файлов Have I Been Pwned в
+	// дополнение к встроенному списку утекших паролей
+	BreachedPasswordsDir string
+
+	// AppURL — адрес фронтенда для ссылок в письмах
+	AppURL string
+
+	// MailDriver: smtp, file или stdout
+	MailDriver   string
+	MailFrom     string
+	MailFile     string
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUser     string
+	SMTPPassword string
+
+	// SMSDriver: stdout, file или none (вход по телефону выключен)
+	SMSDriver string
+	SMSFile   string
+
+	// OIDCProviders — провайдеры входа из OIDC_PROVIDERS (например, vk,yandex)
+	OIDCProviders []OIDCProvider
+}
+
+// OIDCProvider читается из OIDC_<NAME>_ISSUER, _CLIENT_ID, _CLIENT_SECRET,
+// _SCOPES и _REDIRECT_URL. По умолчанию провайдер возвращает пользователя на
+// страницу фронтенда /oauth/<name>/callback.
+type OIDCProvider struct {
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
 }
 
 func LoadConfig() *Config {
-	return &Config{
+	conf := &Config{
 		DBUser:         getEnv("DB_USER", "postgres"),
 		DBPassword:     getEnv("DB_PASSWORD", "postgres"),
 		DBHost:         getEnv("DB_HOST", "localhost"),
 		DBPort:         getEnv("API_DB_PORT", "5432"),
 		DBName:         getEnv("DB_NAME", "postgres"),
 		AppPort:        getEnv("AUTH_PORT", "8082"),
-		SecretKey:      getEnv("SECRET_KEY", "dev-secret"),
-		CSRFSecret:     getEnv("CSRF_SECRET", "dev-csrf-secret"),
+		TrustedProxies: splitList(getEnv("TRUSTED_PROXIES", "")),
+		CSRFSecret:     mustEnv("CSRF_SECRET"),
 		AllowedOrigins: getEnv("ALLOWED_ORIGINS", "http://localhost:3000,http://127.0.0.1:3000"),
 		CookieSecure:   parseBool(getEnv("COOKIE_SECURE", "false")),
 		CookieSameSite: strings.ToLower(getEnv("COOKIE_SAMESITE", "lax")),
+		JWTKeysDir:     getEnv("JWT_KEYS_DIR", ""),
+		JWTActiveKID:   getEnv("JWT_ACTIVE_KID", ""),
+		TwoFactorRoles: splitList(getEnv("TWO_FACTOR_REQUIRED_ROLES", "")),
+		TOTPIssuer:     getEnv("TOTP_ISSUER", "Delivery Club"),
+		AppURL:         getEnv("APP_URL", "http://localhost:3000"),
+		MailDriver:     strings.ToLower(getEnv("MAIL_DRIVER", "stdout")),
+		MailFrom:       getEnv("MAIL_FROM", "noreply@localhost"),
+		MailFile:       getEnv("MAIL_FILE", ""),
+		SMTPHost:       getEnv("SMTP_HOST", "localhost"),
+		SMTPPort:       getEnv("SMTP_PORT", "587"),
+		SMTPUser:       getEnv("SMTP_USER", ""),
+		SMTPPassword:   getEnv("SMTP_PASSWORD", ""),
+
+		TOTPEncryptionKey: mustEnv("TOTP_ENCRYPTION_KEY"),
+
+		BootstrapAdminEmail: getEnv("BOOTSTRAP_ADMIN_EMAIL", ""),
+		IntrospectSecret:    getEnv("INTROSPECT_SECRET", ""),
+		GuestCartSecret:     mustEnv("GUEST_CART_SECRET"),
+
+		PasswordHashAlgorithm: strings.ToLower(getEnv("PASSWORD_HASH_ALGORITHM", "argon2id")),
+		Argon2MemoryKiB:       parseInt(getEnv("ARGON2_MEMORY_KIB", "65536")),
+		Argon2Iterations:      parseInt(getEnv("ARGON2_ITERATIONS", "3")),
+		Argon2Parallelism:     parseInt(getEnv("ARGON2_PARALLELISM", "2")),
+		BcryptCost:            parseInt(getEnv("BCRYPT_COST", "10")),
+
+		PasswordMinScore:     parseInt(getEnv("PASSWORD_MIN_SCORE", "3")),
+		BreachedPasswordsDir: getEnv("BREACHED_PASSWORDS_DIR", ""),
+
+		SMSDriver: strings.ToLower(getEnv("SMS_DRIVER", "stdout")),
+		SMSFile:   getEnv("SMS_FILE", ""),
+	}
+	conf.OIDCProviders = loadOIDCProviders(conf.AppURL)
+	return conf
+}
+
+func loadOIDCProviders(appURL string) []OIDCProvider {
+	var providers []OIDCProvider
+	for _, name := range splitList(strings.ToLower(getEnv("OIDC_PROVIDERS", ""))) {
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+		providers = append(providers, OIDCProvider{
+			Name:         name,
+			Issuer:       getEnv(prefix+"ISSUER", ""),
+			ClientID:     getEnv(prefix+"CLIENT_ID", ""),
+			ClientSecret: getEnv(prefix+"CLIENT_SECRET", ""),
+			RedirectURL:  getEnv(prefix+"REDIRECT_URL", strings.TrimRight(appURL, "/")+"/oauth/"+name+"/callback"),
+			Scopes:       strings.Fields(strings.ReplaceAll(getEnv(prefix+"SCOPES", "email profile"), ",", " ")),
+		})
 	}
+	return providers
 }
 
 func (c *Config) DBPath() string {
@@ -45,8 +169,7 @@ func (c *Config) DBPath() string {
 	)
 }
 
-func (c *Config) AppPortStr() string   { return c.AppPort }
-func (c *Config) SecretKeyStr() string { return c.SecretKey }
+func (c *Config) AppPortStr() string { return c.AppPort }
 
 func getEnv(key, def string) string {
 	if v := os.Getenv(key); v != "" {
@@ -55,6 +178,16 @@ func getEnv(key, def string) string {
 	return def
 }
 
+// mustEnv читает обязательную переменную: общий с другими сервисами секрет
+// не должен молча подменяться значением по умолчанию
+func mustEnv(key string) string {
+	v := os.Getenv(key)
+	if v == "" {
+		panic(fmt.Sprintf("Некорректно заполнен файл .env: не задан %s", key))
+	}
+	return v
+}
+
 func parseBool(v string) bool {
 	switch strings.ToLower(strings.TrimSpace(v)) {
 	case "1", "true", "t", "yes", "y", "on":
@@ -63,3 +196,21 @@ func parseBool(v string) bool {
 		return false
 	}
 }
+
+func parseInt(v string) int {
+	n, err := strconv.Atoi(strings.TrimSpace(v))
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+func splitList(v string) []string {
+	var out []string
+	for _, s := range strings.Split(v, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,110 @@
+package http
+
+import (
+	"apple_backend/auth_service/internal/delivery/transport"
+	"apple_backend/auth_service/internal/domain"
+	"apple_backend/pkg/logger"
+	"log/slog"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+func (h *AuthHandler) apiKeyError(w http.ResponseWriter, r *http.Request, op string, err error) {
+	ctx := r.Context()
+	switch err {
+	case domain.ErrAPIKeyNotFound:
+		h.rs.Error(ctx, w, http.StatusNotFound, op, err, nil)
+	case domain.ErrAPIKeyInvalidName, domain.ErrAPIKeyInvalidExpiry, domain.ErrUnknownScope:
+		h.rs.Error(ctx, w, http.StatusBadRequest, op, err, nil)
+	case domain.ErrScopeNotAllowed:
+		h.rs.Error(ctx, w, http.StatusForbidden, op, err, nil)
+	case domain.ErrTooManyAPIKeys:
+		h.rs.Error(ctx, w, http.StatusConflict, op, err, nil)
+	default:
+		h.rs.Error(ctx, w, http.StatusInternalServerError, op, domain.ErrInternalServer, err)
+	}
+}
+
+// APIKeys — GET список действующих ключей, POST выпуск нового
+func (h *AuthHandler) APIKeys(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "handler APIKeys start")
+
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		log.WarnContext(ctx, "handler APIKeys wrong method")
+		h.rs.Error(ctx, w, http.StatusMethodNotAllowed, "APIKeys", domain.ErrHTTPMethod, nil)
+		return
+	}
+
+	claims, err := h.authenticate(r)
+	if err != nil {
+		log.WarnContext(ctx, "handler APIKeys unauthorized", slog.Any("err", err))
+		h.rs.Error(ctx, w, http.StatusUnauthorized, "APIKeys", domain.ErrUnauthorized, nil)
+		return
+	}
+
+	if r.Method == http.MethodPost {
+		var req transport.CreateAPIKeyRequest
+		if !h.decodeJSON(w, r, "CreateAPIKey", &req) {
+			return
+		}
+		key, token, err := h.uc.CreateAPIKey(ctx, claims.UserID, claims.Roles, req.Name, req.Scopes, req.ExpiresInDays)
+		if err != nil {
+			log.ErrorContext(ctx, "usecase CreateAPIKey failed", slog.Any("err", err))
+			h.apiKeyError(w, r, "CreateAPIKey", err)
+			return
+		}
+		w.Header().Set("Cache-Control", "no-store")
+		h.rs.Send(ctx, w, http.StatusCreated, transport.APIKeyCreated{APIKeyInfo: *transport.ToAPIKeyInfo(key), Key: token})
+		log.InfoContext(ctx, "handler CreateAPIKey success", slog.String("key_id", key.ID))
+		return
+	}
+
+	keys, err := h.uc.ListAPIKeys(ctx, claims.UserID)
+	if err != nil {
+		log.ErrorContext(ctx, "usecase ListAPIKeys failed", slog.Any("err", err))
+		h.apiKeyError(w, r, "APIKeys", err)
+		return
+	}
+
+	h.rs.Send(ctx, w, http.StatusOK, transport.ToAPIKeysResponse(keys))
+	log.InfoContext(ctx, "handler APIKeys success", slog.Int("count", len(keys)))
+}
+
+// DeleteAPIKey — DELETE отзыв ключа
+func (h *AuthHandler) DeleteAPIKey(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "handler DeleteAPIKey start")
+
+	if r.Method != http.MethodDelete {
+		log.WarnContext(ctx, "handler DeleteAPIKey wrong method")
+		h.rs.Error(ctx, w, http.StatusMethodNotAllowed, "DeleteAPIKey", domain.ErrHTTPMethod, nil)
+		return
+	}
+
+	claims, err := h.authenticate(r)
+	if err != nil {
+		log.WarnContext(ctx, "handler DeleteAPIKey unauthorized", slog.Any("err", err))
+		h.rs.Error(ctx, w, http.StatusUnauthorized, "DeleteAPIKey", domain.ErrUnauthorized, nil)
+		return
+	}
+
+	keyID := r.PathValue("id")
+	if _, err := uuid.Parse(keyID); err != nil {
+		log.WarnContext(ctx, "handler DeleteAPIKey invalid id", slog.String("key_id", keyID))
+		h.rs.Error(ctx, w, http.StatusBadRequest, "DeleteAPIKey", domain.ErrRequestParams, nil)
+		return
+	}
+
+	if err := h.uc.RevokeAPIKey(ctx, claims.UserID, keyID); err != nil {
+		log.ErrorContext(ctx, "usecase RevokeAPIKey failed", slog.Any("err", err))
+		h.apiKeyError(w, r, "DeleteAPIKey", err)
+		return
+	}
+
+	log.InfoContext(ctx, "handler DeleteAPIKey success", slog.String("key_id", keyID))
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,153 @@
+package http
+
+import (
+	"apple_backend/auth_service/internal/delivery/transport"
+	"apple_backend/auth_service/internal/domain"
+	"apple_backend/pkg/logger"
+	"apple_backend/pkg/rbac"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultEventsLimit = 20
+	maxEventsLimit     = 100
+)
+
+// eventsPage разбирает last_id и limit. При ошибке ответ уже отправлен.
+func (h *AuthHandler) eventsPage(w http.ResponseWriter, r *http.Request, op string, q url.Values) (lastID string, limit int, ok bool) {
+	ctx := r.Context()
+	limit = defaultEventsLimit
+	if s := q.Get("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n <= 0 || n > maxEventsLimit {
+			logger.FromContext(ctx).WarnContext(ctx, "handler "+op+" invalid limit", slog.String("limit", s))
+			h.rs.Error(ctx, w, http.StatusBadRequest, op, domain.ErrRequestParams, nil)
+			return "", 0, false
+		}
+		limit = n
+	}
+	lastID = q.Get("last_id")
+	if lastID != "" {
+		if _, err := uuid.Parse(lastID); err != nil {
+			logger.FromContext(ctx).WarnContext(ctx, "handler "+op+" invalid last_id", slog.String("last_id", lastID))
+			h.rs.Error(ctx, w, http.StatusBadRequest, op, domain.ErrRequestParams, nil)
+			return "", 0, false
+		}
+	}
+	return lastID, limit, true
+}
+
+// parseEventTime разбирает необязательную границу периода в RFC 3339
+func parseEventTime(s string) (*time.Time, error) {
+	if s == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// Events — история входов и других событий безопасности своего аккаунта
+func (h *AuthHandler) Events(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "handler Events start")
+
+	if r.Method != http.MethodGet {
+		log.WarnContext(ctx, "handler Events wrong method")
+		h.rs.Error(ctx, w, http.StatusMethodNotAllowed, "Events", domain.ErrHTTPMethod, nil)
+		return
+	}
+
+	claims, err := h.authenticate(r)
+	if err != nil {
+		log.WarnContext(ctx, "handler Events unauthorized", slog.Any("err", err))
+		h.rs.Error(ctx, w, http.StatusUnauthorized, "Events", domain.ErrUnauthorized, nil)
+		return
+	}
+
+	lastID, limit, ok := h.eventsPage(w, r, "Events", r.URL.Query())
+	if !ok {
+		return
+	}
+
+	events, err := h.uc.ListUserEvents(ctx, claims.UserID, lastID, limit)
+	if err != nil {
+		log.ErrorContext(ctx, "usecase ListUserEvents failed", slog.Any("err", err))
+		h.rs.Error(ctx, w, http.StatusInternalServerError, "Events", domain.ErrInternalServer, err)
+		return
+	}
+
+	h.rs.Send(ctx, w, http.StatusOK, transport.ToAuthEventsResponse(events, limit))
+	log.InfoContext(ctx, "handler Events success", slog.Int("count", len(events)))
+}
+
+// AdminEvents — выборка журнала по пользователю, типу события, IP и периоду
+func (h *AuthHandler) AdminEvents(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "handler AdminEvents start")
+
+	if r.Method != http.MethodGet {
+		log.WarnContext(ctx, "handler AdminEvents wrong method")
+		h.rs.Error(ctx, w, http.StatusMethodNotAllowed, "AdminEvents", domain.ErrHTTPMethod, nil)
+		return
+	}
+
+	if _, ok := h.authorize(w, r, "AdminEvents", rbac.PermAuditRead); !ok {
+		return
+	}
+
+	q := r.URL.Query()
+	lastID, limit, ok := h.eventsPage(w, r, "AdminEvents", q)
+	if !ok {
+		return
+	}
+	filter := &domain.AuthEventFilter{
+		UserID: q.Get("user_id"),
+		Type:   q.Get("type"),
+		IP:     q.Get("ip"),
+		LastID: lastID,
+		Limit:  limit,
+	}
+	if filter.UserID != "" {
+		if _, err := uuid.Parse(filter.UserID); err != nil {
+			log.WarnContext(ctx, "handler AdminEvents invalid user_id", slog.String("user_id", filter.UserID))
+			h.rs.Error(ctx, w, http.StatusBadRequest, "AdminEvents", domain.ErrRequestParams, nil)
+			return
+		}
+	}
+	var err error
+	if filter.From, err = parseEventTime(q.Get("from")); err != nil {
+		log.WarnContext(ctx, "handler AdminEvents invalid from", slog.String("from", q.Get("from")))
+		h.rs.Error(ctx, w, http.StatusBadRequest, "AdminEvents", domain.ErrRequestParams, nil)
+		return
+	}
+	if filter.To, err = parseEventTime(q.Get("to")); err != nil {
+		log.WarnContext(ctx, "handler AdminEvents invalid to", slog.String("to", q.Get("to")))
+		h.rs.Error(ctx, w, http.StatusBadRequest, "AdminEvents", domain.ErrRequestParams, nil)
+		return
+	}
+
+	events, err := h.uc.ListAuthEvents(ctx, filter)
+	if err != nil {
+		log.ErrorContext(ctx, "usecase ListAuthEvents failed", slog.Any("err", err))
+		if err == domain.ErrRequestParams {
+			h.rs.Error(ctx, w, http.StatusBadRequest, "AdminEvents", err, nil)
+			return
+		}
+		h.rs.Error(ctx, w, http.StatusInternalServerError, "AdminEvents", domain.ErrInternalServer, err)
+		return
+	}
+
+	h.rs.Send(ctx, w, http.StatusOK, transport.ToAuthEventsResponse(events, limit))
+	log.InfoContext(ctx, "handler AdminEvents success", slog.Int("count", len(events)))
+}
@@ -4,38 +4,109 @@ import (
 	"apple_backend/auth_service/internal/delivery/middlewares"
 	"apple_backend/auth_service/internal/delivery/transport"
 	"apple_backend/auth_service/internal/domain"
+	"apple_backend/pkg/csrf"
 	"apple_backend/pkg/http_response"
 	"apple_backend/pkg/logger"
 	"context"
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 type AuthUseCaseInterface interface {
 	Register(ctx context.Context, email, password string) (*transport.AuthResult, error)
 	Login(ctx context.Context, email, password string) (*transport.AuthResult, error)
-	RefreshToken(ctx context.Context, tokenString string) (*transport.AuthResult, error)
+	RestoreAccount(ctx context.Context, email, password string) (*transport.AuthResult, error)
+	RefreshToken(ctx context.Context, refreshToken string) (*transport.AuthResult, error)
+	Logout(ctx context.Context, refreshToken string) error
 	VerifyToken(ctx context.Context, tokenString string) (*transport.Claims, error)
 	ValidateEmail(ctx context.Context, email string) error
 	GetUserByID(ctx context.Context, userID string) (*domain.User, error)
+	ListSessions(ctx context.Context, userID string) ([]*domain.Session, error)
+	RevokeSession(ctx context.Context, userID, sessionID string) error
+	RevokeOtherSessions(ctx context.Context, userID, currentSessionID string) error
+	ForgotPassword(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, token, newPassword string) error
+	VerifyEmail(ctx context.Context, token string) error
+	ResendVerification(ctx context.Context, userID string) error
+	ChangePassword(ctx context.Context, userID, sessionID, currentPassword, code, newPassword string) error
+	RequestEmailChange(ctx context.Context, userID, sessionID, password, code, newEmail string) error
+	ConfirmEmailChange(ctx context.Context, token string) error
+	LoginTwoFactor(ctx context.Context, challengeToken, code string) (*transport.AuthResult, error)
+	ChallengeUser(ctx context.Context, challengeToken string) (string, error)
+	EnrollTwoFactor(ctx context.Context, userID string) (*transport.TwoFactorEnrollment, error)
+	ConfirmTwoFactor(ctx context.Context, userID, sessionID, code string) (*transport.TwoFactorConfirmResult, error)
+	DisableTwoFactor(ctx context.Context, userID, code string) error
+	ListUserRoles(ctx context.Context, userID string) ([]string, error)
+	GrantRole(ctx context.Context, actorID, userID, role string) error
+	RevokeRole(ctx context.Context, actorID, userID, role string) error
+	RequestPhoneCode(ctx context.Context, phone string) (*transport.PhoneCodeSent, error)
+	LoginByPhone(ctx context.Context, phone, code string) (*transport.AuthResult, error)
+	ExternalProviders() []string
+	StartExternalLogin(ctx context.Context, provider string) (*transport.ExternalLoginStart, error)
+	FinishExternalLogin(ctx context.Context, provider, stateToken, state, code string) (*transport.AuthResult, error)
+	ListUserEvents(ctx context.Context, userID, lastID string, limit int) ([]*domain.AuthEvent, error)
+	ListAuthEvents(ctx context.Context, filter *domain.AuthEventFilter) ([]*domain.AuthEvent, error)
+	CreateAPIKey(ctx context.Context, userID string, roles []string, name string, scopes []string, ttlDays int) (*domain.APIKey, string, error)
+	ListAPIKeys(ctx context.Context, userID string) ([]*domain.APIKey, error)
+	RevokeAPIKey(ctx context.Context, userID, keyID string) error
 }
 
 type AuthHandler struct {
 	uc AuthUseCaseInterface
 	rs *http_response.ResponseSender
+	// csrf перевыпускает CSRF-токен под новую сессию при входе
+	csrf *csrf.Protector
+	// guestCarts сливает гостевую корзину при входе; nil — не сливать
+	guestCarts *GuestCarts
 }
 
-func NewAuthRouter(mux *http.ServeMux, base string, uc AuthUseCaseInterface) {
+func NewAuthRouter(mux *http.ServeMux, base string, uc AuthUseCaseInterface, csrfProtector *csrf.Protector, guestCarts *GuestCarts) {
 	h := NewAuthHandler(uc)
+	h.csrf = csrfProtector
+	h.guestCarts = guestCarts
+
+	// один лимит на все маршруты входа и восстановления: иначе клиент
+	// получал бы отдельные 20 запросов в минуту на каждый из них
+	limit := middlewares.RateLimit(20, time.Minute)
+	rateLimitHandler := func(fn http.HandlerFunc) http.Handler { return limit(fn) }
 
 	mux.Handle(base+"/signup", rateLimitHandler(h.Register))
 	mux.Handle(base+"/login", rateLimitHandler(h.Login))
+	mux.Handle(base+"/login/2fa", rateLimitHandler(h.LoginTwoFactor))
+	mux.Handle(base+"/restore", rateLimitHandler(h.RestoreAccount))
+	mux.Handle(base+"/otp/request", rateLimitHandler(h.RequestPhoneCode))
+	mux.Handle(base+"/otp/verify", rateLimitHandler(h.LoginByPhone))
+	mux.HandleFunc(base+"/oidc/providers", h.ExternalProviders)
+	mux.Handle(base+"/oidc/{provider}/login", rateLimitHandler(h.StartExternalLogin))
+	mux.Handle(base+"/oidc/{provider}/callback", rateLimitHandler(h.ExternalCallback))
 	mux.Handle(base+"/refresh", rateLimitHandler(h.RefreshToken))
 	mux.Handle(base+"/logout", rateLimitHandler(h.Logout))
+	mux.Handle(base+"/password/forgot", rateLimitHandler(h.ForgotPassword))
+	mux.Handle(base+"/password/reset", rateLimitHandler(h.ResetPassword))
+	mux.Handle(base+"/password/change", rateLimitHandler(h.ChangePassword))
+	mux.Handle(base+"/email/change", rateLimitHandler(h.RequestEmailChange))
+	mux.Handle(base+"/email/confirm", rateLimitHandler(h.ConfirmEmailChange))
+	mux.Handle(base+"/verify-email", rateLimitHandler(h.VerifyEmail))
+	mux.Handle(base+"/verify-email/resend", rateLimitHandler(h.ResendVerification))
+	mux.Handle(base+"/2fa/enroll", rateLimitHandler(h.EnrollTwoFactor))
+	mux.Handle(base+"/2fa/confirm", rateLimitHandler(h.ConfirmTwoFactor))
+	mux.Handle(base+"/2fa/disable", rateLimitHandler(h.DisableTwoFactor))
+	mux.HandleFunc(base+"/sessions", h.Sessions)
+	mux.HandleFunc(base+"/sessions/{id}", h.DeleteSession)
+	mux.HandleFunc(base+"/events", h.Events)
+	mux.HandleFunc(base+"/api-keys", h.APIKeys)
+	mux.HandleFunc(base+"/api-keys/{id}", h.DeleteAPIKey)
+	mux.HandleFunc(base+"/admin/events", h.AdminEvents)
+	mux.HandleFunc(base+"/admin/users/{id}/roles", h.UserRoles)
+	mux.HandleFunc(base+"/admin/users/{id}/roles/{role}", h.RevokeUserRole)
 }
 
 func NewAuthHandler(uc AuthUseCaseInterface) *AuthHandler {
@@ -45,19 +116,30 @@ func NewAuthHandler(uc AuthUseCaseInterface) *AuthHandler {
 	}
 }
 
-func setAuthCookie(w http.ResponseWriter, token string, expires time.Time) {
-	secure := strings.EqualFold(os.Getenv("COOKIE_SECURE"), "true")
+const (
+	jwtCookieName     = "jwt_token"
+	refreshCookieName = "refresh_token"
+	// refresh-токен нужен только эндпоинтам авторизации
+	refreshCookiePath = "/api/v0/auth"
+)
+
+func cookieSettings() (secure bool, sameSite http.SameSite, domain string) {
+	secure = strings.EqualFold(os.Getenv("COOKIE_SECURE"), "true")
 	sameSiteStr := strings.ToLower(os.Getenv("COOKIE_SAMESITE"))
-	var sameSite http.SameSite = http.SameSiteLaxMode
+	sameSite = http.SameSiteLaxMode
 	if sameSiteStr == "strict" {
 		sameSite = http.SameSiteStrictMode
 	} else if sameSiteStr == "none" {
 		sameSite = http.SameSiteNoneMode
 	}
-	domain := os.Getenv("COOKIE_DOMAIN")
+	return secure, sameSite, os.Getenv("COOKIE_DOMAIN")
+}
+
+func setAuthCookie(w http.ResponseWriter, token string, expires time.Time) {
+	secure, sameSite, domain := cookieSettings()
 
 	http.SetCookie(w, &http.Cookie{
-		Name:     "jwt_token",
+		Name:     jwtCookieName,
 		Value:    token,
 		Expires:  expires,
 		Path:     "/",
@@ -68,17 +150,47 @@ func setAuthCookie(w http.ResponseWriter, token string, expires time.Time) {
 	})
 }
 
+func setRefreshCookie(w http.ResponseWriter, token string, expires time.Time) {
+	secure, sameSite, domain := cookieSettings()
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     refreshCookieName,
+		Value:    token,
+		Expires:  expires,
+		Path:     refreshCookiePath,
+		HttpOnly: true,
+		Secure:   secure,
+		SameSite: sameSite,
+		Domain:   domain,
+	})
+}
+
+func (h *AuthHandler) setSessionCookies(w http.ResponseWriter, res *transport.AuthResult) {
+	setAuthCookie(w, res.Token, res.Expires)
+	setRefreshCookie(w, res.RefreshToken, res.RefreshExpires)
+	if h.csrf != nil {
+		h.csrf.SetCookie(w, res.SessionID)
+	}
+}
+
 func clearAuthCookie(w http.ResponseWriter) {
 	now := time.Now()
 	http.SetCookie(w, &http.Cookie{
-		Name:     "jwt_token",
+		Name:     jwtCookieName,
 		Value:    "",
 		Expires:  now.Add(-time.Hour),
 		Path:     "/",
 		HttpOnly: true,
 	})
 	http.SetCookie(w, &http.Cookie{
-		Name:     "csrf_token",
+		Name:     refreshCookieName,
+		Value:    "",
+		Expires:  now.Add(-time.Hour),
+		Path:     refreshCookiePath,
+		HttpOnly: true,
+	})
+	http.SetCookie(w, &http.Cookie{
+		Name:     csrf.CookieName,
 		Value:    "",
 		Expires:  now.Add(-time.Hour),
 		Path:     "/",
@@ -115,6 +227,15 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 	res, err := h.uc.Register(ctx, req.Email, req.Password)
 	if err != nil {
 		log.ErrorContext(ctx, "usecase Register failed", slog.Any("err", err))
+		var throttled *domain.ThrottledError
+		if errors.As(err, &throttled) {
+			setRetryAfter(w, throttled.RetryAfter)
+			h.rs.Error(ctx, w, http.StatusTooManyRequests, "Register", domain.ErrTooManyRequests, nil)
+			return
+		}
+		if h.weakPassword(ctx, w, "Register", err) {
+			return
+		}
 		switch err {
 		case domain.ErrUserAlreadyExists:
 			h.rs.Error(ctx, w, http.StatusConflict, "Register", err, nil)
@@ -126,24 +247,36 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	setAuthCookie(w, res.Token, res.Expires)
+	h.mergeGuestCart(w, r, res.UserID)
+	h.setSessionCookies(w, res)
 	h.rs.Send(ctx, w, http.StatusOK, res)
 	log.InfoContext(ctx, "handler Register success", slog.String("user_id", res.UserID))
 }
 
 func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
+	h.passwordLogin(w, r, "Login", h.uc.Login)
+}
+
+// RestoreAccount — вход по паролю в удаленный аккаунт, который отменяет
+// удаление, пока данные не обезличены
+func (h *AuthHandler) RestoreAccount(w http.ResponseWriter, r *http.Request) {
+	h.passwordLogin(w, r, "RestoreAccount", h.uc.RestoreAccount)
+}
+
+func (h *AuthHandler) passwordLogin(w http.ResponseWriter, r *http.Request, op string,
+	login func(ctx context.Context, email, password string) (*transport.AuthResult, error)) {
 	ctx := r.Context()
 	log := logger.FromContext(ctx)
-	log.InfoContext(ctx, "handler Login start")
+	log.InfoContext(ctx, "handler "+op+" start")
 
 	if r.Method != http.MethodPost {
-		log.WarnContext(ctx, "handler Login wrong method")
-		h.rs.Error(ctx, w, http.StatusMethodNotAllowed, "Login", domain.ErrHTTPMethod, nil)
+		log.WarnContext(ctx, "handler "+op+" wrong method")
+		h.rs.Error(ctx, w, http.StatusMethodNotAllowed, op, domain.ErrHTTPMethod, nil)
 		return
 	}
 	if ct := strings.ToLower(r.Header.Get("Content-Type")); !strings.HasPrefix(ct, "application/json") {
-		log.WarnContext(ctx, "handler Login unsupported content type", slog.String("content_type", ct))
-		h.rs.Error(ctx, w, http.StatusUnsupportedMediaType, "Login", domain.ErrRequestParams, nil)
+		log.WarnContext(ctx, "handler "+op+" unsupported content type", slog.String("content_type", ct))
+		h.rs.Error(ctx, w, http.StatusUnsupportedMediaType, op, domain.ErrRequestParams, nil)
 		return
 	}
 
@@ -152,28 +285,44 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	dec := json.NewDecoder(r.Body)
 	dec.DisallowUnknownFields()
 	if err := dec.Decode(&req); err != nil {
-		log.ErrorContext(ctx, "handler Login decode failed", slog.Any("err", err))
-		h.rs.Error(ctx, w, http.StatusBadRequest, "Login", domain.ErrRequestParams, err)
+		log.ErrorContext(ctx, "handler "+op+" decode failed", slog.Any("err", err))
+		h.rs.Error(ctx, w, http.StatusBadRequest, op, domain.ErrRequestParams, err)
 		return
 	}
 
-	res, err := h.uc.Login(ctx, req.Email, req.Password)
+	res, err := login(ctx, req.Email, req.Password)
 	if err != nil {
-		log.ErrorContext(ctx, "usecase Login failed", slog.Any("err", err))
+		log.ErrorContext(ctx, "usecase "+op+" failed", slog.Any("err", err))
+		var throttled *domain.ThrottledError
+		if errors.As(err, &throttled) {
+			setRetryAfter(w, throttled.RetryAfter)
+			h.rs.Error(ctx, w, http.StatusTooManyRequests, op, domain.ErrTooManyRequests, nil)
+			return
+		}
 		switch err {
 		case domain.ErrUserNotFound, domain.ErrInvalidPassword:
-			h.rs.Error(ctx, w, http.StatusUnauthorized, "Login", err, nil)
+			h.rs.Error(ctx, w, http.StatusUnauthorized, op, err, nil)
 		case domain.ErrInvalidEmail:
-			h.rs.Error(ctx, w, http.StatusBadRequest, "Login", err, nil)
+			h.rs.Error(ctx, w, http.StatusBadRequest, op, err, nil)
+		case domain.ErrAccountDeleted:
+			h.rs.Error(ctx, w, http.StatusForbidden, op, err, nil)
 		default:
-			h.rs.Error(ctx, w, http.StatusInternalServerError, "Login", domain.ErrInternalServer, err)
+			h.rs.Error(ctx, w, http.StatusInternalServerError, op, domain.ErrInternalServer, err)
 		}
 		return
 	}
 
-	setAuthCookie(w, res.Token, res.Expires)
+	if res.Challenge != nil {
+		// сессии еще нет: cookie выставим после второго шага
+		h.rs.Send(ctx, w, http.StatusOK, res.Challenge)
+		log.InfoContext(ctx, "handler "+op+" second factor required", slog.String("user_id", res.UserID))
+		return
+	}
+
+	h.mergeGuestCart(w, r, res.UserID)
+	h.setSessionCookies(w, res)
 	h.rs.Send(ctx, w, http.StatusOK, res)
-	log.InfoContext(ctx, "handler Login success", slog.String("user_id", res.UserID))
+	log.InfoContext(ctx, "handler "+op+" success", slog.String("user_id", res.UserID))
 }
 
 func (h *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
@@ -187,9 +336,9 @@ func (h *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	c, err := r.Cookie("jwt_token")
+	c, err := r.Cookie(refreshCookieName)
 	if err != nil || c.Value == "" {
-		log.WarnContext(ctx, "handler RefreshToken missing jwt cookie")
+		log.WarnContext(ctx, "handler RefreshToken missing refresh cookie")
 		h.rs.Error(ctx, w, http.StatusUnauthorized, "RefreshToken", domain.ErrUnauthorized, nil)
 		return
 	}
@@ -197,11 +346,22 @@ func (h *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 	res, err := h.uc.RefreshToken(ctx, c.Value)
 	if err != nil {
 		log.ErrorContext(ctx, "usecase RefreshToken failed", slog.Any("err", err))
-		h.rs.Error(ctx, w, http.StatusUnauthorized, "RefreshToken", domain.ErrInvalidToken, err)
+		switch err {
+		case domain.ErrTokenReused:
+			clearAuthCookie(w)
+			h.rs.Error(ctx, w, http.StatusUnauthorized, "RefreshToken", domain.ErrInvalidToken, err)
+		case domain.ErrInvalidToken, domain.ErrUserNotFound:
+			h.rs.Error(ctx, w, http.StatusUnauthorized, "RefreshToken", domain.ErrInvalidToken, err)
+		case domain.ErrAccountDeleted:
+			clearAuthCookie(w)
+			h.rs.Error(ctx, w, http.StatusForbidden, "RefreshToken", err, nil)
+		default:
+			h.rs.Error(ctx, w, http.StatusInternalServerError, "RefreshToken", domain.ErrInternalServer, err)
+		}
 		return
 	}
 
-	setAuthCookie(w, res.Token, res.Expires)
+	h.setSessionCookies(w, res)
 	h.rs.Send(ctx, w, http.StatusOK, res)
 	log.InfoContext(ctx, "handler RefreshToken success", slog.String("user_id", res.UserID))
 }
@@ -217,11 +377,286 @@ func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	var refreshToken string
+	if c, err := r.Cookie(refreshCookieName); err == nil {
+		refreshToken = c.Value
+	}
+	if err := h.uc.Logout(ctx, refreshToken); err != nil {
+		log.ErrorContext(ctx, "usecase Logout failed", slog.Any("err", err))
+		h.rs.Error(ctx, w, http.StatusInternalServerError, "Logout", domain.ErrInternalServer, err)
+		return
+	}
+
 	clearAuthCookie(w)
 	h.rs.Send(ctx, w, http.StatusOK, map[string]string{"message": "logged out"})
 	log.InfoContext(ctx, "handler Logout success")
 }
 
-func rateLimitHandler(fn http.HandlerFunc) http.Handler {
-	return middlewares.RateLimit(5, time.Minute)(fn)
+// decodeJSON проверяет метод и Content-Type и разбирает тело запроса.
+// При ошибке ответ клиенту уже отправлен и возвращается false.
+func (h *AuthHandler) decodeJSON(w http.ResponseWriter, r *http.Request, op string, dst any) bool {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+
+	if r.Method != http.MethodPost {
+		log.WarnContext(ctx, "handler "+op+" wrong method")
+		h.rs.Error(ctx, w, http.StatusMethodNotAllowed, op, domain.ErrHTTPMethod, nil)
+		return false
+	}
+	if ct := strings.ToLower(r.Header.Get("Content-Type")); !strings.HasPrefix(ct, "application/json") {
+		log.WarnContext(ctx, "handler "+op+" unsupported content type", slog.String("content_type", ct))
+		h.rs.Error(ctx, w, http.StatusUnsupportedMediaType, op, domain.ErrRequestParams, nil)
+		return false
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<20)
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(dst); err != nil {
+		log.ErrorContext(ctx, "handler "+op+" decode failed", slog.Any("err", err))
+		h.rs.Error(ctx, w, http.StatusBadRequest, op, domain.ErrRequestParams, err)
+		return false
+	}
+	return true
+}
+
+func (h *AuthHandler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "handler ForgotPassword start")
+
+	var req transport.ForgotPasswordRequest
+	if !h.decodeJSON(w, r, "ForgotPassword", &req) {
+		return
+	}
+
+	if err := h.uc.ForgotPassword(ctx, req.Email); err != nil {
+		log.ErrorContext(ctx, "usecase ForgotPassword failed", slog.Any("err", err))
+		var throttled *domain.ThrottledError
+		if errors.As(err, &throttled) {
+			setRetryAfter(w, throttled.RetryAfter)
+			h.rs.Error(ctx, w, http.StatusTooManyRequests, "ForgotPassword", domain.ErrTooManyRequests, nil)
+			return
+		}
+		switch err {
+		case domain.ErrInvalidEmail:
+			h.rs.Error(ctx, w, http.StatusBadRequest, "ForgotPassword", err, nil)
+		default:
+			h.rs.Error(ctx, w, http.StatusInternalServerError, "ForgotPassword", domain.ErrInternalServer, err)
+		}
+		return
+	}
+
+	h.rs.Send(ctx, w, http.StatusAccepted, map[string]string{
+		"message": "если аккаунт существует, на почту отправлена ссылка для сброса пароля",
+	})
+	log.InfoContext(ctx, "handler ForgotPassword success")
+}
+
+func (h *AuthHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "handler ResetPassword start")
+
+	var req transport.ResetPasswordRequest
+	if !h.decodeJSON(w, r, "ResetPassword", &req) {
+		return
+	}
+
+	if err := h.uc.ResetPassword(ctx, req.Token, req.Password); err != nil {
+		log.ErrorContext(ctx, "usecase ResetPassword failed", slog.Any("err", err))
+		if h.weakPassword(ctx, w, "ResetPassword", err) {
+			return
+		}
+		switch err {
+		case domain.ErrResetTokenInvalid, domain.ErrWeakPassword:
+			h.rs.Error(ctx, w, http.StatusBadRequest, "ResetPassword", err, nil)
+		default:
+			h.rs.Error(ctx, w, http.StatusInternalServerError, "ResetPassword", domain.ErrInternalServer, err)
+		}
+		return
+	}
+
+	clearAuthCookie(w)
+	h.rs.Send(ctx, w, http.StatusOK, map[string]string{"message": "password changed"})
+	log.InfoContext(ctx, "handler ResetPassword success")
+}
+
+func (h *AuthHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "handler VerifyEmail start")
+
+	var req transport.VerifyEmailRequest
+	if !h.decodeJSON(w, r, "VerifyEmail", &req) {
+		return
+	}
+
+	if err := h.uc.VerifyEmail(ctx, req.Token); err != nil {
+		log.ErrorContext(ctx, "usecase VerifyEmail failed", slog.Any("err", err))
+		switch err {
+		case domain.ErrVerifyTokenInvalid:
+			h.rs.Error(ctx, w, http.StatusBadRequest, "VerifyEmail", err, nil)
+		default:
+			h.rs.Error(ctx, w, http.StatusInternalServerError, "VerifyEmail", domain.ErrInternalServer, err)
+		}
+		return
+	}
+
+	h.rs.Send(ctx, w, http.StatusOK, map[string]string{"message": "email verified"})
+	log.InfoContext(ctx, "handler VerifyEmail success")
+}
+
+func (h *AuthHandler) ResendVerification(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "handler ResendVerification start")
+
+	if r.Method != http.MethodPost {
+		log.WarnContext(ctx, "handler ResendVerification wrong method")
+		h.rs.Error(ctx, w, http.StatusMethodNotAllowed, "ResendVerification", domain.ErrHTTPMethod, nil)
+		return
+	}
+
+	claims, err := h.authenticate(r)
+	if err != nil {
+		log.WarnContext(ctx, "handler ResendVerification unauthorized", slog.Any("err", err))
+		h.rs.Error(ctx, w, http.StatusUnauthorized, "ResendVerification", domain.ErrUnauthorized, nil)
+		return
+	}
+
+	if err := h.uc.ResendVerification(ctx, claims.UserID); err != nil {
+		log.ErrorContext(ctx, "usecase ResendVerification failed", slog.Any("err", err))
+		switch err {
+		case domain.ErrEmailAlreadyVerified, domain.ErrEmailNotSet:
+			h.rs.Error(ctx, w, http.StatusConflict, "ResendVerification", err, nil)
+		case domain.ErrTooManyRequests:
+			w.Header().Set("Retry-After", "60")
+			h.rs.Error(ctx, w, http.StatusTooManyRequests, "ResendVerification", err, nil)
+		default:
+			h.rs.Error(ctx, w, http.StatusInternalServerError, "ResendVerification", domain.ErrInternalServer, err)
+		}
+		return
+	}
+
+	h.rs.Send(ctx, w, http.StatusAccepted, map[string]string{"message": "verification email sent"})
+	log.InfoContext(ctx, "handler ResendVerification success", slog.String("user_id", claims.UserID))
+}
+
+// authenticate проверяет access-токен из cookie вместе с его сессией
+func (h *AuthHandler) authenticate(r *http.Request) (*transport.Claims, error) {
+	c, err := r.Cookie(jwtCookieName)
+	if err != nil || c.Value == "" {
+		return nil, domain.ErrUnauthorized
+	}
+	return h.uc.VerifyToken(r.Context(), c.Value)
+}
+
+func (h *AuthHandler) Sessions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "handler Sessions start")
+
+	if r.Method != http.MethodGet && r.Method != http.MethodDelete {
+		log.WarnContext(ctx, "handler Sessions wrong method")
+		h.rs.Error(ctx, w, http.StatusMethodNotAllowed, "Sessions", domain.ErrHTTPMethod, nil)
+		return
+	}
+
+	claims, err := h.authenticate(r)
+	if err != nil {
+		log.WarnContext(ctx, "handler Sessions unauthorized", slog.Any("err", err))
+		h.rs.Error(ctx, w, http.StatusUnauthorized, "Sessions", domain.ErrUnauthorized, nil)
+		return
+	}
+
+	if r.Method == http.MethodDelete {
+		if err := h.uc.RevokeOtherSessions(ctx, claims.UserID, claims.SessionID); err != nil {
+			log.ErrorContext(ctx, "usecase RevokeOtherSessions failed", slog.Any("err", err))
+			h.rs.Error(ctx, w, http.StatusInternalServerError, "Sessions", domain.ErrInternalServer, err)
+			return
+		}
+		log.InfoContext(ctx, "handler Sessions revoked others", slog.String("user_id", claims.UserID))
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	sessions, err := h.uc.ListSessions(ctx, claims.UserID)
+	if err != nil {
+		log.ErrorContext(ctx, "usecase ListSessions failed", slog.Any("err", err))
+		h.rs.Error(ctx, w, http.StatusInternalServerError, "Sessions", domain.ErrInternalServer, err)
+		return
+	}
+
+	h.rs.Send(ctx, w, http.StatusOK, transport.ToSessionsResponse(sessions, claims.SessionID))
+	log.InfoContext(ctx, "handler Sessions success", slog.Int("count", len(sessions)))
+}
+
+func (h *AuthHandler) DeleteSession(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "handler DeleteSession start")
+
+	if r.Method != http.MethodDelete {
+		log.WarnContext(ctx, "handler DeleteSession wrong method")
+		h.rs.Error(ctx, w, http.StatusMethodNotAllowed, "DeleteSession", domain.ErrHTTPMethod, nil)
+		return
+	}
+
+	claims, err := h.authenticate(r)
+	if err != nil {
+		log.WarnContext(ctx, "handler DeleteSession unauthorized", slog.Any("err", err))
+		h.rs.Error(ctx, w, http.StatusUnauthorized, "DeleteSession", domain.ErrUnauthorized, nil)
+		return
+	}
+
+	sessionID := r.PathValue("id")
+	if _, err := uuid.Parse(sessionID); err != nil {
+		log.WarnContext(ctx, "handler DeleteSession invalid id", slog.String("session_id", sessionID))
+		h.rs.Error(ctx, w, http.StatusBadRequest, "DeleteSession", domain.ErrRequestParams, nil)
+		return
+	}
+
+	if err := h.uc.RevokeSession(ctx, claims.UserID, sessionID); err != nil {
+		log.ErrorContext(ctx, "usecase RevokeSession failed", slog.Any("err", err))
+		switch err {
+		case domain.ErrSessionNotFound:
+			h.rs.Error(ctx, w, http.StatusNotFound, "DeleteSession", err, nil)
+		default:
+			h.rs.Error(ctx, w, http.StatusInternalServerError, "DeleteSession", domain.ErrInternalServer, err)
+		}
+		return
+	}
+
+	if sessionID == claims.SessionID {
+		clearAuthCookie(w)
+	}
+	log.InfoContext(ctx, "handler DeleteSession success", slog.String("session_id", sessionID))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// weakPassword отвечает 400 с оценкой и причиной, если пароль не прошел
+// проверку стойкости
+func (h *AuthHandler) weakPassword(ctx context.Context, w http.ResponseWriter, op string, err error) bool {
+	var weak *domain.WeakPasswordError
+	if !errors.As(err, &weak) {
+		return false
+	}
+	logger.FromContext(ctx).WarnContext(ctx, "handler "+op+" weak password",
+		slog.Int("score", weak.Score), slog.String("reason", weak.Reason))
+	h.rs.Send(ctx, w, http.StatusBadRequest, transport.WeakPasswordResponse{
+		Error:  weak.Error(),
+		Score:  weak.Score,
+		Reason: weak.Reason,
+	})
+	return true
+}
+
+// setRetryAfter выставляет Retry-After в секундах, округляя вверх
+func setRetryAfter(w http.ResponseWriter, d time.Duration) {
+	secs := int64((d + time.Second - 1) / time.Second)
+	if secs < 1 {
+		secs = 1
+	}
+	w.Header().Set("Retry-After", strconv.FormatInt(secs, 10))
 }
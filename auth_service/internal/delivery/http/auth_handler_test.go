@@ -3,7 +3,6 @@ package http
 import (
 	"apple_backend/auth_service/internal/delivery/transport"
 	"apple_backend/auth_service/internal/domain"
-	"apple_backend/pkg/logger"
 	"bytes"
 	"context"
 	"encoding/json"
@@ -13,7 +12,6 @@ import (
 	"time"
 
 	mock "apple_backend/auth_service/internal/delivery/http/mock"
-	"log/slog"
 
 	"github.com/golang/mock/gomock"
 )
@@ -69,6 +67,14 @@ func (handlerUC) Login(_ context.Context, email, password string) (*transport.Au
 		Expires: time.Now().Add(time.Hour),
 	}, nil
 }
+func (handlerUC) RestoreAccount(_ context.Context, email, password string) (*transport.AuthResult, error) {
+	return &transport.AuthResult{
+		UserID:  "u1",
+		Email:   email,
+		Token:   "tok",
+		Expires: time.Now().Add(time.Hour),
+	}, nil
+}
 func (handlerUC) RefreshToken(_ context.Context, token string) (*transport.AuthResult, error) {
 	return &transport.AuthResult{
 		UserID:  "u1",
@@ -77,6 +83,7 @@ func (handlerUC) RefreshToken(_ context.Context, token string) (*transport.AuthR
 		Expires: time.Now().Add(time.Hour),
 	}, nil
 }
+func (handlerUC) Logout(_ context.Context, refreshToken string) error { return nil }
 func (handlerUC) VerifyToken(_ context.Context, token string) (*transport.Claims, error) {
 	return &transport.Claims{UserID: "u1", Email: "u@ex.com"}, nil
 }
@@ -84,12 +91,77 @@ func (handlerUC) ValidateEmail(_ context.Context, email string) error { return n
 func (handlerUC) GetUserByID(_ context.Context, id string) (*domain.User, error) {
 	return &domain.User{ID: id, Email: "u@ex.com"}, nil
 }
+func (handlerUC) ListSessions(_ context.Context, userID string) ([]*domain.Session, error) {
+	return []*domain.Session{{ID: "s1", UserID: userID}}, nil
+}
+func (handlerUC) ForgotPassword(_ context.Context, email string) error { return nil }
+func (handlerUC) ResetPassword(_ context.Context, token, newPassword string) error {
+	return nil
+}
+func (handlerUC) VerifyEmail(_ context.Context, token string) error               { return nil }
+func (handlerUC) ResendVerification(_ context.Context, userID string) error       { return nil }
+func (handlerUC) RevokeSession(_ context.Context, userID, sessionID string) error { return nil }
+func (handlerUC) RevokeOtherSessions(_ context.Context, userID, currentSessionID string) error {
+	return nil
+}
+func (handlerUC) ChangePassword(_ context.Context, userID, sessionID, currentPassword, code, newPassword string) error {
+	return nil
+}
+func (handlerUC) RequestEmailChange(_ context.Context, userID, sessionID, password, code, newEmail string) error {
+	return nil
+}
+func (handlerUC) ConfirmEmailChange(_ context.Context, token string) error { return nil }
+func (handlerUC) LoginTwoFactor(_ context.Context, challengeToken, code string) (*transport.AuthResult, error) {
+	return &transport.AuthResult{UserID: "u1", Email: "u@ex.com", Token: "tok"}, nil
+}
+func (handlerUC) ChallengeUser(_ context.Context, challengeToken string) (string, error) {
+	return "u1", nil
+}
+func (handlerUC) EnrollTwoFactor(_ context.Context, userID string) (*transport.TwoFactorEnrollment, error) {
+	return &transport.TwoFactorEnrollment{}, nil
+}
+func (handlerUC) ConfirmTwoFactor(_ context.Context, userID, sessionID, code string) (*transport.TwoFactorConfirmResult, error) {
+	return &transport.TwoFactorConfirmResult{}, nil
+}
+func (handlerUC) DisableTwoFactor(_ context.Context, userID, code string) error { return nil }
+func (handlerUC) ListUserRoles(_ context.Context, userID string) ([]string, error) {
+	return []string{"customer"}, nil
+}
+func (handlerUC) GrantRole(_ context.Context, actorID, userID, role string) error  { return nil }
+func (handlerUC) RevokeRole(_ context.Context, actorID, userID, role string) error { return nil }
+func (handlerUC) RequestPhoneCode(_ context.Context, phone string) (*transport.PhoneCodeSent, error) {
+	return &transport.PhoneCodeSent{Phone: phone}, nil
+}
+func (handlerUC) ExternalProviders() []string { return nil }
+func (handlerUC) StartExternalLogin(_ context.Context, provider string) (*transport.ExternalLoginStart, error) {
+	return nil, nil
+}
+func (handlerUC) FinishExternalLogin(_ context.Context, provider, stateToken, state, code string) (*transport.AuthResult, error) {
+	return nil, nil
+}
+func (handlerUC) ListUserEvents(_ context.Context, userID, lastID string, limit int) ([]*domain.AuthEvent, error) {
+	return nil, nil
+}
+func (handlerUC) ListAuthEvents(_ context.Context, filter *domain.AuthEventFilter) ([]*domain.AuthEvent, error) {
+	return nil, nil
+}
+func (handlerUC) CreateAPIKey(_ context.Context, userID string, roles []string, name string, scopes []string, ttlDays int) (*domain.APIKey, string, error) {
+	return nil, "", nil
+}
+func (handlerUC) ListAPIKeys(_ context.Context, userID string) ([]*domain.APIKey, error) {
+	return nil, nil
+}
+func (handlerUC) RevokeAPIKey(_ context.Context, userID, keyID string) error {
+	return nil
+}
+func (handlerUC) LoginByPhone(_ context.Context, phone, code string) (*transport.AuthResult, error) {
+	return &transport.AuthResult{UserID: "u1", Token: "tok"}, nil
+}
 
 var _ AuthUseCaseInterface = handlerUC{}
 
 func TestAuthHandler_Register(t *testing.T) {
-	logg := logger.NewLogger("auth_handler_test", slog.LevelDebug)
-	h := NewAuthHandler(handlerUC{}, logg)
+	h := NewAuthHandler(handlerUC{})
 
 	body, _ := json.Marshal(transport.RegisterRequest{Email: "u@ex.com", Password: "Str0ng!Pass"})
 	req := httptest.NewRequest(http.MethodPost, "/api/v0/auth/signup", bytes.NewReader(body))
@@ -111,8 +183,7 @@ func TestAuthHandler_Register(t *testing.T) {
 }
 
 func TestAuthHandler_Login(t *testing.T) {
-	logg := logger.NewLogger("auth_handler_test", slog.LevelDebug)
-	h := NewAuthHandler(handlerUC{}, logg)
+	h := NewAuthHandler(handlerUC{})
 
 	body, _ := json.Marshal(transport.LoginRequest{Email: "u@ex.com", Password: "Str0ng!Pass"})
 	req := httptest.NewRequest(http.MethodPost, "/api/v0/auth/login", bytes.NewReader(body))
@@ -127,11 +198,10 @@ func TestAuthHandler_Login(t *testing.T) {
 }
 
 func TestAuthHandler_Refresh(t *testing.T) {
-	logg := logger.NewLogger("auth_handler_test", slog.LevelDebug)
-	h := NewAuthHandler(handlerUC{}, logg)
+	h := NewAuthHandler(handlerUC{})
 
 	req := httptest.NewRequest(http.MethodPost, "/api/v0/auth/refresh", nil)
-	req.AddCookie(&http.Cookie{Name: "jwt_token", Value: "tok"})
+	req.AddCookie(&http.Cookie{Name: "refresh_token", Value: "tok"})
 	w := httptest.NewRecorder()
 
 	h.RefreshToken(w, req)
@@ -145,11 +215,9 @@ func TestRegister_OK(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	logg := logger.NewLogger("auth_handler_test", slog.LevelDebug)
-
 	uc := mock.NewMockAuthUseCaseInterface(ctrl)
 
-	h := NewAuthHandler(uc, logg)
+	h := NewAuthHandler(uc)
 
 	reqBody, _ := json.Marshal(transport.RegisterRequest{Email: "u@ex.com", Password: "Str0ng!Pass"})
 	req := httptest.NewRequest(http.MethodPost, "/api/v0/auth/signup", bytes.NewReader(reqBody))
@@ -173,9 +241,8 @@ func TestLogin_Unauthorized(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	logg := logger.NewLogger("auth_handler_test", slog.LevelDebug)
 	uc := mock.NewMockAuthUseCaseInterface(ctrl)
-	h := NewAuthHandler(uc, logg)
+	h := NewAuthHandler(uc)
 
 	reqBody, _ := json.Marshal(transport.LoginRequest{Email: "u@ex.com", Password: "bad"})
 	req := httptest.NewRequest(http.MethodPost, "/api/v0/auth/login", bytes.NewReader(reqBody))
@@ -197,12 +264,11 @@ func TestRefresh_OK(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	logg := logger.NewLogger("auth_handler_test", slog.LevelDebug)
 	uc := mock.NewMockAuthUseCaseInterface(ctrl)
-	h := NewAuthHandler(uc, logg)
+	h := NewAuthHandler(uc)
 
 	req := httptest.NewRequest(http.MethodPost, "/api/v0/auth/refresh", nil)
-	req.AddCookie(&http.Cookie{Name: "jwt_token", Value: "tok"})
+	req.AddCookie(&http.Cookie{Name: "refresh_token", Value: "tok"})
 	w := httptest.NewRecorder()
 
 	uc.EXPECT().
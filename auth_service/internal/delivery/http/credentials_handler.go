@@ -0,0 +1,121 @@
+package http
+
+import (
+	"apple_backend/auth_service/internal/delivery/transport"
+	"apple_backend/auth_service/internal/domain"
+	"apple_backend/pkg/logger"
+	"errors"
+	"log/slog"
+	"net/http"
+)
+
+// credentialsError отвечает на типовые ошибки смены пароля и email и
+// возвращает false, если ошибка не из их числа.
+func (h *AuthHandler) credentialsError(w http.ResponseWriter, r *http.Request, op string, err error) bool {
+	ctx := r.Context()
+
+	var throttled *domain.ThrottledError
+	if errors.As(err, &throttled) {
+		setRetryAfter(w, throttled.RetryAfter)
+		h.rs.Error(ctx, w, http.StatusTooManyRequests, op, domain.ErrTooManyRequests, nil)
+		return true
+	}
+	if h.weakPassword(ctx, w, op, err) {
+		return true
+	}
+	switch err {
+	// 403, а не 401: сессия действительна, неверен только введенный пароль
+	// или код, либо сессия слишком старая для смены учетных данных
+	case domain.ErrInvalidPassword, domain.ErrTwoFactorCodeInvalid, domain.ErrReauthRequired:
+		h.rs.Error(ctx, w, http.StatusForbidden, op, err, nil)
+	case domain.ErrInvalidEmail, domain.ErrEmailUnchanged, domain.ErrEmailChangeInvalid, domain.ErrWeakPassword:
+		h.rs.Error(ctx, w, http.StatusBadRequest, op, err, nil)
+	case domain.ErrUserAlreadyExists:
+		h.rs.Error(ctx, w, http.StatusConflict, op, err, nil)
+	default:
+		return false
+	}
+	return true
+}
+
+// ChangePassword меняет пароль по текущему; остальные сессии завершаются
+func (h *AuthHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "handler ChangePassword start")
+
+	var req transport.ChangePasswordRequest
+	if !h.decodeJSON(w, r, "ChangePassword", &req) {
+		return
+	}
+	claims, err := h.authenticate(r)
+	if err != nil {
+		log.WarnContext(ctx, "handler ChangePassword unauthorized", slog.Any("err", err))
+		h.rs.Error(ctx, w, http.StatusUnauthorized, "ChangePassword", domain.ErrUnauthorized, nil)
+		return
+	}
+
+	if err := h.uc.ChangePassword(ctx, claims.UserID, claims.SessionID, req.CurrentPassword, req.Code, req.NewPassword); err != nil {
+		log.ErrorContext(ctx, "usecase ChangePassword failed", slog.Any("err", err))
+		if !h.credentialsError(w, r, "ChangePassword", err) {
+			h.rs.Error(ctx, w, http.StatusInternalServerError, "ChangePassword", domain.ErrInternalServer, err)
+		}
+		return
+	}
+
+	h.rs.Send(ctx, w, http.StatusOK, map[string]string{"message": "password changed"})
+	log.InfoContext(ctx, "handler ChangePassword success", slog.String("user_id", claims.UserID))
+}
+
+// RequestEmailChange отправляет подтверждение на новый адрес
+func (h *AuthHandler) RequestEmailChange(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "handler RequestEmailChange start")
+
+	var req transport.ChangeEmailRequest
+	if !h.decodeJSON(w, r, "RequestEmailChange", &req) {
+		return
+	}
+	claims, err := h.authenticate(r)
+	if err != nil {
+		log.WarnContext(ctx, "handler RequestEmailChange unauthorized", slog.Any("err", err))
+		h.rs.Error(ctx, w, http.StatusUnauthorized, "RequestEmailChange", domain.ErrUnauthorized, nil)
+		return
+	}
+
+	if err := h.uc.RequestEmailChange(ctx, claims.UserID, claims.SessionID, req.Password, req.Code, req.NewEmail); err != nil {
+		log.ErrorContext(ctx, "usecase RequestEmailChange failed", slog.Any("err", err))
+		if !h.credentialsError(w, r, "RequestEmailChange", err) {
+			h.rs.Error(ctx, w, http.StatusInternalServerError, "RequestEmailChange", domain.ErrInternalServer, err)
+		}
+		return
+	}
+
+	h.rs.Send(ctx, w, http.StatusAccepted, map[string]string{"message": "confirmation email sent"})
+	log.InfoContext(ctx, "handler RequestEmailChange success", slog.String("user_id", claims.UserID))
+}
+
+// ConfirmEmailChange меняет адрес по ссылке из письма; сессия не нужна,
+// письмо могут открыть на другом устройстве
+func (h *AuthHandler) ConfirmEmailChange(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "handler ConfirmEmailChange start")
+
+	var req transport.ConfirmEmailChangeRequest
+	if !h.decodeJSON(w, r, "ConfirmEmailChange", &req) {
+		return
+	}
+
+	if err := h.uc.ConfirmEmailChange(ctx, req.Token); err != nil {
+		log.ErrorContext(ctx, "usecase ConfirmEmailChange failed", slog.Any("err", err))
+		if !h.credentialsError(w, r, "ConfirmEmailChange", err) {
+			h.rs.Error(ctx, w, http.StatusInternalServerError, "ConfirmEmailChange", domain.ErrInternalServer, err)
+		}
+		return
+	}
+
+	h.rs.Send(ctx, w, http.StatusOK, map[string]string{"message": "email changed"})
+	log.InfoContext(ctx, "handler ConfirmEmailChange success")
+}
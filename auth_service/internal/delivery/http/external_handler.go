@@ -0,0 +1,139 @@
+package http
+
+import (
+	"apple_backend/auth_service/internal/delivery/transport"
+	"apple_backend/auth_service/internal/domain"
+	"apple_backend/pkg/logger"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+const (
+	externalStateCookieName = "oidc_state"
+	// cookie нужна только на время входа и только эндпоинтам OIDC
+	externalStateCookiePath = "/api/v0/auth/oidc"
+)
+
+func setExternalStateCookie(w http.ResponseWriter, token string, expires time.Time) {
+	secure, sameSite, domain := cookieSettings()
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     externalStateCookieName,
+		Value:    token,
+		Expires:  expires,
+		Path:     externalStateCookiePath,
+		HttpOnly: true,
+		Secure:   secure,
+		SameSite: sameSite,
+		Domain:   domain,
+	})
+}
+
+func clearExternalStateCookie(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     externalStateCookieName,
+		Value:    "",
+		Expires:  time.Now().Add(-time.Hour),
+		Path:     externalStateCookiePath,
+		HttpOnly: true,
+	})
+}
+
+// externalError отвечает на типовые ошибки входа через внешний сервис и
+// возвращает false, если ошибка не из их числа.
+func (h *AuthHandler) externalError(w http.ResponseWriter, r *http.Request, op string, err error) bool {
+	ctx := r.Context()
+	switch err {
+	case domain.ErrUnknownProvider:
+		h.rs.Error(ctx, w, http.StatusNotFound, op, err, nil)
+	case domain.ErrExternalStateInvalid, domain.ErrExternalLoginFailed:
+		h.rs.Error(ctx, w, http.StatusUnauthorized, op, err, nil)
+	case domain.ErrExternalIdentityConflict:
+		h.rs.Error(ctx, w, http.StatusConflict, op, err, nil)
+	case domain.ErrTwoFactorRequired, domain.ErrAccountDeleted:
+		h.rs.Error(ctx, w, http.StatusForbidden, op, err, nil)
+	default:
+		return false
+	}
+	return true
+}
+
+func (h *AuthHandler) ExternalProviders(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if r.Method != http.MethodGet {
+		h.rs.Error(ctx, w, http.StatusMethodNotAllowed, "ExternalProviders", domain.ErrHTTPMethod, nil)
+		return
+	}
+	h.rs.Send(ctx, w, http.StatusOK, transport.ExternalProvidersResponse{Providers: h.uc.ExternalProviders()})
+}
+
+// StartExternalLogin перенаправляет браузер на страницу входа провайдера
+func (h *AuthHandler) StartExternalLogin(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+	provider := r.PathValue("provider")
+	log.InfoContext(ctx, "handler StartExternalLogin start", slog.String("provider", provider))
+
+	if r.Method != http.MethodGet {
+		log.WarnContext(ctx, "handler StartExternalLogin wrong method")
+		h.rs.Error(ctx, w, http.StatusMethodNotAllowed, "StartExternalLogin", domain.ErrHTTPMethod, nil)
+		return
+	}
+
+	res, err := h.uc.StartExternalLogin(ctx, provider)
+	if err != nil {
+		log.ErrorContext(ctx, "usecase StartExternalLogin failed", slog.Any("err", err))
+		if !h.externalError(w, r, "StartExternalLogin", err) {
+			h.rs.Error(ctx, w, http.StatusInternalServerError, "StartExternalLogin", domain.ErrInternalServer, err)
+		}
+		return
+	}
+
+	setExternalStateCookie(w, res.StateToken, res.ExpiresAt)
+	w.Header().Set("Cache-Control", "no-store")
+	http.Redirect(w, r, res.AuthURL, http.StatusFound)
+	log.InfoContext(ctx, "handler StartExternalLogin redirected", slog.String("provider", provider))
+}
+
+// ExternalCallback завершает вход: фронтенд передает code и state, с
+// которыми провайдер вернул пользователя.
+func (h *AuthHandler) ExternalCallback(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+	provider := r.PathValue("provider")
+	log.InfoContext(ctx, "handler ExternalCallback start", slog.String("provider", provider))
+
+	var req transport.ExternalCallbackRequest
+	if !h.decodeJSON(w, r, "ExternalCallback", &req) {
+		return
+	}
+	cookie, err := r.Cookie(externalStateCookieName)
+	if err != nil {
+		log.WarnContext(ctx, "handler ExternalCallback no state cookie")
+		h.rs.Error(ctx, w, http.StatusUnauthorized, "ExternalCallback", domain.ErrExternalStateInvalid, nil)
+		return
+	}
+	// state одноразовый: повторить обмен с тем же кодом нельзя
+	clearExternalStateCookie(w)
+
+	res, err := h.uc.FinishExternalLogin(ctx, provider, cookie.Value, req.State, req.Code)
+	if err != nil {
+		log.ErrorContext(ctx, "usecase FinishExternalLogin failed", slog.Any("err", err))
+		if !h.externalError(w, r, "ExternalCallback", err) {
+			h.rs.Error(ctx, w, http.StatusInternalServerError, "ExternalCallback", domain.ErrInternalServer, err)
+		}
+		return
+	}
+
+	if res.Challenge != nil {
+		h.rs.Send(ctx, w, http.StatusOK, res.Challenge)
+		log.InfoContext(ctx, "handler ExternalCallback second factor required", slog.String("user_id", res.UserID))
+		return
+	}
+
+	h.mergeGuestCart(w, r, res.UserID)
+	h.setSessionCookies(w, res)
+	h.rs.Send(ctx, w, http.StatusOK, res)
+	log.InfoContext(ctx, "handler ExternalCallback success", slog.String("user_id", res.UserID))
+}
@@ -0,0 +1,52 @@
+package http
+
+import (
+	"apple_backend/pkg/guestcart"
+	"apple_backend/pkg/logger"
+	"log/slog"
+	"net/http"
+)
+
+// GuestCarts переносит корзину, собранную до входа, в корзину пользователя
+type GuestCarts struct {
+	cookies *guestcart.Cookies
+	merger  guestcart.Merger
+}
+
+func NewGuestCarts(cookies *guestcart.Cookies, merger guestcart.Merger) *GuestCarts {
+	return &GuestCarts{cookies: cookies, merger: merger}
+}
+
+// mergeGuestCart вызывается после успешного входа или регистрации. Ошибка
+// слияния не мешает входу: cookie остается, и слияние повторится при
+// следующем входе.
+func (h *AuthHandler) mergeGuestCart(w http.ResponseWriter, r *http.Request, userID string) {
+	if h.guestCarts == nil {
+		return
+	}
+	if _, err := r.Cookie(guestcart.CookieName); err != nil {
+		return
+	}
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+
+	cartID, err := h.guestCarts.cookies.CartID(r)
+	if err != nil {
+		log.WarnContext(ctx, "handler mergeGuestCart invalid cookie", slog.String("user_id", userID))
+		h.guestCarts.cookies.Clear(w)
+		return
+	}
+	merged, err := h.guestCarts.merger.Merge(ctx, cartID, userID)
+	if err != nil {
+		log.ErrorContext(ctx, "handler mergeGuestCart failed",
+			slog.Any("err", err),
+			slog.String("user_id", userID),
+			slog.String("cart_id", cartID))
+		return
+	}
+	h.guestCarts.cookies.Clear(w)
+	log.InfoContext(ctx, "handler mergeGuestCart success",
+		slog.String("user_id", userID),
+		slog.String("cart_id", cartID),
+		slog.Int("items", merged))
+}
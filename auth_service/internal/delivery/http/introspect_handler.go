@@ -0,0 +1,71 @@
+package http
+
+import (
+	"apple_backend/auth_service/internal/delivery/transport"
+	"apple_backend/auth_service/internal/domain"
+	"apple_backend/pkg/http_response"
+	"apple_backend/pkg/logger"
+	"context"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+type Introspector interface {
+	Introspect(ctx context.Context, token string) (*transport.Introspection, error)
+}
+
+// IntrospectHandler — POST /auth/introspect для других сервисов. Доступ
+// только по общему секрету; CSRF здесь не нужен, браузер сюда не ходит.
+type IntrospectHandler struct {
+	uc     Introspector
+	secret []byte
+	rs     *http_response.ResponseSender
+}
+
+func NewIntrospectHandler(uc Introspector, secret string) *IntrospectHandler {
+	return &IntrospectHandler{
+		uc:     uc,
+		secret: []byte(secret),
+		rs:     http_response.NewResponseSender(logger.Global()),
+	}
+}
+
+func (h *IntrospectHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if r.Method != http.MethodPost {
+		h.rs.Error(ctx, w, http.StatusMethodNotAllowed, "Introspect", domain.ErrHTTPMethod, nil)
+		return
+	}
+	if !h.clientAuthorized(r) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="introspect"`)
+		h.rs.Error(ctx, w, http.StatusUnauthorized, "Introspect", domain.ErrUnauthorized, nil)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		h.rs.Error(ctx, w, http.StatusBadRequest, "Introspect", domain.ErrRequestParams, err)
+		return
+	}
+	token := r.PostForm.Get("token")
+	if token == "" {
+		h.rs.Error(ctx, w, http.StatusBadRequest, "Introspect", domain.ErrRequestParams, nil)
+		return
+	}
+
+	res, err := h.uc.Introspect(ctx, token)
+	if err != nil {
+		h.rs.Error(ctx, w, http.StatusInternalServerError, "Introspect", domain.ErrInternalServer, err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	h.rs.Send(ctx, w, http.StatusOK, res)
+}
+
+func (h *IntrospectHandler) clientAuthorized(r *http.Request) bool {
+	got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || len(h.secret) == 0 {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(got), h.secret) == 1
+}
@@ -0,0 +1,45 @@
+package http
+
+import (
+	"apple_backend/auth_service/internal/domain"
+	"apple_backend/pkg/http_response"
+	"apple_backend/pkg/jwtkeys"
+	"apple_backend/pkg/logger"
+	"net/http"
+)
+
+// KeyPublisher отдает публичные ключи, которыми можно проверить токены
+type KeyPublisher interface {
+	JWKS() (*jwtkeys.Set, error)
+}
+
+type JWKSHandler struct {
+	keys KeyPublisher
+	rs   *http_response.ResponseSender
+}
+
+func NewJWKSHandler(keys KeyPublisher) *JWKSHandler {
+	return &JWKSHandler{
+		keys: keys,
+		rs:   http_response.NewResponseSender(logger.Global()),
+	}
+}
+
+// ServeHTTP — GET /.well-known/jwks.json
+func (h *JWKSHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if r.Method != http.MethodGet {
+		h.rs.Error(ctx, w, http.StatusMethodNotAllowed, "JWKS", domain.ErrHTTPMethod, nil)
+		return
+	}
+
+	set, err := h.keys.JWKS()
+	if err != nil {
+		h.rs.Error(ctx, w, http.StatusInternalServerError, "JWKS", domain.ErrInternalServer, err)
+		return
+	}
+
+	// сервисы кэшируют ключи сами, но и прокси не стоит дергать нас на каждый запрос
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	h.rs.Send(ctx, w, http.StatusOK, set)
+}
@@ -1,5 +1,5 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: auth_service/internal/delivery/http/auth_handler.go
+// Source: internal/delivery/http/auth_handler.go
 
 // Package mock is a generated GoMock package.
 package mock
@@ -36,6 +36,152 @@ func (m *MockAuthUseCaseInterface) EXPECT() *MockAuthUseCaseInterfaceMockRecorde
 	return m.recorder
 }
 
+// ChallengeUser mocks base method.
+func (m *MockAuthUseCaseInterface) ChallengeUser(ctx context.Context, challengeToken string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChallengeUser", ctx, challengeToken)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChallengeUser indicates an expected call of ChallengeUser.
+func (mr *MockAuthUseCaseInterfaceMockRecorder) ChallengeUser(ctx, challengeToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChallengeUser", reflect.TypeOf((*MockAuthUseCaseInterface)(nil).ChallengeUser), ctx, challengeToken)
+}
+
+// ChangePassword mocks base method.
+func (m *MockAuthUseCaseInterface) ChangePassword(ctx context.Context, userID, sessionID, currentPassword, code, newPassword string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangePassword", ctx, userID, sessionID, currentPassword, code, newPassword)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChangePassword indicates an expected call of ChangePassword.
+func (mr *MockAuthUseCaseInterfaceMockRecorder) ChangePassword(ctx, userID, sessionID, currentPassword, code, newPassword interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePassword", reflect.TypeOf((*MockAuthUseCaseInterface)(nil).ChangePassword), ctx, userID, sessionID, currentPassword, code, newPassword)
+}
+
+// ConfirmEmailChange mocks base method.
+func (m *MockAuthUseCaseInterface) ConfirmEmailChange(ctx context.Context, token string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmEmailChange", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ConfirmEmailChange indicates an expected call of ConfirmEmailChange.
+func (mr *MockAuthUseCaseInterfaceMockRecorder) ConfirmEmailChange(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmEmailChange", reflect.TypeOf((*MockAuthUseCaseInterface)(nil).ConfirmEmailChange), ctx, token)
+}
+
+// ConfirmTwoFactor mocks base method.
+func (m *MockAuthUseCaseInterface) ConfirmTwoFactor(ctx context.Context, userID, sessionID, code string) (*transport.TwoFactorConfirmResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmTwoFactor", ctx, userID, sessionID, code)
+	ret0, _ := ret[0].(*transport.TwoFactorConfirmResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConfirmTwoFactor indicates an expected call of ConfirmTwoFactor.
+func (mr *MockAuthUseCaseInterfaceMockRecorder) ConfirmTwoFactor(ctx, userID, sessionID, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmTwoFactor", reflect.TypeOf((*MockAuthUseCaseInterface)(nil).ConfirmTwoFactor), ctx, userID, sessionID, code)
+}
+
+// CreateAPIKey mocks base method.
+func (m *MockAuthUseCaseInterface) CreateAPIKey(ctx context.Context, userID string, roles []string, name string, scopes []string, ttlDays int) (*domain.APIKey, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAPIKey", ctx, userID, roles, name, scopes, ttlDays)
+	ret0, _ := ret[0].(*domain.APIKey)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateAPIKey indicates an expected call of CreateAPIKey.
+func (mr *MockAuthUseCaseInterfaceMockRecorder) CreateAPIKey(ctx, userID, roles, name, scopes, ttlDays interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAPIKey", reflect.TypeOf((*MockAuthUseCaseInterface)(nil).CreateAPIKey), ctx, userID, roles, name, scopes, ttlDays)
+}
+
+// DisableTwoFactor mocks base method.
+func (m *MockAuthUseCaseInterface) DisableTwoFactor(ctx context.Context, userID, code string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableTwoFactor", ctx, userID, code)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DisableTwoFactor indicates an expected call of DisableTwoFactor.
+func (mr *MockAuthUseCaseInterfaceMockRecorder) DisableTwoFactor(ctx, userID, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableTwoFactor", reflect.TypeOf((*MockAuthUseCaseInterface)(nil).DisableTwoFactor), ctx, userID, code)
+}
+
+// EnrollTwoFactor mocks base method.
+func (m *MockAuthUseCaseInterface) EnrollTwoFactor(ctx context.Context, userID string) (*transport.TwoFactorEnrollment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnrollTwoFactor", ctx, userID)
+	ret0, _ := ret[0].(*transport.TwoFactorEnrollment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnrollTwoFactor indicates an expected call of EnrollTwoFactor.
+func (mr *MockAuthUseCaseInterfaceMockRecorder) EnrollTwoFactor(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnrollTwoFactor", reflect.TypeOf((*MockAuthUseCaseInterface)(nil).EnrollTwoFactor), ctx, userID)
+}
+
+// ExternalProviders mocks base method.
+func (m *MockAuthUseCaseInterface) ExternalProviders() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExternalProviders")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// ExternalProviders indicates an expected call of ExternalProviders.
+func (mr *MockAuthUseCaseInterfaceMockRecorder) ExternalProviders() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExternalProviders", reflect.TypeOf((*MockAuthUseCaseInterface)(nil).ExternalProviders))
+}
+
+// FinishExternalLogin mocks base method.
+func (m *MockAuthUseCaseInterface) FinishExternalLogin(ctx context.Context, provider, stateToken, state, code string) (*transport.AuthResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishExternalLogin", ctx, provider, stateToken, state, code)
+	ret0, _ := ret[0].(*transport.AuthResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FinishExternalLogin indicates an expected call of FinishExternalLogin.
+func (mr *MockAuthUseCaseInterfaceMockRecorder) FinishExternalLogin(ctx, provider, stateToken, state, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishExternalLogin", reflect.TypeOf((*MockAuthUseCaseInterface)(nil).FinishExternalLogin), ctx, provider, stateToken, state, code)
+}
+
+// ForgotPassword mocks base method.
+func (m *MockAuthUseCaseInterface) ForgotPassword(ctx context.Context, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForgotPassword", ctx, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ForgotPassword indicates an expected call of ForgotPassword.
+func (mr *MockAuthUseCaseInterfaceMockRecorder) ForgotPassword(ctx, email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForgotPassword", reflect.TypeOf((*MockAuthUseCaseInterface)(nil).ForgotPassword), ctx, email)
+}
+
 // GetUserByID mocks base method.
 func (m *MockAuthUseCaseInterface) GetUserByID(ctx context.Context, userID string) (*domain.User, error) {
 	m.ctrl.T.Helper()
@@ -51,6 +197,95 @@ func (mr *MockAuthUseCaseInterfaceMockRecorder) GetUserByID(ctx, userID interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByID", reflect.TypeOf((*MockAuthUseCaseInterface)(nil).GetUserByID), ctx, userID)
 }
 
+// GrantRole mocks base method.
+func (m *MockAuthUseCaseInterface) GrantRole(ctx context.Context, actorID, userID, role string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GrantRole", ctx, actorID, userID, role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GrantRole indicates an expected call of GrantRole.
+func (mr *MockAuthUseCaseInterfaceMockRecorder) GrantRole(ctx, actorID, userID, role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GrantRole", reflect.TypeOf((*MockAuthUseCaseInterface)(nil).GrantRole), ctx, actorID, userID, role)
+}
+
+// ListAPIKeys mocks base method.
+func (m *MockAuthUseCaseInterface) ListAPIKeys(ctx context.Context, userID string) ([]*domain.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAPIKeys", ctx, userID)
+	ret0, _ := ret[0].([]*domain.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAPIKeys indicates an expected call of ListAPIKeys.
+func (mr *MockAuthUseCaseInterfaceMockRecorder) ListAPIKeys(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAPIKeys", reflect.TypeOf((*MockAuthUseCaseInterface)(nil).ListAPIKeys), ctx, userID)
+}
+
+// ListAuthEvents mocks base method.
+func (m *MockAuthUseCaseInterface) ListAuthEvents(ctx context.Context, filter *domain.AuthEventFilter) ([]*domain.AuthEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAuthEvents", ctx, filter)
+	ret0, _ := ret[0].([]*domain.AuthEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAuthEvents indicates an expected call of ListAuthEvents.
+func (mr *MockAuthUseCaseInterfaceMockRecorder) ListAuthEvents(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAuthEvents", reflect.TypeOf((*MockAuthUseCaseInterface)(nil).ListAuthEvents), ctx, filter)
+}
+
+// ListSessions mocks base method.
+func (m *MockAuthUseCaseInterface) ListSessions(ctx context.Context, userID string) ([]*domain.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSessions", ctx, userID)
+	ret0, _ := ret[0].([]*domain.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSessions indicates an expected call of ListSessions.
+func (mr *MockAuthUseCaseInterfaceMockRecorder) ListSessions(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSessions", reflect.TypeOf((*MockAuthUseCaseInterface)(nil).ListSessions), ctx, userID)
+}
+
+// ListUserEvents mocks base method.
+func (m *MockAuthUseCaseInterface) ListUserEvents(ctx context.Context, userID, lastID string, limit int) ([]*domain.AuthEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserEvents", ctx, userID, lastID, limit)
+	ret0, _ := ret[0].([]*domain.AuthEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserEvents indicates an expected call of ListUserEvents.
+func (mr *MockAuthUseCaseInterfaceMockRecorder) ListUserEvents(ctx, userID, lastID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserEvents", reflect.TypeOf((*MockAuthUseCaseInterface)(nil).ListUserEvents), ctx, userID, lastID, limit)
+}
+
+// ListUserRoles mocks base method.
+func (m *MockAuthUseCaseInterface) ListUserRoles(ctx context.Context, userID string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserRoles", ctx, userID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserRoles indicates an expected call of ListUserRoles.
+func (mr *MockAuthUseCaseInterfaceMockRecorder) ListUserRoles(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserRoles", reflect.TypeOf((*MockAuthUseCaseInterface)(nil).ListUserRoles), ctx, userID)
+}
+
 // Login mocks base method.
 func (m *MockAuthUseCaseInterface) Login(ctx context.Context, email, password string) (*transport.AuthResult, error) {
 	m.ctrl.T.Helper()
@@ -66,19 +301,63 @@ func (mr *MockAuthUseCaseInterfaceMockRecorder) Login(ctx, email, password inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockAuthUseCaseInterface)(nil).Login), ctx, email, password)
 }
 
+// LoginByPhone mocks base method.
+func (m *MockAuthUseCaseInterface) LoginByPhone(ctx context.Context, phone, code string) (*transport.AuthResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoginByPhone", ctx, phone, code)
+	ret0, _ := ret[0].(*transport.AuthResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoginByPhone indicates an expected call of LoginByPhone.
+func (mr *MockAuthUseCaseInterfaceMockRecorder) LoginByPhone(ctx, phone, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoginByPhone", reflect.TypeOf((*MockAuthUseCaseInterface)(nil).LoginByPhone), ctx, phone, code)
+}
+
+// LoginTwoFactor mocks base method.
+func (m *MockAuthUseCaseInterface) LoginTwoFactor(ctx context.Context, challengeToken, code string) (*transport.AuthResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoginTwoFactor", ctx, challengeToken, code)
+	ret0, _ := ret[0].(*transport.AuthResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoginTwoFactor indicates an expected call of LoginTwoFactor.
+func (mr *MockAuthUseCaseInterfaceMockRecorder) LoginTwoFactor(ctx, challengeToken, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoginTwoFactor", reflect.TypeOf((*MockAuthUseCaseInterface)(nil).LoginTwoFactor), ctx, challengeToken, code)
+}
+
+// Logout mocks base method.
+func (m *MockAuthUseCaseInterface) Logout(ctx context.Context, refreshToken string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Logout", ctx, refreshToken)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Logout indicates an expected call of Logout.
+func (mr *MockAuthUseCaseInterfaceMockRecorder) Logout(ctx, refreshToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logout", reflect.TypeOf((*MockAuthUseCaseInterface)(nil).Logout), ctx, refreshToken)
+}
+
 // RefreshToken mocks base method.
-func (m *MockAuthUseCaseInterface) RefreshToken(ctx context.Context, tokenString string) (*transport.AuthResult, error) {
+func (m *MockAuthUseCaseInterface) RefreshToken(ctx context.Context, refreshToken string) (*transport.AuthResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RefreshToken", ctx, tokenString)
+	ret := m.ctrl.Call(m, "RefreshToken", ctx, refreshToken)
 	ret0, _ := ret[0].(*transport.AuthResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // RefreshToken indicates an expected call of RefreshToken.
-func (mr *MockAuthUseCaseInterfaceMockRecorder) RefreshToken(ctx, tokenString interface{}) *gomock.Call {
+func (mr *MockAuthUseCaseInterfaceMockRecorder) RefreshToken(ctx, refreshToken interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshToken", reflect.TypeOf((*MockAuthUseCaseInterface)(nil).RefreshToken), ctx, tokenString)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshToken", reflect.TypeOf((*MockAuthUseCaseInterface)(nil).RefreshToken), ctx, refreshToken)
 }
 
 // Register mocks base method.
@@ -96,6 +375,149 @@ func (mr *MockAuthUseCaseInterfaceMockRecorder) Register(ctx, email, password in
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockAuthUseCaseInterface)(nil).Register), ctx, email, password)
 }
 
+// RequestEmailChange mocks base method.
+func (m *MockAuthUseCaseInterface) RequestEmailChange(ctx context.Context, userID, sessionID, password, code, newEmail string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestEmailChange", ctx, userID, sessionID, password, code, newEmail)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequestEmailChange indicates an expected call of RequestEmailChange.
+func (mr *MockAuthUseCaseInterfaceMockRecorder) RequestEmailChange(ctx, userID, sessionID, password, code, newEmail interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestEmailChange", reflect.TypeOf((*MockAuthUseCaseInterface)(nil).RequestEmailChange), ctx, userID, sessionID, password, code, newEmail)
+}
+
+// RequestPhoneCode mocks base method.
+func (m *MockAuthUseCaseInterface) RequestPhoneCode(ctx context.Context, phone string) (*transport.PhoneCodeSent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestPhoneCode", ctx, phone)
+	ret0, _ := ret[0].(*transport.PhoneCodeSent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RequestPhoneCode indicates an expected call of RequestPhoneCode.
+func (mr *MockAuthUseCaseInterfaceMockRecorder) RequestPhoneCode(ctx, phone interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestPhoneCode", reflect.TypeOf((*MockAuthUseCaseInterface)(nil).RequestPhoneCode), ctx, phone)
+}
+
+// ResendVerification mocks base method.
+func (m *MockAuthUseCaseInterface) ResendVerification(ctx context.Context, userID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResendVerification", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResendVerification indicates an expected call of ResendVerification.
+func (mr *MockAuthUseCaseInterfaceMockRecorder) ResendVerification(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResendVerification", reflect.TypeOf((*MockAuthUseCaseInterface)(nil).ResendVerification), ctx, userID)
+}
+
+// ResetPassword mocks base method.
+func (m *MockAuthUseCaseInterface) ResetPassword(ctx context.Context, token, newPassword string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetPassword", ctx, token, newPassword)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetPassword indicates an expected call of ResetPassword.
+func (mr *MockAuthUseCaseInterfaceMockRecorder) ResetPassword(ctx, token, newPassword interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPassword", reflect.TypeOf((*MockAuthUseCaseInterface)(nil).ResetPassword), ctx, token, newPassword)
+}
+
+// RestoreAccount mocks base method.
+func (m *MockAuthUseCaseInterface) RestoreAccount(ctx context.Context, email, password string) (*transport.AuthResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreAccount", ctx, email, password)
+	ret0, _ := ret[0].(*transport.AuthResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreAccount indicates an expected call of RestoreAccount.
+func (mr *MockAuthUseCaseInterfaceMockRecorder) RestoreAccount(ctx, email, password interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreAccount", reflect.TypeOf((*MockAuthUseCaseInterface)(nil).RestoreAccount), ctx, email, password)
+}
+
+// RevokeAPIKey mocks base method.
+func (m *MockAuthUseCaseInterface) RevokeAPIKey(ctx context.Context, userID, keyID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAPIKey", ctx, userID, keyID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAPIKey indicates an expected call of RevokeAPIKey.
+func (mr *MockAuthUseCaseInterfaceMockRecorder) RevokeAPIKey(ctx, userID, keyID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAPIKey", reflect.TypeOf((*MockAuthUseCaseInterface)(nil).RevokeAPIKey), ctx, userID, keyID)
+}
+
+// RevokeOtherSessions mocks base method.
+func (m *MockAuthUseCaseInterface) RevokeOtherSessions(ctx context.Context, userID, currentSessionID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeOtherSessions", ctx, userID, currentSessionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeOtherSessions indicates an expected call of RevokeOtherSessions.
+func (mr *MockAuthUseCaseInterfaceMockRecorder) RevokeOtherSessions(ctx, userID, currentSessionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeOtherSessions", reflect.TypeOf((*MockAuthUseCaseInterface)(nil).RevokeOtherSessions), ctx, userID, currentSessionID)
+}
+
+// RevokeRole mocks base method.
+func (m *MockAuthUseCaseInterface) RevokeRole(ctx context.Context, actorID, userID, role string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeRole", ctx, actorID, userID, role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeRole indicates an expected call of RevokeRole.
+func (mr *MockAuthUseCaseInterfaceMockRecorder) RevokeRole(ctx, actorID, userID, role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeRole", reflect.TypeOf((*MockAuthUseCaseInterface)(nil).RevokeRole), ctx, actorID, userID, role)
+}
+
+// RevokeSession mocks base method.
+func (m *MockAuthUseCaseInterface) RevokeSession(ctx context.Context, userID, sessionID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSession", ctx, userID, sessionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeSession indicates an expected call of RevokeSession.
+func (mr *MockAuthUseCaseInterfaceMockRecorder) RevokeSession(ctx, userID, sessionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSession", reflect.TypeOf((*MockAuthUseCaseInterface)(nil).RevokeSession), ctx, userID, sessionID)
+}
+
+// StartExternalLogin mocks base method.
+func (m *MockAuthUseCaseInterface) StartExternalLogin(ctx context.Context, provider string) (*transport.ExternalLoginStart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartExternalLogin", ctx, provider)
+	ret0, _ := ret[0].(*transport.ExternalLoginStart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartExternalLogin indicates an expected call of StartExternalLogin.
+func (mr *MockAuthUseCaseInterfaceMockRecorder) StartExternalLogin(ctx, provider interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartExternalLogin", reflect.TypeOf((*MockAuthUseCaseInterface)(nil).StartExternalLogin), ctx, provider)
+}
+
 // ValidateEmail mocks base method.
 func (m *MockAuthUseCaseInterface) ValidateEmail(ctx context.Context, email string) error {
 	m.ctrl.T.Helper()
@@ -110,6 +532,20 @@ func (mr *MockAuthUseCaseInterfaceMockRecorder) ValidateEmail(ctx, email interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateEmail", reflect.TypeOf((*MockAuthUseCaseInterface)(nil).ValidateEmail), ctx, email)
 }
 
+// VerifyEmail mocks base method.
+func (m *MockAuthUseCaseInterface) VerifyEmail(ctx context.Context, token string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyEmail", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VerifyEmail indicates an expected call of VerifyEmail.
+func (mr *MockAuthUseCaseInterfaceMockRecorder) VerifyEmail(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyEmail", reflect.TypeOf((*MockAuthUseCaseInterface)(nil).VerifyEmail), ctx, token)
+}
+
 // VerifyToken mocks base method.
 func (m *MockAuthUseCaseInterface) VerifyToken(ctx context.Context, tokenString string) (*transport.Claims, error) {
 	m.ctrl.T.Helper()
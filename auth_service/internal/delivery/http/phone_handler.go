@@ -0,0 +1,91 @@
+package http
+
+import (
+	"apple_backend/auth_service/internal/delivery/transport"
+	"apple_backend/auth_service/internal/domain"
+	"apple_backend/pkg/logger"
+	"errors"
+	"log/slog"
+	"net/http"
+)
+
+// phoneError отвечает на типовые ошибки входа по телефону и возвращает
+// false, если ошибка не из их числа.
+func (h *AuthHandler) phoneError(w http.ResponseWriter, r *http.Request, op string, err error) bool {
+	ctx := r.Context()
+
+	var throttled *domain.ThrottledError
+	if errors.As(err, &throttled) {
+		setRetryAfter(w, throttled.RetryAfter)
+		h.rs.Error(ctx, w, http.StatusTooManyRequests, op, domain.ErrTooManyRequests, nil)
+		return true
+	}
+	switch err {
+	case domain.ErrInvalidPhone:
+		h.rs.Error(ctx, w, http.StatusBadRequest, op, err, nil)
+	case domain.ErrPhoneCodeInvalid:
+		h.rs.Error(ctx, w, http.StatusUnauthorized, op, err, nil)
+	case domain.ErrPhoneLoginDisabled:
+		h.rs.Error(ctx, w, http.StatusNotFound, op, err, nil)
+	case domain.ErrTwoFactorRequired, domain.ErrAccountDeleted:
+		h.rs.Error(ctx, w, http.StatusForbidden, op, err, nil)
+	default:
+		return false
+	}
+	return true
+}
+
+func (h *AuthHandler) RequestPhoneCode(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "handler RequestPhoneCode start")
+
+	var req transport.PhoneCodeRequest
+	if !h.decodeJSON(w, r, "RequestPhoneCode", &req) {
+		return
+	}
+
+	res, err := h.uc.RequestPhoneCode(ctx, req.Phone)
+	if err != nil {
+		log.ErrorContext(ctx, "usecase RequestPhoneCode failed", slog.Any("err", err))
+		if !h.phoneError(w, r, "RequestPhoneCode", err) {
+			h.rs.Error(ctx, w, http.StatusInternalServerError, "RequestPhoneCode", domain.ErrInternalServer, err)
+		}
+		return
+	}
+
+	h.rs.Send(ctx, w, http.StatusOK, res)
+	log.InfoContext(ctx, "handler RequestPhoneCode success")
+}
+
+func (h *AuthHandler) LoginByPhone(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "handler LoginByPhone start")
+
+	var req transport.PhoneLoginRequest
+	if !h.decodeJSON(w, r, "LoginByPhone", &req) {
+		return
+	}
+
+	res, err := h.uc.LoginByPhone(ctx, req.Phone, req.Code)
+	if err != nil {
+		log.ErrorContext(ctx, "usecase LoginByPhone failed", slog.Any("err", err))
+		if !h.phoneError(w, r, "LoginByPhone", err) {
+			h.rs.Error(ctx, w, http.StatusInternalServerError, "LoginByPhone", domain.ErrInternalServer, err)
+		}
+		return
+	}
+
+	if res.Challenge != nil {
+		// сессии еще нет: cookie выставим после второго шага
+		h.rs.Send(ctx, w, http.StatusOK, res.Challenge)
+		log.InfoContext(ctx, "handler LoginByPhone second factor required", slog.String("user_id", res.UserID))
+		return
+	}
+
+	h.mergeGuestCart(w, r, res.UserID)
+	h.setSessionCookies(w, res)
+	h.rs.Send(ctx, w, http.StatusOK, res)
+	log.InfoContext(ctx, "handler LoginByPhone success", slog.String("user_id", res.UserID))
+}
@@ -0,0 +1,193 @@
+package http
+
+import (
+	authmw "apple_backend/auth_service/internal/delivery/middlewares"
+	"apple_backend/auth_service/internal/domain"
+	"apple_backend/auth_service/internal/usecase"
+	mocks "apple_backend/auth_service/internal/usecase/mock"
+	"apple_backend/pkg/csrf"
+	"apple_backend/pkg/jwtkeys"
+	"apple_backend/pkg/logger"
+	"apple_backend/pkg/passhash"
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+)
+
+// accountStore — общая БД глазами auth_service: пользователь, его сессии и
+// refresh-токены
+type accountStore struct {
+	user     domain.User
+	sessions map[string]*domain.Session
+	tokens   map[string]*domain.RefreshToken
+}
+
+// deleteAccount делает с аккаунтом то же, что DELETE /profiles/me в
+// profile_service: помечает удаленным и отзывает все сессии
+func (s *accountStore) deleteAccount() {
+	now := time.Now()
+	s.user.DeletedAt = &now
+	for _, session := range s.sessions {
+		session.RevokedAt = &now
+	}
+}
+
+func (s *accountStore) expect(repo *mocks.MockAuthRepository, roles *mocks.MockRoleRepository, sessions *mocks.MockSessionRepository) {
+	getUser := func(context.Context, string) (*domain.User, error) {
+		u := s.user
+		return &u, nil
+	}
+	repo.EXPECT().GetUserByEmail(gomock.Any(), s.user.Email).DoAndReturn(getUser).AnyTimes()
+	repo.EXPECT().GetUserByID(gomock.Any(), s.user.ID).DoAndReturn(getUser).AnyTimes()
+	repo.EXPECT().RestoreAccount(gomock.Any(), s.user.ID).DoAndReturn(func(context.Context, string) error {
+		s.user.DeletedAt = nil
+		return nil
+	})
+	roles.EXPECT().GetUserRoles(gomock.Any(), s.user.ID).Return([]string{"customer"}, nil).AnyTimes()
+
+	sessions.EXPECT().CreateSession(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, session *domain.Session, tokenHash string, expiresAt time.Time) (*domain.Session, error) {
+			session.ID = "s" + strconv.Itoa(len(s.sessions)+1)
+			s.sessions[session.ID] = session
+			s.tokens[tokenHash] = &domain.RefreshToken{ID: "rt-" + session.ID, SessionID: session.ID, UserID: session.UserID, ExpiresAt: expiresAt}
+			return session, nil
+		}).AnyTimes()
+	sessions.EXPECT().GetRefreshToken(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, tokenHash string) (*domain.RefreshToken, error) {
+			rt, ok := s.tokens[tokenHash]
+			if !ok {
+				return nil, domain.ErrSessionNotFound
+			}
+			res := *rt
+			res.SessionRevokedAt = s.sessions[rt.SessionID].RevokedAt
+			return &res, nil
+		}).AnyTimes()
+	sessions.EXPECT().RotateRefreshToken(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, oldTokenID, sessionID, newHash string, expiresAt time.Time) error {
+			for hash, rt := range s.tokens {
+				if rt.ID == oldTokenID {
+					delete(s.tokens, hash)
+				}
+			}
+			s.tokens[newHash] = &domain.RefreshToken{ID: "rt-" + newHash[:8], SessionID: sessionID, UserID: s.user.ID, ExpiresAt: expiresAt}
+			return nil
+		}).AnyTimes()
+}
+
+// browser ходит в сервис как фронтенд: хранит cookie и повторяет
+// CSRF-токен в заголовке
+type browser struct {
+	t      *testing.T
+	client *http.Client
+	base   string
+}
+
+func (b *browser) post(path, body string) int {
+	b.t.Helper()
+	req, err := http.NewRequest(http.MethodPost, b.base+path, bytes.NewBufferString(body))
+	if err != nil {
+		b.t.Fatalf("build request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	u, _ := url.Parse(b.base + path)
+	for _, c := range b.client.Jar.Cookies(u) {
+		if c.Name == csrf.CookieName {
+			req.Header.Set(csrf.HeaderName, c.Value)
+		}
+	}
+	resp, err := b.client.Do(req)
+	if err != nil {
+		b.t.Fatalf("POST %s: %v", path, err)
+	}
+	_ = resp.Body.Close()
+	return resp.StatusCode
+}
+
+func (b *browser) get(path string) int {
+	b.t.Helper()
+	resp, err := b.client.Get(b.base + path)
+	if err != nil {
+		b.t.Fatalf("GET %s: %v", path, err)
+	}
+	_ = resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestRestoreAfterDelete_ThroughMiddlewareChain(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	const (
+		email    = "u@ex.com"
+		password = "Str0ng!Pass"
+	)
+	hash, err := passhash.Default().Hash(password)
+	if err != nil {
+		t.Fatalf("hash password: %v", err)
+	}
+	keys, err := jwtkeys.GenerateKeySet()
+	if err != nil {
+		t.Fatalf("generate keys: %v", err)
+	}
+
+	store := &accountStore{
+		user:     domain.User{ID: "u1", Email: email, PasswordHash: hash},
+		sessions: map[string]*domain.Session{},
+		tokens:   map[string]*domain.RefreshToken{},
+	}
+	repo := mocks.NewMockAuthRepository(ctrl)
+	roles := mocks.NewMockRoleRepository(ctrl)
+	sessions := mocks.NewMockSessionRepository(ctrl)
+	store.expect(repo, roles, sessions)
+	uc := usecase.NewAuthUseCase(repo, sessions, nil, nil, keys, usecase.Config{Roles: roles})
+
+	// та же цепочка, что собирает cmd/app.go
+	csrfProtector := csrf.New("test-secret", csrf.Options{})
+	authMux := http.NewServeMux()
+	authMux.HandleFunc("/csrf", func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNoContent) })
+	NewAuthRouter(authMux, "/auth", uc, csrfProtector, nil)
+	mainMux := http.NewServeMux()
+	mainMux.Handle("/api/v0/", http.StripPrefix("/api/v0", csrfProtector.Middleware(authMux)))
+	srv := httptest.NewServer(authmw.CorsMiddleware(authmw.AccessLog(logger.Global(), nil, mainMux)))
+	defer srv.Close()
+
+	jar, _ := cookiejar.New(nil)
+	b := &browser{t: t, client: &http.Client{Jar: jar}, base: srv.URL + "/api/v0"}
+	credentials := `{"email":"` + email + `","password":"` + password + `"}`
+
+	if code := b.get("/csrf"); code != http.StatusNoContent {
+		t.Fatalf("csrf: expected 204, got %d", code)
+	}
+	if code := b.post("/auth/login", credentials); code != http.StatusOK {
+		t.Fatalf("login: expected 200, got %d", code)
+	}
+
+	store.deleteAccount()
+
+	// сессий больше нет: ни refresh, ни обычный вход не пускают
+	if code := b.post("/auth/refresh", ""); code != http.StatusUnauthorized {
+		t.Fatalf("refresh after delete: expected 401, got %d", code)
+	}
+	if code := b.post("/auth/login", credentials); code != http.StatusForbidden {
+		t.Fatalf("login after delete: expected 403, got %d", code)
+	}
+
+	if code := b.post("/auth/restore", credentials); code != http.StatusOK {
+		t.Fatalf("restore: expected 200, got %d", code)
+	}
+	if store.user.Deleted() {
+		t.Fatal("account is still deleted after restore")
+	}
+	// новая сессия рабочая: refresh-cookie и CSRF-токен выданы заново
+	if code := b.post("/auth/refresh", ""); code != http.StatusOK {
+		t.Fatalf("refresh after restore: expected 200, got %d", code)
+	}
+}
@@ -0,0 +1,128 @@
+package http
+
+import (
+	"apple_backend/auth_service/internal/delivery/transport"
+	"apple_backend/auth_service/internal/domain"
+	"apple_backend/pkg/logger"
+	"apple_backend/pkg/rbac"
+	"log/slog"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// authorize проверяет токен и наличие разрешения у его ролей. При ошибке
+// ответ уже отправлен.
+func (h *AuthHandler) authorize(w http.ResponseWriter, r *http.Request, op, perm string) (*transport.Claims, bool) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+
+	claims, err := h.authenticate(r)
+	if err != nil {
+		log.WarnContext(ctx, "handler "+op+" unauthorized", slog.Any("err", err))
+		h.rs.Error(ctx, w, http.StatusUnauthorized, op, domain.ErrUnauthorized, nil)
+		return nil, false
+	}
+	if !rbac.HasPermission(claims.Roles, perm) {
+		log.WarnContext(ctx, "handler "+op+" forbidden", slog.String("user_id", claims.UserID), slog.String("permission", perm))
+		h.rs.Error(ctx, w, http.StatusForbidden, op, domain.ErrForbidden, nil)
+		return nil, false
+	}
+	return claims, true
+}
+
+func (h *AuthHandler) roleError(w http.ResponseWriter, r *http.Request, op string, err error) {
+	ctx := r.Context()
+	switch err {
+	case domain.ErrUserNotFound, domain.ErrRoleNotAssigned:
+		h.rs.Error(ctx, w, http.StatusNotFound, op, err, nil)
+	case domain.ErrUnknownRole:
+		h.rs.Error(ctx, w, http.StatusBadRequest, op, err, nil)
+	case domain.ErrRevokeOwnAdmin:
+		h.rs.Error(ctx, w, http.StatusConflict, op, err, nil)
+	default:
+		h.rs.Error(ctx, w, http.StatusInternalServerError, op, domain.ErrInternalServer, err)
+	}
+}
+
+// UserRoles — GET список ролей пользователя, POST выдача роли
+func (h *AuthHandler) UserRoles(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "handler UserRoles start")
+
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		log.WarnContext(ctx, "handler UserRoles wrong method")
+		h.rs.Error(ctx, w, http.StatusMethodNotAllowed, "UserRoles", domain.ErrHTTPMethod, nil)
+		return
+	}
+
+	userID := r.PathValue("id")
+	if _, err := uuid.Parse(userID); err != nil {
+		log.WarnContext(ctx, "handler UserRoles invalid id", slog.String("user_id", userID))
+		h.rs.Error(ctx, w, http.StatusBadRequest, "UserRoles", domain.ErrRequestParams, nil)
+		return
+	}
+
+	if r.Method == http.MethodPost {
+		var req transport.GrantRoleRequest
+		if !h.decodeJSON(w, r, "GrantRole", &req) {
+			return
+		}
+		claims, ok := h.authorize(w, r, "GrantRole", rbac.PermRolesManage)
+		if !ok {
+			return
+		}
+		if err := h.uc.GrantRole(ctx, claims.UserID, userID, req.Role); err != nil {
+			log.ErrorContext(ctx, "usecase GrantRole failed", slog.Any("err", err))
+			h.roleError(w, r, "GrantRole", err)
+			return
+		}
+	} else if _, ok := h.authorize(w, r, "UserRoles", rbac.PermRolesManage); !ok {
+		return
+	}
+
+	roles, err := h.uc.ListUserRoles(ctx, userID)
+	if err != nil {
+		log.ErrorContext(ctx, "usecase ListUserRoles failed", slog.Any("err", err))
+		h.roleError(w, r, "UserRoles", err)
+		return
+	}
+
+	h.rs.Send(ctx, w, http.StatusOK, transport.UserRoles{UserID: userID, Roles: roles})
+	log.InfoContext(ctx, "handler UserRoles success", slog.String("user_id", userID))
+}
+
+// RevokeUserRole — DELETE снятие роли
+func (h *AuthHandler) RevokeUserRole(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "handler RevokeUserRole start")
+
+	if r.Method != http.MethodDelete {
+		log.WarnContext(ctx, "handler RevokeUserRole wrong method")
+		h.rs.Error(ctx, w, http.StatusMethodNotAllowed, "RevokeUserRole", domain.ErrHTTPMethod, nil)
+		return
+	}
+
+	claims, ok := h.authorize(w, r, "RevokeUserRole", rbac.PermRolesManage)
+	if !ok {
+		return
+	}
+
+	userID := r.PathValue("id")
+	if _, err := uuid.Parse(userID); err != nil {
+		log.WarnContext(ctx, "handler RevokeUserRole invalid id", slog.String("user_id", userID))
+		h.rs.Error(ctx, w, http.StatusBadRequest, "RevokeUserRole", domain.ErrRequestParams, nil)
+		return
+	}
+
+	if err := h.uc.RevokeRole(ctx, claims.UserID, userID, r.PathValue("role")); err != nil {
+		log.ErrorContext(ctx, "usecase RevokeRole failed", slog.Any("err", err))
+		h.roleError(w, r, "RevokeUserRole", err)
+		return
+	}
+
+	log.InfoContext(ctx, "handler RevokeUserRole success", slog.String("user_id", userID))
+	w.WriteHeader(http.StatusNoContent)
+}
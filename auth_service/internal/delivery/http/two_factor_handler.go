@@ -0,0 +1,164 @@
+package http
+
+import (
+	"apple_backend/auth_service/internal/delivery/transport"
+	"apple_backend/auth_service/internal/domain"
+	"apple_backend/pkg/logger"
+	"errors"
+	"log/slog"
+	"net/http"
+)
+
+// twoFactorError отвечает на типовые ошибки второго фактора и возвращает
+// false, если ошибка не из их числа.
+func (h *AuthHandler) twoFactorError(w http.ResponseWriter, r *http.Request, op string, err error) bool {
+	ctx := r.Context()
+
+	var throttled *domain.ThrottledError
+	if errors.As(err, &throttled) {
+		setRetryAfter(w, throttled.RetryAfter)
+		h.rs.Error(ctx, w, http.StatusTooManyRequests, op, domain.ErrTooManyRequests, nil)
+		return true
+	}
+	switch err {
+	case domain.ErrChallengeInvalid, domain.ErrTwoFactorCodeInvalid:
+		h.rs.Error(ctx, w, http.StatusUnauthorized, op, err, nil)
+	case domain.ErrTwoFactorNotEnrolled, domain.ErrTwoFactorAlreadyEnabled:
+		h.rs.Error(ctx, w, http.StatusConflict, op, err, nil)
+	case domain.ErrTwoFactorRequired, domain.ErrAccountDeleted:
+		h.rs.Error(ctx, w, http.StatusForbidden, op, err, nil)
+	default:
+		return false
+	}
+	return true
+}
+
+// twoFactorUser определяет пользователя для настройки 2FA: по challenge-токену
+// (обязательная настройка при входе) или по текущей сессии.
+func (h *AuthHandler) twoFactorUser(r *http.Request, challengeToken string) (userID, sessionID string, err error) {
+	if challengeToken != "" {
+		userID, err = h.uc.ChallengeUser(r.Context(), challengeToken)
+		return userID, "", err
+	}
+	claims, err := h.authenticate(r)
+	if err != nil {
+		return "", "", domain.ErrUnauthorized
+	}
+	return claims.UserID, claims.SessionID, nil
+}
+
+func (h *AuthHandler) LoginTwoFactor(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "handler LoginTwoFactor start")
+
+	var req transport.LoginTwoFactorRequest
+	if !h.decodeJSON(w, r, "LoginTwoFactor", &req) {
+		return
+	}
+
+	res, err := h.uc.LoginTwoFactor(ctx, req.ChallengeToken, req.Code)
+	if err != nil {
+		log.ErrorContext(ctx, "usecase LoginTwoFactor failed", slog.Any("err", err))
+		if !h.twoFactorError(w, r, "LoginTwoFactor", err) {
+			h.rs.Error(ctx, w, http.StatusInternalServerError, "LoginTwoFactor", domain.ErrInternalServer, err)
+		}
+		return
+	}
+
+	h.mergeGuestCart(w, r, res.UserID)
+	h.setSessionCookies(w, res)
+	h.rs.Send(ctx, w, http.StatusOK, res)
+	log.InfoContext(ctx, "handler LoginTwoFactor success", slog.String("user_id", res.UserID))
+}
+
+func (h *AuthHandler) EnrollTwoFactor(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "handler EnrollTwoFactor start")
+
+	var req transport.TwoFactorEnrollRequest
+	if !h.decodeJSON(w, r, "EnrollTwoFactor", &req) {
+		return
+	}
+	userID, _, err := h.twoFactorUser(r, req.ChallengeToken)
+	if err != nil {
+		log.WarnContext(ctx, "handler EnrollTwoFactor unauthorized", slog.Any("err", err))
+		h.rs.Error(ctx, w, http.StatusUnauthorized, "EnrollTwoFactor", domain.ErrUnauthorized, nil)
+		return
+	}
+
+	res, err := h.uc.EnrollTwoFactor(ctx, userID)
+	if err != nil {
+		log.ErrorContext(ctx, "usecase EnrollTwoFactor failed", slog.Any("err", err))
+		if !h.twoFactorError(w, r, "EnrollTwoFactor", err) {
+			h.rs.Error(ctx, w, http.StatusInternalServerError, "EnrollTwoFactor", domain.ErrInternalServer, err)
+		}
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	h.rs.Send(ctx, w, http.StatusOK, res)
+	log.InfoContext(ctx, "handler EnrollTwoFactor success", slog.String("user_id", userID))
+}
+
+func (h *AuthHandler) ConfirmTwoFactor(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "handler ConfirmTwoFactor start")
+
+	var req transport.TwoFactorConfirmRequest
+	if !h.decodeJSON(w, r, "ConfirmTwoFactor", &req) {
+		return
+	}
+	userID, sessionID, err := h.twoFactorUser(r, req.ChallengeToken)
+	if err != nil {
+		log.WarnContext(ctx, "handler ConfirmTwoFactor unauthorized", slog.Any("err", err))
+		h.rs.Error(ctx, w, http.StatusUnauthorized, "ConfirmTwoFactor", domain.ErrUnauthorized, nil)
+		return
+	}
+
+	res, err := h.uc.ConfirmTwoFactor(ctx, userID, sessionID, req.Code)
+	if err != nil {
+		log.ErrorContext(ctx, "usecase ConfirmTwoFactor failed", slog.Any("err", err))
+		if !h.twoFactorError(w, r, "ConfirmTwoFactor", err) {
+			h.rs.Error(ctx, w, http.StatusInternalServerError, "ConfirmTwoFactor", domain.ErrInternalServer, err)
+		}
+		return
+	}
+
+	if res.Auth != nil {
+		h.setSessionCookies(w, res.Auth)
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	h.rs.Send(ctx, w, http.StatusOK, res)
+	log.InfoContext(ctx, "handler ConfirmTwoFactor success", slog.String("user_id", userID))
+}
+
+func (h *AuthHandler) DisableTwoFactor(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "handler DisableTwoFactor start")
+
+	var req transport.TwoFactorDisableRequest
+	if !h.decodeJSON(w, r, "DisableTwoFactor", &req) {
+		return
+	}
+	claims, err := h.authenticate(r)
+	if err != nil {
+		log.WarnContext(ctx, "handler DisableTwoFactor unauthorized", slog.Any("err", err))
+		h.rs.Error(ctx, w, http.StatusUnauthorized, "DisableTwoFactor", domain.ErrUnauthorized, nil)
+		return
+	}
+
+	if err := h.uc.DisableTwoFactor(ctx, claims.UserID, req.Code); err != nil {
+		log.ErrorContext(ctx, "usecase DisableTwoFactor failed", slog.Any("err", err))
+		if !h.twoFactorError(w, r, "DisableTwoFactor", err) {
+			h.rs.Error(ctx, w, http.StatusInternalServerError, "DisableTwoFactor", domain.ErrInternalServer, err)
+		}
+		return
+	}
+
+	h.rs.Send(ctx, w, http.StatusOK, map[string]string{"message": "two-factor authentication disabled"})
+	log.InfoContext(ctx, "handler DisableTwoFactor success", slog.String("user_id", claims.UserID))
+}
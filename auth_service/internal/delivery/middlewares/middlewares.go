@@ -3,10 +3,13 @@ package middlewares
 import (
 	"apple_backend/pkg/logger"
 	"apple_backend/pkg/trace"
+	"fmt"
 	"log/slog"
 	"net"
 	"net/http"
+	"net/netip"
 	"os"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -33,7 +36,10 @@ func (w *statusWriter) Write(b []byte) (int, error) {
 	return n, err
 }
 
-func AccessLog(baseLogger logger.Logger, next http.Handler) http.Handler {
+// AccessLog пишет журнал запросов и кладет в контекст request id, IP и
+// User-Agent клиента. IP за обратным прокси берется из заголовков только
+// для proxies.
+func AccessLog(baseLogger logger.Logger, proxies *TrustedProxies, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		reqID := r.Header.Get("X-Request-Id")
 		if reqID == "" {
@@ -45,6 +51,9 @@ func AccessLog(baseLogger logger.Logger, next http.Handler) http.Handler {
 		ctx = logger.ContextWithRequestID(ctx, reqID)
 		w.Header().Set("X-Request-Id", reqID)
 
+		// IP и User-Agent нужны для привязки сессий к устройствам
+		ctx = trace.SetClientInfo(ctx, proxies.ClientIP(r), r.UserAgent())
+
 		// create per-request logger and put into context
 		reqLogger := baseLogger.With(
 			slog.String("request_id", reqID),
@@ -74,66 +83,6 @@ func AccessLog(baseLogger logger.Logger, next http.Handler) http.Handler {
 	})
 }
 
-func CSRFMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions {
-			next.ServeHTTP(w, r)
-			return
-		}
-
-		headerToken := r.Header.Get("X-CSRF-Token")
-		if headerToken == "" {
-			http.Error(w, "CSRF token required", http.StatusForbidden)
-			return
-		}
-
-		cookieToken, err := r.Cookie("csrf_token")
-		if err != nil || cookieToken.Value == "" {
-			http.Error(w, "CSRF cookie missing", http.StatusForbidden)
-			return
-		}
-
-		if headerToken != cookieToken.Value {
-			http.Error(w, "CSRF token mismatch", http.StatusForbidden)
-			return
-		}
-
-		next.ServeHTTP(w, r)
-	})
-}
-
-func CSRFTokenMiddleware(next http.Handler) http.Handler {
-	secure := strings.EqualFold(os.Getenv("COOKIE_SECURE"), "true")
-	sameSite := http.SameSiteLaxMode
-	switch strings.ToLower(os.Getenv("COOKIE_SAMESITE")) {
-	case "strict":
-		sameSite = http.SameSiteStrictMode
-	case "none":
-		sameSite = http.SameSiteNoneMode
-		if !secure {
-			secure = true
-		}
-	}
-
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// csrf_token — для Double Submit (HttpOnly=false)
-		if _, err := r.Cookie("csrf_token"); err != nil {
-			csrfToken := uuid.NewString()
-			http.SetCookie(w, &http.Cookie{
-				Name:     "csrf_token",
-				Value:    csrfToken,
-				Path:     "/",
-				HttpOnly: false,
-				Secure:   secure,
-				SameSite: sameSite,
-				MaxAge:   86400,
-			})
-		}
-
-		next.ServeHTTP(w, r)
-	})
-}
-
 func CorsMiddleware(next http.Handler) http.Handler {
 	origins := os.Getenv("ALLOWED_ORIGINS")
 	if origins == "" {
@@ -149,6 +98,7 @@ func CorsMiddleware(next http.Handler) http.Handler {
 			w.Header().Set("Vary", "Origin")
 			w.Header().Set("Access-Control-Allow-Credentials", "true")
 			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-CSRF-Token")
+			w.Header().Set("Access-Control-Expose-Headers", "X-CSRF-Token")
 		}
 
 		if r.Method == http.MethodOptions {
@@ -161,6 +111,89 @@ func CorsMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+func remoteIP(r *http.Request) string {
+	ip, _, _ := net.SplitHostPort(r.RemoteAddr)
+	if ip == "" {
+		ip = r.RemoteAddr
+	}
+	return ip
+}
+
+// TrustedProxies — обратные прокси, которым доверяются X-Forwarded-For и
+// X-Real-IP. От остальных адресов заголовки игнорируются: иначе клиент
+// подставил бы любой IP и обошел лимиты. nil — прокси нет.
+type TrustedProxies struct {
+	prefixes []netip.Prefix
+}
+
+// ParseTrustedProxies разбирает список IP и подсетей (10.0.0.1, 10.0.0.0/8)
+func ParseTrustedProxies(list []string) (*TrustedProxies, error) {
+	p := &TrustedProxies{}
+	for _, v := range list {
+		if prefix, err := netip.ParsePrefix(v); err == nil {
+			p.prefixes = append(p.prefixes, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(v)
+		if err != nil {
+			return nil, fmt.Errorf("trusted proxy %q: %w", v, err)
+		}
+		addr = addr.Unmap()
+		p.prefixes = append(p.prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return p, nil
+}
+
+func (p *TrustedProxies) trusted(ip string) bool {
+	if p == nil {
+		return false
+	}
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range p.prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientIP возвращает адрес клиента. Если запрос пришел от доверенного
+// прокси, X-Forwarded-For читается справа налево до первого адреса, который
+// не является прокси; без него используется X-Real-IP.
+func (p *TrustedProxies) ClientIP(r *http.Request) string {
+	ip := remoteIP(r)
+	if !p.trusted(ip) {
+		return ip
+	}
+
+	if xff := r.Header.Values("X-Forwarded-For"); len(xff) > 0 {
+		hops := strings.Split(strings.Join(xff, ","), ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop := strings.TrimSpace(hops[i])
+			if _, err := netip.ParseAddr(hop); err != nil {
+				// испорченную цепочку дальше не разбираем
+				return ip
+			}
+			ip = hop
+			if !p.trusted(hop) {
+				return ip
+			}
+		}
+		return ip
+	}
+
+	if realIP := strings.TrimSpace(r.Header.Get("X-Real-IP")); realIP != "" {
+		if _, err := netip.ParseAddr(realIP); err == nil {
+			return realIP
+		}
+	}
+	return ip
+}
+
 func parseAllowedOrigins(v string) map[string]bool {
 	m := map[string]bool{}
 	for _, s := range strings.Split(v, ",") {
@@ -171,6 +204,11 @@ func parseAllowedOrigins(v string) map[string]bool {
 	return m
 }
 
+// RateLimit — грубый лимит запросов с одного IP в памяти процесса. Он
+// защищает от всплесков нагрузки; лимиты по email и блокировки аккаунтов
+// хранятся в БД и применяются в usecase. IP берется из контекста, куда его
+// кладет AccessLog. Один экземпляр — один общий счетчик на все маршруты,
+// которые он оборачивает.
 func RateLimit(max int, window time.Duration) func(http.Handler) http.Handler {
 	type bucket struct {
 		tokens int
@@ -178,23 +216,35 @@ func RateLimit(max int, window time.Duration) func(http.Handler) http.Handler {
 	}
 	var mu sync.Mutex
 	store := make(map[string]*bucket)
+	lastSweep := time.Now()
 
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			ip, _, _ := net.SplitHostPort(r.RemoteAddr)
+			ip := trace.GetClientIP(r.Context())
 			if ip == "" {
-				ip = r.RemoteAddr
+				ip = remoteIP(r)
 			}
 
 			now := time.Now()
 			mu.Lock()
+			// истекшие окна удаляем, чтобы карта не росла бесконечно
+			if now.Sub(lastSweep) > window {
+				for k, b := range store {
+					if now.After(b.reset) {
+						delete(store, k)
+					}
+				}
+				lastSweep = now
+			}
 			b, ok := store[ip]
 			if !ok || now.After(b.reset) {
 				b = &bucket{tokens: max, reset: now.Add(window)}
 				store[ip] = b
 			}
-			if b.tokens <= 0 && false {
+			if b.tokens <= 0 {
+				retryAfter := int(b.reset.Sub(now).Seconds()) + 1
 				mu.Unlock()
+				w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
 				http.Error(w, "Too Many Requests", http.StatusTooManyRequests)
 				return
 			}
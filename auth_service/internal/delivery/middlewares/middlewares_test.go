@@ -0,0 +1,109 @@
+package middlewares
+
+import (
+	"apple_backend/pkg/logger"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrustedProxies_ClientIP(t *testing.T) {
+	proxies, err := ParseTrustedProxies([]string{"10.0.0.0/8", "192.168.1.5"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		proxies    *TrustedProxies
+		remoteAddr string
+		xff        string
+		realIP     string
+		expected   string
+	}{
+		{
+			name:       "без прокси",
+			proxies:    nil,
+			remoteAddr: "10.0.0.1:5000",
+			xff:        "1.2.3.4",
+			expected:   "10.0.0.1",
+		},
+		{
+			name:       "заголовок от недоверенного адреса",
+			proxies:    proxies,
+			remoteAddr: "8.8.8.8:5000",
+			xff:        "1.2.3.4",
+			expected:   "8.8.8.8",
+		},
+		{
+			name:       "X-Forwarded-For от прокси",
+			proxies:    proxies,
+			remoteAddr: "10.0.0.1:5000",
+			xff:        "1.2.3.4",
+			expected:   "1.2.3.4",
+		},
+		{
+			name:       "подделанное начало цепочки",
+			proxies:    proxies,
+			remoteAddr: "10.0.0.1:5000",
+			xff:        "6.6.6.6, 1.2.3.4, 192.168.1.5",
+			expected:   "1.2.3.4",
+		},
+		{
+			name:       "X-Real-IP от прокси",
+			proxies:    proxies,
+			remoteAddr: "192.168.1.5:5000",
+			realIP:     "1.2.3.4",
+			expected:   "1.2.3.4",
+		},
+		{
+			name:       "испорченный X-Forwarded-For",
+			proxies:    proxies,
+			remoteAddr: "10.0.0.1:5000",
+			xff:        "not-an-ip",
+			expected:   "10.0.0.1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.xff != "" {
+				req.Header.Set("X-Forwarded-For", tt.xff)
+			}
+			if tt.realIP != "" {
+				req.Header.Set("X-Real-IP", tt.realIP)
+			}
+			require.Equal(t, tt.expected, tt.proxies.ClientIP(req))
+		})
+	}
+}
+
+func TestRateLimit_ClientsBehindProxy(t *testing.T) {
+	proxies, err := ParseTrustedProxies([]string{"10.0.0.1"})
+	require.NoError(t, err)
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	limit := RateLimit(1, time.Minute)
+	mux := http.NewServeMux()
+	mux.Handle("/login", limit(ok))
+	mux.Handle("/signup", limit(ok))
+	handler := AccessLog(logger.Global(), proxies, mux)
+
+	call := func(path, client string) int {
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		req.RemoteAddr = "10.0.0.1:5000"
+		req.Header.Set("X-Forwarded-For", client)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	require.Equal(t, http.StatusOK, call("/login", "1.1.1.1"))
+	// лимит общий для всех маршрутов одного клиента
+	require.Equal(t, http.StatusTooManyRequests, call("/signup", "1.1.1.1"))
+	// другой клиент за тем же прокси не блокируется
+	require.Equal(t, http.StatusOK, call("/login", "2.2.2.2"))
+}
@@ -0,0 +1,53 @@
+package transport
+
+import (
+	"apple_backend/auth_service/internal/domain"
+	"time"
+)
+
+// CreateAPIKeyRequest — ExpiresInDays 0 означает срок по умолчанию (90 дней)
+type CreateAPIKeyRequest struct {
+	Name          string   `json:"name"`
+	Scopes        []string `json:"scopes"`
+	ExpiresInDays int      `json:"expires_in_days"`
+} // @name CreateAPIKeyRequest
+
+type APIKeyInfo struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+} // @name APIKeyInfo
+
+// APIKeyCreated — Key показывается только в ответе на создание
+type APIKeyCreated struct {
+	APIKeyInfo
+	Key string `json:"key"`
+} // @name APIKeyCreated
+
+type APIKeys struct {
+	Keys []*APIKeyInfo `json:"keys"`
+} // @name APIKeys
+
+func ToAPIKeyInfo(k *domain.APIKey) *APIKeyInfo {
+	return &APIKeyInfo{
+		ID:         k.ID,
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		Scopes:     k.Scopes,
+		ExpiresAt:  k.ExpiresAt,
+		LastUsedAt: k.LastUsedAt,
+		CreatedAt:  k.CreatedAt,
+	}
+}
+
+func ToAPIKeysResponse(keys []*domain.APIKey) *APIKeys {
+	list := make([]*APIKeyInfo, 0, len(keys))
+	for _, k := range keys {
+		list = append(list, ToAPIKeyInfo(k))
+	}
+	return &APIKeys{Keys: list}
+}
@@ -0,0 +1,47 @@
+package transport
+
+import (
+	"apple_backend/auth_service/internal/domain"
+	"time"
+)
+
+type AuthEventInfo struct {
+	ID         string    `json:"id"`
+	UserID     string    `json:"user_id,omitempty"`
+	Type       string    `json:"type"`
+	Method     string    `json:"method,omitempty"`
+	Identifier string    `json:"identifier,omitempty"`
+	IP         string    `json:"ip,omitempty"`
+	UserAgent  string    `json:"user_agent,omitempty"`
+	RequestID  string    `json:"request_id,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+} // @name AuthEventInfo
+
+// AuthEvents — страница журнала. LastID передается в следующий запрос,
+// пустой — страниц больше нет.
+type AuthEvents struct {
+	Events []*AuthEventInfo `json:"events"`
+	LastID string           `json:"last_id,omitempty"`
+} // @name AuthEvents
+
+func ToAuthEventsResponse(events []*domain.AuthEvent, limit int) *AuthEvents {
+	list := make([]*AuthEventInfo, 0, len(events))
+	for _, e := range events {
+		list = append(list, &AuthEventInfo{
+			ID:         e.ID,
+			UserID:     e.UserID,
+			Type:       e.Type,
+			Method:     e.Method,
+			Identifier: e.Identifier,
+			IP:         e.IP,
+			UserAgent:  e.UserAgent,
+			RequestID:  e.RequestID,
+			CreatedAt:  e.CreatedAt,
+		})
+	}
+	res := &AuthEvents{Events: list}
+	if len(list) > 0 && len(list) == limit {
+		res.LastID = list[len(list)-1].ID
+	}
+	return res
+}
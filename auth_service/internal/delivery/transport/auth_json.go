@@ -7,8 +7,12 @@ import (
 )
 
 type Claims struct {
-	UserID string `json:"user_id"`
-	Email  string `json:"email"`
+	UserID    string   `json:"user_id"`
+	Email     string   `json:"email"`
+	SessionID string   `json:"sid"`
+	Roles     []string `json:"roles"`
+	// MFA — в сессии подтвержден второй фактор
+	MFA bool `json:"mfa,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -23,8 +27,59 @@ type LoginRequest struct {
 }
 
 type AuthResult struct {
-	UserID  string    `json:"user_id"`
-	Email   string    `json:"email"`
-	Token   string    `json:"token"`
-	Expires time.Time `json:"expires"`
+	UserID        string    `json:"user_id"`
+	Email         string    `json:"email"`
+	EmailVerified bool      `json:"email_verified"`
+	Roles         []string  `json:"roles"`
+	Token         string    `json:"token"`
+	Expires       time.Time `json:"expires"`
+
+	// refresh-токен отдается только в HttpOnly cookie
+	RefreshToken   string    `json:"-"`
+	RefreshExpires time.Time `json:"-"`
+	// SessionID нужен для привязки CSRF-токена к сессии
+	SessionID string `json:"-"`
+
+	// Challenge заполняется вместо токенов, если для входа нужен второй фактор
+	Challenge *TwoFactorChallenge `json:"-"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+// WeakPasswordResponse — ответ 400, когда пароль не прошел проверку.
+// Score — оценка от 0 до 4, Reason — код причины (breached,
+// keyboard_sequence, contains_email и т. д.).
+type WeakPasswordResponse struct {
+	Error  string `json:"error"`
+	Score  int    `json:"score"`
+	Reason string `json:"reason"`
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token"`
+}
+
+// Code — код 2FA для аккаунтов без пароля; без него такие аккаунты
+// подтверждают личность только свежим входом
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password"`
+	Code            string `json:"code,omitempty"`
+	NewPassword     string `json:"new_password"`
+}
+
+type ChangeEmailRequest struct {
+	NewEmail string `json:"new_email"`
+	Password string `json:"password"`
+	Code     string `json:"code,omitempty"`
+}
+
+type ConfirmEmailChangeRequest struct {
+	Token string `json:"token"`
 }
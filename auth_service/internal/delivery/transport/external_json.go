@@ -0,0 +1,22 @@
+package transport
+
+import "time"
+
+type ExternalProvidersResponse struct {
+	Providers []string `json:"providers"`
+}
+
+// ExternalLoginStart — адрес страницы входа провайдера и подписанное
+// состояние, которое хранится в HttpOnly cookie до возврата с провайдера
+type ExternalLoginStart struct {
+	AuthURL    string
+	StateToken string
+	ExpiresAt  time.Time
+}
+
+// ExternalCallbackRequest — параметры, с которыми провайдер вернул
+// пользователя на фронтенд
+type ExternalCallbackRequest struct {
+	Code  string `json:"code"`
+	State string `json:"state"`
+}
@@ -0,0 +1,15 @@
+package transport
+
+// Introspection — ответ POST /auth/introspect в духе RFC 7662
+type Introspection struct {
+	Active    bool     `json:"active"`
+	Subject   string   `json:"sub,omitempty"`
+	UserID    string   `json:"user_id,omitempty"`
+	Email     string   `json:"email,omitempty"`
+	SessionID string   `json:"sid,omitempty"`
+	Roles     []string `json:"roles,omitempty"`
+	MFA       bool     `json:"mfa,omitempty"`
+	TokenType string   `json:"token_type,omitempty"`
+	ExpiresAt int64    `json:"exp,omitempty"`
+	IssuedAt  int64    `json:"iat,omitempty"`
+}
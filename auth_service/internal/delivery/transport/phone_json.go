@@ -0,0 +1,20 @@
+package transport
+
+import "time"
+
+type PhoneCodeRequest struct {
+	Phone string `json:"phone"`
+}
+
+// PhoneCodeSent — код отправлен; Phone — номер в том виде, в котором его
+// нужно передать в /otp/verify
+type PhoneCodeSent struct {
+	Phone       string    `json:"phone"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	ResendAfter time.Time `json:"resend_after"`
+}
+
+type PhoneLoginRequest struct {
+	Phone string `json:"phone"`
+	Code  string `json:"code"`
+}
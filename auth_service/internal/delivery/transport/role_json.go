@@ -0,0 +1,10 @@
+package transport
+
+type GrantRoleRequest struct {
+	Role string `json:"role"`
+}
+
+type UserRoles struct {
+	UserID string   `json:"user_id"`
+	Roles  []string `json:"roles"`
+}
@@ -0,0 +1,34 @@
+package transport
+
+import (
+	"apple_backend/auth_service/internal/domain"
+	"time"
+)
+
+type SessionInfo struct {
+	ID         string    `json:"id"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	Current    bool      `json:"current"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+} // @name SessionInfo
+
+type Sessions struct {
+	Sessions []*SessionInfo `json:"sessions"`
+} // @name Sessions
+
+func ToSessionsResponse(sessions []*domain.Session, currentID string) *Sessions {
+	list := make([]*SessionInfo, 0, len(sessions))
+	for _, s := range sessions {
+		list = append(list, &SessionInfo{
+			ID:         s.ID,
+			UserAgent:  s.UserAgent,
+			IP:         s.IP,
+			Current:    s.ID == currentID,
+			CreatedAt:  s.CreatedAt,
+			LastSeenAt: s.LastSeenAt,
+		})
+	}
+	return &Sessions{Sessions: list}
+}
@@ -0,0 +1,43 @@
+package transport
+
+import "time"
+
+// TwoFactorChallenge — ответ на логин, когда требуется второй фактор.
+// SetupRequired означает, что 2FA для роли обязательна, но еще не настроена:
+// challenge-токен тогда принимают только /2fa/enroll и /2fa/confirm.
+type TwoFactorChallenge struct {
+	TwoFactorRequired bool      `json:"two_factor_required"`
+	SetupRequired     bool      `json:"setup_required"`
+	ChallengeToken    string    `json:"challenge_token"`
+	ExpiresAt         time.Time `json:"expires_at"`
+}
+
+type LoginTwoFactorRequest struct {
+	ChallengeToken string `json:"challenge_token"`
+	Code           string `json:"code"`
+}
+
+type TwoFactorEnrollRequest struct {
+	ChallengeToken string `json:"challenge_token,omitempty"`
+}
+
+type TwoFactorEnrollment struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+type TwoFactorConfirmRequest struct {
+	ChallengeToken string `json:"challenge_token,omitempty"`
+	Code           string `json:"code"`
+}
+
+type TwoFactorConfirmResult struct {
+	// коды восстановления показываются один раз, в БД хранятся только хэши
+	RecoveryCodes []string `json:"recovery_codes"`
+	// Auth заполняется, если настройка шла по challenge-токену при входе
+	Auth *AuthResult `json:"auth,omitempty"`
+}
+
+type TwoFactorDisableRequest struct {
+	Code string `json:"code"`
+}
@@ -0,0 +1,17 @@
+package domain
+
+import "time"
+
+// APIKey — персональный ключ для интеграций. Секрет ключа не хранится,
+// Prefix — его открытая часть, по которой ключ можно узнать в списке.
+type APIKey struct {
+	ID         string
+	UserID     string
+	Name       string
+	Prefix     string
+	Scopes     []string
+	ExpiresAt  time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+}
@@ -5,9 +5,26 @@ import (
 )
 
 type User struct {
-	ID           string
-	Email        string
-	PasswordHash string
-	CreatedAt    time.Time
-	UpdatedAt    time.Time
+	ID              string
+	Email           string
+	PasswordHash    string
+	EmailVerifiedAt *time.Time
+	// TwoFactorEnabledAt — когда был подтвержден TOTP; nil, если 2FA выключена
+	TwoFactorEnabledAt *time.Time
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+	// DeletedAt — владелец удалил аккаунт; войти можно только восстановив его
+	DeletedAt *time.Time
+}
+
+func (u *User) EmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+func (u *User) TwoFactorEnabled() bool {
+	return u.TwoFactorEnabledAt != nil
+}
+
+func (u *User) Deleted() bool {
+	return u.DeletedAt != nil
 }
@@ -0,0 +1,60 @@
+package domain
+
+import "time"
+
+// Типы событий журнала авторизации
+const (
+	EventSignup            = "signup"
+	EventLoginSuccess      = "login_success"
+	EventLoginFailed       = "login_failed"
+	EventRefresh           = "refresh"
+	EventTokenReused       = "token_reused"
+	EventLogout            = "logout"
+	EventSessionRevoked    = "session_revoked"
+	EventPasswordChange    = "password_change"
+	EventEmailChangeSent   = "email_change_requested"
+	EventEmailChange       = "email_change"
+	EventPasswordResetSent = "password_reset_requested"
+	EventPasswordReset     = "password_reset"
+	EventTwoFactorEnabled  = "2fa_enabled"
+	EventTwoFactorDisabled = "2fa_disabled"
+	EventLockout           = "lockout"
+	EventAPIKeyCreated     = "api_key_created"
+	EventAPIKeyRevoked     = "api_key_revoked"
+	EventAccountRestored   = "account_restored"
+)
+
+// Способы входа в событиях журнала
+const (
+	MethodPassword = "password"
+	MethodTOTP     = "totp"
+	MethodPhone    = "phone"
+	// MethodExternalPrefix дополняется именем провайдера: oidc:vk
+	MethodExternalPrefix = "oidc:"
+)
+
+// AuthEvent — запись журнала авторизации. UserID пустой, если аккаунт
+// неизвестен (например, вход с несуществующим email).
+type AuthEvent struct {
+	ID         string
+	UserID     string
+	Type       string
+	Method     string
+	Identifier string
+	IP         string
+	UserAgent  string
+	RequestID  string
+	CreatedAt  time.Time
+}
+
+// AuthEventFilter — выборка журнала. Пустые поля не фильтруют. Страницы
+// листаются от новых к старым: LastID — последнее событие предыдущей страницы.
+type AuthEventFilter struct {
+	UserID string
+	Type   string
+	IP     string
+	From   *time.Time
+	To     *time.Time
+	LastID string
+	Limit  int
+}
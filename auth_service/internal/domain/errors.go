@@ -3,14 +3,53 @@ package domain
 import "errors"
 
 var (
-	ErrUserAlreadyExists = errors.New("пользователь с таким email уже зарегистрирован")
-	ErrInvalidEmail      = errors.New("введен некорректный адрес электронной почты")
-	ErrWeakPassword      = errors.New("пароль не соответствует требованиям безопасности")
-	ErrUserNotFound      = errors.New("учетная запись не найдена")
-	ErrInvalidPassword   = errors.New("неверно указан пароль")
-	ErrInvalidToken      = errors.New("токен авторизации недействителен")
-	ErrHTTPMethod        = errors.New("используемый HTTP-метод не разрешен")
-	ErrRequestParams     = errors.New("переданы некорректные параметры запроса")
-	ErrInternalServer    = errors.New("произошла внутренняя ошибка сервера")
-	ErrUnauthorized      = errors.New("требуется авторизация для доступа к ресурсу")
+	ErrUserAlreadyExists    = errors.New("пользователь с таким email уже зарегистрирован")
+	ErrInvalidEmail         = errors.New("введен некорректный адрес электронной почты")
+	ErrWeakPassword         = errors.New("пароль не соответствует требованиям безопасности")
+	ErrUserNotFound         = errors.New("учетная запись не найдена")
+	ErrInvalidPassword      = errors.New("неверно указан пароль")
+	ErrInvalidToken         = errors.New("токен авторизации недействителен")
+	ErrHTTPMethod           = errors.New("используемый HTTP-метод не разрешен")
+	ErrRequestParams        = errors.New("переданы некорректные параметры запроса")
+	ErrInternalServer       = errors.New("произошла внутренняя ошибка сервера")
+	ErrUnauthorized         = errors.New("требуется авторизация для доступа к ресурсу")
+	ErrSessionNotFound      = errors.New("сессия не найдена")
+	ErrTokenReused          = errors.New("обнаружено повторное использование токена обновления")
+	ErrResetTokenInvalid    = errors.New("ссылка для сброса пароля недействительна или устарела")
+	ErrVerifyTokenInvalid   = errors.New("ссылка для подтверждения email недействительна или устарела")
+	ErrEmailAlreadyVerified = errors.New("email уже подтвержден")
+	ErrEmailNotSet          = errors.New("у аккаунта не указан email")
+	ErrEmailChangeInvalid   = errors.New("ссылка для смены email недействительна или устарела")
+	ErrEmailUnchanged       = errors.New("новый email совпадает с текущим")
+	ErrTooManyRequests      = errors.New("слишком много запросов, попробуйте позже")
+	ErrAccountDeleted       = errors.New("аккаунт удален; его можно восстановить, пока данные не обезличены")
+	ErrReauthRequired       = errors.New("подтвердите личность: введите код 2FA или войдите заново")
+
+	ErrTwoFactorCodeInvalid    = errors.New("неверный код подтверждения")
+	ErrTwoFactorAlreadyEnabled = errors.New("двухфакторная аутентификация уже включена")
+	ErrTwoFactorNotEnrolled    = errors.New("двухфакторная аутентификация не настроена")
+	ErrTwoFactorRequired       = errors.New("для вашей роли двухфакторная аутентификация обязательна")
+	ErrChallengeInvalid        = errors.New("время на подтверждение входа истекло, войдите заново")
+
+	ErrUnknownRole     = errors.New("неизвестная роль")
+	ErrRoleNotAssigned = errors.New("у пользователя нет такой роли")
+	ErrForbidden       = errors.New("недостаточно прав для выполнения операции")
+	ErrRevokeOwnAdmin  = errors.New("нельзя снять роль администратора с самого себя")
+
+	ErrInvalidPhone       = errors.New("введен некорректный номер телефона")
+	ErrPhoneCodeInvalid   = errors.New("неверный или устаревший код из SMS")
+	ErrPhoneCodeTooSoon   = errors.New("код уже отправлен, запросить новый можно чуть позже")
+	ErrPhoneLoginDisabled = errors.New("вход по номеру телефона недоступен")
+
+	ErrUnknownProvider          = errors.New("вход через этот сервис недоступен")
+	ErrExternalStateInvalid     = errors.New("время на вход истекло, попробуйте еще раз")
+	ErrExternalLoginFailed      = errors.New("не удалось войти через внешний сервис")
+	ErrExternalIdentityConflict = errors.New("аккаунт уже привязан к другому профилю этого сервиса")
+
+	ErrAPIKeyNotFound      = errors.New("API-ключ не найден")
+	ErrAPIKeyInvalidName   = errors.New("название API-ключа должно быть от 1 до 100 символов")
+	ErrAPIKeyInvalidExpiry = errors.New("срок действия API-ключа должен быть от 1 до 365 дней")
+	ErrUnknownScope        = errors.New("неизвестная область доступа API-ключа")
+	ErrScopeNotAllowed     = errors.New("ваша роль не позволяет выпустить ключ с такой областью доступа")
+	ErrTooManyAPIKeys      = errors.New("достигнуто максимальное число действующих API-ключей")
 )
@@ -0,0 +1,32 @@
+package domain
+
+import "apple_backend/pkg/passcheck"
+
+// WeakPasswordError — пароль отклонен проверкой стойкости. Score — оценка
+// от 0 до 4, Reason — машиночитаемая причина (passcheck.Reason*).
+type WeakPasswordError struct {
+	Score  int
+	Reason string
+}
+
+var weakPasswordMessages = map[string]string{
+	passcheck.ReasonTooShort:           "пароль должен быть не короче 8 символов",
+	passcheck.ReasonCharacterClasses:   "пароль должен содержать строчные и заглавные буквы, цифры и спецсимволы",
+	passcheck.ReasonBreached:           "этот пароль встречался в утечках данных, выберите другой",
+	passcheck.ReasonCommonPassword:     "пароль основан на слишком распространенном пароле",
+	passcheck.ReasonContainsEmail:      "пароль не должен содержать ваш email",
+	passcheck.ReasonKeyboardSequence:   "пароль содержит последовательность соседних клавиш",
+	passcheck.ReasonSequence:           "пароль содержит простую последовательность символов",
+	passcheck.ReasonRepeated:           "пароль содержит повторяющиеся символы",
+	passcheck.ReasonDate:               "пароль содержит дату или год, их легко угадать",
+	passcheck.ReasonTransliteratedWord: "пароль содержит русское слово, набранное латиницей",
+}
+
+func (e *WeakPasswordError) Error() string {
+	if msg, ok := weakPasswordMessages[e.Reason]; ok {
+		return msg
+	}
+	return ErrWeakPassword.Error()
+}
+
+func (e *WeakPasswordError) Unwrap() error { return ErrWeakPassword }
@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"time"
+)
+
+// Session — одна авторизация пользователя (устройство), внутри которой
+// ротируются refresh-токены. Все токены сессии образуют одно семейство.
+type Session struct {
+	ID        string
+	UserID    string
+	UserAgent string
+	IP        string
+	RevokedAt *time.Time
+	// MFAVerifiedAt — когда в сессии был подтвержден второй фактор
+	MFAVerifiedAt *time.Time
+	LastSeenAt    time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// RefreshToken — непрозрачный refresh-токен, в БД хранится только его хэш.
+type RefreshToken struct {
+	ID               string
+	SessionID        string
+	UserID           string
+	ExpiresAt        time.Time
+	UsedAt           *time.Time
+	SessionRevokedAt *time.Time
+
+	SessionMFAVerified bool
+}
@@ -0,0 +1,13 @@
+package domain
+
+import "time"
+
+// ThrottledError — попытка отклонена из-за превышения лимита. RetryAfter
+// подсказывает клиенту, когда можно повторить запрос.
+type ThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *ThrottledError) Error() string { return ErrTooManyRequests.Error() }
+
+func (e *ThrottledError) Unwrap() error { return ErrTooManyRequests }
@@ -0,0 +1,11 @@
+package domain
+
+import "time"
+
+// TOTP — секрет второго фактора пользователя. Секрет появляется при начале
+// настройки, а EnabledAt — только после подтверждения первым кодом.
+type TOTP struct {
+	Secret    string
+	EnabledAt *time.Time
+	LastStep  int64
+}
@@ -0,0 +1,46 @@
+package mailer
+
+import (
+	"apple_backend/pkg/logger"
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+)
+
+// FileMailer не отправляет письма, а дописывает их в файл или stdout.
+// Нужен для локальной разработки, чтобы забирать ссылки из писем.
+type FileMailer struct {
+	mu   sync.Mutex
+	out  io.Writer
+	from string
+}
+
+func NewFileMailer(out io.Writer, from string) *FileMailer {
+	return &FileMailer{out: out, from: from}
+}
+
+// NewFileMailerFromPath открывает файл на дозапись; пустой путь — stdout.
+func NewFileMailerFromPath(path, from string) (*FileMailer, error) {
+	if path == "" {
+		return NewFileMailer(os.Stdout, from), nil
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	return NewFileMailer(f, from), nil
+}
+
+func (m *FileMailer) Send(ctx context.Context, to, subject, body string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, err := m.out.Write(buildMessage(m.from, to, subject, body)); err != nil {
+		logger.FromContext(ctx).ErrorContext(ctx, "file mailer Send failed", slog.Any("err", err), slog.String("to", to))
+		return err
+	}
+	_, err := io.WriteString(m.out, "\r\n")
+	return err
+}
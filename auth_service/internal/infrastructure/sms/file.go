@@ -0,0 +1,46 @@
+package sms
+
+import (
+	"apple_backend/pkg/logger"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// FileSender не отправляет SMS, а дописывает их в файл или stdout.
+// Нужен для локальной разработки, чтобы забирать коды для входа.
+type FileSender struct {
+	mu  sync.Mutex
+	out io.Writer
+}
+
+func NewFileSender(out io.Writer) *FileSender {
+	return &FileSender{out: out}
+}
+
+// NewFileSenderFromPath открывает файл на дозапись; пустой путь — stdout.
+func NewFileSenderFromPath(path string) (*FileSender, error) {
+	if path == "" {
+		return NewFileSender(os.Stdout), nil
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	return NewFileSender(f), nil
+}
+
+func (s *FileSender) Send(ctx context.Context, phone, text string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := fmt.Fprintf(s.out, "%s SMS to +%s: %s\n", time.Now().Format(time.RFC3339), phone, text); err != nil {
+		logger.FromContext(ctx).ErrorContext(ctx, "file sms Send failed", slog.Any("err", err))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"apple_backend/auth_service/internal/domain"
+	"apple_backend/pkg/logger"
+	"context"
+	_ "embed"
+	"log/slog"
+
+	"github.com/google/uuid"
+)
+
+//go:embed sql/api_key/create_api_key.sql
+var createAPIKeySQL string
+
+//go:embed sql/api_key/get_user_api_keys.sql
+var getUserAPIKeysSQL string
+
+//go:embed sql/api_key/revoke_api_key.sql
+var revokeAPIKeySQL string
+
+// APIKeyRepoPostgres — персональные API-ключи
+type APIKeyRepoPostgres struct {
+	db PgxIface
+}
+
+func NewAPIKeyRepoPostgres(db PgxIface) *APIKeyRepoPostgres {
+	return &APIKeyRepoPostgres{db: db}
+}
+
+// CreateAPIKey сохраняет ключ. Хранится только хэш секретной части.
+func (r *APIKeyRepoPostgres) CreateAPIKey(ctx context.Context, key *domain.APIKey, secretHash string) (*domain.APIKey, error) {
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "repo CreateAPIKey start", slog.String("user_id", key.UserID), slog.String("prefix", key.Prefix))
+
+	var k domain.APIKey
+	err := r.db.QueryRow(ctx, createAPIKeySQL, uuid.NewString(), key.UserID, key.Name, key.Prefix, secretHash, key.Scopes, key.ExpiresAt).
+		Scan(&k.ID, &k.UserID, &k.Name, &k.Prefix, &k.Scopes, &k.ExpiresAt, &k.LastUsedAt, &k.RevokedAt, &k.CreatedAt)
+	if err != nil {
+		log.ErrorContext(ctx, "repo CreateAPIKey database error", slog.Any("err", err), slog.String("user_id", key.UserID))
+		return nil, err
+	}
+
+	log.InfoContext(ctx, "repo CreateAPIKey success", slog.String("key_id", k.ID))
+	return &k, nil
+}
+
+// GetUserAPIKeys возвращает действующие ключи пользователя.
+func (r *APIKeyRepoPostgres) GetUserAPIKeys(ctx context.Context, userID string) ([]*domain.APIKey, error) {
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "repo GetUserAPIKeys start", slog.String("user_id", userID))
+
+	rows, err := r.db.Query(ctx, getUserAPIKeysSQL, userID)
+	if err != nil {
+		log.ErrorContext(ctx, "repo GetUserAPIKeys database error", slog.Any("err", err), slog.String("user_id", userID))
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := make([]*domain.APIKey, 0)
+	for rows.Next() {
+		var k domain.APIKey
+		if err := rows.Scan(&k.ID, &k.UserID, &k.Name, &k.Prefix, &k.Scopes, &k.ExpiresAt, &k.LastUsedAt, &k.RevokedAt, &k.CreatedAt); err != nil {
+			log.ErrorContext(ctx, "repo GetUserAPIKeys scan error", slog.Any("err", err), slog.String("user_id", userID))
+			return nil, err
+		}
+		keys = append(keys, &k)
+	}
+	if err := rows.Err(); err != nil {
+		log.ErrorContext(ctx, "repo GetUserAPIKeys rows error", slog.Any("err", err), slog.String("user_id", userID))
+		return nil, err
+	}
+
+	log.InfoContext(ctx, "repo GetUserAPIKeys success", slog.String("user_id", userID), slog.Int("count", len(keys)))
+	return keys, nil
+}
+
+// RevokeAPIKey отзывает ключ пользователя. Чужой ключ неотличим от
+// несуществующего.
+func (r *APIKeyRepoPostgres) RevokeAPIKey(ctx context.Context, userID, keyID string) error {
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "repo RevokeAPIKey start", slog.String("user_id", userID), slog.String("key_id", keyID))
+
+	tag, err := r.db.Exec(ctx, revokeAPIKeySQL, keyID, userID)
+	if err != nil {
+		log.ErrorContext(ctx, "repo RevokeAPIKey database error", slog.Any("err", err), slog.String("key_id", keyID))
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		log.WarnContext(ctx, "repo RevokeAPIKey not found", slog.String("key_id", keyID))
+		return domain.ErrAPIKeyNotFound
+	}
+
+	log.InfoContext(ctx, "repo RevokeAPIKey success", slog.String("key_id", keyID))
+	return nil
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"apple_backend/auth_service/internal/domain"
+	"apple_backend/pkg/logger"
+	"context"
+	_ "embed"
+	"log/slog"
+
+	"github.com/google/uuid"
+)
+
+//go:embed sql/auth_event/create_auth_event.sql
+var createAuthEventSQL string
+
+//go:embed sql/auth_event/list_auth_events.sql
+var listAuthEventsSQL string
+
+// AuthEventRepoPostgres — журнал событий авторизации в таблице auth_event
+type AuthEventRepoPostgres struct {
+	db PgxIface
+}
+
+func NewAuthEventRepoPostgres(db PgxIface) *AuthEventRepoPostgres {
+	return &AuthEventRepoPostgres{db: db}
+}
+
+// CreateAuthEvent дописывает событие в журнал и заполняет ID и CreatedAt.
+func (r *AuthEventRepoPostgres) CreateAuthEvent(ctx context.Context, event *domain.AuthEvent) error {
+	log := logger.FromContext(ctx)
+	log.DebugContext(ctx, "repo CreateAuthEvent start", slog.String("type", event.Type), slog.String("user_id", event.UserID))
+
+	id := uuid.NewString()
+	err := r.db.QueryRow(ctx, createAuthEventSQL, id, event.UserID, event.Type, event.Method,
+		event.Identifier, event.IP, event.UserAgent, event.RequestID).Scan(&event.CreatedAt)
+	if err != nil {
+		log.ErrorContext(ctx, "repo CreateAuthEvent database error", slog.Any("err", err), slog.String("type", event.Type))
+		return err
+	}
+	event.ID = id
+
+	log.DebugContext(ctx, "repo CreateAuthEvent success", slog.String("event_id", id))
+	return nil
+}
+
+// ListAuthEvents возвращает события от новых к старым.
+func (r *AuthEventRepoPostgres) ListAuthEvents(ctx context.Context, filter *domain.AuthEventFilter) ([]*domain.AuthEvent, error) {
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "repo ListAuthEvents start",
+		slog.String("user_id", filter.UserID),
+		slog.String("type", filter.Type),
+		slog.String("last_id", filter.LastID),
+		slog.Int("limit", filter.Limit))
+
+	rows, err := r.db.Query(ctx, listAuthEventsSQL, filter.UserID, filter.Type, filter.IP,
+		filter.From, filter.To, filter.LastID, filter.Limit)
+	if err != nil {
+		log.ErrorContext(ctx, "repo ListAuthEvents database error", slog.Any("err", err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := make([]*domain.AuthEvent, 0, filter.Limit)
+	for rows.Next() {
+		var e domain.AuthEvent
+		if err := rows.Scan(&e.ID, &e.UserID, &e.Type, &e.Method, &e.Identifier,
+			&e.IP, &e.UserAgent, &e.RequestID, &e.CreatedAt); err != nil {
+			log.ErrorContext(ctx, "repo ListAuthEvents scan error", slog.Any("err", err))
+			return nil, err
+		}
+		events = append(events, &e)
+	}
+	if err := rows.Err(); err != nil {
+		log.ErrorContext(ctx, "repo ListAuthEvents rows error", slog.Any("err", err))
+		return nil, err
+	}
+
+	log.InfoContext(ctx, "repo ListAuthEvents success", slog.Int("count", len(events)))
+	return events, nil
+}
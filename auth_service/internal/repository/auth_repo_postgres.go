@@ -5,7 +5,9 @@ import (
 	"apple_backend/pkg/logger"
 	"context"
 	_ "embed"
+	"errors"
 	"log/slog"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -32,7 +34,7 @@ func (r *AuthRepoPostgres) CreateUser(ctx context.Context, email, hashedPassword
 
 	var u domain.User
 	err := r.db.QueryRow(ctx, createUserSQL, id, email, hashedPassword).
-		Scan(&u.ID, &u.Email, &u.PasswordHash, &u.CreatedAt, &u.UpdatedAt)
+		Scan(&u.ID, &u.Email, &u.PasswordHash, &u.EmailVerifiedAt, &u.TwoFactorEnabledAt, &u.CreatedAt, &u.UpdatedAt)
 	if err != nil {
 		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" {
 			log.WarnContext(ctx, "repo CreateUser user already exists", slog.String("email", email))
@@ -55,7 +57,7 @@ func (r *AuthRepoPostgres) GetUserByEmail(ctx context.Context, email string) (*d
 
 	var u domain.User
 	err := r.db.QueryRow(ctx, getUserByEmailSQL, email).
-		Scan(&u.ID, &u.Email, &u.PasswordHash, &u.CreatedAt, &u.UpdatedAt)
+		Scan(&u.ID, &u.Email, &u.PasswordHash, &u.EmailVerifiedAt, &u.TwoFactorEnabledAt, &u.CreatedAt, &u.UpdatedAt, &u.DeletedAt)
 	if err == pgx.ErrNoRows {
 		log.WarnContext(ctx, "repo GetUserByEmail user not found", slog.String("email", email))
 		return nil, domain.ErrUserNotFound
@@ -78,7 +80,7 @@ func (r *AuthRepoPostgres) GetUserByID(ctx context.Context, id string) (*domain.
 
 	var u domain.User
 	err := r.db.QueryRow(ctx, getUserByIDSQL, id).
-		Scan(&u.ID, &u.Email, &u.PasswordHash, &u.CreatedAt, &u.UpdatedAt)
+		Scan(&u.ID, &u.Email, &u.PasswordHash, &u.EmailVerifiedAt, &u.TwoFactorEnabledAt, &u.CreatedAt, &u.UpdatedAt, &u.DeletedAt)
 	if err == pgx.ErrNoRows {
 		log.WarnContext(ctx, "repo GetUserByID user not found", slog.String("user_id", id))
 		return nil, domain.ErrUserNotFound
@@ -109,3 +111,184 @@ func (r *AuthRepoPostgres) UserExists(ctx context.Context, email string) (bool,
 	log.DebugContext(ctx, "repo UserExists result", slog.Bool("exists", exists), slog.String("email", email))
 	return exists, nil
 }
+
+//go:embed sql/auth/create_password_reset.sql
+var createPasswordResetSQL string
+
+func (r *AuthRepoPostgres) CreatePasswordReset(ctx context.Context, userID, tokenHash string, expiresAt time.Time) error {
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "repo CreatePasswordReset start", slog.String("user_id", userID))
+
+	if _, err := r.db.Exec(ctx, createPasswordResetSQL, uuid.NewString(), userID, tokenHash, expiresAt); err != nil {
+		log.ErrorContext(ctx, "repo CreatePasswordReset database error", slog.Any("err", err), slog.String("user_id", userID))
+		return err
+	}
+
+	log.InfoContext(ctx, "repo CreatePasswordReset success", slog.String("user_id", userID))
+	return nil
+}
+
+//go:embed sql/auth/get_password_reset_user_id.sql
+var getPasswordResetUserIDSQL string
+
+func (r *AuthRepoPostgres) GetPasswordResetUserID(ctx context.Context, tokenHash string) (string, error) {
+	log := logger.FromContext(ctx)
+	log.DebugContext(ctx, "repo GetPasswordResetUserID start")
+
+	var userID string
+	err := r.db.QueryRow(ctx, getPasswordResetUserIDSQL, tokenHash).Scan(&userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		log.WarnContext(ctx, "repo GetPasswordResetUserID token not found or expired")
+		return "", domain.ErrResetTokenInvalid
+	}
+	if err != nil {
+		log.ErrorContext(ctx, "repo GetPasswordResetUserID database error", slog.Any("err", err))
+		return "", err
+	}
+
+	log.DebugContext(ctx, "repo GetPasswordResetUserID success", slog.String("user_id", userID))
+	return userID, nil
+}
+
+//go:embed sql/auth/consume_password_reset.sql
+var consumePasswordResetSQL string
+
+//go:embed sql/auth/invalidate_password_resets.sql
+var invalidatePasswordResetsSQL string
+
+//go:embed sql/auth/update_password.sql
+var updatePasswordSQL string
+
+// ResetPassword гасит токен сброса и меняет хэш пароля в одной транзакции.
+// Остальные неиспользованные токены пользователя тоже гасятся.
+func (r *AuthRepoPostgres) ResetPassword(ctx context.Context, tokenHash, hashedPassword string) (string, error) {
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "repo ResetPassword start")
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		log.ErrorContext(ctx, "repo ResetPassword begin failed", slog.Any("err", err))
+		return "", err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var userID string
+	err = tx.QueryRow(ctx, consumePasswordResetSQL, tokenHash).Scan(&userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		log.WarnContext(ctx, "repo ResetPassword token not found or already used")
+		return "", domain.ErrResetTokenInvalid
+	}
+	if err != nil {
+		log.ErrorContext(ctx, "repo ResetPassword consume failed", slog.Any("err", err))
+		return "", err
+	}
+
+	if _, err = tx.Exec(ctx, updatePasswordSQL, userID, hashedPassword); err != nil {
+		log.ErrorContext(ctx, "repo ResetPassword update hash failed", slog.Any("err", err), slog.String("user_id", userID))
+		return "", err
+	}
+
+	if _, err = tx.Exec(ctx, invalidatePasswordResetsSQL, userID); err != nil {
+		log.ErrorContext(ctx, "repo ResetPassword invalidate tokens failed", slog.Any("err", err), slog.String("user_id", userID))
+		return "", err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.ErrorContext(ctx, "repo ResetPassword commit failed", slog.Any("err", err), slog.String("user_id", userID))
+		return "", err
+	}
+
+	log.InfoContext(ctx, "repo ResetPassword success", slog.String("user_id", userID))
+	return userID, nil
+}
+
+//go:embed sql/auth/mark_email_verified.sql
+var markEmailVerifiedSQL string
+
+// MarkEmailVerified подтверждает email, только если он не менялся с момента
+// отправки письма.
+func (r *AuthRepoPostgres) MarkEmailVerified(ctx context.Context, userID, email string) error {
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "repo MarkEmailVerified start", slog.String("user_id", userID))
+
+	tag, err := r.db.Exec(ctx, markEmailVerifiedSQL, userID, email)
+	if err != nil {
+		log.ErrorContext(ctx, "repo MarkEmailVerified database error", slog.Any("err", err), slog.String("user_id", userID))
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		log.WarnContext(ctx, "repo MarkEmailVerified user not found", slog.String("user_id", userID))
+		return domain.ErrUserNotFound
+	}
+
+	log.InfoContext(ctx, "repo MarkEmailVerified success", slog.String("user_id", userID))
+	return nil
+}
+
+//go:embed sql/auth/restore_account.sql
+var restoreAccountSQL string
+
+// RestoreAccount отменяет удаление аккаунта, пока он не обезличен
+func (r *AuthRepoPostgres) RestoreAccount(ctx context.Context, userID string) error {
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "repo RestoreAccount start", slog.String("user_id", userID))
+
+	tag, err := r.db.Exec(ctx, restoreAccountSQL, userID)
+	if err != nil {
+		log.ErrorContext(ctx, "repo RestoreAccount database error", slog.Any("err", err), slog.String("user_id", userID))
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		log.WarnContext(ctx, "repo RestoreAccount user not found", slog.String("user_id", userID))
+		return domain.ErrUserNotFound
+	}
+
+	log.InfoContext(ctx, "repo RestoreAccount success", slog.String("user_id", userID))
+	return nil
+}
+
+//go:embed sql/auth/rehash_password.sql
+var rehashPasswordSQL string
+
+// RehashPassword заменяет хэш пароля пересчитанным, только если в базе все
+// еще лежит oldHash: пароль мог успеть смениться параллельно.
+func (r *AuthRepoPostgres) RehashPassword(ctx context.Context, userID, oldHash, newHash string) error {
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "repo RehashPassword start", slog.String("user_id", userID))
+
+	tag, err := r.db.Exec(ctx, rehashPasswordSQL, userID, oldHash, newHash)
+	if err != nil {
+		log.ErrorContext(ctx, "repo RehashPassword database error", slog.Any("err", err), slog.String("user_id", userID))
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		log.WarnContext(ctx, "repo RehashPassword hash changed concurrently", slog.String("user_id", userID))
+		return nil
+	}
+
+	log.InfoContext(ctx, "repo RehashPassword success", slog.String("user_id", userID))
+	return nil
+}
+
+//go:embed sql/auth/touch_verification_sent.sql
+var touchVerificationSentSQL string
+
+// TouchVerificationSent фиксирует отправку письма с подтверждением. Если
+// предыдущее письмо ушло меньше interval назад, возвращает domain.ErrTooManyRequests.
+func (r *AuthRepoPostgres) TouchVerificationSent(ctx context.Context, userID string, interval time.Duration) error {
+	log := logger.FromContext(ctx)
+	log.DebugContext(ctx, "repo TouchVerificationSent start", slog.String("user_id", userID))
+
+	tag, err := r.db.Exec(ctx, touchVerificationSentSQL, userID, interval.Seconds())
+	if err != nil {
+		log.ErrorContext(ctx, "repo TouchVerificationSent database error", slog.Any("err", err), slog.String("user_id", userID))
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		log.WarnContext(ctx, "repo TouchVerificationSent too frequent", slog.String("user_id", userID))
+		return domain.ErrTooManyRequests
+	}
+
+	log.DebugContext(ctx, "repo TouchVerificationSent success", slog.String("user_id", userID))
+	return nil
+}
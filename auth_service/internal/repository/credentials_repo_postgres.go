@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"apple_backend/auth_service/internal/domain"
+	"apple_backend/pkg/logger"
+	"context"
+	_ "embed"
+	"errors"
+	"log/slog"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// CredentialsRepoPostgres — смена пароля и email вошедшим пользователем
+type CredentialsRepoPostgres struct {
+	db PgxIface
+}
+
+func NewCredentialsRepoPostgres(db PgxIface) *CredentialsRepoPostgres {
+	return &CredentialsRepoPostgres{db: db}
+}
+
+//go:embed sql/auth/change_password.sql
+var changePasswordSQL string
+
+// ChangePassword меняет хэш, только если в базе все еще лежит oldHash, и
+// гасит неиспользованные ссылки сброса пароля. Если пароль успели сменить
+// параллельно — domain.ErrInvalidPassword.
+func (r *CredentialsRepoPostgres) ChangePassword(ctx context.Context, userID, oldHash, newHash string) error {
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "repo ChangePassword start", slog.String("user_id", userID))
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		log.ErrorContext(ctx, "repo ChangePassword begin failed", slog.Any("err", err))
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	tag, err := tx.Exec(ctx, changePasswordSQL, userID, oldHash, newHash)
+	if err != nil {
+		log.ErrorContext(ctx, "repo ChangePassword update hash failed", slog.Any("err", err), slog.String("user_id", userID))
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		log.WarnContext(ctx, "repo ChangePassword hash changed concurrently", slog.String("user_id", userID))
+		return domain.ErrInvalidPassword
+	}
+
+	if _, err = tx.Exec(ctx, invalidatePasswordResetsSQL, userID); err != nil {
+		log.ErrorContext(ctx, "repo ChangePassword invalidate tokens failed", slog.Any("err", err), slog.String("user_id", userID))
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.ErrorContext(ctx, "repo ChangePassword commit failed", slog.Any("err", err), slog.String("user_id", userID))
+		return err
+	}
+
+	log.InfoContext(ctx, "repo ChangePassword success", slog.String("user_id", userID))
+	return nil
+}
+
+//go:embed sql/auth/change_email.sql
+var changeEmailSQL string
+
+// ChangeEmail заменяет адрес подтвержденным newEmail, только если текущий
+// адрес все еще oldEmail (пустой — адреса нет), и гасит ссылки сброса
+// пароля, ушедшие на старый адрес. Занятый адрес — domain.ErrUserAlreadyExists,
+// не прошедший ограничения таблицы — domain.ErrInvalidEmail, сменившийся —
+// domain.ErrEmailChangeInvalid.
+func (r *CredentialsRepoPostgres) ChangeEmail(ctx context.Context, userID, oldEmail, newEmail string) error {
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "repo ChangeEmail start", slog.String("user_id", userID))
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		log.ErrorContext(ctx, "repo ChangeEmail begin failed", slog.Any("err", err))
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	tag, err := tx.Exec(ctx, changeEmailSQL, userID, oldEmail, newEmail)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			log.WarnContext(ctx, "repo ChangeEmail email taken", slog.String("user_id", userID))
+			return domain.ErrUserAlreadyExists
+		}
+		if errors.As(err, &pgErr) && pgErr.Code == "23514" {
+			log.WarnContext(ctx, "repo ChangeEmail email rejected by constraint", slog.String("user_id", userID),
+				slog.String("constraint", pgErr.ConstraintName))
+			return domain.ErrInvalidEmail
+		}
+		log.ErrorContext(ctx, "repo ChangeEmail update failed", slog.Any("err", err), slog.String("user_id", userID))
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		log.WarnContext(ctx, "repo ChangeEmail email changed concurrently", slog.String("user_id", userID))
+		return domain.ErrEmailChangeInvalid
+	}
+
+	if _, err = tx.Exec(ctx, invalidatePasswordResetsSQL, userID); err != nil {
+		log.ErrorContext(ctx, "repo ChangeEmail invalidate tokens failed", slog.Any("err", err), slog.String("user_id", userID))
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.ErrorContext(ctx, "repo ChangeEmail commit failed", slog.Any("err", err), slog.String("user_id", userID))
+		return err
+	}
+
+	log.InfoContext(ctx, "repo ChangeEmail success", slog.String("user_id", userID))
+	return nil
+}
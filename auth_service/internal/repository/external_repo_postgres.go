@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"apple_backend/auth_service/internal/domain"
+	"apple_backend/pkg/logger"
+	"context"
+	_ "embed"
+	"errors"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+//go:embed sql/external/get_external_identity_user.sql
+var getExternalIdentityUserSQL string
+
+//go:embed sql/external/link_external_identity.sql
+var linkExternalIdentitySQL string
+
+//go:embed sql/external/create_external_user.sql
+var createExternalUserSQL string
+
+//go:embed sql/external/claim_unverified_account.sql
+var claimUnverifiedAccountSQL string
+
+//go:embed sql/external/revoke_user_api_keys.sql
+var revokeUserAPIKeysSQL string
+
+// ExternalRepoPostgres — аккаунты, привязанные к внешним провайдерам
+type ExternalRepoPostgres struct {
+	db PgxIface
+}
+
+func NewExternalRepoPostgres(db PgxIface) *ExternalRepoPostgres {
+	return &ExternalRepoPostgres{db: db}
+}
+
+// GetExternalIdentityUser ищет аккаунт, привязанный к пользователю провайдера.
+func (r *ExternalRepoPostgres) GetExternalIdentityUser(ctx context.Context, provider, subject string) (*domain.User, error) {
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "repo GetExternalIdentityUser start", slog.String("provider", provider))
+
+	var u domain.User
+	err := r.db.QueryRow(ctx, getExternalIdentityUserSQL, provider, subject).
+		Scan(&u.ID, &u.Email, &u.PasswordHash, &u.EmailVerifiedAt, &u.TwoFactorEnabledAt, &u.CreatedAt, &u.UpdatedAt, &u.DeletedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		log.InfoContext(ctx, "repo GetExternalIdentityUser not linked", slog.String("provider", provider))
+		return nil, domain.ErrUserNotFound
+	}
+	if err != nil {
+		log.ErrorContext(ctx, "repo GetExternalIdentityUser database error", slog.Any("err", err), slog.String("provider", provider))
+		return nil, err
+	}
+
+	log.InfoContext(ctx, "repo GetExternalIdentityUser success", slog.String("user_id", u.ID), slog.String("provider", provider))
+	return &u, nil
+}
+
+// LinkExternalIdentity привязывает пользователя провайдера к аккаунту. Если
+// у аккаунта уже есть другой пользователь этого провайдера или этот
+// пользователь привязан к другому аккаунту — domain.ErrExternalIdentityConflict.
+func (r *ExternalRepoPostgres) LinkExternalIdentity(ctx context.Context, userID, provider, subject, email string) error {
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "repo LinkExternalIdentity start", slog.String("user_id", userID), slog.String("provider", provider))
+
+	_, err := r.db.Exec(ctx, linkExternalIdentitySQL, uuid.NewString(), userID, provider, subject, email)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			log.WarnContext(ctx, "repo LinkExternalIdentity conflict", slog.String("user_id", userID), slog.String("provider", provider))
+			return domain.ErrExternalIdentityConflict
+		}
+		log.ErrorContext(ctx, "repo LinkExternalIdentity database error", slog.Any("err", err), slog.String("user_id", userID))
+		return err
+	}
+
+	log.InfoContext(ctx, "repo LinkExternalIdentity success", slog.String("user_id", userID), slog.String("provider", provider))
+	return nil
+}
+
+// ClaimUnverifiedAccount привязывает пользователя провайдера к аккаунту с
+// неподтвержденным email и подтверждает адрес. Пароль, TOTP, коды
+// восстановления, сессии, API-ключи и ссылки сброса мог оставить тот, кто
+// зарегистрировался на чужой адрес раньше владельца, поэтому в той же
+// транзакции они сбрасываются. Если адрес успели подтвердить или сменить —
+// domain.ErrExternalLoginFailed.
+func (r *ExternalRepoPostgres) ClaimUnverifiedAccount(ctx context.Context, userID, email, provider, subject string) error {
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "repo ClaimUnverifiedAccount start", slog.String("user_id", userID), slog.String("provider", provider))
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		log.ErrorContext(ctx, "repo ClaimUnverifiedAccount begin failed", slog.Any("err", err))
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	tag, err := tx.Exec(ctx, claimUnverifiedAccountSQL, userID, email)
+	if err != nil {
+		log.ErrorContext(ctx, "repo ClaimUnverifiedAccount reset credentials failed", slog.Any("err", err), slog.String("user_id", userID))
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		log.WarnContext(ctx, "repo ClaimUnverifiedAccount email changed concurrently", slog.String("user_id", userID))
+		return domain.ErrExternalLoginFailed
+	}
+
+	if _, err = tx.Exec(ctx, deleteRecoveryCodesSQL, userID); err != nil {
+		log.ErrorContext(ctx, "repo ClaimUnverifiedAccount delete recovery codes failed", slog.Any("err", err), slog.String("user_id", userID))
+		return err
+	}
+
+	if _, err = tx.Exec(ctx, revokeUserSessionsSQL, userID); err != nil {
+		log.ErrorContext(ctx, "repo ClaimUnverifiedAccount revoke sessions failed", slog.Any("err", err), slog.String("user_id", userID))
+		return err
+	}
+
+	if _, err = tx.Exec(ctx, revokeUserAPIKeysSQL, userID); err != nil {
+		log.ErrorContext(ctx, "repo ClaimUnverifiedAccount revoke api keys failed", slog.Any("err", err), slog.String("user_id", userID))
+		return err
+	}
+
+	if _, err = tx.Exec(ctx, invalidatePasswordResetsSQL, userID); err != nil {
+		log.ErrorContext(ctx, "repo ClaimUnverifiedAccount invalidate tokens failed", slog.Any("err", err), slog.String("user_id", userID))
+		return err
+	}
+
+	_, err = tx.Exec(ctx, linkExternalIdentitySQL, uuid.NewString(), userID, provider, subject, email)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			log.WarnContext(ctx, "repo ClaimUnverifiedAccount link conflict", slog.String("user_id", userID), slog.String("provider", provider))
+			return domain.ErrExternalIdentityConflict
+		}
+		log.ErrorContext(ctx, "repo ClaimUnverifiedAccount link failed", slog.Any("err", err), slog.String("user_id", userID))
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.ErrorContext(ctx, "repo ClaimUnverifiedAccount commit failed", slog.Any("err", err), slog.String("user_id", userID))
+		return err
+	}
+
+	log.InfoContext(ctx, "repo ClaimUnverifiedAccount success", slog.String("user_id", userID), slog.String("provider", provider))
+	return nil
+}
+
+// CreateExternalUser создает аккаунт без пароля. Пустой email сохраняется
+// как NULL, непустой считается подтвержденным.
+func (r *ExternalRepoPostgres) CreateExternalUser(ctx context.Context, email string) (*domain.User, error) {
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "repo CreateExternalUser start", slog.String("email", email))
+
+	var u domain.User
+	err := r.db.QueryRow(ctx, createExternalUserSQL, uuid.NewString(), email).
+		Scan(&u.ID, &u.Email, &u.PasswordHash, &u.EmailVerifiedAt, &u.TwoFactorEnabledAt, &u.CreatedAt, &u.UpdatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			log.WarnContext(ctx, "repo CreateExternalUser user already exists", slog.String("email", email))
+			return nil, domain.ErrUserAlreadyExists
+		}
+		if errors.As(err, &pgErr) && pgErr.Code == "23514" {
+			log.WarnContext(ctx, "repo CreateExternalUser email rejected by constraint", slog.String("email", email),
+				slog.String("constraint", pgErr.ConstraintName))
+			return nil, domain.ErrInvalidEmail
+		}
+		log.ErrorContext(ctx, "repo CreateExternalUser database error", slog.Any("err", err), slog.String("email", email))
+		return nil, err
+	}
+
+	log.InfoContext(ctx, "repo CreateExternalUser success", slog.String("user_id", u.ID))
+	return &u, nil
+}
@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"apple_backend/auth_service/internal/domain"
+	"apple_backend/pkg/logger"
+	"context"
+	_ "embed"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+//go:embed sql/auth/get_user_by_phone.sql
+var getUserByPhoneSQL string
+
+//go:embed sql/auth/create_phone_user.sql
+var createPhoneUserSQL string
+
+//go:embed sql/phone/create_phone_code.sql
+var createPhoneCodeSQL string
+
+//go:embed sql/phone/use_phone_code_attempt.sql
+var usePhoneCodeAttemptSQL string
+
+//go:embed sql/phone/consume_phone_code.sql
+var consumePhoneCodeSQL string
+
+//go:embed sql/phone/delete_phone_code.sql
+var deletePhoneCodeSQL string
+
+//go:embed sql/phone/delete_expired_phone_codes.sql
+var deleteExpiredPhoneCodesSQL string
+
+// PhoneRepoPostgres — вход по коду из SMS
+type PhoneRepoPostgres struct {
+	db PgxIface
+}
+
+func NewPhoneRepoPostgres(db PgxIface) *PhoneRepoPostgres {
+	return &PhoneRepoPostgres{db: db}
+}
+
+// GetUserByPhone ищет аккаунт с подтвержденным номером.
+func (r *PhoneRepoPostgres) GetUserByPhone(ctx context.Context, phone string) (*domain.User, error) {
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "repo GetUserByPhone start")
+
+	var u domain.User
+	err := r.db.QueryRow(ctx, getUserByPhoneSQL, phone).
+		Scan(&u.ID, &u.Email, &u.PasswordHash, &u.EmailVerifiedAt, &u.TwoFactorEnabledAt, &u.CreatedAt, &u.UpdatedAt, &u.DeletedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		log.InfoContext(ctx, "repo GetUserByPhone user not found")
+		return nil, domain.ErrUserNotFound
+	}
+	if err != nil {
+		log.ErrorContext(ctx, "repo GetUserByPhone database error", slog.Any("err", err))
+		return nil, err
+	}
+
+	log.InfoContext(ctx, "repo GetUserByPhone success", slog.String("user_id", u.ID))
+	return &u, nil
+}
+
+// CreatePhoneUser создает аккаунт без email и пароля с уже подтвержденным
+// номером.
+func (r *PhoneRepoPostgres) CreatePhoneUser(ctx context.Context, phone string) (*domain.User, error) {
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "repo CreatePhoneUser start")
+
+	var u domain.User
+	err := r.db.QueryRow(ctx, createPhoneUserSQL, uuid.NewString(), phone).
+		Scan(&u.ID, &u.Email, &u.PasswordHash, &u.EmailVerifiedAt, &u.TwoFactorEnabledAt, &u.CreatedAt, &u.UpdatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			log.WarnContext(ctx, "repo CreatePhoneUser phone already taken")
+			return nil, domain.ErrUserAlreadyExists
+		}
+		log.ErrorContext(ctx, "repo CreatePhoneUser database error", slog.Any("err", err))
+		return nil, err
+	}
+
+	log.InfoContext(ctx, "repo CreatePhoneUser success", slog.String("user_id", u.ID))
+	return &u, nil
+}
+
+// CreatePhoneCode заменяет код для номера. Если прошлый код отправлен
+// меньше resendInterval назад, возвращает domain.ErrPhoneCodeTooSoon.
+func (r *PhoneRepoPostgres) CreatePhoneCode(ctx context.Context, phone, codeHash string, expiresAt time.Time, resendInterval time.Duration) error {
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "repo CreatePhoneCode start")
+
+	var sentAt time.Time
+	err := r.db.QueryRow(ctx, createPhoneCodeSQL, phone, codeHash, expiresAt, resendInterval.Seconds()).Scan(&sentAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		log.WarnContext(ctx, "repo CreatePhoneCode resend too soon")
+		return domain.ErrPhoneCodeTooSoon
+	}
+	if err != nil {
+		log.ErrorContext(ctx, "repo CreatePhoneCode database error", slog.Any("err", err))
+		return err
+	}
+
+	log.InfoContext(ctx, "repo CreatePhoneCode success")
+	return nil
+}
+
+// UsePhoneCodeAttempt учитывает попытку ввода и возвращает хэш действующего
+// кода. Если кода нет, он истек или попытки кончились — domain.ErrPhoneCodeInvalid.
+func (r *PhoneRepoPostgres) UsePhoneCodeAttempt(ctx context.Context, phone string, maxAttempts int) (string, error) {
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "repo UsePhoneCodeAttempt start")
+
+	var codeHash string
+	err := r.db.QueryRow(ctx, usePhoneCodeAttemptSQL, phone, maxAttempts).Scan(&codeHash)
+	if errors.Is(err, pgx.ErrNoRows) {
+		log.WarnContext(ctx, "repo UsePhoneCodeAttempt no active code")
+		return "", domain.ErrPhoneCodeInvalid
+	}
+	if err != nil {
+		log.ErrorContext(ctx, "repo UsePhoneCodeAttempt database error", slog.Any("err", err))
+		return "", err
+	}
+
+	log.InfoContext(ctx, "repo UsePhoneCodeAttempt success")
+	return codeHash, nil
+}
+
+// ConsumePhoneCode удаляет использованный код. Код, который уже забрал
+// параллельный запрос, считается недействительным.
+func (r *PhoneRepoPostgres) ConsumePhoneCode(ctx context.Context, phone, codeHash string) error {
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "repo ConsumePhoneCode start")
+
+	tag, err := r.db.Exec(ctx, consumePhoneCodeSQL, phone, codeHash)
+	if err != nil {
+		log.ErrorContext(ctx, "repo ConsumePhoneCode database error", slog.Any("err", err))
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		log.WarnContext(ctx, "repo ConsumePhoneCode already used")
+		return domain.ErrPhoneCodeInvalid
+	}
+
+	log.InfoContext(ctx, "repo ConsumePhoneCode success")
+	return nil
+}
+
+// DeletePhoneCode удаляет код, который не удалось отправить.
+func (r *PhoneRepoPostgres) DeletePhoneCode(ctx context.Context, phone string) error {
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "repo DeletePhoneCode start")
+
+	if _, err := r.db.Exec(ctx, deletePhoneCodeSQL, phone); err != nil {
+		log.ErrorContext(ctx, "repo DeletePhoneCode database error", slog.Any("err", err))
+		return err
+	}
+
+	log.InfoContext(ctx, "repo DeletePhoneCode success")
+	return nil
+}
+
+func (r *PhoneRepoPostgres) DeleteExpiredPhoneCodes(ctx context.Context) error {
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "repo DeleteExpiredPhoneCodes start")
+
+	tag, err := r.db.Exec(ctx, deleteExpiredPhoneCodesSQL)
+	if err != nil {
+		log.ErrorContext(ctx, "repo DeleteExpiredPhoneCodes database error", slog.Any("err", err))
+		return err
+	}
+
+	log.InfoContext(ctx, "repo DeleteExpiredPhoneCodes success", slog.Int64("deleted", tag.RowsAffected()))
+	return nil
+}
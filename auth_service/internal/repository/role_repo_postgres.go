@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"apple_backend/auth_service/internal/domain"
+	"apple_backend/pkg/logger"
+	"context"
+	_ "embed"
+	"log/slog"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+//go:embed sql/role/get_user_roles.sql
+var getUserRolesSQL string
+
+//go:embed sql/role/grant_role.sql
+var grantRoleSQL string
+
+//go:embed sql/role/revoke_role.sql
+var revokeRoleSQL string
+
+//go:embed sql/role/grant_role_by_email.sql
+var grantRoleByEmailSQL string
+
+// RoleRepoPostgres — роли аккаунтов
+type RoleRepoPostgres struct {
+	db PgxIface
+}
+
+func NewRoleRepoPostgres(db PgxIface) *RoleRepoPostgres {
+	return &RoleRepoPostgres{db: db}
+}
+
+func (r *RoleRepoPostgres) GetUserRoles(ctx context.Context, userID string) ([]string, error) {
+	log := logger.FromContext(ctx)
+	log.DebugContext(ctx, "repo GetUserRoles start", slog.String("user_id", userID))
+
+	rows, err := r.db.Query(ctx, getUserRolesSQL, userID)
+	if err != nil {
+		log.ErrorContext(ctx, "repo GetUserRoles query failed", slog.Any("err", err), slog.String("user_id", userID))
+		return nil, err
+	}
+	defer rows.Close()
+
+	roles := []string{}
+	for rows.Next() {
+		var role string
+		if err := rows.Scan(&role); err != nil {
+			log.ErrorContext(ctx, "repo GetUserRoles scan failed", slog.Any("err", err), slog.String("user_id", userID))
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+	if err := rows.Err(); err != nil {
+		log.ErrorContext(ctx, "repo GetUserRoles rows error", slog.Any("err", err), slog.String("user_id", userID))
+		return nil, err
+	}
+
+	log.DebugContext(ctx, "repo GetUserRoles success", slog.String("user_id", userID), slog.Int("count", len(roles)))
+	return roles, nil
+}
+
+func (r *RoleRepoPostgres) GrantRole(ctx context.Context, userID, role string) error {
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "repo GrantRole start", slog.String("user_id", userID), slog.String("role", role))
+
+	if _, err := r.db.Exec(ctx, grantRoleSQL, userID, role); err != nil {
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23503" {
+			log.WarnContext(ctx, "repo GrantRole user not found", slog.String("user_id", userID))
+			return domain.ErrUserNotFound
+		}
+		log.ErrorContext(ctx, "repo GrantRole database error", slog.Any("err", err), slog.String("user_id", userID))
+		return err
+	}
+
+	log.InfoContext(ctx, "repo GrantRole success", slog.String("user_id", userID), slog.String("role", role))
+	return nil
+}
+
+func (r *RoleRepoPostgres) RevokeRole(ctx context.Context, userID, role string) error {
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "repo RevokeRole start", slog.String("user_id", userID), slog.String("role", role))
+
+	tag, err := r.db.Exec(ctx, revokeRoleSQL, userID, role)
+	if err != nil {
+		log.ErrorContext(ctx, "repo RevokeRole database error", slog.Any("err", err), slog.String("user_id", userID))
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		log.WarnContext(ctx, "repo RevokeRole role not assigned", slog.String("user_id", userID), slog.String("role", role))
+		return domain.ErrRoleNotAssigned
+	}
+
+	log.InfoContext(ctx, "repo RevokeRole success", slog.String("user_id", userID), slog.String("role", role))
+	return nil
+}
+
+// GrantRoleByEmail выдает роль по email; возвращает false, если аккаунта нет.
+func (r *RoleRepoPostgres) GrantRoleByEmail(ctx context.Context, email, role string) (bool, error) {
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "repo GrantRoleByEmail start", slog.String("email", email), slog.String("role", role))
+
+	tag, err := r.db.Exec(ctx, grantRoleByEmailSQL, email, role)
+	if err != nil {
+		log.ErrorContext(ctx, "repo GrantRoleByEmail database error", slog.Any("err", err), slog.String("email", email))
+		return false, err
+	}
+
+	log.InfoContext(ctx, "repo GrantRoleByEmail success", slog.String("email", email), slog.Int64("granted", tag.RowsAffected()))
+	return tag.RowsAffected() > 0, nil
+}
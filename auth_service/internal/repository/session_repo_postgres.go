@@ -0,0 +1,261 @@
+package repository
+
+import (
+	"apple_backend/auth_service/internal/domain"
+	"apple_backend/pkg/logger"
+	"context"
+	_ "embed"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+//go:embed sql/session/create_session.sql
+var createSessionSQL string
+
+//go:embed sql/session/insert_refresh_token.sql
+var insertRefreshTokenSQL string
+
+//go:embed sql/session/get_refresh_token.sql
+var getRefreshTokenSQL string
+
+//go:embed sql/session/mark_refresh_token_used.sql
+var markRefreshTokenUsedSQL string
+
+//go:embed sql/session/revoke_session.sql
+var revokeSessionSQL string
+
+//go:embed sql/session/get_session.sql
+var getSessionSQL string
+
+//go:embed sql/session/get_user_sessions.sql
+var getUserSessionsSQL string
+
+//go:embed sql/session/revoke_other_sessions.sql
+var revokeOtherSessionsSQL string
+
+//go:embed sql/session/touch_session.sql
+var touchSessionSQL string
+
+//go:embed sql/session/revoke_user_sessions.sql
+var revokeUserSessionsSQL string
+
+//go:embed sql/session/mark_session_mfa.sql
+var markSessionMFASQL string
+
+type SessionRepoPostgres struct {
+	db PgxIface
+}
+
+func NewSessionRepoPostgres(db PgxIface) *SessionRepoPostgres {
+	return &SessionRepoPostgres{db: db}
+}
+
+func (r *SessionRepoPostgres) CreateSession(ctx context.Context, session *domain.Session, tokenHash string, expiresAt time.Time) (*domain.Session, error) {
+	userID := session.UserID
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "repo CreateSession start", slog.String("user_id", userID))
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		log.ErrorContext(ctx, "repo CreateSession begin failed", slog.Any("err", err), slog.String("user_id", userID))
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var s domain.Session
+	err = tx.QueryRow(ctx, createSessionSQL, uuid.NewString(), userID, session.UserAgent, session.IP, session.MFAVerifiedAt).
+		Scan(&s.ID, &s.UserID, &s.UserAgent, &s.IP, &s.RevokedAt, &s.MFAVerifiedAt, &s.LastSeenAt, &s.CreatedAt, &s.UpdatedAt)
+	if err != nil {
+		log.ErrorContext(ctx, "repo CreateSession insert session failed", slog.Any("err", err), slog.String("user_id", userID))
+		return nil, err
+	}
+
+	if _, err = tx.Exec(ctx, insertRefreshTokenSQL, uuid.NewString(), s.ID, tokenHash, expiresAt); err != nil {
+		log.ErrorContext(ctx, "repo CreateSession insert refresh token failed", slog.Any("err", err), slog.String("session_id", s.ID))
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.ErrorContext(ctx, "repo CreateSession commit failed", slog.Any("err", err), slog.String("session_id", s.ID))
+		return nil, err
+	}
+
+	log.InfoContext(ctx, "repo CreateSession success", slog.String("session_id", s.ID), slog.String("user_id", userID))
+	return &s, nil
+}
+
+func (r *SessionRepoPostgres) GetRefreshToken(ctx context.Context, tokenHash string) (*domain.RefreshToken, error) {
+	log := logger.FromContext(ctx)
+	log.DebugContext(ctx, "repo GetRefreshToken start")
+
+	var t domain.RefreshToken
+	err := r.db.QueryRow(ctx, getRefreshTokenSQL, tokenHash).
+		Scan(&t.ID, &t.SessionID, &t.UserID, &t.ExpiresAt, &t.UsedAt, &t.SessionRevokedAt, &t.SessionMFAVerified)
+	if errors.Is(err, pgx.ErrNoRows) {
+		log.WarnContext(ctx, "repo GetRefreshToken token not found")
+		return nil, domain.ErrSessionNotFound
+	}
+	if err != nil {
+		log.ErrorContext(ctx, "repo GetRefreshToken database error", slog.Any("err", err))
+		return nil, err
+	}
+
+	log.DebugContext(ctx, "repo GetRefreshToken success", slog.String("session_id", t.SessionID))
+	return &t, nil
+}
+
+// RotateRefreshToken помечает старый токен использованным и выпускает новый
+// в той же сессии. Если старый токен уже был использован (в том числе
+// параллельным запросом), возвращает domain.ErrTokenReused.
+func (r *SessionRepoPostgres) RotateRefreshToken(ctx context.Context, oldTokenID, sessionID, newHash string, expiresAt time.Time) error {
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "repo RotateRefreshToken start", slog.String("session_id", sessionID))
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		log.ErrorContext(ctx, "repo RotateRefreshToken begin failed", slog.Any("err", err), slog.String("session_id", sessionID))
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	tag, err := tx.Exec(ctx, markRefreshTokenUsedSQL, oldTokenID)
+	if err != nil {
+		log.ErrorContext(ctx, "repo RotateRefreshToken mark used failed", slog.Any("err", err), slog.String("session_id", sessionID))
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		log.WarnContext(ctx, "repo RotateRefreshToken token already used", slog.String("session_id", sessionID))
+		return domain.ErrTokenReused
+	}
+
+	if _, err = tx.Exec(ctx, insertRefreshTokenSQL, uuid.NewString(), sessionID, newHash, expiresAt); err != nil {
+		log.ErrorContext(ctx, "repo RotateRefreshToken insert failed", slog.Any("err", err), slog.String("session_id", sessionID))
+		return err
+	}
+
+	if _, err = tx.Exec(ctx, touchSessionSQL, sessionID); err != nil {
+		log.ErrorContext(ctx, "repo RotateRefreshToken touch session failed", slog.Any("err", err), slog.String("session_id", sessionID))
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.ErrorContext(ctx, "repo RotateRefreshToken commit failed", slog.Any("err", err), slog.String("session_id", sessionID))
+		return err
+	}
+
+	log.InfoContext(ctx, "repo RotateRefreshToken success", slog.String("session_id", sessionID))
+	return nil
+}
+
+func (r *SessionRepoPostgres) RevokeSession(ctx context.Context, sessionID string) error {
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "repo RevokeSession start", slog.String("session_id", sessionID))
+
+	if _, err := r.db.Exec(ctx, revokeSessionSQL, sessionID); err != nil {
+		log.ErrorContext(ctx, "repo RevokeSession database error", slog.Any("err", err), slog.String("session_id", sessionID))
+		return err
+	}
+
+	log.InfoContext(ctx, "repo RevokeSession success", slog.String("session_id", sessionID))
+	return nil
+}
+
+func (r *SessionRepoPostgres) GetSession(ctx context.Context, sessionID string) (*domain.Session, error) {
+	log := logger.FromContext(ctx)
+	log.DebugContext(ctx, "repo GetSession start", slog.String("session_id", sessionID))
+
+	var s domain.Session
+	err := r.db.QueryRow(ctx, getSessionSQL, sessionID).
+		Scan(&s.ID, &s.UserID, &s.UserAgent, &s.IP, &s.RevokedAt, &s.MFAVerifiedAt, &s.LastSeenAt, &s.CreatedAt, &s.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		log.WarnContext(ctx, "repo GetSession session not found", slog.String("session_id", sessionID))
+		return nil, domain.ErrSessionNotFound
+	}
+	if err != nil {
+		log.ErrorContext(ctx, "repo GetSession database error", slog.Any("err", err), slog.String("session_id", sessionID))
+		return nil, err
+	}
+
+	log.DebugContext(ctx, "repo GetSession success", slog.String("session_id", sessionID))
+	return &s, nil
+}
+
+func (r *SessionRepoPostgres) GetUserSessions(ctx context.Context, userID string) ([]*domain.Session, error) {
+	log := logger.FromContext(ctx)
+	log.DebugContext(ctx, "repo GetUserSessions start", slog.String("user_id", userID))
+
+	rows, err := r.db.Query(ctx, getUserSessionsSQL, userID)
+	if err != nil {
+		log.ErrorContext(ctx, "repo GetUserSessions query failed", slog.Any("err", err), slog.String("user_id", userID))
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := []*domain.Session{}
+	for rows.Next() {
+		var s domain.Session
+		if err := rows.Scan(&s.ID, &s.UserID, &s.UserAgent, &s.IP, &s.RevokedAt, &s.MFAVerifiedAt, &s.LastSeenAt, &s.CreatedAt, &s.UpdatedAt); err != nil {
+			log.ErrorContext(ctx, "repo GetUserSessions scan failed", slog.Any("err", err), slog.String("user_id", userID))
+			return nil, err
+		}
+		sessions = append(sessions, &s)
+	}
+	if err := rows.Err(); err != nil {
+		log.ErrorContext(ctx, "repo GetUserSessions rows error", slog.Any("err", err), slog.String("user_id", userID))
+		return nil, err
+	}
+
+	log.DebugContext(ctx, "repo GetUserSessions success", slog.String("user_id", userID), slog.Int("count", len(sessions)))
+	return sessions, nil
+}
+
+func (r *SessionRepoPostgres) RevokeOtherSessions(ctx context.Context, userID, keepSessionID string) error {
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "repo RevokeOtherSessions start", slog.String("user_id", userID), slog.String("keep_session_id", keepSessionID))
+
+	tag, err := r.db.Exec(ctx, revokeOtherSessionsSQL, userID, keepSessionID)
+	if err != nil {
+		log.ErrorContext(ctx, "repo RevokeOtherSessions database error", slog.Any("err", err), slog.String("user_id", userID))
+		return err
+	}
+
+	log.InfoContext(ctx, "repo RevokeOtherSessions success", slog.String("user_id", userID), slog.Int64("revoked", tag.RowsAffected()))
+	return nil
+}
+
+func (r *SessionRepoPostgres) RevokeUserSessions(ctx context.Context, userID string) error {
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "repo RevokeUserSessions start", slog.String("user_id", userID))
+
+	tag, err := r.db.Exec(ctx, revokeUserSessionsSQL, userID)
+	if err != nil {
+		log.ErrorContext(ctx, "repo RevokeUserSessions database error", slog.Any("err", err), slog.String("user_id", userID))
+		return err
+	}
+
+	log.InfoContext(ctx, "repo RevokeUserSessions success", slog.String("user_id", userID), slog.Int64("revoked", tag.RowsAffected()))
+	return nil
+}
+
+// MarkSessionMFA отмечает, что в сессии подтвержден второй фактор.
+func (r *SessionRepoPostgres) MarkSessionMFA(ctx context.Context, sessionID string) error {
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "repo MarkSessionMFA start", slog.String("session_id", sessionID))
+
+	tag, err := r.db.Exec(ctx, markSessionMFASQL, sessionID)
+	if err != nil {
+		log.ErrorContext(ctx, "repo MarkSessionMFA database error", slog.Any("err", err), slog.String("session_id", sessionID))
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		log.WarnContext(ctx, "repo MarkSessionMFA session not found", slog.String("session_id", sessionID))
+		return domain.ErrSessionNotFound
+	}
+
+	log.InfoContext(ctx, "repo MarkSessionMFA success", slog.String("session_id", sessionID))
+	return nil
+}
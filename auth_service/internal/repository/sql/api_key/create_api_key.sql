@@ -0,0 +1,3 @@
+INSERT INTO api_key (id, user_id, name, prefix, secret_hash, scopes, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, user_id, name, prefix, scopes, expires_at, last_used_at, revoked_at, created_at;
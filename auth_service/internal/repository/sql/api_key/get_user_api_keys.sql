@@ -0,0 +1,6 @@
+SELECT id, user_id, name, prefix, scopes, expires_at, last_used_at, revoked_at, created_at
+FROM api_key
+WHERE user_id = $1
+  AND revoked_at IS NULL
+  AND expires_at > current_timestamp
+ORDER BY created_at DESC;
@@ -0,0 +1,5 @@
+UPDATE api_key
+SET revoked_at = current_timestamp
+WHERE id = $1
+  AND user_id = $2
+  AND revoked_at IS NULL;
@@ -0,0 +1,5 @@
+UPDATE account
+SET email             = $3,
+    email_verified_at = current_timestamp
+WHERE id = $1
+  AND email IS NOT DISTINCT FROM nullif($2, '');
@@ -0,0 +1,6 @@
+UPDATE password_reset_token
+SET used_at = current_timestamp
+WHERE hash = $1
+  AND used_at IS NULL
+  AND expires_at > current_timestamp
+RETURNING user_id;
\ No newline at end of file
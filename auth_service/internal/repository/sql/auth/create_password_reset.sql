@@ -0,0 +1,2 @@
+INSERT INTO password_reset_token (id, user_id, hash, expires_at)
+VALUES ($1, $2, $3, $4);
\ No newline at end of file
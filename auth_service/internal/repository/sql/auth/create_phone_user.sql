@@ -0,0 +1,10 @@
+WITH created AS (
+    INSERT INTO account (id, hash, phone, phone_verified_at)
+        VALUES ($1, '', $2, current_timestamp)
+        RETURNING id, email, hash, email_verified_at, totp_enabled_at, created_at, updated_at),
+     default_role AS (
+         INSERT INTO account_role (user_id, role)
+             SELECT id, 'customer'
+             FROM created)
+SELECT id, coalesce(email, ''), hash, email_verified_at, totp_enabled_at, created_at, updated_at
+FROM created;
@@ -1,3 +1,10 @@
-INSERT INTO account (id, email, hash)
-VALUES ($1, $2, $3)
-RETURNING id, email, hash, created_at, updated_at;
\ No newline at end of file
+WITH created AS (
+    INSERT INTO account (id, email, hash)
+        VALUES ($1, $2, $3)
+        RETURNING id, email, hash, email_verified_at, totp_enabled_at, created_at, updated_at),
+     default_role AS (
+         INSERT INTO account_role (user_id, role)
+             SELECT id, 'customer'
+             FROM created)
+SELECT id, email, hash, email_verified_at, totp_enabled_at, created_at, updated_at
+FROM created;
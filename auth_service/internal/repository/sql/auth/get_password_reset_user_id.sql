@@ -0,0 +1,5 @@
+SELECT user_id
+FROM password_reset_token
+WHERE hash = $1
+  AND used_at IS NULL
+  AND expires_at > current_timestamp;
\ No newline at end of file
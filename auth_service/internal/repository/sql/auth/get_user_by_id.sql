@@ -1,3 +1,4 @@
-SELECT id, email, hash, created_at, updated_at
+SELECT id, coalesce(email, ''), hash, email_verified_at, totp_enabled_at, created_at, updated_at, deleted_at
 FROM account
-WHERE id = $1;
\ No newline at end of file
+WHERE id = $1
+  AND anonymized_at IS NULL;
\ No newline at end of file
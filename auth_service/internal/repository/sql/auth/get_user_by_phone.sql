@@ -0,0 +1,5 @@
+SELECT id, coalesce(email, ''), hash, email_verified_at, totp_enabled_at, created_at, updated_at, deleted_at
+FROM account
+WHERE phone = $1
+  AND phone_verified_at IS NOT NULL
+  AND anonymized_at IS NULL;
\ No newline at end of file
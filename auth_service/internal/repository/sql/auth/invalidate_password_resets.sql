@@ -0,0 +1,4 @@
+UPDATE password_reset_token
+SET used_at = current_timestamp
+WHERE user_id = $1
+  AND used_at IS NULL;
\ No newline at end of file
@@ -0,0 +1,4 @@
+UPDATE account
+SET email_verified_at = current_timestamp
+WHERE id = $1
+  AND email = $2;
\ No newline at end of file
@@ -0,0 +1,4 @@
+UPDATE account
+SET hash = $3
+WHERE id = $1
+  AND hash = $2;
@@ -0,0 +1,5 @@
+UPDATE account
+SET deleted_at = NULL
+WHERE id = $1
+  AND deleted_at IS NOT NULL
+  AND anonymized_at IS NULL;
\ No newline at end of file
@@ -0,0 +1,5 @@
+UPDATE account
+SET email_verification_sent_at = current_timestamp
+WHERE id = $1
+  AND (email_verification_sent_at IS NULL
+    OR email_verification_sent_at < current_timestamp - make_interval(secs => $2));
\ No newline at end of file
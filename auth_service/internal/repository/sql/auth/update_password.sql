@@ -0,0 +1,3 @@
+UPDATE account
+SET hash = $2
+WHERE id = $1;
\ No newline at end of file
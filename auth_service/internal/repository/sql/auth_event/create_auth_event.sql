@@ -0,0 +1,3 @@
+INSERT INTO auth_event (id, user_id, type, method, identifier, ip, user_agent, request_id)
+VALUES ($1, nullif($2, '')::uuid, $3, nullif($4, ''), nullif($5, ''), nullif($6, ''), nullif($7, ''), nullif($8, ''))
+RETURNING created_at;
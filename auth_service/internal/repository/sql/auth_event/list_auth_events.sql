@@ -0,0 +1,21 @@
+SELECT e.id,
+       coalesce(e.user_id::text, ''),
+       e.type,
+       coalesce(e.method, ''),
+       coalesce(e.identifier, ''),
+       coalesce(e.ip, ''),
+       coalesce(e.user_agent, ''),
+       coalesce(e.request_id, ''),
+       e.created_at
+FROM auth_event e
+WHERE ($1 = '' OR e.user_id = nullif($1, '')::uuid)
+  AND ($2 = '' OR e.type = $2)
+  AND ($3 = '' OR e.ip = $3)
+  AND ($4::timestamptz IS NULL OR e.created_at >= $4)
+  AND ($5::timestamptz IS NULL OR e.created_at < $5)
+  AND (
+    $6 = ''
+        OR (e.created_at, e.id) < (SELECT created_at, id FROM auth_event WHERE id = nullif($6, '')::uuid)
+    )
+ORDER BY e.created_at DESC, e.id DESC
+LIMIT $7;
@@ -0,0 +1,11 @@
+-- адрес подтвердил провайдер; пароль и второй фактор мог задать тот, кто
+-- зарегистрировался на чужой адрес раньше владельца
+UPDATE account
+SET email_verified_at = current_timestamp,
+    hash              = '',
+    totp_secret       = NULL,
+    totp_enabled_at   = NULL,
+    totp_last_step    = 0
+WHERE id = $1
+  AND email = $2
+  AND email_verified_at IS NULL;
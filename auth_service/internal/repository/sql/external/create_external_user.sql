@@ -0,0 +1,11 @@
+-- email от провайдера уже подтвержден; пароля у аккаунта нет
+WITH created AS (
+    INSERT INTO account (id, email, hash, email_verified_at)
+        VALUES ($1, nullif($2, ''), '', CASE WHEN $2 <> '' THEN current_timestamp END)
+        RETURNING id, email, hash, email_verified_at, totp_enabled_at, created_at, updated_at),
+     default_role AS (
+         INSERT INTO account_role (user_id, role)
+             SELECT id, 'customer'
+             FROM created)
+SELECT id, coalesce(email, ''), hash, email_verified_at, totp_enabled_at, created_at, updated_at
+FROM created;
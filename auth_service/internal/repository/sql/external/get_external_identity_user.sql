@@ -0,0 +1,6 @@
+SELECT a.id, coalesce(a.email, ''), a.hash, a.email_verified_at, a.totp_enabled_at, a.created_at, a.updated_at, a.deleted_at
+FROM external_identity ei
+         JOIN account a ON a.id = ei.user_id
+WHERE ei.provider = $1
+  AND ei.subject = $2
+  AND a.anonymized_at IS NULL;
\ No newline at end of file
@@ -0,0 +1,2 @@
+INSERT INTO external_identity (id, user_id, provider, subject, email)
+VALUES ($1, $2, $3, $4, nullif($5, ''));
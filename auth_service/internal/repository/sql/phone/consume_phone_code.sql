@@ -0,0 +1,4 @@
+DELETE
+FROM phone_code
+WHERE phone = $1
+  AND code_hash = $2;
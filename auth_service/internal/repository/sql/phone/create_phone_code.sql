@@ -0,0 +1,10 @@
+-- заменяет код, только если предыдущий отправлен раньше интервала $4 секунд
+INSERT INTO phone_code (phone, code_hash, attempts, expires_at, sent_at)
+VALUES ($1, $2, 0, $3, current_timestamp)
+ON CONFLICT (phone) DO UPDATE
+    SET code_hash  = excluded.code_hash,
+        attempts   = 0,
+        expires_at = excluded.expires_at,
+        sent_at    = excluded.sent_at
+WHERE phone_code.sent_at <= current_timestamp - make_interval(secs => $4)
+RETURNING sent_at;
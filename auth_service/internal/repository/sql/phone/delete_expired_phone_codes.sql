@@ -0,0 +1,3 @@
+DELETE
+FROM phone_code
+WHERE expires_at < current_timestamp;
@@ -0,0 +1,8 @@
+-- попытка засчитывается до сравнения кода, чтобы параллельные запросы не
+-- обходили лимит
+UPDATE phone_code
+SET attempts = attempts + 1
+WHERE phone = $1
+  AND expires_at > current_timestamp
+  AND attempts < $2
+RETURNING code_hash;
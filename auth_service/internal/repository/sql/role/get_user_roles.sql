@@ -0,0 +1,4 @@
+SELECT role
+FROM account_role
+WHERE user_id = $1
+ORDER BY role;
@@ -0,0 +1,3 @@
+INSERT INTO account_role (user_id, role)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,5 @@
+INSERT INTO account_role (user_id, role)
+SELECT id, $2
+FROM account
+WHERE email = $1
+ON CONFLICT DO NOTHING;
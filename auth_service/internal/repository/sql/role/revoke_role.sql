@@ -0,0 +1,4 @@
+DELETE
+FROM account_role
+WHERE user_id = $1
+  AND role = $2;
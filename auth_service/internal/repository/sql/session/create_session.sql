@@ -0,0 +1,3 @@
+INSERT INTO session (id, user_id, user_agent, ip, mfa_verified_at)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, user_id, coalesce(user_agent, ''), coalesce(ip, ''), revoked_at, mfa_verified_at, last_seen_at, created_at, updated_at;
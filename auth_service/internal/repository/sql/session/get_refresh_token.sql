@@ -0,0 +1,4 @@
+SELECT rt.id, rt.session_id, s.user_id, rt.expires_at, rt.used_at, s.revoked_at, s.mfa_verified_at IS NOT NULL
+FROM refresh_token rt
+         JOIN session s ON s.id = rt.session_id
+WHERE rt.hash = $1;
@@ -0,0 +1,3 @@
+SELECT id, user_id, coalesce(user_agent, ''), coalesce(ip, ''), revoked_at, mfa_verified_at, last_seen_at, created_at, updated_at
+FROM session
+WHERE id = $1;
\ No newline at end of file
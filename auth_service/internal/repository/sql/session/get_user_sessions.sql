@@ -0,0 +1,10 @@
+SELECT s.id, s.user_id, coalesce(s.user_agent, ''), coalesce(s.ip, ''), s.revoked_at, s.mfa_verified_at, s.last_seen_at, s.created_at, s.updated_at
+FROM session s
+WHERE s.user_id = $1
+  AND s.revoked_at IS NULL
+  AND EXISTS(SELECT 1
+             FROM refresh_token rt
+             WHERE rt.session_id = s.id
+               AND rt.used_at IS NULL
+               AND rt.expires_at > current_timestamp)
+ORDER BY s.last_seen_at DESC;
\ No newline at end of file
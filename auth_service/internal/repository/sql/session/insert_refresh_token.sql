@@ -0,0 +1,2 @@
+INSERT INTO refresh_token (id, session_id, hash, expires_at)
+VALUES ($1, $2, $3, $4);
\ No newline at end of file
@@ -0,0 +1,4 @@
+UPDATE refresh_token
+SET used_at = current_timestamp
+WHERE id = $1
+  AND used_at IS NULL;
\ No newline at end of file
@@ -0,0 +1,4 @@
+UPDATE session
+SET mfa_verified_at = current_timestamp
+WHERE id = $1
+  AND revoked_at IS NULL;
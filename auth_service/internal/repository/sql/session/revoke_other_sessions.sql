@@ -0,0 +1,5 @@
+UPDATE session
+SET revoked_at = current_timestamp
+WHERE user_id = $1
+  AND id <> $2
+  AND revoked_at IS NULL;
\ No newline at end of file
@@ -0,0 +1,4 @@
+UPDATE session
+SET revoked_at = current_timestamp
+WHERE user_id = $1
+  AND revoked_at IS NULL;
\ No newline at end of file
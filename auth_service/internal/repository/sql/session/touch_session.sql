@@ -0,0 +1,3 @@
+UPDATE session
+SET last_seen_at = current_timestamp
+WHERE id = $1;
\ No newline at end of file
@@ -0,0 +1,4 @@
+DELETE
+FROM auth_throttle
+WHERE updated_at < current_timestamp - make_interval(secs => $1)
+  AND (locked_until IS NULL OR locked_until < current_timestamp);
@@ -0,0 +1,13 @@
+INSERT INTO auth_throttle (key, attempts, window_start)
+VALUES ($1, 1, current_timestamp)
+ON CONFLICT (key) DO UPDATE
+    SET attempts     = CASE
+                           WHEN auth_throttle.window_start < current_timestamp - make_interval(secs => $2) THEN 1
+                           ELSE auth_throttle.attempts + 1
+        END,
+        window_start = CASE
+                           WHEN auth_throttle.window_start < current_timestamp - make_interval(secs => $2)
+                               THEN current_timestamp
+                           ELSE auth_throttle.window_start
+            END
+RETURNING attempts;
@@ -0,0 +1,3 @@
+UPDATE auth_throttle
+SET locked_until = greatest(locked_until, $2)
+WHERE key = $1;
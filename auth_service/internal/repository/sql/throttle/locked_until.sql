@@ -0,0 +1,4 @@
+SELECT max(locked_until)
+FROM auth_throttle
+WHERE key = ANY ($1)
+  AND locked_until > current_timestamp;
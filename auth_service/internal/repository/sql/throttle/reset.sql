@@ -0,0 +1,3 @@
+DELETE
+FROM auth_throttle
+WHERE key = $1;
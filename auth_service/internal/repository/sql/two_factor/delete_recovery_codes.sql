@@ -0,0 +1,3 @@
+DELETE
+FROM recovery_code
+WHERE user_id = $1;
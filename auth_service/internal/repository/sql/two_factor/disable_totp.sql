@@ -0,0 +1,5 @@
+UPDATE account
+SET totp_secret     = NULL,
+    totp_enabled_at = NULL,
+    totp_last_step  = 0
+WHERE id = $1;
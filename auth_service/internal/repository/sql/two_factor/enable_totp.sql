@@ -0,0 +1,6 @@
+UPDATE account
+SET totp_enabled_at = current_timestamp,
+    totp_last_step  = $2
+WHERE id = $1
+  AND totp_secret IS NOT NULL
+  AND totp_enabled_at IS NULL;
@@ -0,0 +1,3 @@
+SELECT coalesce(totp_secret, ''), totp_enabled_at, totp_last_step
+FROM account
+WHERE id = $1;
@@ -0,0 +1,2 @@
+INSERT INTO recovery_code (id, user_id, hash)
+VALUES ($1, $2, $3);
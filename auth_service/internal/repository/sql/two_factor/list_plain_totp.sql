@@ -0,0 +1,5 @@
+-- секреты, сохраненные до шифрования
+SELECT id, totp_secret
+FROM account
+WHERE totp_secret IS NOT NULL
+  AND totp_secret NOT LIKE 'v1:%';
@@ -0,0 +1,4 @@
+UPDATE account
+SET totp_secret = $3
+WHERE id = $1
+  AND totp_secret = $2;
@@ -0,0 +1,4 @@
+UPDATE account
+SET totp_secret = $2
+WHERE id = $1
+  AND totp_enabled_at IS NULL;
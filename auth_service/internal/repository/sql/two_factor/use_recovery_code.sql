@@ -0,0 +1,5 @@
+UPDATE recovery_code
+SET used_at = current_timestamp
+WHERE user_id = $1
+  AND hash = $2
+  AND used_at IS NULL;
@@ -0,0 +1,5 @@
+UPDATE account
+SET totp_last_step = $2
+WHERE id = $1
+  AND totp_enabled_at IS NOT NULL
+  AND totp_last_step < $2;
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"apple_backend/pkg/logger"
+	"context"
+	_ "embed"
+	"log/slog"
+	"time"
+)
+
+//go:embed sql/throttle/hit.sql
+var throttleHitSQL string
+
+//go:embed sql/throttle/lock.sql
+var throttleLockSQL string
+
+//go:embed sql/throttle/locked_until.sql
+var throttleLockedUntilSQL string
+
+//go:embed sql/throttle/reset.sql
+var throttleResetSQL string
+
+//go:embed sql/throttle/delete_stale.sql
+var throttleDeleteStaleSQL string
+
+// ThrottleRepoPostgres хранит счетчики попыток в БД, поэтому лимиты
+// действуют сразу на все реплики auth_service.
+type ThrottleRepoPostgres struct {
+	db PgxIface
+}
+
+func NewThrottleRepoPostgres(db PgxIface) *ThrottleRepoPostgres {
+	return &ThrottleRepoPostgres{db: db}
+}
+
+// Hit учитывает попытку по ключу и возвращает число попыток в текущем окне.
+func (r *ThrottleRepoPostgres) Hit(ctx context.Context, key string, window time.Duration) (int, error) {
+	log := logger.FromContext(ctx)
+	log.DebugContext(ctx, "repo ThrottleHit start", slog.String("key", key))
+
+	var attempts int
+	if err := r.db.QueryRow(ctx, throttleHitSQL, key, window.Seconds()).Scan(&attempts); err != nil {
+		log.ErrorContext(ctx, "repo ThrottleHit database error", slog.Any("err", err), slog.String("key", key))
+		return 0, err
+	}
+
+	log.DebugContext(ctx, "repo ThrottleHit success", slog.String("key", key), slog.Int("attempts", attempts))
+	return attempts, nil
+}
+
+func (r *ThrottleRepoPostgres) Lock(ctx context.Context, key string, until time.Time) error {
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "repo ThrottleLock start", slog.String("key", key), slog.Time("until", until))
+
+	if _, err := r.db.Exec(ctx, throttleLockSQL, key, until); err != nil {
+		log.ErrorContext(ctx, "repo ThrottleLock database error", slog.Any("err", err), slog.String("key", key))
+		return err
+	}
+
+	log.InfoContext(ctx, "repo ThrottleLock success", slog.String("key", key))
+	return nil
+}
+
+// LockedUntil возвращает самую позднюю активную блокировку среди ключей или nil.
+func (r *ThrottleRepoPostgres) LockedUntil(ctx context.Context, keys ...string) (*time.Time, error) {
+	log := logger.FromContext(ctx)
+	log.DebugContext(ctx, "repo ThrottleLockedUntil start", slog.Any("keys", keys))
+
+	var until *time.Time
+	if err := r.db.QueryRow(ctx, throttleLockedUntilSQL, keys).Scan(&until); err != nil {
+		log.ErrorContext(ctx, "repo ThrottleLockedUntil database error", slog.Any("err", err), slog.Any("keys", keys))
+		return nil, err
+	}
+
+	log.DebugContext(ctx, "repo ThrottleLockedUntil success", slog.Bool("locked", until != nil))
+	return until, nil
+}
+
+func (r *ThrottleRepoPostgres) Reset(ctx context.Context, key string) error {
+	log := logger.FromContext(ctx)
+	log.DebugContext(ctx, "repo ThrottleReset start", slog.String("key", key))
+
+	if _, err := r.db.Exec(ctx, throttleResetSQL, key); err != nil {
+		log.ErrorContext(ctx, "repo ThrottleReset database error", slog.Any("err", err), slog.String("key", key))
+		return err
+	}
+
+	log.DebugContext(ctx, "repo ThrottleReset success", slog.String("key", key))
+	return nil
+}
+
+// DeleteStale удаляет счетчики, которые не обновлялись дольше olderThan и
+// уже не блокируют вход.
+func (r *ThrottleRepoPostgres) DeleteStale(ctx context.Context, olderThan time.Duration) error {
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "repo ThrottleDeleteStale start")
+
+	tag, err := r.db.Exec(ctx, throttleDeleteStaleSQL, olderThan.Seconds())
+	if err != nil {
+		log.ErrorContext(ctx, "repo ThrottleDeleteStale database error", slog.Any("err", err))
+		return err
+	}
+
+	log.InfoContext(ctx, "repo ThrottleDeleteStale success", slog.Int64("deleted", tag.RowsAffected()))
+	return nil
+}
@@ -0,0 +1,252 @@
+package repository
+
+import (
+	"apple_backend/auth_service/internal/domain"
+	"apple_backend/pkg/logger"
+	"context"
+	_ "embed"
+	"errors"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+//go:embed sql/two_factor/set_pending_totp.sql
+var setPendingTOTPSQL string
+
+//go:embed sql/two_factor/get_totp.sql
+var getTOTPSQL string
+
+//go:embed sql/two_factor/enable_totp.sql
+var enableTOTPSQL string
+
+//go:embed sql/two_factor/use_totp_step.sql
+var useTOTPStepSQL string
+
+//go:embed sql/two_factor/disable_totp.sql
+var disableTOTPSQL string
+
+//go:embed sql/two_factor/list_plain_totp.sql
+var listPlainTOTPSQL string
+
+//go:embed sql/two_factor/replace_totp_secret.sql
+var replaceTOTPSecretSQL string
+
+//go:embed sql/two_factor/delete_recovery_codes.sql
+var deleteRecoveryCodesSQL string
+
+//go:embed sql/two_factor/insert_recovery_code.sql
+var insertRecoveryCodeSQL string
+
+//go:embed sql/two_factor/use_recovery_code.sql
+var useRecoveryCodeSQL string
+
+// TwoFactorRepoPostgres — секреты TOTP и коды восстановления
+type TwoFactorRepoPostgres struct {
+	db PgxIface
+}
+
+func NewTwoFactorRepoPostgres(db PgxIface) *TwoFactorRepoPostgres {
+	return &TwoFactorRepoPostgres{db: db}
+}
+
+// SetPendingTOTP сохраняет новый секрет, пока 2FA еще не подтверждена.
+func (r *TwoFactorRepoPostgres) SetPendingTOTP(ctx context.Context, userID, secret string) error {
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "repo SetPendingTOTP start", slog.String("user_id", userID))
+
+	tag, err := r.db.Exec(ctx, setPendingTOTPSQL, userID, secret)
+	if err != nil {
+		log.ErrorContext(ctx, "repo SetPendingTOTP database error", slog.Any("err", err), slog.String("user_id", userID))
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		log.WarnContext(ctx, "repo SetPendingTOTP already enabled", slog.String("user_id", userID))
+		return domain.ErrTwoFactorAlreadyEnabled
+	}
+
+	log.InfoContext(ctx, "repo SetPendingTOTP success", slog.String("user_id", userID))
+	return nil
+}
+
+func (r *TwoFactorRepoPostgres) GetTOTP(ctx context.Context, userID string) (*domain.TOTP, error) {
+	log := logger.FromContext(ctx)
+	log.DebugContext(ctx, "repo GetTOTP start", slog.String("user_id", userID))
+
+	var t domain.TOTP
+	err := r.db.QueryRow(ctx, getTOTPSQL, userID).Scan(&t.Secret, &t.EnabledAt, &t.LastStep)
+	if errors.Is(err, pgx.ErrNoRows) {
+		log.WarnContext(ctx, "repo GetTOTP user not found", slog.String("user_id", userID))
+		return nil, domain.ErrUserNotFound
+	}
+	if err != nil {
+		log.ErrorContext(ctx, "repo GetTOTP database error", slog.Any("err", err), slog.String("user_id", userID))
+		return nil, err
+	}
+	if t.Secret == "" {
+		return nil, domain.ErrTwoFactorNotEnrolled
+	}
+
+	log.DebugContext(ctx, "repo GetTOTP success", slog.String("user_id", userID))
+	return &t, nil
+}
+
+// EnableTOTP включает 2FA и заменяет коды восстановления новыми.
+func (r *TwoFactorRepoPostgres) EnableTOTP(ctx context.Context, userID string, step int64, recoveryHashes []string) error {
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "repo EnableTOTP start", slog.String("user_id", userID))
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		log.ErrorContext(ctx, "repo EnableTOTP begin failed", slog.Any("err", err), slog.String("user_id", userID))
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	tag, err := tx.Exec(ctx, enableTOTPSQL, userID, step)
+	if err != nil {
+		log.ErrorContext(ctx, "repo EnableTOTP update failed", slog.Any("err", err), slog.String("user_id", userID))
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		log.WarnContext(ctx, "repo EnableTOTP nothing to enable", slog.String("user_id", userID))
+		return domain.ErrTwoFactorAlreadyEnabled
+	}
+
+	if _, err = tx.Exec(ctx, deleteRecoveryCodesSQL, userID); err != nil {
+		log.ErrorContext(ctx, "repo EnableTOTP delete codes failed", slog.Any("err", err), slog.String("user_id", userID))
+		return err
+	}
+	for _, h := range recoveryHashes {
+		if _, err = tx.Exec(ctx, insertRecoveryCodeSQL, uuid.NewString(), userID, h); err != nil {
+			log.ErrorContext(ctx, "repo EnableTOTP insert code failed", slog.Any("err", err), slog.String("user_id", userID))
+			return err
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.ErrorContext(ctx, "repo EnableTOTP commit failed", slog.Any("err", err), slog.String("user_id", userID))
+		return err
+	}
+
+	log.InfoContext(ctx, "repo EnableTOTP success", slog.String("user_id", userID))
+	return nil
+}
+
+// UseTOTPStep запоминает принятый шаг. Шаг, не больше уже принятого,
+// означает повтор кода — возвращается domain.ErrTwoFactorCodeInvalid.
+func (r *TwoFactorRepoPostgres) UseTOTPStep(ctx context.Context, userID string, step int64) error {
+	log := logger.FromContext(ctx)
+	log.DebugContext(ctx, "repo UseTOTPStep start", slog.String("user_id", userID))
+
+	tag, err := r.db.Exec(ctx, useTOTPStepSQL, userID, step)
+	if err != nil {
+		log.ErrorContext(ctx, "repo UseTOTPStep database error", slog.Any("err", err), slog.String("user_id", userID))
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		log.WarnContext(ctx, "repo UseTOTPStep code replayed", slog.String("user_id", userID))
+		return domain.ErrTwoFactorCodeInvalid
+	}
+
+	log.DebugContext(ctx, "repo UseTOTPStep success", slog.String("user_id", userID))
+	return nil
+}
+
+func (r *TwoFactorRepoPostgres) UseRecoveryCode(ctx context.Context, userID, codeHash string) error {
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "repo UseRecoveryCode start", slog.String("user_id", userID))
+
+	tag, err := r.db.Exec(ctx, useRecoveryCodeSQL, userID, codeHash)
+	if err != nil {
+		log.ErrorContext(ctx, "repo UseRecoveryCode database error", slog.Any("err", err), slog.String("user_id", userID))
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		log.WarnContext(ctx, "repo UseRecoveryCode code not found", slog.String("user_id", userID))
+		return domain.ErrTwoFactorCodeInvalid
+	}
+
+	log.InfoContext(ctx, "repo UseRecoveryCode success", slog.String("user_id", userID))
+	return nil
+}
+
+func (r *TwoFactorRepoPostgres) DisableTOTP(ctx context.Context, userID string) error {
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "repo DisableTOTP start", slog.String("user_id", userID))
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		log.ErrorContext(ctx, "repo DisableTOTP begin failed", slog.Any("err", err), slog.String("user_id", userID))
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err = tx.Exec(ctx, disableTOTPSQL, userID); err != nil {
+		log.ErrorContext(ctx, "repo DisableTOTP update failed", slog.Any("err", err), slog.String("user_id", userID))
+		return err
+	}
+	if _, err = tx.Exec(ctx, deleteRecoveryCodesSQL, userID); err != nil {
+		log.ErrorContext(ctx, "repo DisableTOTP delete codes failed", slog.Any("err", err), slog.String("user_id", userID))
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.ErrorContext(ctx, "repo DisableTOTP commit failed", slog.Any("err", err), slog.String("user_id", userID))
+		return err
+	}
+
+	log.InfoContext(ctx, "repo DisableTOTP success", slog.String("user_id", userID))
+	return nil
+}
+
+// ListPlainTOTPSecrets возвращает незашифрованные секреты по id пользователя.
+func (r *TwoFactorRepoPostgres) ListPlainTOTPSecrets(ctx context.Context) (map[string]string, error) {
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "repo ListPlainTOTPSecrets start")
+
+	rows, err := r.db.Query(ctx, listPlainTOTPSQL)
+	if err != nil {
+		log.ErrorContext(ctx, "repo ListPlainTOTPSecrets database error", slog.Any("err", err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	secrets := make(map[string]string)
+	for rows.Next() {
+		var userID, secret string
+		if err := rows.Scan(&userID, &secret); err != nil {
+			log.ErrorContext(ctx, "repo ListPlainTOTPSecrets scan failed", slog.Any("err", err))
+			return nil, err
+		}
+		secrets[userID] = secret
+	}
+	if err := rows.Err(); err != nil {
+		log.ErrorContext(ctx, "repo ListPlainTOTPSecrets rows error", slog.Any("err", err))
+		return nil, err
+	}
+
+	log.InfoContext(ctx, "repo ListPlainTOTPSecrets success", slog.Int("count", len(secrets)))
+	return secrets, nil
+}
+
+// ReplaceTOTPSecret заменяет секрет, только если он не изменился с момента
+// чтения: параллельная перенастройка 2FA не затирается.
+func (r *TwoFactorRepoPostgres) ReplaceTOTPSecret(ctx context.Context, userID, oldSecret, newSecret string) error {
+	log := logger.FromContext(ctx)
+	log.DebugContext(ctx, "repo ReplaceTOTPSecret start", slog.String("user_id", userID))
+
+	tag, err := r.db.Exec(ctx, replaceTOTPSecretSQL, userID, oldSecret, newSecret)
+	if err != nil {
+		log.ErrorContext(ctx, "repo ReplaceTOTPSecret database error", slog.Any("err", err), slog.String("user_id", userID))
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		log.WarnContext(ctx, "repo ReplaceTOTPSecret secret changed", slog.String("user_id", userID))
+		return nil
+	}
+
+	log.DebugContext(ctx, "repo ReplaceTOTPSecret success", slog.String("user_id", userID))
+	return nil
+}
@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"apple_backend/auth_service/internal/domain"
+	"apple_backend/pkg/apikey"
+	"apple_backend/pkg/rbac"
+	"context"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	defaultAPIKeyTTLDays = 90
+	maxAPIKeyTTLDays     = 365
+	maxActiveAPIKeys     = 20
+	maxAPIKeyNameLen     = 100
+)
+
+// CreateAPIKey выпускает ключ со scopes. roles — роли текущей сессии:
+// scope, требующий разрешения, выпускается только если оно уже есть.
+// Ключ возвращается один раз, сохраняется только его хэш.
+func (uc *authUseCase) CreateAPIKey(ctx context.Context, userID string, roles []string, name string, scopes []string, ttlDays int) (*domain.APIKey, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > maxAPIKeyNameLen {
+		return nil, "", domain.ErrAPIKeyInvalidName
+	}
+	if ttlDays == 0 {
+		ttlDays = defaultAPIKeyTTLDays
+	}
+	if ttlDays < 0 || ttlDays > maxAPIKeyTTLDays {
+		return nil, "", domain.ErrAPIKeyInvalidExpiry
+	}
+	scopes, err := normalizeScopes(roles, scopes)
+	if err != nil {
+		return nil, "", err
+	}
+
+	active, err := uc.apiKeys.GetUserAPIKeys(ctx, userID)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(active) >= maxActiveAPIKeys {
+		return nil, "", domain.ErrTooManyAPIKeys
+	}
+
+	prefix, secretHash, token, err := apikey.Generate()
+	if err != nil {
+		return nil, "", err
+	}
+	key, err := uc.apiKeys.CreateAPIKey(ctx, &domain.APIKey{
+		UserID:    userID,
+		Name:      name,
+		Prefix:    prefix,
+		Scopes:    scopes,
+		ExpiresAt: time.Now().Add(time.Duration(ttlDays) * 24 * time.Hour),
+	}, secretHash)
+	if err != nil {
+		return nil, "", err
+	}
+	uc.recordEvent(ctx, domain.AuthEvent{UserID: userID, Type: domain.EventAPIKeyCreated, Identifier: prefix})
+	return key, token, nil
+}
+
+func (uc *authUseCase) ListAPIKeys(ctx context.Context, userID string) ([]*domain.APIKey, error) {
+	return uc.apiKeys.GetUserAPIKeys(ctx, userID)
+}
+
+// RevokeAPIKey отзывает ключ сразу: сервисы проверяют ключ по БД при
+// каждом запросе.
+func (uc *authUseCase) RevokeAPIKey(ctx context.Context, userID, keyID string) error {
+	if err := uc.apiKeys.RevokeAPIKey(ctx, userID, keyID); err != nil {
+		return err
+	}
+	uc.recordEvent(ctx, domain.AuthEvent{UserID: userID, Type: domain.EventAPIKeyRevoked, Identifier: keyID})
+	return nil
+}
+
+// normalizeScopes убирает повторы и проверяет, что каждый scope известен и
+// доступен ролям
+func normalizeScopes(roles, scopes []string) ([]string, error) {
+	if len(scopes) == 0 {
+		return nil, domain.ErrUnknownScope
+	}
+	seen := make(map[string]bool, len(scopes))
+	res := make([]string, 0, len(scopes))
+	for _, s := range scopes {
+		s = strings.TrimSpace(s)
+		if !rbac.IsKnownScope(s) {
+			return nil, domain.ErrUnknownScope
+		}
+		if !rbac.CanGrantScope(roles, s) {
+			return nil, domain.ErrScopeNotAllowed
+		}
+		if !seen[s] {
+			seen[s] = true
+			res = append(res, s)
+		}
+	}
+	return res, nil
+}
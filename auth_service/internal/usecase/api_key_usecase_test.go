@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"apple_backend/auth_service/internal/domain"
+	mocks "apple_backend/auth_service/internal/usecase/mock"
+	"apple_backend/pkg/apikey"
+	"apple_backend/pkg/rbac"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+)
+
+func TestCreateAPIKey_OK(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	keys := mocks.NewMockAPIKeyRepository(ctrl)
+	uc := NewAuthUseCase(nil, nil, nil, nil, testSigner(t), Config{APIKeys: keys})
+
+	keys.EXPECT().GetUserAPIKeys(gomock.Any(), "u1").Return(nil, nil)
+	var storedHash string
+	keys.EXPECT().CreateAPIKey(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, k *domain.APIKey, hash string) (*domain.APIKey, error) {
+			if len(k.Scopes) != 2 || k.Name != "POS" {
+				t.Fatalf("unexpected key %+v", k)
+			}
+			if d := time.Until(k.ExpiresAt); d < 29*24*time.Hour || d > 30*24*time.Hour {
+				t.Fatalf("unexpected expiry %v", k.ExpiresAt)
+			}
+			storedHash = hash
+			k.ID = "k1"
+			return k, nil
+		})
+
+	scopes := []string{rbac.ScopeMenuWrite, rbac.ScopeOrdersRead, rbac.ScopeMenuWrite}
+	key, token, err := uc.CreateAPIKey(context.Background(), "u1", []string{rbac.RoleStoreOwner}, " POS ", scopes, 30)
+	if err != nil || key.ID != "k1" {
+		t.Fatalf("create failed: key=%+v err=%v", key, err)
+	}
+	prefix, secret, ok := apikey.Parse(token)
+	if !ok || prefix != key.Prefix || apikey.HashSecret(secret) != storedHash {
+		t.Fatal("returned token does not match stored prefix and hash")
+	}
+}
+
+func TestCreateAPIKey_Rejects(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	keys := mocks.NewMockAPIKeyRepository(ctrl)
+	uc := NewAuthUseCase(nil, nil, nil, nil, testSigner(t), Config{APIKeys: keys})
+	ctx := context.Background()
+	customer := []string{rbac.RoleCustomer}
+
+	tests := []struct {
+		name   string
+		label  string
+		scopes []string
+		days   int
+		want   error
+	}{
+		{name: "empty name", label: " ", scopes: []string{rbac.ScopeOrdersRead}, want: domain.ErrAPIKeyInvalidName},
+		{name: "too long expiry", label: "k", scopes: []string{rbac.ScopeOrdersRead}, days: 400, want: domain.ErrAPIKeyInvalidExpiry},
+		{name: "no scopes", label: "k", want: domain.ErrUnknownScope},
+		{name: "unknown scope", label: "k", scopes: []string{"payments:write"}, want: domain.ErrUnknownScope},
+		{name: "scope beyond role", label: "k", scopes: []string{rbac.ScopeMenuWrite}, want: domain.ErrScopeNotAllowed},
+		{name: "store orders beyond role", label: "k", scopes: []string{rbac.ScopeOrdersRead}, want: domain.ErrScopeNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := uc.CreateAPIKey(ctx, "u1", customer, tt.label, tt.scopes, tt.days); err != tt.want {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+
+	full := make([]*domain.APIKey, maxActiveAPIKeys)
+	keys.EXPECT().GetUserAPIKeys(gomock.Any(), "u1").Return(full, nil)
+	owner := []string{rbac.RoleStoreOwner}
+	if _, _, err := uc.CreateAPIKey(ctx, "u1", owner, "k", []string{rbac.ScopeOrdersRead}, 0); err != domain.ErrTooManyAPIKeys {
+		t.Fatalf("expected ErrTooManyAPIKeys, got %v", err)
+	}
+}
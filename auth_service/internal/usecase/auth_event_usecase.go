@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"apple_backend/auth_service/internal/delivery/transport"
+	"apple_backend/auth_service/internal/domain"
+	"apple_backend/pkg/logger"
+	"apple_backend/pkg/trace"
+	"context"
+	"log/slog"
+)
+
+const (
+	defaultEventsLimit = 20
+	maxEventsLimit     = 100
+)
+
+// recordEvent дописывает событие в журнал, дополняя его IP, User-Agent и
+// request id из контекста. Сбой журнала не должен ломать вход, поэтому
+// ошибка только логируется.
+func (uc *authUseCase) recordEvent(ctx context.Context, event domain.AuthEvent) {
+	if uc.events == nil {
+		return
+	}
+	event.IP = trace.GetClientIP(ctx)
+	event.UserAgent = truncateUserAgent(trace.GetUserAgent(ctx))
+	event.RequestID = trace.GetRequestID(ctx)
+	if err := uc.events.CreateAuthEvent(ctx, &event); err != nil {
+		logger.FromContext(ctx).ErrorContext(ctx, "usecase recordEvent failed",
+			slog.Any("err", err), slog.String("type", event.Type), slog.String("user_id", event.UserID))
+	}
+}
+
+// loginSession создает сессию после успешного входа и записывает его в журнал
+func (uc *authUseCase) loginSession(ctx context.Context, user *domain.User, mfa bool, method string) (*transport.AuthResult, error) {
+	res, err := uc.startSession(ctx, user, mfa)
+	if err != nil {
+		return nil, err
+	}
+	uc.recordEvent(ctx, domain.AuthEvent{UserID: user.ID, Type: domain.EventLoginSuccess, Method: method})
+	return res, nil
+}
+
+// loginFailedEvent записывает неудачную попытку входа. userID пустой, если
+// аккаунт не найден.
+func (uc *authUseCase) loginFailedEvent(ctx context.Context, userID, method, identifier string) {
+	uc.recordEvent(ctx, domain.AuthEvent{UserID: userID, Type: domain.EventLoginFailed, Method: method, Identifier: identifier})
+}
+
+// ListUserEvents — история событий одного пользователя
+func (uc *authUseCase) ListUserEvents(ctx context.Context, userID, lastID string, limit int) ([]*domain.AuthEvent, error) {
+	return uc.ListAuthEvents(ctx, &domain.AuthEventFilter{UserID: userID, LastID: lastID, Limit: limit})
+}
+
+// ListAuthEvents — выборка журнала с фильтрами для администратора
+func (uc *authUseCase) ListAuthEvents(ctx context.Context, filter *domain.AuthEventFilter) ([]*domain.AuthEvent, error) {
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, domain.ErrRequestParams
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultEventsLimit
+	}
+	if filter.Limit > maxEventsLimit {
+		filter.Limit = maxEventsLimit
+	}
+	if uc.events == nil {
+		return []*domain.AuthEvent{}, nil
+	}
+	return uc.events.ListAuthEvents(ctx, filter)
+}
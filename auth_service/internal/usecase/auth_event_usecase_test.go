@@ -0,0 +1,153 @@
+package usecase
+
+import (
+	"apple_backend/auth_service/internal/domain"
+	mocks "apple_backend/auth_service/internal/usecase/mock"
+	"apple_backend/pkg/rbac"
+	"apple_backend/pkg/trace"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func eventContext() context.Context {
+	ctx := trace.SetClientInfo(context.Background(), "10.0.0.1", "test-agent")
+	return trace.SetRequestID(ctx, "req-1")
+}
+
+func TestLogin_RecordsEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockAuthRepository(ctrl)
+	roles := mocks.NewMockRoleRepository(ctrl)
+	sessions := mocks.NewMockSessionRepository(ctrl)
+	events := mocks.NewMockAuthEventRepository(ctrl)
+	uc := NewAuthUseCase(repo, sessions, nil, nil, testSigner(t), Config{Events: events, Roles: roles})
+
+	hash, _ := bcrypt.GenerateFromPassword([]byte("Str0ng!Pass"), bcrypt.MinCost)
+	user := &domain.User{ID: "u1", Email: "u@ex.com", PasswordHash: string(hash)}
+	repo.EXPECT().GetUserByEmail(gomock.Any(), "u@ex.com").Return(user, nil).Times(2)
+	repo.EXPECT().RehashPassword(gomock.Any(), "u1", gomock.Any(), gomock.Any()).Return(nil)
+	roles.EXPECT().GetUserRoles(gomock.Any(), "u1").Return([]string{rbac.RoleCustomer}, nil).AnyTimes()
+	sessions.EXPECT().
+		CreateSession(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&domain.Session{ID: "s1", UserID: "u1"}, nil)
+
+	var recorded []domain.AuthEvent
+	events.EXPECT().CreateAuthEvent(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, e *domain.AuthEvent) error {
+			recorded = append(recorded, *e)
+			return nil
+		}).Times(2)
+
+	ctx := eventContext()
+	if _, err := uc.Login(ctx, "u@ex.com", "Wr0ng!Pass"); err != domain.ErrInvalidPassword {
+		t.Fatalf("expected ErrInvalidPassword, got %v", err)
+	}
+	if _, err := uc.Login(ctx, "u@ex.com", "Str0ng!Pass"); err != nil {
+		t.Fatalf("login failed: %v", err)
+	}
+
+	want := []domain.AuthEvent{
+		{UserID: "u1", Type: domain.EventLoginFailed, Method: domain.MethodPassword, Identifier: "u@ex.com"},
+		{UserID: "u1", Type: domain.EventLoginSuccess, Method: domain.MethodPassword},
+	}
+	if len(recorded) != len(want) {
+		t.Fatalf("expected %d events, got %+v", len(want), recorded)
+	}
+	for i, e := range recorded {
+		w := want[i]
+		if e.UserID != w.UserID || e.Type != w.Type || e.Method != w.Method || e.Identifier != w.Identifier {
+			t.Errorf("event %d = %+v, want %+v", i, e, w)
+		}
+		if e.IP != "10.0.0.1" || e.UserAgent != "test-agent" || e.RequestID != "req-1" {
+			t.Errorf("event %d lacks request info: %+v", i, e)
+		}
+	}
+}
+
+func TestLogin_UnknownEmailRecordsIdentifier(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockAuthRepository(ctrl)
+	events := mocks.NewMockAuthEventRepository(ctrl)
+	uc := NewAuthUseCase(repo, nil, nil, nil, testSigner(t), Config{Events: events})
+
+	repo.EXPECT().GetUserByEmail(gomock.Any(), "ghost@ex.com").Return(nil, domain.ErrUserNotFound)
+	events.EXPECT().CreateAuthEvent(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, e *domain.AuthEvent) error {
+			if e.UserID != "" || e.Type != domain.EventLoginFailed || e.Identifier != "ghost@ex.com" {
+				t.Fatalf("unexpected event %+v", e)
+			}
+			return nil
+		})
+
+	if _, err := uc.Login(eventContext(), "ghost@ex.com", "Str0ng!Pass"); err != domain.ErrUserNotFound {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+}
+
+func TestLogin_LockoutRecorded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockAuthRepository(ctrl)
+	throttle := mocks.NewMockThrottleRepository(ctrl)
+	events := mocks.NewMockAuthEventRepository(ctrl)
+	uc := NewAuthUseCase(repo, nil, throttle, nil, testSigner(t), Config{Events: events})
+
+	throttle.EXPECT().LockedUntil(gomock.Any(), gomock.Any()).Return(nil, nil)
+	repo.EXPECT().GetUserByEmail(gomock.Any(), "u@ex.com").Return(nil, domain.ErrUserNotFound)
+	throttle.EXPECT().Hit(gomock.Any(), "login:email:u@ex.com", gomock.Any()).Return(2, nil)
+	throttle.EXPECT().Hit(gomock.Any(), "login:pair:10.0.0.1|u@ex.com", gomock.Any()).Return(loginPairRule.lockAfter, nil)
+	throttle.EXPECT().Hit(gomock.Any(), "login:ip:10.0.0.1", gomock.Any()).Return(2, nil)
+	throttle.EXPECT().Lock(gomock.Any(), "login:pair:10.0.0.1|u@ex.com", gomock.Any()).Return(nil)
+
+	var types []string
+	events.EXPECT().CreateAuthEvent(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, e *domain.AuthEvent) error {
+			types = append(types, e.Type)
+			if e.Type == domain.EventLockout && e.Identifier != "login:pair:10.0.0.1|u@ex.com" {
+				t.Fatalf("unexpected lockout identifier %q", e.Identifier)
+			}
+			return nil
+		}).Times(2)
+
+	if _, err := uc.Login(eventContext(), "u@ex.com", "Str0ng!Pass"); err != domain.ErrUserNotFound {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+	if len(types) != 2 || types[0] != domain.EventLockout || types[1] != domain.EventLoginFailed {
+		t.Fatalf("unexpected events %v", types)
+	}
+}
+
+func TestListAuthEvents_Limits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	events := mocks.NewMockAuthEventRepository(ctrl)
+	uc := NewAuthUseCase(nil, nil, nil, nil, testSigner(t), Config{Events: events})
+	ctx := context.Background()
+
+	events.EXPECT().ListAuthEvents(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, f *domain.AuthEventFilter) ([]*domain.AuthEvent, error) {
+			if f.UserID != "u1" || f.Limit != defaultEventsLimit || f.LastID != "e1" {
+				t.Fatalf("unexpected filter %+v", f)
+			}
+			return []*domain.AuthEvent{{ID: "e2"}}, nil
+		})
+	if _, err := uc.ListUserEvents(ctx, "u1", "e1", 0); err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+
+	from := time.Now()
+	to := from.Add(-time.Hour)
+	if _, err := uc.ListAuthEvents(ctx, &domain.AuthEventFilter{From: &from, To: &to}); err != domain.ErrRequestParams {
+		t.Fatalf("expected ErrRequestParams for inverted period, got %v", err)
+	}
+}
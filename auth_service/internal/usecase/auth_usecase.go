@@ -3,34 +3,286 @@ package usecase
 import (
 	"apple_backend/auth_service/internal/delivery/transport"
 	"apple_backend/auth_service/internal/domain"
+	"apple_backend/pkg/logger"
+	"apple_backend/pkg/oidc"
+	"apple_backend/pkg/passcheck"
+	"apple_backend/pkg/passhash"
+	"apple_backend/pkg/trace"
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"log/slog"
 	"regexp"
 	"strings"
 	"time"
 	"unicode"
 
 	"github.com/golang-jwt/jwt/v4"
-	"golang.org/x/crypto/bcrypt"
 )
 
+// AuthRepository — аккаунты: регистрация, вход по паролю, сброс пароля и
+// подтверждение email
 type AuthRepository interface {
 	CreateUser(ctx context.Context, email, hashedPassword string) (*domain.User, error)
 	GetUserByEmail(ctx context.Context, email string) (*domain.User, error)
 	GetUserByID(ctx context.Context, id string) (*domain.User, error)
 	UserExists(ctx context.Context, email string) (bool, error)
+	CreatePasswordReset(ctx context.Context, userID, tokenHash string, expiresAt time.Time) error
+	GetPasswordResetUserID(ctx context.Context, tokenHash string) (string, error)
+	ResetPassword(ctx context.Context, tokenHash, hashedPassword string) (string, error)
+	RehashPassword(ctx context.Context, userID, oldHash, newHash string) error
+	MarkEmailVerified(ctx context.Context, userID, email string) error
+	RestoreAccount(ctx context.Context, userID string) error
+	TouchVerificationSent(ctx context.Context, userID string, interval time.Duration) error
 }
 
+// CredentialsRepository — смена пароля и email вошедшим пользователем
+type CredentialsRepository interface {
+	ChangePassword(ctx context.Context, userID, oldHash, newHash string) error
+	ChangeEmail(ctx context.Context, userID, oldEmail, newEmail string) error
+}
+
+// TwoFactorRepository — секреты TOTP и коды восстановления
+type TwoFactorRepository interface {
+	SetPendingTOTP(ctx context.Context, userID, secret string) error
+	GetTOTP(ctx context.Context, userID string) (*domain.TOTP, error)
+	EnableTOTP(ctx context.Context, userID string, step int64, recoveryHashes []string) error
+	UseTOTPStep(ctx context.Context, userID string, step int64) error
+	UseRecoveryCode(ctx context.Context, userID, codeHash string) error
+	DisableTOTP(ctx context.Context, userID string) error
+	ListPlainTOTPSecrets(ctx context.Context) (map[string]string, error)
+	ReplaceTOTPSecret(ctx context.Context, userID, oldSecret, newSecret string) error
+}
+
+// RoleRepository — роли аккаунтов
+type RoleRepository interface {
+	GetUserRoles(ctx context.Context, userID string) ([]string, error)
+	GrantRole(ctx context.Context, userID, role string) error
+	RevokeRole(ctx context.Context, userID, role string) error
+	GrantRoleByEmail(ctx context.Context, email, role string) (bool, error)
+}
+
+// PhoneRepository — аккаунты с телефоном и одноразовые коды из SMS
+type PhoneRepository interface {
+	GetUserByPhone(ctx context.Context, phone string) (*domain.User, error)
+	CreatePhoneUser(ctx context.Context, phone string) (*domain.User, error)
+	CreatePhoneCode(ctx context.Context, phone, codeHash string, expiresAt time.Time, resendInterval time.Duration) error
+	UsePhoneCodeAttempt(ctx context.Context, phone string, maxAttempts int) (string, error)
+	ConsumePhoneCode(ctx context.Context, phone, codeHash string) error
+	DeletePhoneCode(ctx context.Context, phone string) error
+	DeleteExpiredPhoneCodes(ctx context.Context) error
+}
+
+// ExternalIdentityRepository — аккаунты, привязанные к внешним провайдерам
+type ExternalIdentityRepository interface {
+	GetExternalIdentityUser(ctx context.Context, provider, subject string) (*domain.User, error)
+	LinkExternalIdentity(ctx context.Context, userID, provider, subject, email string) error
+	ClaimUnverifiedAccount(ctx context.Context, userID, email, provider, subject string) error
+	CreateExternalUser(ctx context.Context, email string) (*domain.User, error)
+}
+
+// APIKeyRepository — персональные API-ключи
+type APIKeyRepository interface {
+	CreateAPIKey(ctx context.Context, key *domain.APIKey, secretHash string) (*domain.APIKey, error)
+	GetUserAPIKeys(ctx context.Context, userID string) ([]*domain.APIKey, error)
+	RevokeAPIKey(ctx context.Context, userID, keyID string) error
+}
+
+type SessionRepository interface {
+	CreateSession(ctx context.Context, session *domain.Session, tokenHash string, expiresAt time.Time) (*domain.Session, error)
+	GetSession(ctx context.Context, sessionID string) (*domain.Session, error)
+	GetUserSessions(ctx context.Context, userID string) ([]*domain.Session, error)
+	GetRefreshToken(ctx context.Context, tokenHash string) (*domain.RefreshToken, error)
+	RotateRefreshToken(ctx context.Context, oldTokenID, sessionID, newHash string, expiresAt time.Time) error
+	RevokeSession(ctx context.Context, sessionID string) error
+	RevokeOtherSessions(ctx context.Context, userID, keepSessionID string) error
+	RevokeUserSessions(ctx context.Context, userID string) error
+	MarkSessionMFA(ctx context.Context, sessionID string) error
+}
+
+// ThrottleRepository — общее для всех реплик хранилище счетчиков попыток
+type ThrottleRepository interface {
+	Hit(ctx context.Context, key string, window time.Duration) (int, error)
+	Lock(ctx context.Context, key string, until time.Time) error
+	LockedUntil(ctx context.Context, keys ...string) (*time.Time, error)
+	Reset(ctx context.Context, key string) error
+	DeleteStale(ctx context.Context, olderThan time.Duration) error
+}
+
+// AuthEventRepository — журнал событий авторизации, только дописывается
+type AuthEventRepository interface {
+	CreateAuthEvent(ctx context.Context, event *domain.AuthEvent) error
+	ListAuthEvents(ctx context.Context, filter *domain.AuthEventFilter) ([]*domain.AuthEvent, error)
+}
+
+type Mailer interface {
+	Send(ctx context.Context, to, subject, body string) error
+}
+
+// SMSSender отправляет SMS на номер в международном формате без «+»
+type SMSSender interface {
+	Send(ctx context.Context, phone, text string) error
+}
+
+// PasswordHasher хэширует пароли. Verify сообщает rehash = true, если
+// пароль верный, но хэш получен устаревшим алгоритмом или параметрами.
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	Verify(password, encoded string) (ok, rehash bool, err error)
+}
+
+// BreachChecker проверяет пароль по списку утекших
+type BreachChecker interface {
+	Breached(password string) (bool, error)
+}
+
+// ExternalProvider — OIDC-провайдер для входа через внешний сервис
+type ExternalProvider interface {
+	AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error)
+	Exchange(ctx context.Context, code, verifier string) (*oidc.Token, error)
+	VerifyIDToken(ctx context.Context, rawIDToken, nonce string) (*oidc.Claims, error)
+}
+
+// TokenSigner подписывает JWT закрытым ключом и отдает публичный ключ по kid
+type TokenSigner interface {
+	Sign(claims jwt.Claims) (string, error)
+	Keyfunc(token *jwt.Token) (interface{}, error)
+}
+
+// Config — настраиваемые параметры авторизации
+type Config struct {
+	// AppURL — адрес фронтенда, на который ведут ссылки из писем
+	AppURL string
+	// TwoFactorRoles — роли, которым нельзя войти без второго фактора
+	TwoFactorRoles []string
+	// TOTPIssuer — название сервиса в приложении-аутентификаторе
+	TOTPIssuer string
+	// TOTPKey — ключ шифрования секретов TOTP в базе
+	TOTPKey string
+	// Passwords — схема хэширования паролей; по умолчанию argon2id
+	Passwords PasswordHasher
+	// BreachedPasswords — список утекших паролей; по умолчанию встроенный
+	BreachedPasswords BreachChecker
+	// PasswordMinScore — минимальная оценка стойкости пароля (0..4)
+	PasswordMinScore int
+	// SMS — отправка кодов для входа по телефону; nil — вход выключен
+	SMS SMSSender
+	// ExternalProviders — провайдеры входа через внешние сервисы по имени
+	ExternalProviders map[string]ExternalProvider
+	// Events — журнал событий авторизации; nil — события не записываются
+	Events AuthEventRepository
+
+	// хранилища отдельных функций; сценарию нужно только хранилище своей
+	// функции
+	Credentials CredentialsRepository
+	TwoFactor   TwoFactorRepository
+	Roles       RoleRepository
+	Phones      PhoneRepository
+	External    ExternalIdentityRepository
+	APIKeys     APIKeyRepository
+}
+
+const (
+	accessTokenTTL  = 15 * time.Minute
+	refreshTokenTTL = 30 * 24 * time.Hour
+
+	maxUserAgentLen = 500
+)
+
 type authUseCase struct {
-	repo      AuthRepository
-	jwtSecret []byte
+	repo     AuthRepository
+	sessions SessionRepository
+	throttle ThrottleRepository
+	mailer   Mailer
+	sms      SMSSender
+	signer   TokenSigner
+	appURL   string
+
+	passwords        PasswordHasher
+	breached         BreachChecker
+	passwordMinScore int
+
+	twoFactorRoles map[string]bool
+	totpIssuer     string
+	totpCipher     *totpCipher
+
+	external map[string]ExternalProvider
+
+	events AuthEventRepository
+
+	credentials CredentialsRepository
+	twoFactor   TwoFactorRepository
+	roles       RoleRepository
+	phones      PhoneRepository
+	externalIDs ExternalIdentityRepository
+	apiKeys     APIKeyRepository
 }
 
-func NewAuthUseCase(repo AuthRepository, secretKey string) *authUseCase {
-	return &authUseCase{repo: repo, jwtSecret: []byte(secretKey)}
+// NewAuthUseCase создает сценарии авторизации. throttle может быть nil —
+// тогда ограничение попыток входа и регистрации отключено.
+func NewAuthUseCase(repo AuthRepository, sessions SessionRepository, throttle ThrottleRepository, mailer Mailer, signer TokenSigner, conf Config) *authUseCase {
+	twoFactorRoles := make(map[string]bool, len(conf.TwoFactorRoles))
+	for _, role := range conf.TwoFactorRoles {
+		twoFactorRoles[role] = true
+	}
+	totpIssuer := conf.TOTPIssuer
+	if totpIssuer == "" {
+		totpIssuer = "Delivery Club"
+	}
+	passwords := conf.Passwords
+	if passwords == nil {
+		passwords = passhash.Default()
+	}
+	breached := conf.BreachedPasswords
+	if breached == nil {
+		breached = passcheck.NewBreachList("")
+	}
+	minScore := conf.PasswordMinScore
+	if minScore <= 0 || minScore > passcheck.MaxScore {
+		minScore = passcheck.DefaultMinScore
+	}
+	return &authUseCase{
+		repo:     repo,
+		sessions: sessions,
+		throttle: throttle,
+		mailer:   mailer,
+		sms:      conf.SMS,
+		signer:   signer,
+		appURL:   strings.TrimRight(conf.AppURL, "/"),
+
+		passwords:        passwords,
+		breached:         breached,
+		passwordMinScore: minScore,
+
+		twoFactorRoles: twoFactorRoles,
+		totpIssuer:     totpIssuer,
+		totpCipher:     newTOTPCipher(conf.TOTPKey),
+
+		external: conf.ExternalProviders,
+
+		events: conf.Events,
+
+		credentials: conf.Credentials,
+		twoFactor:   conf.TwoFactor,
+		roles:       conf.Roles,
+		phones:      conf.Phones,
+		externalIDs: conf.External,
+		apiKeys:     conf.APIKeys,
+	}
 }
 
 func (uc *authUseCase) Register(ctx context.Context, email, password string) (*transport.AuthResult, error) {
-	if err := uc.validateRegistrationInput(email, password); err != nil {
+	if err := uc.validateRegistrationInput(ctx, email, password); err != nil {
+		return nil, err
+	}
+	keys := signupThrottleKeys(ctx, email)
+	if err := uc.checkThrottle(ctx, keys); err != nil {
+		return nil, err
+	}
+	if err := uc.registerAttempts(ctx, keys); err != nil {
 		return nil, err
 	}
 	exists, err := uc.repo.UserExists(ctx, email)
@@ -48,74 +300,192 @@ func (uc *authUseCase) Register(ctx context.Context, email, password string) (*t
 	if err != nil {
 		return nil, err
 	}
-	token, err := uc.generateToken(user.ID, user.Email)
-	if err != nil {
-		return nil, err
+	uc.recordEvent(ctx, domain.AuthEvent{UserID: user.ID, Type: domain.EventSignup, Method: domain.MethodPassword})
+	// письмо не должно ломать регистрацию: его можно запросить повторно
+	if err := uc.sendVerificationEmail(ctx, user); err != nil {
+		logger.FromContext(ctx).WarnContext(ctx, "usecase Register verification email not sent",
+			slog.Any("err", err), slog.String("user_id", user.ID))
 	}
-	return &transport.AuthResult{
-		UserID:  user.ID,
-		Email:   user.Email,
-		Token:   token,
-		Expires: time.Now().Add(24 * time.Hour),
-	}, nil
+	return uc.startSession(ctx, user, false)
 }
 
 func (uc *authUseCase) Login(ctx context.Context, email, password string) (*transport.AuthResult, error) {
+	return uc.passwordLogin(ctx, email, password, false)
+}
+
+// RestoreAccount входит по паролю в удаленный, но еще не обезличенный
+// аккаунт и отменяет удаление. Если включена 2FA, сессию выдаст только
+// второй шаг входа.
+func (uc *authUseCase) RestoreAccount(ctx context.Context, email, password string) (*transport.AuthResult, error) {
+	return uc.passwordLogin(ctx, email, password, true)
+}
+
+func (uc *authUseCase) passwordLogin(ctx context.Context, email, password string, restore bool) (*transport.AuthResult, error) {
 	if err := uc.validateLoginInput(email, password); err != nil {
 		return nil, err
 	}
+	keys := loginThrottleKeys(ctx, email)
+	if err := uc.checkThrottle(ctx, keys); err != nil {
+		return nil, err
+	}
 	user, err := uc.repo.GetUserByEmail(ctx, strings.TrimSpace(email))
 	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			uc.loginFailed(ctx, keys)
+			uc.loginFailedEvent(ctx, "", domain.MethodPassword, strings.TrimSpace(email))
+		}
 		return nil, domain.ErrUserNotFound
 	}
-	if !uc.checkPasswordHash(password, user.PasswordHash) {
+	if !uc.checkPassword(ctx, user, password) {
+		uc.loginFailed(ctx, keys)
+		uc.loginFailedEvent(ctx, user.ID, domain.MethodPassword, user.Email)
 		return nil, domain.ErrInvalidPassword
 	}
-	token, err := uc.generateToken(user.ID, user.Email)
+	uc.loginSucceeded(ctx, keys)
+
+	if restore && user.Deleted() {
+		if err := uc.repo.RestoreAccount(ctx, user.ID); err != nil {
+			return nil, err
+		}
+		user.DeletedAt = nil
+		uc.recordEvent(ctx, domain.AuthEvent{UserID: user.ID, Type: domain.EventAccountRestored, Method: domain.MethodPassword})
+	}
+
+	challenge, err := uc.loginChallenge(ctx, user)
 	if err != nil {
 		return nil, err
 	}
-	return &transport.AuthResult{
-		UserID:  user.ID,
-		Email:   user.Email,
-		Token:   token,
-		Expires: time.Now().Add(24 * time.Hour),
-	}, nil
+	if challenge != nil {
+		return challenge, nil
+	}
+	return uc.loginSession(ctx, user, false, domain.MethodPassword)
 }
 
-func (uc *authUseCase) RefreshToken(ctx context.Context, tokenString string) (*transport.AuthResult, error) {
-	claims, err := uc.VerifyToken(ctx, tokenString)
+// RefreshToken обменивает refresh-токен на новую пару токенов. Каждый
+// refresh-токен одноразовый: повторное предъявление уже использованного
+// токена считается кражей и отзывает всю сессию.
+func (uc *authUseCase) RefreshToken(ctx context.Context, refreshToken string) (*transport.AuthResult, error) {
+	if refreshToken == "" {
+		return nil, domain.ErrInvalidToken
+	}
+	rt, err := uc.sessions.GetRefreshToken(ctx, hashToken(refreshToken))
 	if err != nil {
+		if errors.Is(err, domain.ErrSessionNotFound) {
+			return nil, domain.ErrInvalidToken
+		}
 		return nil, err
 	}
-	user, err := uc.repo.GetUserByID(ctx, claims.UserID)
+	if rt.SessionRevokedAt != nil {
+		return nil, domain.ErrInvalidToken
+	}
+	if rt.UsedAt != nil {
+		if err := uc.sessions.RevokeSession(ctx, rt.SessionID); err != nil {
+			return nil, err
+		}
+		uc.recordEvent(ctx, domain.AuthEvent{UserID: rt.UserID, Type: domain.EventTokenReused})
+		return nil, domain.ErrTokenReused
+	}
+	if time.Now().After(rt.ExpiresAt) {
+		return nil, domain.ErrInvalidToken
+	}
+
+	user, err := uc.repo.GetUserByID(ctx, rt.UserID)
 	if err != nil {
 		return nil, domain.ErrUserNotFound
 	}
-	newTok, err := uc.generateToken(user.ID, user.Email)
+	if user.Deleted() {
+		return nil, domain.ErrAccountDeleted
+	}
+
+	newRefresh, err := generateSecureToken()
 	if err != nil {
 		return nil, err
 	}
-	return &transport.AuthResult{
-		UserID: user.ID, Email: user.Email,
-		Token: newTok, Expires: time.Now().Add(24 * time.Hour),
-	}, nil
+	refreshExpires := time.Now().Add(refreshTokenTTL)
+	err = uc.sessions.RotateRefreshToken(ctx, rt.ID, rt.SessionID, hashToken(newRefresh), refreshExpires)
+	if errors.Is(err, domain.ErrTokenReused) {
+		if err := uc.sessions.RevokeSession(ctx, rt.SessionID); err != nil {
+			return nil, err
+		}
+		uc.recordEvent(ctx, domain.AuthEvent{UserID: rt.UserID, Type: domain.EventTokenReused})
+		return nil, domain.ErrTokenReused
+	}
+	if err != nil {
+		return nil, err
+	}
+	uc.recordEvent(ctx, domain.AuthEvent{UserID: rt.UserID, Type: domain.EventRefresh})
+
+	return uc.buildAuthResult(ctx, user, rt.SessionID, rt.SessionMFAVerified, newRefresh, refreshExpires)
+}
+
+// Logout отзывает сессию, которой принадлежит refresh-токен.
+func (uc *authUseCase) Logout(ctx context.Context, refreshToken string) error {
+	if refreshToken == "" {
+		return nil
+	}
+	rt, err := uc.sessions.GetRefreshToken(ctx, hashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, domain.ErrSessionNotFound) {
+			return nil
+		}
+		return err
+	}
+	if err := uc.sessions.RevokeSession(ctx, rt.SessionID); err != nil {
+		return err
+	}
+	uc.recordEvent(ctx, domain.AuthEvent{UserID: rt.UserID, Type: domain.EventLogout})
+	return nil
 }
 
 func (uc *authUseCase) VerifyToken(ctx context.Context, tokenString string) (*transport.Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &transport.Claims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+	token, err := jwt.ParseWithClaims(tokenString, &transport.Claims{}, uc.signer.Keyfunc)
+	if err != nil {
+		return nil, err
+	}
+	claims, ok := token.Claims.(*transport.Claims)
+	if !ok || !token.Valid || claims.SessionID == "" {
+		return nil, domain.ErrInvalidToken
+	}
+	session, err := uc.sessions.GetSession(ctx, claims.SessionID)
+	if err != nil {
+		if errors.Is(err, domain.ErrSessionNotFound) {
 			return nil, domain.ErrInvalidToken
 		}
-		return uc.jwtSecret, nil
-	})
-	if err != nil {
 		return nil, err
 	}
-	if claims, ok := token.Claims.(*transport.Claims); ok && token.Valid {
-		return claims, nil
+	if session.RevokedAt != nil || session.UserID != claims.UserID {
+		return nil, domain.ErrInvalidToken
 	}
-	return nil, domain.ErrInvalidToken
+	return claims, nil
+}
+
+func (uc *authUseCase) ListSessions(ctx context.Context, userID string) ([]*domain.Session, error) {
+	return uc.sessions.GetUserSessions(ctx, userID)
+}
+
+// RevokeSession завершает одну из сессий пользователя. Чужие сессии
+// неотличимы от несуществующих.
+func (uc *authUseCase) RevokeSession(ctx context.Context, userID, sessionID string) error {
+	session, err := uc.sessions.GetSession(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	if session.UserID != userID {
+		return domain.ErrSessionNotFound
+	}
+	if err := uc.sessions.RevokeSession(ctx, sessionID); err != nil {
+		return err
+	}
+	uc.recordEvent(ctx, domain.AuthEvent{UserID: userID, Type: domain.EventSessionRevoked})
+	return nil
+}
+
+func (uc *authUseCase) RevokeOtherSessions(ctx context.Context, userID, currentSessionID string) error {
+	if err := uc.sessions.RevokeOtherSessions(ctx, userID, currentSessionID); err != nil {
+		return err
+	}
+	uc.recordEvent(ctx, domain.AuthEvent{UserID: userID, Type: domain.EventSessionRevoked})
+	return nil
 }
 
 /* validation + helpers */
@@ -128,11 +498,11 @@ func (uc *authUseCase) GetUserByID(ctx context.Context, userID string) (*domain.
 	return uc.repo.GetUserByID(ctx, userID)
 }
 
-func (uc *authUseCase) validateRegistrationInput(email, password string) error {
+func (uc *authUseCase) validateRegistrationInput(ctx context.Context, email, password string) error {
 	if err := uc.validateEmailFormat(email); err != nil {
 		return err
 	}
-	return uc.validatePasswordSecurity(email, password)
+	return uc.validatePasswordSecurity(ctx, email, password)
 }
 
 func (uc *authUseCase) validateLoginInput(email, password string) error {
@@ -159,10 +529,37 @@ func (uc *authUseCase) validateEmailFormat(email string) error {
 	return nil
 }
 
-func (uc *authUseCase) validatePasswordSecurity(email, password string) error {
+// accountEmailRe повторяет ограничение email_format таблицы account
+var accountEmailRe = regexp.MustCompile(`^[a-zA-Z0-9._-]+@[a-zA-Z0-9._-]+\.[a-zA-Z0-9_-]+$`)
+
+// maxAccountEmailLen — ограничение длины account.email
+const maxAccountEmailLen = 100
+
+// validateAccountEmail проверяет адрес, который будет записан в account:
+// кроме общего формата он должен пройти ограничения таблицы, иначе
+// подтверждение по ссылке из письма упадет на записи.
+func (uc *authUseCase) validateAccountEmail(email string) error {
+	if err := uc.validateEmailFormat(email); err != nil {
+		return err
+	}
+	email = strings.TrimSpace(email)
+	if len(email) > maxAccountEmailLen || !accountEmailRe.MatchString(email) {
+		return domain.ErrInvalidEmail
+	}
+	return nil
+}
+
+// validatePasswordSecurity отклоняет пароль с *domain.WeakPasswordError:
+// слишком короткий, без нужных классов символов, из утечек или с низкой
+// оценкой стойкости.
+func (uc *authUseCase) validatePasswordSecurity(ctx context.Context, email, password string) error {
 	if len(password) < 8 {
-		return domain.ErrWeakPassword
+		return &domain.WeakPasswordError{Score: 0, Reason: passcheck.ReasonTooShort}
+	}
+	if strings.EqualFold(email, password) {
+		return &domain.WeakPasswordError{Score: 0, Reason: passcheck.ReasonContainsEmail}
 	}
+	strength := passcheck.Estimate(password, passcheck.EmailInputs(email)...)
 	var up, low, num, spec bool
 	for _, r := range password {
 		switch {
@@ -177,30 +574,139 @@ func (uc *authUseCase) validatePasswordSecurity(email, password string) error {
 		}
 	}
 	if !up || !low || !num || !spec {
-		return domain.ErrWeakPassword
+		return &domain.WeakPasswordError{Score: strength.Score, Reason: passcheck.ReasonCharacterClasses}
 	}
-	if strings.EqualFold(email, password) {
-		return domain.ErrWeakPassword
+
+	breached, err := uc.breached.Breached(password)
+	if err != nil {
+		// список утечек — дополнительная проверка, его сбой не мешает сменить пароль
+		logger.FromContext(ctx).WarnContext(ctx, "usecase validatePasswordSecurity breach check failed", slog.Any("err", err))
+	}
+	if breached {
+		return &domain.WeakPasswordError{Score: 0, Reason: passcheck.ReasonBreached}
+	}
+	if strength.Score < uc.passwordMinScore {
+		return &domain.WeakPasswordError{Score: strength.Score, Reason: strength.Reason}
 	}
 	return nil
 }
 
 func (uc *authUseCase) hashPassword(password string) (string, error) {
-	b, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-	return string(b), err
+	return uc.passwords.Hash(password)
 }
 
-func (uc *authUseCase) checkPasswordHash(password, hash string) bool {
-	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+// checkPassword проверяет пароль и, если хэш устарел, тут же пересчитывает
+// его текущим алгоритмом. Ошибка пересчета вход не ломает.
+func (uc *authUseCase) checkPassword(ctx context.Context, user *domain.User, password string) bool {
+	log := logger.FromContext(ctx)
+	if user.PasswordHash == "" {
+		// аккаунт создан входом по телефону и пароля не имеет
+		return false
+	}
+	ok, rehash, err := uc.passwords.Verify(password, user.PasswordHash)
+	if err != nil {
+		log.ErrorContext(ctx, "usecase checkPassword bad stored hash",
+			slog.Any("err", err), slog.String("user_id", user.ID))
+		return false
+	}
+	if !ok || !rehash {
+		return ok
+	}
+
+	hashed, err := uc.hashPassword(password)
+	if err == nil {
+		err = uc.repo.RehashPassword(ctx, user.ID, user.PasswordHash, hashed)
+	}
+	if err != nil {
+		log.WarnContext(ctx, "usecase checkPassword rehash failed",
+			slog.Any("err", err), slog.String("user_id", user.ID))
+		return true
+	}
+	user.PasswordHash = hashed
+	return true
 }
 
-func (uc *authUseCase) generateToken(userID, email string) (string, error) {
+// startSession — единственное место, где создаются сессии, поэтому здесь же
+// отсекаются удаленные аккаунты при любом способе входа
+func (uc *authUseCase) startSession(ctx context.Context, user *domain.User, mfa bool) (*transport.AuthResult, error) {
+	if user.Deleted() {
+		return nil, domain.ErrAccountDeleted
+	}
+	refresh, err := generateSecureToken()
+	if err != nil {
+		return nil, err
+	}
+	refreshExpires := time.Now().Add(refreshTokenTTL)
+	s := &domain.Session{
+		UserID:    user.ID,
+		UserAgent: truncateUserAgent(trace.GetUserAgent(ctx)),
+		IP:        trace.GetClientIP(ctx),
+	}
+	if mfa {
+		now := time.Now()
+		s.MFAVerifiedAt = &now
+	}
+	session, err := uc.sessions.CreateSession(ctx, s, hashToken(refresh), refreshExpires)
+	if err != nil {
+		return nil, err
+	}
+	return uc.buildAuthResult(ctx, user, session.ID, mfa, refresh, refreshExpires)
+}
+
+func (uc *authUseCase) buildAuthResult(ctx context.Context, user *domain.User, sessionID string, mfa bool, refresh string, refreshExpires time.Time) (*transport.AuthResult, error) {
+	roles, err := uc.effectiveRoles(ctx, user.ID, mfa)
+	if err != nil {
+		return nil, err
+	}
+	expires := time.Now().Add(accessTokenTTL)
+	token, err := uc.generateToken(user.ID, user.Email, sessionID, roles, mfa, expires)
+	if err != nil {
+		return nil, err
+	}
+	return &transport.AuthResult{
+		UserID:         user.ID,
+		Email:          user.Email,
+		EmailVerified:  user.EmailVerified(),
+		Roles:          roles,
+		Token:          token,
+		Expires:        expires,
+		RefreshToken:   refresh,
+		RefreshExpires: refreshExpires,
+		SessionID:      sessionID,
+	}, nil
+}
+
+func (uc *authUseCase) generateToken(userID, email, sessionID string, roles []string, mfa bool, expires time.Time) (string, error) {
 	claims := jwt.MapClaims{
 		"user_id": userID,
 		"email":   email,
-		"exp":     time.Now().Add(24 * time.Hour).Unix(),
+		"sid":     sessionID,
+		"roles":   roles,
+		"exp":     expires.Unix(),
 		"iat":     time.Now().Unix(),
 	}
-	t := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return t.SignedString(uc.jwtSecret)
+	if mfa {
+		claims["mfa"] = true
+	}
+	return uc.signer.Sign(claims)
+}
+
+func truncateUserAgent(userAgent string) string {
+	if r := []rune(userAgent); len(r) > maxUserAgentLen {
+		return string(r[:maxUserAgentLen])
+	}
+	return userAgent
+}
+
+func generateSecureToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
@@ -3,7 +3,12 @@ package usecase
 import (
 	"apple_backend/auth_service/internal/domain"
 	mocks "apple_backend/auth_service/internal/usecase/mock"
+	"apple_backend/pkg/jwtkeys"
+	"apple_backend/pkg/rbac"
+	"apple_backend/pkg/trace"
 	"context"
+	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -11,12 +16,25 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
+func testSigner(t *testing.T) TokenSigner {
+	t.Helper()
+	ks, err := jwtkeys.GenerateKeySet()
+	if err != nil {
+		t.Fatalf("generate keys: %v", err)
+	}
+	return ks
+}
+
 func TestRegister_OK(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	repo := mocks.NewMockAuthRepository(ctrl)
-	uc := NewAuthUseCase(repo, "test-secret")
+	roles := mocks.NewMockRoleRepository(ctrl)
+	sessions := mocks.NewMockSessionRepository(ctrl)
+	mailer := mocks.NewMockMailer(ctrl)
+	uc := NewAuthUseCase(repo, sessions, nil, mailer, testSigner(t), Config{Roles: roles})
+	roles.EXPECT().GetUserRoles(gomock.Any(), "u1").Return([]string{rbac.RoleCustomer}, nil)
 
 	repo.EXPECT().UserExists(gomock.Any(), "u@ex.com").Return(false, nil)
 	repo.EXPECT().
@@ -24,9 +42,14 @@ func TestRegister_OK(t *testing.T) {
 		DoAndReturn(func(_ context.Context, email, hash string) (*domain.User, error) {
 			return &domain.User{ID: "u1", Email: email, PasswordHash: hash, CreatedAt: time.Now(), UpdatedAt: time.Now()}, nil
 		})
+	repo.EXPECT().TouchVerificationSent(gomock.Any(), "u1", gomock.Any()).Return(nil)
+	mailer.EXPECT().Send(gomock.Any(), "u@ex.com", gomock.Any(), gomock.Any()).Return(nil)
+	sessions.EXPECT().
+		CreateSession(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&domain.Session{ID: "s1", UserID: "u1"}, nil)
 
 	res, err := uc.Register(context.Background(), "u@ex.com", "Str0ng!Pass")
-	if err != nil || res.Token == "" || res.UserID == "" {
+	if err != nil || res.Token == "" || res.UserID == "" || res.RefreshToken == "" {
 		t.Fatalf("register failed: err=%v res=%+v", err, res)
 	}
 }
@@ -36,17 +59,65 @@ func TestLogin_OK(t *testing.T) {
 	defer ctrl.Finish()
 
 	repo := mocks.NewMockAuthRepository(ctrl)
-	uc := NewAuthUseCase(repo, "test-secret")
+	roles := mocks.NewMockRoleRepository(ctrl)
+	sessions := mocks.NewMockSessionRepository(ctrl)
+	uc := NewAuthUseCase(repo, sessions, nil, nil, testSigner(t), Config{Roles: roles})
+	roles.EXPECT().GetUserRoles(gomock.Any(), "u1").Return([]string{rbac.RoleCustomer}, nil)
 
 	hash, _ := bcrypt.GenerateFromPassword([]byte("Str0ng!Pass"), bcrypt.DefaultCost)
 	user := &domain.User{ID: "u1", Email: "u@ex.com", PasswordHash: string(hash)}
 
 	repo.EXPECT().GetUserByEmail(gomock.Any(), "u@ex.com").Return(user, nil)
+	var rehashed string
+	repo.EXPECT().RehashPassword(gomock.Any(), "u1", string(hash), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _, _, newHash string) error {
+			rehashed = newHash
+			return nil
+		})
+	sessions.EXPECT().
+		CreateSession(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&domain.Session{ID: "s1", UserID: "u1"}, nil)
 
 	res, err := uc.Login(context.Background(), "u@ex.com", "Str0ng!Pass")
 	if err != nil || res.Token == "" {
 		t.Fatalf("login failed: err=%v", err)
 	}
+	if !strings.HasPrefix(rehashed, "$argon2id$") {
+		t.Fatalf("expected legacy bcrypt hash upgraded to argon2id, got %q", rehashed)
+	}
+
+	sessions.EXPECT().GetSession(gomock.Any(), "s1").Return(&domain.Session{ID: "s1", UserID: "u1"}, nil)
+	claims, err := uc.VerifyToken(context.Background(), res.Token)
+	if err != nil || claims.SessionID != "s1" {
+		t.Fatalf("expected sid in token: err=%v claims=%+v", err, claims)
+	}
+	if len(claims.Roles) != 1 || claims.Roles[0] != rbac.RoleCustomer {
+		t.Fatalf("expected roles in token, got %v", claims.Roles)
+	}
+}
+
+func TestLogin_CurrentHashNotRehashed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockAuthRepository(ctrl)
+	roles := mocks.NewMockRoleRepository(ctrl)
+	sessions := mocks.NewMockSessionRepository(ctrl)
+	uc := NewAuthUseCase(repo, sessions, nil, nil, testSigner(t), Config{Roles: roles})
+	roles.EXPECT().GetUserRoles(gomock.Any(), "u1").Return([]string{rbac.RoleCustomer}, nil)
+
+	hash, err := uc.hashPassword("Str0ng!Pass")
+	if err != nil {
+		t.Fatalf("hash: %v", err)
+	}
+	repo.EXPECT().GetUserByEmail(gomock.Any(), "u@ex.com").Return(&domain.User{ID: "u1", Email: "u@ex.com", PasswordHash: hash}, nil)
+	sessions.EXPECT().
+		CreateSession(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&domain.Session{ID: "s1", UserID: "u1"}, nil)
+
+	if _, err := uc.Login(context.Background(), "u@ex.com", "Str0ng!Pass"); err != nil {
+		t.Fatalf("login failed: err=%v", err)
+	}
 }
 
 func TestRefresh_InvalidToken(t *testing.T) {
@@ -54,9 +125,366 @@ func TestRefresh_InvalidToken(t *testing.T) {
 	defer ctrl.Finish()
 
 	repo := mocks.NewMockAuthRepository(ctrl)
-	uc := NewAuthUseCase(repo, "test-secret")
+	sessions := mocks.NewMockSessionRepository(ctrl)
+	uc := NewAuthUseCase(repo, sessions, nil, nil, testSigner(t), Config{})
+
+	sessions.EXPECT().GetRefreshToken(gomock.Any(), gomock.Any()).Return(nil, domain.ErrSessionNotFound)
+
+	if _, err := uc.RefreshToken(context.Background(), "bad.token"); err != domain.ErrInvalidToken {
+		t.Fatalf("expected ErrInvalidToken, got %v", err)
+	}
+}
+
+func TestRefresh_Rotates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockAuthRepository(ctrl)
+	roles := mocks.NewMockRoleRepository(ctrl)
+	sessions := mocks.NewMockSessionRepository(ctrl)
+	uc := NewAuthUseCase(repo, sessions, nil, nil, testSigner(t), Config{Roles: roles})
+	roles.EXPECT().GetUserRoles(gomock.Any(), "u1").Return([]string{rbac.RoleCustomer}, nil)
+
+	sessions.EXPECT().
+		GetRefreshToken(gomock.Any(), hashToken("old")).
+		Return(&domain.RefreshToken{ID: "t1", SessionID: "s1", UserID: "u1", ExpiresAt: time.Now().Add(time.Hour)}, nil)
+	repo.EXPECT().GetUserByID(gomock.Any(), "u1").Return(&domain.User{ID: "u1", Email: "u@ex.com"}, nil)
+	sessions.EXPECT().
+		RotateRefreshToken(gomock.Any(), "t1", "s1", gomock.Any(), gomock.Any()).
+		Return(nil)
+
+	res, err := uc.RefreshToken(context.Background(), "old")
+	if err != nil {
+		t.Fatalf("refresh failed: %v", err)
+	}
+	if res.RefreshToken == "" || res.RefreshToken == "old" {
+		t.Fatalf("expected rotated refresh token, got %q", res.RefreshToken)
+	}
+}
+
+func TestRefresh_ReuseRevokesSession(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockAuthRepository(ctrl)
+	sessions := mocks.NewMockSessionRepository(ctrl)
+	uc := NewAuthUseCase(repo, sessions, nil, nil, testSigner(t), Config{})
+
+	usedAt := time.Now().Add(-time.Minute)
+	sessions.EXPECT().
+		GetRefreshToken(gomock.Any(), gomock.Any()).
+		Return(&domain.RefreshToken{ID: "t1", SessionID: "s1", UserID: "u1", ExpiresAt: time.Now().Add(time.Hour), UsedAt: &usedAt}, nil)
+	sessions.EXPECT().RevokeSession(gomock.Any(), "s1").Return(nil)
+
+	if _, err := uc.RefreshToken(context.Background(), "stolen"); err != domain.ErrTokenReused {
+		t.Fatalf("expected ErrTokenReused, got %v", err)
+	}
+}
+
+func TestLogout_RevokesSession(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockAuthRepository(ctrl)
+	sessions := mocks.NewMockSessionRepository(ctrl)
+	uc := NewAuthUseCase(repo, sessions, nil, nil, testSigner(t), Config{})
+
+	sessions.EXPECT().
+		GetRefreshToken(gomock.Any(), hashToken("rt")).
+		Return(&domain.RefreshToken{ID: "t1", SessionID: "s1", UserID: "u1"}, nil)
+	sessions.EXPECT().RevokeSession(gomock.Any(), "s1").Return(nil)
+
+	if err := uc.Logout(context.Background(), "rt"); err != nil {
+		t.Fatalf("logout failed: %v", err)
+	}
+}
+
+func TestVerifyToken_RevokedSession(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockAuthRepository(ctrl)
+	sessions := mocks.NewMockSessionRepository(ctrl)
+	uc := NewAuthUseCase(repo, sessions, nil, nil, testSigner(t), Config{})
+
+	token, err := uc.generateToken("u1", "u@ex.com", "s1", nil, false, time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatalf("generate token: %v", err)
+	}
+	revokedAt := time.Now()
+	sessions.EXPECT().GetSession(gomock.Any(), "s1").Return(&domain.Session{ID: "s1", UserID: "u1", RevokedAt: &revokedAt}, nil)
+
+	if _, err := uc.VerifyToken(context.Background(), token); err != domain.ErrInvalidToken {
+		t.Fatalf("expected ErrInvalidToken, got %v", err)
+	}
+}
+
+func TestRevokeSession_ForeignSession(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockAuthRepository(ctrl)
+	sessions := mocks.NewMockSessionRepository(ctrl)
+	uc := NewAuthUseCase(repo, sessions, nil, nil, testSigner(t), Config{})
+
+	sessions.EXPECT().GetSession(gomock.Any(), "s2").Return(&domain.Session{ID: "s2", UserID: "other"}, nil)
+
+	if err := uc.RevokeSession(context.Background(), "u1", "s2"); err != domain.ErrSessionNotFound {
+		t.Fatalf("expected ErrSessionNotFound, got %v", err)
+	}
+}
+
+func TestForgotPassword_UnknownEmailSendsNothing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockAuthRepository(ctrl)
+	sessions := mocks.NewMockSessionRepository(ctrl)
+	mailer := mocks.NewMockMailer(ctrl)
+	uc := NewAuthUseCase(repo, sessions, nil, mailer, testSigner(t), Config{})
+
+	repo.EXPECT().GetUserByEmail(gomock.Any(), "nobody@ex.com").Return(nil, domain.ErrUserNotFound)
+
+	if err := uc.ForgotPassword(context.Background(), "nobody@ex.com"); err != nil {
+		t.Fatalf("expected nil for unknown email, got %v", err)
+	}
+}
+
+func TestForgotPassword_SendsLink(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockAuthRepository(ctrl)
+	sessions := mocks.NewMockSessionRepository(ctrl)
+	mailer := mocks.NewMockMailer(ctrl)
+	uc := NewAuthUseCase(repo, sessions, nil, mailer, testSigner(t), Config{AppURL: "http://front/"})
+
+	repo.EXPECT().GetUserByEmail(gomock.Any(), "u@ex.com").Return(&domain.User{ID: "u1", Email: "u@ex.com"}, nil)
+	repo.EXPECT().CreatePasswordReset(gomock.Any(), "u1", gomock.Any(), gomock.Any()).Return(nil)
+	mailer.EXPECT().
+		Send(gomock.Any(), "u@ex.com", gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _, _, body string) error {
+			if !strings.Contains(body, "http://front/password/reset?token=") {
+				t.Fatalf("reset link not found in body: %s", body)
+			}
+			return nil
+		})
+
+	if err := uc.ForgotPassword(context.Background(), "u@ex.com"); err != nil {
+		t.Fatalf("forgot password failed: %v", err)
+	}
+}
+
+func TestForgotPassword_SendFailureHidden(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockAuthRepository(ctrl)
+	sessions := mocks.NewMockSessionRepository(ctrl)
+	mailer := mocks.NewMockMailer(ctrl)
+	uc := NewAuthUseCase(repo, sessions, nil, mailer, testSigner(t), Config{})
+
+	repo.EXPECT().GetUserByEmail(gomock.Any(), "u@ex.com").Return(&domain.User{ID: "u1", Email: "u@ex.com"}, nil)
+	repo.EXPECT().CreatePasswordReset(gomock.Any(), "u1", gomock.Any(), gomock.Any()).Return(nil)
+	mailer.EXPECT().Send(gomock.Any(), "u@ex.com", gomock.Any(), gomock.Any()).Return(errors.New("smtp down"))
+
+	if err := uc.ForgotPassword(context.Background(), "u@ex.com"); err != nil {
+		t.Fatalf("expected nil on send failure, got %v", err)
+	}
+}
+
+func TestForgotPassword_Throttled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockAuthRepository(ctrl)
+	sessions := mocks.NewMockSessionRepository(ctrl)
+	throttle := mocks.NewMockThrottleRepository(ctrl)
+	uc := NewAuthUseCase(repo, sessions, throttle, nil, testSigner(t), Config{})
+
+	ctx := trace.SetClientInfo(context.Background(), "10.0.0.1", "test")
+	until := time.Now().Add(time.Minute)
+	throttle.EXPECT().LockedUntil(gomock.Any(), "forgot:email:u@ex.com", "forgot:ip:10.0.0.1").Return(&until, nil)
+
+	err := uc.ForgotPassword(ctx, "U@ex.com")
+	var throttled *domain.ThrottledError
+	if !errors.As(err, &throttled) {
+		t.Fatalf("expected ThrottledError, got %v", err)
+	}
+}
+
+func TestForgotPassword_UnknownEmailCounted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockAuthRepository(ctrl)
+	sessions := mocks.NewMockSessionRepository(ctrl)
+	throttle := mocks.NewMockThrottleRepository(ctrl)
+	uc := NewAuthUseCase(repo, sessions, throttle, nil, testSigner(t), Config{})
+
+	throttle.EXPECT().LockedUntil(gomock.Any(), "forgot:email:nobody@ex.com").Return(nil, nil)
+	throttle.EXPECT().Hit(gomock.Any(), "forgot:email:nobody@ex.com", forgotEmailRule.window).Return(1, nil)
+	repo.EXPECT().GetUserByEmail(gomock.Any(), "nobody@ex.com").Return(nil, domain.ErrUserNotFound)
+
+	if err := uc.ForgotPassword(context.Background(), "nobody@ex.com"); err != nil {
+		t.Fatalf("expected nil for unknown email, got %v", err)
+	}
+}
+
+func TestResetPassword_WeakPasswordKeepsToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockAuthRepository(ctrl)
+	sessions := mocks.NewMockSessionRepository(ctrl)
+	uc := NewAuthUseCase(repo, sessions, nil, nil, testSigner(t), Config{})
+
+	repo.EXPECT().GetPasswordResetUserID(gomock.Any(), hashToken("tok")).Return("u1", nil)
+	repo.EXPECT().GetUserByID(gomock.Any(), "u1").Return(&domain.User{ID: "u1", Email: "u@ex.com"}, nil)
+
+	if err := uc.ResetPassword(context.Background(), "tok", "weak"); !errors.Is(err, domain.ErrWeakPassword) {
+		t.Fatalf("expected ErrWeakPassword, got %v", err)
+	}
+}
+
+func TestResetPassword_RevokesSessions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockAuthRepository(ctrl)
+	sessions := mocks.NewMockSessionRepository(ctrl)
+	uc := NewAuthUseCase(repo, sessions, nil, nil, testSigner(t), Config{})
+
+	repo.EXPECT().GetPasswordResetUserID(gomock.Any(), hashToken("tok")).Return("u1", nil)
+	repo.EXPECT().GetUserByID(gomock.Any(), "u1").Return(&domain.User{ID: "u1", Email: "u@ex.com"}, nil)
+	repo.EXPECT().ResetPassword(gomock.Any(), hashToken("tok"), gomock.Any()).Return("u1", nil)
+	sessions.EXPECT().RevokeUserSessions(gomock.Any(), "u1").Return(nil)
+
+	if err := uc.ResetPassword(context.Background(), "tok", "N3w!Harb0r-Lantern"); err != nil {
+		t.Fatalf("reset password failed: %v", err)
+	}
+}
+
+func TestVerifyEmail_OK(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockAuthRepository(ctrl)
+	sessions := mocks.NewMockSessionRepository(ctrl)
+	mailer := mocks.NewMockMailer(ctrl)
+	uc := NewAuthUseCase(repo, sessions, nil, mailer, testSigner(t), Config{})
+
+	var link string
+	repo.EXPECT().TouchVerificationSent(gomock.Any(), "u1", gomock.Any()).Return(nil)
+	mailer.EXPECT().
+		Send(gomock.Any(), "u@ex.com", gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _, _, body string) error {
+			link = body
+			return nil
+		})
+	repo.EXPECT().GetUserByID(gomock.Any(), "u1").Return(&domain.User{ID: "u1", Email: "u@ex.com"}, nil)
+
+	if err := uc.ResendVerification(context.Background(), "u1"); err != nil {
+		t.Fatalf("resend failed: %v", err)
+	}
+
+	i := strings.Index(link, "token=")
+	if i < 0 {
+		t.Fatalf("token not found in body: %s", link)
+	}
+	token := strings.Fields(link[i+len("token="):])[0]
+
+	repo.EXPECT().MarkEmailVerified(gomock.Any(), "u1", "u@ex.com").Return(nil)
+	if err := uc.VerifyEmail(context.Background(), token); err != nil {
+		t.Fatalf("verify failed: %v", err)
+	}
+}
+
+func TestVerifyEmail_RejectsAccessToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockAuthRepository(ctrl)
+	sessions := mocks.NewMockSessionRepository(ctrl)
+	uc := NewAuthUseCase(repo, sessions, nil, nil, testSigner(t), Config{})
+
+	access, _ := uc.generateToken("u1", "u@ex.com", "s1", nil, false, time.Now().Add(time.Minute))
+	if err := uc.VerifyEmail(context.Background(), access); err != domain.ErrVerifyTokenInvalid {
+		t.Fatalf("expected ErrVerifyTokenInvalid, got %v", err)
+	}
+}
+
+func TestResendVerification_AlreadyVerified(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockAuthRepository(ctrl)
+	sessions := mocks.NewMockSessionRepository(ctrl)
+	uc := NewAuthUseCase(repo, sessions, nil, nil, testSigner(t), Config{})
+
+	verifiedAt := time.Now()
+	repo.EXPECT().GetUserByID(gomock.Any(), "u1").Return(&domain.User{ID: "u1", Email: "u@ex.com", EmailVerifiedAt: &verifiedAt}, nil)
+
+	if err := uc.ResendVerification(context.Background(), "u1"); err != domain.ErrEmailAlreadyVerified {
+		t.Fatalf("expected ErrEmailAlreadyVerified, got %v", err)
+	}
+}
+
+func TestLogin_LockedOut(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockAuthRepository(ctrl)
+	sessions := mocks.NewMockSessionRepository(ctrl)
+	throttle := mocks.NewMockThrottleRepository(ctrl)
+	uc := NewAuthUseCase(repo, sessions, throttle, nil, testSigner(t), Config{})
+
+	until := time.Now().Add(time.Minute)
+	throttle.EXPECT().LockedUntil(gomock.Any(), "login:email:u@ex.com").Return(&until, nil)
+
+	_, err := uc.Login(context.Background(), "U@ex.com", "Str0ng!Pass")
+	var throttled *domain.ThrottledError
+	if !errors.As(err, &throttled) || throttled.RetryAfter <= 0 {
+		t.Fatalf("expected ThrottledError, got %v", err)
+	}
+}
+
+func TestLogin_FailureLocksAfterFreeAttempts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockAuthRepository(ctrl)
+	sessions := mocks.NewMockSessionRepository(ctrl)
+	throttle := mocks.NewMockThrottleRepository(ctrl)
+	uc := NewAuthUseCase(repo, sessions, throttle, nil, testSigner(t), Config{})
+
+	ctx := trace.SetClientInfo(context.Background(), "10.0.0.1", "test")
+	hash, _ := bcrypt.GenerateFromPassword([]byte("Str0ng!Pass"), bcrypt.MinCost)
+
+	throttle.EXPECT().LockedUntil(gomock.Any(), "login:email:u@ex.com", "login:pair:10.0.0.1|u@ex.com", "login:ip:10.0.0.1").Return(nil, nil)
+	repo.EXPECT().GetUserByEmail(gomock.Any(), "u@ex.com").Return(&domain.User{ID: "u1", Email: "u@ex.com", PasswordHash: string(hash)}, nil)
+	throttle.EXPECT().Hit(gomock.Any(), "login:email:u@ex.com", gomock.Any()).Return(2, nil)
+	throttle.EXPECT().Hit(gomock.Any(), "login:pair:10.0.0.1|u@ex.com", gomock.Any()).Return(loginPairRule.free+2, nil)
+	throttle.EXPECT().Hit(gomock.Any(), "login:ip:10.0.0.1", gomock.Any()).Return(2, nil)
+	throttle.EXPECT().Lock(gomock.Any(), "login:pair:10.0.0.1|u@ex.com", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, until time.Time) error {
+			if d := time.Until(until); d < time.Second || d > 3*time.Second {
+				t.Fatalf("expected ~2s delay, got %v", d)
+			}
+			return nil
+		})
+
+	if _, err := uc.Login(ctx, "u@ex.com", "Wr0ng!Pass"); err != domain.ErrInvalidPassword {
+		t.Fatalf("expected ErrInvalidPassword, got %v", err)
+	}
+}
 
-	if _, err := uc.RefreshToken(context.Background(), "bad.token"); err == nil {
-		t.Fatal("expected error for bad token")
+func TestThrottleRule_Delay(t *testing.T) {
+	r := throttleRule{free: 3, lockAfter: 10, lockout: 15 * time.Minute}
+	cases := map[int]time.Duration{1: 0, 3: 0, 4: time.Second, 5: 2 * time.Second, 9: 32 * time.Second, 10: 15 * time.Minute}
+	for attempts, want := range cases {
+		if got := r.delay(attempts); got != want {
+			t.Errorf("delay(%d) = %v, want %v", attempts, got, want)
+		}
 	}
 }
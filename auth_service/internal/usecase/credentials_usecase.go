@@ -0,0 +1,205 @@
+package usecase
+
+import (
+	"apple_backend/auth_service/internal/domain"
+	"apple_backend/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	emailChangeTTL     = 24 * time.Hour
+	emailChangePurpose = "email_change"
+
+	// reauthFreshness — сколько после входа сессия аккаунта без пароля
+	// считается подтверждением личности
+	reauthFreshness = 5 * time.Minute
+)
+
+// emailChangeClaims — запрос на смену адреса. OldEmail нужен, чтобы ссылка
+// перестала работать, как только адрес сменится любым способом.
+type emailChangeClaims struct {
+	UserID   string `json:"user_id"`
+	OldEmail string `json:"old_email"`
+	NewEmail string `json:"new_email"`
+	Purpose  string `json:"purpose"`
+	jwt.RegisteredClaims
+}
+
+// reauthenticate проверяет текущий пароль внутри открытой сессии. У
+// аккаунта без пароля (вход по телефону или через провайдера) вместо него
+// нужен код 2FA или сессия, открытая входом не раньше reauthFreshness назад:
+// одного украденного токена не должно хватать, чтобы задать пароль или
+// сменить email.
+func (uc *authUseCase) reauthenticate(ctx context.Context, user *domain.User, sessionID, password, code string) error {
+	if user.PasswordHash == "" {
+		return uc.reauthenticatePasswordless(ctx, user, sessionID, code)
+	}
+	keys := []throttleKey{{reauthRule, user.ID}}
+	if err := uc.checkThrottle(ctx, keys); err != nil {
+		return err
+	}
+	if !uc.checkPassword(ctx, user, password) {
+		uc.loginFailed(ctx, keys)
+		return domain.ErrInvalidPassword
+	}
+	uc.resetThrottle(ctx, keys)
+	return nil
+}
+
+func (uc *authUseCase) reauthenticatePasswordless(ctx context.Context, user *domain.User, sessionID, code string) error {
+	if code != "" && user.TwoFactorEnabled() {
+		return uc.verifySecondFactor(ctx, user.ID, code)
+	}
+	if sessionID == "" {
+		return domain.ErrReauthRequired
+	}
+	session, err := uc.sessions.GetSession(ctx, sessionID)
+	if errors.Is(err, domain.ErrSessionNotFound) {
+		return domain.ErrReauthRequired
+	}
+	if err != nil {
+		return err
+	}
+	authenticatedAt := session.CreatedAt
+	if session.MFAVerifiedAt != nil && session.MFAVerifiedAt.After(authenticatedAt) {
+		authenticatedAt = *session.MFAVerifiedAt
+	}
+	if session.UserID != user.ID || session.RevokedAt != nil || time.Since(authenticatedAt) > reauthFreshness {
+		return domain.ErrReauthRequired
+	}
+	return nil
+}
+
+// ChangePassword меняет пароль после проверки текущего и завершает все
+// сессии пользователя, кроме той, из которой пришел запрос.
+func (uc *authUseCase) ChangePassword(ctx context.Context, userID, sessionID, currentPassword, code, newPassword string) error {
+	user, err := uc.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := uc.reauthenticate(ctx, user, sessionID, currentPassword, code); err != nil {
+		return err
+	}
+	if err := uc.validatePasswordSecurity(ctx, user.Email, newPassword); err != nil {
+		return err
+	}
+
+	hashed, err := uc.hashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+	if err := uc.credentials.ChangePassword(ctx, user.ID, user.PasswordHash, hashed); err != nil {
+		return err
+	}
+	if err := uc.sessions.RevokeOtherSessions(ctx, user.ID, sessionID); err != nil {
+		return err
+	}
+	uc.recordEvent(ctx, domain.AuthEvent{UserID: user.ID, Type: domain.EventPasswordChange})
+	return nil
+}
+
+// RequestEmailChange отправляет ссылку подтверждения на новый адрес и
+// уведомление на старый. Адрес меняется только после перехода по ссылке.
+func (uc *authUseCase) RequestEmailChange(ctx context.Context, userID, sessionID, password, code, newEmail string) error {
+	log := logger.FromContext(ctx)
+	newEmail = strings.TrimSpace(newEmail)
+	if err := uc.validateAccountEmail(newEmail); err != nil {
+		return err
+	}
+
+	user, err := uc.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if strings.EqualFold(user.Email, newEmail) {
+		return domain.ErrEmailUnchanged
+	}
+	if err := uc.reauthenticate(ctx, user, sessionID, password, code); err != nil {
+		return err
+	}
+
+	keys := []throttleKey{{emailChangeRule, user.ID}}
+	if err := uc.checkThrottle(ctx, keys); err != nil {
+		return err
+	}
+	exists, err := uc.repo.UserExists(ctx, newEmail)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return domain.ErrUserAlreadyExists
+	}
+	if err := uc.registerAttempts(ctx, keys); err != nil {
+		log.ErrorContext(ctx, "usecase RequestEmailChange throttle update failed", slog.Any("err", err))
+	}
+
+	now := time.Now()
+	token, err := uc.signer.Sign(&emailChangeClaims{
+		UserID:   user.ID,
+		OldEmail: user.Email,
+		NewEmail: newEmail,
+		Purpose:  emailChangePurpose,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(now.Add(emailChangeTTL)),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/email/confirm?token=%s", uc.appURL, url.QueryEscape(token))
+	body := fmt.Sprintf(
+		"Здравствуйте!\n\nЧтобы использовать этот адрес для входа, перейдите по ссылке:\n%s\n\n"+
+			"Ссылка действует %d часа. Если вы не меняли адрес, проигнорируйте это письмо.",
+		link, int(emailChangeTTL.Hours()),
+	)
+	if err := uc.mailer.Send(ctx, newEmail, "Подтверждение нового email", body); err != nil {
+		log.ErrorContext(ctx, "usecase RequestEmailChange send failed",
+			slog.Any("err", err), slog.String("user_id", user.ID))
+		return err
+	}
+
+	if user.Email != "" {
+		notice := fmt.Sprintf(
+			"Здравствуйте!\n\nДля вашего аккаунта запрошена смена email на %s.\n"+
+				"Адрес сменится, только когда владелец нового адреса подтвердит его.\n"+
+				"Если это были не вы, смените пароль и завершите чужие сессии.",
+			newEmail,
+		)
+		// уведомление не должно мешать смене: главное письмо уже отправлено
+		if err := uc.mailer.Send(ctx, user.Email, "Смена email", notice); err != nil {
+			log.ErrorContext(ctx, "usecase RequestEmailChange notice failed",
+				slog.Any("err", err), slog.String("user_id", user.ID))
+		}
+	}
+
+	uc.recordEvent(ctx, domain.AuthEvent{UserID: user.ID, Type: domain.EventEmailChangeSent, Identifier: newEmail})
+	return nil
+}
+
+// ConfirmEmailChange меняет адрес по подписанному токену из письма.
+func (uc *authUseCase) ConfirmEmailChange(ctx context.Context, token string) error {
+	claims := &emailChangeClaims{}
+	parsed, err := jwt.ParseWithClaims(token, claims, uc.signer.Keyfunc)
+	if err != nil || !parsed.Valid || claims.Purpose != emailChangePurpose || claims.UserID == "" {
+		return domain.ErrEmailChangeInvalid
+	}
+	if err := uc.validateAccountEmail(claims.NewEmail); err != nil {
+		return domain.ErrEmailChangeInvalid
+	}
+
+	if err := uc.credentials.ChangeEmail(ctx, claims.UserID, claims.OldEmail, claims.NewEmail); err != nil {
+		return err
+	}
+	uc.recordEvent(ctx, domain.AuthEvent{UserID: claims.UserID, Type: domain.EventEmailChange, Identifier: claims.NewEmail})
+	return nil
+}
@@ -0,0 +1,193 @@
+package usecase
+
+import (
+	"apple_backend/auth_service/internal/domain"
+	mocks "apple_backend/auth_service/internal/usecase/mock"
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func passwordUser(t *testing.T, password string) *domain.User {
+	t.Helper()
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &domain.User{ID: "u1", Email: "old@ex.com", PasswordHash: string(hash)}
+}
+
+func TestChangePassword_RevokesOtherSessions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockAuthRepository(ctrl)
+	credentials := mocks.NewMockCredentialsRepository(ctrl)
+	sessions := mocks.NewMockSessionRepository(ctrl)
+	uc := NewAuthUseCase(repo, sessions, nil, nil, testSigner(t), Config{Credentials: credentials})
+
+	user := passwordUser(t, "Str0ng!Pass")
+	repo.EXPECT().GetUserByID(gomock.Any(), "u1").Return(user, nil)
+	repo.EXPECT().RehashPassword(gomock.Any(), "u1", gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	// bcrypt-хэш пересчитывается при проверке, сравнение идет уже с новым
+	credentials.EXPECT().ChangePassword(gomock.Any(), "u1", gomock.Any(), gomock.Any()).Return(nil)
+	sessions.EXPECT().RevokeOtherSessions(gomock.Any(), "u1", "s1").Return(nil)
+
+	if err := uc.ChangePassword(context.Background(), "u1", "s1", "Str0ng!Pass", "", "An0ther#Secret9"); err != nil {
+		t.Fatalf("change password failed: %v", err)
+	}
+}
+
+func TestChangePassword_WrongCurrentPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockAuthRepository(ctrl)
+	sessions := mocks.NewMockSessionRepository(ctrl)
+	uc := NewAuthUseCase(repo, sessions, nil, nil, testSigner(t), Config{})
+
+	repo.EXPECT().GetUserByID(gomock.Any(), "u1").Return(passwordUser(t, "Str0ng!Pass"), nil)
+
+	err := uc.ChangePassword(context.Background(), "u1", "s1", "Wr0ng!Pass", "", "An0ther#Secret9")
+	if err != domain.ErrInvalidPassword {
+		t.Fatalf("expected ErrInvalidPassword, got %v", err)
+	}
+}
+
+func TestChangePassword_PasswordlessNeedsFreshSession(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockAuthRepository(ctrl)
+	credentials := mocks.NewMockCredentialsRepository(ctrl)
+	sessions := mocks.NewMockSessionRepository(ctrl)
+	uc := NewAuthUseCase(repo, sessions, nil, nil, testSigner(t), Config{Credentials: credentials})
+	ctx := context.Background()
+
+	// аккаунт создан входом по телефону: пароля нет, сверять нечего
+	repo.EXPECT().GetUserByID(gomock.Any(), "u1").Return(&domain.User{ID: "u1"}, nil).AnyTimes()
+
+	// украденная давняя сессия не дает задать пароль
+	sessions.EXPECT().GetSession(gomock.Any(), "s-old").
+		Return(&domain.Session{ID: "s-old", UserID: "u1", CreatedAt: time.Now().Add(-time.Hour)}, nil)
+	if err := uc.ChangePassword(ctx, "u1", "s-old", "", "", "An0ther#Secret9"); err != domain.ErrReauthRequired {
+		t.Fatalf("expected ErrReauthRequired, got %v", err)
+	}
+
+	// код 2FA без включенной 2FA не подтверждает личность
+	sessions.EXPECT().GetSession(gomock.Any(), "s-old").
+		Return(&domain.Session{ID: "s-old", UserID: "u1", CreatedAt: time.Now().Add(-time.Hour)}, nil)
+	if err := uc.ChangePassword(ctx, "u1", "s-old", "", "123456", "An0ther#Secret9"); err != domain.ErrReauthRequired {
+		t.Fatalf("expected ErrReauthRequired, got %v", err)
+	}
+
+	// сразу после входа по коду из SMS пароль задать можно
+	sessions.EXPECT().GetSession(gomock.Any(), "s-new").
+		Return(&domain.Session{ID: "s-new", UserID: "u1", CreatedAt: time.Now().Add(-time.Minute)}, nil)
+	credentials.EXPECT().ChangePassword(gomock.Any(), "u1", "", gomock.Any()).Return(nil)
+	sessions.EXPECT().RevokeOtherSessions(gomock.Any(), "u1", "s-new").Return(nil)
+	if err := uc.ChangePassword(ctx, "u1", "s-new", "", "", "An0ther#Secret9"); err != nil {
+		t.Fatalf("change password failed: %v", err)
+	}
+}
+
+func TestRequestEmailChange_PasswordlessStaleSession(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockAuthRepository(ctrl)
+	sessions := mocks.NewMockSessionRepository(ctrl)
+	uc := NewAuthUseCase(repo, sessions, nil, nil, testSigner(t), Config{})
+
+	repo.EXPECT().GetUserByID(gomock.Any(), "u1").Return(&domain.User{ID: "u1", Email: "old@ex.com"}, nil)
+	sessions.EXPECT().GetSession(gomock.Any(), "s1").
+		Return(&domain.Session{ID: "s1", UserID: "u1", CreatedAt: time.Now().Add(-time.Hour)}, nil)
+
+	err := uc.RequestEmailChange(context.Background(), "u1", "s1", "", "", "new@ex.com")
+	if err != domain.ErrReauthRequired {
+		t.Fatalf("expected ErrReauthRequired, got %v", err)
+	}
+}
+
+func TestRequestEmailChange_Validation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockAuthRepository(ctrl)
+	uc := NewAuthUseCase(repo, nil, nil, nil, testSigner(t), Config{})
+	ctx := context.Background()
+
+	// адреса, которые не пройдут ограничения account.email
+	for _, email := range []string{"not-an-email", "user+tag@ex.com", strings.Repeat("a", 95) + "@ex.com"} {
+		if err := uc.RequestEmailChange(ctx, "u1", "s1", "Str0ng!Pass", "", email); err != domain.ErrInvalidEmail {
+			t.Fatalf("%s: expected ErrInvalidEmail, got %v", email, err)
+		}
+	}
+
+	user := passwordUser(t, "Str0ng!Pass")
+	repo.EXPECT().GetUserByID(gomock.Any(), "u1").Return(user, nil).Times(2)
+	repo.EXPECT().RehashPassword(gomock.Any(), "u1", gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	if err := uc.RequestEmailChange(ctx, "u1", "s1", "Str0ng!Pass", "", "OLD@ex.com"); err != domain.ErrEmailUnchanged {
+		t.Fatalf("expected ErrEmailUnchanged, got %v", err)
+	}
+
+	repo.EXPECT().UserExists(gomock.Any(), "taken@ex.com").Return(true, nil)
+	if err := uc.RequestEmailChange(ctx, "u1", "s1", "Str0ng!Pass", "", "taken@ex.com"); err != domain.ErrUserAlreadyExists {
+		t.Fatalf("expected ErrUserAlreadyExists, got %v", err)
+	}
+}
+
+func TestEmailChange_ConfirmAndNotice(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockAuthRepository(ctrl)
+	credentials := mocks.NewMockCredentialsRepository(ctrl)
+	mailer := mocks.NewMockMailer(ctrl)
+	uc := NewAuthUseCase(repo, nil, nil, mailer, testSigner(t), Config{AppURL: "http://front/", Credentials: credentials})
+	ctx := context.Background()
+
+	user := passwordUser(t, "Str0ng!Pass")
+	repo.EXPECT().GetUserByID(gomock.Any(), "u1").Return(user, nil)
+	repo.EXPECT().RehashPassword(gomock.Any(), "u1", gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	repo.EXPECT().UserExists(gomock.Any(), "new@ex.com").Return(false, nil)
+
+	var token string
+	mailer.EXPECT().
+		Send(gomock.Any(), "new@ex.com", gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _, _, body string) error {
+			const prefix = "http://front/email/confirm?token="
+			i := strings.Index(body, prefix)
+			if i < 0 {
+				t.Fatalf("confirm link not found in body: %s", body)
+			}
+			raw := strings.Fields(body[i+len(prefix):])[0]
+			token, _ = url.QueryUnescape(raw)
+			return nil
+		})
+	mailer.EXPECT().
+		Send(gomock.Any(), "old@ex.com", gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _, _, body string) error {
+			if !strings.Contains(body, "new@ex.com") || strings.Contains(body, "token=") {
+				t.Fatalf("unexpected notice body: %s", body)
+			}
+			return nil
+		})
+
+	if err := uc.RequestEmailChange(ctx, "u1", "s1", "Str0ng!Pass", "", "new@ex.com"); err != nil {
+		t.Fatalf("request email change failed: %v", err)
+	}
+
+	credentials.EXPECT().ChangeEmail(gomock.Any(), "u1", "old@ex.com", "new@ex.com").Return(nil)
+	if err := uc.ConfirmEmailChange(ctx, token); err != nil {
+		t.Fatalf("confirm failed: %v", err)
+	}
+	if err := uc.ConfirmEmailChange(ctx, "garbage"); err != domain.ErrEmailChangeInvalid {
+		t.Fatalf("expected ErrEmailChangeInvalid, got %v", err)
+	}
+}
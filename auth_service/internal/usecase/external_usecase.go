@@ -0,0 +1,198 @@
+package usecase
+
+import (
+	"apple_backend/auth_service/internal/delivery/transport"
+	"apple_backend/auth_service/internal/domain"
+	"apple_backend/pkg/logger"
+	"apple_backend/pkg/oidc"
+	"context"
+	"crypto/subtle"
+	"errors"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	externalStateTTL     = 10 * time.Minute
+	externalStatePurpose = "oidc_state"
+)
+
+// externalStateClaims — состояние входа между редиректом на провайдера и
+// возвратом. Подписано ключом сервиса и хранится в cookie браузера, так что
+// отдельная таблица для state не нужна.
+type externalStateClaims struct {
+	Provider string `json:"provider"`
+	State    string `json:"state"`
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
+	Purpose  string `json:"purpose"`
+	jwt.RegisteredClaims
+}
+
+// ExternalProviders — имена настроенных провайдеров
+func (uc *authUseCase) ExternalProviders() []string {
+	names := make([]string, 0, len(uc.external))
+	for name := range uc.external {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// StartExternalLogin готовит редирект на провайдера: state против CSRF,
+// nonce против подмены ID-токена и code_verifier для PKCE.
+func (uc *authUseCase) StartExternalLogin(ctx context.Context, provider string) (*transport.ExternalLoginStart, error) {
+	p, ok := uc.external[provider]
+	if !ok {
+		return nil, domain.ErrUnknownProvider
+	}
+	state, err := oidc.RandomString()
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := oidc.RandomString()
+	if err != nil {
+		return nil, err
+	}
+	verifier, err := oidc.NewVerifier()
+	if err != nil {
+		return nil, err
+	}
+
+	authURL, err := p.AuthCodeURL(ctx, state, nonce, verifier)
+	if err != nil {
+		logger.FromContext(ctx).ErrorContext(ctx, "usecase StartExternalLogin provider unavailable",
+			slog.Any("err", err), slog.String("provider", provider))
+		return nil, domain.ErrExternalLoginFailed
+	}
+
+	now := time.Now()
+	expires := now.Add(externalStateTTL)
+	stateToken, err := uc.signer.Sign(&externalStateClaims{
+		Provider: provider,
+		State:    state,
+		Nonce:    nonce,
+		Verifier: verifier,
+		Purpose:  externalStatePurpose,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expires),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &transport.ExternalLoginStart{AuthURL: authURL, StateToken: stateToken, ExpiresAt: expires}, nil
+}
+
+// FinishExternalLogin обменивает код на ID-токен и входит в привязанный
+// аккаунт. Непривязанный пользователь провайдера привязывается к аккаунту
+// с тем же подтвержденным email, а если такого нет — создается новый аккаунт.
+func (uc *authUseCase) FinishExternalLogin(ctx context.Context, provider, stateToken, state, code string) (*transport.AuthResult, error) {
+	log := logger.FromContext(ctx)
+	p, ok := uc.external[provider]
+	if !ok {
+		return nil, domain.ErrUnknownProvider
+	}
+	claims := &externalStateClaims{}
+	parsed, err := jwt.ParseWithClaims(stateToken, claims, uc.signer.Keyfunc)
+	if err != nil || !parsed.Valid || claims.Purpose != externalStatePurpose || claims.Provider != provider ||
+		state == "" || subtle.ConstantTimeCompare([]byte(claims.State), []byte(state)) != 1 {
+		return nil, domain.ErrExternalStateInvalid
+	}
+	if code == "" {
+		return nil, domain.ErrExternalLoginFailed
+	}
+
+	tok, err := p.Exchange(ctx, code, claims.Verifier)
+	if err != nil {
+		log.WarnContext(ctx, "usecase FinishExternalLogin exchange failed", slog.Any("err", err), slog.String("provider", provider))
+		return nil, domain.ErrExternalLoginFailed
+	}
+	identity, err := p.VerifyIDToken(ctx, tok.IDToken, claims.Nonce)
+	if err != nil {
+		log.WarnContext(ctx, "usecase FinishExternalLogin id token rejected", slog.Any("err", err), slog.String("provider", provider))
+		return nil, domain.ErrExternalLoginFailed
+	}
+
+	user, err := uc.externalUser(ctx, provider, identity)
+	if err != nil {
+		return nil, err
+	}
+	challenge, err := uc.loginChallenge(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	if challenge != nil {
+		return challenge, nil
+	}
+	return uc.loginSession(ctx, user, false, domain.MethodExternalPrefix+provider)
+}
+
+func (uc *authUseCase) externalUser(ctx context.Context, provider string, identity *oidc.Claims) (*domain.User, error) {
+	log := logger.FromContext(ctx)
+	user, err := uc.externalIDs.GetExternalIdentityUser(ctx, provider, identity.Subject)
+	if !errors.Is(err, domain.ErrUserNotFound) {
+		return user, err
+	}
+
+	// неподтвержденному провайдером email не доверяем: по нему можно было
+	// бы войти в чужой аккаунт. Адрес, который не пройдет ограничения
+	// таблицы account (например, с "+"), тоже не сохраняем
+	email := ""
+	if identity.EmailVerified && uc.validateAccountEmail(identity.Email) == nil {
+		email = identity.Email
+	}
+
+	if email != "" {
+		user, err = uc.repo.GetUserByEmail(ctx, email)
+		if err == nil {
+			if err := uc.linkByEmail(ctx, user, provider, identity.Subject); err != nil {
+				return nil, err
+			}
+			log.InfoContext(ctx, "usecase FinishExternalLogin linked by email",
+				slog.String("user_id", user.ID), slog.String("provider", provider))
+			return user, nil
+		}
+		if !errors.Is(err, domain.ErrUserNotFound) {
+			return nil, err
+		}
+	}
+
+	user, err = uc.externalIDs.CreateExternalUser(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.externalIDs.LinkExternalIdentity(ctx, user.ID, provider, identity.Subject, email); err != nil {
+		if errors.Is(err, domain.ErrExternalIdentityConflict) {
+			// параллельный вход уже привязал этого пользователя
+			return uc.externalIDs.GetExternalIdentityUser(ctx, provider, identity.Subject)
+		}
+		return nil, err
+	}
+	log.InfoContext(ctx, "usecase FinishExternalLogin account created",
+		slog.String("user_id", user.ID), slog.String("provider", provider))
+	uc.recordEvent(ctx, domain.AuthEvent{UserID: user.ID, Type: domain.EventSignup, Method: domain.MethodExternalPrefix + provider, Identifier: email})
+	return user, nil
+}
+
+// linkByEmail привязывает пользователя провайдера к аккаунту с тем же
+// email. Неподтвержденный адрес мог занять тот, кто зарегистрировался на
+// чужой email раньше владельца: его пароль, второй фактор и сессии
+// сбрасываются вместе с привязкой, иначе он сохранил бы доступ к аккаунту.
+func (uc *authUseCase) linkByEmail(ctx context.Context, user *domain.User, provider, subject string) error {
+	if user.EmailVerified() {
+		return uc.externalIDs.LinkExternalIdentity(ctx, user.ID, provider, subject, user.Email)
+	}
+	if err := uc.externalIDs.ClaimUnverifiedAccount(ctx, user.ID, user.Email, provider, subject); err != nil {
+		return err
+	}
+	now := time.Now()
+	user.EmailVerifiedAt = &now
+	user.PasswordHash = ""
+	user.TwoFactorEnabledAt = nil
+	return nil
+}
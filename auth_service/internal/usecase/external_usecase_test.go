@@ -0,0 +1,209 @@
+package usecase
+
+import (
+	"apple_backend/auth_service/internal/delivery/transport"
+	"apple_backend/auth_service/internal/domain"
+	mocks "apple_backend/auth_service/internal/usecase/mock"
+	"apple_backend/pkg/oidc"
+	"apple_backend/pkg/oidc/oidctest"
+	"apple_backend/pkg/rbac"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+)
+
+type externalFixture struct {
+	provider   *oidctest.Server
+	repo       *mocks.MockAuthRepository
+	identities *mocks.MockExternalIdentityRepository
+	roles      *mocks.MockRoleRepository
+	sessions   *mocks.MockSessionRepository
+	uc         *authUseCase
+}
+
+func newExternalFixture(t *testing.T) *externalFixture {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	srv, err := oidctest.NewServer("client", "s3cret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(srv.Close)
+
+	f := &externalFixture{
+		provider:   srv,
+		repo:       mocks.NewMockAuthRepository(ctrl),
+		identities: mocks.NewMockExternalIdentityRepository(ctrl),
+		roles:      mocks.NewMockRoleRepository(ctrl),
+		sessions:   mocks.NewMockSessionRepository(ctrl),
+	}
+	f.uc = NewAuthUseCase(f.repo, f.sessions, nil, nil, testSigner(t), Config{
+		ExternalProviders: map[string]ExternalProvider{
+			"test": oidc.NewProvider(oidc.Config{
+				Issuer:       srv.Issuer(),
+				ClientID:     "client",
+				ClientSecret: "s3cret",
+				RedirectURL:  "http://front/oauth/test/callback",
+			}),
+		},
+		External: f.identities,
+		Roles:    f.roles,
+	})
+	return f
+}
+
+// login проходит редирект на провайдера и возвращает результат callback
+func (f *externalFixture) login(t *testing.T) (*transport.AuthResult, error) {
+	t.Helper()
+	ctx := context.Background()
+	start, err := f.uc.StartExternalLogin(ctx, "test")
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	code, state, err := f.provider.Authorize(start.AuthURL)
+	if err != nil {
+		t.Fatalf("authorize: %v", err)
+	}
+	return f.uc.FinishExternalLogin(ctx, "test", start.StateToken, state, code)
+}
+
+func (f *externalFixture) expectSession(userID string) {
+	f.roles.EXPECT().GetUserRoles(gomock.Any(), userID).Return([]string{rbac.RoleCustomer}, nil).AnyTimes()
+	f.sessions.EXPECT().
+		CreateSession(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&domain.Session{ID: "s1", UserID: userID}, nil)
+}
+
+func TestExternalLogin_LinkedIdentity(t *testing.T) {
+	f := newExternalFixture(t)
+	f.provider.SetUser(oidctest.User{Subject: "vk-1", Email: "u@ex.com", EmailVerified: true})
+
+	f.identities.EXPECT().GetExternalIdentityUser(gomock.Any(), "test", "vk-1").Return(&domain.User{ID: "u1"}, nil)
+	f.expectSession("u1")
+
+	res, err := f.login(t)
+	if err != nil || res.UserID != "u1" || res.Token == "" {
+		t.Fatalf("login failed: err=%v res=%+v", err, res)
+	}
+}
+
+func TestExternalLogin_LinksByVerifiedEmail(t *testing.T) {
+	f := newExternalFixture(t)
+	f.provider.SetUser(oidctest.User{Subject: "vk-1", Email: "u@ex.com", EmailVerified: true})
+
+	verified := time.Now()
+	f.identities.EXPECT().GetExternalIdentityUser(gomock.Any(), "test", "vk-1").Return(nil, domain.ErrUserNotFound)
+	f.repo.EXPECT().GetUserByEmail(gomock.Any(), "u@ex.com").Return(&domain.User{ID: "u1", Email: "u@ex.com", EmailVerifiedAt: &verified}, nil)
+	f.identities.EXPECT().LinkExternalIdentity(gomock.Any(), "u1", "test", "vk-1", "u@ex.com").Return(nil)
+	f.expectSession("u1")
+
+	res, err := f.login(t)
+	if err != nil || res.UserID != "u1" {
+		t.Fatalf("login failed: err=%v res=%+v", err, res)
+	}
+}
+
+func TestExternalLogin_ClaimsUnverifiedAccount(t *testing.T) {
+	f := newExternalFixture(t)
+	f.provider.SetUser(oidctest.User{Subject: "vk-1", Email: "u@ex.com", EmailVerified: true})
+
+	f.identities.EXPECT().GetExternalIdentityUser(gomock.Any(), "test", "vk-1").Return(nil, domain.ErrUserNotFound)
+	f.repo.EXPECT().GetUserByEmail(gomock.Any(), "u@ex.com").Return(&domain.User{ID: "u1", Email: "u@ex.com"}, nil)
+	f.identities.EXPECT().ClaimUnverifiedAccount(gomock.Any(), "u1", "u@ex.com", "test", "vk-1").Return(nil)
+	f.expectSession("u1")
+
+	if _, err := f.login(t); err != nil {
+		t.Fatalf("login failed: %v", err)
+	}
+}
+
+func TestExternalLogin_ClaimDropsSquatterCredentials(t *testing.T) {
+	f := newExternalFixture(t)
+	f.provider.SetUser(oidctest.User{Subject: "vk-1", Email: "u@ex.com", EmailVerified: true})
+
+	// злоумышленник зарегистрировался на адрес владельца и включил 2FA
+	hash, err := f.uc.hashPassword("Squ4tter!Pass")
+	if err != nil {
+		t.Fatalf("hash: %v", err)
+	}
+	enabled := time.Now()
+	account := domain.User{ID: "u1", Email: "u@ex.com", PasswordHash: hash, TwoFactorEnabledAt: &enabled}
+	f.repo.EXPECT().GetUserByEmail(gomock.Any(), "u@ex.com").
+		DoAndReturn(func(context.Context, string) (*domain.User, error) {
+			u := account
+			return &u, nil
+		}).Times(2)
+	f.identities.EXPECT().GetExternalIdentityUser(gomock.Any(), "test", "vk-1").Return(nil, domain.ErrUserNotFound)
+	f.identities.EXPECT().ClaimUnverifiedAccount(gomock.Any(), "u1", "u@ex.com", "test", "vk-1").
+		DoAndReturn(func(context.Context, string, string, string, string) error {
+			verified := time.Now()
+			account.EmailVerifiedAt = &verified
+			account.PasswordHash = ""
+			account.TwoFactorEnabledAt = nil
+			return nil
+		})
+	f.expectSession("u1")
+
+	// владелец входит сразу, без второго шага злоумышленника
+	res, err := f.login(t)
+	if err != nil || res.UserID != "u1" || res.Token == "" {
+		t.Fatalf("login failed: err=%v res=%+v", err, res)
+	}
+
+	if _, err := f.uc.Login(context.Background(), "u@ex.com", "Squ4tter!Pass"); err != domain.ErrInvalidPassword {
+		t.Fatalf("expected ErrInvalidPassword for the squatter's password, got %v", err)
+	}
+}
+
+func TestExternalLogin_UnverifiedEmailCreatesAccount(t *testing.T) {
+	f := newExternalFixture(t)
+	f.provider.SetUser(oidctest.User{Subject: "vk-1", Email: "u@ex.com", EmailVerified: false})
+
+	// адрес не подтвержден провайдером: к аккаунту с этим email не привязываем
+	f.identities.EXPECT().GetExternalIdentityUser(gomock.Any(), "test", "vk-1").Return(nil, domain.ErrUserNotFound)
+	f.identities.EXPECT().CreateExternalUser(gomock.Any(), "").Return(&domain.User{ID: "u2"}, nil)
+	f.identities.EXPECT().LinkExternalIdentity(gomock.Any(), "u2", "test", "vk-1", "").Return(nil)
+	f.expectSession("u2")
+
+	res, err := f.login(t)
+	if err != nil || res.UserID != "u2" {
+		t.Fatalf("login failed: err=%v res=%+v", err, res)
+	}
+}
+
+func TestExternalLogin_EmailRejectedByAccountTable(t *testing.T) {
+	f := newExternalFixture(t)
+	f.provider.SetUser(oidctest.User{Subject: "ya-1", Email: "u+shop@ex.com", EmailVerified: true})
+
+	// адрес с "+" не пройдет email_format таблицы account: аккаунт без email
+	f.identities.EXPECT().GetExternalIdentityUser(gomock.Any(), "test", "ya-1").Return(nil, domain.ErrUserNotFound)
+	f.identities.EXPECT().CreateExternalUser(gomock.Any(), "").Return(&domain.User{ID: "u2"}, nil)
+	f.identities.EXPECT().LinkExternalIdentity(gomock.Any(), "u2", "test", "ya-1", "").Return(nil)
+	f.expectSession("u2")
+
+	res, err := f.login(t)
+	if err != nil || res.UserID != "u2" {
+		t.Fatalf("login failed: err=%v res=%+v", err, res)
+	}
+}
+
+func TestExternalLogin_StateMismatch(t *testing.T) {
+	f := newExternalFixture(t)
+	ctx := context.Background()
+
+	start, err := f.uc.StartExternalLogin(ctx, "test")
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	code, _, _ := f.provider.Authorize(start.AuthURL)
+	if _, err := f.uc.FinishExternalLogin(ctx, "test", start.StateToken, "forged", code); err != domain.ErrExternalStateInvalid {
+		t.Fatalf("expected ErrExternalStateInvalid, got %v", err)
+	}
+	if _, err := f.uc.FinishExternalLogin(ctx, "other", start.StateToken, "forged", code); err != domain.ErrUnknownProvider {
+		t.Fatalf("expected ErrUnknownProvider, got %v", err)
+	}
+}
@@ -0,0 +1,51 @@
+package usecase
+
+import (
+	"apple_backend/auth_service/internal/delivery/transport"
+	"apple_backend/auth_service/internal/domain"
+	"context"
+	"errors"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// Introspect сообщает, действителен ли access-токен прямо сейчас: подпись,
+// срок и то, что его сессия не отозвана. Любой недействительный токен дает
+// {"active": false} без объяснения причины.
+func (uc *authUseCase) Introspect(ctx context.Context, token string) (*transport.Introspection, error) {
+	claims, err := uc.VerifyToken(ctx, token)
+	if err != nil {
+		if isInactiveTokenErr(err) {
+			return &transport.Introspection{Active: false}, nil
+		}
+		return nil, err
+	}
+
+	res := &transport.Introspection{
+		Active:    true,
+		Subject:   claims.UserID,
+		UserID:    claims.UserID,
+		Email:     claims.Email,
+		SessionID: claims.SessionID,
+		Roles:     claims.Roles,
+		MFA:       claims.MFA,
+		TokenType: "access_token",
+	}
+	if claims.ExpiresAt != nil {
+		res.ExpiresAt = claims.ExpiresAt.Unix()
+	}
+	if claims.IssuedAt != nil {
+		res.IssuedAt = claims.IssuedAt.Unix()
+	}
+	return res, nil
+}
+
+// isInactiveTokenErr отделяет недействительный токен от сбоя хранилища:
+// ошибки разбора JWT и ErrInvalidToken значат «неактивен».
+func isInactiveTokenErr(err error) bool {
+	if errors.Is(err, domain.ErrInvalidToken) {
+		return true
+	}
+	var jwtErr *jwt.ValidationError
+	return errors.As(err, &jwtErr)
+}
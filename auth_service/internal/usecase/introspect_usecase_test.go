@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"apple_backend/auth_service/internal/domain"
+	mocks "apple_backend/auth_service/internal/usecase/mock"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+)
+
+func TestIntrospect_Active(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	sessions := mocks.NewMockSessionRepository(ctrl)
+	uc := NewAuthUseCase(mocks.NewMockAuthRepository(ctrl), sessions, nil, nil, testSigner(t), Config{})
+
+	token, err := uc.generateToken("u1", "u@ex.com", "s1", []string{"customer"}, false, time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatalf("generate token: %v", err)
+	}
+	sessions.EXPECT().GetSession(gomock.Any(), "s1").Return(&domain.Session{ID: "s1", UserID: "u1"}, nil)
+
+	res, err := uc.Introspect(context.Background(), token)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !res.Active || res.Subject != "u1" || res.SessionID != "s1" || res.ExpiresAt == 0 {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+}
+
+func TestIntrospect_InactiveToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	sessions := mocks.NewMockSessionRepository(ctrl)
+	uc := NewAuthUseCase(mocks.NewMockAuthRepository(ctrl), sessions, nil, nil, testSigner(t), Config{})
+
+	expired, _ := uc.generateToken("u1", "u@ex.com", "s1", nil, false, time.Now().Add(-time.Minute))
+	revoked, _ := uc.generateToken("u1", "u@ex.com", "s2", nil, false, time.Now().Add(time.Minute))
+	revokedAt := time.Now()
+	sessions.EXPECT().GetSession(gomock.Any(), "s2").Return(&domain.Session{ID: "s2", UserID: "u1", RevokedAt: &revokedAt}, nil)
+
+	for _, token := range []string{"garbage", expired, revoked} {
+		res, err := uc.Introspect(context.Background(), token)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if res.Active || res.Subject != "" {
+			t.Fatalf("expected inactive result, got %+v", res)
+		}
+	}
+}
+
+func TestIntrospect_StorageError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	sessions := mocks.NewMockSessionRepository(ctrl)
+	uc := NewAuthUseCase(mocks.NewMockAuthRepository(ctrl), sessions, nil, nil, testSigner(t), Config{})
+
+	token, _ := uc.generateToken("u1", "u@ex.com", "s1", nil, false, time.Now().Add(time.Minute))
+	dbErr := errors.New("db down")
+	sessions.EXPECT().GetSession(gomock.Any(), "s1").Return(nil, dbErr)
+
+	if _, err := uc.Introspect(context.Background(), token); !errors.Is(err, dbErr) {
+		t.Fatalf("expected storage error, got %v", err)
+	}
+}
@@ -0,0 +1,63 @@
+package usecase
+
+import (
+	"apple_backend/auth_service/internal/domain"
+	mocks "apple_backend/auth_service/internal/usecase/mock"
+	"apple_backend/pkg/passcheck"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+)
+
+func TestValidatePasswordSecurity_Reasons(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uc := NewAuthUseCase(mocks.NewMockAuthRepository(ctrl), mocks.NewMockSessionRepository(ctrl), nil, nil, testSigner(t), Config{})
+
+	cases := map[string]string{
+		"Sh0rt!":        passcheck.ReasonTooShort,
+		"alllowercase1": passcheck.ReasonCharacterClasses,
+		"Password1!":    passcheck.ReasonBreached,
+		"Sunshine12!":   passcheck.ReasonCommonPassword,
+		"Zxcvbnm,./1A":  passcheck.ReasonKeyboardSequence,
+		"Gfhjkm-2021!":  passcheck.ReasonTransliteratedWord,
+		"Ivanpetrov1!":  passcheck.ReasonContainsEmail,
+	}
+	for password, reason := range cases {
+		err := uc.validatePasswordSecurity(context.Background(), "ivan.petrov@ex.com", password)
+		var weak *domain.WeakPasswordError
+		if !errors.As(err, &weak) {
+			t.Errorf("%q: expected WeakPasswordError, got %v", password, err)
+			continue
+		}
+		if weak.Reason != reason {
+			t.Errorf("%q: expected reason %s, got %s (score %d)", password, reason, weak.Reason, weak.Score)
+		}
+		if !errors.Is(err, domain.ErrWeakPassword) {
+			t.Errorf("%q: expected error to wrap ErrWeakPassword", password)
+		}
+	}
+
+	if err := uc.validatePasswordSecurity(context.Background(), "ivan.petrov@ex.com", "N3w!Harb0r-Lantern"); err != nil {
+		t.Fatalf("expected strong password to pass, got %v", err)
+	}
+}
+
+type failingBreachList struct{}
+
+func (failingBreachList) Breached(string) (bool, error) { return false, errors.New("disk error") }
+
+func TestValidatePasswordSecurity_BreachListErrorIgnored(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uc := NewAuthUseCase(mocks.NewMockAuthRepository(ctrl), mocks.NewMockSessionRepository(ctrl), nil, nil, testSigner(t),
+		Config{BreachedPasswords: failingBreachList{}})
+
+	if err := uc.validatePasswordSecurity(context.Background(), "u@ex.com", "N3w!Harb0r-Lantern"); err != nil {
+		t.Fatalf("expected password accepted, got %v", err)
+	}
+}
@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"apple_backend/auth_service/internal/domain"
+	"apple_backend/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const passwordResetTTL = time.Hour
+
+// ForgotPassword отправляет ссылку для сброса пароля. Для неизвестного email
+// ничего не происходит, чтобы по ответу нельзя было перебирать аккаунты.
+// Попытка учитывается до поиска пользователя: лимит срабатывает одинаково
+// для существующих и несуществующих адресов.
+func (uc *authUseCase) ForgotPassword(ctx context.Context, email string) error {
+	email = strings.TrimSpace(email)
+	if err := uc.validateEmailFormat(email); err != nil {
+		return err
+	}
+
+	keys := forgotPasswordThrottleKeys(ctx, email)
+	if err := uc.checkThrottle(ctx, keys); err != nil {
+		return err
+	}
+	if err := uc.registerAttempts(ctx, keys); err != nil {
+		return err
+	}
+
+	user, err := uc.repo.GetUserByEmail(ctx, email)
+	if errors.Is(err, domain.ErrUserNotFound) {
+		logger.FromContext(ctx).InfoContext(ctx, "usecase ForgotPassword unknown email")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	token, err := generateSecureToken()
+	if err != nil {
+		return err
+	}
+	if err := uc.repo.CreatePasswordReset(ctx, user.ID, hashToken(token), time.Now().Add(passwordResetTTL)); err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/password/reset?token=%s", uc.appURL, url.QueryEscape(token))
+	body := fmt.Sprintf(
+		"Здравствуйте!\n\nЧтобы задать новый пароль, перейдите по ссылке:\n%s\n\n"+
+			"Ссылка действует %d минут и может быть использована один раз.\n"+
+			"Если вы не запрашивали сброс пароля, просто проигнорируйте это письмо.",
+		link, int(passwordResetTTL.Minutes()),
+	)
+	if err := uc.mailer.Send(ctx, user.Email, "Восстановление пароля", body); err != nil {
+		logger.FromContext(ctx).ErrorContext(ctx, "usecase ForgotPassword send failed",
+			slog.Any("err", err), slog.String("user_id", user.ID))
+		// ответ не должен отличаться от ответа для неизвестного email
+		return nil
+	}
+	uc.recordEvent(ctx, domain.AuthEvent{UserID: user.ID, Type: domain.EventPasswordResetSent, Identifier: user.Email})
+	return nil
+}
+
+// ResetPassword задает новый пароль по токену из письма и завершает все
+// сессии пользователя.
+func (uc *authUseCase) ResetPassword(ctx context.Context, token, newPassword string) error {
+	if token == "" {
+		return domain.ErrResetTokenInvalid
+	}
+	tokenHash := hashToken(token)
+
+	userID, err := uc.repo.GetPasswordResetUserID(ctx, tokenHash)
+	if err != nil {
+		return err
+	}
+	user, err := uc.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := uc.validatePasswordSecurity(ctx, user.Email, newPassword); err != nil {
+		return err
+	}
+
+	hashed, err := uc.hashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+	if _, err := uc.repo.ResetPassword(ctx, tokenHash, hashed); err != nil {
+		return err
+	}
+	if err := uc.sessions.RevokeUserSessions(ctx, user.ID); err != nil {
+		return err
+	}
+	uc.recordEvent(ctx, domain.AuthEvent{UserID: user.ID, Type: domain.EventPasswordReset})
+	return nil
+}
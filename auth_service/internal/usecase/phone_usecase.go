@@ -0,0 +1,219 @@
+package usecase
+
+import (
+	"apple_backend/auth_service/internal/delivery/transport"
+	"apple_backend/auth_service/internal/domain"
+	"apple_backend/pkg/logger"
+	"apple_backend/pkg/trace"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"strings"
+	"time"
+)
+
+const (
+	phoneCodeTTL            = 5 * time.Minute
+	phoneCodeResendInterval = time.Minute
+	phoneCodeMaxAttempts    = 5
+	phoneCodeDigits         = 6
+)
+
+var (
+	// SMS платные, поэтому на номер — не больше 5 кодов в час
+	phoneCodeRule = throttleRule{prefix: "otp:phone:", window: time.Hour, free: 5, lockAfter: 5, lockout: time.Hour}
+	// с одного IP — против рассылки кодов на множество чужих номеров
+	phoneCodeIPRule = throttleRule{prefix: "otp:ip:", window: time.Hour, free: 20, lockAfter: 20, lockout: time.Hour}
+	// неверные коды с одного IP — против перебора по множеству номеров,
+	// перебор одного кода ограничен phoneCodeMaxAttempts
+	phoneVerifyIPRule = throttleRule{prefix: "otp:verify:ip:", window: 15 * time.Minute, free: 20, lockAfter: 50, lockout: 15 * time.Minute}
+)
+
+// RequestPhoneCode отправляет одноразовый код для входа. Повторно код можно
+// запросить не раньше чем через минуту; новый код заменяет прежний.
+func (uc *authUseCase) RequestPhoneCode(ctx context.Context, phone string) (*transport.PhoneCodeSent, error) {
+	if uc.sms == nil {
+		return nil, domain.ErrPhoneLoginDisabled
+	}
+	phone, err := normalizePhone(phone)
+	if err != nil {
+		return nil, err
+	}
+	keys := phoneCodeThrottleKeys(ctx, phone)
+	if err := uc.checkThrottle(ctx, keys); err != nil {
+		return nil, err
+	}
+
+	code, err := generatePhoneCode()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	expires := now.Add(phoneCodeTTL)
+	err = uc.phones.CreatePhoneCode(ctx, phone, hashPhoneCode(phone, code), expires, phoneCodeResendInterval)
+	if errors.Is(err, domain.ErrPhoneCodeTooSoon) {
+		return nil, &domain.ThrottledError{RetryAfter: phoneCodeResendInterval}
+	}
+	if err != nil {
+		return nil, err
+	}
+	// учитываем только реально отправленные коды
+	if err := uc.registerAttempts(ctx, keys); err != nil {
+		return nil, err
+	}
+
+	text := fmt.Sprintf("Код для входа: %s. Никому не сообщайте его.", code)
+	if err := uc.sms.Send(ctx, phone, text); err != nil {
+		logger.FromContext(ctx).ErrorContext(ctx, "usecase RequestPhoneCode sms not sent", slog.Any("err", err))
+		// иначе повторный запрос упрется в интервал, хотя код не дошел
+		if err := uc.phones.DeletePhoneCode(ctx, phone); err != nil {
+			logger.FromContext(ctx).ErrorContext(ctx, "usecase RequestPhoneCode delete code failed", slog.Any("err", err))
+		}
+		return nil, err
+	}
+	return &transport.PhoneCodeSent{
+		Phone:       phone,
+		ExpiresAt:   expires,
+		ResendAfter: now.Add(phoneCodeResendInterval),
+	}, nil
+}
+
+// LoginByPhone проверяет код и входит в аккаунт с этим номером, а если
+// такого нет — создает новый. Номер, указанный в профиле без подтверждения,
+// аккаунт не находит: войти в него кодом нельзя.
+func (uc *authUseCase) LoginByPhone(ctx context.Context, phone, code string) (*transport.AuthResult, error) {
+	if uc.sms == nil {
+		return nil, domain.ErrPhoneLoginDisabled
+	}
+	phone, err := normalizePhone(phone)
+	if err != nil {
+		return nil, err
+	}
+	code = strings.TrimSpace(code)
+	if len(code) != phoneCodeDigits {
+		return nil, domain.ErrPhoneCodeInvalid
+	}
+	keys := phoneVerifyThrottleKeys(ctx)
+	if err := uc.checkThrottle(ctx, keys); err != nil {
+		return nil, err
+	}
+
+	codeHash, err := uc.phones.UsePhoneCodeAttempt(ctx, phone, phoneCodeMaxAttempts)
+	if err != nil {
+		if errors.Is(err, domain.ErrPhoneCodeInvalid) {
+			uc.loginFailed(ctx, keys)
+			uc.loginFailedEvent(ctx, "", domain.MethodPhone, phone)
+		}
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(codeHash), []byte(hashPhoneCode(phone, code))) != 1 {
+		uc.loginFailed(ctx, keys)
+		uc.loginFailedEvent(ctx, "", domain.MethodPhone, phone)
+		return nil, domain.ErrPhoneCodeInvalid
+	}
+	if err := uc.phones.ConsumePhoneCode(ctx, phone, codeHash); err != nil {
+		return nil, err
+	}
+
+	user, err := uc.phoneUser(ctx, phone)
+	if err != nil {
+		return nil, err
+	}
+	challenge, err := uc.loginChallenge(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	if challenge != nil {
+		return challenge, nil
+	}
+	return uc.loginSession(ctx, user, false, domain.MethodPhone)
+}
+
+// phoneUser находит аккаунт по подтвержденному номеру или создает новый.
+// Если аккаунт параллельно создал другой запрос, берется он.
+func (uc *authUseCase) phoneUser(ctx context.Context, phone string) (*domain.User, error) {
+	user, err := uc.phones.GetUserByPhone(ctx, phone)
+	if !errors.Is(err, domain.ErrUserNotFound) {
+		return user, err
+	}
+	user, err = uc.phones.CreatePhoneUser(ctx, phone)
+	if errors.Is(err, domain.ErrUserAlreadyExists) {
+		return uc.phones.GetUserByPhone(ctx, phone)
+	}
+	if err != nil {
+		return nil, err
+	}
+	logger.FromContext(ctx).InfoContext(ctx, "usecase LoginByPhone account created", slog.String("user_id", user.ID))
+	uc.recordEvent(ctx, domain.AuthEvent{UserID: user.ID, Type: domain.EventSignup, Method: domain.MethodPhone, Identifier: phone})
+	return user, nil
+}
+
+// CleanupPhoneCodes удаляет истекшие коды.
+func (uc *authUseCase) CleanupPhoneCodes(ctx context.Context) error {
+	return uc.phones.DeleteExpiredPhoneCodes(ctx)
+}
+
+// normalizePhone приводит номер к цифрам в международном формате, как он
+// хранится в account.phone. Российские 8XXXXXXXXXX и 10-значные 9XXXXXXXXX
+// дополняются кодом страны 7.
+func normalizePhone(phone string) (string, error) {
+	var b strings.Builder
+	for i, r := range strings.TrimSpace(phone) {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == '+' && i == 0:
+		case r == ' ' || r == '-' || r == '(' || r == ')':
+		default:
+			return "", domain.ErrInvalidPhone
+		}
+	}
+	digits := b.String()
+	switch {
+	case len(digits) == 11 && digits[0] == '8':
+		digits = "7" + digits[1:]
+	case len(digits) == 10 && digits[0] == '9':
+		digits = "7" + digits
+	}
+	if len(digits) < 11 || len(digits) > 15 || digits[0] == '0' {
+		return "", domain.ErrInvalidPhone
+	}
+	return digits, nil
+}
+
+func generatePhoneCode() (string, error) {
+	max := big.NewInt(1)
+	for i := 0; i < phoneCodeDigits; i++ {
+		max.Mul(max, big.NewInt(10))
+	}
+	n, err := rand.Int(rand.Reader, max)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%0*d", phoneCodeDigits, n), nil
+}
+
+// hashPhoneCode привязывает хэш к номеру, чтобы одинаковые коды разных
+// номеров не совпадали в БД
+func hashPhoneCode(phone, code string) string {
+	return hashToken(phone + ":" + code)
+}
+
+func phoneCodeThrottleKeys(ctx context.Context, phone string) []throttleKey {
+	keys := []throttleKey{{phoneCodeRule, phone}}
+	if ip := trace.GetClientIP(ctx); ip != "" {
+		keys = append(keys, throttleKey{phoneCodeIPRule, ip})
+	}
+	return keys
+}
+
+func phoneVerifyThrottleKeys(ctx context.Context) []throttleKey {
+	if ip := trace.GetClientIP(ctx); ip != "" {
+		return []throttleKey{{phoneVerifyIPRule, ip}}
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package usecase
+
+import (
+	"apple_backend/auth_service/internal/domain"
+	mocks "apple_backend/auth_service/internal/usecase/mock"
+	"apple_backend/pkg/rbac"
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+)
+
+func TestNormalizePhone(t *testing.T) {
+	tests := []struct {
+		in, want string
+		err      error
+	}{
+		{in: "+7 (916) 123-45-67", want: "79161234567"},
+		{in: "89161234567", want: "79161234567"},
+		{in: "9161234567", want: "79161234567"},
+		{in: "+44 20 7946 0958", want: "442079460958"},
+		{in: "12345", err: domain.ErrInvalidPhone},
+		{in: "+7 916 abc", err: domain.ErrInvalidPhone},
+		{in: "7916+1234567", err: domain.ErrInvalidPhone},
+	}
+	for _, tt := range tests {
+		got, err := normalizePhone(tt.in)
+		if err != tt.err || got != tt.want {
+			t.Errorf("normalizePhone(%q) = %q, %v; want %q, %v", tt.in, got, err, tt.want, tt.err)
+		}
+	}
+}
+
+func TestRequestPhoneCode_SendsCode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	phones := mocks.NewMockPhoneRepository(ctrl)
+	sender := mocks.NewMockSMSSender(ctrl)
+	uc := NewAuthUseCase(nil, nil, nil, nil, testSigner(t), Config{SMS: sender, Phones: phones})
+
+	var storedHash string
+	phones.EXPECT().
+		CreatePhoneCode(gomock.Any(), "79161234567", gomock.Any(), gomock.Any(), phoneCodeResendInterval).
+		DoAndReturn(func(_ context.Context, _, codeHash string, _ time.Time, _ time.Duration) error {
+			storedHash = codeHash
+			return nil
+		})
+	sender.EXPECT().
+		Send(gomock.Any(), "79161234567", gomock.Any()).
+		DoAndReturn(func(_ context.Context, phone, text string) error {
+			code := regexp.MustCompile(`\d{6}`).FindString(text)
+			if code == "" || hashPhoneCode(phone, code) != storedHash {
+				t.Fatalf("sms does not contain stored code: %q", text)
+			}
+			return nil
+		})
+
+	res, err := uc.RequestPhoneCode(context.Background(), "8 916 123 45 67")
+	if err != nil || res.Phone != "79161234567" || !res.ExpiresAt.After(res.ResendAfter) {
+		t.Fatalf("unexpected result: err=%v res=%+v", err, res)
+	}
+}
+
+func TestRequestPhoneCode_TooSoon(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	phones := mocks.NewMockPhoneRepository(ctrl)
+	sender := mocks.NewMockSMSSender(ctrl)
+	uc := NewAuthUseCase(nil, nil, nil, nil, testSigner(t), Config{SMS: sender, Phones: phones})
+
+	phones.EXPECT().
+		CreatePhoneCode(gomock.Any(), "79161234567", gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(domain.ErrPhoneCodeTooSoon)
+
+	_, err := uc.RequestPhoneCode(context.Background(), "+79161234567")
+	var throttled *domain.ThrottledError
+	if !errors.As(err, &throttled) || throttled.RetryAfter <= 0 {
+		t.Fatalf("expected ThrottledError, got %v", err)
+	}
+}
+
+func TestRequestPhoneCode_PerNumberLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	phones := mocks.NewMockPhoneRepository(ctrl)
+	throttle := mocks.NewMockThrottleRepository(ctrl)
+	sender := mocks.NewMockSMSSender(ctrl)
+	uc := NewAuthUseCase(nil, nil, throttle, nil, testSigner(t), Config{SMS: sender, Phones: phones})
+
+	throttle.EXPECT().LockedUntil(gomock.Any(), "otp:phone:79161234567").Return(nil, nil)
+	phones.EXPECT().CreatePhoneCode(gomock.Any(), "79161234567", gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+	throttle.EXPECT().Hit(gomock.Any(), "otp:phone:79161234567", time.Hour).Return(phoneCodeRule.lockAfter, nil)
+	throttle.EXPECT().Lock(gomock.Any(), "otp:phone:79161234567", gomock.Any()).Return(nil)
+	sender.EXPECT().Send(gomock.Any(), "79161234567", gomock.Any()).Return(nil)
+
+	if _, err := uc.RequestPhoneCode(context.Background(), "+79161234567"); err != nil {
+		t.Fatalf("last allowed code must be sent: %v", err)
+	}
+}
+
+func TestLoginByPhone_CreatesAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	roles := mocks.NewMockRoleRepository(ctrl)
+	phones := mocks.NewMockPhoneRepository(ctrl)
+	sessions := mocks.NewMockSessionRepository(ctrl)
+	uc := NewAuthUseCase(nil, sessions, nil, nil, testSigner(t), Config{SMS: mocks.NewMockSMSSender(ctrl), Roles: roles, Phones: phones})
+
+	codeHash := hashPhoneCode("79161234567", "123456")
+	phones.EXPECT().UsePhoneCodeAttempt(gomock.Any(), "79161234567", phoneCodeMaxAttempts).Return(codeHash, nil)
+	phones.EXPECT().ConsumePhoneCode(gomock.Any(), "79161234567", codeHash).Return(nil)
+	phones.EXPECT().GetUserByPhone(gomock.Any(), "79161234567").Return(nil, domain.ErrUserNotFound)
+	phones.EXPECT().CreatePhoneUser(gomock.Any(), "79161234567").Return(&domain.User{ID: "u1"}, nil)
+	roles.EXPECT().GetUserRoles(gomock.Any(), "u1").Return([]string{rbac.RoleCustomer}, nil).AnyTimes()
+	sessions.EXPECT().
+		CreateSession(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&domain.Session{ID: "s1", UserID: "u1"}, nil)
+
+	res, err := uc.LoginByPhone(context.Background(), "+7 916 123-45-67", "123456")
+	if err != nil || res.UserID != "u1" || res.Token == "" || res.RefreshToken == "" {
+		t.Fatalf("login failed: err=%v res=%+v", err, res)
+	}
+}
+
+func TestLoginByPhone_WrongCode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	phones := mocks.NewMockPhoneRepository(ctrl)
+	uc := NewAuthUseCase(nil, nil, nil, nil, testSigner(t), Config{SMS: mocks.NewMockSMSSender(ctrl), Phones: phones})
+
+	phones.EXPECT().
+		UsePhoneCodeAttempt(gomock.Any(), "79161234567", phoneCodeMaxAttempts).
+		Return(hashPhoneCode("79161234567", "123456"), nil)
+
+	if _, err := uc.LoginByPhone(context.Background(), "79161234567", "654321"); err != domain.ErrPhoneCodeInvalid {
+		t.Fatalf("expected ErrPhoneCodeInvalid, got %v", err)
+	}
+}
+
+func TestLoginByPhone_AttemptsExhausted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	phones := mocks.NewMockPhoneRepository(ctrl)
+	uc := NewAuthUseCase(nil, nil, nil, nil, testSigner(t), Config{SMS: mocks.NewMockSMSSender(ctrl), Phones: phones})
+
+	// код истек или попытки кончились: хранилище не отдает хэш
+	phones.EXPECT().
+		UsePhoneCodeAttempt(gomock.Any(), "79161234567", phoneCodeMaxAttempts).
+		Return("", domain.ErrPhoneCodeInvalid)
+
+	if _, err := uc.LoginByPhone(context.Background(), "79161234567", "123456"); err != domain.ErrPhoneCodeInvalid {
+		t.Fatalf("expected ErrPhoneCodeInvalid, got %v", err)
+	}
+}
+
+func TestLoginByPhone_Disabled(t *testing.T) {
+	uc := NewAuthUseCase(nil, nil, nil, nil, testSigner(t), Config{})
+	if _, err := uc.RequestPhoneCode(context.Background(), "79161234567"); err != domain.ErrPhoneLoginDisabled {
+		t.Fatalf("expected ErrPhoneLoginDisabled, got %v", err)
+	}
+}
@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"apple_backend/auth_service/internal/domain"
+	"apple_backend/pkg/logger"
+	"apple_backend/pkg/rbac"
+	"context"
+	"log/slog"
+)
+
+// effectiveRoles — роли, которые попадут в access-токен. Роли, требующие
+// второго фактора, выдаются только сессиям, где он подтвержден.
+func (uc *authUseCase) effectiveRoles(ctx context.Context, userID string, mfa bool) ([]string, error) {
+	roles, err := uc.roles.GetUserRoles(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if mfa || len(uc.twoFactorRoles) == 0 {
+		return roles, nil
+	}
+	effective := make([]string, 0, len(roles))
+	for _, role := range roles {
+		if !uc.twoFactorRoles[role] {
+			effective = append(effective, role)
+		}
+	}
+	return effective, nil
+}
+
+func (uc *authUseCase) ListUserRoles(ctx context.Context, userID string) ([]string, error) {
+	if _, err := uc.repo.GetUserByID(ctx, userID); err != nil {
+		return nil, err
+	}
+	return uc.roles.GetUserRoles(ctx, userID)
+}
+
+// GrantRole выдает роль. Новая роль появится в токене после ближайшего refresh.
+func (uc *authUseCase) GrantRole(ctx context.Context, actorID, userID, role string) error {
+	if !rbac.IsKnownRole(role) {
+		return domain.ErrUnknownRole
+	}
+	if err := uc.roles.GrantRole(ctx, userID, role); err != nil {
+		return err
+	}
+	logger.FromContext(ctx).InfoContext(ctx, "usecase GrantRole success",
+		slog.String("actor_id", actorID), slog.String("user_id", userID), slog.String("role", role))
+	return nil
+}
+
+// RevokeRole снимает роль и завершает сессии пользователя, чтобы уже
+// выданные токены с этой ролью перестали действовать сразу.
+func (uc *authUseCase) RevokeRole(ctx context.Context, actorID, userID, role string) error {
+	if !rbac.IsKnownRole(role) {
+		return domain.ErrUnknownRole
+	}
+	if role == rbac.RoleAdmin && actorID == userID {
+		return domain.ErrRevokeOwnAdmin
+	}
+	if err := uc.roles.RevokeRole(ctx, userID, role); err != nil {
+		return err
+	}
+	logger.FromContext(ctx).InfoContext(ctx, "usecase RevokeRole success",
+		slog.String("actor_id", actorID), slog.String("user_id", userID), slog.String("role", role))
+	return uc.sessions.RevokeUserSessions(ctx, userID)
+}
+
+// BootstrapAdmin выдает роль администратора аккаунту с указанным email.
+// Нужен, чтобы в новой установке появился первый администратор.
+func (uc *authUseCase) BootstrapAdmin(ctx context.Context, email string) error {
+	granted, err := uc.roles.GrantRoleByEmail(ctx, email, rbac.RoleAdmin)
+	if err != nil {
+		return err
+	}
+	if !granted {
+		logger.FromContext(ctx).WarnContext(ctx, "usecase BootstrapAdmin account not found or already admin", slog.String("email", email))
+	}
+	return nil
+}
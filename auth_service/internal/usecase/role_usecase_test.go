@@ -0,0 +1,60 @@
+package usecase
+
+import (
+	"apple_backend/auth_service/internal/domain"
+	mocks "apple_backend/auth_service/internal/usecase/mock"
+	"apple_backend/pkg/rbac"
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+)
+
+func TestEffectiveRoles_DropsTwoFactorRolesWithoutMFA(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	roles := mocks.NewMockRoleRepository(ctrl)
+	uc := NewAuthUseCase(nil, nil, nil, nil, testSigner(t), Config{TwoFactorRoles: []string{rbac.RoleAdmin}, Roles: roles})
+
+	roles.EXPECT().GetUserRoles(gomock.Any(), "u1").Return([]string{rbac.RoleAdmin, rbac.RoleCustomer}, nil).Times(2)
+
+	got, err := uc.effectiveRoles(context.Background(), "u1", false)
+	if err != nil || len(got) != 1 || got[0] != rbac.RoleCustomer {
+		t.Fatalf("without mfa: roles=%v err=%v", got, err)
+	}
+	got, err = uc.effectiveRoles(context.Background(), "u1", true)
+	if err != nil || len(got) != 2 {
+		t.Fatalf("with mfa: roles=%v err=%v", got, err)
+	}
+}
+
+func TestGrantRole_Unknown(t *testing.T) {
+	uc := NewAuthUseCase(nil, nil, nil, nil, testSigner(t), Config{})
+	if err := uc.GrantRole(context.Background(), "admin", "u1", "superuser"); err != domain.ErrUnknownRole {
+		t.Fatalf("expected ErrUnknownRole, got %v", err)
+	}
+}
+
+func TestRevokeRole_OwnAdmin(t *testing.T) {
+	uc := NewAuthUseCase(nil, nil, nil, nil, testSigner(t), Config{})
+	if err := uc.RevokeRole(context.Background(), "a1", "a1", rbac.RoleAdmin); err != domain.ErrRevokeOwnAdmin {
+		t.Fatalf("expected ErrRevokeOwnAdmin, got %v", err)
+	}
+}
+
+func TestRevokeRole_RevokesSessions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	roles := mocks.NewMockRoleRepository(ctrl)
+	sessions := mocks.NewMockSessionRepository(ctrl)
+	uc := NewAuthUseCase(nil, sessions, nil, nil, testSigner(t), Config{Roles: roles})
+
+	roles.EXPECT().RevokeRole(gomock.Any(), "u1", rbac.RoleStoreStaff).Return(nil)
+	sessions.EXPECT().RevokeUserSessions(gomock.Any(), "u1").Return(nil)
+
+	if err := uc.RevokeRole(context.Background(), "a1", "u1", rbac.RoleStoreStaff); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,182 @@
+package usecase
+
+import (
+	"apple_backend/auth_service/internal/domain"
+	"apple_backend/pkg/logger"
+	"apple_backend/pkg/trace"
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// throttleRule — ограничение попыток по одному ключу. Первые free попыток в
+// окне проходят без задержки, дальше каждая следующая блокирует ключ на
+// удваивающееся время (1s, 2s, 4s, ...), а после lockAfter попыток — на lockout.
+type throttleRule struct {
+	prefix    string
+	window    time.Duration
+	free      int
+	lockAfter int
+	lockout   time.Duration
+}
+
+var (
+	// пара IP+email — основной ключ против подбора пароля к одному аккаунту
+	loginPairRule = throttleRule{prefix: "login:pair:", window: 15 * time.Minute, free: 3, lockAfter: 10, lockout: 15 * time.Minute}
+	// email отдельно — против распределенного подбора с разных IP; порог
+	// выше, чтобы чужие попытки не блокировали владельца слишком легко
+	loginEmailRule = throttleRule{prefix: "login:email:", window: 15 * time.Minute, free: 10, lockAfter: 50, lockout: 15 * time.Minute}
+	// IP отдельно — против перебора множества аккаунтов с одного адреса
+	loginIPRule = throttleRule{prefix: "login:ip:", window: 15 * time.Minute, free: 20, lockAfter: 100, lockout: 15 * time.Minute}
+
+	signupEmailRule = throttleRule{prefix: "signup:email:", window: time.Hour, free: 3, lockAfter: 10, lockout: time.Hour}
+	signupIPRule    = throttleRule{prefix: "signup:ip:", window: time.Hour, free: 10, lockAfter: 30, lockout: time.Hour}
+
+	// письма для сброса пароля — против засыпания чужого ящика ссылками
+	forgotEmailRule = throttleRule{prefix: "forgot:email:", window: time.Hour, free: 3, lockAfter: 10, lockout: time.Hour}
+	forgotIPRule    = throttleRule{prefix: "forgot:ip:", window: time.Hour, free: 10, lockAfter: 30, lockout: time.Hour}
+
+	// повторный ввод пароля в уже открытой сессии: украденная сессия не
+	// должна давать перебирать пароль для смены пароля или email
+	reauthRule = throttleRule{prefix: "reauth:user:", window: 15 * time.Minute, free: 5, lockAfter: 10, lockout: 15 * time.Minute}
+	// письма на новый адрес — против рассылки через смену email
+	emailChangeRule = throttleRule{prefix: "email_change:user:", window: time.Hour, free: 3, lockAfter: 10, lockout: time.Hour}
+)
+
+// staleThrottleAge — через сколько неиспользуемые счетчики можно удалять
+const staleThrottleAge = 24 * time.Hour
+
+type throttleKey struct {
+	rule  throttleRule
+	value string
+}
+
+func (k throttleKey) String() string { return k.rule.prefix + k.value }
+
+func (r throttleRule) delay(attempts int) time.Duration {
+	if attempts >= r.lockAfter {
+		return r.lockout
+	}
+	if attempts <= r.free {
+		return 0
+	}
+	d := time.Second << uint(attempts-r.free-1)
+	if d <= 0 || d > r.lockout {
+		return r.lockout
+	}
+	return d
+}
+
+func loginThrottleKeys(ctx context.Context, email string) []throttleKey {
+	email = strings.ToLower(strings.TrimSpace(email))
+	keys := []throttleKey{{loginEmailRule, email}}
+	if ip := trace.GetClientIP(ctx); ip != "" {
+		keys = append(keys, throttleKey{loginPairRule, ip + "|" + email}, throttleKey{loginIPRule, ip})
+	}
+	return keys
+}
+
+func signupThrottleKeys(ctx context.Context, email string) []throttleKey {
+	keys := []throttleKey{{signupEmailRule, strings.ToLower(strings.TrimSpace(email))}}
+	if ip := trace.GetClientIP(ctx); ip != "" {
+		keys = append(keys, throttleKey{signupIPRule, ip})
+	}
+	return keys
+}
+
+func forgotPasswordThrottleKeys(ctx context.Context, email string) []throttleKey {
+	keys := []throttleKey{{forgotEmailRule, strings.ToLower(email)}}
+	if ip := trace.GetClientIP(ctx); ip != "" {
+		keys = append(keys, throttleKey{forgotIPRule, ip})
+	}
+	return keys
+}
+
+// checkThrottle возвращает *domain.ThrottledError, если хотя бы один ключ
+// сейчас заблокирован.
+func (uc *authUseCase) checkThrottle(ctx context.Context, keys []throttleKey) error {
+	if uc.throttle == nil || len(keys) == 0 {
+		return nil
+	}
+	names := make([]string, len(keys))
+	for i, k := range keys {
+		names[i] = k.String()
+	}
+	until, err := uc.throttle.LockedUntil(ctx, names...)
+	if err != nil {
+		return err
+	}
+	if until == nil {
+		return nil
+	}
+	wait := time.Until(*until)
+	if wait <= 0 {
+		return nil
+	}
+	logger.FromContext(ctx).WarnContext(ctx, "usecase throttle rejected", slog.Duration("retry_after", wait))
+	return &domain.ThrottledError{RetryAfter: wait}
+}
+
+// registerAttempts учитывает попытку по всем ключам и блокирует те, что
+// превысили порог.
+func (uc *authUseCase) registerAttempts(ctx context.Context, keys []throttleKey) error {
+	if uc.throttle == nil {
+		return nil
+	}
+	now := time.Now()
+	for _, k := range keys {
+		attempts, err := uc.throttle.Hit(ctx, k.String(), k.rule.window)
+		if err != nil {
+			return err
+		}
+		if d := k.rule.delay(attempts); d > 0 {
+			if err := uc.throttle.Lock(ctx, k.String(), now.Add(d)); err != nil {
+				return err
+			}
+		}
+		if attempts == k.rule.lockAfter {
+			uc.recordEvent(ctx, domain.AuthEvent{Type: domain.EventLockout, Identifier: k.String()})
+		}
+	}
+	return nil
+}
+
+// loginFailed учитывает неудачный вход. Ошибка хранилища не должна менять
+// ответ пользователю, поэтому только логируется.
+func (uc *authUseCase) loginFailed(ctx context.Context, keys []throttleKey) {
+	if err := uc.registerAttempts(ctx, keys); err != nil {
+		logger.FromContext(ctx).ErrorContext(ctx, "usecase Login throttle update failed", slog.Any("err", err))
+	}
+}
+
+// loginSucceeded сбрасывает счетчики, привязанные к email. Счетчик IP не
+// сбрасывается: иначе вход в свой аккаунт обнулял бы перебор чужих.
+func (uc *authUseCase) loginSucceeded(ctx context.Context, keys []throttleKey) {
+	reset := make([]throttleKey, 0, len(keys))
+	for _, k := range keys {
+		if k.rule.prefix != loginIPRule.prefix {
+			reset = append(reset, k)
+		}
+	}
+	uc.resetThrottle(ctx, reset)
+}
+
+func (uc *authUseCase) resetThrottle(ctx context.Context, keys []throttleKey) {
+	if uc.throttle == nil {
+		return
+	}
+	for _, k := range keys {
+		if err := uc.throttle.Reset(ctx, k.String()); err != nil {
+			logger.FromContext(ctx).ErrorContext(ctx, "usecase throttle reset failed", slog.Any("err", err))
+		}
+	}
+}
+
+// CleanupThrottle удаляет устаревшие счетчики попыток.
+func (uc *authUseCase) CleanupThrottle(ctx context.Context) error {
+	if uc.throttle == nil {
+		return nil
+	}
+	return uc.throttle.DeleteStale(ctx, staleThrottleAge)
+}
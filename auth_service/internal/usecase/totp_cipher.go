@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+)
+
+// Секрет TOTP хранится в базе зашифрованным AES-256-GCM ключом из конфига
+// auth_service: по дампу базы нельзя генерировать коды. id пользователя
+// входит в AAD, поэтому зашифрованный секрет не переносится в чужой аккаунт.
+const totpSealedPrefix = "v1:"
+
+var errTOTPSecretInvalid = errors.New("totp: secret cannot be decrypted")
+
+type totpCipher struct {
+	key [32]byte
+}
+
+func newTOTPCipher(secret string) *totpCipher {
+	return &totpCipher{key: sha256.Sum256([]byte(secret))}
+}
+
+func (c *totpCipher) aead() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(c.key[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal шифрует секрет для записи в account.totp_secret
+func (c *totpCipher) seal(userID, secret string) (string, error) {
+	aead, err := c.aead()
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(secret), []byte(userID))
+	return totpSealedPrefix + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// open расшифровывает секрет из базы. Незашифрованное значение, чужой ключ
+// и измененные данные дают errTOTPSecretInvalid.
+func (c *totpCipher) open(userID, stored string) (string, error) {
+	raw, ok := strings.CutPrefix(stored, totpSealedPrefix)
+	if !ok {
+		return "", errTOTPSecretInvalid
+	}
+	data, err := base64.RawStdEncoding.DecodeString(raw)
+	if err != nil {
+		return "", errTOTPSecretInvalid
+	}
+	aead, err := c.aead()
+	if err != nil {
+		return "", err
+	}
+	if len(data) < aead.NonceSize() {
+		return "", errTOTPSecretInvalid
+	}
+	secret, err := aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], []byte(userID))
+	if err != nil {
+		return "", errTOTPSecretInvalid
+	}
+	return string(secret), nil
+}
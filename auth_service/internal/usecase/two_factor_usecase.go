@@ -0,0 +1,343 @@
+package usecase
+
+import (
+	"apple_backend/auth_service/internal/delivery/transport"
+	"apple_backend/auth_service/internal/domain"
+	"apple_backend/pkg/logger"
+	"context"
+	"crypto/rand"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	twoFactorChallengeTTL     = 5 * time.Minute
+	twoFactorChallengePurpose = "2fa_challenge"
+
+	recoveryCodeCount = 10
+	recoveryCodeLen   = 10
+)
+
+// перебор 6-значного кода ограничиваем отдельно от пароля
+var twoFactorRule = throttleRule{prefix: "2fa:user:", window: 15 * time.Minute, free: 5, lockAfter: 10, lockout: 15 * time.Minute}
+
+type twoFactorChallengeClaims struct {
+	UserID  string `json:"user_id"`
+	Purpose string `json:"purpose"`
+	Setup   bool   `json:"setup,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// LoginTwoFactor завершает вход: обменивает challenge-токен и код из
+// приложения (или код восстановления) на обычную сессию.
+func (uc *authUseCase) LoginTwoFactor(ctx context.Context, challengeToken, code string) (*transport.AuthResult, error) {
+	claims, err := uc.parseChallenge(challengeToken)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Setup {
+		return nil, domain.ErrTwoFactorNotEnrolled
+	}
+	user, err := uc.repo.GetUserByID(ctx, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.TwoFactorEnabled() {
+		return nil, domain.ErrTwoFactorNotEnrolled
+	}
+	if err := uc.verifySecondFactor(ctx, user.ID, code); err != nil {
+		if errors.Is(err, domain.ErrTwoFactorCodeInvalid) {
+			uc.loginFailedEvent(ctx, user.ID, domain.MethodTOTP, "")
+		}
+		return nil, err
+	}
+	return uc.loginSession(ctx, user, true, domain.MethodTOTP)
+}
+
+// ChallengeUser возвращает пользователя по challenge-токену, выданному для
+// обязательной настройки 2FA.
+func (uc *authUseCase) ChallengeUser(ctx context.Context, challengeToken string) (string, error) {
+	claims, err := uc.parseChallenge(challengeToken)
+	if err != nil {
+		return "", err
+	}
+	if !claims.Setup {
+		return "", domain.ErrChallengeInvalid
+	}
+	return claims.UserID, nil
+}
+
+// EnrollTwoFactor создает новый секрет. 2FA включится только после
+// подтверждения кодом, до этого секрет можно перевыпускать.
+func (uc *authUseCase) EnrollTwoFactor(ctx context.Context, userID string) (*transport.TwoFactorEnrollment, error) {
+	user, err := uc.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TwoFactorEnabled() {
+		return nil, domain.ErrTwoFactorAlreadyEnabled
+	}
+	secret, err := generateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := uc.totpCipher.seal(user.ID, secret)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.twoFactor.SetPendingTOTP(ctx, user.ID, sealed); err != nil {
+		return nil, err
+	}
+	return &transport.TwoFactorEnrollment{
+		Secret:     secret,
+		OTPAuthURI: totpURI(uc.totpIssuer, user.Email, secret),
+	}, nil
+}
+
+// ConfirmTwoFactor включает 2FA по первому коду из приложения и выдает коды
+// восстановления. Если sessionID пустой (настройка при входе), заодно
+// создается новая сессия с подтвержденным вторым фактором.
+func (uc *authUseCase) ConfirmTwoFactor(ctx context.Context, userID, sessionID, code string) (*transport.TwoFactorConfirmResult, error) {
+	keys := []throttleKey{{twoFactorRule, userID}}
+	if err := uc.checkThrottle(ctx, keys); err != nil {
+		return nil, err
+	}
+
+	totp, err := uc.twoFactor.GetTOTP(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if totp.EnabledAt != nil {
+		return nil, domain.ErrTwoFactorAlreadyEnabled
+	}
+	secret, err := uc.totpCipher.open(userID, totp.Secret)
+	if err != nil {
+		return nil, err
+	}
+	step, ok := verifyTOTP(secret, strings.TrimSpace(code), time.Now())
+	if !ok {
+		uc.twoFactorFailed(ctx, keys)
+		return nil, domain.ErrTwoFactorCodeInvalid
+	}
+	uc.resetThrottle(ctx, keys)
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.twoFactor.EnableTOTP(ctx, userID, step, hashes); err != nil {
+		return nil, err
+	}
+	uc.recordEvent(ctx, domain.AuthEvent{UserID: userID, Type: domain.EventTwoFactorEnabled})
+	res := &transport.TwoFactorConfirmResult{RecoveryCodes: codes}
+
+	if sessionID != "" {
+		// новый access-токен с mfa выдаст ближайший refresh
+		if err := uc.sessions.MarkSessionMFA(ctx, sessionID); err != nil {
+			return nil, err
+		}
+		return res, nil
+	}
+
+	user, err := uc.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	res.Auth, err = uc.loginSession(ctx, user, true, domain.MethodTOTP)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// DisableTwoFactor выключает 2FA после проверки текущего кода. Для ролей,
+// где 2FA обязательна, выключение запрещено.
+func (uc *authUseCase) DisableTwoFactor(ctx context.Context, userID, code string) error {
+	required, err := uc.twoFactorRequired(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if required {
+		return domain.ErrTwoFactorRequired
+	}
+	user, err := uc.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !user.TwoFactorEnabled() {
+		return domain.ErrTwoFactorNotEnrolled
+	}
+	if err := uc.verifySecondFactor(ctx, userID, code); err != nil {
+		return err
+	}
+	if err := uc.twoFactor.DisableTOTP(ctx, userID); err != nil {
+		return err
+	}
+	uc.recordEvent(ctx, domain.AuthEvent{UserID: userID, Type: domain.EventTwoFactorDisabled})
+	return nil
+}
+
+// loginChallenge решает, нужен ли второй шаг входа. Возвращает nil, если
+// можно сразу создавать сессию.
+func (uc *authUseCase) loginChallenge(ctx context.Context, user *domain.User) (*transport.AuthResult, error) {
+	if user.Deleted() {
+		return nil, domain.ErrAccountDeleted
+	}
+	setup := false
+	if !user.TwoFactorEnabled() {
+		required, err := uc.twoFactorRequired(ctx, user.ID)
+		if err != nil {
+			return nil, err
+		}
+		if !required {
+			return nil, nil
+		}
+		setup = true
+	}
+
+	now := time.Now()
+	expires := now.Add(twoFactorChallengeTTL)
+	token, err := uc.signer.Sign(&twoFactorChallengeClaims{
+		UserID:  user.ID,
+		Purpose: twoFactorChallengePurpose,
+		Setup:   setup,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expires),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &transport.AuthResult{
+		UserID:        user.ID,
+		Email:         user.Email,
+		EmailVerified: user.EmailVerified(),
+		Challenge: &transport.TwoFactorChallenge{
+			TwoFactorRequired: true,
+			SetupRequired:     setup,
+			ChallengeToken:    token,
+			ExpiresAt:         expires,
+		},
+	}, nil
+}
+
+func (uc *authUseCase) parseChallenge(token string) (*twoFactorChallengeClaims, error) {
+	claims := &twoFactorChallengeClaims{}
+	parsed, err := jwt.ParseWithClaims(token, claims, uc.signer.Keyfunc)
+	if err != nil || !parsed.Valid || claims.Purpose != twoFactorChallengePurpose || claims.UserID == "" {
+		return nil, domain.ErrChallengeInvalid
+	}
+	return claims, nil
+}
+
+func (uc *authUseCase) twoFactorRequired(ctx context.Context, userID string) (bool, error) {
+	if len(uc.twoFactorRoles) == 0 {
+		return false, nil
+	}
+	roles, err := uc.roles.GetUserRoles(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	for _, role := range roles {
+		if uc.twoFactorRoles[role] {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// verifySecondFactor принимает 6-значный TOTP-код или код восстановления.
+func (uc *authUseCase) verifySecondFactor(ctx context.Context, userID, code string) error {
+	keys := []throttleKey{{twoFactorRule, userID}}
+	if err := uc.checkThrottle(ctx, keys); err != nil {
+		return err
+	}
+
+	code = strings.ReplaceAll(strings.TrimSpace(code), " ", "")
+	var err error
+	if isTOTPCode(code) {
+		err = uc.checkTOTP(ctx, userID, code)
+	} else {
+		err = uc.twoFactor.UseRecoveryCode(ctx, userID, hashToken(normalizeRecoveryCode(code)))
+	}
+	if errors.Is(err, domain.ErrTwoFactorCodeInvalid) {
+		uc.twoFactorFailed(ctx, keys)
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	uc.resetThrottle(ctx, keys)
+	return nil
+}
+
+func (uc *authUseCase) checkTOTP(ctx context.Context, userID, code string) error {
+	totp, err := uc.twoFactor.GetTOTP(ctx, userID)
+	if err != nil {
+		return err
+	}
+	secret, err := uc.totpCipher.open(userID, totp.Secret)
+	if err != nil {
+		return err
+	}
+	step, ok := verifyTOTP(secret, code, time.Now())
+	if !ok {
+		return domain.ErrTwoFactorCodeInvalid
+	}
+	return uc.twoFactor.UseTOTPStep(ctx, userID, step)
+}
+
+// EncryptTOTPSecrets шифрует секреты, сохраненные до появления шифрования.
+// Вызывается при старте сервиса, возвращает число зашифрованных секретов.
+func (uc *authUseCase) EncryptTOTPSecrets(ctx context.Context) (int, error) {
+	secrets, err := uc.twoFactor.ListPlainTOTPSecrets(ctx)
+	if err != nil {
+		return 0, err
+	}
+	done := 0
+	for userID, secret := range secrets {
+		sealed, err := uc.totpCipher.seal(userID, secret)
+		if err != nil {
+			return done, err
+		}
+		if err := uc.twoFactor.ReplaceTOTPSecret(ctx, userID, secret, sealed); err != nil {
+			return done, err
+		}
+		done++
+	}
+	if done > 0 {
+		logger.FromContext(ctx).InfoContext(ctx, "usecase EncryptTOTPSecrets success", slog.Int("count", done))
+	}
+	return done, nil
+}
+
+func (uc *authUseCase) twoFactorFailed(ctx context.Context, keys []throttleKey) {
+	if err := uc.registerAttempts(ctx, keys); err != nil {
+		logger.FromContext(ctx).ErrorContext(ctx, "usecase 2fa throttle update failed", slog.Any("err", err))
+	}
+}
+
+// generateRecoveryCodes возвращает коды для показа пользователю и их хэши.
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		b := make([]byte, 8)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+		raw := strings.ToLower(totpEncoding.EncodeToString(b))[:recoveryCodeLen]
+		codes = append(codes, raw[:recoveryCodeLen/2]+"-"+raw[recoveryCodeLen/2:])
+		hashes = append(hashes, hashToken(raw))
+	}
+	return codes, hashes, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.ReplaceAll(code, "-", ""))
+}
@@ -0,0 +1,228 @@
+package usecase
+
+import (
+	"apple_backend/auth_service/internal/domain"
+	mocks "apple_backend/auth_service/internal/usecase/mock"
+	"apple_backend/pkg/rbac"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestTOTPCode_RFC6238(t *testing.T) {
+	// тестовый вектор RFC 6238 для SHA1: T=59 -> 94287082 (последние 6 цифр)
+	if got := totpCode([]byte("12345678901234567890"), 59/totpPeriod); got != "287082" {
+		t.Fatalf("totpCode = %s, want 287082", got)
+	}
+}
+
+func sealTOTP(t *testing.T, uc *authUseCase, userID, secret string) string {
+	t.Helper()
+	sealed, err := uc.totpCipher.seal(userID, secret)
+	if err != nil {
+		t.Fatalf("seal totp secret: %v", err)
+	}
+	return sealed
+}
+
+func TestTOTPCipher_BindsKeyAndUser(t *testing.T) {
+	c := newTOTPCipher("key")
+	sealed, err := c.seal("u1", "JBSWY3DPEHPK3PXP")
+	if err != nil {
+		t.Fatalf("seal: %v", err)
+	}
+	if strings.Contains(sealed, "JBSWY3DPEHPK3PXP") {
+		t.Fatal("secret must not be stored in plaintext")
+	}
+	if secret, err := c.open("u1", sealed); err != nil || secret != "JBSWY3DPEHPK3PXP" {
+		t.Fatalf("open: %q %v", secret, err)
+	}
+	// чужой аккаунт, другой ключ и незашифрованное значение не открываются
+	if _, err := c.open("u2", sealed); err != errTOTPSecretInvalid {
+		t.Fatalf("expected errTOTPSecretInvalid for other user, got %v", err)
+	}
+	if _, err := newTOTPCipher("other").open("u1", sealed); err != errTOTPSecretInvalid {
+		t.Fatalf("expected errTOTPSecretInvalid for other key, got %v", err)
+	}
+	if _, err := c.open("u1", "JBSWY3DPEHPK3PXP"); err != errTOTPSecretInvalid {
+		t.Fatalf("expected errTOTPSecretInvalid for plaintext, got %v", err)
+	}
+}
+
+func TestEnrollTwoFactor_StoresEncryptedSecret(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockAuthRepository(ctrl)
+	twoFactor := mocks.NewMockTwoFactorRepository(ctrl)
+	sessions := mocks.NewMockSessionRepository(ctrl)
+	uc := NewAuthUseCase(repo, sessions, nil, nil, testSigner(t), Config{TwoFactor: twoFactor, TOTPKey: "key"})
+
+	repo.EXPECT().GetUserByID(gomock.Any(), "u1").Return(&domain.User{ID: "u1", Email: "u@ex.com"}, nil)
+	var stored string
+	twoFactor.EXPECT().SetPendingTOTP(gomock.Any(), "u1", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _, secret string) error {
+			stored = secret
+			return nil
+		})
+
+	res, err := uc.EnrollTwoFactor(context.Background(), "u1")
+	if err != nil {
+		t.Fatalf("enroll failed: %v", err)
+	}
+	if stored == res.Secret {
+		t.Fatal("secret must be stored encrypted")
+	}
+	if secret, err := uc.totpCipher.open("u1", stored); err != nil || secret != res.Secret {
+		t.Fatalf("stored secret does not decrypt: %q %v", secret, err)
+	}
+}
+
+func TestEncryptTOTPSecrets(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockAuthRepository(ctrl)
+	twoFactor := mocks.NewMockTwoFactorRepository(ctrl)
+	sessions := mocks.NewMockSessionRepository(ctrl)
+	uc := NewAuthUseCase(repo, sessions, nil, nil, testSigner(t), Config{TwoFactor: twoFactor, TOTPKey: "key"})
+
+	twoFactor.EXPECT().ListPlainTOTPSecrets(gomock.Any()).Return(map[string]string{"u1": "JBSWY3DPEHPK3PXP"}, nil)
+	twoFactor.EXPECT().ReplaceTOTPSecret(gomock.Any(), "u1", "JBSWY3DPEHPK3PXP", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _, _, sealed string) error {
+			if secret, err := uc.totpCipher.open("u1", sealed); err != nil || secret != "JBSWY3DPEHPK3PXP" {
+				t.Fatalf("sealed secret does not decrypt: %q %v", secret, err)
+			}
+			return nil
+		})
+
+	n, err := uc.EncryptTOTPSecrets(context.Background())
+	if err != nil || n != 1 {
+		t.Fatalf("EncryptTOTPSecrets: %d %v", n, err)
+	}
+}
+
+func TestLogin_TwoFactorChallenge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockAuthRepository(ctrl)
+	roles := mocks.NewMockRoleRepository(ctrl)
+	twoFactor := mocks.NewMockTwoFactorRepository(ctrl)
+	sessions := mocks.NewMockSessionRepository(ctrl)
+	uc := NewAuthUseCase(repo, sessions, nil, nil, testSigner(t), Config{Roles: roles, TwoFactor: twoFactor})
+	roles.EXPECT().GetUserRoles(gomock.Any(), "u1").Return([]string{rbac.RoleCustomer}, nil)
+
+	secret, _ := generateTOTPSecret()
+	hash, _ := bcrypt.GenerateFromPassword([]byte("Str0ng!Pass"), bcrypt.MinCost)
+	enabledAt := time.Now()
+	user := &domain.User{ID: "u1", Email: "u@ex.com", PasswordHash: string(hash), TwoFactorEnabledAt: &enabledAt}
+
+	repo.EXPECT().GetUserByEmail(gomock.Any(), "u@ex.com").Return(user, nil)
+	repo.EXPECT().RehashPassword(gomock.Any(), "u1", string(hash), gomock.Any()).Return(nil)
+
+	res, err := uc.Login(context.Background(), "u@ex.com", "Str0ng!Pass")
+	if err != nil || res.Challenge == nil || res.Token != "" {
+		t.Fatalf("expected challenge without tokens: err=%v res=%+v", err, res)
+	}
+
+	key, _ := totpEncoding.DecodeString(secret)
+	code := totpCode(key, totpStep(time.Now()))
+
+	repo.EXPECT().GetUserByID(gomock.Any(), "u1").Return(user, nil)
+	twoFactor.EXPECT().GetTOTP(gomock.Any(), "u1").Return(&domain.TOTP{Secret: sealTOTP(t, uc, "u1", secret), EnabledAt: &enabledAt}, nil)
+	twoFactor.EXPECT().UseTOTPStep(gomock.Any(), "u1", gomock.Any()).Return(nil)
+	sessions.EXPECT().
+		CreateSession(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, s *domain.Session, _ string, _ time.Time) (*domain.Session, error) {
+			if s.MFAVerifiedAt == nil {
+				t.Fatal("session must be marked as mfa verified")
+			}
+			return &domain.Session{ID: "s1", UserID: "u1"}, nil
+		})
+
+	auth, err := uc.LoginTwoFactor(context.Background(), res.Challenge.ChallengeToken, code)
+	if err != nil || auth.Token == "" {
+		t.Fatalf("second step failed: err=%v", err)
+	}
+}
+
+func TestLogin_TwoFactorSetupRequiredForRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockAuthRepository(ctrl)
+	roles := mocks.NewMockRoleRepository(ctrl)
+	sessions := mocks.NewMockSessionRepository(ctrl)
+	uc := NewAuthUseCase(repo, sessions, nil, nil, testSigner(t), Config{TwoFactorRoles: []string{rbac.RoleAdmin}, Roles: roles})
+
+	hash, _ := bcrypt.GenerateFromPassword([]byte("Str0ng!Pass"), bcrypt.MinCost)
+	repo.EXPECT().GetUserByEmail(gomock.Any(), "a@ex.com").Return(&domain.User{ID: "a1", Email: "a@ex.com", PasswordHash: string(hash)}, nil)
+	roles.EXPECT().GetUserRoles(gomock.Any(), "a1").Return([]string{rbac.RoleAdmin}, nil)
+	repo.EXPECT().RehashPassword(gomock.Any(), "a1", string(hash), gomock.Any()).Return(nil)
+
+	res, err := uc.Login(context.Background(), "a@ex.com", "Str0ng!Pass")
+	if err != nil || res.Challenge == nil || !res.Challenge.SetupRequired {
+		t.Fatalf("expected setup challenge: err=%v res=%+v", err, res)
+	}
+
+	// setup-токен нельзя обменять на сессию без настройки 2FA
+	if _, err := uc.LoginTwoFactor(context.Background(), res.Challenge.ChallengeToken, "000000"); err != domain.ErrTwoFactorNotEnrolled {
+		t.Fatalf("expected ErrTwoFactorNotEnrolled, got %v", err)
+	}
+	userID, err := uc.ChallengeUser(context.Background(), res.Challenge.ChallengeToken)
+	if err != nil || userID != "a1" {
+		t.Fatalf("ChallengeUser: %v %q", err, userID)
+	}
+}
+
+func TestConfirmTwoFactor_IssuesRecoveryCodes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockAuthRepository(ctrl)
+	twoFactor := mocks.NewMockTwoFactorRepository(ctrl)
+	sessions := mocks.NewMockSessionRepository(ctrl)
+	uc := NewAuthUseCase(repo, sessions, nil, nil, testSigner(t), Config{TwoFactor: twoFactor})
+
+	secret, _ := generateTOTPSecret()
+	key, _ := totpEncoding.DecodeString(secret)
+
+	twoFactor.EXPECT().GetTOTP(gomock.Any(), "u1").Return(&domain.TOTP{Secret: sealTOTP(t, uc, "u1", secret)}, nil)
+	var stored []string
+	twoFactor.EXPECT().EnableTOTP(gomock.Any(), "u1", gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, _ int64, hashes []string) error {
+			stored = hashes
+			return nil
+		})
+	sessions.EXPECT().MarkSessionMFA(gomock.Any(), "s1").Return(nil)
+
+	res, err := uc.ConfirmTwoFactor(context.Background(), "u1", "s1", totpCode(key, totpStep(time.Now())))
+	if err != nil || len(res.RecoveryCodes) != recoveryCodeCount || res.Auth != nil {
+		t.Fatalf("confirm failed: err=%v res=%+v", err, res)
+	}
+	if stored[0] != hashToken(normalizeRecoveryCode(res.RecoveryCodes[0])) {
+		t.Fatal("recovery codes must be stored hashed")
+	}
+}
+
+func TestConfirmTwoFactor_WrongCode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockAuthRepository(ctrl)
+	twoFactor := mocks.NewMockTwoFactorRepository(ctrl)
+	sessions := mocks.NewMockSessionRepository(ctrl)
+	uc := NewAuthUseCase(repo, sessions, nil, nil, testSigner(t), Config{TwoFactor: twoFactor})
+
+	secret, _ := generateTOTPSecret()
+	twoFactor.EXPECT().GetTOTP(gomock.Any(), "u1").Return(&domain.TOTP{Secret: sealTOTP(t, uc, "u1", secret)}, nil)
+
+	if _, err := uc.ConfirmTwoFactor(context.Background(), "u1", "s1", "12345"); err != domain.ErrTwoFactorCodeInvalid {
+		t.Fatalf("expected ErrTwoFactorCodeInvalid, got %v", err)
+	}
+}
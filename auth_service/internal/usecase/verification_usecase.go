@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"apple_backend/auth_service/internal/domain"
+	"apple_backend/pkg/logger"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	emailVerificationTTL       = 24 * time.Hour
+	verificationResendInterval = time.Minute
+
+	emailVerificationPurpose = "email_verification"
+)
+
+type emailVerificationClaims struct {
+	UserID  string `json:"user_id"`
+	Email   string `json:"email"`
+	Purpose string `json:"purpose"`
+	jwt.RegisteredClaims
+}
+
+// VerifyEmail подтверждает адрес по подписанному токену из письма.
+func (uc *authUseCase) VerifyEmail(ctx context.Context, token string) error {
+	claims := &emailVerificationClaims{}
+	parsed, err := jwt.ParseWithClaims(token, claims, uc.signer.Keyfunc)
+	if err != nil || !parsed.Valid || claims.Purpose != emailVerificationPurpose || claims.UserID == "" {
+		return domain.ErrVerifyTokenInvalid
+	}
+
+	if err := uc.repo.MarkEmailVerified(ctx, claims.UserID, claims.Email); err != nil {
+		if err == domain.ErrUserNotFound {
+			return domain.ErrVerifyTokenInvalid
+		}
+		return err
+	}
+	return nil
+}
+
+// ResendVerification повторно отправляет письмо, но не чаще раза в минуту.
+func (uc *authUseCase) ResendVerification(ctx context.Context, userID string) error {
+	user, err := uc.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.EmailVerified() {
+		return domain.ErrEmailAlreadyVerified
+	}
+	if user.Email == "" {
+		return domain.ErrEmailNotSet
+	}
+	return uc.sendVerificationEmail(ctx, user)
+}
+
+func (uc *authUseCase) sendVerificationEmail(ctx context.Context, user *domain.User) error {
+	if err := uc.repo.TouchVerificationSent(ctx, user.ID, verificationResendInterval); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	token, err := uc.signer.Sign(&emailVerificationClaims{
+		UserID:  user.ID,
+		Email:   user.Email,
+		Purpose: emailVerificationPurpose,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(now.Add(emailVerificationTTL)),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/verify-email?token=%s", uc.appURL, url.QueryEscape(token))
+	body := fmt.Sprintf(
+		"Здравствуйте!\n\nПодтвердите адрес электронной почты, перейдя по ссылке:\n%s\n\n"+
+			"Ссылка действует %d часа. Если вы не регистрировались, проигнорируйте это письмо.",
+		link, int(emailVerificationTTL.Hours()),
+	)
+	if err := uc.mailer.Send(ctx, user.Email, "Подтверждение email", body); err != nil {
+		logger.FromContext(ctx).ErrorContext(ctx, "usecase sendVerificationEmail failed",
+			slog.Any("err", err), slog.String("user_id", user.ID))
+		return err
+	}
+	return nil
+}
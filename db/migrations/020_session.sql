@@ -0,0 +1,40 @@
+-- Write your migrate up statements here
+create table if not exists session
+(
+    id         uuid primary key,
+    user_id    uuid        not null references account (id) on delete cascade,
+    revoked_at timestamptz,
+    updated_at timestamptz not null default current_timestamp,
+    created_at timestamptz not null default current_timestamp
+);
+
+CREATE TRIGGER trg_update_session_updated_at
+    BEFORE UPDATE
+    ON session
+    FOR EACH ROW
+EXECUTE FUNCTION update_updated_at();
+
+CREATE INDEX idx_session_user_id ON session (user_id);
+
+create table if not exists refresh_token
+(
+    id         uuid primary key,
+    session_id uuid        not null references session (id) on delete cascade,
+    hash       text        not null unique,
+    expires_at timestamptz not null,
+    used_at    timestamptz,
+    updated_at timestamptz not null default current_timestamp,
+    created_at timestamptz not null default current_timestamp
+);
+
+CREATE TRIGGER trg_update_refresh_token_updated_at
+    BEFORE UPDATE
+    ON refresh_token
+    FOR EACH ROW
+EXECUTE FUNCTION update_updated_at();
+
+CREATE INDEX idx_refresh_token_session_id ON refresh_token (session_id);
+
+---- create above / drop below ----
+drop table if exists refresh_token;
+drop table if exists session;
@@ -0,0 +1,11 @@
+-- Write your migrate up statements here
+alter table session
+    add column if not exists user_agent   text check (length(user_agent) <= 500),
+    add column if not exists ip           text check (length(ip) <= 45),
+    add column if not exists last_seen_at timestamptz not null default current_timestamp;
+
+---- create above / drop below ----
+alter table session
+    drop column if exists user_agent,
+    drop column if exists ip,
+    drop column if exists last_seen_at;
@@ -0,0 +1,22 @@
+-- Write your migrate up statements here
+create table if not exists password_reset_token
+(
+    id         uuid primary key,
+    user_id    uuid        not null references account (id) on delete cascade,
+    hash       text        not null unique,
+    expires_at timestamptz not null,
+    used_at    timestamptz,
+    updated_at timestamptz not null default current_timestamp,
+    created_at timestamptz not null default current_timestamp
+);
+
+CREATE TRIGGER trg_update_password_reset_token_updated_at
+    BEFORE UPDATE
+    ON password_reset_token
+    FOR EACH ROW
+EXECUTE FUNCTION update_updated_at();
+
+CREATE INDEX idx_password_reset_token_user_id ON password_reset_token (user_id);
+
+---- create above / drop below ----
+drop table if exists password_reset_token;
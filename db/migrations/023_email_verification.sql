@@ -0,0 +1,14 @@
+-- Write your migrate up statements here
+alter table account
+    add column if not exists email_verified_at          timestamptz,
+    add column if not exists email_verification_sent_at timestamptz;
+
+-- аккаунты, созданные до появления подтверждения, считаем подтвержденными
+update account
+set email_verified_at = created_at
+where email_verified_at is null;
+
+---- create above / drop below ----
+alter table account
+    drop column if exists email_verified_at,
+    drop column if exists email_verification_sent_at;
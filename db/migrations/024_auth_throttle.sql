@@ -0,0 +1,22 @@
+-- Write your migrate up statements here
+-- счетчики попыток входа/регистрации, общие для всех реплик auth_service
+create table if not exists auth_throttle
+(
+    key          text primary key,
+    attempts     integer     not null default 0 check (attempts >= 0),
+    window_start timestamptz not null default current_timestamp,
+    locked_until timestamptz,
+    updated_at   timestamptz not null default current_timestamp,
+    created_at   timestamptz not null default current_timestamp
+);
+
+CREATE TRIGGER trg_update_auth_throttle_updated_at
+    BEFORE UPDATE
+    ON auth_throttle
+    FOR EACH ROW
+EXECUTE FUNCTION update_updated_at();
+
+CREATE INDEX idx_auth_throttle_updated_at ON auth_throttle (updated_at);
+
+---- create above / drop below ----
+drop table if exists auth_throttle;
@@ -0,0 +1,37 @@
+-- Write your migrate up statements here
+alter table account
+    add column if not exists totp_secret     text,
+    add column if not exists totp_enabled_at timestamptz,
+    -- последний принятый 30-секундный шаг, чтобы код нельзя было использовать повторно
+    add column if not exists totp_last_step  bigint not null default 0;
+
+alter table session
+    add column if not exists mfa_verified_at timestamptz;
+
+create table if not exists recovery_code
+(
+    id         uuid primary key,
+    user_id    uuid        not null references account (id) on delete cascade,
+    hash       text        not null,
+    used_at    timestamptz,
+    updated_at timestamptz not null default current_timestamp,
+    created_at timestamptz not null default current_timestamp,
+    unique (user_id, hash)
+);
+
+CREATE TRIGGER trg_update_recovery_code_updated_at
+    BEFORE UPDATE
+    ON recovery_code
+    FOR EACH ROW
+EXECUTE FUNCTION update_updated_at();
+
+---- create above / drop below ----
+drop table if exists recovery_code;
+
+alter table session
+    drop column if exists mfa_verified_at;
+
+alter table account
+    drop column if exists totp_secret,
+    drop column if exists totp_enabled_at,
+    drop column if exists totp_last_step;
@@ -0,0 +1,18 @@
+-- Write your migrate up statements here
+create table if not exists account_role
+(
+    user_id    uuid        not null references account (id) on delete cascade,
+    role       text        not null check (role in ('customer', 'store_owner', 'store_staff', 'courier', 'admin')),
+    updated_at timestamptz not null default current_timestamp,
+    created_at timestamptz not null default current_timestamp,
+    primary key (user_id, role)
+);
+
+CREATE TRIGGER trg_update_account_role_updated_at
+    BEFORE UPDATE
+    ON account_role
+    FOR EACH ROW
+EXECUTE FUNCTION update_updated_at();
+
+---- create above / drop below ----
+drop table if exists account_role;
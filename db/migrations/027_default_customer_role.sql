@@ -0,0 +1,11 @@
+-- Write your migrate up statements here
+-- у каждого существующего аккаунта есть роль покупателя
+insert into account_role (user_id, role)
+select id, 'customer'
+from account
+on conflict do nothing;
+
+---- create above / drop below ----
+delete
+from account_role
+where role = 'customer';
@@ -0,0 +1,44 @@
+-- Write your migrate up statements here
+-- аккаунт, созданный входом по телефону, может не иметь email и пароля
+alter table account
+    alter column email drop not null,
+    add column if not exists phone_verified_at timestamptz;
+
+-- войти по коду можно только в аккаунт с подтвержденным номером, поэтому
+-- уникален только подтвержденный номер: указанный в профиле номер без
+-- подтверждения не должен мешать владельцу номера
+create unique index if not exists account_verified_phone_key
+    on account (phone)
+    where phone_verified_at is not null;
+
+-- действующий одноразовый код для номера; новый запрос заменяет старый код
+create table if not exists phone_code
+(
+    phone      text primary key,
+    code_hash  text        not null,
+    attempts   integer     not null default 0 check (attempts >= 0),
+    expires_at timestamptz not null,
+    sent_at    timestamptz not null default current_timestamp,
+    updated_at timestamptz not null default current_timestamp,
+    created_at timestamptz not null default current_timestamp
+);
+
+CREATE TRIGGER trg_update_phone_code_updated_at
+    BEFORE UPDATE
+    ON phone_code
+    FOR EACH ROW
+EXECUTE FUNCTION update_updated_at();
+
+CREATE INDEX idx_phone_code_expires_at ON phone_code (expires_at);
+
+---- create above / drop below ----
+drop table if exists phone_code;
+
+drop index if exists account_verified_phone_key;
+
+alter table account
+    drop column if exists phone_verified_at;
+
+-- аккаунты без email нужно удалить вручную до отката
+alter table account
+    alter column email set not null;
@@ -0,0 +1,27 @@
+-- Write your migrate up statements here
+-- аккаунты внешних провайдеров (VK ID, Яндекс ID, ...); у одного account
+-- может быть несколько провайдеров
+create table if not exists external_identity
+(
+    id         uuid primary key,
+    user_id    uuid        not null references account (id) on delete cascade,
+    provider   text        not null check (length(provider) <= 50),
+    subject    text        not null check (length(subject) <= 255),
+    -- email, который провайдер сообщил при привязке
+    email      text check (length(email) <= 254),
+    updated_at timestamptz not null default current_timestamp,
+    created_at timestamptz not null default current_timestamp,
+    unique (provider, subject),
+    unique (user_id, provider)
+);
+
+CREATE TRIGGER trg_update_external_identity_updated_at
+    BEFORE UPDATE
+    ON external_identity
+    FOR EACH ROW
+EXECUTE FUNCTION update_updated_at();
+
+CREATE INDEX idx_external_identity_user_id ON external_identity (user_id);
+
+---- create above / drop below ----
+drop table if exists external_identity;
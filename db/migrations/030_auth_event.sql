@@ -0,0 +1,40 @@
+-- Write your migrate up statements here
+-- журнал событий авторизации. Только дописывается: без внешнего ключа на
+-- account, чтобы история переживала удаление аккаунта, и без updated_at
+create table if not exists auth_event
+(
+    id         uuid primary key,
+    -- null — событие без известного аккаунта (вход с неизвестным email)
+    user_id    uuid,
+    type       text        not null check (length(type) <= 50),
+    -- способ входа: password, totp, phone, oidc:<провайдер>
+    method     text check (length(method) <= 60),
+    -- email, номер или ключ блокировки, к которому относилась попытка
+    identifier text check (length(identifier) <= 320),
+    ip         text check (length(ip) <= 45),
+    user_agent text check (length(user_agent) <= 500),
+    request_id text check (length(request_id) <= 100),
+    created_at timestamptz not null default current_timestamp
+);
+
+CREATE OR REPLACE FUNCTION auth_event_append_only()
+    RETURNS TRIGGER AS
+$$
+BEGIN
+    RAISE EXCEPTION 'auth_event is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_auth_event_append_only
+    BEFORE UPDATE OR DELETE
+    ON auth_event
+    FOR EACH ROW
+EXECUTE FUNCTION auth_event_append_only();
+
+CREATE INDEX idx_auth_event_user_id_created_at ON auth_event (user_id, created_at DESC, id DESC);
+CREATE INDEX idx_auth_event_created_at ON auth_event (created_at DESC, id DESC);
+CREATE INDEX idx_auth_event_ip ON auth_event (ip);
+
+---- create above / drop below ----
+drop table if exists auth_event;
+drop function if exists auth_event_append_only();
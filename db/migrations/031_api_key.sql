@@ -0,0 +1,28 @@
+-- Write your migrate up statements here
+-- персональные API-ключи для интеграций. Сам ключ не хранится: только
+-- открытый префикс для поиска и SHA-256 секретной части
+create table if not exists api_key
+(
+    id           uuid primary key,
+    user_id      uuid        not null references account (id) on delete cascade,
+    name         text        not null check (length(name) between 1 and 100),
+    prefix       text        not null unique check (length(prefix) = 12),
+    secret_hash  text        not null,
+    scopes       text[]      not null check (cardinality(scopes) > 0 and scopes <@ array ['menu:write', 'orders:read']),
+    expires_at   timestamptz not null,
+    last_used_at timestamptz,
+    revoked_at   timestamptz,
+    updated_at   timestamptz not null default current_timestamp,
+    created_at   timestamptz not null default current_timestamp
+);
+
+CREATE TRIGGER trg_update_api_key_updated_at
+    BEFORE UPDATE
+    ON api_key
+    FOR EACH ROW
+EXECUTE FUNCTION update_updated_at();
+
+CREATE INDEX idx_api_key_user_id ON api_key (user_id);
+
+---- create above / drop below ----
+drop table if exists api_key;
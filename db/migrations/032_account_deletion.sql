@@ -0,0 +1,59 @@
+-- Write your migrate up statements here
+-- удаление аккаунта в два шага: сначала deleted_at (пока идет срок на
+-- отмену), затем фоновая задача обезличивает строку и ставит anonymized_at.
+-- Сама строка account не удаляется, поэтому каскады больше не срабатывают
+alter table account
+    add column if not exists deleted_at    timestamptz,
+    add column if not exists anonymized_at timestamptz;
+
+CREATE INDEX idx_account_deleted_at ON account (deleted_at)
+    WHERE deleted_at IS NOT NULL AND anonymized_at IS NULL;
+
+-- заказы и платежи нужны бухгалтерии и после удаления покупателя
+alter table orders
+    alter column user_id drop not null,
+    drop constraint if exists orders_user_id_fkey,
+    add constraint orders_user_id_fkey
+        foreign key (user_id) references account (id) on delete set null;
+
+-- журнал auth_event только дописывается, но email или телефон, IP и
+-- user agent обезличенного аккаунта хранить нельзя. Единственное
+-- разрешенное изменение — обнулить эти три поля у событий аккаунта, который
+-- уже обезличен; тип, способ, время и request_id остаются как были
+CREATE OR REPLACE FUNCTION auth_event_append_only()
+    RETURNS TRIGGER AS
+$$
+BEGIN
+    IF TG_OP = 'UPDATE'
+        AND NEW.identifier IS NULL
+        AND NEW.ip IS NULL
+        AND NEW.user_agent IS NULL
+        AND (NEW.id, NEW.user_id, NEW.type, NEW.method, NEW.request_id, NEW.created_at)
+            IS NOT DISTINCT FROM (OLD.id, OLD.user_id, OLD.type, OLD.method, OLD.request_id, OLD.created_at)
+        AND EXISTS (SELECT 1 FROM account WHERE id = OLD.user_id AND anonymized_at IS NOT NULL) THEN
+        RETURN NEW;
+    END IF;
+    RAISE EXCEPTION 'auth_event is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+---- create above / drop below ----
+CREATE OR REPLACE FUNCTION auth_event_append_only()
+    RETURNS TRIGGER AS
+$$
+BEGIN
+    RAISE EXCEPTION 'auth_event is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+-- отвязанные заказы не удаляются, поэтому not null не возвращается
+alter table orders
+    drop constraint if exists orders_user_id_fkey,
+    add constraint orders_user_id_fkey
+        foreign key (user_id) references account (id) on delete cascade;
+
+drop index if exists idx_account_deleted_at;
+
+alter table account
+    drop column if exists deleted_at,
+    drop column if exists anonymized_at;
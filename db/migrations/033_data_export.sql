@@ -0,0 +1,34 @@
+-- Write your migrate up statements here
+-- выгрузки персональных данных по запросу пользователя. Архив лежит на диске
+-- сервиса профилей под именем <id>.zip до expires_at
+create table if not exists data_export
+(
+    id          uuid primary key,
+    user_id     uuid        not null references account (id) on delete cascade,
+    status      text        not null default 'pending'
+        check (status in ('pending', 'running', 'ready', 'failed', 'expired')),
+    error       text check (length(error) <= 500),
+    started_at  timestamptz,
+    finished_at timestamptz,
+    expires_at  timestamptz,
+    updated_at  timestamptz not null default current_timestamp,
+    created_at  timestamptz not null default current_timestamp
+);
+
+CREATE TRIGGER trg_update_data_export_updated_at
+    BEFORE UPDATE
+    ON data_export
+    FOR EACH ROW
+EXECUTE FUNCTION update_updated_at();
+
+-- не больше одной незавершенной выгрузки на пользователя
+create unique index if not exists data_export_active_key
+    on data_export (user_id)
+    where status in ('pending', 'running');
+
+CREATE INDEX idx_data_export_user_id_created_at ON data_export (user_id, created_at DESC);
+CREATE INDEX idx_data_export_queue ON data_export (created_at) WHERE status in ('pending', 'running');
+CREATE INDEX idx_data_export_expires_at ON data_export (expires_at) WHERE status = 'ready';
+
+---- create above / drop below ----
+drop table if exists data_export;
@@ -0,0 +1,15 @@
+-- Write your migrate up statements here
+-- гостевая корзина — cart без пользователя, ее id хранится в подписанной cookie
+alter table cart
+    alter column user_id drop not null;
+
+-- очистка истекших гостевых корзин
+CREATE INDEX idx_cart_guest_updated_at ON cart (updated_at) WHERE user_id IS NULL;
+
+---- create above / drop below ----
+drop index if exists idx_cart_guest_updated_at;
+
+delete from cart where user_id is null;
+
+alter table cart
+    alter column user_id set not null;
@@ -0,0 +1,14 @@
+-- Write your migrate up statements here
+-- владельцы магазинов: изменять магазин может только его владелец (или admin)
+create table if not exists store_owner
+(
+    store_id   uuid        not null references store (id) on delete cascade,
+    user_id    uuid        not null references account (id) on delete cascade,
+    created_at timestamptz not null default current_timestamp,
+    primary key (store_id, user_id)
+);
+
+CREATE INDEX idx_store_owner_user_id ON store_owner (user_id);
+
+---- create above / drop below ----
+drop table if exists store_owner;
@@ -0,0 +1,19 @@
+-- Write your migrate up statements here
+-- товары и позиции меню не удаляются, а архивируются: на store_item ссылаются
+-- order_item с каскадным удалением, и удаление стерло бы историю заказов
+alter table item
+    add column if not exists archived_at timestamptz;
+
+alter table store_item
+    add column if not exists archived_at timestamptz;
+
+CREATE INDEX idx_store_item_store_id_active ON store_item (store_id) WHERE archived_at IS NULL;
+
+---- create above / drop below ----
+drop index if exists idx_store_item_store_id_active;
+
+alter table store_item
+    drop column if exists archived_at;
+
+alter table item
+    drop column if exists archived_at;
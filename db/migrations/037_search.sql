@@ -0,0 +1,62 @@
+-- Write your migrate up statements here
+-- полнотекстовый поиск по магазинам и товарам: tsvector с русской
+-- морфологией и триграммы по названиям для опечаток
+create extension if not exists pg_trgm;
+
+alter table store
+    add column if not exists search_vector tsvector;
+
+alter table item
+    add column if not exists search_vector tsvector;
+
+-- название весит больше описания
+CREATE OR REPLACE FUNCTION name_description_search_vector()
+    RETURNS TRIGGER AS
+$$
+BEGIN
+    NEW.search_vector := setweight(to_tsvector('russian', coalesce(NEW.name, '')), 'A') ||
+                         setweight(to_tsvector('russian', coalesce(NEW.description, '')), 'B');
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_store_search_vector
+    BEFORE INSERT OR UPDATE OF name, description
+    ON store
+    FOR EACH ROW
+EXECUTE FUNCTION name_description_search_vector();
+
+CREATE TRIGGER trg_item_search_vector
+    BEFORE INSERT OR UPDATE OF name, description
+    ON item
+    FOR EACH ROW
+EXECUTE FUNCTION name_description_search_vector();
+
+update store
+set search_vector = setweight(to_tsvector('russian', coalesce(name, '')), 'A') ||
+                    setweight(to_tsvector('russian', coalesce(description, '')), 'B');
+
+update item
+set search_vector = setweight(to_tsvector('russian', coalesce(name, '')), 'A') ||
+                    setweight(to_tsvector('russian', coalesce(description, '')), 'B');
+
+CREATE INDEX idx_store_search_vector ON store USING gin (search_vector);
+CREATE INDEX idx_item_search_vector ON item USING gin (search_vector);
+CREATE INDEX idx_store_name_trgm ON store USING gin (lower(name) gin_trgm_ops);
+CREATE INDEX idx_item_name_trgm ON item USING gin (lower(name) gin_trgm_ops);
+
+---- create above / drop below ----
+drop index if exists idx_item_name_trgm;
+drop index if exists idx_store_name_trgm;
+drop index if exists idx_item_search_vector;
+drop index if exists idx_store_search_vector;
+
+drop trigger if exists trg_item_search_vector on item;
+drop trigger if exists trg_store_search_vector on store;
+drop function if exists name_description_search_vector();
+
+alter table item
+    drop column if exists search_vector;
+
+alter table store
+    drop column if exists search_vector;
@@ -0,0 +1,77 @@
+-- Write your migrate up statements here
+-- версия каталога растет при изменении магазинов, меню, тегов и типов;
+-- store_service сверяет ее, чтобы перестроить индекс подсказок. У магазинов,
+-- товаров и позиций меню версию двигают только поля, попадающие в индекс:
+-- рейтинг, цены и остатки меняются часто и перестраивать индекс не должны
+create table if not exists catalog_version
+(
+    id         boolean primary key default true check (id),
+    version    bigint      not null default 0,
+    updated_at timestamptz not null default current_timestamp
+);
+
+insert into catalog_version (id)
+values (true)
+on conflict do nothing;
+
+CREATE OR REPLACE FUNCTION bump_catalog_version()
+    RETURNS TRIGGER AS
+$$
+BEGIN
+    UPDATE catalog_version SET version = version + 1, updated_at = current_timestamp;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_store_catalog_version
+    AFTER INSERT OR DELETE OR UPDATE OF name, city_id
+    ON store
+    FOR EACH STATEMENT
+EXECUTE FUNCTION bump_catalog_version();
+
+CREATE TRIGGER trg_item_catalog_version
+    AFTER INSERT OR DELETE OR UPDATE OF name, archived_at
+    ON item
+    FOR EACH STATEMENT
+EXECUTE FUNCTION bump_catalog_version();
+
+CREATE TRIGGER trg_store_item_catalog_version
+    AFTER INSERT OR DELETE OR UPDATE OF store_id, item_id, archived_at
+    ON store_item
+    FOR EACH STATEMENT
+EXECUTE FUNCTION bump_catalog_version();
+
+CREATE TRIGGER trg_item_type_catalog_version
+    AFTER INSERT OR UPDATE OR DELETE
+    ON item_type
+    FOR EACH STATEMENT
+EXECUTE FUNCTION bump_catalog_version();
+
+CREATE TRIGGER trg_store_tag_catalog_version
+    AFTER INSERT OR UPDATE OR DELETE
+    ON store_tag
+    FOR EACH STATEMENT
+EXECUTE FUNCTION bump_catalog_version();
+
+CREATE TRIGGER trg_tag_catalog_version
+    AFTER INSERT OR UPDATE OR DELETE
+    ON tag
+    FOR EACH STATEMENT
+EXECUTE FUNCTION bump_catalog_version();
+
+CREATE TRIGGER trg_type_catalog_version
+    AFTER INSERT OR UPDATE OR DELETE
+    ON type
+    FOR EACH STATEMENT
+EXECUTE FUNCTION bump_catalog_version();
+
+---- create above / drop below ----
+drop trigger if exists trg_type_catalog_version on type;
+drop trigger if exists trg_tag_catalog_version on tag;
+drop trigger if exists trg_store_tag_catalog_version on store_tag;
+drop trigger if exists trg_item_type_catalog_version on item_type;
+drop trigger if exists trg_store_item_catalog_version on store_item;
+drop trigger if exists trg_item_catalog_version on item;
+drop trigger if exists trg_store_catalog_version on store;
+drop function if exists bump_catalog_version();
+drop table if exists catalog_version;
@@ -0,0 +1,58 @@
+-- Write your migrate up statements here
+-- координаты магазинов и адресов пользователей и зоны доставки. Геометрию
+-- считает сервис, в базе лежат GeoJSON зоны и описанный вокруг нее
+-- прямоугольник, по которому отбираются кандидаты
+alter table store
+    add column if not exists lat double precision,
+    add column if not exists lon double precision,
+    add column if not exists delivery_radius_m integer,
+    add column if not exists delivery_area jsonb,
+    add column if not exists zone_min_lat double precision,
+    add column if not exists zone_min_lon double precision,
+    add column if not exists zone_max_lat double precision,
+    add column if not exists zone_max_lon double precision,
+    add constraint store_coordinates_check check (
+        (lat is null) = (lon is null)
+            and lat between -90 and 90
+            and lon between -180 and 180
+        ),
+    add constraint store_delivery_radius_m_check check (
+        delivery_radius_m is null or (delivery_radius_m between 1 and 100000 and lat is not null)
+        ),
+    add constraint store_delivery_zone_check check (
+        (delivery_radius_m is null or delivery_area is null)
+            and (delivery_area is null or lat is not null)
+            and (zone_min_lat is null) = (delivery_radius_m is null and delivery_area is null)
+        );
+
+CREATE INDEX idx_store_zone_bounds ON store (zone_min_lat, zone_max_lat) WHERE zone_min_lat IS NOT NULL;
+
+alter table account
+    add column if not exists lat double precision,
+    add column if not exists lon double precision,
+    add constraint account_coordinates_check check (
+        (lat is null) = (lon is null)
+            and lat between -90 and 90
+            and lon between -180 and 180
+        );
+
+---- create above / drop below ----
+alter table account
+    drop constraint if exists account_coordinates_check,
+    drop column if exists lon,
+    drop column if exists lat;
+
+drop index if exists idx_store_zone_bounds;
+
+alter table store
+    drop constraint if exists store_delivery_zone_check,
+    drop constraint if exists store_delivery_radius_m_check,
+    drop constraint if exists store_coordinates_check,
+    drop column if exists zone_max_lon,
+    drop column if exists zone_max_lat,
+    drop column if exists zone_min_lon,
+    drop column if exists zone_min_lat,
+    drop column if exists delivery_area,
+    drop column if exists delivery_radius_m,
+    drop column if exists lon,
+    drop column if exists lat;
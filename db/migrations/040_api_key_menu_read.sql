@@ -0,0 +1,23 @@
+-- Write your migrate up statements here
+-- отдельный scope для чтения меню: интеграции, которой нужно только меню,
+-- не выдается право его менять
+alter table api_key
+    drop constraint if exists api_key_scopes_check,
+    add constraint api_key_scopes_check check (
+        cardinality(scopes) > 0 and scopes <@ array ['menu:read', 'menu:write', 'orders:read']
+        );
+
+---- create above / drop below ----
+update api_key
+set scopes = array_remove(scopes, 'menu:read')
+where 'menu:read' = any (scopes);
+
+delete
+from api_key
+where cardinality(scopes) = 0;
+
+alter table api_key
+    drop constraint if exists api_key_scopes_check,
+    add constraint api_key_scopes_check check (
+        cardinality(scopes) > 0 and scopes <@ array ['menu:write', 'orders:read']
+        );
@@ -4,7 +4,10 @@ import (
 	"apple_backend/order_service/internal/config"
 	shttp "apple_backend/order_service/internal/delivery/http"
 	"apple_backend/order_service/internal/delivery/middlewares"
+	"apple_backend/pkg/csrf"
+	"apple_backend/pkg/jwtkeys"
 	"apple_backend/pkg/logger"
+	"apple_backend/pkg/session"
 	"context"
 	"fmt"
 	"log"
@@ -13,6 +16,12 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+func csrfOptions(apiPrefix string) csrf.Options {
+	opts := csrf.OptionsFromEnv()
+	opts.Exempt = []string{apiPrefix + "payments/webhook"}
+	return opts
+}
+
 func Run() {
 	conf := config.MustConfig()
 	apiV0Prefix := "/api/v0/"
@@ -29,10 +38,10 @@ func Run() {
 	openMux.HandleFunc(apiV0Prefix+"fake-payment", fakeHandler.FakePayment)
 
 	protectedMux := http.NewServeMux()
-	shttp.NewOrderRouter(protectedMux, dbPool, apiV0Prefix)
+	shttp.NewOrderRouter(protectedMux, dbPool, apiV0Prefix, conf.RequireVerifiedEmail)
 	shttp.NewPaymentRouter(protectedMux, dbPool, conf, apiV0Prefix)
 
-	protectedHandler := middlewares.AuthMiddleware(protectedMux, conf.JWTSecret)
+	protectedHandler := middlewares.AuthMiddleware(protectedMux, jwtkeys.NewRemoteKeySet(conf.JWKSURL, 0).Keyfunc, session.NewPostgresChecker(dbPool))
 
 	mux := http.NewServeMux()
 	mux.Handle(apiV0Prefix+"orders", protectedHandler)
@@ -43,7 +52,10 @@ func Run() {
 
 	handler := middlewares.AccessLog(
 		logger.Global(),
-		middlewares.CorsMiddleware(mux),
+		middlewares.CorsMiddleware(
+			// вебхук ЮKassa вызывается сервером, а не браузером
+			csrf.New(conf.CSRFSecret, csrfOptions(apiV0Prefix)).Middleware(mux),
+		),
 	)
 
 	addr := fmt.Sprintf("0.0.0.0:%s", conf.AppPort)
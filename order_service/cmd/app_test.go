@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	shttp "apple_backend/order_service/internal/delivery/http"
+	"apple_backend/order_service/internal/delivery/middlewares"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pashagolub/pgxmock/v2"
+	"github.com/stretchr/testify/require"
+)
+
+// order_service API-ключи не принимает: заказы магазинов интеграции читают
+// через store_service (GET stores/{id}/orders со scope orders:read)
+func TestAuthMiddleware_RejectsAPIKeys(t *testing.T) {
+	const (
+		apiPrefix  = "/api/v0/"
+		partnerKey = "dck_0123456789ab_partner"
+		orderID    = "00000000-0000-0000-0000-000000000030"
+	)
+
+	db, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer db.Close()
+
+	protectedMux := http.NewServeMux()
+	shttp.NewOrderRouter(protectedMux, db, apiPrefix, false)
+	handler := middlewares.AuthMiddleware(protectedMux, nil, nil)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Authorization", "Bearer "+partnerKey)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get(apiPrefix + "orders?limit=10")
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = get(apiPrefix + "orders/" + orderID)
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// до репозитория запросы не доходят
+	require.NoError(t, db.ExpectationsWereMet())
+}
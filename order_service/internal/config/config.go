@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/go-playground/validator/v10"
 )
@@ -16,7 +17,19 @@ type Config struct {
 
 	AppPort string `validate:"required"`
 
-	JWTSecret string `validate:"required"`
+	// JWKSURL — адрес публичных ключей auth_service для проверки JWT
+	JWKSURL string `validate:"required,url"`
+
+	// AuthURL и IntrospectSecret — для онлайн-проверки токена в auth_service
+	// перед созданием платежа
+	AuthURL          string `validate:"required,url"`
+	IntrospectSecret string `validate:"required"`
+
+	// CSRFSecret — общий для всех сервисов ключ подписи CSRF-токенов
+	CSRFSecret string `validate:"required"`
+
+	// RequireVerifiedEmail запрещает оформление заказа без подтвержденного email
+	RequireVerifiedEmail bool
 
 	YookassaShopID  string `validate:"required"`
 	YookassaSecret  string `validate:"required"`
@@ -31,7 +44,13 @@ func MustConfig() *Config {
 		DBPort:     os.Getenv("API_DB_PORT"),
 		DBName:     os.Getenv("DB_NAME"),
 		AppPort:    os.Getenv("ORDER_SERVICE_PORT"),
-		JWTSecret:  os.Getenv("SECRET_KEY"),
+		JWKSURL:    os.Getenv("JWKS_URL"),
+
+		AuthURL:          getEnv("AUTH_URL", "http://auth_service:8082"),
+		IntrospectSecret: os.Getenv("INTROSPECT_SECRET"),
+		CSRFSecret:       os.Getenv("CSRF_SECRET"),
+
+		RequireVerifiedEmail: strings.EqualFold(os.Getenv("REQUIRE_VERIFIED_EMAIL"), "true"),
 
 		YookassaShopID:  os.Getenv("YOOKASSA_SHOP_ID"),
 		YookassaSecret:  os.Getenv("YOOKASSA_SECRET_KEY"),
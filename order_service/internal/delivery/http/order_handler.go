@@ -3,6 +3,7 @@ package http
 import (
 	"apple_backend/order_service/internal/delivery/middlewares"
 	"apple_backend/order_service/internal/repository"
+	"apple_backend/pkg/account"
 	"apple_backend/pkg/http_response"
 	"apple_backend/pkg/logger"
 	"context"
@@ -40,17 +41,22 @@ func NewOrderHandler(uc OrderUsecaseInterface) *OrderHandler {
 	}
 }
 
-func NewOrderRouter(mux *http.ServeMux, db repository.PgxIface, apiPrefix string) {
+func NewOrderRouter(mux *http.ServeMux, db repository.PgxIface, apiPrefix string, requireVerifiedEmail bool) {
 	orderRepo := repository.NewOrderRepoPostgres(db)
 	orderUC := usecase.NewOrderUsecase(orderRepo)
 	orderHandler := NewOrderHandler(orderUC)
 
+	createOrder := orderHandler.CreateOrder
+	if requireVerifiedEmail {
+		createOrder = middlewares.RequireVerifiedEmail(createOrder, account.NewPostgresVerificationChecker(db))
+	}
+
 	mux.HandleFunc(apiPrefix+"orders", func(w http.ResponseWriter, r *http.Request) {
 		switch r.Method {
 		case http.MethodGet:
 			orderHandler.GetOrdersUser(w, r)
 		case http.MethodPost:
-			orderHandler.CreateOrder(w, r)
+			createOrder(w, r)
 		default:
 			ctx := r.Context()
 			log := logger.FromContext(ctx)
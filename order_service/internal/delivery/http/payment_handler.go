@@ -8,8 +8,12 @@ import (
 	"apple_backend/order_service/internal/infrastructure/yookassa"
 	"apple_backend/order_service/internal/repository"
 	"apple_backend/order_service/internal/usecase"
+	"apple_backend/pkg/account"
 	"apple_backend/pkg/http_response"
+	"apple_backend/pkg/introspect"
+	"apple_backend/pkg/jwtkeys"
 	"apple_backend/pkg/logger"
+	"apple_backend/pkg/session"
 	"context"
 	"encoding/json"
 	"errors"
@@ -54,11 +58,19 @@ func NewPaymentRouter(mux *http.ServeMux, db *pgxpool.Pool, config *config.Confi
 
 	mux.HandleFunc(apiPrefix+"payments/webhook", paymentHandler.HandleWebhook)
 
+	createPayment := paymentHandler.CreatePayment
+	if config.RequireVerifiedEmail {
+		createPayment = middlewares.RequireVerifiedEmail(createPayment, account.NewPostgresVerificationChecker(db))
+	}
+	// списание денег не должно пройти по токену отозванной сессии
+	tokens := introspect.NewClient(config.AuthURL, config.IntrospectSecret, introspect.DefaultTTL)
+	createPayment = tokens.RequireActive(middlewares.JwtCookieName, http.HandlerFunc(createPayment)).ServeHTTP
+
 	protectedMux := http.NewServeMux()
 	protectedMux.HandleFunc(apiPrefix+"payments", func(w http.ResponseWriter, r *http.Request) {
 		switch r.Method {
 		case http.MethodPost:
-			paymentHandler.CreatePayment(w, r)
+			createPayment(w, r)
 		default:
 			ctx := r.Context()
 			log := logger.FromContext(ctx)
@@ -68,7 +80,7 @@ func NewPaymentRouter(mux *http.ServeMux, db *pgxpool.Pool, config *config.Confi
 	})
 	protectedMux.HandleFunc(apiPrefix+"payments/order/{id}", paymentHandler.GetPaymentByOrderID)
 
-	protectedHandler := middlewares.AuthMiddleware(protectedMux, config.JWTSecret)
+	protectedHandler := middlewares.AuthMiddleware(protectedMux, jwtkeys.NewRemoteKeySet(config.JWKSURL, 0).Keyfunc, session.NewPostgresChecker(db))
 	mux.Handle(apiPrefix+"payments", protectedHandler)
 	mux.Handle(apiPrefix+"payments/", protectedHandler)
 }
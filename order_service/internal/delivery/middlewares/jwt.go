@@ -1,7 +1,10 @@
 package middlewares
 
 import (
+	"apple_backend/pkg/logger"
+	"apple_backend/pkg/session"
 	"context"
+	"log/slog"
 	"net/http"
 
 	"github.com/golang-jwt/jwt/v4"
@@ -13,6 +16,8 @@ type ctxKey string
 
 var UserIDKey ctxKey = "user_id"
 
+var RolesKey ctxKey = "roles"
+
 func WithUserID(ctx context.Context, userID string) context.Context {
 	return context.WithValue(ctx, UserIDKey, userID)
 }
@@ -23,9 +28,18 @@ func UserIDFromContext(ctx context.Context) (string, bool) {
 	return id, ok
 }
 
-func AuthMiddleware(next http.Handler, jwtSecret string) http.Handler {
-	secret := []byte(jwtSecret)
+func WithRoles(ctx context.Context, roles []string) context.Context {
+	return context.WithValue(ctx, RolesKey, roles)
+}
 
+func RolesFromContext(ctx context.Context) []string {
+	roles, _ := ctx.Value(RolesKey).([]string)
+	return roles
+}
+
+// AuthMiddleware проверяет подпись токена публичными ключами auth_service
+// (keyfunc выбирает ключ по kid, см. jwtkeys.RemoteKeySet) и что сессия не отозвана.
+func AuthMiddleware(next http.Handler, keyfunc jwt.Keyfunc, sessions session.Checker) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		c, err := r.Cookie(JwtCookieName)
 		if err != nil {
@@ -33,21 +47,30 @@ func AuthMiddleware(next http.Handler, jwtSecret string) http.Handler {
 			return
 		}
 		type claims struct {
-			UserID string `json:"user_id"`
+			UserID    string   `json:"user_id"`
+			SessionID string   `json:"sid"`
+			Roles     []string `json:"roles"`
 			jwt.RegisteredClaims
 		}
 		cl := &claims{}
-		tkn, err := jwt.ParseWithClaims(c.Value, cl, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, jwt.ErrSignatureInvalid
-			}
-			return secret, nil
-		})
-		if err != nil || !tkn.Valid || cl.UserID == "" {
+		tkn, err := jwt.ParseWithClaims(c.Value, cl, keyfunc)
+		if err != nil || !tkn.Valid || cl.UserID == "" || cl.SessionID == "" {
+			http.Error(w, `{"error":"unauthorized"}`, http.StatusUnauthorized)
+			return
+		}
+		active, err := sessions.IsActive(r.Context(), cl.SessionID)
+		if err != nil {
+			logger.FromContext(r.Context()).ErrorContext(r.Context(), "AuthMiddleware session check failed",
+				slog.Any("err", err), slog.String("session_id", cl.SessionID))
+			http.Error(w, `{"error":"internal server error"}`, http.StatusInternalServerError)
+			return
+		}
+		if !active {
 			http.Error(w, `{"error":"unauthorized"}`, http.StatusUnauthorized)
 			return
 		}
 		ctx := WithUserID(r.Context(), cl.UserID)
+		ctx = WithRoles(ctx, cl.Roles)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
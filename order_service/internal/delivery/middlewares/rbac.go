@@ -0,0 +1,34 @@
+package middlewares
+
+import (
+	"apple_backend/pkg/rbac"
+	"net/http"
+)
+
+// RequireRole пропускает запрос, если у пользователя есть хотя бы одна из
+// ролей. Должен стоять после AuthMiddleware.
+func RequireRole(roles ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !rbac.HasRole(RolesFromContext(r.Context()), roles...) {
+				http.Error(w, `{"error":"forbidden"}`, http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequirePermission пропускает запрос, если разрешение есть у одной из ролей
+// пользователя. Должен стоять после AuthMiddleware.
+func RequirePermission(perm string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !rbac.HasPermission(RolesFromContext(r.Context()), perm) {
+				http.Error(w, `{"error":"forbidden"}`, http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package account
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// VerificationChecker сообщает, подтвердил ли пользователь свой email.
+type VerificationChecker interface {
+	IsEmailVerified(ctx context.Context, userID string) (bool, error)
+}
+
+type rowQuerier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+const isEmailVerifiedSQL = `SELECT email_verified_at IS NOT NULL FROM account WHERE id = $1`
+
+type PostgresVerificationChecker struct {
+	db rowQuerier
+}
+
+func NewPostgresVerificationChecker(db rowQuerier) *PostgresVerificationChecker {
+	return &PostgresVerificationChecker{db: db}
+}
+
+func (c *PostgresVerificationChecker) IsEmailVerified(ctx context.Context, userID string) (bool, error) {
+	var verified bool
+	err := c.db.QueryRow(ctx, isEmailVerifiedSQL, userID).Scan(&verified)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return verified, nil
+}
@@ -0,0 +1,170 @@
+// Package apikey — персональные API-ключи для интеграций (кассы партнеров).
+//
+// Ключ выглядит как dck_<prefix>_<secret>. Prefix открыт: по нему ключ
+// находится в таблице api_key и показывается пользователю в списке ключей.
+// Secret хранится только в виде SHA-256 — он случайный и длинный, поэтому
+// медленный хэш не нужен. Ключ выдает auth_service, а проверяют сервисы,
+// читая общую таблицу api_key, как и pkg/session.
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const (
+	// TokenPrefix отличает API-ключ от других Bearer-токенов
+	TokenPrefix = "dck_"
+
+	prefixBytes = 6
+	secretBytes = 32
+
+	// lastUsedGranularity — чаще last_used_at не обновляется, чтобы каждый
+	// запрос интеграции не писал в БД
+	lastUsedGranularity = time.Minute
+)
+
+var (
+	ErrInvalidKey = errors.New("apikey: invalid key")
+	ErrForbidden  = errors.New("apikey: route not allowed for key")
+)
+
+// Generate создает новый ключ. Возвращает открытый префикс, SHA-256 секрета
+// для хранения и сам ключ, который показывается пользователю один раз.
+func Generate() (prefix, secretHash, token string, err error) {
+	p := make([]byte, prefixBytes)
+	if _, err := rand.Read(p); err != nil {
+		return "", "", "", err
+	}
+	s := make([]byte, secretBytes)
+	if _, err := rand.Read(s); err != nil {
+		return "", "", "", err
+	}
+	prefix = hex.EncodeToString(p)
+	secret := base64.RawURLEncoding.EncodeToString(s)
+	return prefix, HashSecret(secret), TokenPrefix + prefix + "_" + secret, nil
+}
+
+// Parse разбирает ключ на префикс и секрет
+func Parse(token string) (prefix, secret string, ok bool) {
+	rest, found := strings.CutPrefix(token, TokenPrefix)
+	if !found || len(rest) < prefixBytes*2+2 || rest[prefixBytes*2] != '_' {
+		return "", "", false
+	}
+	prefix, secret = rest[:prefixBytes*2], rest[prefixBytes*2+1:]
+	if _, err := hex.DecodeString(prefix); err != nil {
+		return "", "", false
+	}
+	return prefix, secret, true
+}
+
+func HashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// BearerToken — API-ключ из заголовка Authorization: Bearer. Другие
+// Bearer-токены не возвращаются.
+func BearerToken(r *http.Request) (string, bool) {
+	scheme, token, found := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	if !strings.HasPrefix(token, TokenPrefix) {
+		return "", false
+	}
+	return token, true
+}
+
+// Key — проверенный ключ. Roles — текущие роли владельца: ключ не дает
+// больше прав, чем есть у пользователя сейчас.
+type Key struct {
+	ID     string
+	UserID string
+	Scopes []string
+	Roles  []string
+}
+
+func (k *Key) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+type Verifier interface {
+	Verify(ctx context.Context, token string) (*Key, error)
+}
+
+type pgQuerier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+}
+
+const getKeySQL = `
+SELECT k.id,
+       k.user_id,
+       k.secret_hash,
+       k.scopes,
+       array(SELECT r.role FROM account_role r WHERE r.user_id = k.user_id ORDER BY r.role),
+       k.last_used_at
+FROM api_key k
+WHERE k.prefix = $1
+  AND k.revoked_at IS NULL
+  AND k.expires_at > current_timestamp`
+
+const touchKeySQL = `UPDATE api_key SET last_used_at = current_timestamp WHERE id = $1`
+
+// PostgresVerifier проверяет ключи по общей таблице api_key.
+type PostgresVerifier struct {
+	db  pgQuerier
+	now func() time.Time
+}
+
+func NewPostgresVerifier(db pgQuerier) *PostgresVerifier {
+	return &PostgresVerifier{db: db, now: time.Now}
+}
+
+// Verify возвращает ErrInvalidKey для неизвестного, отозванного или
+// истекшего ключа и отмечает время использования.
+func (v *PostgresVerifier) Verify(ctx context.Context, token string) (*Key, error) {
+	prefix, secret, ok := Parse(token)
+	if !ok {
+		return nil, ErrInvalidKey
+	}
+	var (
+		key      Key
+		hash     string
+		lastUsed *time.Time
+	)
+	err := v.db.QueryRow(ctx, getKeySQL, prefix).Scan(&key.ID, &key.UserID, &hash, &key.Scopes, &key.Roles, &lastUsed)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrInvalidKey
+	}
+	if err != nil {
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(hash), []byte(HashSecret(secret))) != 1 {
+		return nil, ErrInvalidKey
+	}
+	if lastUsed == nil || v.now().Sub(*lastUsed) >= lastUsedGranularity {
+		if _, err := v.db.Exec(ctx, touchKeySQL, key.ID); err != nil {
+			return nil, err
+		}
+	}
+	return &key, nil
+}
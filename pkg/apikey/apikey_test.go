@@ -0,0 +1,81 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGenerateParse(t *testing.T) {
+	prefix, hash, token, err := Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, secret, ok := Parse(token)
+	if !ok || p != prefix || HashSecret(secret) != hash {
+		t.Fatalf("parse mismatch: token=%q prefix=%q", token, p)
+	}
+	for _, bad := range []string{"", "dck_", "dck_short_x", "xyz_0123456789ab_secret", "dck_0123456789zz_secret"} {
+		if _, _, ok := Parse(bad); ok {
+			t.Errorf("expected %q to be rejected", bad)
+		}
+	}
+}
+
+func TestBearerToken(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	if _, ok := BearerToken(r); ok {
+		t.Fatal("expected no token without header")
+	}
+	r.Header.Set("Authorization", "Bearer eyJhbGciOi.jwt")
+	if _, ok := BearerToken(r); ok {
+		t.Fatal("JWT must not be taken for an API key")
+	}
+	r.Header.Set("Authorization", "bearer dck_0123456789ab_s")
+	if tok, ok := BearerToken(r); !ok || tok != "dck_0123456789ab_s" {
+		t.Fatalf("unexpected token %q", tok)
+	}
+}
+
+type verifierFunc func(ctx context.Context, token string) (*Key, error)
+
+func (f verifierFunc) Verify(ctx context.Context, token string) (*Key, error) { return f(ctx, token) }
+
+func TestGuard(t *testing.T) {
+	g := NewGuard(verifierFunc(func(_ context.Context, token string) (*Key, error) {
+		if token != "good" {
+			return nil, ErrInvalidKey
+		}
+		return &Key{ID: "k1", UserID: "u1", Scopes: []string{"orders:read"}}, nil
+	}))
+	g.Allow("GET /api/v0/orders/{id}", "orders:read")
+	g.Allow("PUT /api/v0/stores/{id}/items/{item}", "menu:write")
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		token  string
+		want   error
+	}{
+		{name: "allowed", method: http.MethodGet, path: "/api/v0/orders/1", token: "good"},
+		{name: "bad key", method: http.MethodGet, path: "/api/v0/orders/1", token: "bad", want: ErrInvalidKey},
+		{name: "route not allowed", method: http.MethodGet, path: "/api/v0/cart", token: "good", want: ErrForbidden},
+		{name: "method not allowed", method: http.MethodDelete, path: "/api/v0/orders/1", token: "good", want: ErrForbidden},
+		{name: "scope missing", method: http.MethodPut, path: "/api/v0/stores/1/items/2", token: "good", want: ErrForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, tt.path, nil)
+			key, err := g.Authenticate(context.Background(), r, tt.token)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+			if tt.want == nil && key.UserID != "u1" {
+				t.Fatalf("unexpected key %+v", key)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package apikey
+
+import (
+	"context"
+	"net/http"
+)
+
+// scopeHandler — метка маршрута в mux Guard, сам он запросы не обслуживает
+type scopeHandler string
+
+func (scopeHandler) ServeHTTP(http.ResponseWriter, *http.Request) {}
+
+// Guard пускает запросы с API-ключом только на явно разрешенные маршруты и
+// только если у ключа есть нужный scope. Все остальное (корзина, оплата,
+// профиль) по ключу недоступно.
+type Guard struct {
+	verifier Verifier
+	routes   *http.ServeMux
+}
+
+func NewGuard(verifier Verifier) *Guard {
+	return &Guard{verifier: verifier, routes: http.NewServeMux()}
+}
+
+// Allow разрешает маршрут (шаблон http.ServeMux, например
+// "GET /api/v0/orders/{id}") ключам со scope.
+func (g *Guard) Allow(pattern, scope string) {
+	g.routes.Handle(pattern, scopeHandler(scope))
+}
+
+// Authenticate проверяет ключ и доступ к маршруту запроса. Возвращает
+// ErrInvalidKey, если ключ не подошел, и ErrForbidden, если маршрут закрыт
+// для ключей или у ключа нет нужного scope.
+func (g *Guard) Authenticate(ctx context.Context, r *http.Request, token string) (*Key, error) {
+	key, err := g.verifier.Verify(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	h, _ := g.routes.Handler(r)
+	scope, ok := h.(scopeHandler)
+	if !ok || !key.HasScope(string(scope)) {
+		return nil, ErrForbidden
+	}
+	return key, nil
+}
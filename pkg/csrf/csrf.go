@@ -0,0 +1,287 @@
+// Package csrf выдает и проверяет CSRF-токены, общие для всех сервисов.
+//
+// Токен — это <срок>.<nonce>.<сессия>.<подпись>, где сессия — короткий хэш
+// sid из JWT-cookie («-» до входа), а подпись — HMAC-SHA256 всего
+// остального на CSRF_SECRET. Токен лежит в читаемой из JS cookie
+// csrf_token, клиент повторяет его в заголовке X-CSRF-Token (double
+// submit). Подделать токен без CSRF_SECRET нельзя, а токен чужой или
+// прошлой сессии не подходит, поэтому при входе токен выдается заново.
+//
+// Access-cookie живет меньше сессии, поэтому запрос без нее (например,
+// /auth/refresh) принимает токен любой сессии — подпись при этом все равно
+// проверяется.
+//
+// Запросы интеграций с ключом в Authorization: Bearer и без cookie сессии
+// не проверяются: такой заголовок браузер сам не подставит.
+package csrf
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	CookieName = "csrf_token"
+	HeaderName = "X-CSRF-Token"
+
+	// SessionCookieName — cookie с access-токеном, из которого берется сессия
+	SessionCookieName = "jwt_token"
+
+	DefaultTTL = 12 * time.Hour
+
+	nonceSize   = 16
+	bindingSize = 12
+	anonymous   = "-"
+)
+
+var (
+	ErrMissing  = errors.New("csrf: token required")
+	ErrMismatch = errors.New("csrf: token mismatch")
+	ErrInvalid  = errors.New("csrf: invalid token")
+	ErrExpired  = errors.New("csrf: token expired")
+)
+
+var b64 = base64.RawURLEncoding
+
+// Options — настройки cookie и срока жизни токена
+type Options struct {
+	TTL      time.Duration
+	Secure   bool
+	SameSite http.SameSite
+	Domain   string
+	// Exempt — пути, которые вызывают не браузеры (вебхуки платежей и т. п.)
+	Exempt []string
+}
+
+// OptionsFromEnv читает COOKIE_SECURE, COOKIE_SAMESITE и COOKIE_DOMAIN, как
+// и остальные cookie сервисов
+func OptionsFromEnv() Options {
+	opts := Options{
+		Secure:   strings.EqualFold(os.Getenv("COOKIE_SECURE"), "true"),
+		SameSite: http.SameSiteLaxMode,
+		Domain:   os.Getenv("COOKIE_DOMAIN"),
+	}
+	switch strings.ToLower(os.Getenv("COOKIE_SAMESITE")) {
+	case "strict":
+		opts.SameSite = http.SameSiteStrictMode
+	case "none":
+		opts.SameSite = http.SameSiteNoneMode
+		opts.Secure = true
+	}
+	return opts
+}
+
+type Protector struct {
+	secret []byte
+	opts   Options
+	exempt map[string]bool
+	now    func() time.Time
+}
+
+func New(secret string, opts Options) *Protector {
+	if opts.TTL <= 0 {
+		opts.TTL = DefaultTTL
+	}
+	exempt := make(map[string]bool, len(opts.Exempt))
+	for _, p := range opts.Exempt {
+		exempt[p] = true
+	}
+	return &Protector{secret: []byte(secret), opts: opts, exempt: exempt, now: time.Now}
+}
+
+// Issue создает токен для сессии sessionID ("" — до входа)
+func (p *Protector) Issue(sessionID string) (string, time.Time) {
+	expires := p.now().Add(p.opts.TTL)
+	nonce := make([]byte, nonceSize)
+	_, _ = rand.Read(nonce)
+
+	payload := strconv.FormatInt(expires.Unix(), 10) + "." + b64.EncodeToString(nonce) + "." + binding(sessionID)
+	return payload + "." + b64.EncodeToString(p.sign(payload)), expires
+}
+
+// Verify проверяет подпись, срок и привязку токена к сессии. Пустой
+// sessionID означает, что сессия запроса неизвестна.
+func (p *Protector) Verify(token, sessionID string) error {
+	t, ok := parse(token)
+	if !ok {
+		return ErrInvalid
+	}
+	mac, err := b64.DecodeString(t.sig)
+	if err != nil || !hmac.Equal(mac, p.sign(t.payload)) {
+		return ErrInvalid
+	}
+	if sessionID != "" && t.binding != binding(sessionID) {
+		return ErrInvalid
+	}
+	if !p.now().Before(t.expires) {
+		return ErrExpired
+	}
+	return nil
+}
+
+type parsedToken struct {
+	payload, binding, sig string
+	expires               time.Time
+}
+
+func parse(token string) (parsedToken, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 4 {
+		return parsedToken{}, false
+	}
+	unix, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return parsedToken{}, false
+	}
+	return parsedToken{
+		payload: strings.Join(parts[:3], "."),
+		binding: parts[2],
+		sig:     parts[3],
+		expires: time.Unix(unix, 0),
+	}, true
+}
+
+// binding — короткий хэш sid: сам идентификатор сессии в читаемую из JS
+// cookie не попадает
+func binding(sessionID string) string {
+	if sessionID == "" {
+		return anonymous
+	}
+	sum := sha256.Sum256([]byte(sessionID))
+	return b64.EncodeToString(sum[:bindingSize])
+}
+
+func (p *Protector) sign(payload string) []byte {
+	mac := hmac.New(sha256.New, p.secret)
+	mac.Write([]byte("csrf/v1\n" + payload))
+	return mac.Sum(nil)
+}
+
+// SetCookie выдает новый токен для сессии и кладет его в cookie
+func (p *Protector) SetCookie(w http.ResponseWriter, sessionID string) string {
+	token, expires := p.Issue(sessionID)
+	http.SetCookie(w, &http.Cookie{
+		Name:     CookieName,
+		Value:    token,
+		Path:     "/",
+		Expires:  expires,
+		HttpOnly: false,
+		Secure:   p.opts.Secure,
+		SameSite: p.opts.SameSite,
+		Domain:   p.opts.Domain,
+	})
+	w.Header().Set(HeaderName, token)
+	return token
+}
+
+// SessionID — сессия из JWT-cookie. Подпись здесь не проверяется: токен
+// проверит AuthMiddleware, а для CSRF важно лишь, что сессия та же.
+func SessionID(r *http.Request) string {
+	c, err := r.Cookie(SessionCookieName)
+	if err != nil || c.Value == "" {
+		return ""
+	}
+	claims := jwt.MapClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(c.Value, claims); err != nil {
+		return ""
+	}
+	sid, _ := claims["sid"].(string)
+	return sid
+}
+
+// Check проверяет изменяющий запрос: заголовок совпадает с cookie и токен
+// подписан для текущей сессии
+func (p *Protector) Check(r *http.Request) error {
+	header := r.Header.Get(HeaderName)
+	if header == "" {
+		return ErrMissing
+	}
+	cookie, err := r.Cookie(CookieName)
+	if err != nil || cookie.Value == "" {
+		return ErrMissing
+	}
+	if subtle.ConstantTimeCompare([]byte(header), []byte(cookie.Value)) != 1 {
+		return ErrMismatch
+	}
+	return p.Verify(header, SessionID(r))
+}
+
+// needsRefresh — cookie нет, она не от этой сессии или истекает меньше
+// чем через половину срока
+func (p *Protector) needsRefresh(r *http.Request, sessionID string) bool {
+	cookie, err := r.Cookie(CookieName)
+	if err != nil || p.Verify(cookie.Value, sessionID) != nil {
+		return true
+	}
+	t, _ := parse(cookie.Value)
+	return t.expires.Sub(p.now()) < p.opts.TTL/2
+}
+
+// bearerOnly — запрос с Authorization: Bearer без cookie сессии
+func bearerOnly(r *http.Request) bool {
+	scheme, token, found := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+		return false
+	}
+	_, err := r.Cookie(SessionCookieName)
+	return err != nil
+}
+
+func safeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// Middleware выдает токен, если его нет или он устарел, и отклоняет
+// изменяющие запросы без действительного токена с 403.
+func (p *Protector) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if p.exempt[r.URL.Path] || bearerOnly(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		sessionID := SessionID(r)
+		if p.needsRefresh(r, sessionID) {
+			p.SetCookie(w, sessionID)
+		}
+
+		if !safeMethod(r.Method) {
+			if err := p.Check(r); err != nil {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusForbidden)
+				_, _ = w.Write([]byte(`{"error":"` + errorMessage(err) + `"}`))
+				return
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func errorMessage(err error) string {
+	switch err {
+	case ErrMissing:
+		return "CSRF token required"
+	case ErrMismatch:
+		return "CSRF token mismatch"
+	case ErrExpired:
+		return "CSRF token expired"
+	default:
+		return "CSRF token invalid"
+	}
+}
@@ -0,0 +1,138 @@
+package csrf
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+func sessionCookie(t *testing.T, sid string) *http.Cookie {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sid": sid}).SignedString([]byte("k"))
+	if err != nil {
+		t.Fatalf("sign jwt: %v", err)
+	}
+	return &http.Cookie{Name: SessionCookieName, Value: token}
+}
+
+func TestVerify_BindsToSession(t *testing.T) {
+	p := New("secret", Options{})
+	token, _ := p.Issue("s1")
+
+	if err := p.Verify(token, "s1"); err != nil {
+		t.Fatalf("expected valid token, got %v", err)
+	}
+	if err := p.Verify(token, "s2"); !errors.Is(err, ErrInvalid) {
+		t.Fatalf("expected ErrInvalid for other session, got %v", err)
+	}
+	// без access-cookie сессия неизвестна, проверяется только подпись
+	if err := p.Verify(token, ""); err != nil {
+		t.Fatalf("expected token accepted without session, got %v", err)
+	}
+}
+
+func TestVerify_RejectsForgedAndExpired(t *testing.T) {
+	p := New("secret", Options{TTL: time.Minute})
+	token, _ := p.Issue("")
+
+	if err := New("other", Options{}).Verify(token, ""); !errors.Is(err, ErrInvalid) {
+		t.Fatalf("expected ErrInvalid for foreign secret, got %v", err)
+	}
+	parts := strings.Split(token, ".")
+	parts[0] = "9999999999"
+	if err := p.Verify(strings.Join(parts, "."), ""); !errors.Is(err, ErrInvalid) {
+		t.Fatalf("expected ErrInvalid for altered expiry, got %v", err)
+	}
+	if err := p.Verify("garbage", ""); !errors.Is(err, ErrInvalid) {
+		t.Fatalf("expected ErrInvalid for garbage, got %v", err)
+	}
+
+	p.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	if err := p.Verify(token, ""); !errors.Is(err, ErrExpired) {
+		t.Fatalf("expected ErrExpired, got %v", err)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	p := New("secret", Options{Exempt: []string{"/webhook"}})
+	handler := p.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	anon, _ := p.Issue("")
+	bound, _ := p.Issue("s1")
+
+	tests := []struct {
+		name    string
+		method  string
+		path    string
+		header  string
+		cookie  string
+		session string
+		bearer  string
+		want    int
+	}{
+		{name: "safe method", method: http.MethodGet, path: "/", want: http.StatusNoContent},
+		{name: "no token", method: http.MethodPost, path: "/", want: http.StatusForbidden},
+		{name: "header differs from cookie", method: http.MethodPost, path: "/", header: anon, cookie: bound, want: http.StatusForbidden},
+		{name: "anonymous token", method: http.MethodPost, path: "/", header: anon, cookie: anon, want: http.StatusNoContent},
+		{name: "bound token", method: http.MethodPost, path: "/", header: bound, cookie: bound, session: "s1", want: http.StatusNoContent},
+		{name: "anonymous token after login", method: http.MethodPost, path: "/", header: anon, cookie: anon, session: "s1", want: http.StatusForbidden},
+		{name: "other session", method: http.MethodDelete, path: "/", header: bound, cookie: bound, session: "s2", want: http.StatusForbidden},
+		{name: "exempt path", method: http.MethodPost, path: "/webhook", want: http.StatusNoContent},
+		{name: "bearer without session cookie", method: http.MethodPost, path: "/", bearer: "dck_key", want: http.StatusNoContent},
+		{name: "bearer with session cookie", method: http.MethodPost, path: "/", bearer: "dck_key", session: "s1", want: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.header != "" {
+				r.Header.Set(HeaderName, tt.header)
+			}
+			if tt.cookie != "" {
+				r.AddCookie(&http.Cookie{Name: CookieName, Value: tt.cookie})
+			}
+			if tt.session != "" {
+				r.AddCookie(sessionCookie(t, tt.session))
+			}
+			if tt.bearer != "" {
+				r.Header.Set("Authorization", "Bearer "+tt.bearer)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+			if w.Code != tt.want {
+				t.Fatalf("expected %d, got %d (%s)", tt.want, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestMiddleware_IssuesCookieForSession(t *testing.T) {
+	p := New("secret", Options{})
+	handler := p.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.AddCookie(sessionCookie(t, "s1"))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	var issued string
+	for _, c := range w.Result().Cookies() {
+		if c.Name == CookieName {
+			issued = c.Value
+		}
+	}
+	if issued == "" {
+		t.Fatal("expected csrf cookie to be issued")
+	}
+	if err := p.Verify(issued, "s1"); err != nil {
+		t.Fatalf("issued token is not bound to session: %v", err)
+	}
+	if w.Header().Get(HeaderName) != issued {
+		t.Fatal("expected token in response header")
+	}
+}
@@ -0,0 +1,251 @@
+// Package geo — геометрия зон доставки без PostGIS: расстояние по
+// большому кругу, проверка попадания точки в круг и в многоугольник GeoJSON.
+// Многоугольник считается на плоскости долгота/широта; на масштабе города
+// ошибка такого приближения меньше точности самих координат. Зоны, которые
+// пересекают 180-й меридиан, не поддерживаются.
+package geo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// EarthRadius — средний радиус Земли в метрах
+const EarthRadius = 6371008.8
+
+// MaxVertices ограничивает число вершин зоны, чтобы проверка точки и ответ
+// со списком магазинов оставались дешевыми
+const MaxVertices = 2000
+
+var ErrInvalidGeometry = errors.New("geo: некорректная геометрия")
+
+// Point — точка в градусах
+type Point struct {
+	Lat float64
+	Lon float64
+}
+
+// Valid проверяет, что координаты конечны и в допустимых пределах
+func (p Point) Valid() bool {
+	return !math.IsNaN(p.Lat) && !math.IsNaN(p.Lon) &&
+		p.Lat >= -90 && p.Lat <= 90 && p.Lon >= -180 && p.Lon <= 180
+}
+
+// Distance — расстояние между точками в метрах по формуле гаверсинусов
+func Distance(a, b Point) float64 {
+	lat1, lat2 := radians(a.Lat), radians(b.Lat)
+	dLat := lat2 - lat1
+	dLon := radians(b.Lon - a.Lon)
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * EarthRadius * math.Asin(math.Sqrt(min(h, 1)))
+}
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+// Box — прямоугольник в градусах, по нему база отбирает кандидатов
+type Box struct {
+	MinLat, MinLon float64
+	MaxLat, MaxLon float64
+}
+
+func (b Box) Contains(p Point) bool {
+	return p.Lat >= b.MinLat && p.Lat <= b.MaxLat && p.Lon >= b.MinLon && p.Lon <= b.MaxLon
+}
+
+// Polygon — внешнее кольцо и дырки. Кольцо замкнуто: последняя вершина
+// совпадает с первой.
+type Polygon [][]Point
+
+// Area — мультиполигон
+type Area []Polygon
+
+// ParseArea разбирает геометрию GeoJSON типа Polygon или MultiPolygon.
+// Координаты в GeoJSON идут в порядке [долгота, широта].
+func ParseArea(data []byte) (Area, error) {
+	var g struct {
+		Type        string          `json:"type"`
+		Coordinates json.RawMessage `json:"coordinates"`
+	}
+	if err := json.Unmarshal(data, &g); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidGeometry, err)
+	}
+
+	var polygons [][][][]float64
+	switch g.Type {
+	case "Polygon":
+		var rings [][][]float64
+		if err := json.Unmarshal(g.Coordinates, &rings); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidGeometry, err)
+		}
+		polygons = [][][][]float64{rings}
+	case "MultiPolygon":
+		if err := json.Unmarshal(g.Coordinates, &polygons); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidGeometry, err)
+		}
+	default:
+		return nil, fmt.Errorf("%w: тип %q, ожидается Polygon или MultiPolygon", ErrInvalidGeometry, g.Type)
+	}
+	if len(polygons) == 0 {
+		return nil, fmt.Errorf("%w: нет многоугольников", ErrInvalidGeometry)
+	}
+
+	area := make(Area, 0, len(polygons))
+	vertices := 0
+	for _, rings := range polygons {
+		if len(rings) == 0 {
+			return nil, fmt.Errorf("%w: многоугольник без колец", ErrInvalidGeometry)
+		}
+		polygon := make(Polygon, 0, len(rings))
+		for _, ring := range rings {
+			if vertices += len(ring); vertices > MaxVertices {
+				return nil, fmt.Errorf("%w: больше %d вершин", ErrInvalidGeometry, MaxVertices)
+			}
+			r, err := parseRing(ring)
+			if err != nil {
+				return nil, err
+			}
+			polygon = append(polygon, r)
+		}
+		area = append(area, polygon)
+	}
+	return area, nil
+}
+
+func parseRing(positions [][]float64) ([]Point, error) {
+	if len(positions) < 4 {
+		return nil, fmt.Errorf("%w: в кольце меньше 4 вершин", ErrInvalidGeometry)
+	}
+	ring := make([]Point, 0, len(positions))
+	for _, pos := range positions {
+		if len(pos) < 2 {
+			return nil, fmt.Errorf("%w: вершина без координат", ErrInvalidGeometry)
+		}
+		p := Point{Lat: pos[1], Lon: pos[0]}
+		if !p.Valid() {
+			return nil, fmt.Errorf("%w: координаты вне допустимых пределов", ErrInvalidGeometry)
+		}
+		ring = append(ring, p)
+	}
+	if ring[0] != ring[len(ring)-1] {
+		return nil, fmt.Errorf("%w: кольцо не замкнуто", ErrInvalidGeometry)
+	}
+	return ring, nil
+}
+
+// Contains проверяет, что точка внутри одного из многоугольников и не в его
+// дырке
+func (a Area) Contains(p Point) bool {
+	for _, polygon := range a {
+		if !ringContains(polygon[0], p) {
+			continue
+		}
+		inHole := false
+		for _, hole := range polygon[1:] {
+			if ringContains(hole, p) {
+				inHole = true
+				break
+			}
+		}
+		if !inHole {
+			return true
+		}
+	}
+	return false
+}
+
+// ringContains — проверка лучом: точка внутри, если горизонтальный луч из
+// нее пересекает кольцо нечетное число раз
+func ringContains(ring []Point, p Point) bool {
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		a, b := ring[i], ring[j]
+		if (a.Lat > p.Lat) != (b.Lat > p.Lat) &&
+			p.Lon < (b.Lon-a.Lon)*(p.Lat-a.Lat)/(b.Lat-a.Lat)+a.Lon {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// Bounds — прямоугольник, описанный вокруг внешних колец
+func (a Area) Bounds() Box {
+	b := Box{MinLat: 90, MinLon: 180, MaxLat: -90, MaxLon: -180}
+	for _, polygon := range a {
+		for _, p := range polygon[0] {
+			b.MinLat, b.MaxLat = min(b.MinLat, p.Lat), max(b.MaxLat, p.Lat)
+			b.MinLon, b.MaxLon = min(b.MinLon, p.Lon), max(b.MaxLon, p.Lon)
+		}
+	}
+	return b
+}
+
+// Zone — зона доставки: круг радиусом Radius метров вокруг Center или
+// многоугольник Area, если он задан
+type Zone struct {
+	Center Point
+	Radius float64
+	Area   Area
+}
+
+func (z Zone) Contains(p Point) bool {
+	if z.Area != nil {
+		return z.Area.Contains(p)
+	}
+	return Distance(z.Center, p) <= z.Radius
+}
+
+// Bounds — прямоугольник, в который целиком входит зона
+func (z Zone) Bounds() Box {
+	if z.Area != nil {
+		return z.Area.Bounds()
+	}
+
+	b, dLon := z.circleBounds()
+	if dLon > 0 {
+		b.MinLon = max(z.Center.Lon-dLon, -180)
+		b.MaxLon = min(z.Center.Lon+dLon, 180)
+	}
+	return b
+}
+
+// CrossesAntimeridian сообщает, заходит ли зона за меридиан 180°. Такой
+// зоне не найти один прямоугольник без разрыва по долготе
+func (z Zone) CrossesAntimeridian() bool {
+	if z.Area != nil {
+		for _, polygon := range z.Area {
+			ring := polygon[0]
+			for i := 1; i < len(ring); i++ {
+				if math.Abs(ring[i].Lon-ring[i-1].Lon) > 180 {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	_, dLon := z.circleBounds()
+	return dLon > 0 && (z.Center.Lon-dLon < -180 || z.Center.Lon+dLon > 180)
+}
+
+// circleBounds считает широты прямоугольника круга и его полуширину по
+// долготе. У полюса круг охватывает все долготы, и полуширина равна 0
+func (z Zone) circleBounds() (Box, float64) {
+	dLat := z.Radius / EarthRadius * 180 / math.Pi
+	b := Box{
+		MinLat: max(z.Center.Lat-dLat, -90),
+		MaxLat: min(z.Center.Lat+dLat, 90),
+		MinLon: -180,
+		MaxLon: 180,
+	}
+	if b.MinLat <= -90 || b.MaxLat >= 90 {
+		return b, 0
+	}
+	cos := math.Cos(radians(max(math.Abs(b.MinLat), math.Abs(b.MaxLat))))
+	return b, dLat / cos
+}
@@ -0,0 +1,127 @@
+package geo
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestDistance(t *testing.T) {
+	moscow := Point{Lat: 55.7558, Lon: 37.6173}
+	spb := Point{Lat: 59.9343, Lon: 30.3351}
+
+	if got := Distance(moscow, spb); math.Abs(got-634_000) > 3_000 {
+		t.Errorf("Distance(moscow, spb) = %.0f, want ~634000", got)
+	}
+	if got := Distance(moscow, moscow); got != 0 {
+		t.Errorf("Distance(p, p) = %v, want 0", got)
+	}
+}
+
+// square — квадрат 0..10 с дыркой 4..6 и отдельный квадрат 20..21
+const square = `{
+	"type": "MultiPolygon",
+	"coordinates": [
+		[
+			[[0, 0], [10, 0], [10, 10], [0, 10], [0, 0]],
+			[[4, 4], [6, 4], [6, 6], [4, 6], [4, 4]]
+		],
+		[
+			[[20, 20], [21, 20], [21, 21], [20, 21], [20, 20]]
+		]
+	]
+}`
+
+func TestAreaContains(t *testing.T) {
+	area, err := ParseArea([]byte(square))
+	if err != nil {
+		t.Fatalf("ParseArea: %v", err)
+	}
+
+	cases := []struct {
+		p    Point
+		want bool
+	}{
+		{Point{Lat: 1, Lon: 1}, true},
+		{Point{Lat: 5, Lon: 5}, false},
+		{Point{Lat: 20.5, Lon: 20.5}, true},
+		{Point{Lat: 15, Lon: 15}, false},
+		{Point{Lat: 5, Lon: -1}, false},
+	}
+	for _, c := range cases {
+		if got := area.Contains(c.p); got != c.want {
+			t.Errorf("Contains(%v) = %v, want %v", c.p, got, c.want)
+		}
+	}
+
+	want := Box{MinLat: 0, MinLon: 0, MaxLat: 21, MaxLon: 21}
+	if got := area.Bounds(); got != want {
+		t.Errorf("Bounds() = %v, want %v", got, want)
+	}
+}
+
+func TestParseAreaInvalid(t *testing.T) {
+	cases := map[string]string{
+		"not json":     `{`,
+		"point":        `{"type": "Point", "coordinates": [1, 2]}`,
+		"open ring":    `{"type": "Polygon", "coordinates": [[[0, 0], [1, 0], [1, 1], [0, 1]]]}`,
+		"short ring":   `{"type": "Polygon", "coordinates": [[[0, 0], [1, 0], [0, 0]]]}`,
+		"out of range": `{"type": "Polygon", "coordinates": [[[0, 0], [200, 0], [1, 1], [0, 0]]]}`,
+		"no rings":     `{"type": "Polygon", "coordinates": []}`,
+	}
+	for name, data := range cases {
+		if _, err := ParseArea([]byte(data)); !errors.Is(err, ErrInvalidGeometry) {
+			t.Errorf("%s: err = %v, want ErrInvalidGeometry", name, err)
+		}
+	}
+}
+
+func TestZoneRadius(t *testing.T) {
+	center := Point{Lat: 55.75, Lon: 37.62}
+	zone := Zone{Center: center, Radius: 3000}
+	box := zone.Bounds()
+
+	// точки на расстоянии чуть меньше радиуса по восьми направлениям
+	for i := 0; i < 8; i++ {
+		bearing := float64(i) * math.Pi / 4
+		d := 2990 / EarthRadius * 180 / math.Pi
+		p := Point{
+			Lat: center.Lat + d*math.Cos(bearing),
+			Lon: center.Lon + d*math.Sin(bearing)/math.Cos(radians(center.Lat)),
+		}
+		if !zone.Contains(p) {
+			t.Errorf("Contains(%v) = false, distance %.0f", p, Distance(center, p))
+		}
+		if !box.Contains(p) {
+			t.Errorf("Bounds() %v does not contain %v", box, p)
+		}
+	}
+
+	if zone.Contains(Point{Lat: 55.80, Lon: 37.62}) {
+		t.Error("point 5.5 km away is inside 3 km zone")
+	}
+}
+
+func TestZoneCrossesAntimeridian(t *testing.T) {
+	area, err := ParseArea([]byte(`{"type": "Polygon", "coordinates": [[[179, 64], [-179, 64], [-179, 65], [179, 65], [179, 64]]]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]struct {
+		zone Zone
+		want bool
+	}{
+		"moscow":       {zone: Zone{Center: Point{Lat: 55.75, Lon: 37.62}, Radius: 3000}},
+		"anadyr east":  {zone: Zone{Center: Point{Lat: 64.73, Lon: 178.5}, Radius: 100000}, want: true},
+		"near west":    {zone: Zone{Center: Point{Lat: 0, Lon: -179.99}, Radius: 5000}, want: true},
+		"pole":         {zone: Zone{Center: Point{Lat: 89.99, Lon: 179.99}, Radius: 5000}},
+		"area crosses": {zone: Zone{Area: area}, want: true},
+		"area":         {zone: Zone{Area: Area{{{{Lat: 0, Lon: 0}, {Lat: 0, Lon: 1}, {Lat: 1, Lon: 1}, {Lat: 0, Lon: 0}}}}}},
+	}
+	for name, tt := range cases {
+		if got := tt.zone.CrossesAntimeridian(); got != tt.want {
+			t.Errorf("%s: CrossesAntimeridian() = %v, want %v", name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+// Package guestcart — корзина посетителя без аккаунта.
+//
+// Гостевая корзина хранится в общих таблицах cart/cart_item с пустым
+// user_id, а ее id лежит в HttpOnly cookie guest_cart вместе с
+// HMAC-SHA256 подписью на GUEST_CART_SECRET: подобрать чужую корзину без
+// секрета нельзя. store_service наполняет корзину, auth_service при входе
+// или регистрации переносит ее в корзину пользователя (Merger).
+package guestcart
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	CookieName = "guest_cart"
+
+	// TTL — сколько живет корзина без изменений; столько же живет cookie
+	TTL = 30 * 24 * time.Hour
+
+	// MaxQuantity — предел количества одной позиции после слияния корзин
+	MaxQuantity = 99
+)
+
+var ErrInvalid = errors.New("guestcart: invalid cookie")
+
+var b64 = base64.RawURLEncoding
+
+// Cookies выдает и проверяет подписанную cookie гостевой корзины
+type Cookies struct {
+	secret   []byte
+	secure   bool
+	sameSite http.SameSite
+	domain   string
+}
+
+// New читает COOKIE_SECURE, COOKIE_SAMESITE и COOKIE_DOMAIN, как и
+// остальные cookie сервисов
+func New(secret string) *Cookies {
+	c := &Cookies{
+		secret:   []byte(secret),
+		secure:   strings.EqualFold(os.Getenv("COOKIE_SECURE"), "true"),
+		sameSite: http.SameSiteLaxMode,
+		domain:   os.Getenv("COOKIE_DOMAIN"),
+	}
+	switch strings.ToLower(os.Getenv("COOKIE_SAMESITE")) {
+	case "strict":
+		c.sameSite = http.SameSiteStrictMode
+	case "none":
+		c.sameSite = http.SameSiteNoneMode
+		c.secure = true
+	}
+	return c
+}
+
+// CartID возвращает id гостевой корзины из cookie запроса
+func (c *Cookies) CartID(r *http.Request) (string, error) {
+	ck, err := r.Cookie(CookieName)
+	if err != nil {
+		return "", ErrInvalid
+	}
+	id, sig, ok := strings.Cut(ck.Value, ".")
+	if !ok {
+		return "", ErrInvalid
+	}
+	mac, err := b64.DecodeString(sig)
+	if err != nil || !hmac.Equal(mac, c.sign(id)) {
+		return "", ErrInvalid
+	}
+	if _, err := uuid.Parse(id); err != nil {
+		return "", ErrInvalid
+	}
+	return id, nil
+}
+
+// Set выставляет cookie на корзину cartID; вызывается при каждом изменении
+// корзины, чтобы cookie не истекла раньше самой корзины
+func (c *Cookies) Set(w http.ResponseWriter, cartID string) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     CookieName,
+		Value:    cartID + "." + b64.EncodeToString(c.sign(cartID)),
+		Expires:  time.Now().Add(TTL),
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   c.secure,
+		SameSite: c.sameSite,
+		Domain:   c.domain,
+	})
+}
+
+// Clear удаляет cookie после слияния корзин
+func (c *Cookies) Clear(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     CookieName,
+		Value:    "",
+		Expires:  time.Now().Add(-time.Hour),
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   c.secure,
+		SameSite: c.sameSite,
+		Domain:   c.domain,
+	})
+}
+
+func (c *Cookies) sign(cartID string) []byte {
+	m := hmac.New(sha256.New, c.secret)
+	m.Write([]byte(cartID))
+	return m.Sum(nil)
+}
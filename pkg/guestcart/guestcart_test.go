@@ -0,0 +1,59 @@
+package guestcart
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func roundTrip(t *testing.T, c *Cookies, cartID string) *http.Request {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	c.Set(rec, cartID)
+	r := httptest.NewRequest(http.MethodGet, "/api/v0/cart", nil)
+	for _, ck := range rec.Result().Cookies() {
+		r.AddCookie(ck)
+	}
+	return r
+}
+
+func TestCookies_RoundTrip(t *testing.T) {
+	c := New("secret")
+	id := uuid.NewString()
+
+	got, err := c.CartID(roundTrip(t, c, id))
+	if err != nil {
+		t.Fatalf("expected valid cookie, got %v", err)
+	}
+	if got != id {
+		t.Fatalf("expected cart %s, got %s", id, got)
+	}
+}
+
+func TestCookies_RejectsForged(t *testing.T) {
+	c := New("secret")
+	id := uuid.NewString()
+
+	// cookie с другим ключом
+	if _, err := c.CartID(roundTrip(t, New("other"), id)); !errors.Is(err, ErrInvalid) {
+		t.Fatalf("expected ErrInvalid for foreign secret, got %v", err)
+	}
+
+	// подпись от одной корзины к другой не подходит
+	r := roundTrip(t, c, id)
+	ck, _ := r.Cookie(CookieName)
+	_, sig, _ := strings.Cut(ck.Value, ".")
+	forged := httptest.NewRequest(http.MethodGet, "/api/v0/cart", nil)
+	forged.AddCookie(&http.Cookie{Name: CookieName, Value: uuid.NewString() + "." + sig})
+	if _, err := c.CartID(forged); !errors.Is(err, ErrInvalid) {
+		t.Fatalf("expected ErrInvalid for swapped cart id, got %v", err)
+	}
+
+	if _, err := c.CartID(httptest.NewRequest(http.MethodGet, "/", nil)); !errors.Is(err, ErrInvalid) {
+		t.Fatalf("expected ErrInvalid without cookie, got %v", err)
+	}
+}
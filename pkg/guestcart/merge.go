@@ -0,0 +1,92 @@
+package guestcart
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// Merger переносит гостевую корзину в корзину пользователя
+type Merger interface {
+	Merge(ctx context.Context, guestCartID, userID string) (int, error)
+}
+
+type txBeginner interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+const (
+	lockGuestCartSQL = `SELECT id FROM cart WHERE id = $1 AND user_id IS NULL FOR UPDATE`
+
+	// корзина пользователя создается, если ее еще не было. DO UPDATE, а не
+	// DO NOTHING: так RETURNING отдает и уже существующую корзину, в том числе
+	// созданную параллельной транзакцией, которую обычный SELECT не увидел бы
+	ensureUserCartSQL = `
+INSERT INTO cart (id, user_id) VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE SET user_id = EXCLUDED.user_id
+RETURNING id`
+
+	// совпавшие позиции складываются, количество ограничено MaxQuantity;
+	// удаленные и архивные позиции сняты с продажи и не переносятся, как и
+	// при добавлении в корзину
+	mergeItemsSQL = `
+INSERT INTO cart_item (id, cart_id, store_item_id, quantity)
+SELECT gen_random_uuid(), $2, ci.store_item_id, least(ci.quantity, $3)
+FROM cart_item ci
+JOIN store_item si ON si.id = ci.store_item_id
+JOIN item i ON i.id = si.item_id
+WHERE ci.cart_id = $1
+  AND si.archived_at IS NULL
+  AND i.archived_at IS NULL
+ON CONFLICT (cart_id, store_item_id)
+    DO UPDATE SET quantity = least(cart_item.quantity + excluded.quantity, $3)`
+
+	deleteGuestCartSQL = `DELETE FROM cart WHERE id = $1`
+)
+
+// PostgresMerger сливает корзины напрямую в общих таблицах cart/cart_item
+type PostgresMerger struct {
+	db txBeginner
+}
+
+func NewPostgresMerger(db txBeginner) *PostgresMerger {
+	return &PostgresMerger{db: db}
+}
+
+// Merge добавляет позиции гостевой корзины в корзину пользователя и удаляет
+// гостевую. Возвращает число перенесенных позиций; уже слитая или истекшая
+// корзина — не ошибка, а 0.
+func (m *PostgresMerger) Merge(ctx context.Context, guestCartID, userID string) (int, error) {
+	tx, err := m.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var id string
+	err = tx.QueryRow(ctx, lockGuestCartSQL, guestCartID).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	var userCartID string
+	if err := tx.QueryRow(ctx, ensureUserCartSQL, uuid.NewString(), userID).Scan(&userCartID); err != nil {
+		return 0, err
+	}
+	tag, err := tx.Exec(ctx, mergeItemsSQL, guestCartID, userCartID, MaxQuantity)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := tx.Exec(ctx, deleteGuestCartSQL, guestCartID); err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return int(tag.RowsAffected()), nil
+}
@@ -0,0 +1,44 @@
+package guestcart
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pashagolub/pgxmock/v2"
+)
+
+func TestPostgresMerger_ExistingUserCart(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM cart WHERE id = \$1`).
+		WithArgs("g1").
+		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow("g1"))
+	// корзина пользователя уже есть: RETURNING отдает ее id без отдельного SELECT
+	mock.ExpectQuery(`ON CONFLICT \(user_id\) DO UPDATE SET user_id = EXCLUDED.user_id RETURNING id`).
+		WithArgs(pgxmock.AnyArg(), "u1").
+		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow("c1"))
+	mock.ExpectExec(`INSERT INTO cart_item`).
+		WithArgs("g1", "c1", MaxQuantity).
+		WillReturnResult(pgxmock.NewResult("INSERT", 2))
+	mock.ExpectExec(`DELETE FROM cart WHERE id = \$1`).
+		WithArgs("g1").
+		WillReturnResult(pgxmock.NewResult("DELETE", 1))
+	mock.ExpectCommit()
+	mock.ExpectRollback()
+
+	merged, err := NewPostgresMerger(mock).Merge(context.Background(), "g1", "u1")
+	if err != nil {
+		t.Fatalf("merge failed: %v", err)
+	}
+	if merged != 2 {
+		t.Fatalf("merged = %d, want 2", merged)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
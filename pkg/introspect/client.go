@@ -0,0 +1,163 @@
+// Package introspect — клиент эндпоинта POST /auth/introspect (RFC 7662).
+// Локальная проверка подписи JWT не видит отзыва сессии, поэтому там, где
+// это важно (платежи, удаление профиля), сервисы спрашивают auth_service.
+package introspect
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultTTL = 10 * time.Second
+
+	requestTimeout = 3 * time.Second
+	maxCacheSize   = 10000
+)
+
+// Result — ответ интроспекции. Для неактивного токена заполнено только Active.
+type Result struct {
+	Active    bool     `json:"active"`
+	Subject   string   `json:"sub,omitempty"`
+	UserID    string   `json:"user_id,omitempty"`
+	SessionID string   `json:"sid,omitempty"`
+	Roles     []string `json:"roles,omitempty"`
+	MFA       bool     `json:"mfa,omitempty"`
+	TokenType string   `json:"token_type,omitempty"`
+	ExpiresAt int64    `json:"exp,omitempty"`
+	IssuedAt  int64    `json:"iat,omitempty"`
+}
+
+type cacheEntry struct {
+	res     *Result
+	expires time.Time
+}
+
+// Client кэширует ответы на ttl (но не дольше срока жизни токена), так что
+// отзыв сессии становится виден не позже чем через ttl.
+type Client struct {
+	endpoint string
+	secret   string
+	ttl      time.Duration
+	http     *http.Client
+
+	mu    sync.Mutex
+	cache map[string]cacheEntry
+}
+
+// NewClient создает клиент. authURL — адрес auth_service (AUTH_URL), secret —
+// общий с auth_service INTROSPECT_SECRET.
+func NewClient(authURL, secret string, ttl time.Duration) *Client {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return &Client{
+		endpoint: strings.TrimRight(authURL, "/") + "/api/v0/auth/introspect",
+		secret:   secret,
+		ttl:      ttl,
+		http:     &http.Client{Timeout: requestTimeout},
+		cache:    make(map[string]cacheEntry),
+	}
+}
+
+func (c *Client) Introspect(ctx context.Context, token string) (*Result, error) {
+	if token == "" {
+		return &Result{Active: false}, nil
+	}
+	sum := sha256.Sum256([]byte(token))
+	key := hex.EncodeToString(sum[:])
+
+	now := time.Now()
+	c.mu.Lock()
+	if e, ok := c.cache[key]; ok && now.Before(e.expires) {
+		c.mu.Unlock()
+		return e.res, nil
+	}
+	c.mu.Unlock()
+
+	res, err := c.fetch(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	expires := now.Add(c.ttl)
+	if res.Active && res.ExpiresAt > 0 {
+		if exp := time.Unix(res.ExpiresAt, 0); exp.Before(expires) {
+			expires = exp
+		}
+	}
+	c.mu.Lock()
+	if len(c.cache) >= maxCacheSize {
+		for k, e := range c.cache {
+			if now.After(e.expires) {
+				delete(c.cache, k)
+			}
+		}
+		if len(c.cache) >= maxCacheSize {
+			c.cache = make(map[string]cacheEntry)
+		}
+	}
+	c.cache[key] = cacheEntry{res: res, expires: expires}
+	c.mu.Unlock()
+
+	return res, nil
+}
+
+func (c *Client) fetch(ctx context.Context, token string) (*Result, error) {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	form := url.Values{"token": {token}, "token_type_hint": {"access_token"}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Authorization", "Bearer "+c.secret)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("introspect: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("introspect: unexpected status %d", resp.StatusCode)
+	}
+
+	var res Result
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, fmt.Errorf("introspect: decode: %w", err)
+	}
+	return &res, nil
+}
+
+// RequireActive пропускает запрос, только если auth_service подтверждает,
+// что токен из cookie активен. Если auth_service недоступен, запрос
+// отклоняется: эти операции важнее доступности.
+func (c *Client) RequireActive(cookieName string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cookie, err := r.Cookie(cookieName)
+		if err != nil {
+			http.Error(w, `{"error":"unauthorized"}`, http.StatusUnauthorized)
+			return
+		}
+		res, err := c.Introspect(r.Context(), cookie.Value)
+		if err != nil {
+			http.Error(w, `{"error":"auth service unavailable"}`, http.StatusServiceUnavailable)
+			return
+		}
+		if !res.Active {
+			http.Error(w, `{"error":"unauthorized"}`, http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
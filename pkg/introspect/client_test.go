@@ -0,0 +1,80 @@
+package introspect
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestIntrospect_CachesResult(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if r.Header.Get("Authorization") != "Bearer s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_ = r.ParseForm()
+		active := r.PostForm.Get("token") == "good"
+		_ = json.NewEncoder(w).Encode(Result{Active: active, Subject: "u1", ExpiresAt: time.Now().Add(time.Hour).Unix()})
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "s3cret", time.Minute)
+	for i := 0; i < 3; i++ {
+		res, err := c.Introspect(context.Background(), "good")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !res.Active || res.Subject != "u1" {
+			t.Fatalf("unexpected result: %+v", res)
+		}
+	}
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Fatalf("expected 1 request, got %d", n)
+	}
+
+	res, err := c.Introspect(context.Background(), "bad")
+	if err != nil || res.Active {
+		t.Fatalf("expected inactive token, got %+v, %v", res, err)
+	}
+}
+
+func TestRequireActive(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		_ = json.NewEncoder(w).Encode(Result{Active: r.PostForm.Get("token") == "good"})
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "s3cret", time.Minute)
+	h := c.RequireActive("jwt_token", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	cases := map[string]int{"good": http.StatusNoContent, "bad": http.StatusUnauthorized, "": http.StatusUnauthorized}
+	for token, want := range cases {
+		req := httptest.NewRequest(http.MethodDelete, "/", nil)
+		if token != "" {
+			req.AddCookie(&http.Cookie{Name: "jwt_token", Value: token})
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != want {
+			t.Fatalf("token %q: expected %d, got %d", token, want, rec.Code)
+		}
+	}
+
+	srv.Close()
+	req := httptest.NewRequest(http.MethodDelete, "/", nil)
+	req.AddCookie(&http.Cookie{Name: "jwt_token", Value: "other"})
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 when auth is down, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,107 @@
+package jwtkeys
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// JWK — публичный ключ в формате RFC 7517 (поддерживаются RSA и Ed25519)
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+}
+
+// Set — содержимое /.well-known/jwks.json
+type Set struct {
+	Keys []JWK `json:"keys"`
+}
+
+var ErrUnsupportedKey = errors.New("jwtkeys: unsupported key type")
+
+func signingMethodFor(pub crypto.PublicKey) (jwt.SigningMethod, error) {
+	switch pub.(type) {
+	case ed25519.PublicKey:
+		return jwt.SigningMethodEdDSA, nil
+	case *rsa.PublicKey:
+		return jwt.SigningMethodRS256, nil
+	default:
+		return nil, ErrUnsupportedKey
+	}
+}
+
+func publicJWK(kid string, pub crypto.PublicKey) (JWK, error) {
+	b64 := base64.RawURLEncoding
+	switch k := pub.(type) {
+	case ed25519.PublicKey:
+		return JWK{Kty: "OKP", Kid: kid, Use: "sig", Alg: jwt.SigningMethodEdDSA.Alg(), Crv: "Ed25519", X: b64.EncodeToString(k)}, nil
+	case *rsa.PublicKey:
+		return JWK{
+			Kty: "RSA", Kid: kid, Use: "sig", Alg: jwt.SigningMethodRS256.Alg(),
+			N: b64.EncodeToString(k.N.Bytes()),
+			E: b64.EncodeToString(big.NewInt(int64(k.E)).Bytes()),
+		}, nil
+	default:
+		return JWK{}, ErrUnsupportedKey
+	}
+}
+
+func (k JWK) publicKey() (crypto.PublicKey, error) {
+	b64 := base64.RawURLEncoding
+	switch k.Kty {
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("%w: curve %q", ErrUnsupportedKey, k.Crv)
+		}
+		x, err := b64.DecodeString(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("jwtkeys: bad Ed25519 key %q", k.Kid)
+		}
+		return ed25519.PublicKey(x), nil
+	case "RSA":
+		n, err := b64.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("jwtkeys: bad RSA modulus %q: %w", k.Kid, err)
+		}
+		e, err := b64.DecodeString(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("jwtkeys: bad RSA exponent %q: %w", k.Kid, err)
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	default:
+		return nil, fmt.Errorf("%w: kty %q", ErrUnsupportedKey, k.Kty)
+	}
+}
+
+// verificationKey проверяет, что алгоритм токена соответствует ключу,
+// чтобы нельзя было подменить alg в заголовке.
+func verificationKey(t *jwt.Token, pub crypto.PublicKey) (interface{}, error) {
+	method, err := signingMethodFor(pub)
+	if err != nil {
+		return nil, err
+	}
+	if t.Method.Alg() != method.Alg() {
+		return nil, fmt.Errorf("jwtkeys: unexpected signing method %q", t.Method.Alg())
+	}
+	return pub, nil
+}
+
+func kidFromToken(t *jwt.Token) (string, error) {
+	kid, _ := t.Header["kid"].(string)
+	if kid == "" {
+		return "", errors.New("jwtkeys: token has no kid")
+	}
+	return kid, nil
+}
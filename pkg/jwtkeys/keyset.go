@@ -0,0 +1,165 @@
+package jwtkeys
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+type signingKey struct {
+	kid     string
+	method  jwt.SigningMethod
+	private crypto.Signer
+}
+
+// KeySet — закрытые ключи auth_service. Подписывает активный ключ, а
+// проверяются и публикуются все ключи набора, поэтому новый ключ можно
+// выложить заранее, а старый убрать после истечения выданных им токенов.
+type KeySet struct {
+	active *signingKey
+	keys   map[string]*signingKey
+}
+
+// LoadKeySet читает из dir все файлы *.pem с закрытыми ключами (PKCS#8 или
+// PKCS#1 для RSA). kid ключа — имя файла без расширения. Если activeKID
+// пустой, подписывает ключ с наибольшим kid.
+func LoadKeySet(dir, activeKID string) (*KeySet, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("jwtkeys: no *.pem keys in %q", dir)
+	}
+	sort.Strings(paths)
+
+	ks := &KeySet{keys: make(map[string]*signingKey, len(paths))}
+	for _, p := range paths {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return nil, err
+		}
+		signer, err := parsePrivateKey(data)
+		if err != nil {
+			return nil, fmt.Errorf("jwtkeys: %s: %w", p, err)
+		}
+		kid := strings.TrimSuffix(filepath.Base(p), filepath.Ext(p))
+		if err := ks.add(kid, signer); err != nil {
+			return nil, err
+		}
+		if activeKID == "" {
+			ks.active = ks.keys[kid]
+		}
+	}
+
+	if activeKID != "" {
+		active, ok := ks.keys[activeKID]
+		if !ok {
+			return nil, fmt.Errorf("jwtkeys: active key %q not found in %q", activeKID, dir)
+		}
+		ks.active = active
+	}
+	return ks, nil
+}
+
+// GenerateKeySet создает набор из одного временного Ed25519-ключа. Подходит
+// для локальной разработки и тестов: после рестарта все токены становятся
+// недействительными.
+func GenerateKeySet() (*KeySet, error) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	kid := "ephemeral-" + hex.EncodeToString(b)
+
+	ks := &KeySet{keys: map[string]*signingKey{}}
+	if err := ks.add(kid, priv); err != nil {
+		return nil, err
+	}
+	ks.active = ks.keys[kid]
+	return ks, nil
+}
+
+func (ks *KeySet) add(kid string, signer crypto.Signer) error {
+	method, err := signingMethodFor(signer.Public())
+	if err != nil {
+		return fmt.Errorf("jwtkeys: key %q: %w", kid, err)
+	}
+	ks.keys[kid] = &signingKey{kid: kid, method: method, private: signer}
+	return nil
+}
+
+func (ks *KeySet) ActiveKID() string {
+	return ks.active.kid
+}
+
+// Sign подписывает claims активным ключом и проставляет kid в заголовок
+func (ks *KeySet) Sign(claims jwt.Claims) (string, error) {
+	t := jwt.NewWithClaims(ks.active.method, claims)
+	t.Header["kid"] = ks.active.kid
+	return t.SignedString(ks.active.private)
+}
+
+// Keyfunc для jwt.Parse: выбирает публичный ключ по kid из заголовка
+func (ks *KeySet) Keyfunc(t *jwt.Token) (interface{}, error) {
+	kid, err := kidFromToken(t)
+	if err != nil {
+		return nil, err
+	}
+	k, ok := ks.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("jwtkeys: unknown kid %q", kid)
+	}
+	return verificationKey(t, k.private.Public())
+}
+
+// JWKS возвращает публичные части всех ключей набора
+func (ks *KeySet) JWKS() (*Set, error) {
+	kids := make([]string, 0, len(ks.keys))
+	for kid := range ks.keys {
+		kids = append(kids, kid)
+	}
+	sort.Strings(kids)
+
+	set := &Set{Keys: make([]JWK, 0, len(kids))}
+	for _, kid := range kids {
+		jwk, err := publicJWK(kid, ks.keys[kid].private.Public())
+		if err != nil {
+			return nil, err
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set, nil
+}
+
+func parsePrivateKey(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block found")
+	}
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, ErrUnsupportedKey
+		}
+		return signer, nil
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unsupported private key format %q", block.Type)
+}
@@ -0,0 +1,187 @@
+package jwtkeys
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+func writeKey(t *testing.T, dir, kid string, key interface{}) {
+	t.Helper()
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	if err := os.WriteFile(filepath.Join(dir, kid+".pem"), data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func testClaims() jwt.Claims {
+	return jwt.RegisteredClaims{Subject: "u1", ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute))}
+}
+
+func TestLoadKeySet_Rotation(t *testing.T) {
+	dir := t.TempDir()
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeKey(t, dir, "2024-01", rsaKey)
+	writeKey(t, dir, "2024-02", edKey)
+
+	old, err := LoadKeySet(dir, "2024-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldToken, err := old.Sign(testClaims())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// по умолчанию подписывает ключ с наибольшим kid
+	ks, err := LoadKeySet(dir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ks.ActiveKID() != "2024-02" {
+		t.Fatalf("active kid = %q", ks.ActiveKID())
+	}
+	newToken, err := ks.Sign(testClaims())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tok := range []string{oldToken, newToken} {
+		if _, err := jwt.Parse(tok, ks.Keyfunc); err != nil {
+			t.Fatalf("token not accepted: %v", err)
+		}
+	}
+
+	set, err := ks.JWKS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(set.Keys) != 2 || set.Keys[0].Kty != "RSA" || set.Keys[1].Kty != "OKP" {
+		t.Fatalf("unexpected jwks: %+v", set.Keys)
+	}
+}
+
+func TestKeyfunc_RejectsAlgSwap(t *testing.T) {
+	ks, err := GenerateKeySet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// HMAC-токен, подписанный публичным ключом как секретом
+	set, _ := ks.JWKS()
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, testClaims())
+	forged.Header["kid"] = ks.ActiveKID()
+	s, err := forged.SignedString([]byte(set.Keys[0].X))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := jwt.Parse(s, ks.Keyfunc); err == nil {
+		t.Fatal("forged token accepted")
+	}
+}
+
+func TestRemoteKeySet(t *testing.T) {
+	ks, err := GenerateKeySet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		set, _ := ks.JWKS()
+		_ = json.NewEncoder(w).Encode(set)
+	}))
+	defer srv.Close()
+
+	remote := NewRemoteKeySet(srv.URL, time.Minute)
+	for i := 0; i < 3; i++ {
+		tok, err := ks.Sign(testClaims())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := jwt.Parse(tok, remote.Keyfunc); err != nil {
+			t.Fatalf("remote verify failed: %v", err)
+		}
+	}
+	if requests != 1 {
+		t.Fatalf("jwks fetched %d times, want 1", requests)
+	}
+
+	other, _ := GenerateKeySet()
+	tok, _ := other.Sign(testClaims())
+	if _, err := jwt.Parse(tok, remote.Keyfunc); err == nil {
+		t.Fatal("token with unknown kid accepted")
+	}
+}
+
+func TestRemoteKeySet_VerifiesDuringFetch(t *testing.T) {
+	ks, err := GenerateKeySet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var requests atomic.Int32
+	started := make(chan struct{})
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 2 {
+			close(started)
+			<-release
+		}
+		set, _ := ks.JWKS()
+		_ = json.NewEncoder(w).Encode(set)
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	remote := NewRemoteKeySet(srv.URL, time.Minute)
+	tok, err := ks.Sign(testClaims())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := jwt.Parse(tok, remote.Keyfunc); err != nil {
+		t.Fatalf("remote verify failed: %v", err)
+	}
+
+	// ключи устарели, а повторная загрузка JWKS зависла
+	remote.mu.Lock()
+	remote.fetchedAt = time.Time{}
+	remote.mu.Unlock()
+	remote.fetchMu.Lock()
+	remote.lastAttempt = time.Time{}
+	remote.fetchMu.Unlock()
+	go func() { _ = remote.refresh(true) }()
+	<-started
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := jwt.Parse(tok, remote.Keyfunc)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("remote verify failed: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("verification waited for the jwks fetch")
+	}
+}
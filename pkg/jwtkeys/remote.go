@@ -0,0 +1,136 @@
+package jwtkeys
+
+import (
+	"context"
+	"crypto"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	defaultCacheTTL  = 5 * time.Minute
+	minRefreshPeriod = 30 * time.Second
+	fetchTimeout     = 5 * time.Second
+)
+
+// RemoteKeySet — кэш публичных ключей, загружаемых с JWKS-эндпоинта
+// auth_service. Ключи перечитываются по истечении TTL, а также когда
+// встречается неизвестный kid (не чаще раза в minRefreshPeriod).
+type RemoteKeySet struct {
+	url    string
+	ttl    time.Duration
+	client *http.Client
+
+	mu        sync.RWMutex
+	keys      map[string]crypto.PublicKey
+	fetchedAt time.Time
+
+	// fetchMu пропускает к JWKS-эндпоинту одну загрузку за раз и
+	// защищает lastAttempt; mu на время запроса не берется
+	fetchMu     sync.Mutex
+	lastAttempt time.Time
+}
+
+func NewRemoteKeySet(url string, ttl time.Duration) *RemoteKeySet {
+	if ttl <= 0 {
+		ttl = defaultCacheTTL
+	}
+	return &RemoteKeySet{
+		url:    url,
+		ttl:    ttl,
+		client: &http.Client{Timeout: fetchTimeout},
+		keys:   map[string]crypto.PublicKey{},
+	}
+}
+
+// Keyfunc для jwt.Parse
+func (r *RemoteKeySet) Keyfunc(t *jwt.Token) (interface{}, error) {
+	kid, err := kidFromToken(t)
+	if err != nil {
+		return nil, err
+	}
+
+	pub, fresh := r.lookup(kid)
+	if pub == nil || !fresh {
+		if err := r.refresh(pub == nil); err != nil && pub == nil {
+			return nil, err
+		}
+		pub, _ = r.lookup(kid)
+	}
+	if pub == nil {
+		return nil, fmt.Errorf("jwtkeys: unknown kid %q", kid)
+	}
+	return verificationKey(t, pub)
+}
+
+func (r *RemoteKeySet) lookup(kid string) (crypto.PublicKey, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.keys[kid], time.Since(r.fetchedAt) < r.ttl
+}
+
+// refresh перечитывает ключи. Пока идет запрос, lookup отдает старый набор.
+// С wait=false вызов не ждет уже идущую загрузку: ключ для kid есть, только
+// устарел, и токен можно проверить по нему.
+func (r *RemoteKeySet) refresh(wait bool) error {
+	if wait {
+		r.fetchMu.Lock()
+	} else if !r.fetchMu.TryLock() {
+		return nil
+	}
+	defer r.fetchMu.Unlock()
+
+	if time.Since(r.lastAttempt) < minRefreshPeriod {
+		return nil
+	}
+	r.lastAttempt = time.Now()
+
+	keys, err := r.fetch()
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	r.keys = keys
+	r.fetchedAt = time.Now()
+	r.mu.Unlock()
+	return nil
+}
+
+func (r *RemoteKeySet) fetch() (map[string]crypto.PublicKey, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("jwtkeys: fetch %s: %w", r.url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("jwtkeys: fetch %s: status %d", r.url, resp.StatusCode)
+	}
+
+	var set Set
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return nil, fmt.Errorf("jwtkeys: decode %s: %w", r.url, err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		pub, err := k.publicKey()
+		if err != nil {
+			// неизвестные типы ключей пропускаем, остальные ключи рабочие
+			continue
+		}
+		keys[k.Kid] = pub
+	}
+	return keys, nil
+}
@@ -0,0 +1,133 @@
+package oidc_test
+
+import (
+	"apple_backend/pkg/oidc"
+	"apple_backend/pkg/oidc/oidctest"
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+func newProvider(t *testing.T) (*oidctest.Server, *oidc.Provider) {
+	t.Helper()
+	srv, err := oidctest.NewServer("client", "s3cret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(srv.Close)
+	p := oidc.NewProvider(oidc.Config{
+		Issuer:       srv.Issuer(),
+		ClientID:     "client",
+		ClientSecret: "s3cret",
+		RedirectURL:  "http://front/oauth/test/callback",
+		Scopes:       []string{"email"},
+	})
+	return srv, p
+}
+
+func TestProvider_CodeFlow(t *testing.T) {
+	srv, p := newProvider(t)
+	ctx := context.Background()
+	srv.SetUser(oidctest.User{Subject: "42", Email: "u@ex.com", EmailVerified: true})
+
+	verifier, _ := oidc.NewVerifier()
+	authURL, err := p.AuthCodeURL(ctx, "st", "nn", verifier)
+	if err != nil {
+		t.Fatalf("auth url: %v", err)
+	}
+	u, _ := url.Parse(authURL)
+	if u.Query().Get("code_challenge") != oidc.Challenge(verifier) || u.Query().Get("scope") != "openid email" {
+		t.Fatalf("unexpected auth url: %s", authURL)
+	}
+
+	code, state, err := srv.Authorize(authURL)
+	if err != nil || state != "st" || code == "" {
+		t.Fatalf("authorize: code=%q state=%q err=%v", code, state, err)
+	}
+	tok, err := p.Exchange(ctx, code, verifier)
+	if err != nil {
+		t.Fatalf("exchange: %v", err)
+	}
+	claims, err := p.VerifyIDToken(ctx, tok.IDToken, "nn")
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if claims.Subject != "42" || claims.Email != "u@ex.com" || !claims.EmailVerified {
+		t.Fatalf("unexpected claims: %+v", claims)
+	}
+
+	if _, err := p.VerifyIDToken(ctx, tok.IDToken, "other"); !errors.Is(err, oidc.ErrInvalidToken) {
+		t.Fatalf("expected nonce mismatch, got %v", err)
+	}
+}
+
+func TestProvider_ExchangeRequiresVerifier(t *testing.T) {
+	srv, p := newProvider(t)
+	ctx := context.Background()
+
+	verifier, _ := oidc.NewVerifier()
+	authURL, _ := p.AuthCodeURL(ctx, "st", "nn", verifier)
+	code, _, _ := srv.Authorize(authURL)
+
+	other, _ := oidc.NewVerifier()
+	if _, err := p.Exchange(ctx, code, other); !errors.Is(err, oidc.ErrExchange) {
+		t.Fatalf("expected ErrExchange for wrong verifier, got %v", err)
+	}
+	// код одноразовый
+	if _, err := p.Exchange(ctx, code, verifier); !errors.Is(err, oidc.ErrExchange) {
+		t.Fatalf("expected ErrExchange for reused code, got %v", err)
+	}
+}
+
+func TestProvider_VerifyIDTokenRejects(t *testing.T) {
+	srv, p := newProvider(t)
+	ctx := context.Background()
+	now := time.Now()
+	valid := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss": srv.Issuer(), "sub": "42", "aud": "client", "nonce": "nn",
+			"exp": now.Add(time.Minute).Unix(), "iat": now.Unix(),
+		}
+	}
+
+	tests := []struct {
+		name   string
+		modify func(jwt.MapClaims)
+	}{
+		{name: "other audience", modify: func(c jwt.MapClaims) { c["aud"] = "someone-else" }},
+		{name: "other issuer", modify: func(c jwt.MapClaims) { c["iss"] = "https://evil.example" }},
+		{name: "expired", modify: func(c jwt.MapClaims) { c["exp"] = now.Add(-time.Minute).Unix() }},
+		{name: "no subject", modify: func(c jwt.MapClaims) { delete(c, "sub") }},
+		{name: "multiple audiences without azp", modify: func(c jwt.MapClaims) { c["aud"] = []string{"client", "other"} }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := valid()
+			tt.modify(claims)
+			raw, err := srv.SignIDToken(claims)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := p.VerifyIDToken(ctx, raw, "nn"); !errors.Is(err, oidc.ErrInvalidToken) {
+				t.Fatalf("expected ErrInvalidToken, got %v", err)
+			}
+		})
+	}
+
+	raw, _ := srv.SignIDToken(valid())
+	if _, err := p.VerifyIDToken(ctx, raw, "nn"); err != nil {
+		t.Fatalf("valid token rejected: %v", err)
+	}
+}
+
+func TestProvider_DiscoveryFailure(t *testing.T) {
+	srv, _ := newProvider(t)
+	p := oidc.NewProvider(oidc.Config{Issuer: srv.Issuer() + "/other", ClientID: "client"})
+	if _, err := p.AuthCodeURL(context.Background(), "s", "n", "v"); !errors.Is(err, oidc.ErrDiscovery) {
+		t.Fatalf("expected ErrDiscovery, got %v", err)
+	}
+}
@@ -0,0 +1,90 @@
+package passcheck
+
+import (
+	"bufio"
+	"crypto/sha1"
+	_ "embed"
+	"encoding/hex"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+const prefixLength = 5
+
+//go:embed data/breached.txt
+var breachedData string
+
+// BreachList отвечает, встречался ли пароль в утечках. Поиск идет по
+// первым пяти символам SHA-1, как в range API Have I Been Pwned: сам
+// пароль и его полный хэш никуда не передаются и в памяти не ищутся.
+//
+// Встроенный список небольшой. Полную базу можно выгрузить утилитой
+// PwnedPasswordsDownloader в каталог файлов <PREFIX>.txt со строками
+// <SUFFIX>:<COUNT> и передать его в NewBreachList.
+type BreachList struct {
+	bundled map[string][]string
+	dir     string
+}
+
+var (
+	bundledOnce     sync.Once
+	bundledPrefixes map[string][]string
+)
+
+// NewBreachList создает список из встроенных хэшей и, если dir не пуст,
+// каталога с range-файлами.
+func NewBreachList(dir string) *BreachList {
+	bundledOnce.Do(func() {
+		bundledPrefixes = make(map[string][]string)
+		for _, line := range strings.Split(breachedData, "\n") {
+			hash, _, _ := strings.Cut(strings.TrimSpace(line), ":")
+			if len(hash) != sha1.Size*2 {
+				continue
+			}
+			prefix := hash[:prefixLength]
+			bundledPrefixes[prefix] = append(bundledPrefixes[prefix], hash[prefixLength:])
+		}
+	})
+	return &BreachList{bundled: bundledPrefixes, dir: dir}
+}
+
+// Breached сообщает, есть ли пароль в списке утекших
+func (b *BreachList) Breached(password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:prefixLength], hash[prefixLength:]
+
+	for _, s := range b.bundled[prefix] {
+		if s == suffix {
+			return true, nil
+		}
+	}
+	if b.dir == "" {
+		return false, nil
+	}
+	return b.inRangeFile(prefix, suffix)
+}
+
+func (b *BreachList) inRangeFile(prefix, suffix string) (bool, error) {
+	f, err := os.Open(filepath.Join(b.dir, prefix+".txt"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		s, _, _ := strings.Cut(sc.Text(), ":")
+		if strings.EqualFold(strings.TrimSpace(s), suffix) {
+			return true, nil
+		}
+	}
+	return false, sc.Err()
+}
@@ -0,0 +1,167 @@
+package passcheck
+
+import (
+	"math"
+	"regexp"
+	"strings"
+)
+
+// Result — оценка стойкости пароля
+type Result struct {
+	// Score — от 0 (угадывается сразу) до 4 (очень стойкий)
+	Score int
+	// Guesses — оценка числа попыток до угадывания
+	Guesses float64
+	// Reason — самая заметная слабость пароля; пусто, если слабостей нет
+	Reason string
+}
+
+var userInputSplitRe = regexp.MustCompile(`[^\p{L}]+`)
+
+// Estimate оценивает пароль. userInputs — то, что злоумышленник может знать
+// о пользователе (email, имя); их части считаются самыми вероятными словами.
+func Estimate(password string, userInputs ...string) Result {
+	rs := []rune(password)
+	if len(rs) > maxEstimatedLength {
+		rs = rs[:maxEstimatedLength]
+	}
+	if len(rs) == 0 {
+		return Result{Score: 0, Guesses: 1, Reason: ReasonTooShort}
+	}
+
+	guesses, seq := estimate(rs, userInputDictionary(userInputs))
+	res := Result{Score: score(guesses), Guesses: guesses}
+	res.Reason = weakestReason(seq)
+	return res
+}
+
+// EmailInputs возвращает часть email до @: ее и ее слова злоумышленник
+// проверит первыми.
+func EmailInputs(email string) []string {
+	local := strings.ToLower(strings.TrimSpace(email))
+	if at := strings.LastIndexByte(local, '@'); at >= 0 {
+		local = local[:at]
+	}
+	return []string{local}
+}
+
+func userInputDictionary(inputs []string) map[string]int {
+	dict := make(map[string]int)
+	add := func(w string) {
+		if len([]rune(w)) >= minUserInputLength {
+			if _, ok := dict[w]; !ok {
+				dict[w] = len(dict) + 1
+			}
+		}
+	}
+	for _, in := range inputs {
+		in = strings.ToLower(strings.TrimSpace(in))
+		add(in)
+		parts := userInputSplitRe.Split(in, -1)
+		for _, part := range parts {
+			add(part)
+		}
+		// ivan.petrov → ivanpetrov
+		add(strings.Join(parts, ""))
+	}
+	return dict
+}
+
+func score(guesses float64) int {
+	const delta = 5
+	switch {
+	case guesses < 1e3+delta:
+		return 0
+	case guesses < 1e6+delta:
+		return 1
+	case guesses < 1e8+delta:
+		return 2
+	case guesses < 1e10+delta:
+		return 3
+	default:
+		return 4
+	}
+}
+
+// weakestReason — причина по фрагменту, который закрывает большую часть пароля
+func weakestReason(seq []match) string {
+	best := match{pattern: patternBruteforce}
+	for _, m := range seq {
+		if m.pattern != patternBruteforce && m.length() > best.length() {
+			best = m
+		}
+	}
+	if best.pattern == patternBruteforce {
+		return ReasonTooGuessable
+	}
+	return patternReasons[best.pattern]
+}
+
+type candidate struct {
+	product float64
+	seq     []match
+}
+
+// estimate находит разбиение пароля на фрагменты с наименьшим числом
+// попыток, как это делает zxcvbn: для l фрагментов попыток
+// l! * Π guesses + 10000^(l-1). Между найденными шаблонами — перебор.
+func estimate(rs []rune, userInputs map[string]int) (float64, []match) {
+	n := len(rs)
+	byEnd := make([][]match, n)
+	for _, m := range findMatches(rs, userInputs) {
+		byEnd[m.j] = append(byEnd[m.j], m)
+	}
+
+	// best[k][l] — лучшее разбиение префикса rs[:k+1] на l фрагментов
+	best := make([]map[int]candidate, n)
+	update := func(m match, l int, prev candidate) {
+		product := prev.product * math.Max(m.guesses, 1)
+		if c, ok := best[m.j][l]; ok && c.product <= product {
+			return
+		}
+		seq := make([]match, len(prev.seq), len(prev.seq)+1)
+		copy(seq, prev.seq)
+		best[m.j][l] = candidate{product: product, seq: append(seq, m)}
+	}
+	extend := func(m match, allowAfterBruteforce bool) {
+		if m.i == 0 {
+			update(m, 1, candidate{product: 1})
+			return
+		}
+		for l, prev := range best[m.i-1] {
+			if !allowAfterBruteforce && prev.seq[len(prev.seq)-1].pattern == patternBruteforce {
+				continue
+			}
+			update(m, l+1, prev)
+		}
+	}
+
+	for k := 0; k < n; k++ {
+		best[k] = make(map[int]candidate)
+		for _, m := range byEnd[k] {
+			extend(m, true)
+		}
+		for i := 0; i <= k; i++ {
+			bf := match{i: i, j: k, pattern: patternBruteforce, guesses: math.Pow(10, float64(k-i+1))}
+			extend(bf, false)
+		}
+	}
+
+	total := math.Inf(1)
+	var seq []match
+	for l, c := range best[n-1] {
+		g := factorial(l)*c.product + math.Pow(10000, float64(l-1))
+		if g < total {
+			total, seq = g, c.seq
+		}
+	}
+	return total, seq
+}
+
+func factorial(n int) float64 {
+	f := 1.0
+	for i := 2; i <= n; i++ {
+		f *= float64(i)
+	}
+	return f
+}
@@ -0,0 +1,426 @@
+package passcheck
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type pattern int
+
+const (
+	patternBruteforce pattern = iota
+	patternDictionary
+	patternTranslit
+	patternUserInput
+	patternSpatial
+	patternSequence
+	patternRepeat
+	patternDate
+)
+
+var patternReasons = map[pattern]string{
+	patternBruteforce: ReasonTooGuessable,
+	patternDictionary: ReasonCommonPassword,
+	patternTranslit:   ReasonTransliteratedWord,
+	patternUserInput:  ReasonContainsEmail,
+	patternSpatial:    ReasonKeyboardSequence,
+	patternSequence:   ReasonSequence,
+	patternRepeat:     ReasonRepeated,
+	patternDate:       ReasonDate,
+}
+
+// match — фрагмент пароля [i, j] (по символам, включительно), который
+// угадывается за guesses попыток
+type match struct {
+	i, j    int
+	pattern pattern
+	guesses float64
+}
+
+func (m match) length() int { return m.j - m.i + 1 }
+
+func findMatches(rs []rune, userInputs map[string]int) []match {
+	lower := []rune(strings.ToLower(string(rs)))
+	var out []match
+	out = append(out, dictionaryMatches(rs, lower, userInputs)...)
+	out = append(out, spatialMatches(rs, lower)...)
+	out = append(out, sequenceMatches(lower)...)
+	out = append(out, repeatMatches(rs)...)
+	out = append(out, dateMatches(lower)...)
+	return out
+}
+
+/* словари */
+
+func dictionaryMatches(rs, lower []rune, userInputs map[string]int) []match {
+	forms := []form{identityForm(lower)}
+	if f, ok := leetForm(lower); ok {
+		forms = append(forms, f)
+	}
+	if hasCyrillic(lower) {
+		if f, ok := cyrillicForm(lower); ok {
+			forms = append(forms, f)
+		}
+	} else if f, ok := layoutForm(lower); ok {
+		forms = append(forms, f)
+	}
+
+	dicts := []struct {
+		words   map[string]int
+		pattern pattern
+	}{
+		{userInputs, patternUserInput},
+		{commonWords, patternDictionary},
+		{translitWords, patternTranslit},
+	}
+
+	var out []match
+	for _, f := range forms {
+		n := len(f.text)
+		for a := 0; a < n; a++ {
+			// слово должно начинаться и заканчиваться на границе исходного символа
+			if a > 0 && f.start[a] == f.start[a-1] {
+				continue
+			}
+			for b := a + minDictionaryWordLength; b <= n && b-a <= maxWordLength; b++ {
+				if b < n && f.start[b] == f.start[b-1] {
+					continue
+				}
+				word := f.text[a:b]
+				for _, d := range dicts {
+					rank, ok := d.words[word]
+					if !ok {
+						continue
+					}
+					i, j := f.start[a], f.end[b-1]
+					p := d.pattern
+					if f.transformed && p == patternDictionary {
+						p = patternTranslit
+					}
+					guesses := float64(rank) * caseVariations(rs[i:j+1])
+					if f.leet {
+						guesses *= leetVariations(lower[i : j+1])
+					}
+					if f.transformed {
+						guesses *= 2
+					}
+					out = append(out, match{i: i, j: j, pattern: p, guesses: guesses})
+				}
+			}
+		}
+	}
+	return out
+}
+
+// caseVariations — во сколько раз больше вариантов дает регистр букв:
+// «Password» и «PASSWORD» почти ничего не добавляют к «password».
+func caseVariations(rs []rune) float64 {
+	var upper, lower int
+	for _, r := range rs {
+		switch {
+		case unicode.IsUpper(r):
+			upper++
+		case unicode.IsLower(r):
+			lower++
+		}
+	}
+	if upper == 0 {
+		return 1
+	}
+	if lower == 0 || (upper == 1 && (unicode.IsUpper(rs[0]) || unicode.IsUpper(rs[len(rs)-1]))) {
+		return 2
+	}
+	return variations(upper, lower)
+}
+
+// leetVariations — множитель за замены вроде «0» вместо «o»: для каждой
+// замены учитывается, сколько раз встречаются замененный и исходный символ.
+func leetVariations(lower []rune) float64 {
+	count := make(map[rune]int)
+	for _, r := range lower {
+		count[r]++
+	}
+	v := 1.0
+	for sub, letter := range leetToLatin {
+		subbed := count[sub]
+		if subbed == 0 {
+			continue
+		}
+		if unsubbed := count[letter]; unsubbed == 0 {
+			v *= 2
+		} else {
+			v *= variations(subbed, unsubbed)
+		}
+	}
+	return v
+}
+
+// variations — сумма C(a+b, k) для k = 1..min(a, b)
+func variations(a, b int) float64 {
+	n := a + b
+	sum := 0.0
+	for k := 1; k <= a && k <= b; k++ {
+		sum += binomial(n, k)
+	}
+	return math.Max(sum, 1)
+}
+
+func binomial(n, k int) float64 {
+	if k > n {
+		return 0
+	}
+	r := 1.0
+	for d := 1; d <= k; d++ {
+		r = r * float64(n-k+d) / float64(d)
+	}
+	return r
+}
+
+/* раскладка клавиатуры */
+
+type keyPos struct{ row, x float64 }
+
+// qwertyRows — ряды клавиатуры со сдвигом относительно верхнего ряда
+var qwertyRows = []struct {
+	keys   string
+	shift  string
+	offset float64
+}{
+	{"`1234567890-=", "~!@#$%^&*()_+", 0},
+	{"qwertyuiop[]\\", "QWERTYUIOP{}|", 1.5},
+	{"asdfghjkl;'", "ASDFGHJKL:\"", 1.75},
+	{"zxcvbnm,./", "ZXCVBNM<>?", 2.25},
+}
+
+var (
+	keyPositions = map[rune]keyPos{}
+	shiftedKeys  = map[rune]rune{}
+	keyCount     = 0
+	avgDegree    = 0.0
+)
+
+func init() {
+	for row, r := range qwertyRows {
+		shift := []rune(r.shift)
+		for col, k := range []rune(r.keys) {
+			keyPositions[k] = keyPos{row: float64(row), x: float64(col) + r.offset}
+			shiftedKeys[shift[col]] = k
+		}
+	}
+	keyCount = len(keyPositions)
+	total := 0
+	for a := range keyPositions {
+		for b := range keyPositions {
+			if a != b && adjacent(a, b) {
+				total++
+			}
+		}
+	}
+	avgDegree = float64(total) / float64(keyCount)
+}
+
+// baseKey возвращает клавишу, на которой набран символ, и был ли нажат Shift.
+// Кириллица сводится к той же клавише латинской раскладки.
+func baseKey(r rune) (rune, bool, bool) {
+	if k, ok := shiftedKeys[r]; ok {
+		return k, true, true
+	}
+	shifted := unicode.IsUpper(r)
+	r = unicode.ToLower(r)
+	if k, ok := cyrillicToQwerty[r]; ok {
+		r = k
+	}
+	_, ok := keyPositions[r]
+	return r, shifted, ok
+}
+
+func adjacent(a, b rune) bool {
+	pa, pb := keyPositions[a], keyPositions[b]
+	dr := math.Abs(pa.row - pb.row)
+	dx := math.Abs(pa.x - pb.x)
+	return (dr == 0 && dx == 1) || (dr == 1 && dx < 1)
+}
+
+// spatialMatches находит пути по соседним клавишам: qwerty, 1qaz2wsx, йцукен
+func spatialMatches(rs, lower []rune) []match {
+	var out []match
+	n := len(rs)
+	for i := 0; i < n-2; i++ {
+		prev, shifted, ok := baseKey(rs[i])
+		if !ok {
+			continue
+		}
+		shiftCount := 0
+		if shifted {
+			shiftCount++
+		}
+		turns := 0
+		lastDir := keyPos{}
+		j := i
+		for j+1 < n {
+			k, sh, ok := baseKey(rs[j+1])
+			if !ok || k == prev || !adjacent(prev, k) {
+				break
+			}
+			dir := keyPos{row: keyPositions[k].row - keyPositions[prev].row, x: keyPositions[k].x - keyPositions[prev].x}
+			if j > i && dir != lastDir {
+				turns++
+			}
+			lastDir = dir
+			if sh {
+				shiftCount++
+			}
+			prev = k
+			j++
+		}
+		if length := j - i + 1; length >= 3 {
+			out = append(out, match{i: i, j: j, pattern: patternSpatial, guesses: spatialGuesses(length, turns, shiftCount)})
+			i = j - 1
+		}
+	}
+	return out
+}
+
+func spatialGuesses(length, turns, shifted int) float64 {
+	if turns < 1 {
+		turns = 1
+	}
+	guesses := 0.0
+	for l := 2; l <= length; l++ {
+		for t := 1; t <= turns && t <= l-1; t++ {
+			guesses += binomial(l-1, t-1) * float64(keyCount) * math.Pow(avgDegree, float64(t))
+		}
+	}
+	if shifted > 0 {
+		if unshifted := length - shifted; unshifted == 0 {
+			guesses *= 2
+		} else {
+			guesses *= variations(shifted, unshifted)
+		}
+	}
+	return guesses
+}
+
+/* последовательности и повторы */
+
+// sequenceMatches — abc, 9876, абвг
+func sequenceMatches(lower []rune) []match {
+	var out []match
+	n := len(lower)
+	for i := 0; i < n-2; {
+		delta := lower[i+1] - lower[i]
+		if delta != 1 && delta != -1 {
+			i++
+			continue
+		}
+		j := i + 1
+		for j+1 < n && lower[j+1]-lower[j] == delta {
+			j++
+		}
+		if j-i+1 >= 3 && (unicode.IsLetter(lower[i]) || unicode.IsDigit(lower[i])) {
+			base := 26.0
+			switch {
+			case strings.ContainsRune("az019а", lower[i]):
+				base = 4
+			case unicode.IsDigit(lower[i]):
+				base = 10
+			case unicode.Is(unicode.Cyrillic, lower[i]):
+				base = 33
+			}
+			if delta < 0 {
+				base *= 2
+			}
+			out = append(out, match{i: i, j: j, pattern: patternSequence, guesses: base * float64(j-i+1)})
+		}
+		i = j
+	}
+	return out
+}
+
+// repeatMatches — aaaa, abcabc, 123123. Для каждой позиции берется самый
+// короткий повторяющийся кусок: он сам уже не состоит из повторов, так что
+// рекурсивная оценка куска неглубокая.
+func repeatMatches(rs []rune) []match {
+	var out []match
+	n := len(rs)
+	for i := 0; i < n; i++ {
+		for size := 1; size <= (n-i)/2; size++ {
+			unit := string(rs[i : i+size])
+			count := 1
+			for i+(count+1)*size <= n && string(rs[i+count*size:i+(count+1)*size]) == unit {
+				count++
+			}
+			if count < 2 || (size == 1 && count < 3) {
+				continue
+			}
+			base, _ := estimate([]rune(unit), nil)
+			out = append(out, match{
+				i:       i,
+				j:       i + count*size - 1,
+				pattern: patternRepeat,
+				guesses: base * float64(count),
+			})
+			break
+		}
+	}
+	return out
+}
+
+/* даты */
+
+var dateRe = regexp.MustCompile(`^(\d{1,2})([./\-]?)(\d{1,2})([./\-]?)(\d{2}|\d{4})$`)
+
+const (
+	referenceYear = 2025
+	minYearSpace  = 20
+)
+
+func yearSpace(year int) float64 {
+	return math.Max(math.Abs(float64(year-referenceYear)), minYearSpace)
+}
+
+// dateMatches — годы (1987) и даты (01.02.1990, 010290)
+func dateMatches(lower []rune) []match {
+	var out []match
+	n := len(lower)
+	for i := 0; i < n; i++ {
+		for j := i + 3; j < n && j-i < 10; j++ {
+			s := string(lower[i : j+1])
+			if j-i == 3 {
+				if year, err := strconv.Atoi(s); err == nil && year >= 1900 && year <= 2039 {
+					out = append(out, match{i: i, j: j, pattern: patternDate, guesses: yearSpace(year)})
+				}
+				continue
+			}
+			m := dateRe.FindStringSubmatch(s)
+			if m == nil || m[2] != m[4] {
+				continue
+			}
+			a, _ := strconv.Atoi(m[1])
+			b, _ := strconv.Atoi(m[3])
+			year, _ := strconv.Atoi(m[5])
+			if len(m[5]) == 2 {
+				year += 1900
+				if year < 1950 {
+					year += 100
+				}
+			}
+			if !validDayMonth(a, b) && !validDayMonth(b, a) {
+				continue
+			}
+			guesses := 365 * yearSpace(year)
+			if m[2] != "" {
+				guesses *= 4
+			}
+			out = append(out, match{i: i, j: j, pattern: patternDate, guesses: guesses})
+		}
+	}
+	return out
+}
+
+func validDayMonth(day, month int) bool {
+	return day >= 1 && day <= 31 && month >= 1 && month <= 12
+}
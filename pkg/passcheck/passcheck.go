@@ -0,0 +1,75 @@
+// Package passcheck оценивает стойкость паролей без обращения к сети:
+// сверяет пароль со встроенным списком утекших паролей (SHA-1 с поиском по
+// префиксу, как в k-anonymity API Have I Been Pwned) и дает оценку в духе
+// zxcvbn — сколько попыток нужно, чтобы пароль угадать.
+package passcheck
+
+import (
+	_ "embed"
+	"strings"
+)
+
+// Причины, по которым пароль считается слабым. Уходят клиенту как есть.
+const (
+	ReasonTooShort           = "too_short"
+	ReasonCharacterClasses   = "missing_character_classes"
+	ReasonBreached           = "breached"
+	ReasonCommonPassword     = "common_password"
+	ReasonContainsEmail      = "contains_email"
+	ReasonKeyboardSequence   = "keyboard_sequence"
+	ReasonSequence           = "sequence"
+	ReasonRepeated           = "repeated"
+	ReasonDate               = "date"
+	ReasonTransliteratedWord = "transliterated_word"
+	ReasonTooGuessable       = "too_guessable"
+)
+
+const (
+	// MaxScore — оценка самого стойкого пароля, шкала 0..4 как у zxcvbn
+	MaxScore = 4
+	// DefaultMinScore — минимальная оценка, с которой пароль принимается
+	DefaultMinScore = 3
+
+	maxEstimatedLength      = 64
+	minDictionaryWordLength = 3
+	minUserInputLength      = 3
+)
+
+//go:embed data/common.txt
+var commonData string
+
+//go:embed data/translit.txt
+var translitData string
+
+// словари: слово → ранг (чем меньше, тем популярнее)
+var (
+	commonWords   = rankedWords(commonData)
+	translitWords = rankedWords(translitData)
+	maxWordLength = longestWord(commonWords, translitWords)
+)
+
+func rankedWords(data string) map[string]int {
+	words := make(map[string]int)
+	for _, w := range strings.Split(data, "\n") {
+		w = strings.ToLower(strings.TrimSpace(w))
+		if w == "" {
+			continue
+		}
+		if _, ok := words[w]; !ok {
+			words[w] = len(words) + 1
+		}
+	}
+	return words
+}
+
+func longestWord(dicts ...map[string]int) int {
+	n := 0
+	for _, d := range dicts {
+		for w := range d {
+			if len(w) > n {
+				n = len(w)
+			}
+		}
+	}
+	return n
+}
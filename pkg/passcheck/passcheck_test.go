@@ -0,0 +1,70 @@
+package passcheck
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEstimate_Reasons(t *testing.T) {
+	cases := []struct {
+		password string
+		reason   string
+	}{
+		{"Password1!", ReasonCommonPassword},
+		{"qwerty123", ReasonCommonPassword},
+		{"zxcvbnm,./", ReasonKeyboardSequence},
+		{"Gfhjkm2024!", ReasonTransliteratedWord},
+		{"Lyubov1987!", ReasonTransliteratedWord},
+		{"Пароль123!", ReasonTransliteratedWord},
+		{"Ivanpetrov1!", ReasonContainsEmail},
+		{"aaaaaaaa", ReasonRepeated},
+		{"01.02.1990", ReasonDate},
+	}
+	for _, c := range cases {
+		res := Estimate(c.password, EmailInputs("ivan.petrov@example.com")...)
+		if res.Score >= DefaultMinScore {
+			t.Errorf("%q: expected weak password, got score %d", c.password, res.Score)
+		}
+		if res.Reason != c.reason {
+			t.Errorf("%q: expected reason %s, got %s", c.password, c.reason, res.Reason)
+		}
+	}
+}
+
+func TestEstimate_StrongPasswords(t *testing.T) {
+	for _, p := range []string{"Str0ng!Pass", "K8#pV2!mz9Lq", "correct horse battery staple"} {
+		if res := Estimate(p); res.Score < DefaultMinScore {
+			t.Errorf("%q: expected strong password, got score %d (%s)", p, res.Score, res.Reason)
+		}
+	}
+}
+
+func TestBreachList_Bundled(t *testing.T) {
+	b := NewBreachList("")
+	for p, want := range map[string]bool{"Password1!": true, "ghbdtn": true, "K8#pV2!mz9Lq": false} {
+		got, err := b.Breached(p)
+		if err != nil || got != want {
+			t.Errorf("%q: expected %v, got %v (err %v)", p, want, got, err)
+		}
+	}
+}
+
+func TestBreachList_RangeDir(t *testing.T) {
+	const password = "K8#pV2!mz9Lq"
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, hash[:5]+".txt"), []byte(hash[5:]+":3\r\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := NewBreachList(dir).Breached(password)
+	if err != nil || !got {
+		t.Fatalf("expected password from range file, got %v (err %v)", got, err)
+	}
+}
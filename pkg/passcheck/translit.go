@@ -0,0 +1,129 @@
+package passcheck
+
+import "unicode"
+
+// qwertyToCyrillic — русская раскладка ЙЦУКЕН поверх латинской: «gfhjkm» —
+// это «пароль», набранный без переключения раскладки.
+var qwertyToCyrillic = map[rune]rune{
+	'q': 'й', 'w': 'ц', 'e': 'у', 'r': 'к', 't': 'е', 'y': 'н', 'u': 'г',
+	'i': 'ш', 'o': 'щ', 'p': 'з', '[': 'х', ']': 'ъ', 'a': 'ф', 's': 'ы',
+	'd': 'в', 'f': 'а', 'g': 'п', 'h': 'р', 'j': 'о', 'k': 'л', 'l': 'д',
+	';': 'ж', '\'': 'э', 'z': 'я', 'x': 'ч', 'c': 'с', 'v': 'м', 'b': 'и',
+	'n': 'т', 'm': 'ь', ',': 'б', '.': 'ю', '`': 'ё',
+}
+
+var cyrillicToQwerty = invert(qwertyToCyrillic)
+
+var cyrillicToLatin = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e",
+	'ж': "zh", 'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m",
+	'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u",
+	'ф': "f", 'х': "h", 'ц': "c", 'ч': "ch", 'ш': "sh", 'щ': "sch", 'ъ': "",
+	'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+}
+
+// leetToLatin — самые частые замены букв цифрами и символами
+var leetToLatin = map[rune]rune{
+	'4': 'a', '@': 'a', '3': 'e', '1': 'i', '!': 'i', '0': 'o',
+	'$': 's', '5': 's', '7': 't', '+': 't',
+}
+
+func invert(m map[rune]rune) map[rune]rune {
+	out := make(map[rune]rune, len(m))
+	for k, v := range m {
+		out[v] = k
+	}
+	return out
+}
+
+// form — вариант записи пароля, в котором ищутся словарные слова. Каждой
+// позиции text соответствует диапазон исходных символов [start, end].
+type form struct {
+	text       string
+	start, end []int
+	// transformed — слово найдено только после смены раскладки или
+	// транслитерации
+	transformed bool
+	leet        bool
+}
+
+type chunkFunc func(r rune) (string, bool)
+
+// buildForm переводит пароль посимвольно; chunk может вернуть несколько
+// латинских букв («ш» → «sh») или ни одной («ь»).
+func buildForm(lower []rune, chunk chunkFunc) (form, bool) {
+	var f form
+	var text []byte
+	changed := false
+	for i, r := range lower {
+		s, ok := chunk(r)
+		if ok {
+			changed = true
+		} else {
+			s = string(r)
+		}
+		if s == "" {
+			// пустой кусок приклеивается к предыдущему символу
+			if n := len(f.end); n > 0 {
+				f.end[n-1] = i
+			}
+			continue
+		}
+		for j := 0; j < len(s); j++ {
+			text = append(text, s[j])
+			f.start = append(f.start, i)
+			f.end = append(f.end, i)
+		}
+	}
+	f.text = string(text)
+	return f, changed
+}
+
+func identityForm(lower []rune) form {
+	f, _ := buildForm(lower, func(r rune) (string, bool) { return "", false })
+	return f
+}
+
+func leetForm(lower []rune) (form, bool) {
+	f, ok := buildForm(lower, func(r rune) (string, bool) {
+		if l, ok := leetToLatin[r]; ok {
+			return string(l), true
+		}
+		return "", false
+	})
+	f.leet = true
+	return f, ok
+}
+
+// layoutForm — латинские символы, набранные в русской раскладке, переведенные
+// в транслит: «ghbdtn» → «привет» → «privet»
+func layoutForm(lower []rune) (form, bool) {
+	f, ok := buildForm(lower, func(r rune) (string, bool) {
+		if c, ok := qwertyToCyrillic[r]; ok {
+			return cyrillicToLatin[c], true
+		}
+		return "", false
+	})
+	f.transformed = true
+	return f, ok
+}
+
+func cyrillicForm(lower []rune) (form, bool) {
+	f, ok := buildForm(lower, func(r rune) (string, bool) {
+		if s, ok := cyrillicToLatin[r]; ok {
+			return s, true
+		}
+		return "", false
+	})
+	f.transformed = true
+	return f, ok
+}
+
+func hasCyrillic(rs []rune) bool {
+	for _, r := range rs {
+		if unicode.Is(unicode.Cyrillic, r) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,50 @@
+package passhash
+
+import (
+	"errors"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const DefaultBcryptCost = bcrypt.DefaultCost
+
+// Bcrypt хранит хэши в собственном формате $2a$<cost>$..., который тоже
+// укладывается в схему $<алгоритм>$.
+type Bcrypt struct {
+	cost int
+}
+
+func NewBcrypt(cost int) *Bcrypt {
+	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		cost = DefaultBcryptCost
+	}
+	return &Bcrypt{cost: cost}
+}
+
+func (b *Bcrypt) ID() string { return "2a" }
+
+func (b *Bcrypt) Hash(password string) (string, error) {
+	h, err := bcrypt.GenerateFromPassword([]byte(password), b.cost)
+	return string(h), err
+}
+
+func (b *Bcrypt) Recognizes(encoded string) bool {
+	switch AlgorithmOf(encoded) {
+	case "2a", "2b", "2y":
+		return true
+	}
+	return false
+}
+
+func (b *Bcrypt) Verify(password, encoded string) (bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (b *Bcrypt) Outdated(encoded string) bool {
+	cost, err := bcrypt.Cost([]byte(encoded))
+	return err != nil || cost != b.cost
+}
@@ -0,0 +1,75 @@
+// Package passhash хэширует пароли. Хэши хранятся в PHC-формате
+// ($<алгоритм>$...), поэтому в базе могут одновременно жить хэши разных
+// алгоритмов и параметров: новые пароли хэшируются текущим алгоритмом, а
+// старые хэши проверяются своим и при входе пересчитываются.
+package passhash
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrUnknownFormat = errors.New("passhash: unknown hash format")
+
+// Algorithm — одна схема хэширования паролей
+type Algorithm interface {
+	// ID — идентификатор алгоритма в PHC-строке
+	ID() string
+	Hash(password string) (string, error)
+	// Recognizes сообщает, что encoded получен этим алгоритмом
+	Recognizes(encoded string) bool
+	Verify(password, encoded string) (bool, error)
+	// Outdated сообщает, что encoded посчитан с параметрами, отличными от
+	// текущих настроек алгоритма
+	Outdated(encoded string) bool
+}
+
+// Hasher хэширует пароли текущим алгоритмом и проверяет хэши любого из
+// известных алгоритмов.
+type Hasher struct {
+	current Algorithm
+	all     []Algorithm
+}
+
+// New создает Hasher: current используется для новых хэшей, legacy —
+// только для проверки уже сохраненных.
+func New(current Algorithm, legacy ...Algorithm) *Hasher {
+	return &Hasher{
+		current: current,
+		all:     append([]Algorithm{current}, legacy...),
+	}
+}
+
+// Default — argon2id с параметрами по умолчанию, bcrypt для старых хэшей
+func Default() *Hasher {
+	return New(NewArgon2id(DefaultArgon2idParams), NewBcrypt(DefaultBcryptCost))
+}
+
+func (h *Hasher) Hash(password string) (string, error) {
+	return h.current.Hash(password)
+}
+
+// Verify проверяет пароль. rehash = true, если пароль верный, но хэш стоит
+// пересчитать: он получен другим алгоритмом или с устаревшими параметрами.
+func (h *Hasher) Verify(password, encoded string) (ok, rehash bool, err error) {
+	for _, alg := range h.all {
+		if !alg.Recognizes(encoded) {
+			continue
+		}
+		ok, err = alg.Verify(password, encoded)
+		if err != nil || !ok {
+			return false, false, err
+		}
+		return true, alg != h.current || alg.Outdated(encoded), nil
+	}
+	return false, false, ErrUnknownFormat
+}
+
+// AlgorithmOf возвращает идентификатор алгоритма из PHC-строки
+func AlgorithmOf(encoded string) string {
+	parts := strings.SplitN(encoded, "$", 3)
+	if len(parts) < 3 || parts[0] != "" {
+		return ""
+	}
+	return parts[1]
+}
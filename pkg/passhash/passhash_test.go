@@ -0,0 +1,61 @@
+package passhash
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+var testArgon2 = Argon2idParams{Memory: 1024, Iterations: 1, Parallelism: 1}
+
+func TestArgon2id_RoundTrip(t *testing.T) {
+	h := New(NewArgon2id(testArgon2))
+	encoded, err := h.Hash("Secret#123")
+	if err != nil {
+		t.Fatalf("hash: %v", err)
+	}
+	if !strings.HasPrefix(encoded, "$argon2id$v=19$m=1024,t=1,p=1$") {
+		t.Fatalf("unexpected PHC string %q", encoded)
+	}
+
+	ok, rehash, err := h.Verify("Secret#123", encoded)
+	if err != nil || !ok || rehash {
+		t.Fatalf("expected match without rehash, got ok=%v rehash=%v err=%v", ok, rehash, err)
+	}
+	if ok, _, _ := h.Verify("secret#123", encoded); ok {
+		t.Fatal("wrong password accepted")
+	}
+}
+
+func TestVerify_LegacyBcryptNeedsRehash(t *testing.T) {
+	legacy, _ := bcrypt.GenerateFromPassword([]byte("Secret#123"), bcrypt.MinCost)
+	h := New(NewArgon2id(testArgon2), NewBcrypt(bcrypt.MinCost))
+
+	ok, rehash, err := h.Verify("Secret#123", string(legacy))
+	if err != nil || !ok || !rehash {
+		t.Fatalf("expected match with rehash, got ok=%v rehash=%v err=%v", ok, rehash, err)
+	}
+	if ok, rehash, _ := h.Verify("nope", string(legacy)); ok || rehash {
+		t.Fatal("wrong password accepted")
+	}
+}
+
+func TestVerify_OutdatedParams(t *testing.T) {
+	old, _ := NewArgon2id(testArgon2).Hash("Secret#123")
+	stronger := testArgon2
+	stronger.Iterations = 2
+	h := New(NewArgon2id(stronger))
+
+	ok, rehash, err := h.Verify("Secret#123", old)
+	if err != nil || !ok || !rehash {
+		t.Fatalf("expected rehash for old params, got ok=%v rehash=%v err=%v", ok, rehash, err)
+	}
+}
+
+func TestVerify_UnknownFormat(t *testing.T) {
+	h := New(NewArgon2id(testArgon2), NewBcrypt(bcrypt.MinCost))
+	if _, _, err := h.Verify("x", "plaintext"); err != ErrUnknownFormat {
+		t.Fatalf("expected ErrUnknownFormat, got %v", err)
+	}
+}
@@ -0,0 +1,96 @@
+// Package rbac — общая для всех сервисов модель ролей и разрешений.
+// Роли хранятся в auth_service (таблица account_role) и приходят в JWT,
+// а разрешения вычисляются из ролей на месте.
+package rbac
+
+const (
+	RoleCustomer   = "customer"
+	RoleStoreOwner = "store_owner"
+	RoleStoreStaff = "store_staff"
+	RoleCourier    = "courier"
+	RoleAdmin      = "admin"
+)
+
+const (
+	PermStoresManage     = "stores:manage"
+	PermMenuWrite        = "menu:write"
+	PermStoreOrdersRead  = "store_orders:read"
+	PermDeliveriesManage = "deliveries:manage"
+	PermRolesManage      = "roles:manage"
+	PermAuditRead        = "audit:read"
+)
+
+var rolePermissions = map[string][]string{
+	RoleCustomer:   {},
+	RoleStoreOwner: {PermStoresManage, PermMenuWrite, PermStoreOrdersRead},
+	// права на меню и заказы появятся вместе с привязкой сотрудника к
+	// магазину: сейчас доступ к магазину проверяется только по store_owner
+	RoleStoreStaff: {},
+	RoleCourier:    {PermDeliveriesManage},
+	RoleAdmin: {
+		PermStoresManage, PermMenuWrite, PermStoreOrdersRead,
+		PermDeliveriesManage, PermRolesManage, PermAuditRead,
+	},
+}
+
+// IsKnownRole проверяет, что роль есть в модели (и в check-ограничении БД)
+func IsKnownRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+func HasRole(roles []string, want ...string) bool {
+	for _, r := range roles {
+		for _, w := range want {
+			if r == w {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func HasPermission(roles []string, perm string) bool {
+	for _, r := range roles {
+		for _, p := range rolePermissions[r] {
+			if p == perm {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Scopes API-ключей. Ключ работает только на маршрутах своих scope и
+// только в пределах текущих ролей владельца.
+const (
+	ScopeMenuRead   = "menu:read"
+	ScopeMenuWrite  = "menu:write"
+	ScopeOrdersRead = "orders:read"
+)
+
+// scopePermissions — разрешение, без которого scope нельзя выпустить;
+// пустая строка — scope доступен любому пользователю. orders:read открывает
+// заказы магазинов владельца ключа, а не его личные заказы
+var scopePermissions = map[string]string{
+	// меню для управления отдается с архивными позициями, поэтому читать его
+	// может только тот, кто может его менять
+	ScopeMenuRead:   PermMenuWrite,
+	ScopeMenuWrite:  PermMenuWrite,
+	ScopeOrdersRead: PermStoreOrdersRead,
+}
+
+func IsKnownScope(scope string) bool {
+	_, ok := scopePermissions[scope]
+	return ok
+}
+
+// CanGrantScope проверяет, что пользователь с ролями roles может выпустить
+// ключ со scope
+func CanGrantScope(roles []string, scope string) bool {
+	perm, ok := scopePermissions[scope]
+	if !ok {
+		return false
+	}
+	return perm == "" || HasPermission(roles, perm)
+}
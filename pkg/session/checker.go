@@ -0,0 +1,41 @@
+package session
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Checker проверяет, что сессия, в рамках которой выпущен access-токен,
+// не была отозвана в auth_service.
+type Checker interface {
+	IsActive(ctx context.Context, sessionID string) (bool, error)
+}
+
+type rowQuerier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+const isActiveSQL = `SELECT revoked_at IS NULL FROM session WHERE id = $1`
+
+// PostgresChecker читает состояние сессии напрямую из общей таблицы session.
+type PostgresChecker struct {
+	db rowQuerier
+}
+
+func NewPostgresChecker(db rowQuerier) *PostgresChecker {
+	return &PostgresChecker{db: db}
+}
+
+func (c *PostgresChecker) IsActive(ctx context.Context, sessionID string) (bool, error) {
+	var active bool
+	err := c.db.QueryRow(ctx, isActiveSQL, sessionID).Scan(&active)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return active, nil
+}
@@ -0,0 +1,46 @@
+package signedurl_test
+
+import (
+	"apple_backend/pkg/signedurl"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func parse(t *testing.T, raw string) *url.URL {
+	t.Helper()
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u
+}
+
+func TestSigner_Verify(t *testing.T) {
+	s := signedurl.New("secret")
+	link := s.Sign("/api/v0/exports/e1/download", time.Now().Add(time.Hour))
+
+	if err := s.Verify(parse(t, link)); err != nil {
+		t.Fatalf("valid link rejected: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		link string
+		want error
+	}{
+		{name: "other path", link: strings.Replace(link, "/e1/", "/e2/", 1), want: signedurl.ErrInvalid},
+		{name: "extended expiry", link: strings.Replace(link, "expires=", "expires=9", 1), want: signedurl.ErrInvalid},
+		{name: "no signature", link: "/api/v0/exports/e1/download?expires=9999999999", want: signedurl.ErrInvalid},
+		{name: "other secret", link: signedurl.New("other").Sign("/api/v0/exports/e1/download", time.Now().Add(time.Hour)), want: signedurl.ErrInvalid},
+		{name: "expired", link: s.Sign("/api/v0/exports/e1/download", time.Now().Add(-time.Second)), want: signedurl.ErrExpired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := s.Verify(parse(t, tt.link)); err != tt.want {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
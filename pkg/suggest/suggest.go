@@ -0,0 +1,251 @@
+// Package suggest — префиксный индекс для подсказок поиска. Индекс строится
+// целиком из снимка каталога и дальше только читается, поэтому поиск не
+// берет блокировок: в каждом узле дерева заранее лежат лучшие записи, и
+// ответ стоит O(длина префикса).
+package suggest
+
+import (
+	"apple_backend/pkg/translit"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// maxDepth — глубина дерева в рунах. Более длинный префикс ищется по первым
+// maxDepth рунам, а кандидаты дофильтровываются по тексту.
+const maxDepth = 16
+
+// Entry — подсказка
+type Entry struct {
+	Kind   string
+	ID     string
+	Text   string
+	Weight float64
+}
+
+type key struct {
+	city string
+	kind string
+}
+
+// node хранит детей срезом: у большинства узлов один-два ребенка, и
+// линейный поиск по срезу дешевле карты и по памяти, и по времени сборки
+type node struct {
+	runes    []rune
+	children []*node
+	top      []*Entry
+}
+
+func (n *node) child(r rune) *node {
+	for i, c := range n.runes {
+		if c == r {
+			return n.children[i]
+		}
+	}
+	return nil
+}
+
+// Index — неизменяемый индекс подсказок по городам и видам записей
+type Index struct {
+	roots map[key]*node
+}
+
+// Builder собирает записи для индекса. Одна и та же запись (вид и ID),
+// добавленная несколько раз в один город, складывает веса.
+type Builder struct {
+	topK    int
+	entries map[key]map[string]*Entry
+}
+
+// NewBuilder создает сборщик, который хранит в каждом узле topK лучших
+// записей
+func NewBuilder(topK int) *Builder {
+	return &Builder{topK: topK, entries: make(map[key]map[string]*Entry)}
+}
+
+// Add добавляет запись в город city и в общий индекс без города
+func (b *Builder) Add(city string, e Entry) {
+	b.add(key{city: city, kind: e.Kind}, e)
+	if city != "" {
+		b.add(key{kind: e.Kind}, e)
+	}
+}
+
+func (b *Builder) add(k key, e Entry) {
+	byID, ok := b.entries[k]
+	if !ok {
+		byID = make(map[string]*Entry)
+		b.entries[k] = byID
+	}
+	if cur, ok := byID[e.ID]; ok {
+		cur.Weight += e.Weight
+		return
+	}
+	byID[e.ID] = &e
+}
+
+// Build строит индекс. Записи находятся по началу любого слова текста, в
+// том числе в транслитерации: "пиц" находит "Pizza Heart".
+func (b *Builder) Build() *Index {
+	ix := &Index{roots: make(map[key]*node, len(b.entries))}
+	for k, byID := range b.entries {
+		entries := make([]*Entry, 0, len(byID))
+		for _, e := range byID {
+			entries = append(entries, e)
+		}
+		// тяжелые записи вставляются первыми, и узлу достаточно взять
+		// первые topK, которые до него дошли
+		sort.Slice(entries, func(i, j int) bool {
+			if entries[i].Weight != entries[j].Weight {
+				return entries[i].Weight > entries[j].Weight
+			}
+			return entries[i].Text < entries[j].Text
+		})
+
+		alloc := &nodeAlloc{}
+		root := alloc.new()
+		for _, e := range entries {
+			for _, text := range keys(e.Text) {
+				for _, suffix := range wordSuffixes(text) {
+					root.insert(alloc, suffix, e, b.topK)
+				}
+			}
+		}
+		ix.roots[k] = root
+	}
+	return ix
+}
+
+// nodeAlloc выделяет узлы пачками: у длинных названий почти каждый узел
+// хвоста уникален, и поштучное выделение делает сборку в разы медленнее
+type nodeAlloc struct {
+	chunk []node
+}
+
+func (a *nodeAlloc) new() *node {
+	if len(a.chunk) == 0 {
+		a.chunk = make([]node, 4096)
+	}
+	n := &a.chunk[0]
+	a.chunk = a.chunk[1:]
+	return n
+}
+
+func (n *node) insert(alloc *nodeAlloc, suffix []rune, e *Entry, topK int) {
+	cur := n
+	for i := 0; i < len(suffix) && i < maxDepth; i++ {
+		next := cur.child(suffix[i])
+		if next == nil {
+			next = alloc.new()
+			cur.runes = append(cur.runes, suffix[i])
+			cur.children = append(cur.children, next)
+		}
+		cur = next
+		cur.offer(e, topK)
+	}
+}
+
+// offer добавляет запись в лучшие узла, если там есть место. Запись могла
+// уже дойти до узла через другое слово или транслитерацию.
+func (n *node) offer(e *Entry, topK int) {
+	if len(n.top) >= topK {
+		return
+	}
+	for _, t := range n.top {
+		if t == e {
+			return
+		}
+	}
+	n.top = append(n.top, e)
+}
+
+// Lookup возвращает до limit лучших записей вида kind в городе city
+// (пусто — во всех городах), у которых какое-то слово начинается с prefix
+func (ix *Index) Lookup(city, kind, prefix string, limit int) []Entry {
+	if ix == nil || limit <= 0 {
+		return nil
+	}
+	q := []rune(Normalize(prefix))
+	if len(q) == 0 {
+		return nil
+	}
+	cur, ok := ix.roots[key{city: city, kind: kind}]
+	if !ok {
+		return nil
+	}
+	for i := 0; i < len(q) && i < maxDepth; i++ {
+		if cur = cur.child(q[i]); cur == nil {
+			return nil
+		}
+	}
+
+	out := make([]Entry, 0, min(limit, len(cur.top)))
+	long := len(q) > maxDepth
+	for _, e := range cur.top {
+		if long && !matchesPrefix(e.Text, string(q)) {
+			continue
+		}
+		out = append(out, *e)
+		if len(out) == limit {
+			break
+		}
+	}
+	return out
+}
+
+// Normalize приводит текст к виду ключей индекса: нижний регистр, е вместо
+// ё, слова из букв и цифр через один пробел
+func Normalize(s string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range strings.ToLower(s) {
+		if r == 'ё' {
+			r = 'е'
+		}
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if space && b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			space = false
+			b.WriteRune(r)
+			continue
+		}
+		space = true
+	}
+	return b.String()
+}
+
+// keys — нормализованный текст и его транслитерации без повторов
+func keys(text string) []string {
+	norm := Normalize(text)
+	out := []string{norm}
+	for _, v := range []string{translit.ToLatin(norm), translit.ToCyrillic(norm)} {
+		if v = Normalize(v); v != "" && v != out[0] && (len(out) == 1 || v != out[1]) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// wordSuffixes возвращает хвосты text, начинающиеся с каждого слова
+func wordSuffixes(text string) [][]rune {
+	runes := []rune(text)
+	var out [][]rune
+	for i := range runes {
+		if i == 0 || runes[i-1] == ' ' {
+			out = append(out, runes[i:])
+		}
+	}
+	return out
+}
+
+func matchesPrefix(text, prefix string) bool {
+	for _, k := range keys(text) {
+		for _, suffix := range wordSuffixes(k) {
+			if strings.HasPrefix(string(suffix), prefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
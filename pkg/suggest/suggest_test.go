@@ -0,0 +1,80 @@
+package suggest
+
+import (
+	"fmt"
+	"testing"
+)
+
+func ids(entries []Entry) []string {
+	out := make([]string, 0, len(entries))
+	for _, e := range entries {
+		out = append(out, e.ID)
+	}
+	return out
+}
+
+func TestLookup_PrefixWordsAndWeight(t *testing.T) {
+	b := NewBuilder(10)
+	b.Add("msk", Entry{Kind: "store", ID: "heart", Text: "Pizza Heart", Weight: 5})
+	b.Add("msk", Entry{Kind: "store", ID: "napoli", Text: "Пиццерия Наполи", Weight: 9})
+	b.Add("spb", Entry{Kind: "store", ID: "pirog", Text: "Пироги", Weight: 100})
+	ix := b.Build()
+
+	got := ids(ix.Lookup("msk", "store", "пиц", 10))
+	if fmt.Sprint(got) != "[napoli heart]" {
+		t.Fatalf("expected weighted match with transliteration, got %v", got)
+	}
+	if got := ids(ix.Lookup("msk", "store", "hea", 10)); fmt.Sprint(got) != "[heart]" {
+		t.Fatalf("expected match by second word, got %v", got)
+	}
+	if got := ids(ix.Lookup("msk", "store", "пир", 10)); len(got) != 0 {
+		t.Fatalf("expected other city to be excluded, got %v", got)
+	}
+	if got := ids(ix.Lookup("", "store", "пи", 1)); fmt.Sprint(got) != "[pirog]" {
+		t.Fatalf("expected all cities without city filter, got %v", got)
+	}
+	if got := ix.Lookup("msk", "item", "пиц", 10); len(got) != 0 {
+		t.Fatalf("expected kinds to be separate, got %v", got)
+	}
+}
+
+func TestBuilder_SumsWeightsOfSameEntry(t *testing.T) {
+	b := NewBuilder(10)
+	b.Add("msk", Entry{Kind: "item", ID: "margarita", Text: "Маргарита", Weight: 1})
+	b.Add("msk", Entry{Kind: "item", ID: "margarita", Text: "Маргарита", Weight: 2})
+	b.Add("msk", Entry{Kind: "item", ID: "mango", Text: "Манго ласси", Weight: 2.5})
+	ix := b.Build()
+
+	got := ix.Lookup("msk", "item", "ма", 10)
+	if len(got) != 2 || got[0].ID != "margarita" || got[0].Weight != 3 {
+		t.Fatalf("expected summed weight to win, got %+v", got)
+	}
+}
+
+func TestLookup_LongPrefix(t *testing.T) {
+	b := NewBuilder(10)
+	b.Add("", Entry{Kind: "item", ID: "a", Text: "Пицца четыре сыра с грушей и медом", Weight: 2})
+	b.Add("", Entry{Kind: "item", ID: "b", Text: "Пицца четыре сыра с ветчиной", Weight: 1})
+	ix := b.Build()
+
+	if got := ids(ix.Lookup("", "item", "пицца четыре сыра с ветч", 10)); fmt.Sprint(got) != "[b]" {
+		t.Fatalf("expected prefix longer than tree depth to be filtered, got %v", got)
+	}
+}
+
+func BenchmarkLookup(b *testing.B) {
+	builder := NewBuilder(10)
+	for i := 0; i < 50000; i++ {
+		builder.Add(fmt.Sprintf("city%d", i%10), Entry{
+			Kind:   "item",
+			ID:     fmt.Sprint(i),
+			Text:   fmt.Sprintf("Пицца номер %d с сыром", i),
+			Weight: float64(i % 100),
+		})
+	}
+	ix := builder.Build()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ix.Lookup("city3", "item", "пицца ном", 10)
+	}
+}
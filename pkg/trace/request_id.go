@@ -23,3 +23,34 @@ func GetRequestID(ctx context.Context) string {
 func SetRequestID(ctx context.Context, reqID string) context.Context {
 	return context.WithValue(ctx, RequestIDKey, reqID)
 }
+
+const (
+	ClientIPKey  ctxKey = "client_ip"
+	UserAgentKey ctxKey = "user_agent"
+)
+
+// SetClientInfo добавляет в контекст IP-адрес и User-Agent клиента
+func SetClientInfo(ctx context.Context, ip, userAgent string) context.Context {
+	ctx = context.WithValue(ctx, ClientIPKey, ip)
+	return context.WithValue(ctx, UserAgentKey, userAgent)
+}
+
+// GetClientIP извлечение IP-адреса клиента из контекста
+func GetClientIP(ctx context.Context) string {
+	return stringValue(ctx, ClientIPKey)
+}
+
+// GetUserAgent извлечение User-Agent клиента из контекста
+func GetUserAgent(ctx context.Context) string {
+	return stringValue(ctx, UserAgentKey)
+}
+
+func stringValue(ctx context.Context, key ctxKey) string {
+	if ctx == nil {
+		return ""
+	}
+	if s, ok := ctx.Value(key).(string); ok {
+		return s
+	}
+	return ""
+}
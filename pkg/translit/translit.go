@@ -0,0 +1,131 @@
+// Package translit переводит поисковые запросы между латиницей и кириллицей,
+// чтобы "pizza" находила "Пицца", а "пицца" — "Pizza Heart". Правила
+// рассчитаны на поиск, а не на обратимую транслитерацию: они передают
+// звучание названий блюд и брендов.
+package translit
+
+import "strings"
+
+// латинские сочетания проверяются раньше одиночных букв, длинные раньше
+// коротких
+var latinDigraphs = []struct {
+	from, to string
+}{
+	{"shch", "щ"},
+	{"sch", "щ"},
+	{"sh", "ш"},
+	{"ch", "ч"},
+	{"zh", "ж"},
+	{"kh", "х"},
+	{"ts", "ц"},
+	{"tz", "ц"},
+	// итальянское zz: pizza — пицца
+	{"zz", "цц"},
+	{"ph", "ф"},
+	{"th", "т"},
+	{"ck", "к"},
+	{"yo", "ё"},
+	{"yu", "ю"},
+	{"ya", "я"},
+	{"ye", "е"},
+}
+
+var latinLetters = map[rune]string{
+	'a': "а", 'b': "б", 'c': "к", 'd': "д", 'e': "е", 'f': "ф", 'g': "г",
+	'h': "х", 'i': "и", 'j': "дж", 'k': "к", 'l': "л", 'm': "м", 'n': "н",
+	'o': "о", 'p': "п", 'q': "к", 'r': "р", 's': "с", 't': "т", 'u': "у",
+	'v': "в", 'w': "в", 'x': "кс", 'y': "и", 'z': "з",
+}
+
+var cyrillicLetters = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e",
+	'ж': "zh", 'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m",
+	'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u",
+	'ф': "f", 'х': "h", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "",
+	'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+}
+
+// ToCyrillic переводит латинские буквы s в кириллицу. Остальные символы
+// не меняются, результат в нижнем регистре.
+func ToCyrillic(s string) string {
+	runes := []rune(strings.ToLower(s))
+	var b strings.Builder
+	b.Grow(len(s) * 2)
+
+next:
+	for i := 0; i < len(runes); {
+		for _, d := range latinDigraphs {
+			if hasPrefix(runes[i:], d.from) {
+				b.WriteString(d.to)
+				i += len(d.from)
+				continue next
+			}
+		}
+		r := runes[i]
+		// c перед e, i, y читается как ц: cezar, citrus
+		if r == 'c' && i+1 < len(runes) && strings.ContainsRune("eiy", runes[i+1]) {
+			b.WriteString("ц")
+		} else if to, ok := latinLetters[r]; ok {
+			b.WriteString(to)
+		} else {
+			b.WriteRune(r)
+		}
+		i++
+	}
+	return b.String()
+}
+
+// hasPrefix сравнивает начало runes с ASCII-строкой prefix
+func hasPrefix(runes []rune, prefix string) bool {
+	if len(runes) < len(prefix) {
+		return false
+	}
+	for i := 0; i < len(prefix); i++ {
+		if runes[i] != rune(prefix[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// ToLatin переводит кириллические буквы s в латиницу. Остальные символы не
+// меняются, результат в нижнем регистре.
+func ToLatin(s string) string {
+	runes := []rune(strings.ToLower(s))
+	var b strings.Builder
+	b.Grow(len(s))
+
+	for i, r := range runes {
+		// цц — обычно zz в заимствованиях: пицца — pizza
+		if r == 'ц' && (i+1 < len(runes) && runes[i+1] == 'ц' || i > 0 && runes[i-1] == 'ц') {
+			b.WriteByte('z')
+			continue
+		}
+		if to, ok := cyrillicLetters[r]; ok {
+			b.WriteString(to)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// Variants возвращает запрос и его транслитерации без повторов, исходный
+// вариант первым
+func Variants(query string) []string {
+	query = strings.ToLower(strings.TrimSpace(query))
+	out := []string{query}
+	for _, v := range []string{ToCyrillic(query), ToLatin(query)} {
+		dup := false
+		for _, o := range out {
+			if o == v {
+				dup = true
+				break
+			}
+		}
+		if !dup {
+			out = append(out, v)
+		}
+	}
+	return out
+}
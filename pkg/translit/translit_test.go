@@ -0,0 +1,50 @@
+package translit
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestToCyrillic(t *testing.T) {
+	cases := map[string]string{
+		"pizza":        "пицца",
+		"Sushi":        "суши",
+		"burger":       "бургер",
+		"shawarma":     "шаварма",
+		"cezar":        "цезар",
+		"pizza 4 сыра": "пицца 4 сыра",
+	}
+	for in, want := range cases {
+		if got := ToCyrillic(in); got != want {
+			t.Errorf("ToCyrillic(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestToLatin(t *testing.T) {
+	cases := map[string]string{
+		"Пицца":       "pizza",
+		"пиццерия":    "pizzeriya",
+		"шашлык":      "shashlyk",
+		"хачапури":    "hachapuri",
+		"царь":        "tsar",
+		"burger кинг": "burger king",
+	}
+	for in, want := range cases {
+		if got := ToLatin(in); got != want {
+			t.Errorf("ToLatin(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestVariants(t *testing.T) {
+	if got := Variants("  Pizza "); !slices.Equal(got, []string{"pizza", "пицца"}) {
+		t.Fatalf("unexpected variants for latin query: %q", got)
+	}
+	if got := Variants("пицца"); !slices.Equal(got, []string{"пицца", "pizza"}) {
+		t.Fatalf("unexpected variants for cyrillic query: %q", got)
+	}
+	if got := Variants("42"); !slices.Equal(got, []string{"42"}) {
+		t.Fatalf("unexpected variants without letters: %q", got)
+	}
+}
@@ -0,0 +1,224 @@
+// Package userdata читает данные пользователя из таблиц, которыми владеют
+// другие сервисы: корзину и отзывы store_service, заказы и платежи
+// order_service, журнал входов auth_service. Запросы к чужим таблицам
+// живут здесь, а не в читающем сервисе, чтобы при изменении схемы их
+// правили вместе с остальным общим SQL, как pkg/guestcart.
+package userdata
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Reader отдает данные пользователя по разделам
+type Reader interface {
+	Cart(ctx context.Context, userID string) ([]CartItem, error)
+	Orders(ctx context.Context, userID string) ([]Order, error)
+	Payments(ctx context.Context, userID string) ([]Payment, error)
+	Reviews(ctx context.Context, userID string) ([]Review, error)
+	AuthEvents(ctx context.Context, userID string) ([]AuthEvent, error)
+}
+
+type querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+type CartItem struct {
+	StoreItemID string
+	Name        string
+	Price       float64
+	Quantity    int
+	AddedAt     time.Time
+}
+
+type Order struct {
+	ID        string
+	Status    string
+	Total     float64
+	CreatedAt time.Time
+	Items     []OrderItem
+}
+
+type OrderItem struct {
+	StoreItemID string
+	Name        string
+	Price       float64
+	Quantity    int
+}
+
+type Payment struct {
+	ID          string
+	OrderID     string
+	Status      string
+	Amount      float64
+	Currency    string
+	Description *string
+	CreatedAt   time.Time
+}
+
+type Review struct {
+	ID        string
+	StoreID   string
+	StoreName string
+	Rating    float64
+	Comment   *string
+	CreatedAt time.Time
+}
+
+type AuthEvent struct {
+	Type       string
+	Method     *string
+	Identifier *string
+	IP         *string
+	UserAgent  *string
+	CreatedAt  time.Time
+}
+
+const (
+	cartSQL = `
+SELECT si.id, it.name, si.price, ci.quantity, ci.created_at
+FROM cart c
+JOIN cart_item ci ON ci.cart_id = c.id
+JOIN store_item si ON si.id = ci.store_item_id
+JOIN item it ON it.id = si.item_id
+WHERE c.user_id = $1
+ORDER BY ci.created_at`
+
+	// заказ без позиций тоже попадает в выдачу
+	ordersSQL = `
+SELECT o.id, o.status::text, coalesce(o.total_price, 0), o.created_at,
+       si.id, i.name, oi.price, oi.quantity
+FROM orders o
+LEFT JOIN order_item oi ON oi.order_id = o.id
+LEFT JOIN store_item si ON si.id = oi.store_item_id
+LEFT JOIN item i ON i.id = si.item_id
+WHERE o.user_id = $1
+ORDER BY o.created_at, o.id, oi.created_at`
+
+	paymentsSQL = `
+SELECT p.id, p.order_id, p.status::text, p.amount, p.currency, p.description, p.created_at
+FROM payment p
+JOIN orders o ON o.id = p.order_id
+WHERE o.user_id = $1
+ORDER BY p.created_at`
+
+	reviewsSQL = `
+SELECT r.id, r.store_id, s.name, r.rating, r.comment, r.created_at
+FROM review r
+JOIN store s ON s.id = r.store_id
+WHERE r.user_id = $1
+ORDER BY r.created_at`
+
+	authEventsSQL = `
+SELECT type, method, identifier, ip, user_agent, created_at
+FROM auth_event
+WHERE user_id = $1
+ORDER BY created_at, id`
+)
+
+// PostgresReader читает разделы напрямую из общих таблиц. db может быть
+// транзакцией, чтобы все разделы читались в одном снимке базы.
+type PostgresReader struct {
+	db querier
+}
+
+func NewPostgresReader(db querier) *PostgresReader {
+	return &PostgresReader{db: db}
+}
+
+func (r *PostgresReader) Cart(ctx context.Context, userID string) ([]CartItem, error) {
+	rows, err := r.db.Query(ctx, cartSQL, userID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (CartItem, error) {
+		var c CartItem
+		err := row.Scan(&c.StoreItemID, &c.Name, &c.Price, &c.Quantity, &c.AddedAt)
+		return c, err
+	})
+}
+
+// Orders собирает заказы из строк «заказ × позиция»
+func (r *PostgresReader) Orders(ctx context.Context, userID string) ([]Order, error) {
+	rows, err := r.db.Query(ctx, ordersSQL, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var orders []Order
+	for rows.Next() {
+		var (
+			o            Order
+			itemID, name *string
+			price        *float64
+			quantity     *int
+		)
+		if err := rows.Scan(&o.ID, &o.Status, &o.Total, &o.CreatedAt, &itemID, &name, &price, &quantity); err != nil {
+			return nil, err
+		}
+		if n := len(orders); n == 0 || orders[n-1].ID != o.ID {
+			orders = append(orders, o)
+		}
+		if itemID == nil {
+			continue
+		}
+		last := &orders[len(orders)-1]
+		last.Items = append(last.Items, OrderItem{
+			StoreItemID: *itemID,
+			Name:        deref(name),
+			Price:       deref(price),
+			Quantity:    deref(quantity),
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+func (r *PostgresReader) Payments(ctx context.Context, userID string) ([]Payment, error) {
+	rows, err := r.db.Query(ctx, paymentsSQL, userID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (Payment, error) {
+		var p Payment
+		err := row.Scan(&p.ID, &p.OrderID, &p.Status, &p.Amount, &p.Currency, &p.Description, &p.CreatedAt)
+		return p, err
+	})
+}
+
+func (r *PostgresReader) Reviews(ctx context.Context, userID string) ([]Review, error) {
+	rows, err := r.db.Query(ctx, reviewsSQL, userID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (Review, error) {
+		var rv Review
+		err := row.Scan(&rv.ID, &rv.StoreID, &rv.StoreName, &rv.Rating, &rv.Comment, &rv.CreatedAt)
+		return rv, err
+	})
+}
+
+func (r *PostgresReader) AuthEvents(ctx context.Context, userID string) ([]AuthEvent, error) {
+	rows, err := r.db.Query(ctx, authEventsSQL, userID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (AuthEvent, error) {
+		var e AuthEvent
+		err := row.Scan(&e.Type, &e.Method, &e.Identifier, &e.IP, &e.UserAgent, &e.CreatedAt)
+		return e, err
+	})
+}
+
+func deref[T any](p *T) T {
+	var zero T
+	if p == nil {
+		return zero
+	}
+	return *p
+}
@@ -0,0 +1,42 @@
+package userdata
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pashagolub/pgxmock/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgresReader_Orders(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	createdAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	itemID, name, price, quantity := "si1", "Пицца", 500.0, 2
+
+	// первый заказ с двумя позициями, второй без позиций
+	mock.ExpectQuery(`FROM orders o`).
+		WithArgs("u1").
+		WillReturnRows(pgxmock.NewRows([]string{"id", "status", "total", "created_at", "item_id", "name", "price", "quantity"}).
+			AddRow("o1", "paid", 1500.0, createdAt, &itemID, &name, &price, &quantity).
+			AddRow("o1", "paid", 1500.0, createdAt, &itemID, &name, &price, &quantity).
+			AddRow("o2", "created", 0.0, createdAt, nil, nil, nil, nil))
+
+	orders, err := NewPostgresReader(mock).Orders(context.Background(), "u1")
+
+	require.NoError(t, err)
+	require.Equal(t, []Order{
+		{
+			ID: "o1", Status: "paid", Total: 1500, CreatedAt: createdAt,
+			Items: []OrderItem{
+				{StoreItemID: itemID, Name: name, Price: price, Quantity: quantity},
+				{StoreItemID: itemID, Name: name, Price: price, Quantity: quantity},
+			},
+		},
+		{ID: "o2", Status: "created", CreatedAt: createdAt},
+	}, orders)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -1,10 +1,17 @@
 package cmd
 
 import (
+	"apple_backend/pkg/csrf"
+	"apple_backend/pkg/introspect"
+	"apple_backend/pkg/jwtkeys"
 	"apple_backend/pkg/logger"
+	"apple_backend/pkg/session"
+	"apple_backend/pkg/signedurl"
 	"apple_backend/profile_service/internal/config"
 	phttp "apple_backend/profile_service/internal/delivery/http"
 	"apple_backend/profile_service/internal/delivery/middlewares"
+	"apple_backend/profile_service/internal/repository"
+	"apple_backend/profile_service/internal/usecase"
 	"context"
 	"fmt"
 	"log"
@@ -12,10 +19,39 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// purgeDeletedAccounts раз в час обезличивает аккаунты, срок на отмену
+// удаления которых истек
+func purgeDeletedAccounts(purger *usecase.AccountPurger) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+	for range ticker.C {
+		if _, err := purger.PurgeDeleted(context.Background()); err != nil {
+			log.Println("deleted accounts purge failed:", err)
+		}
+	}
+}
+
+// runExports собирает запрошенные выгрузки персональных данных и удаляет
+// просроченные архивы
+func runExports(uc *usecase.ExportUsecase) {
+	ticker := time.NewTicker(30 * time.Second)
+	defer ticker.Stop()
+	for range ticker.C {
+		ctx := context.Background()
+		if _, err := uc.RunPending(ctx); err != nil {
+			log.Println("data exports run failed:", err)
+		}
+		if err := uc.CleanupExpired(ctx); err != nil {
+			log.Println("data exports cleanup failed:", err)
+		}
+	}
+}
+
 func Run() {
 	conf := config.LoadConfig()
 
@@ -25,6 +61,14 @@ func Run() {
 	}
 	defer dbPool.Close()
 
+	profileRepo := repository.NewProfileRepoPostgres(dbPool)
+	grace := time.Duration(conf.DeletionGraceDays) * 24 * time.Hour
+	go purgeDeletedAccounts(usecase.NewAccountPurger(profileRepo, conf.UploadPath, conf.ExportPath, grace))
+
+	exportUC := usecase.NewExportUsecase(profileRepo, signedurl.New(conf.ExportSecret), conf.ExportPath, conf.BaseURL, "/api/v0")
+	exportHandler := phttp.NewExportHandler(exportUC, "/api/v0")
+	go runExports(exportUC)
+
 	mux := http.NewServeMux()
 
 	// статика для аватарок
@@ -61,19 +105,21 @@ func Run() {
 		http.ServeFile(w, r, fullPath)
 	})
 
+	// архив отдается по подписанной ссылке без авторизации
+	mux.HandleFunc("/api/v0/exports/", exportHandler.DownloadExport)
+
 	protectedMux := http.NewServeMux()
-	phttp.NewProfileRouter(protectedMux, dbPool, "/api/v0", conf.UploadPath, conf.BaseURL)
+	tokens := introspect.NewClient(conf.AuthURL, conf.IntrospectSecret, introspect.DefaultTTL)
+	phttp.NewProfileRouter(protectedMux, dbPool, "/api/v0", conf.UploadPath, conf.BaseURL, tokens, exportHandler)
 
-	jwtSecret := conf.JWTSecret
-	protectedHandler := middlewares.AuthMiddleware(protectedMux, jwtSecret)
+	keys := jwtkeys.NewRemoteKeySet(conf.JWKSURL, 0)
+	protectedHandler := middlewares.AuthMiddleware(protectedMux, keys.Keyfunc, session.NewPostgresChecker(dbPool))
 	mux.Handle("/api/v0/", protectedHandler)
 
 	handler := middlewares.AccessLog(
 		logger.Global(),
 		middlewares.CorsMiddleware(
-			middlewares.CSRFTokenMiddleware(
-				middlewares.CSRFMiddleware(mux),
-			),
+			csrf.New(conf.CSRFSecret, csrf.OptionsFromEnv()).Middleware(mux),
 		),
 	)
 
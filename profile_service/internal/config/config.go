@@ -3,6 +3,8 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 )
 
 type Config struct {
@@ -13,9 +15,26 @@ type Config struct {
 	DBName     string
 	AppHost    string
 	AppPort    string
-	JWTSecret  string
+	JWKSURL    string
 	UploadPath string
 	BaseURL    string
+
+	// AuthURL и IntrospectSecret — для проверки токена в auth_service
+	// перед удалением профиля; без секрета сервис не запускается
+	AuthURL          string
+	IntrospectSecret string
+
+	// CSRFSecret — общий для всех сервисов ключ подписи CSRF-токенов
+	CSRFSecret string
+
+	// DeletionGraceDays — сколько дней удаленный аккаунт можно восстановить,
+	// прежде чем он будет обезличен
+	DeletionGraceDays int
+
+	// ExportPath — каталог с архивами выгрузок персональных данных,
+	// ExportSecret — ключ подписи ссылок на их скачивание
+	ExportPath   string
+	ExportSecret string
 }
 
 func LoadConfig() *Config {
@@ -53,6 +72,12 @@ func LoadConfig() *Config {
 		uploadPath = "/app/avatars"
 	}
 
+	// без срока на отмену удаленные аккаунты обезличивались бы сразу
+	graceDays := parseInt(getEnv("ACCOUNT_DELETION_GRACE_DAYS", "30"))
+	if graceDays <= 0 {
+		graceDays = 30
+	}
+
 	return &Config{
 		DBUser:     getEnv("DB_USER", "postgres"),
 		DBPassword: getEnv("DB_PASSWORD", "postgres"),
@@ -61,9 +86,19 @@ func LoadConfig() *Config {
 		DBName:     getEnv("DB_NAME", "postgres"),
 		AppHost:    appHost,
 		AppPort:    appPort,
-		JWTSecret:  os.Getenv("SECRET_KEY"),
+		JWKSURL:    getEnv("JWKS_URL", "http://auth_service:8082/.well-known/jwks.json"),
 		UploadPath: uploadPath,
 		BaseURL:    baseURL,
+
+		AuthURL:          getEnv("AUTH_URL", "http://auth_service:8082"),
+		IntrospectSecret: mustEnv("INTROSPECT_SECRET"),
+
+		CSRFSecret: mustEnv("CSRF_SECRET"),
+
+		DeletionGraceDays: graceDays,
+
+		ExportPath:   getEnv("EXPORT_DIR", "/app/exports"),
+		ExportSecret: mustEnv("EXPORT_SIGNING_SECRET"),
 	}
 }
 
@@ -80,3 +115,21 @@ func getEnv(key, fallback string) string {
 	}
 	return fallback
 }
+
+// mustEnv читает обязательную переменную: секрет не должен молча
+// подменяться значением по умолчанию
+func mustEnv(key string) string {
+	value := os.Getenv(key)
+	if value == "" {
+		panic(fmt.Sprintf("Некорректно заполнен файл .env: не задан %s", key))
+	}
+	return value
+}
+
+func parseInt(v string) int {
+	n, err := strconv.Atoi(strings.TrimSpace(v))
+	if err != nil {
+		return 0
+	}
+	return n
+}
@@ -0,0 +1,199 @@
+package http
+
+import (
+	"apple_backend/pkg/http_response"
+	"apple_backend/pkg/logger"
+	"apple_backend/profile_service/internal/delivery/middlewares"
+	"apple_backend/profile_service/internal/delivery/transport"
+	"apple_backend/profile_service/internal/domain"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+type ExportUsecaseInterface interface {
+	RequestExport(ctx context.Context, userID string) (*domain.DataExport, error)
+	GetExport(ctx context.Context, userID string) (*domain.DataExport, string, time.Time, error)
+	OpenExport(ctx context.Context, id string, link *url.URL) (string, error)
+}
+
+type ExportHandler struct {
+	uc          ExportUsecaseInterface
+	rs          *http_response.ResponseSender
+	exportsPath string
+}
+
+func NewExportHandler(uc ExportUsecaseInterface, apiPrefix string) *ExportHandler {
+	return &ExportHandler{
+		uc:          uc,
+		rs:          http_response.NewResponseSender(logger.Global()),
+		exportsPath: strings.TrimRight(apiPrefix, "/") + "/exports/",
+	}
+}
+
+// HandleExport обрабатывает /profiles/me/export: POST ставит выгрузку в
+// очередь, GET возвращает ее статус и ссылку на архив
+func (h *ExportHandler) HandleExport(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+
+	sub, ok := middlewares.UserIDFromContext(ctx)
+	if !ok || sub == "" {
+		log.WarnContext(ctx, "handler HandleExport unauthorized - no user in context")
+		h.rs.Error(ctx, w, http.StatusUnauthorized, "HandleExport", domain.ErrUnauthorized, nil)
+		return
+	}
+	// выгрузить можно только свои данные
+	path := strings.TrimSuffix(strings.TrimRight(r.URL.Path, "/"), "/export")
+	if id := path[strings.LastIndexByte(path, '/')+1:]; id != "me" && id != sub {
+		log.WarnContext(ctx, "handler HandleExport forbidden",
+			slog.String("subject", sub),
+			slog.String("target", id))
+		h.rs.Error(ctx, w, http.StatusForbidden, "HandleExport", domain.ErrForbidden, nil)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		h.RequestExport(w, r, sub)
+	case http.MethodGet:
+		h.GetExport(w, r, sub)
+	default:
+		log.WarnContext(ctx, "handler HandleExport method not allowed", slog.String("method", r.Method))
+		h.rs.Error(ctx, w, http.StatusMethodNotAllowed, "HandleExport", domain.ErrHTTPMethod, nil)
+	}
+}
+
+// RequestExport godoc
+// @Summary Запросить выгрузку персональных данных
+// @Description Ставит в очередь сборку ZIP-архива со всеми данными пользователя. Если выгрузка уже готовится, возвращает ее.
+// @Tags profiles
+// @Produce json
+// @Success 202 {object} transport.DataExportResponse
+// @Failure 401 {object} http_response.ErrResponse "Не авторизован"
+// @Failure 429 {object} http_response.ErrResponse "Выгрузка запрашивается слишком часто"
+// @Failure 500 {object} http_response.ErrResponse "Внутренняя ошибка сервера"
+// @Router /profiles/me/export [post]
+func (h *ExportHandler) RequestExport(w http.ResponseWriter, r *http.Request, userID string) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "handler RequestExport start", slog.String("user_id", userID))
+
+	e, err := h.uc.RequestExport(ctx, userID)
+	if err != nil {
+		log.ErrorContext(ctx, "handler RequestExport usecase failed",
+			slog.Any("err", err),
+			slog.String("user_id", userID))
+		switch {
+		case errors.Is(err, domain.ErrInvalidProfileData):
+			h.rs.Error(ctx, w, http.StatusBadRequest, "RequestExport", err, nil)
+		case errors.Is(err, domain.ErrExportTooFrequent):
+			h.rs.Error(ctx, w, http.StatusTooManyRequests, "RequestExport", err, nil)
+		default:
+			h.rs.Error(ctx, w, http.StatusInternalServerError, "RequestExport", domain.ErrInternalServer, err)
+		}
+		return
+	}
+
+	log.InfoContext(ctx, "handler RequestExport success",
+		slog.String("user_id", userID),
+		slog.String("export_id", e.ID),
+		slog.String("status", e.Status))
+	h.rs.Send(ctx, w, http.StatusAccepted, transport.ToDataExportResponse(e, "", time.Time{}))
+}
+
+// GetExport godoc
+// @Summary Статус выгрузки персональных данных
+// @Description Возвращает последнюю выгрузку. У готовой выгрузки есть подписанная ссылка на архив с ограниченным сроком действия.
+// @Tags profiles
+// @Produce json
+// @Success 200 {object} transport.DataExportResponse
+// @Failure 401 {object} http_response.ErrResponse "Не авторизован"
+// @Failure 404 {object} http_response.ErrResponse "Выгрузок не было"
+// @Failure 500 {object} http_response.ErrResponse "Внутренняя ошибка сервера"
+// @Router /profiles/me/export [get]
+func (h *ExportHandler) GetExport(w http.ResponseWriter, r *http.Request, userID string) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "handler GetExport start", slog.String("user_id", userID))
+
+	e, link, linkExpires, err := h.uc.GetExport(ctx, userID)
+	if err != nil {
+		log.ErrorContext(ctx, "handler GetExport usecase failed",
+			slog.Any("err", err),
+			slog.String("user_id", userID))
+		switch {
+		case errors.Is(err, domain.ErrInvalidProfileData):
+			h.rs.Error(ctx, w, http.StatusBadRequest, "GetExport", err, nil)
+		case errors.Is(err, domain.ErrExportNotFound):
+			h.rs.Error(ctx, w, http.StatusNotFound, "GetExport", err, nil)
+		default:
+			h.rs.Error(ctx, w, http.StatusInternalServerError, "GetExport", domain.ErrInternalServer, err)
+		}
+		return
+	}
+
+	// в ответе подписанная ссылка, кэшировать его нельзя
+	w.Header().Set("Cache-Control", "no-store")
+	h.rs.Send(ctx, w, http.StatusOK, transport.ToDataExportResponse(e, link, linkExpires))
+}
+
+// DownloadExport godoc
+// @Summary Скачать архив с персональными данными
+// @Description Доступ только по подписанной ссылке из GET /profiles/me/export, авторизация не требуется
+// @Tags profiles
+// @Produce application/zip
+// @Param id path string true "ID выгрузки"
+// @Param expires query int true "Срок действия ссылки, unix-время"
+// @Param sig query string true "Подпись ссылки"
+// @Success 200 {file} file
+// @Failure 403 {object} http_response.ErrResponse "Ссылка недействительна или устарела"
+// @Failure 405 {object} http_response.ErrResponse "Неверный HTTP-метод"
+// @Failure 500 {object} http_response.ErrResponse "Внутренняя ошибка сервера"
+// @Router /exports/{id}/download [get]
+func (h *ExportHandler) DownloadExport(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "handler DownloadExport start")
+
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		log.WarnContext(ctx, "handler DownloadExport method not allowed", slog.String("method", r.Method))
+		h.rs.Error(ctx, w, http.StatusMethodNotAllowed, "DownloadExport", domain.ErrHTTPMethod, nil)
+		return
+	}
+
+	id, _, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, h.exportsPath), "/")
+	path, err := h.uc.OpenExport(ctx, id, r.URL)
+	if err != nil {
+		log.WarnContext(ctx, "handler DownloadExport rejected", slog.Any("err", err), slog.String("export_id", id))
+		if errors.Is(err, domain.ErrExportLinkInvalid) {
+			h.rs.Error(ctx, w, http.StatusForbidden, "DownloadExport", err, nil)
+			return
+		}
+		h.rs.Error(ctx, w, http.StatusInternalServerError, "DownloadExport", domain.ErrInternalServer, err)
+		return
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		h.rs.Error(ctx, w, http.StatusInternalServerError, "DownloadExport", domain.ErrInternalServer, err)
+		return
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		h.rs.Error(ctx, w, http.StatusInternalServerError, "DownloadExport", domain.ErrInternalServer, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", `attachment; filename="personal-data.zip"`)
+	w.Header().Set("Cache-Control", "no-store")
+	http.ServeContent(w, r, "", info.ModTime(), f)
+	log.InfoContext(ctx, "handler DownloadExport success", slog.String("export_id", id))
+}
@@ -2,6 +2,7 @@ package http
 
 import (
 	"apple_backend/pkg/http_response"
+	"apple_backend/pkg/introspect"
 	"apple_backend/pkg/logger"
 	"apple_backend/profile_service/internal/delivery/middlewares"
 	"apple_backend/profile_service/internal/delivery/transport"
@@ -88,6 +89,8 @@ func NewProfileRouter(
 	apiPrefix string,
 	uploadPath string,
 	baseURL string,
+	tokens *introspect.Client,
+	exports *ExportHandler,
 ) {
 	profileRepo := repository.NewProfileRepoPostgres(db)
 	profileUC := usecase.NewProfileUsecase(profileRepo)
@@ -96,6 +99,19 @@ func NewProfileRouter(
 	profileHandler := NewProfileHandler(profileUC, apiPrefix)
 	avatarHandler := NewAvatarHandler(avatarUC)
 
+	// удаление отзывает все сессии и ключи и запускает срок до обезличивания,
+	// поэтому токен дополнительно сверяется с auth_service: вдруг сессию уже
+	// отозвали
+	routes := http.HandlerFunc(profileHandler.handleProfileRoutes)
+	checked := tokens.RequireActive(middlewares.JwtCookieName, routes)
+	profileRoutes := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			checked.ServeHTTP(w, r)
+			return
+		}
+		routes.ServeHTTP(w, r)
+	})
+
 	mux.Handle(apiPrefix+"/profiles/",
 		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			path := strings.TrimRight(r.URL.Path, "/")
@@ -103,7 +119,11 @@ func NewProfileRouter(
 				avatarHandler.UploadAvatar(w, r)
 				return
 			}
-			profileHandler.handleProfileRoutes(w, r)
+			if strings.HasSuffix(path, "/export") {
+				exports.HandleExport(w, r)
+				return
+			}
+			profileRoutes.ServeHTTP(w, r)
 		}),
 	)
 
@@ -264,6 +284,8 @@ func (h *ProfileHandler) UpdateProfile(w http.ResponseWriter, r *http.Request, i
 		Phone:   req.Phone,
 		CityID:  req.CityID,
 		Address: req.Address,
+		Lat:     req.Lat,
+		Lon:     req.Lon,
 	}
 
 	err := h.uc.UpdateProfile(ctx, profile)
@@ -290,7 +312,9 @@ func (h *ProfileHandler) UpdateProfile(w http.ResponseWriter, r *http.Request, i
 
 // DeleteProfile godoc
 // @Summary Удалить профиль
-// @Description Удаляет профиль пользователя по UUID
+// @Description Помечает аккаунт удаленным и завершает все его сессии. Заказы сохраняются,
+// @Description а личные данные обезличиваются по истечении срока, в течение которого удаление можно отменить.
+// @Description Сессий у удаленного аккаунта нет, поэтому отмена — вход по паролю через POST /auth/restore
 // @Tags profiles
 // @Accept json
 // @Produce json
@@ -72,66 +72,6 @@ func AccessLog(baseLogger logger.Logger, next http.Handler) http.Handler {
 	})
 }
 
-func CSRFMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions {
-			next.ServeHTTP(w, r)
-			return
-		}
-
-		headerToken := r.Header.Get("X-CSRF-Token")
-		if headerToken == "" {
-			http.Error(w, "CSRF token required", http.StatusForbidden)
-			return
-		}
-
-		cookieToken, err := r.Cookie("csrf_token")
-		if err != nil || cookieToken.Value == "" {
-			http.Error(w, "CSRF cookie missing", http.StatusForbidden)
-			return
-		}
-
-		if headerToken != cookieToken.Value {
-			http.Error(w, "CSRF token mismatch", http.StatusForbidden)
-			return
-		}
-
-		next.ServeHTTP(w, r)
-	})
-}
-
-func CSRFTokenMiddleware(next http.Handler) http.Handler {
-	secure := strings.EqualFold(os.Getenv("COOKIE_SECURE"), "true")
-	sameSite := http.SameSiteLaxMode
-	switch strings.ToLower(os.Getenv("COOKIE_SAMESITE")) {
-	case "strict":
-		sameSite = http.SameSiteStrictMode
-	case "none":
-		sameSite = http.SameSiteNoneMode
-		if !secure {
-			secure = true
-		}
-	}
-
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// csrf_token — для Double Submit (HttpOnly=false)
-		if _, err := r.Cookie("csrf_token"); err != nil {
-			csrfToken := uuid.NewString()
-			http.SetCookie(w, &http.Cookie{
-				Name:     "csrf_token",
-				Value:    csrfToken,
-				Path:     "/",
-				HttpOnly: false,
-				Secure:   secure,
-				SameSite: sameSite,
-				MaxAge:   86400,
-			})
-		}
-
-		next.ServeHTTP(w, r)
-	})
-}
-
 func CorsMiddleware(next http.Handler) http.Handler {
 	origins := os.Getenv("ALLOWED_ORIGINS")
 	if origins == "" {
@@ -147,6 +87,7 @@ func CorsMiddleware(next http.Handler) http.Handler {
 			w.Header().Set("Vary", "Origin")
 			w.Header().Set("Access-Control-Allow-Credentials", "true")
 			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-CSRF-Token")
+			w.Header().Set("Access-Control-Expose-Headers", "X-CSRF-Token")
 		}
 
 		if r.Method == http.MethodOptions {
@@ -0,0 +1,169 @@
+package transport
+
+import (
+	"apple_backend/profile_service/internal/domain"
+	"time"
+)
+
+type DataExportResponse struct {
+	ID         string     `json:"id"`
+	Status     string     `json:"status"`
+	CreatedAt  time.Time  `json:"created_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	// DownloadURL — подписанная ссылка на архив, есть только у готовой выгрузки
+	DownloadURL       string     `json:"download_url,omitempty"`
+	DownloadExpiresAt *time.Time `json:"download_expires_at,omitempty"`
+} // @name DataExportResponse
+
+func ToDataExportResponse(e *domain.DataExport, link string, linkExpires time.Time) *DataExportResponse {
+	resp := &DataExportResponse{
+		ID:         e.ID,
+		Status:     e.Status,
+		CreatedAt:  e.CreatedAt,
+		FinishedAt: e.FinishedAt,
+		ExpiresAt:  e.ExpiresAt,
+	}
+	if link != "" {
+		resp.DownloadURL = link
+		resp.DownloadExpiresAt = &linkExpires
+	}
+	return resp
+}
+
+// Файлы архива выгрузки. Имена полей стабильны: пользователь может
+// разбирать архив программно.
+
+type ExportAccountFile struct {
+	ID        string     `json:"id"`
+	Email     string     `json:"email,omitempty"`
+	Name      *string    `json:"name,omitempty"`
+	Phone     *string    `json:"phone,omitempty"`
+	AvatarURL *string    `json:"avatar_url,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+type ExportAddress struct {
+	CityID  *string  `json:"city_id,omitempty"`
+	City    *string  `json:"city,omitempty"`
+	Address *string  `json:"address,omitempty"`
+	Lat     *float64 `json:"lat,omitempty"`
+	Lon     *float64 `json:"lon,omitempty"`
+}
+
+type ExportCartItem struct {
+	StoreItemID string    `json:"store_item_id"`
+	Name        string    `json:"name"`
+	Price       float64   `json:"price"`
+	Quantity    int       `json:"quantity"`
+	AddedAt     time.Time `json:"added_at"`
+}
+
+type ExportOrder struct {
+	ID        string            `json:"id"`
+	Status    string            `json:"status"`
+	Total     float64           `json:"total"`
+	CreatedAt time.Time         `json:"created_at"`
+	Items     []ExportOrderItem `json:"items"`
+}
+
+type ExportOrderItem struct {
+	StoreItemID string  `json:"store_item_id"`
+	Name        string  `json:"name"`
+	Price       float64 `json:"price"`
+	Quantity    int     `json:"quantity"`
+}
+
+type ExportPayment struct {
+	ID          string    `json:"id"`
+	OrderID     string    `json:"order_id"`
+	Status      string    `json:"status"`
+	Amount      float64   `json:"amount"`
+	Currency    string    `json:"currency"`
+	Description *string   `json:"description,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type ExportReview struct {
+	ID        string    `json:"id"`
+	StoreID   string    `json:"store_id"`
+	StoreName string    `json:"store_name"`
+	Rating    float64   `json:"rating"`
+	Comment   *string   `json:"comment,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ExportFriend struct {
+	UserID    string    `json:"user_id"`
+	Name      *string   `json:"name,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ExportAuthEvent struct {
+	Type       string    `json:"type"`
+	Method     *string   `json:"method,omitempty"`
+	Identifier *string   `json:"identifier,omitempty"`
+	IP         *string   `json:"ip,omitempty"`
+	UserAgent  *string   `json:"user_agent,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// ToExportFiles раскладывает данные пользователя по файлам архива
+func ToExportFiles(d *domain.PersonalData) map[string]any {
+	a := d.Account
+	addresses := []ExportAddress{}
+	if a.Address != nil || a.CityID != nil {
+		addresses = append(addresses, ExportAddress{CityID: a.CityID, City: d.City, Address: a.Address, Lat: a.Lat, Lon: a.Lon})
+	}
+
+	cart := make([]ExportCartItem, 0, len(d.Cart))
+	for _, c := range d.Cart {
+		cart = append(cart, ExportCartItem(c))
+	}
+	orders := make([]ExportOrder, 0, len(d.Orders))
+	for _, o := range d.Orders {
+		items := make([]ExportOrderItem, 0, len(o.Items))
+		for _, it := range o.Items {
+			items = append(items, ExportOrderItem(it))
+		}
+		orders = append(orders, ExportOrder{ID: o.ID, Status: o.Status, Total: o.Total, CreatedAt: o.CreatedAt, Items: items})
+	}
+	payments := make([]ExportPayment, 0, len(d.Payments))
+	for _, p := range d.Payments {
+		payments = append(payments, ExportPayment(p))
+	}
+	reviews := make([]ExportReview, 0, len(d.Reviews))
+	for _, r := range d.Reviews {
+		reviews = append(reviews, ExportReview(r))
+	}
+	friends := make([]ExportFriend, 0, len(d.Friends))
+	for _, f := range d.Friends {
+		friends = append(friends, ExportFriend(f))
+	}
+	events := make([]ExportAuthEvent, 0, len(d.AuthEvents))
+	for _, e := range d.AuthEvents {
+		events = append(events, ExportAuthEvent(e))
+	}
+
+	return map[string]any{
+		"account.json": ExportAccountFile{
+			ID:        a.ID,
+			Email:     a.Email,
+			Name:      a.Name,
+			Phone:     a.Phone,
+			AvatarURL: a.AvatarURL,
+			CreatedAt: a.CreatedAt,
+			UpdatedAt: a.UpdatedAt,
+			DeletedAt: a.DeletedAt,
+		},
+		"addresses.json":   addresses,
+		"cart.json":        cart,
+		"orders.json":      orders,
+		"payments.json":    payments,
+		"reviews.json":     reviews,
+		"friends.json":     friends,
+		"auth_events.json": events,
+	}
+}
@@ -10,15 +10,19 @@ type CreateProfileResponse struct {
 } // @name CreateProfileResponse
 
 type ProfileResponse struct {
-	ID        string  `json:"id"`
-	Email     string  `json:"email"`
-	Name      *string `json:"name,omitempty"`
-	Phone     *string `json:"phone,omitempty"`
-	CityID    *string `json:"city_id,omitempty"`
-	Address   *string `json:"address,omitempty"`
-	AvatarURL *string `json:"avatar_url,omitempty"`
-	CreatedAt string  `json:"created_at"`
-	UpdatedAt string  `json:"updated_at"`
+	ID        string   `json:"id"`
+	Email     string   `json:"email"`
+	Name      *string  `json:"name,omitempty"`
+	Phone     *string  `json:"phone,omitempty"`
+	CityID    *string  `json:"city_id,omitempty"`
+	Address   *string  `json:"address,omitempty"`
+	Lat       *float64 `json:"lat,omitempty"`
+	Lon       *float64 `json:"lon,omitempty"`
+	AvatarURL *string  `json:"avatar_url,omitempty"`
+	CreatedAt string   `json:"created_at"`
+	UpdatedAt string   `json:"updated_at"`
+	// DeletedAt — аккаунт удален и будет обезличен, если не восстановить его
+	DeletedAt *string `json:"deleted_at,omitempty"`
 } // @name ProfileResponse
 
 type CreateProfileRequest struct {
@@ -26,12 +30,16 @@ type CreateProfileRequest struct {
 	Password string `json:"password"`
 } // @name CreateProfileRequest
 
+// UpdateProfileRequest — изменяемые поля профиля. lat и lon передаются
+// вместе; новый адрес без них сбрасывает прежние координаты
 type UpdateProfileRequest struct {
-	Name      *string `json:"name,omitempty"`
-	Phone     *string `json:"phone,omitempty"`
-	CityID    *string `json:"city_id,omitempty"`
-	Address   *string `json:"address,omitempty"`
-	AvatarURL *string `json:"avatar_url,omitempty"`
+	Name      *string  `json:"name,omitempty"`
+	Phone     *string  `json:"phone,omitempty"`
+	CityID    *string  `json:"city_id,omitempty"`
+	Address   *string  `json:"address,omitempty"`
+	Lat       *float64 `json:"lat,omitempty"`
+	Lon       *float64 `json:"lon,omitempty"`
+	AvatarURL *string  `json:"avatar_url,omitempty"`
 } // @name UpdateProfileRequest
 
 func ToProfileResponse(p *domain.Profile) *ProfileResponse {
@@ -39,6 +47,12 @@ func ToProfileResponse(p *domain.Profile) *ProfileResponse {
 		return nil
 	}
 
+	var deletedAt *string
+	if p.DeletedAt != nil {
+		s := p.DeletedAt.Format(time.RFC3339)
+		deletedAt = &s
+	}
+
 	return &ProfileResponse{
 		ID:        p.ID,
 		Email:     p.Email,
@@ -46,8 +60,11 @@ func ToProfileResponse(p *domain.Profile) *ProfileResponse {
 		Phone:     p.Phone,
 		CityID:    p.CityID,
 		Address:   p.Address,
+		Lat:       p.Lat,
+		Lon:       p.Lon,
 		AvatarURL: p.AvatarURL,
 		CreatedAt: p.CreatedAt.Format(time.RFC3339),
 		UpdatedAt: p.UpdatedAt.Format(time.RFC3339),
+		DeletedAt: deletedAt,
 	}
 }
@@ -11,6 +11,11 @@ var (
 	ErrProfileExist       = errors.New("профиль уже существует")
 	ErrInvalidProfileData = errors.New("неверные данные профиля")
 
+	ErrExportNotFound    = errors.New("выгрузка не найдена")
+	ErrExportInProgress  = errors.New("выгрузка уже готовится")
+	ErrExportTooFrequent = errors.New("выгрузку можно запрашивать не чаще раза в час")
+	ErrExportLinkInvalid = errors.New("ссылка на выгрузку недействительна или устарела")
+
 	ErrFileTooLarge    = errors.New("слишком большой размер файла")
 	ErrInvalidFileType = errors.New("недопустимый формат файла")
 	ErrUnauthorized    = errors.New("неавторизованный доступ")
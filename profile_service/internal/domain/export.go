@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"apple_backend/pkg/userdata"
+	"time"
+)
+
+const (
+	ExportPending = "pending"
+	ExportRunning = "running"
+	ExportReady   = "ready"
+	ExportFailed  = "failed"
+	ExportExpired = "expired"
+)
+
+// DataExport — запрос пользователя на выгрузку своих данных
+type DataExport struct {
+	ID         string
+	UserID     string
+	Status     string
+	Error      *string
+	StartedAt  *time.Time
+	FinishedAt *time.Time
+	ExpiresAt  *time.Time
+	CreatedAt  time.Time
+}
+
+// Active — выгрузка еще не собрана
+func (e *DataExport) Active() bool {
+	return e.Status == ExportPending || e.Status == ExportRunning
+}
+
+// PersonalData — все, что сервисы хранят о пользователе
+type PersonalData struct {
+	Account    Profile
+	City       *string
+	Cart       []ExportCartItem
+	Orders     []ExportOrder
+	Payments   []ExportPayment
+	Reviews    []ExportReview
+	Friends    []ExportFriend
+	AuthEvents []ExportAuthEvent
+}
+
+// разделы, которые хранят другие сервисы, читает pkg/userdata
+type (
+	ExportCartItem  = userdata.CartItem
+	ExportOrder     = userdata.Order
+	ExportOrderItem = userdata.OrderItem
+	ExportPayment   = userdata.Payment
+	ExportReview    = userdata.Review
+	ExportAuthEvent = userdata.AuthEvent
+)
+
+type ExportFriend struct {
+	UserID    string
+	Name      *string
+	CreatedAt time.Time
+}
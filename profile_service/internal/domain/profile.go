@@ -3,13 +3,26 @@ package domain
 import "time"
 
 type Profile struct {
-	ID        string
-	Email     string
-	Name      *string
-	Phone     *string
-	CityID    *string
-	Address   *string
+	ID      string
+	Email   string
+	Name    *string
+	Phone   *string
+	CityID  *string
+	Address *string
+	// Lat и Lon — координаты адреса, задаются вместе
+	Lat       *float64
+	Lon       *float64
 	AvatarURL *string
 	CreatedAt time.Time
 	UpdatedAt time.Time
+
+	// DeletedAt — когда владелец удалил аккаунт. Пока аккаунт не обезличен,
+	// удаление можно отменить
+	DeletedAt *time.Time
+}
+
+// DeletedAccount — аккаунт, срок отмены удаления которого истек
+type DeletedAccount struct {
+	ID        string
+	AvatarURL *string
 }
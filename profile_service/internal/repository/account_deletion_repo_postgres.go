@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"apple_backend/pkg/logger"
+	"apple_backend/profile_service/internal/domain"
+	"context"
+	_ "embed"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+//go:embed sql/profile/list_due_deletions.sql
+var listDueDeletionsQuery string
+
+//go:embed sql/profile/lock_due_account.sql
+var lockDueAccountQuery string
+
+//go:embed sql/profile/purge_account_data.sql
+var purgeAccountDataQuery string
+
+//go:embed sql/profile/delete_exports.sql
+var deleteExportsQuery string
+
+//go:embed sql/profile/detach_user_records.sql
+var detachUserRecordsQuery string
+
+//go:embed sql/profile/anonymize_account.sql
+var anonymizeAccountQuery string
+
+//go:embed sql/profile/scrub_auth_events.sql
+var scrubAuthEventsQuery string
+
+// ListDueDeletions возвращает аккаунты, удаленные не позже before и еще не
+// обезличенные.
+func (r *ProfileRepoPostgres) ListDueDeletions(ctx context.Context, before time.Time, limit int) ([]*domain.DeletedAccount, error) {
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "repo ListDueDeletions start", slog.Time("before", before))
+
+	rows, err := r.db.Query(ctx, listDueDeletionsQuery, before, limit)
+	if err != nil {
+		log.ErrorContext(ctx, "repo ListDueDeletions db error", slog.Any("err", err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var accounts []*domain.DeletedAccount
+	for rows.Next() {
+		a := &domain.DeletedAccount{}
+		if err := rows.Scan(&a.ID, &a.AvatarURL); err != nil {
+			log.ErrorContext(ctx, "repo ListDueDeletions scan failed", slog.Any("err", err))
+			return nil, err
+		}
+		accounts = append(accounts, a)
+	}
+	if err := rows.Err(); err != nil {
+		log.ErrorContext(ctx, "repo ListDueDeletions rows error", slog.Any("err", err))
+		return nil, err
+	}
+
+	log.InfoContext(ctx, "repo ListDueDeletions success", slog.Int("count", len(accounts)))
+	return accounts, nil
+}
+
+// AnonymizeAccount в одной транзакции удаляет личные данные аккаунта и его
+// выгрузки, отвязывает от него заказы и отзывы, стирает поля профиля и личные
+// поля его событий в журнале авторизации. Возвращает id удаленных выгрузок,
+// чтобы стереть их архивы, и false, если за это время удаление отменили или
+// аккаунт уже обезличен.
+func (r *ProfileRepoPostgres) AnonymizeAccount(ctx context.Context, id string, before time.Time) ([]string, bool, error) {
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "repo AnonymizeAccount start", slog.String("id", id))
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		log.ErrorContext(ctx, "repo AnonymizeAccount begin failed", slog.String("id", id), slog.Any("err", err))
+		return nil, false, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	// блокировка строки не дает параллельно отменить удаление
+	var phone *string
+	err = tx.QueryRow(ctx, lockDueAccountQuery, id, before).Scan(&phone)
+	if errors.Is(err, pgx.ErrNoRows) {
+		log.InfoContext(ctx, "repo AnonymizeAccount skipped", slog.String("id", id))
+		return nil, false, nil
+	}
+	if err != nil {
+		log.ErrorContext(ctx, "repo AnonymizeAccount lock failed", slog.String("id", id), slog.Any("err", err))
+		return nil, false, err
+	}
+
+	if _, err = tx.Exec(ctx, purgeAccountDataQuery, id, phone); err != nil {
+		log.ErrorContext(ctx, "repo AnonymizeAccount purge failed", slog.String("id", id), slog.Any("err", err))
+		return nil, false, err
+	}
+	rows, err := tx.Query(ctx, deleteExportsQuery, id)
+	if err != nil {
+		log.ErrorContext(ctx, "repo AnonymizeAccount delete exports failed", slog.String("id", id), slog.Any("err", err))
+		return nil, false, err
+	}
+	exportIDs, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		log.ErrorContext(ctx, "repo AnonymizeAccount delete exports scan failed", slog.String("id", id), slog.Any("err", err))
+		return nil, false, err
+	}
+	if _, err = tx.Exec(ctx, detachUserRecordsQuery, id); err != nil {
+		log.ErrorContext(ctx, "repo AnonymizeAccount detach failed", slog.String("id", id), slog.Any("err", err))
+		return nil, false, err
+	}
+	if _, err = tx.Exec(ctx, anonymizeAccountQuery, id); err != nil {
+		log.ErrorContext(ctx, "repo AnonymizeAccount update failed", slog.String("id", id), slog.Any("err", err))
+		return nil, false, err
+	}
+	if _, err = tx.Exec(ctx, scrubAuthEventsQuery, id); err != nil {
+		log.ErrorContext(ctx, "repo AnonymizeAccount scrub auth events failed", slog.String("id", id), slog.Any("err", err))
+		return nil, false, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.ErrorContext(ctx, "repo AnonymizeAccount commit failed", slog.String("id", id), slog.Any("err", err))
+		return nil, false, err
+	}
+
+	log.InfoContext(ctx, "repo AnonymizeAccount success", slog.String("id", id), slog.Int("exports", len(exportIDs)))
+	return exportIDs, true, nil
+}
@@ -0,0 +1,268 @@
+package repository
+
+import (
+	"apple_backend/pkg/logger"
+	"apple_backend/pkg/userdata"
+	"apple_backend/profile_service/internal/domain"
+	"context"
+	_ "embed"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+//go:embed sql/export/create_export.sql
+var createExportQuery string
+
+//go:embed sql/export/get_latest_export.sql
+var getLatestExportQuery string
+
+//go:embed sql/export/get_export.sql
+var getExportQuery string
+
+//go:embed sql/export/claim_export.sql
+var claimExportQuery string
+
+//go:embed sql/export/finish_export.sql
+var finishExportQuery string
+
+//go:embed sql/export/fail_export.sql
+var failExportQuery string
+
+//go:embed sql/export/expire_exports.sql
+var expireExportsQuery string
+
+func scanExport(row pgx.Row) (*domain.DataExport, error) {
+	e := &domain.DataExport{}
+	err := row.Scan(&e.ID, &e.UserID, &e.Status, &e.Error, &e.StartedAt, &e.FinishedAt, &e.ExpiresAt, &e.CreatedAt)
+	return e, err
+}
+
+// CreateExport ставит выгрузку в очередь. Если у пользователя уже есть
+// незавершенная выгрузка — domain.ErrExportInProgress.
+func (r *ProfileRepoPostgres) CreateExport(ctx context.Context, userID string) (*domain.DataExport, error) {
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "repo CreateExport start", slog.String("user_id", userID))
+
+	e, err := scanExport(r.db.QueryRow(ctx, createExportQuery, uuid.NewString(), userID))
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			log.WarnContext(ctx, "repo CreateExport already in progress", slog.String("user_id", userID))
+			return nil, domain.ErrExportInProgress
+		}
+		log.ErrorContext(ctx, "repo CreateExport db error", slog.Any("err", err), slog.String("user_id", userID))
+		return nil, err
+	}
+
+	log.InfoContext(ctx, "repo CreateExport success", slog.String("export_id", e.ID))
+	return e, nil
+}
+
+// GetLatestExport возвращает последнюю выгрузку пользователя
+func (r *ProfileRepoPostgres) GetLatestExport(ctx context.Context, userID string) (*domain.DataExport, error) {
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "repo GetLatestExport start", slog.String("user_id", userID))
+
+	e, err := scanExport(r.db.QueryRow(ctx, getLatestExportQuery, userID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		log.InfoContext(ctx, "repo GetLatestExport no exports", slog.String("user_id", userID))
+		return nil, domain.ErrExportNotFound
+	}
+	if err != nil {
+		log.ErrorContext(ctx, "repo GetLatestExport db error", slog.Any("err", err), slog.String("user_id", userID))
+		return nil, err
+	}
+
+	log.InfoContext(ctx, "repo GetLatestExport success", slog.String("export_id", e.ID))
+	return e, nil
+}
+
+func (r *ProfileRepoPostgres) GetExport(ctx context.Context, id string) (*domain.DataExport, error) {
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "repo GetExport start", slog.String("export_id", id))
+
+	e, err := scanExport(r.db.QueryRow(ctx, getExportQuery, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		log.WarnContext(ctx, "repo GetExport not found", slog.String("export_id", id))
+		return nil, domain.ErrExportNotFound
+	}
+	if err != nil {
+		log.ErrorContext(ctx, "repo GetExport db error", slog.Any("err", err), slog.String("export_id", id))
+		return nil, err
+	}
+
+	log.InfoContext(ctx, "repo GetExport success", slog.String("export_id", id))
+	return e, nil
+}
+
+// ClaimExport переводит в running самую старую ожидающую выгрузку или
+// выгрузку, зависшую в running дольше staleBefore. Пустая очередь — nil.
+func (r *ProfileRepoPostgres) ClaimExport(ctx context.Context, staleBefore time.Time) (*domain.DataExport, error) {
+	log := logger.FromContext(ctx)
+
+	e, err := scanExport(r.db.QueryRow(ctx, claimExportQuery, staleBefore))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		log.ErrorContext(ctx, "repo ClaimExport db error", slog.Any("err", err))
+		return nil, err
+	}
+
+	log.InfoContext(ctx, "repo ClaimExport success", slog.String("export_id", e.ID), slog.String("user_id", e.UserID))
+	return e, nil
+}
+
+func (r *ProfileRepoPostgres) FinishExport(ctx context.Context, id string, expiresAt time.Time) error {
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "repo FinishExport start", slog.String("export_id", id))
+
+	res, err := r.db.Exec(ctx, finishExportQuery, id, expiresAt)
+	if err != nil {
+		log.ErrorContext(ctx, "repo FinishExport db error", slog.Any("err", err), slog.String("export_id", id))
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		log.WarnContext(ctx, "repo FinishExport export not running", slog.String("export_id", id))
+		return domain.ErrExportNotFound
+	}
+
+	log.InfoContext(ctx, "repo FinishExport success", slog.String("export_id", id))
+	return nil
+}
+
+func (r *ProfileRepoPostgres) FailExport(ctx context.Context, id, reason string) error {
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "repo FailExport start", slog.String("export_id", id))
+
+	if _, err := r.db.Exec(ctx, failExportQuery, id, reason); err != nil {
+		log.ErrorContext(ctx, "repo FailExport db error", slog.Any("err", err), slog.String("export_id", id))
+		return err
+	}
+
+	log.InfoContext(ctx, "repo FailExport success", slog.String("export_id", id))
+	return nil
+}
+
+// ExpireExports помечает истекшие выгрузки и возвращает их id, чтобы
+// удалить архивы
+func (r *ProfileRepoPostgres) ExpireExports(ctx context.Context) ([]string, error) {
+	log := logger.FromContext(ctx)
+
+	rows, err := r.db.Query(ctx, expireExportsQuery)
+	if err != nil {
+		log.ErrorContext(ctx, "repo ExpireExports db error", slog.Any("err", err))
+		return nil, err
+	}
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		log.ErrorContext(ctx, "repo ExpireExports scan failed", slog.Any("err", err))
+		return nil, err
+	}
+
+	if len(ids) > 0 {
+		log.InfoContext(ctx, "repo ExpireExports success", slog.Int("expired", len(ids)))
+	}
+	return ids, nil
+}
+
+//go:embed sql/export/get_export_account.sql
+var getExportAccountQuery string
+
+//go:embed sql/export/get_export_friends.sql
+var getExportFriendsQuery string
+
+// GetPersonalData собирает данные пользователя: свои таблицы читает сам,
+// таблицы других сервисов — через pkg/userdata. Чтение идет в одном снимке
+// базы, чтобы заказы и платежи в выгрузке согласовывались между собой.
+func (r *ProfileRepoPostgres) GetPersonalData(ctx context.Context, userID string) (*domain.PersonalData, error) {
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "repo GetPersonalData start", slog.String("user_id", userID))
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		log.ErrorContext(ctx, "repo GetPersonalData begin failed", slog.Any("err", err))
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err = tx.Exec(ctx, "SET TRANSACTION ISOLATION LEVEL REPEATABLE READ READ ONLY"); err != nil {
+		log.ErrorContext(ctx, "repo GetPersonalData set isolation failed", slog.Any("err", err))
+		return nil, err
+	}
+
+	data := &domain.PersonalData{}
+	a := &data.Account
+	err = tx.QueryRow(ctx, getExportAccountQuery, userID).Scan(
+		&a.ID, &a.Email, &a.Name, &a.Phone, &a.CityID, &a.Address, &a.Lat, &a.Lon, &a.AvatarURL,
+		&a.CreatedAt, &a.UpdatedAt, &a.DeletedAt, &data.City,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		log.WarnContext(ctx, "repo GetPersonalData profile not found", slog.String("user_id", userID))
+		return nil, domain.ErrProfileNotFound
+	}
+	if err != nil {
+		log.ErrorContext(ctx, "repo GetPersonalData account failed", slog.Any("err", err), slog.String("user_id", userID))
+		return nil, err
+	}
+
+	// разделы других сервисов читаются их общими запросами в той же транзакции
+	reader := userdata.NewPostgresReader(tx)
+	sections := []struct {
+		name string
+		load func() error
+	}{
+		{"cart", func() (err error) {
+			data.Cart, err = reader.Cart(ctx, userID)
+			return err
+		}},
+		{"orders", func() (err error) {
+			data.Orders, err = reader.Orders(ctx, userID)
+			return err
+		}},
+		{"payments", func() (err error) {
+			data.Payments, err = reader.Payments(ctx, userID)
+			return err
+		}},
+		{"reviews", func() (err error) {
+			data.Reviews, err = reader.Reviews(ctx, userID)
+			return err
+		}},
+		{"friends", func() error {
+			rows, err := tx.Query(ctx, getExportFriendsQuery, userID)
+			if err != nil {
+				return err
+			}
+			data.Friends, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.ExportFriend, error) {
+				var f domain.ExportFriend
+				err := row.Scan(&f.UserID, &f.Name, &f.CreatedAt)
+				return f, err
+			})
+			return err
+		}},
+		{"auth_events", func() (err error) {
+			data.AuthEvents, err = reader.AuthEvents(ctx, userID)
+			return err
+		}},
+	}
+	for _, s := range sections {
+		if err := s.load(); err != nil {
+			log.ErrorContext(ctx, "repo GetPersonalData section failed", slog.String("section", s.name), slog.Any("err", err))
+			return nil, err
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.ErrorContext(ctx, "repo GetPersonalData commit failed", slog.Any("err", err))
+		return nil, err
+	}
+
+	log.InfoContext(ctx, "repo GetPersonalData success", slog.String("user_id", userID),
+		slog.Int("orders", len(data.Orders)), slog.Int("events", len(data.AuthEvents)))
+	return data, nil
+}
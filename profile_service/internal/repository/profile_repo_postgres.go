@@ -34,9 +34,12 @@ func (r *ProfileRepoPostgres) GetProfile(ctx context.Context, id string) (*domai
 		&p.Phone,
 		&p.CityID,
 		&p.Address,
+		&p.Lat,
+		&p.Lon,
 		&p.AvatarURL,
 		&p.CreatedAt,
 		&p.UpdatedAt,
+		&p.DeletedAt,
 	)
 
 	if err != nil {
@@ -65,6 +68,8 @@ func (r *ProfileRepoPostgres) UpdateProfile(ctx context.Context, p *domain.Profi
 		p.CityID,
 		p.Address,
 		p.AvatarURL,
+		p.Lat,
+		p.Lon,
 		p.ID,
 	)
 
@@ -85,22 +90,49 @@ func (r *ProfileRepoPostgres) UpdateProfile(ctx context.Context, p *domain.Profi
 //go:embed sql/profile/delete_profile.sql
 var deleteProfileQuery string
 
+//go:embed sql/profile/revoke_sessions.sql
+var revokeSessionsQuery string
+
+//go:embed sql/profile/revoke_api_keys.sql
+var revokeAPIKeysQuery string
+
+// DeleteProfile помечает аккаунт удаленным и отзывает все его сессии и
+// API-ключи. Сами данные обезличивает позже AnonymizeAccount.
 func (r *ProfileRepoPostgres) DeleteProfile(ctx context.Context, id string) error {
 	log := logger.FromContext(ctx)
 	log.InfoContext(ctx, "repo DeleteProfile start", slog.String("id", id))
 
-	res, err := r.db.Exec(ctx, deleteProfileQuery, id)
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		log.ErrorContext(ctx, "repo DeleteProfile begin failed", slog.String("id", id), slog.Any("err", err))
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
 
+	res, err := tx.Exec(ctx, deleteProfileQuery, id)
 	if err != nil {
 		log.ErrorContext(ctx, "repo DeleteProfile db error", slog.String("id", id), slog.Any("err", err))
 		return err
 	}
-
 	if res.RowsAffected() == 0 {
 		log.WarnContext(ctx, "repo DeleteProfile profile not found", slog.String("id", id))
 		return domain.ErrProfileNotFound
 	}
 
+	if _, err = tx.Exec(ctx, revokeSessionsQuery, id); err != nil {
+		log.ErrorContext(ctx, "repo DeleteProfile revoke sessions failed", slog.String("id", id), slog.Any("err", err))
+		return err
+	}
+	if _, err = tx.Exec(ctx, revokeAPIKeysQuery, id); err != nil {
+		log.ErrorContext(ctx, "repo DeleteProfile revoke api keys failed", slog.String("id", id), slog.Any("err", err))
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.ErrorContext(ctx, "repo DeleteProfile commit failed", slog.String("id", id), slog.Any("err", err))
+		return err
+	}
+
 	log.InfoContext(ctx, "repo DeleteProfile success", slog.String("id", id))
 	return nil
 }
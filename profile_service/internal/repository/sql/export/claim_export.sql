@@ -0,0 +1,12 @@
+-- берет самую старую ожидающую выгрузку или зависшую после падения сервиса;
+-- SKIP LOCKED позволяет запускать несколько экземпляров сервиса
+UPDATE data_export
+SET status     = 'running',
+    started_at = current_timestamp
+WHERE id = (SELECT id
+            FROM data_export
+            WHERE status = 'pending'
+               OR (status = 'running' AND started_at < $1)
+            ORDER BY created_at
+            LIMIT 1 FOR UPDATE SKIP LOCKED)
+RETURNING id, user_id, status, error, started_at, finished_at, expires_at, created_at;
@@ -0,0 +1,3 @@
+INSERT INTO data_export (id, user_id)
+VALUES ($1, $2)
+RETURNING id, user_id, status, error, started_at, finished_at, expires_at, created_at;
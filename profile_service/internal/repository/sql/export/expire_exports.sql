@@ -0,0 +1,5 @@
+UPDATE data_export
+SET status = 'expired'
+WHERE status = 'ready'
+  AND expires_at <= current_timestamp
+RETURNING id;
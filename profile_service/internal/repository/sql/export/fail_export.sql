@@ -0,0 +1,6 @@
+UPDATE data_export
+SET status      = 'failed',
+    finished_at = current_timestamp,
+    error       = left($2, 500)
+WHERE id = $1
+  AND status = 'running';
@@ -0,0 +1,6 @@
+UPDATE data_export
+SET status      = 'ready',
+    finished_at = current_timestamp,
+    expires_at  = $2
+WHERE id = $1
+  AND status = 'running';
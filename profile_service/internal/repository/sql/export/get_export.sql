@@ -0,0 +1,3 @@
+SELECT id, user_id, status, error, started_at, finished_at, expires_at, created_at
+FROM data_export
+WHERE id = $1;
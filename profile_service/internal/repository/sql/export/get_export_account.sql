@@ -0,0 +1,17 @@
+SELECT a.id,
+       coalesce(a.email, ''),
+       a.name,
+       a.phone,
+       a.city_id,
+       a.address,
+       a.lat,
+       a.lon,
+       a.avatar_url,
+       a.created_at,
+       a.updated_at,
+       a.deleted_at,
+       c.name
+FROM account a
+         LEFT JOIN city c ON c.id = a.city_id
+WHERE a.id = $1
+  AND a.anonymized_at IS NULL;
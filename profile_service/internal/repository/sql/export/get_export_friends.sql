@@ -0,0 +1,8 @@
+SELECT a.id,
+       a.name,
+       f.created_at
+FROM friend f
+         JOIN account a ON a.id = CASE WHEN f.user_id_1 = $1 THEN f.user_id_2 ELSE f.user_id_1 END
+WHERE f.user_id_1 = $1
+   OR f.user_id_2 = $1
+ORDER BY f.created_at;
@@ -0,0 +1,5 @@
+SELECT id, user_id, status, error, started_at, finished_at, expires_at, created_at
+FROM data_export
+WHERE user_id = $1
+ORDER BY created_at DESC
+LIMIT 1;
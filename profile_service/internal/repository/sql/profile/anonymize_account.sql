@@ -0,0 +1,17 @@
+UPDATE account
+SET email                      = NULL,
+    hash                       = '',
+    name                       = NULL,
+    phone                      = NULL,
+    phone_verified_at          = NULL,
+    city_id                    = NULL,
+    address                    = NULL,
+    lat                        = NULL,
+    lon                        = NULL,
+    avatar_url                 = NULL,
+    email_verified_at          = NULL,
+    email_verification_sent_at = NULL,
+    totp_secret                = NULL,
+    totp_enabled_at            = NULL,
+    anonymized_at              = current_timestamp
+WHERE id = $1;
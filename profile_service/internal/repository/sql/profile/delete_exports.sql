@@ -0,0 +1,5 @@
+-- архивы на диске удаляет вызывающий код по возвращенным id
+DELETE
+FROM data_export
+WHERE user_id = $1
+RETURNING id;
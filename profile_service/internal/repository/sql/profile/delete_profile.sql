@@ -1 +1,4 @@
-DELETE FROM account WHERE id = $1;
\ No newline at end of file
+UPDATE account
+SET deleted_at = coalesce(deleted_at, current_timestamp)
+WHERE id = $1
+  AND anonymized_at IS NULL;
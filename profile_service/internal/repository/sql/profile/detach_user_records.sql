@@ -0,0 +1,7 @@
+-- заказы (а с ними платежи) и отзывы остаются, но больше не указывают на человека
+WITH orders_detached AS (
+    UPDATE orders SET user_id = NULL WHERE user_id = $1
+)
+UPDATE review
+SET user_id = NULL
+WHERE user_id = $1;
@@ -1,3 +1,4 @@
-SELECT id, email, name, phone, city_id, address, avatar_url, created_at, updated_at
+SELECT id, coalesce(email, ''), name, phone, city_id, address, lat, lon, avatar_url, created_at, updated_at, deleted_at
 FROM account
-WHERE id = $1;
\ No newline at end of file
+WHERE id = $1
+  AND anonymized_at IS NULL;
@@ -0,0 +1,6 @@
+SELECT id, avatar_url
+FROM account
+WHERE deleted_at <= $1
+  AND anonymized_at IS NULL
+ORDER BY deleted_at
+LIMIT $2;
@@ -0,0 +1,6 @@
+SELECT phone
+FROM account
+WHERE id = $1
+  AND deleted_at <= $2
+  AND anonymized_at IS NULL
+    FOR UPDATE;
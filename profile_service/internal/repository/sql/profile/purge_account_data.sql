@@ -0,0 +1,16 @@
+-- данные, которые нужны только самому пользователю, удаляются целиком.
+-- Журнал auth_event остается: он только дописывается и не связан с account,
+-- личные поля его событий стирает scrub_auth_events.sql. Выгрузки удаляет
+-- delete_exports.sql: их id нужны, чтобы стереть архивы
+WITH phone_codes AS (DELETE FROM phone_code WHERE phone = $2),
+     carts AS (DELETE FROM cart WHERE user_id = $1),
+     friends AS (DELETE FROM friend WHERE user_id_1 = $1 OR user_id_2 = $1),
+     identities AS (DELETE FROM external_identity WHERE user_id = $1),
+     recovery_codes AS (DELETE FROM recovery_code WHERE user_id = $1),
+     resets AS (DELETE FROM password_reset_token WHERE user_id = $1),
+     api_keys AS (DELETE FROM api_key WHERE user_id = $1),
+     roles AS (DELETE FROM account_role WHERE user_id = $1),
+     store_owners AS (DELETE FROM store_owner WHERE user_id = $1)
+DELETE
+FROM session
+WHERE user_id = $1;
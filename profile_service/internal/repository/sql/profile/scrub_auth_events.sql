@@ -0,0 +1,9 @@
+-- события остаются в журнале, но без email или телефона, IP и user agent.
+-- Триггер auth_event пропускает это изменение, только если аккаунт уже
+-- обезличен, поэтому запрос выполняется после anonymize_account.sql
+UPDATE auth_event
+SET identifier = NULL,
+    ip         = NULL,
+    user_agent = NULL
+WHERE user_id = $1
+  AND (identifier IS NOT NULL OR ip IS NOT NULL OR user_agent IS NOT NULL);
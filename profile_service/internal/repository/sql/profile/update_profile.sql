@@ -1,8 +1,12 @@
 UPDATE account
 SET
-    name       = $1,
-    phone      = $2,
-    city_id    = $3,
-    address    = $4,
-    avatar_url = $5
-WHERE id = $6;
\ No newline at end of file
+    name              = $1,
+    phone             = $2,
+    -- номер, измененный в профиле, для входа по коду нужно подтвердить заново
+    phone_verified_at = CASE WHEN phone IS NOT DISTINCT FROM $2 THEN phone_verified_at END,
+    city_id           = $3,
+    address           = $4,
+    avatar_url        = $5,
+    lat               = $6,
+    lon               = $7
+WHERE id = $8;
\ No newline at end of file
@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"apple_backend/pkg/logger"
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"time"
+)
+
+// purgeBatchSize — сколько аккаунтов обезличивается за один проход
+const purgeBatchSize = 100
+
+// AccountPurger обезличивает аккаунты, у которых истек срок на отмену
+// удаления. Запускается фоновой задачей.
+type AccountPurger struct {
+	repo       AccountDeletionRepository
+	uploadPath string
+	exportDir  string
+	grace      time.Duration
+}
+
+func NewAccountPurger(repo AccountDeletionRepository, uploadPath, exportDir string, grace time.Duration) *AccountPurger {
+	return &AccountPurger{repo: repo, uploadPath: uploadPath, exportDir: exportDir, grace: grace}
+}
+
+// PurgeDeleted обезличивает все просроченные аккаунты и возвращает их число.
+// Ошибка одного аккаунта не останавливает остальные.
+func (p *AccountPurger) PurgeDeleted(ctx context.Context) (int, error) {
+	log := logger.FromContext(ctx)
+	before := time.Now().Add(-p.grace)
+
+	purged := 0
+	var errs []error
+	for {
+		accounts, err := p.repo.ListDueDeletions(ctx, before, purgeBatchSize)
+		if err != nil {
+			return purged, err
+		}
+
+		done := 0
+		for _, a := range accounts {
+			exportIDs, ok, err := p.repo.AnonymizeAccount(ctx, a.ID, before)
+			if err != nil {
+				log.ErrorContext(ctx, "usecase PurgeDeleted anonymize failed", slog.Any("err", err), slog.String("user_id", a.ID))
+				errs = append(errs, err)
+				continue
+			}
+			if !ok {
+				continue
+			}
+			done++
+			// файлы удаляются после коммита: ссылки на них уже стерты
+			if path, ok := avatarPath(p.uploadPath, a.AvatarURL); ok {
+				if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+					log.WarnContext(ctx, "usecase PurgeDeleted avatar remove failed", slog.Any("err", err), slog.String("user_id", a.ID))
+				}
+			}
+			for _, id := range exportIDs {
+				if err := os.Remove(exportArchivePath(p.exportDir, id)); err != nil && !errors.Is(err, os.ErrNotExist) {
+					log.WarnContext(ctx, "usecase PurgeDeleted export remove failed", slog.Any("err", err), slog.String("export_id", id))
+				}
+			}
+		}
+		purged += done
+
+		// неудачные аккаунты остаются в выборке, поэтому без прогресса — выход
+		if len(accounts) < purgeBatchSize || done == 0 {
+			break
+		}
+	}
+
+	if purged > 0 {
+		log.InfoContext(ctx, "usecase PurgeDeleted success", slog.Int("purged", purged))
+	}
+	return purged, errors.Join(errs...)
+}
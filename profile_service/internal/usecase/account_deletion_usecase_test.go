@@ -0,0 +1,70 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"apple_backend/profile_service/internal/domain"
+	"apple_backend/profile_service/internal/usecase/mock"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountPurger_PurgeDeleted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tmpDir := t.TempDir()
+	avatar := filepath.Join(tmpDir, "u1_20240101T000000.000Z.jpg")
+	require.NoError(t, os.WriteFile(avatar, []byte("img"), 0o644))
+	avatarURL := "http://localhost/u1_20240101T000000.000Z.jpg"
+	exportDir := t.TempDir()
+	archive := exportArchivePath(exportDir, "e1")
+	require.NoError(t, os.WriteFile(archive, []byte("zip"), 0o600))
+
+	repo := mock.NewMockAccountDeletionRepository(ctrl)
+	purger := NewAccountPurger(repo, tmpDir, exportDir, 30*24*time.Hour)
+
+	var cutoff time.Time
+	repo.EXPECT().ListDueDeletions(gomock.Any(), gomock.Any(), purgeBatchSize).
+		DoAndReturn(func(_ context.Context, before time.Time, _ int) ([]*domain.DeletedAccount, error) {
+			cutoff = before
+			return []*domain.DeletedAccount{
+				{ID: "u1", AvatarURL: &avatarURL},
+				{ID: "u2"},
+				{ID: "u3"},
+			}, nil
+		})
+	repo.EXPECT().AnonymizeAccount(gomock.Any(), "u1", gomock.Any()).Return([]string{"e1", "e2"}, true, nil)
+	// удаление отменили между выборкой и обезличиванием
+	repo.EXPECT().AnonymizeAccount(gomock.Any(), "u2", gomock.Any()).Return(nil, false, nil)
+	repo.EXPECT().AnonymizeAccount(gomock.Any(), "u3", gomock.Any()).Return(nil, false, errors.New("db down"))
+
+	purged, err := purger.PurgeDeleted(context.Background())
+	require.Error(t, err)
+	require.Equal(t, 1, purged)
+	require.WithinDuration(t, time.Now().Add(-30*24*time.Hour), cutoff, time.Minute)
+
+	_, statErr := os.Stat(avatar)
+	require.True(t, errors.Is(statErr, os.ErrNotExist), "avatar file must be removed")
+	_, statErr = os.Stat(archive)
+	require.True(t, errors.Is(statErr, os.ErrNotExist), "export archive must be removed")
+}
+
+func TestAvatarPath(t *testing.T) {
+	url := "http://localhost/avatars/u1_x.png"
+	path, ok := avatarPath("/data", &url)
+	require.True(t, ok)
+	require.Equal(t, filepath.Join("/data", "u1_x.png"), path)
+
+	empty := ""
+	_, ok = avatarPath("/data", &empty)
+	require.False(t, ok)
+	_, ok = avatarPath("/data", nil)
+	require.False(t, ok)
+}
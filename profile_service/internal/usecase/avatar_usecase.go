@@ -84,13 +84,8 @@ func (uc *AvatarUsecase) UploadAvatar(ctx context.Context, userID string, src io
 		_ = os.Remove(dstPath)
 		return "", err
 	}
-	if profile.AvatarURL != nil && *profile.AvatarURL != "" {
-		if u, perr := url.Parse(*profile.AvatarURL); perr == nil {
-			oldName := filepath.Base(u.Path)
-			if oldName != "" {
-				_ = os.Remove(filepath.Join(uc.uploadPath, oldName))
-			}
-		}
+	if oldPath, ok := avatarPath(uc.uploadPath, profile.AvatarURL); ok {
+		_ = os.Remove(oldPath)
 	}
 
 	avatarURL := uc.baseURL + "/" + filename
@@ -102,3 +97,19 @@ func (uc *AvatarUsecase) UploadAvatar(ctx context.Context, userID string, src io
 	}
 	return avatarURL, nil
 }
+
+// avatarPath возвращает путь к файлу аватарки по ее публичному URL
+func avatarPath(uploadPath string, avatarURL *string) (string, bool) {
+	if avatarURL == nil || *avatarURL == "" {
+		return "", false
+	}
+	u, err := url.Parse(*avatarURL)
+	if err != nil {
+		return "", false
+	}
+	name := filepath.Base(u.Path)
+	if name == "" || name == "." || name == "/" {
+		return "", false
+	}
+	return filepath.Join(uploadPath, name), true
+}
@@ -0,0 +1,230 @@
+package usecase
+
+import (
+	"apple_backend/pkg/logger"
+	"apple_backend/profile_service/internal/delivery/transport"
+	"apple_backend/profile_service/internal/domain"
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// exportCooldown — как часто можно запрашивать новую выгрузку
+	exportCooldown = time.Hour
+	// exportTTL — сколько хранится готовый архив
+	exportTTL = 7 * 24 * time.Hour
+	// exportLinkTTL — срок одной ссылки на скачивание; новую ссылку выдает
+	// каждый запрос статуса
+	exportLinkTTL = time.Hour
+	// exportStaleAfter — через сколько выгрузка в running считается брошенной
+	// упавшим экземпляром сервиса
+	exportStaleAfter = 30 * time.Minute
+)
+
+// ExportUsecase собирает архивы с данными пользователя. Запрос только ставит
+// выгрузку в очередь, архив собирает фоновая задача RunPending.
+type ExportUsecase struct {
+	repo   ExportRepository
+	signer URLSigner
+	// dir — каталог с архивами, baseURL и apiPrefix — для ссылок на скачивание
+	dir       string
+	baseURL   string
+	apiPrefix string
+}
+
+func NewExportUsecase(repo ExportRepository, signer URLSigner, dir, baseURL, apiPrefix string) *ExportUsecase {
+	return &ExportUsecase{
+		repo:      repo,
+		signer:    signer,
+		dir:       dir,
+		baseURL:   strings.TrimRight(baseURL, "/"),
+		apiPrefix: strings.TrimRight(apiPrefix, "/"),
+	}
+}
+
+// RequestExport ставит выгрузку в очередь. Незавершенная выгрузка
+// возвращается как есть, новую можно запросить не чаще exportCooldown.
+func (uc *ExportUsecase) RequestExport(ctx context.Context, userID string) (*domain.DataExport, error) {
+	if _, err := uuid.Parse(userID); err != nil {
+		return nil, domain.ErrInvalidProfileData
+	}
+	latest, err := uc.repo.GetLatestExport(ctx, userID)
+	switch {
+	case errors.Is(err, domain.ErrExportNotFound):
+	case err != nil:
+		return nil, err
+	case latest.Active():
+		return latest, nil
+	case latest.Status != domain.ExportFailed && time.Since(latest.CreatedAt) < exportCooldown:
+		return nil, domain.ErrExportTooFrequent
+	}
+
+	e, err := uc.repo.CreateExport(ctx, userID)
+	if errors.Is(err, domain.ErrExportInProgress) {
+		// параллельный запрос успел поставить свою выгрузку
+		return uc.repo.GetLatestExport(ctx, userID)
+	}
+	return e, err
+}
+
+// GetExport возвращает последнюю выгрузку пользователя и, если архив готов,
+// свежую ссылку на него со сроком действия.
+func (uc *ExportUsecase) GetExport(ctx context.Context, userID string) (*domain.DataExport, string, time.Time, error) {
+	if _, err := uuid.Parse(userID); err != nil {
+		return nil, "", time.Time{}, domain.ErrInvalidProfileData
+	}
+	e, err := uc.repo.GetLatestExport(ctx, userID)
+	if err != nil {
+		return nil, "", time.Time{}, err
+	}
+	if e.Status != domain.ExportReady || e.ExpiresAt == nil {
+		return e, "", time.Time{}, nil
+	}
+
+	expires := time.Now().Add(exportLinkTTL)
+	if e.ExpiresAt.Before(expires) {
+		expires = *e.ExpiresAt
+	}
+	return e, uc.baseURL + uc.signer.Sign(uc.downloadPath(e.ID), expires), expires, nil
+}
+
+// OpenExport проверяет подписанную ссылку и возвращает путь к архиву
+func (uc *ExportUsecase) OpenExport(ctx context.Context, id string, link *url.URL) (string, error) {
+	if _, err := uuid.Parse(id); err != nil || link.Path != uc.downloadPath(id) || uc.signer.Verify(link) != nil {
+		return "", domain.ErrExportLinkInvalid
+	}
+	e, err := uc.repo.GetExport(ctx, id)
+	if errors.Is(err, domain.ErrExportNotFound) {
+		return "", domain.ErrExportLinkInvalid
+	}
+	if err != nil {
+		return "", err
+	}
+	if e.Status != domain.ExportReady || e.ExpiresAt == nil || time.Now().After(*e.ExpiresAt) {
+		return "", domain.ErrExportLinkInvalid
+	}
+	return uc.archivePath(id), nil
+}
+
+func (uc *ExportUsecase) downloadPath(id string) string {
+	return uc.apiPrefix + "/exports/" + id + "/download"
+}
+
+func (uc *ExportUsecase) archivePath(id string) string {
+	return exportArchivePath(uc.dir, id)
+}
+
+func exportArchivePath(dir, id string) string {
+	return filepath.Join(dir, id+".zip")
+}
+
+// RunPending собирает все выгрузки из очереди и возвращает их число
+func (uc *ExportUsecase) RunPending(ctx context.Context) (int, error) {
+	log := logger.FromContext(ctx)
+	done := 0
+	for {
+		e, err := uc.repo.ClaimExport(ctx, time.Now().Add(-exportStaleAfter))
+		if err != nil {
+			return done, err
+		}
+		if e == nil {
+			return done, nil
+		}
+
+		if err := uc.build(ctx, e); err != nil {
+			log.ErrorContext(ctx, "usecase RunPending build failed", slog.Any("err", err), slog.String("export_id", e.ID))
+			_ = os.Remove(uc.archivePath(e.ID))
+			if ferr := uc.repo.FailExport(ctx, e.ID, err.Error()); ferr != nil {
+				return done, ferr
+			}
+			continue
+		}
+		err = uc.repo.FinishExport(ctx, e.ID, time.Now().Add(exportTTL))
+		if errors.Is(err, domain.ErrExportNotFound) {
+			// аккаунт обезличили, пока собирался архив: выгрузки больше нет
+			log.WarnContext(ctx, "usecase RunPending export gone", slog.String("export_id", e.ID))
+			_ = os.Remove(uc.archivePath(e.ID))
+			continue
+		}
+		if err != nil {
+			return done, err
+		}
+		done++
+		log.InfoContext(ctx, "usecase RunPending export ready", slog.String("export_id", e.ID), slog.String("user_id", e.UserID))
+	}
+}
+
+// build пишет архив во временный файл и переименовывает его, чтобы по
+// ссылке никогда не отдавался недописанный архив
+func (uc *ExportUsecase) build(ctx context.Context, e *domain.DataExport) error {
+	data, err := uc.repo.GetPersonalData(ctx, e.UserID)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(uc.dir, 0o700); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(uc.dir, e.ID+"-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := writeExportArchive(tmp, transport.ToExportFiles(data)); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), uc.archivePath(e.ID))
+}
+
+func writeExportArchive(f *os.File, files map[string]any) error {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	zw := zip.NewWriter(f)
+	for _, name := range names {
+		w, err := zw.Create(name)
+		if err != nil {
+			return err
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(files[name]); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// CleanupExpired удаляет архивы, срок хранения которых истек
+func (uc *ExportUsecase) CleanupExpired(ctx context.Context) error {
+	ids, err := uc.repo.ExpireExports(ctx)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if err := os.Remove(uc.archivePath(id)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			logger.FromContext(ctx).WarnContext(ctx, "usecase CleanupExpired remove failed",
+				slog.Any("err", err), slog.String("export_id", id))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package usecase
+
+import (
+	"archive/zip"
+	"context"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"apple_backend/pkg/signedurl"
+	"apple_backend/profile_service/internal/domain"
+	"apple_backend/profile_service/internal/usecase/mock"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+const exportUserID = "7f9c2a1e-3b4d-4c5e-8f60-1a2b3c4d5e6f"
+
+func TestExportUsecase_RequestExport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock.NewMockExportRepository(ctrl)
+	uc := NewExportUsecase(repo, signedurl.New("secret"), t.TempDir(), "http://localhost", "/api/v0")
+	ctx := context.Background()
+
+	// незавершенная выгрузка возвращается без создания новой
+	running := &domain.DataExport{ID: "e1", Status: domain.ExportRunning, CreatedAt: time.Now()}
+	repo.EXPECT().GetLatestExport(gomock.Any(), exportUserID).Return(running, nil)
+	e, err := uc.RequestExport(ctx, exportUserID)
+	require.NoError(t, err)
+	require.Equal(t, running, e)
+
+	// готовая выгрузка моложе часа — слишком часто
+	repo.EXPECT().GetLatestExport(gomock.Any(), exportUserID).
+		Return(&domain.DataExport{ID: "e1", Status: domain.ExportReady, CreatedAt: time.Now().Add(-10 * time.Minute)}, nil)
+	_, err = uc.RequestExport(ctx, exportUserID)
+	require.ErrorIs(t, err, domain.ErrExportTooFrequent)
+
+	// упавшую выгрузку можно перезапросить сразу
+	repo.EXPECT().GetLatestExport(gomock.Any(), exportUserID).
+		Return(&domain.DataExport{ID: "e1", Status: domain.ExportFailed, CreatedAt: time.Now()}, nil)
+	created := &domain.DataExport{ID: "e2", Status: domain.ExportPending}
+	repo.EXPECT().CreateExport(gomock.Any(), exportUserID).Return(created, nil)
+	e, err = uc.RequestExport(ctx, exportUserID)
+	require.NoError(t, err)
+	require.Equal(t, created, e)
+
+	_, err = uc.RequestExport(ctx, "not-a-uuid")
+	require.ErrorIs(t, err, domain.ErrInvalidProfileData)
+}
+
+func TestExportUsecase_RunPendingAndDownload(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dir := t.TempDir()
+	repo := mock.NewMockExportRepository(ctrl)
+	uc := NewExportUsecase(repo, signedurl.New("secret"), dir, "http://localhost", "/api/v0")
+	ctx := context.Background()
+
+	exportID := "0b6f5d3a-9c2e-4a1f-b7d8-6e5f4a3b2c1d"
+	name := "Иван"
+	gomock.InOrder(
+		repo.EXPECT().ClaimExport(gomock.Any(), gomock.Any()).
+			Return(&domain.DataExport{ID: exportID, UserID: exportUserID, Status: domain.ExportRunning}, nil),
+		repo.EXPECT().GetPersonalData(gomock.Any(), exportUserID).Return(&domain.PersonalData{
+			Account: domain.Profile{ID: exportUserID, Email: "ivan@example.com", Name: &name},
+			Orders: []domain.ExportOrder{{
+				ID:     "o1",
+				Status: "delivered",
+				Total:  500,
+				Items:  []domain.ExportOrderItem{{StoreItemID: "i1", Name: "Пицца", Price: 250, Quantity: 2}},
+			}},
+		}, nil),
+		repo.EXPECT().FinishExport(gomock.Any(), exportID, gomock.Any()).Return(nil),
+		repo.EXPECT().ClaimExport(gomock.Any(), gomock.Any()).Return(nil, nil),
+	)
+
+	done, err := uc.RunPending(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, done)
+
+	zr, err := zip.OpenReader(filepath.Join(dir, exportID+".zip"))
+	require.NoError(t, err)
+	var names []string
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+	}
+	require.NoError(t, zr.Close())
+	require.ElementsMatch(t, []string{
+		"account.json", "addresses.json", "cart.json", "orders.json",
+		"payments.json", "reviews.json", "friends.json", "auth_events.json",
+	}, names)
+
+	// временные файлы не остаются в каталоге
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	expiresAt := time.Now().Add(exportTTL)
+	ready := &domain.DataExport{ID: exportID, UserID: exportUserID, Status: domain.ExportReady, ExpiresAt: &expiresAt}
+	repo.EXPECT().GetLatestExport(gomock.Any(), exportUserID).Return(ready, nil)
+	_, link, linkExpires, err := uc.GetExport(ctx, exportUserID)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(link, "http://localhost/api/v0/exports/"+exportID+"/download?"))
+	require.WithinDuration(t, time.Now().Add(exportLinkTTL), linkExpires, time.Minute)
+
+	u, err := url.Parse(link)
+	require.NoError(t, err)
+	repo.EXPECT().GetExport(gomock.Any(), exportID).Return(ready, nil)
+	path, err := uc.OpenExport(ctx, exportID, u)
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(dir, exportID+".zip"), path)
+
+	// подделанная подпись и ссылка на чужую выгрузку отклоняются без обращения к БД
+	q := u.Query()
+	q.Set("sig", "forged")
+	u.RawQuery = q.Encode()
+	_, err = uc.OpenExport(ctx, exportID, u)
+	require.ErrorIs(t, err, domain.ErrExportLinkInvalid)
+
+	u, _ = url.Parse(link)
+	_, err = uc.OpenExport(ctx, "1c2d3e4f-5a6b-4c7d-8e9f-0a1b2c3d4e5f", u)
+	require.ErrorIs(t, err, domain.ErrExportLinkInvalid)
+}
+
+func TestExportUsecase_RunPendingExportPurged(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dir := t.TempDir()
+	repo := mock.NewMockExportRepository(ctrl)
+	uc := NewExportUsecase(repo, signedurl.New("secret"), dir, "http://localhost", "/api/v0")
+
+	exportID := "0b6f5d3a-9c2e-4a1f-b7d8-6e5f4a3b2c1d"
+	gomock.InOrder(
+		repo.EXPECT().ClaimExport(gomock.Any(), gomock.Any()).
+			Return(&domain.DataExport{ID: exportID, UserID: exportUserID, Status: domain.ExportRunning}, nil),
+		repo.EXPECT().GetPersonalData(gomock.Any(), exportUserID).
+			Return(&domain.PersonalData{Account: domain.Profile{ID: exportUserID}}, nil),
+		// аккаунт обезличили во время сборки, строки выгрузки уже нет
+		repo.EXPECT().FinishExport(gomock.Any(), exportID, gomock.Any()).Return(domain.ErrExportNotFound),
+		repo.EXPECT().ClaimExport(gomock.Any(), gomock.Any()).Return(nil, nil),
+	)
+
+	done, err := uc.RunPending(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 0, done)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
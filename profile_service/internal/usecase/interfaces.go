@@ -4,6 +4,8 @@ import (
 	"apple_backend/profile_service/internal/domain"
 	"context"
 	"io"
+	"net/url"
+	"time"
 )
 
 type ProfileRepository interface {
@@ -12,6 +14,27 @@ type ProfileRepository interface {
 	DeleteProfile(ctx context.Context, id string) error
 }
 
+type AccountDeletionRepository interface {
+	ListDueDeletions(ctx context.Context, before time.Time, limit int) ([]*domain.DeletedAccount, error)
+	AnonymizeAccount(ctx context.Context, id string, before time.Time) (exportIDs []string, ok bool, err error)
+}
+
+type ExportRepository interface {
+	CreateExport(ctx context.Context, userID string) (*domain.DataExport, error)
+	GetLatestExport(ctx context.Context, userID string) (*domain.DataExport, error)
+	GetExport(ctx context.Context, id string) (*domain.DataExport, error)
+	ClaimExport(ctx context.Context, staleBefore time.Time) (*domain.DataExport, error)
+	FinishExport(ctx context.Context, id string, expiresAt time.Time) error
+	FailExport(ctx context.Context, id, reason string) error
+	ExpireExports(ctx context.Context) ([]string, error)
+	GetPersonalData(ctx context.Context, userID string) (*domain.PersonalData, error)
+}
+
+type URLSigner interface {
+	Sign(path string, expires time.Time) string
+	Verify(u *url.URL) error
+}
+
 // пока что для будущего перехода на s3
 type AvatarStorage interface {
 	Upload(ctx context.Context, key string, file io.Reader, contentType string) (string, error)
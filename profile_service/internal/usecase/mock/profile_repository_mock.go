@@ -8,7 +8,9 @@ import (
 	domain "apple_backend/profile_service/internal/domain"
 	context "context"
 	io "io"
+	url "net/url"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -79,6 +81,252 @@ func (mr *MockProfileRepositoryMockRecorder) UpdateProfile(ctx, profile interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProfile", reflect.TypeOf((*MockProfileRepository)(nil).UpdateProfile), ctx, profile)
 }
 
+// MockAccountDeletionRepository is a mock of AccountDeletionRepository interface.
+type MockAccountDeletionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccountDeletionRepositoryMockRecorder
+}
+
+// MockAccountDeletionRepositoryMockRecorder is the mock recorder for MockAccountDeletionRepository.
+type MockAccountDeletionRepositoryMockRecorder struct {
+	mock *MockAccountDeletionRepository
+}
+
+// NewMockAccountDeletionRepository creates a new mock instance.
+func NewMockAccountDeletionRepository(ctrl *gomock.Controller) *MockAccountDeletionRepository {
+	mock := &MockAccountDeletionRepository{ctrl: ctrl}
+	mock.recorder = &MockAccountDeletionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccountDeletionRepository) EXPECT() *MockAccountDeletionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AnonymizeAccount mocks base method.
+func (m *MockAccountDeletionRepository) AnonymizeAccount(ctx context.Context, id string, before time.Time) ([]string, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AnonymizeAccount", ctx, id, before)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// AnonymizeAccount indicates an expected call of AnonymizeAccount.
+func (mr *MockAccountDeletionRepositoryMockRecorder) AnonymizeAccount(ctx, id, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnonymizeAccount", reflect.TypeOf((*MockAccountDeletionRepository)(nil).AnonymizeAccount), ctx, id, before)
+}
+
+// ListDueDeletions mocks base method.
+func (m *MockAccountDeletionRepository) ListDueDeletions(ctx context.Context, before time.Time, limit int) ([]*domain.DeletedAccount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDueDeletions", ctx, before, limit)
+	ret0, _ := ret[0].([]*domain.DeletedAccount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDueDeletions indicates an expected call of ListDueDeletions.
+func (mr *MockAccountDeletionRepositoryMockRecorder) ListDueDeletions(ctx, before, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDueDeletions", reflect.TypeOf((*MockAccountDeletionRepository)(nil).ListDueDeletions), ctx, before, limit)
+}
+
+// MockExportRepository is a mock of ExportRepository interface.
+type MockExportRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockExportRepositoryMockRecorder
+}
+
+// MockExportRepositoryMockRecorder is the mock recorder for MockExportRepository.
+type MockExportRepositoryMockRecorder struct {
+	mock *MockExportRepository
+}
+
+// NewMockExportRepository creates a new mock instance.
+func NewMockExportRepository(ctrl *gomock.Controller) *MockExportRepository {
+	mock := &MockExportRepository{ctrl: ctrl}
+	mock.recorder = &MockExportRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExportRepository) EXPECT() *MockExportRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ClaimExport mocks base method.
+func (m *MockExportRepository) ClaimExport(ctx context.Context, staleBefore time.Time) (*domain.DataExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimExport", ctx, staleBefore)
+	ret0, _ := ret[0].(*domain.DataExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimExport indicates an expected call of ClaimExport.
+func (mr *MockExportRepositoryMockRecorder) ClaimExport(ctx, staleBefore interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimExport", reflect.TypeOf((*MockExportRepository)(nil).ClaimExport), ctx, staleBefore)
+}
+
+// CreateExport mocks base method.
+func (m *MockExportRepository) CreateExport(ctx context.Context, userID string) (*domain.DataExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateExport", ctx, userID)
+	ret0, _ := ret[0].(*domain.DataExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateExport indicates an expected call of CreateExport.
+func (mr *MockExportRepositoryMockRecorder) CreateExport(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateExport", reflect.TypeOf((*MockExportRepository)(nil).CreateExport), ctx, userID)
+}
+
+// ExpireExports mocks base method.
+func (m *MockExportRepository) ExpireExports(ctx context.Context) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireExports", ctx)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireExports indicates an expected call of ExpireExports.
+func (mr *MockExportRepositoryMockRecorder) ExpireExports(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireExports", reflect.TypeOf((*MockExportRepository)(nil).ExpireExports), ctx)
+}
+
+// FailExport mocks base method.
+func (m *MockExportRepository) FailExport(ctx context.Context, id, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailExport", ctx, id, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FailExport indicates an expected call of FailExport.
+func (mr *MockExportRepositoryMockRecorder) FailExport(ctx, id, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailExport", reflect.TypeOf((*MockExportRepository)(nil).FailExport), ctx, id, reason)
+}
+
+// FinishExport mocks base method.
+func (m *MockExportRepository) FinishExport(ctx context.Context, id string, expiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishExport", ctx, id, expiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FinishExport indicates an expected call of FinishExport.
+func (mr *MockExportRepositoryMockRecorder) FinishExport(ctx, id, expiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishExport", reflect.TypeOf((*MockExportRepository)(nil).FinishExport), ctx, id, expiresAt)
+}
+
+// GetExport mocks base method.
+func (m *MockExportRepository) GetExport(ctx context.Context, id string) (*domain.DataExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExport", ctx, id)
+	ret0, _ := ret[0].(*domain.DataExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExport indicates an expected call of GetExport.
+func (mr *MockExportRepositoryMockRecorder) GetExport(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExport", reflect.TypeOf((*MockExportRepository)(nil).GetExport), ctx, id)
+}
+
+// GetLatestExport mocks base method.
+func (m *MockExportRepository) GetLatestExport(ctx context.Context, userID string) (*domain.DataExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLatestExport", ctx, userID)
+	ret0, _ := ret[0].(*domain.DataExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLatestExport indicates an expected call of GetLatestExport.
+func (mr *MockExportRepositoryMockRecorder) GetLatestExport(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestExport", reflect.TypeOf((*MockExportRepository)(nil).GetLatestExport), ctx, userID)
+}
+
+// GetPersonalData mocks base method.
+func (m *MockExportRepository) GetPersonalData(ctx context.Context, userID string) (*domain.PersonalData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPersonalData", ctx, userID)
+	ret0, _ := ret[0].(*domain.PersonalData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPersonalData indicates an expected call of GetPersonalData.
+func (mr *MockExportRepositoryMockRecorder) GetPersonalData(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPersonalData", reflect.TypeOf((*MockExportRepository)(nil).GetPersonalData), ctx, userID)
+}
+
+// MockURLSigner is a mock of URLSigner interface.
+type MockURLSigner struct {
+	ctrl     *gomock.Controller
+	recorder *MockURLSignerMockRecorder
+}
+
+// MockURLSignerMockRecorder is the mock recorder for MockURLSigner.
+type MockURLSignerMockRecorder struct {
+	mock *MockURLSigner
+}
+
+// NewMockURLSigner creates a new mock instance.
+func NewMockURLSigner(ctrl *gomock.Controller) *MockURLSigner {
+	mock := &MockURLSigner{ctrl: ctrl}
+	mock.recorder = &MockURLSignerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockURLSigner) EXPECT() *MockURLSignerMockRecorder {
+	return m.recorder
+}
+
+// Sign mocks base method.
+func (m *MockURLSigner) Sign(path string, expires time.Time) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Sign", path, expires)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Sign indicates an expected call of Sign.
+func (mr *MockURLSignerMockRecorder) Sign(path, expires interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sign", reflect.TypeOf((*MockURLSigner)(nil).Sign), path, expires)
+}
+
+// Verify mocks base method.
+func (m *MockURLSigner) Verify(u *url.URL) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Verify", u)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Verify indicates an expected call of Verify.
+func (mr *MockURLSignerMockRecorder) Verify(u interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*MockURLSigner)(nil).Verify), u)
+}
+
 // MockAvatarStorage is a mock of AvatarStorage interface.
 type MockAvatarStorage struct {
 	ctrl     *gomock.Controller
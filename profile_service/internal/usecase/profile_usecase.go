@@ -5,6 +5,7 @@ import (
 	"context"
 	"strings"
 
+	"apple_backend/pkg/geo"
 	"apple_backend/profile_service/internal/domain"
 
 	"github.com/google/uuid"
@@ -46,6 +47,12 @@ func (uc *ProfileUsecase) UpdateProfile(ctx context.Context, in *domain.Profile)
 	if in.Address != nil && len(*in.Address) > 200 {
 		return domain.ErrInvalidProfileData
 	}
+	if (in.Lat == nil) != (in.Lon == nil) {
+		return domain.ErrInvalidProfileData
+	}
+	if in.Lat != nil && !(geo.Point{Lat: *in.Lat, Lon: *in.Lon}).Valid() {
+		return domain.ErrInvalidProfileData
+	}
 	if in.Name != nil {
 		existing.Name = in.Name
 	}
@@ -57,6 +64,11 @@ func (uc *ProfileUsecase) UpdateProfile(ctx context.Context, in *domain.Profile)
 	}
 	if in.Address != nil {
 		existing.Address = in.Address
+		// координаты относятся к адресу: без новых прежние уже неверны
+		existing.Lat, existing.Lon = nil, nil
+	}
+	if in.Lat != nil {
+		existing.Lat, existing.Lon = in.Lat, in.Lon
 	}
 
 	return uc.repo.UpdateProfile(ctx, existing)
@@ -14,6 +14,8 @@ import (
 
 func stringPtr(s string) *string { return &s }
 
+func floatPtr(f float64) *float64 { return &f }
+
 func TestProfileUsecase_GetProfile(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -222,6 +224,68 @@ func TestProfileUsecase_UpdateProfile(t *testing.T) {
 			expectError: true,
 			errorType:   domain.ErrInvalidProfileData,
 		},
+		{
+			name: "Координаты адреса",
+			updateProfile: &domain.Profile{
+				ID:  "550e8400-e29b-41d4-a716-446655440000",
+				Lat: floatPtr(55.7558),
+				Lon: floatPtr(37.6173),
+			},
+			setupMock: func() {
+				mockRepo.EXPECT().GetProfile(gomock.Any(), gomock.Any()).Return(newExisting(), nil)
+				mockRepo.EXPECT().UpdateProfile(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, p *domain.Profile) error {
+					if p.Lat == nil || *p.Lat != 55.7558 || p.Lon == nil || *p.Lon != 37.6173 {
+						return errors.New("coordinates not saved")
+					}
+					return nil
+				})
+			},
+			expectError: false,
+		},
+		{
+			name: "Новый адрес без координат сбрасывает прежние",
+			updateProfile: &domain.Profile{
+				ID:      "550e8400-e29b-41d4-a716-446655440000",
+				Address: stringPtr("New Address"),
+			},
+			setupMock: func() {
+				existing := newExisting()
+				existing.Lat, existing.Lon = floatPtr(55.7558), floatPtr(37.6173)
+				mockRepo.EXPECT().GetProfile(gomock.Any(), gomock.Any()).Return(existing, nil)
+				mockRepo.EXPECT().UpdateProfile(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, p *domain.Profile) error {
+					if p.Lat != nil || p.Lon != nil {
+						return errors.New("stale coordinates kept")
+					}
+					return nil
+				})
+			},
+			expectError: false,
+		},
+		{
+			name: "Валидация: только широта",
+			updateProfile: &domain.Profile{
+				ID:  "550e8400-e29b-41d4-a716-446655440000",
+				Lat: floatPtr(55.7558),
+			},
+			setupMock: func() {
+				mockRepo.EXPECT().GetProfile(gomock.Any(), gomock.Any()).Return(newExisting(), nil)
+			},
+			expectError: true,
+			errorType:   domain.ErrInvalidProfileData,
+		},
+		{
+			name: "Валидация: широта вне диапазона",
+			updateProfile: &domain.Profile{
+				ID:  "550e8400-e29b-41d4-a716-446655440000",
+				Lat: floatPtr(95),
+				Lon: floatPtr(37.6173),
+			},
+			setupMock: func() {
+				mockRepo.EXPECT().GetProfile(gomock.Any(), gomock.Any()).Return(newExisting(), nil)
+			},
+			expectError: true,
+			errorType:   domain.ErrInvalidProfileData,
+		},
 		{
 			name: "Ошибка репозитория при UpdateProfile",
 			updateProfile: &domain.Profile{
@@ -1,19 +1,63 @@
 package cmd
 
 import (
+	"apple_backend/pkg/apikey"
+	"apple_backend/pkg/csrf"
+	"apple_backend/pkg/guestcart"
+	"apple_backend/pkg/jwtkeys"
 	"apple_backend/pkg/logger"
+	"apple_backend/pkg/rbac"
+	"apple_backend/pkg/session"
 	"apple_backend/store_service/internal/config"
 	shttp "apple_backend/store_service/internal/delivery/http"
 	"apple_backend/store_service/internal/delivery/middlewares"
+	"apple_backend/store_service/internal/repository"
+	"apple_backend/store_service/internal/usecase"
 	"context"
 	"fmt"
 	"log"
 	"net/http"
 	"path/filepath"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// newAPIKeyGuard — маршруты, доступные интеграциям по API-ключу. Кассе
+// партнера нужны заказы его магазинов, а не личные заказы владельца ключа
+func newAPIKeyGuard(verifier apikey.Verifier, apiPrefix string) *apikey.Guard {
+	guard := apikey.NewGuard(verifier)
+	guard.Allow("GET "+apiPrefix+"stores/{id}/orders", rbac.ScopeOrdersRead)
+	guard.Allow("GET "+apiPrefix+"stores/{id}/menu", rbac.ScopeMenuRead)
+	guard.Allow("POST "+apiPrefix+"stores/{id}/menu", rbac.ScopeMenuWrite)
+	guard.Allow("PATCH "+apiPrefix+"stores/{id}/menu/{item_id}", rbac.ScopeMenuWrite)
+	guard.Allow("DELETE "+apiPrefix+"stores/{id}/menu/{item_id}", rbac.ScopeMenuWrite)
+	return guard
+}
+
+// expireGuestCarts раз в час удаляет гостевые корзины, срок которых истек
+func expireGuestCarts(uc *usecase.CartUsecase) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+	for range ticker.C {
+		if _, err := uc.ExpireGuestCarts(context.Background()); err != nil {
+			log.Println("guest carts cleanup failed:", err)
+		}
+	}
+}
+
+// refreshSuggestions раз в несколько секунд сверяет версию каталога и
+// перестраивает индекс подсказок, если каталог изменился
+func refreshSuggestions(uc *usecase.SuggestUsecase) {
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+	for range ticker.C {
+		if _, err := uc.Refresh(context.Background()); err != nil {
+			log.Println("suggest index refresh failed:", err)
+		}
+	}
+}
+
 func Run() {
 	conf := config.MustConfig()
 	apiV0Prefix := "/api/v0/"
@@ -24,19 +68,35 @@ func Run() {
 	}
 	defer dbPool.Close()
 
+	go expireGuestCarts(usecase.NewCartUsecase(repository.NewCartRepoPostgres(dbPool)))
+
+	// без индекса подсказки пусты, но сервис работает: фоновая задача
+	// соберет индекс, когда бд станет доступна
+	suggestUC := usecase.NewSuggestUsecase(repository.NewSuggestRepoPostgres(dbPool))
+	if _, err := suggestUC.Refresh(context.Background()); err != nil {
+		log.Println("suggest index build failed:", err)
+	}
+	go refreshSuggestions(suggestUC)
+
 	openMux := http.NewServeMux()
 	protectedMux := http.NewServeMux()
+	guestMux := http.NewServeMux()
 
 	// все роутеры без передачи логгера
 	shttp.NewStoreRouter(openMux, dbPool, apiV0Prefix)
 	shttp.NewItemRouter(openMux, dbPool, apiV0Prefix)
+	shttp.NewSearchRouter(openMux, dbPool, apiV0Prefix)
+	shttp.NewSuggestRouter(openMux, suggestUC, apiV0Prefix)
 	shttp.NewCartRouter(protectedMux, dbPool, apiV0Prefix)
-	shttp.NewOrderRouter(protectedMux, dbPool, apiV0Prefix)
+	shttp.NewGuestCartRouter(guestMux, dbPool, apiV0Prefix, guestcart.New(conf.GuestCartSecret))
+	shttp.NewOrderRouter(protectedMux, dbPool, apiV0Prefix, conf.RequireVerifiedEmail)
+	shttp.NewStoreManageRouter(protectedMux, dbPool, apiV0Prefix, conf.UploadStoreDir)
+	shttp.NewMenuRouter(protectedMux, dbPool, apiV0Prefix)
 
 	paymentHandler := shttp.NewPaymentHandler()
 	openMux.HandleFunc(apiV0Prefix+"fake-payment", paymentHandler.FakePayment)
 
-	protectedHandler := middlewares.AuthMiddleware(protectedMux, conf.JWTSecret)
+	protectedHandler := middlewares.AuthMiddleware(protectedMux, jwtkeys.NewRemoteKeySet(conf.JWKSURL, 0).Keyfunc, session.NewPostgresChecker(dbPool), newAPIKeyGuard(apikey.NewPostgresVerifier(dbPool), apiV0Prefix))
 
 	mux := http.NewServeMux()
 
@@ -61,15 +121,29 @@ func Run() {
 	})))
 
 	// маршрутизация API
-	mux.Handle(apiV0Prefix+"cart", protectedHandler)
+	// без входа корзина гостевая, при входе auth_service сливает ее с корзиной пользователя
+	mux.Handle(apiV0Prefix+"cart", middlewares.GuestOrAuth(protectedHandler, guestMux))
 	mux.Handle(apiV0Prefix+"orders", protectedHandler)
 	mux.Handle(apiV0Prefix+"orders/", protectedHandler)
+	// чтение магазинов открыто, изменение только после входа
+	mux.Handle("POST "+apiV0Prefix+"stores", protectedHandler)
+	mux.Handle("PATCH "+apiV0Prefix+"stores/{id}", protectedHandler)
+	mux.Handle("DELETE "+apiV0Prefix+"stores/{id}", protectedHandler)
+	mux.Handle("PUT "+apiV0Prefix+"stores/{id}/image", protectedHandler)
+	mux.Handle("DELETE "+apiV0Prefix+"stores/{id}/image", protectedHandler)
+	mux.Handle("GET "+apiV0Prefix+"stores/{id}/orders", protectedHandler)
+	mux.Handle("GET "+apiV0Prefix+"stores/{id}/menu", protectedHandler)
+	mux.Handle("POST "+apiV0Prefix+"stores/{id}/menu", protectedHandler)
+	mux.Handle("PATCH "+apiV0Prefix+"stores/{id}/menu/{item_id}", protectedHandler)
+	mux.Handle("DELETE "+apiV0Prefix+"stores/{id}/menu/{item_id}", protectedHandler)
 	mux.Handle(apiV0Prefix, openMux)
 
 	// middleware цепочка
 	handler := middlewares.AccessLog(
 		logger.Global(),
-		middlewares.CorsMiddleware(mux),
+		middlewares.CorsMiddleware(
+			csrf.New(conf.CSRFSecret, csrf.OptionsFromEnv()).Middleware(mux),
+		),
 	)
 
 	addr := fmt.Sprintf("0.0.0.0:%s", conf.AppPort)
@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"apple_backend/pkg/apikey"
+	"apple_backend/pkg/rbac"
+	shttp "apple_backend/store_service/internal/delivery/http"
+	"apple_backend/store_service/internal/delivery/middlewares"
+	"apple_backend/store_service/internal/delivery/transport"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pashagolub/pgxmock/v2"
+	"github.com/stretchr/testify/require"
+)
+
+type verifierFunc func(ctx context.Context, token string) (*apikey.Key, error)
+
+func (f verifierFunc) Verify(ctx context.Context, token string) (*apikey.Key, error) {
+	return f(ctx, token)
+}
+
+func TestAPIKeyGuard_PartnerListsStoreOrders(t *testing.T) {
+	const (
+		apiPrefix  = "/api/v0/"
+		partnerKey = "dck_0123456789ab_partner"
+		partnerID  = "00000000-0000-0000-0000-000000000020"
+		storeID    = "00000000-0000-0000-0000-000000000010"
+		foreignID  = "00000000-0000-0000-0000-000000000011"
+		// заказ оформил покупатель, а не партнер
+		customerOrderID = "00000000-0000-0000-0000-000000000030"
+	)
+
+	db, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer db.Close()
+
+	protectedMux := http.NewServeMux()
+	shttp.NewOrderRouter(protectedMux, db, apiPrefix, false)
+	guard := newAPIKeyGuard(verifierFunc(func(_ context.Context, token string) (*apikey.Key, error) {
+		if token != partnerKey {
+			return nil, apikey.ErrInvalidKey
+		}
+		return &apikey.Key{
+			ID:     "k1",
+			UserID: partnerID,
+			Scopes: []string{rbac.ScopeOrdersRead},
+			Roles:  []string{rbac.RoleStoreOwner},
+		}, nil
+	}), apiPrefix)
+	handler := middlewares.AuthMiddleware(protectedMux, nil, nil, guard)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Authorization", "Bearer "+partnerKey)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	ownerColumns := []string{"exists", "owner"}
+	db.ExpectQuery(`from store_owner where store_id = \$1 and user_id = \$2`).
+		WithArgs(storeID, partnerID).
+		WillReturnRows(pgxmock.NewRows(ownerColumns).AddRow(true, true))
+	db.ExpectQuery(`AND si.store_id = \$1`).
+		WithArgs(storeID, "", 10).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "status", "total", "created_at"}).
+			AddRow(customerOrderID, "paid", 99.9, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)))
+
+	w := get(apiPrefix + "stores/" + storeID + "/orders?limit=10")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var orders []*transport.Order
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &orders))
+	require.Len(t, orders, 1)
+	require.Equal(t, customerOrderID, orders[0].ID)
+
+	// магазин другого владельца
+	db.ExpectQuery(`from store_owner where store_id = \$1 and user_id = \$2`).
+		WithArgs(foreignID, partnerID).
+		WillReturnRows(pgxmock.NewRows(ownerColumns).AddRow(true, false))
+	w = get(apiPrefix + "stores/" + foreignID + "/orders?limit=10")
+	require.Equal(t, http.StatusForbidden, w.Code)
+
+	// личные заказы владельца ключа интеграции не нужны и закрыты
+	w = get(apiPrefix + "orders?limit=10")
+	require.Equal(t, http.StatusForbidden, w.Code)
+
+	require.NoError(t, db.ExpectationsWereMet())
+}
+
+func TestAPIKeyGuard_MenuReadScope(t *testing.T) {
+	const (
+		apiPrefix = "/api/v0/"
+		readKey   = "dck_0123456789ab_reader"
+		writeKey  = "dck_ba9876543210_writer"
+		partnerID = "00000000-0000-0000-0000-000000000020"
+		storeID   = "00000000-0000-0000-0000-000000000010"
+	)
+
+	db, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer db.Close()
+
+	protectedMux := http.NewServeMux()
+	shttp.NewMenuRouter(protectedMux, db, apiPrefix)
+	keys := map[string][]string{
+		readKey:  {rbac.ScopeMenuRead},
+		writeKey: {rbac.ScopeMenuWrite},
+	}
+	guard := newAPIKeyGuard(verifierFunc(func(_ context.Context, token string) (*apikey.Key, error) {
+		scopes, ok := keys[token]
+		if !ok {
+			return nil, apikey.ErrInvalidKey
+		}
+		return &apikey.Key{ID: "k1", UserID: partnerID, Scopes: scopes, Roles: []string{rbac.RoleStoreOwner}}, nil
+	}), apiPrefix)
+	handler := middlewares.AuthMiddleware(protectedMux, nil, nil, guard)
+
+	get := func(key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, apiPrefix+"stores/"+storeID+"/menu", nil)
+		req.Header.Set("Authorization", "Bearer "+key)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	db.ExpectQuery(`from store_owner where store_id = \$1 and user_id = \$2`).
+		WithArgs(storeID, partnerID).
+		WillReturnRows(pgxmock.NewRows([]string{"exists", "owner"}).AddRow(true, true))
+	db.ExpectQuery(`where si.store_id = \$1`).
+		WithArgs(storeID).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "item_id", "name", "description", "card_img", "price", "types", "archived", "updated_at"}))
+
+	w := get(readKey)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	// чтение меню требует своего scope, menu:write его не открывает
+	w = get(writeKey)
+	require.Equal(t, http.StatusForbidden, w.Code)
+
+	require.NoError(t, db.ExpectationsWereMet())
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/go-playground/validator/v10"
 )
@@ -16,11 +17,21 @@ type Config struct {
 
 	AppPort string `validate:"required"`
 
-	JWTSecret string `validate:"required"`
+	// JWKSURL — адрес публичных ключей auth_service для проверки JWT
+	JWKSURL string `validate:"required,url"`
 
 	UploadStoreDir string `validate:"required"`
 
 	UploadItemDir string `validate:"required"`
+
+	// RequireVerifiedEmail запрещает оформление заказа без подтвержденного email
+	RequireVerifiedEmail bool
+
+	// CSRFSecret — общий для всех сервисов ключ подписи CSRF-токенов
+	CSRFSecret string `validate:"required"`
+
+	// GuestCartSecret — общий с auth_service ключ подписи cookie гостевой корзины
+	GuestCartSecret string `validate:"required"`
 }
 
 func MustConfig() *Config {
@@ -31,9 +42,13 @@ func MustConfig() *Config {
 		DBPort:         os.Getenv("API_DB_PORT"),
 		DBName:         os.Getenv("DB_NAME"),
 		AppPort:        os.Getenv("STORE_SERVICE_PORT"),
-		JWTSecret:      os.Getenv("SECRET_KEY"),
+		JWKSURL:        os.Getenv("JWKS_URL"),
 		UploadStoreDir: os.Getenv("UPLOAD_STORE_DIR"),
 		UploadItemDir:  os.Getenv("UPLOAD_ITEM_DIR"),
+
+		RequireVerifiedEmail: strings.EqualFold(os.Getenv("REQUIRE_VERIFIED_EMAIL"), "true"),
+		CSRFSecret:           os.Getenv("CSRF_SECRET"),
+		GuestCartSecret:      os.Getenv("GUEST_CART_SECRET"),
 	}
 
 	if err := validator.New().Struct(conf); err != nil {
@@ -2,7 +2,6 @@ package http
 
 import (
 	"apple_backend/pkg/http_response"
-	"apple_backend/pkg/logger"
 	"apple_backend/store_service/internal/delivery/middlewares"
 	"apple_backend/store_service/internal/delivery/mock"
 	"apple_backend/store_service/internal/delivery/transport"
@@ -45,14 +44,14 @@ func TestCartHandler_GetCart(t *testing.T) {
 		Name:     name1,
 		Price:    price1,
 		Quantity: quantity1,
-		CardImg:  cardImg1,
+		CardImg:  "/images/items/" + cardImg1,
 	}
 	item2 := &transport.CartItem{
 		ID:       uid2,
 		Name:     name2,
 		Price:    price2,
 		Quantity: quantity2,
-		CardImg:  cardImg2,
+		CardImg:  "/images/items/" + cardImg2,
 	}
 
 	itemUC1 := &domain.CartItem{
@@ -174,7 +173,7 @@ func TestCartHandler_GetCart(t *testing.T) {
 			defer ctrl.Finish()
 
 			uc := mock.NewMockCartUsecaseInterface(ctrl)
-			handler := NewCartHandler(uc, logger.NewNilLogger())
+			handler := NewCartHandler(uc)
 
 			req := tt.mockSetup(uc, tt.method, tt.id)
 			w := httptest.NewRecorder()
@@ -336,7 +335,7 @@ func TestCartHandler_UpdateCart(t *testing.T) {
 			defer ctrl.Finish()
 
 			uc := mock.NewMockCartUsecaseInterface(ctrl)
-			handler := NewCartHandler(uc, logger.NewNilLogger())
+			handler := NewCartHandler(uc)
 
 			req := tt.mockSetup(uc, tt.method, tt.id, tt.body)
 
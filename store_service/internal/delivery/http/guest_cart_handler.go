@@ -0,0 +1,149 @@
+package http
+
+import (
+	"apple_backend/pkg/guestcart"
+	"apple_backend/pkg/http_response"
+	"apple_backend/pkg/logger"
+	"apple_backend/store_service/internal/delivery/transport"
+	"apple_backend/store_service/internal/domain"
+	"apple_backend/store_service/internal/repository"
+	"apple_backend/store_service/internal/usecase"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type GuestCartUsecaseInterface interface {
+	GetGuestCart(ctx context.Context, cartID string) (*domain.Cart, error)
+	UpdateGuestCart(ctx context.Context, cartID string, updateCart *domain.CartUpdate) (string, error)
+}
+
+// GuestCartHandler обслуживает корзину посетителя без аккаунта. Корзина
+// определяется подписанной cookie и переносится в корзину пользователя
+// auth_service при входе.
+type GuestCartHandler struct {
+	uc        GuestCartUsecaseInterface
+	cookies   *guestcart.Cookies
+	rs        *http_response.ResponseSender
+	validator *validator.Validate
+}
+
+func NewGuestCartHandler(uc GuestCartUsecaseInterface, cookies *guestcart.Cookies) *GuestCartHandler {
+	return &GuestCartHandler{
+		uc:        uc,
+		cookies:   cookies,
+		rs:        http_response.NewResponseSender(logger.Global()),
+		validator: validator.New(),
+	}
+}
+
+func NewGuestCartRouter(mux *http.ServeMux, db repository.PgxIface, apiPrefix string, cookies *guestcart.Cookies) {
+	cartRepo := repository.NewCartRepoPostgres(db)
+	cartUC := usecase.NewCartUsecase(cartRepo)
+	cartHandler := NewGuestCartHandler(cartUC, cookies)
+
+	mux.HandleFunc(apiPrefix+"cart", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			cartHandler.GetGuestCart(w, r)
+		case http.MethodPut:
+			cartHandler.UpdateGuestCart(w, r)
+		default:
+			ctx := r.Context()
+			log := logger.FromContext(ctx)
+			log.WarnContext(ctx, "handler guest cart wrong method", slog.String("method", r.Method))
+			cartHandler.rs.Error(ctx, w, http.StatusMethodNotAllowed, "cart", domain.ErrHTTPMethod, nil)
+		}
+	})
+}
+
+// cartID возвращает id корзины из cookie; поддельная cookie считается
+// отсутствующей
+func (h *GuestCartHandler) cartID(r *http.Request) string {
+	id, err := h.cookies.CartID(r)
+	if err != nil {
+		return ""
+	}
+	return id
+}
+
+// GetGuestCart godoc
+// @Summary Гостевая корзина
+// @Description Корзина посетителя без входа, определяется cookie guest_cart. Без cookie возвращается пустая корзина.
+// @Tags cart
+// @Produce json
+// @Success 200 {object} transport.Cart
+// @Failure 500 {object} http_response.ErrResponse "Внутренняя ошибка сервера"
+// @Router /cart [get]
+func (h *GuestCartHandler) GetGuestCart(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "handler GetGuestCart start")
+
+	cartID := h.cartID(r)
+	cart, err := h.uc.GetGuestCart(ctx, cartID)
+	if err != nil {
+		log.ErrorContext(ctx, "handler GetGuestCart usecase failed", slog.Any("err", err), slog.String("cart_id", cartID))
+		h.rs.Error(ctx, w, http.StatusInternalServerError, "GetGuestCart", domain.ErrInternalServer, err)
+		return
+	}
+
+	log.InfoContext(ctx, "handler GetGuestCart success",
+		slog.String("cart_id", cartID),
+		slog.Int("items_count", len(cart.Items)))
+	h.rs.Send(ctx, w, http.StatusOK, transport.ToCartResponse(cart))
+}
+
+// UpdateGuestCart godoc
+// @Summary Изменить гостевую корзину
+// @Description Заменяет содержимое гостевой корзины. Первая запись создает корзину и выставляет cookie guest_cart.
+// @Tags cart
+// @Accept json
+// @Param cart body transport.CartUpdate true "Новое содержимое корзины"
+// @Success 204 "Корзина обновлена"
+// @Failure 400 {object} http_response.ErrResponse "Ошибка входных данных"
+// @Failure 404 {object} http_response.ErrResponse "Товар не найден"
+// @Failure 500 {object} http_response.ErrResponse "Внутренняя ошибка сервера"
+// @Router /cart [put]
+func (h *GuestCartHandler) UpdateGuestCart(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "handler UpdateGuestCart start")
+
+	cartUpdate := &transport.CartUpdate{}
+	if err := json.NewDecoder(r.Body).Decode(cartUpdate); err != nil {
+		log.ErrorContext(ctx, "handler UpdateGuestCart decode failed", slog.Any("err", err))
+		h.rs.Error(ctx, w, http.StatusBadRequest, "UpdateGuestCart", domain.ErrRequestParams, err)
+		return
+	}
+
+	if err := h.validator.Struct(cartUpdate); err != nil {
+		log.WarnContext(ctx, "handler UpdateGuestCart validation failed", slog.Any("err", err))
+		h.rs.Error(ctx, w, http.StatusBadRequest, "UpdateGuestCart", domain.ErrRequestParams, err)
+		return
+	}
+
+	cartID, err := h.uc.UpdateGuestCart(ctx, h.cartID(r), transport.FromCartUpdate(cartUpdate))
+	if err != nil {
+		log.ErrorContext(ctx, "handler UpdateGuestCart usecase failed", slog.Any("err", err))
+		switch {
+		case errors.Is(err, domain.ErrRequestParams), errors.Is(err, domain.ErrInvalidQuantity):
+			h.rs.Error(ctx, w, http.StatusBadRequest, "UpdateGuestCart", domain.ErrRequestParams, err)
+		case errors.Is(err, domain.ErrRowsNotFound):
+			h.rs.Error(ctx, w, http.StatusNotFound, "UpdateGuestCart", domain.ErrRowsNotFound, err)
+		default:
+			h.rs.Error(ctx, w, http.StatusInternalServerError, "UpdateGuestCart", domain.ErrInternalServer, err)
+		}
+		return
+	}
+
+	h.cookies.Set(w, cartID)
+	log.InfoContext(ctx, "handler UpdateGuestCart success",
+		slog.String("cart_id", cartID),
+		slog.Int("items_count", len(cartUpdate.Items)))
+	w.WriteHeader(http.StatusNoContent)
+}
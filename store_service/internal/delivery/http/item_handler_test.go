@@ -2,7 +2,6 @@ package http
 
 import (
 	"apple_backend/pkg/http_response"
-	"apple_backend/pkg/logger"
 	"apple_backend/store_service/internal/delivery/mock"
 	"apple_backend/store_service/internal/delivery/transport"
 	"apple_backend/store_service/internal/domain"
@@ -107,7 +106,7 @@ func TestItemHandler_GetItemTypes(t *testing.T) {
 	defer ctrl.Finish()
 
 	uc := mock.NewMockItemUsecaseInterface(ctrl)
-	handler := NewItemHandler(uc, logger.NewNilLogger())
+	handler := NewItemHandler(uc)
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -169,7 +168,7 @@ func TestItemHandler_GetItems(t *testing.T) {
 		Name:        "name1",
 		Price:       1,
 		Description: "description1",
-		CardImg:     "card_img1",
+		CardImg:     "/images/items/card_img1",
 		TypesID:     []string{"type1"},
 	}
 	itemResp2 := &transport.Item{
@@ -177,7 +176,7 @@ func TestItemHandler_GetItems(t *testing.T) {
 		Name:        "name2",
 		Price:       2,
 		Description: "description2",
-		CardImg:     "card_img2",
+		CardImg:     "/images/items/card_img2",
 		TypesID:     []string{"type2"},
 	}
 
@@ -240,7 +239,7 @@ func TestItemHandler_GetItems(t *testing.T) {
 	defer ctrl.Finish()
 
 	uc := mock.NewMockItemUsecaseInterface(ctrl)
-	handler := NewItemHandler(uc, logger.NewNilLogger())
+	handler := NewItemHandler(uc)
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,250 @@
+package http
+
+import (
+	"apple_backend/pkg/http_response"
+	"apple_backend/pkg/logger"
+	"apple_backend/pkg/rbac"
+	"apple_backend/store_service/internal/delivery/middlewares"
+	"apple_backend/store_service/internal/delivery/transport"
+	"apple_backend/store_service/internal/domain"
+	"apple_backend/store_service/internal/repository"
+	"apple_backend/store_service/internal/usecase"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+type MenuUsecaseInterface interface {
+	GetMenu(ctx context.Context, actor domain.Actor, storeID string) ([]*domain.MenuItem, error)
+	CreateMenuItem(ctx context.Context, actor domain.Actor, storeID string, in *domain.MenuItemInput) (*domain.MenuItem, error)
+	UpdateMenuItem(ctx context.Context, actor domain.Actor, storeID, id string, in *domain.MenuItemInput) (*domain.MenuItem, error)
+	ArchiveMenuItem(ctx context.Context, actor domain.Actor, storeID, id string, version time.Time) error
+}
+
+type MenuHandler struct {
+	uc MenuUsecaseInterface
+	rs *http_response.ResponseSender
+}
+
+func NewMenuHandler(uc MenuUsecaseInterface) *MenuHandler {
+	return &MenuHandler{
+		uc: uc,
+		rs: http_response.NewResponseSender(logger.Global()),
+	}
+}
+
+// NewMenuRouter регистрирует изменение меню; mux должен стоять за
+// AuthMiddleware
+func NewMenuRouter(mux *http.ServeMux, db repository.PgxIface, apiPrefix string) {
+	menuRepo := repository.NewMenuRepoPostgres(db)
+	storeRepo := repository.NewStoreRepoPostgres(db)
+	menuUC := usecase.NewMenuUsecase(menuRepo, storeRepo)
+	h := NewMenuHandler(menuUC)
+
+	write := middlewares.RequirePermission(rbac.PermMenuWrite)
+	mux.Handle("GET "+apiPrefix+"stores/{id}/menu", write(http.HandlerFunc(h.GetMenu)))
+	mux.Handle("POST "+apiPrefix+"stores/{id}/menu", write(http.HandlerFunc(h.CreateMenuItem)))
+	mux.Handle("PATCH "+apiPrefix+"stores/{id}/menu/{item_id}", write(http.HandlerFunc(h.UpdateMenuItem)))
+	mux.Handle("DELETE "+apiPrefix+"stores/{id}/menu/{item_id}", write(http.HandlerFunc(h.ArchiveMenuItem)))
+}
+
+// menuError отвечает на ошибку изменения меню
+func (h *MenuHandler) menuError(ctx context.Context, w http.ResponseWriter, op string, err error) {
+	switch {
+	case errors.Is(err, domain.ErrItemName),
+		errors.Is(err, domain.ErrItemDescription),
+		errors.Is(err, domain.ErrItemPrice),
+		errors.Is(err, domain.ErrTypeNotFound):
+		h.rs.Error(ctx, w, http.StatusBadRequest, op, err, nil)
+	case errors.Is(err, domain.ErrRequestParams):
+		h.rs.Error(ctx, w, http.StatusBadRequest, op, domain.ErrRequestParams, err)
+	case errors.Is(err, domain.ErrForbidden):
+		h.rs.Error(ctx, w, http.StatusForbidden, op, err, nil)
+	case errors.Is(err, domain.ErrRowsNotFound):
+		h.rs.Error(ctx, w, http.StatusNotFound, op, err, nil)
+	case errors.Is(err, domain.ErrMenuItemExist), errors.Is(err, domain.ErrMenuConflict):
+		h.rs.Error(ctx, w, http.StatusConflict, op, err, nil)
+	default:
+		h.rs.Error(ctx, w, http.StatusInternalServerError, op, domain.ErrInternalServer, err)
+	}
+}
+
+// GetMenu godoc
+// @Summary Меню магазина для владельца
+// @Description Все позиции магазина, включая архивные, с версией updated_at для изменения. Нужно разрешение menu:write, API-ключу — scope menu:read.
+// @Tags menu
+// @Produce json
+// @Param id path string true "ID магазина"
+// @Success 200 {array} transport.MenuItem
+// @Failure 400 {object} http_response.ErrResponse "Неверный ID"
+// @Failure 403 {object} http_response.ErrResponse "Магазин принадлежит другому владельцу"
+// @Failure 404 {object} http_response.ErrResponse "Магазин не найден"
+// @Failure 500 {object} http_response.ErrResponse "Внутренняя ошибка сервера"
+// @Router /stores/{id}/menu [get]
+func (h *MenuHandler) GetMenu(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+	storeID := r.PathValue("id")
+	log.InfoContext(ctx, "handler GetMenu start", slog.String("store_id", storeID))
+
+	actor, ok := actorFromContext(ctx)
+	if !ok {
+		log.WarnContext(ctx, "handler GetMenu unauthorized")
+		h.rs.Error(ctx, w, http.StatusUnauthorized, "GetMenu", domain.ErrUnauthorized, nil)
+		return
+	}
+
+	items, err := h.uc.GetMenu(ctx, actor, storeID)
+	if err != nil {
+		log.WarnContext(ctx, "handler GetMenu usecase failed", slog.Any("err", err), slog.String("store_id", storeID))
+		h.menuError(ctx, w, "GetMenu", err)
+		return
+	}
+
+	log.InfoContext(ctx, "handler GetMenu success",
+		slog.String("store_id", storeID),
+		slog.Int("items_count", len(items)))
+	h.rs.Send(ctx, w, http.StatusOK, transport.ToMenuResponse(items))
+}
+
+// CreateMenuItem godoc
+// @Summary Добавить позицию в меню
+// @Description Создает товар с типами и ценой или добавляет в меню существующий товар по item_id. Архивная позиция того же товара возвращается в продажу.
+// @Tags menu
+// @Accept json
+// @Produce json
+// @Param id path string true "ID магазина"
+// @Param item body transport.MenuItemRequest true "Новая позиция"
+// @Success 201 {object} transport.MenuItem
+// @Failure 400 {object} http_response.ErrResponse "Ошибка входных данных"
+// @Failure 403 {object} http_response.ErrResponse "Магазин принадлежит другому владельцу"
+// @Failure 404 {object} http_response.ErrResponse "Магазин или товар не найден"
+// @Failure 409 {object} http_response.ErrResponse "Товар уже есть в меню"
+// @Failure 500 {object} http_response.ErrResponse "Внутренняя ошибка сервера"
+// @Router /stores/{id}/menu [post]
+func (h *MenuHandler) CreateMenuItem(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+	storeID := r.PathValue("id")
+	log.InfoContext(ctx, "handler CreateMenuItem start", slog.String("store_id", storeID))
+
+	actor, ok := actorFromContext(ctx)
+	if !ok {
+		log.WarnContext(ctx, "handler CreateMenuItem unauthorized")
+		h.rs.Error(ctx, w, http.StatusUnauthorized, "CreateMenuItem", domain.ErrUnauthorized, nil)
+		return
+	}
+
+	req := &transport.MenuItemRequest{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		log.WarnContext(ctx, "handler CreateMenuItem decode failed", slog.Any("err", err))
+		h.rs.Error(ctx, w, http.StatusBadRequest, "CreateMenuItem", domain.ErrRequestParams, err)
+		return
+	}
+
+	item, err := h.uc.CreateMenuItem(ctx, actor, storeID, transport.FromMenuItemRequest(req))
+	if err != nil {
+		log.WarnContext(ctx, "handler CreateMenuItem usecase failed", slog.Any("err", err), slog.String("store_id", storeID))
+		h.menuError(ctx, w, "CreateMenuItem", err)
+		return
+	}
+
+	log.InfoContext(ctx, "handler CreateMenuItem success",
+		slog.String("store_id", storeID),
+		slog.String("store_item_id", item.ID))
+	h.rs.Send(ctx, w, http.StatusCreated, transport.ToMenuItemResponse(item))
+}
+
+// UpdateMenuItem godoc
+// @Summary Изменить позицию меню
+// @Description Меняет цену в магазине, а также название, описание и типы товара. Цены уже оформленных заказов не меняются. Нужен updated_at из последнего ответа: если позицию успели изменить, вернется 409.
+// @Tags menu
+// @Accept json
+// @Produce json
+// @Param id path string true "ID магазина"
+// @Param item_id path string true "ID позиции (store_item)"
+// @Param item body transport.MenuItemRequest true "Изменяемые поля и updated_at"
+// @Success 200 {object} transport.MenuItem
+// @Failure 400 {object} http_response.ErrResponse "Ошибка входных данных"
+// @Failure 403 {object} http_response.ErrResponse "Нет прав на магазин или товар продается в чужих магазинах"
+// @Failure 404 {object} http_response.ErrResponse "Позиция не найдена"
+// @Failure 409 {object} http_response.ErrResponse "Позиция изменена другим запросом"
+// @Failure 500 {object} http_response.ErrResponse "Внутренняя ошибка сервера"
+// @Router /stores/{id}/menu/{item_id} [patch]
+func (h *MenuHandler) UpdateMenuItem(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+	storeID, id := r.PathValue("id"), r.PathValue("item_id")
+	log.InfoContext(ctx, "handler UpdateMenuItem start", slog.String("store_id", storeID), slog.String("store_item_id", id))
+
+	actor, ok := actorFromContext(ctx)
+	if !ok {
+		log.WarnContext(ctx, "handler UpdateMenuItem unauthorized")
+		h.rs.Error(ctx, w, http.StatusUnauthorized, "UpdateMenuItem", domain.ErrUnauthorized, nil)
+		return
+	}
+
+	req := &transport.MenuItemRequest{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		log.WarnContext(ctx, "handler UpdateMenuItem decode failed", slog.Any("err", err))
+		h.rs.Error(ctx, w, http.StatusBadRequest, "UpdateMenuItem", domain.ErrRequestParams, err)
+		return
+	}
+
+	item, err := h.uc.UpdateMenuItem(ctx, actor, storeID, id, transport.FromMenuItemRequest(req))
+	if err != nil {
+		log.WarnContext(ctx, "handler UpdateMenuItem usecase failed", slog.Any("err", err), slog.String("store_item_id", id))
+		h.menuError(ctx, w, "UpdateMenuItem", err)
+		return
+	}
+
+	log.InfoContext(ctx, "handler UpdateMenuItem success", slog.String("store_item_id", id))
+	h.rs.Send(ctx, w, http.StatusOK, transport.ToMenuItemResponse(item))
+}
+
+// ArchiveMenuItem godoc
+// @Summary Снять позицию с продажи
+// @Description Архивирует позицию: она пропадает из меню и корзин, заказы с ней не меняются. Вернуть в продажу можно через POST с item_id.
+// @Tags menu
+// @Param id path string true "ID магазина"
+// @Param item_id path string true "ID позиции (store_item)"
+// @Param updated_at query string true "Версия позиции из последнего ответа (RFC 3339)"
+// @Success 204 "Позиция архивирована"
+// @Failure 400 {object} http_response.ErrResponse "Ошибка входных данных"
+// @Failure 403 {object} http_response.ErrResponse "Магазин принадлежит другому владельцу"
+// @Failure 404 {object} http_response.ErrResponse "Позиция не найдена"
+// @Failure 409 {object} http_response.ErrResponse "Позиция изменена другим запросом"
+// @Failure 500 {object} http_response.ErrResponse "Внутренняя ошибка сервера"
+// @Router /stores/{id}/menu/{item_id} [delete]
+func (h *MenuHandler) ArchiveMenuItem(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+	storeID, id := r.PathValue("id"), r.PathValue("item_id")
+	log.InfoContext(ctx, "handler ArchiveMenuItem start", slog.String("store_id", storeID), slog.String("store_item_id", id))
+
+	actor, ok := actorFromContext(ctx)
+	if !ok {
+		log.WarnContext(ctx, "handler ArchiveMenuItem unauthorized")
+		h.rs.Error(ctx, w, http.StatusUnauthorized, "ArchiveMenuItem", domain.ErrUnauthorized, nil)
+		return
+	}
+
+	version, err := time.Parse(time.RFC3339Nano, r.URL.Query().Get("updated_at"))
+	if err != nil {
+		log.WarnContext(ctx, "handler ArchiveMenuItem invalid updated_at", slog.Any("err", err))
+		h.rs.Error(ctx, w, http.StatusBadRequest, "ArchiveMenuItem", domain.ErrRequestParams, err)
+		return
+	}
+
+	if err := h.uc.ArchiveMenuItem(ctx, actor, storeID, id, version); err != nil {
+		log.WarnContext(ctx, "handler ArchiveMenuItem usecase failed", slog.Any("err", err), slog.String("store_item_id", id))
+		h.menuError(ctx, w, "ArchiveMenuItem", err)
+		return
+	}
+
+	log.InfoContext(ctx, "handler ArchiveMenuItem success", slog.String("store_item_id", id))
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -1,8 +1,10 @@
 package http
 
 import (
+	"apple_backend/pkg/account"
 	"apple_backend/pkg/http_response"
 	"apple_backend/pkg/logger"
+	"apple_backend/pkg/rbac"
 	"apple_backend/store_service/internal/delivery/middlewares"
 	"context"
 	"encoding/json"
@@ -24,6 +26,7 @@ type OrderUsecaseInterface interface {
 	UpdateOrderStatus(ctx context.Context, orderID, userID, status string) error
 	GetOrdersUser(ctx context.Context, filter *domain.OrderFilter) ([]*domain.Order, error)
 	GetOrder(ctx context.Context, orderID, userID string) (*domain.OrderInfo, error)
+	GetStoreOrders(ctx context.Context, actor domain.Actor, filter *domain.OrderFilter) ([]*domain.Order, error)
 }
 
 type OrderHandler struct {
@@ -40,17 +43,22 @@ func NewOrderHandler(uc OrderUsecaseInterface) *OrderHandler {
 	}
 }
 
-func NewOrderRouter(mux *http.ServeMux, db repository.PgxIface, apiPrefix string) {
+func NewOrderRouter(mux *http.ServeMux, db repository.PgxIface, apiPrefix string, requireVerifiedEmail bool) {
 	orderRepo := repository.NewOrderRepoPostgres(db)
-	orderUC := usecase.NewOrderUsecase(orderRepo)
+	orderUC := usecase.NewOrderUsecase(orderRepo, repository.NewStoreRepoPostgres(db))
 	orderHandler := NewOrderHandler(orderUC)
 
+	createOrder := orderHandler.CreateOrder
+	if requireVerifiedEmail {
+		createOrder = middlewares.RequireVerifiedEmail(createOrder, account.NewPostgresVerificationChecker(db))
+	}
+
 	mux.HandleFunc(apiPrefix+"orders", func(w http.ResponseWriter, r *http.Request) {
 		switch r.Method {
 		case http.MethodGet:
 			orderHandler.GetOrdersUser(w, r)
 		case http.MethodPost:
-			orderHandler.CreateOrder(w, r)
+			createOrder(w, r)
 		default:
 			ctx := r.Context()
 			log := logger.FromContext(ctx)
@@ -61,6 +69,10 @@ func NewOrderRouter(mux *http.ServeMux, db repository.PgxIface, apiPrefix string
 
 	mux.HandleFunc(apiPrefix+"orders/{id}/status", orderHandler.UpdateOrderStatus)
 	mux.HandleFunc(apiPrefix+"orders/{id}", orderHandler.GetOrder)
+
+	// заказы магазина для владельца и его интеграций (API-ключ orders:read)
+	storeOrders := middlewares.RequirePermission(rbac.PermStoreOrdersRead)
+	mux.Handle("GET "+apiPrefix+"stores/{id}/orders", storeOrders(http.HandlerFunc(orderHandler.GetStoreOrders)))
 }
 
 func (h *OrderHandler) CreateOrder(w http.ResponseWriter, r *http.Request) {
@@ -154,6 +166,58 @@ func (h *OrderHandler) GetOrdersUser(w http.ResponseWriter, r *http.Request) {
 	h.rs.Send(ctx, w, http.StatusOK, ordersInfo)
 }
 
+// GetStoreOrders отдает владельцу заказы покупателей с позициями его
+// магазина, новые сначала
+func (h *OrderHandler) GetStoreOrders(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+	storeID := r.PathValue("id")
+	log.InfoContext(ctx, "handler GetStoreOrders start", slog.String("store_id", storeID))
+
+	actor, ok := actorFromContext(ctx)
+	if !ok {
+		log.WarnContext(ctx, "handler GetStoreOrders unauthorized")
+		h.rs.Error(ctx, w, http.StatusUnauthorized, "GetStoreOrders", domain.ErrUnauthorized, nil)
+		return
+	}
+
+	q := r.URL.Query()
+	limit, err := strconv.Atoi(q.Get("limit"))
+	if err != nil || limit <= 0 || limit > 100 {
+		log.WarnContext(ctx, "handler GetStoreOrders invalid limit", slog.String("limit", q.Get("limit")))
+		h.rs.Error(ctx, w, http.StatusBadRequest, "GetStoreOrders", domain.ErrRequestParams, errors.New("invalid limit"))
+		return
+	}
+
+	filter := &domain.OrderFilter{
+		StoreID: storeID,
+		Limit:   limit,
+		LastID:  q.Get("last_id"),
+	}
+
+	orders, err := h.uc.GetStoreOrders(ctx, actor, filter)
+	if err != nil {
+		log.WarnContext(ctx, "handler GetStoreOrders failed", slog.Any("err", err), slog.String("store_id", storeID))
+
+		switch {
+		case errors.Is(err, domain.ErrRequestParams):
+			h.rs.Error(ctx, w, http.StatusBadRequest, "GetStoreOrders", domain.ErrRequestParams, err)
+		case errors.Is(err, domain.ErrForbidden):
+			h.rs.Error(ctx, w, http.StatusForbidden, "GetStoreOrders", domain.ErrForbidden, nil)
+		case errors.Is(err, domain.ErrRowsNotFound):
+			h.rs.Error(ctx, w, http.StatusNotFound, "GetStoreOrders", domain.ErrRowsNotFound, nil)
+		default:
+			h.rs.Error(ctx, w, http.StatusInternalServerError, "GetStoreOrders", domain.ErrInternalServer, err)
+		}
+		return
+	}
+
+	log.InfoContext(ctx, "handler GetStoreOrders success",
+		slog.String("store_id", storeID),
+		slog.Int("orders_count", len(orders)))
+	h.rs.Send(ctx, w, http.StatusOK, transport.ToOrdersResponse(orders))
+}
+
 func (h *OrderHandler) GetOrder(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	log := logger.FromContext(ctx)
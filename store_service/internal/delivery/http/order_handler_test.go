@@ -2,7 +2,6 @@ package http
 
 import (
 	"apple_backend/pkg/http_response"
-	"apple_backend/pkg/logger"
 	"apple_backend/store_service/internal/delivery/middlewares"
 	"apple_backend/store_service/internal/delivery/mock"
 	"apple_backend/store_service/internal/delivery/transport"
@@ -13,8 +12,10 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/pashagolub/pgxmock/v2"
 	"github.com/stretchr/testify/require"
 )
 
@@ -43,7 +44,7 @@ func TestOrderHandler_CreateOrder(t *testing.T) {
 
 	status := "pending"
 	total := price1 + price2
-	createdAt := "2024-01-01"
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
 
 	item1 := &transport.OrderItemInfo{
 		ID:       uid1,
@@ -113,20 +114,6 @@ func TestOrderHandler_CreateOrder(t *testing.T) {
 			},
 			expectedErrResult: nil,
 		},
-		{
-			name:   "метод не разрешен",
-			method: http.MethodPut,
-			id:     uid1,
-			mockSetup: func(uc *mock.MockOrderUsecaseInterface, method, userID string) *http.Request {
-				req := httptest.NewRequest(method, url, bytes.NewBuffer(nil))
-				ctx := context.WithValue(req.Context(), middlewares.UserIDKey, userID)
-				req = req.WithContext(ctx)
-
-				return req
-			},
-			expectedCode:      http.StatusMethodNotAllowed,
-			expectedErrResult: &http_response.ErrResponse{Err: domain.ErrHTTPMethod.Error()},
-		},
 		{
 			name:   "не аутентифицирован",
 			method: http.MethodPost,
@@ -141,20 +128,6 @@ func TestOrderHandler_CreateOrder(t *testing.T) {
 			expectedCode:      http.StatusUnauthorized,
 			expectedErrResult: &http_response.ErrResponse{Err: domain.ErrUnauthorized.Error()},
 		},
-		{
-			name:   "неверный формат id",
-			method: http.MethodPost,
-			id:     "00000000-1",
-			mockSetup: func(uc *mock.MockOrderUsecaseInterface, method, userID string) *http.Request {
-				req := httptest.NewRequest(method, url, bytes.NewBuffer(nil))
-				ctx := context.WithValue(req.Context(), middlewares.UserIDKey, userID)
-				req = req.WithContext(ctx)
-
-				return req
-			},
-			expectedCode:      http.StatusUnauthorized,
-			expectedErrResult: &http_response.ErrResponse{Err: domain.ErrUnauthorized.Error()},
-		},
 		{
 			name:   "внутренняя ошибка",
 			method: http.MethodPost,
@@ -183,7 +156,7 @@ func TestOrderHandler_CreateOrder(t *testing.T) {
 			defer ctrl.Finish()
 
 			uc := mock.NewMockOrderUsecaseInterface(ctrl)
-			handler := NewOrderHandler(uc, logger.NewNilLogger())
+			handler := NewOrderHandler(uc)
 
 			req := tt.mockSetup(uc, tt.method, tt.id)
 
@@ -228,7 +201,7 @@ func TestOrderHandler_GetOrder(t *testing.T) {
 
 	status := "pending"
 	total := price1 + price2
-	createdAt := "2024-01-01"
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
 
 	item1 := &transport.OrderItemInfo{
 		ID:       uid1,
@@ -319,51 +292,6 @@ func TestOrderHandler_GetOrder(t *testing.T) {
 			expectedResult:    nil,
 			expectedErrResult: &http_response.ErrResponse{Err: domain.ErrForbidden.Error()},
 		},
-		{
-			name:    "метод не разрешен",
-			method:  http.MethodPost,
-			userID:  uid1,
-			orderID: uid2,
-			mockSetup: func(uc *mock.MockOrderUsecaseInterface, method, orderID, userID string) *http.Request {
-				req := httptest.NewRequest(method, fmt.Sprintf(url, orderID), bytes.NewBuffer(nil))
-				ctx := context.WithValue(req.Context(), middlewares.UserIDKey, userID)
-				req = req.WithContext(ctx)
-
-				return req
-			},
-			expectedCode:      http.StatusMethodNotAllowed,
-			expectedErrResult: &http_response.ErrResponse{Err: domain.ErrHTTPMethod.Error()},
-		},
-		{
-			name:    "неверный формат userID",
-			method:  http.MethodGet,
-			userID:  "00000000-1",
-			orderID: uid2,
-			mockSetup: func(uc *mock.MockOrderUsecaseInterface, method, orderID, userID string) *http.Request {
-				req := httptest.NewRequest(method, fmt.Sprintf(url, orderID), bytes.NewBuffer(nil))
-				ctx := context.WithValue(req.Context(), middlewares.UserIDKey, userID)
-				req = req.WithContext(ctx)
-
-				return req
-			},
-			expectedCode:      http.StatusUnauthorized,
-			expectedErrResult: &http_response.ErrResponse{Err: domain.ErrUnauthorized.Error()},
-		},
-		{
-			name:    "неверный формат orderID",
-			method:  http.MethodGet,
-			userID:  uid1,
-			orderID: "00000000-1",
-			mockSetup: func(uc *mock.MockOrderUsecaseInterface, method, orderID, userID string) *http.Request {
-				req := httptest.NewRequest(method, fmt.Sprintf(url, orderID), bytes.NewBuffer(nil))
-				ctx := context.WithValue(req.Context(), middlewares.UserIDKey, userID)
-				req = req.WithContext(ctx)
-
-				return req
-			},
-			expectedCode:      http.StatusBadRequest,
-			expectedErrResult: &http_response.ErrResponse{Err: domain.ErrRequestParams.Error()},
-		},
 		{
 			name:    "не найдено заказа",
 			method:  http.MethodGet,
@@ -412,7 +340,7 @@ func TestOrderHandler_GetOrder(t *testing.T) {
 			defer ctrl.Finish()
 
 			uc := mock.NewMockOrderUsecaseInterface(ctrl)
-			handler := NewOrderHandler(uc, logger.NewNilLogger())
+			handler := NewOrderHandler(uc)
 
 			req := tt.mockSetup(uc, tt.method, tt.orderID, tt.userID)
 			req.SetPathValue("id", tt.orderID)
@@ -433,21 +361,21 @@ func TestOrderHandler_GetOrder(t *testing.T) {
 }
 
 func TestOrderHandler_GetOrdersUser(t *testing.T) {
-	url := "/orders"
+	url := "/orders?limit=10"
 	type testCase struct {
 		name              string
 		method            string
 		id                string
 		mockSetup         func(uc *mock.MockOrderUsecaseInterface, method, userID string) *http.Request
 		expectedCode      int
-		expectedResult    *transport.Orders
+		expectedResult    []*transport.Order
 		expectedErrResult *http_response.ErrResponse
 	}
 
 	uid := "00000000-0000-0000-0000-000000000001"
 	status := "on the way"
 	total := 101.5
-	createdAt := "2024-01-01"
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
 
 	order := &transport.Order{
 		ID:        uid,
@@ -473,42 +401,42 @@ func TestOrderHandler_GetOrdersUser(t *testing.T) {
 				req = req.WithContext(ctx)
 
 				uc.EXPECT().
-					GetOrdersUser(ctx, uid).
+					GetOrdersUser(ctx, &domain.OrderFilter{UserID: uid, Limit: 10}).
 					Return([]*domain.Order{orderUC}, nil)
 
 				return req
 			},
 			expectedCode:      http.StatusOK,
-			expectedResult:    &transport.Orders{Orders: []*transport.Order{order}},
+			expectedResult:    []*transport.Order{order},
 			expectedErrResult: nil,
 		},
 		{
-			name:   "метод не разрешен",
-			method: http.MethodPost,
+			name:   "без limit",
+			method: http.MethodGet,
 			id:     uid,
 			mockSetup: func(uc *mock.MockOrderUsecaseInterface, method, userID string) *http.Request {
-				req := httptest.NewRequest(method, url, bytes.NewBuffer(nil))
+				req := httptest.NewRequest(method, "/orders", bytes.NewBuffer(nil))
 				ctx := context.WithValue(req.Context(), middlewares.UserIDKey, userID)
 				req = req.WithContext(ctx)
 
 				return req
 			},
-			expectedCode:      http.StatusMethodNotAllowed,
-			expectedErrResult: &http_response.ErrResponse{Err: domain.ErrHTTPMethod.Error()},
+			expectedCode:      http.StatusBadRequest,
+			expectedErrResult: &http_response.ErrResponse{Err: domain.ErrRequestParams.Error()},
 		},
 		{
-			name:   "неверный формат id",
+			name:   "неверный limit",
 			method: http.MethodGet,
-			id:     "00000000-1",
+			id:     uid,
 			mockSetup: func(uc *mock.MockOrderUsecaseInterface, method, userID string) *http.Request {
-				req := httptest.NewRequest(method, url, bytes.NewBuffer(nil))
+				req := httptest.NewRequest(method, "/orders?limit=0", bytes.NewBuffer(nil))
 				ctx := context.WithValue(req.Context(), middlewares.UserIDKey, userID)
 				req = req.WithContext(ctx)
 
 				return req
 			},
-			expectedCode:      http.StatusUnauthorized,
-			expectedErrResult: &http_response.ErrResponse{Err: domain.ErrUnauthorized.Error()},
+			expectedCode:      http.StatusBadRequest,
+			expectedErrResult: &http_response.ErrResponse{Err: domain.ErrRequestParams.Error()},
 		},
 		{
 			name:   "не найдено заказов",
@@ -520,7 +448,7 @@ func TestOrderHandler_GetOrdersUser(t *testing.T) {
 				req = req.WithContext(ctx)
 
 				uc.EXPECT().
-					GetOrdersUser(ctx, uid).
+					GetOrdersUser(ctx, &domain.OrderFilter{UserID: uid, Limit: 10}).
 					Return(nil, domain.ErrRowsNotFound)
 
 				return req
@@ -538,7 +466,7 @@ func TestOrderHandler_GetOrdersUser(t *testing.T) {
 				req = req.WithContext(ctx)
 
 				uc.EXPECT().
-					GetOrdersUser(ctx, uid).
+					GetOrdersUser(ctx, &domain.OrderFilter{UserID: uid, Limit: 10}).
 					Return(nil, domain.ErrInternalServer)
 
 				return req
@@ -570,7 +498,7 @@ func TestOrderHandler_GetOrdersUser(t *testing.T) {
 			defer ctrl.Finish()
 
 			uc := mock.NewMockOrderUsecaseInterface(ctrl)
-			handler := NewOrderHandler(uc, logger.NewNilLogger())
+			handler := NewOrderHandler(uc)
 
 			req := tt.mockSetup(uc, tt.method, tt.id)
 
@@ -603,7 +531,7 @@ func TestOrderHandler_UpdateOrderStatus(t *testing.T) {
 	}
 
 	uid1 := "00000000-0000-0000-0000-000000000001"
-	status := &transport.OrderStatus{Status: "on the way"}
+	status := &transport.OrderStatus{Status: "cancelled"}
 
 	tests := []testCase{
 		{
@@ -626,54 +554,6 @@ func TestOrderHandler_UpdateOrderStatus(t *testing.T) {
 			expectedCode:      http.StatusNoContent,
 			expectedErrResult: nil,
 		},
-		{
-			name:    "метод не разрешен",
-			method:  http.MethodPost,
-			userID:  uid1,
-			orderID: uid1,
-			body:    parseJSON(status),
-			mockSetup: func(uc *mock.MockOrderUsecaseInterface, method, userID, orderID, body string) *http.Request {
-				req := httptest.NewRequest(method, fmt.Sprintf(url, orderID), bytes.NewBuffer([]byte(body)))
-				ctx := context.WithValue(req.Context(), middlewares.UserIDKey, userID)
-				req = req.WithContext(ctx)
-
-				return req
-			},
-			expectedCode:      http.StatusMethodNotAllowed,
-			expectedErrResult: &http_response.ErrResponse{Err: domain.ErrHTTPMethod.Error()},
-		},
-		{
-			name:    "неверный формат user_id",
-			method:  http.MethodPatch,
-			userID:  "00000000-1",
-			orderID: uid1,
-			body:    parseJSON(status),
-			mockSetup: func(uc *mock.MockOrderUsecaseInterface, method, userID, orderID, body string) *http.Request {
-				req := httptest.NewRequest(method, fmt.Sprintf(url, orderID), bytes.NewBuffer([]byte(body)))
-				ctx := context.WithValue(req.Context(), middlewares.UserIDKey, userID)
-				req = req.WithContext(ctx)
-
-				return req
-			},
-			expectedCode:      http.StatusUnauthorized,
-			expectedErrResult: &http_response.ErrResponse{Err: domain.ErrUnauthorized.Error()},
-		},
-		{
-			name:    "неверный формат order_id",
-			method:  http.MethodPatch,
-			userID:  uid1,
-			orderID: "00000000-1",
-			body:    parseJSON(status),
-			mockSetup: func(uc *mock.MockOrderUsecaseInterface, method, userID, orderID, body string) *http.Request {
-				req := httptest.NewRequest(method, fmt.Sprintf(url, orderID), bytes.NewBuffer([]byte(body)))
-				ctx := context.WithValue(req.Context(), middlewares.UserIDKey, userID)
-				req = req.WithContext(ctx)
-
-				return req
-			},
-			expectedCode:      http.StatusBadRequest,
-			expectedErrResult: &http_response.ErrResponse{Err: domain.ErrRequestParams.Error()},
-		},
 		{
 			name:    "не найдено заказа",
 			method:  http.MethodPatch,
@@ -705,14 +585,11 @@ func TestOrderHandler_UpdateOrderStatus(t *testing.T) {
 				ctx := context.WithValue(req.Context(), middlewares.UserIDKey, userID)
 				req = req.WithContext(ctx)
 
-				uc.EXPECT().
-					UpdateOrderStatus(ctx, orderID, userID, "on").
-					Return(domain.ErrRequestParams)
-
 				return req
 			},
-			expectedCode:      http.StatusBadRequest,
-			expectedErrResult: &http_response.ErrResponse{Err: domain.ErrRequestParams.Error()},
+			// клиент может только отменить заказ
+			expectedCode:      http.StatusForbidden,
+			expectedErrResult: &http_response.ErrResponse{Err: domain.ErrForbidden.Error()},
 		},
 		{
 			name:    "некорректное тело запроса",
@@ -776,7 +653,7 @@ func TestOrderHandler_UpdateOrderStatus(t *testing.T) {
 			defer ctrl.Finish()
 
 			uc := mock.NewMockOrderUsecaseInterface(ctrl)
-			handler := NewOrderHandler(uc, logger.NewNilLogger())
+			handler := NewOrderHandler(uc)
 
 			req := tt.mockSetup(uc, tt.method, tt.userID, tt.orderID, tt.body)
 			req.SetPathValue("id", tt.orderID)
@@ -792,3 +669,23 @@ func TestOrderHandler_UpdateOrderStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestOrderRouter_MethodNotAllowed(t *testing.T) {
+	db, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mux := http.NewServeMux()
+	NewOrderRouter(mux, db, "/", false)
+
+	// метод для /orders проверяет роутер: GET — список, POST — создание
+	req := httptest.NewRequest(http.MethodPut, "/orders", nil)
+	req = req.WithContext(context.WithValue(req.Context(), middlewares.UserIDKey, "00000000-0000-0000-0000-000000000001"))
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	require.JSONEq(t, w.Body.String(), parseJSON(&http_response.ErrResponse{Err: domain.ErrHTTPMethod.Error()}))
+	require.NoError(t, db.ExpectationsWereMet())
+}
@@ -0,0 +1,91 @@
+package http
+
+import (
+	"apple_backend/pkg/http_response"
+	"apple_backend/pkg/logger"
+	"apple_backend/store_service/internal/delivery/transport"
+	"apple_backend/store_service/internal/domain"
+	"apple_backend/store_service/internal/repository"
+	"apple_backend/store_service/internal/usecase"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type SearchUsecaseInterface interface {
+	Search(ctx context.Context, filter *domain.SearchFilter) ([]*domain.SearchStore, error)
+}
+
+type SearchHandler struct {
+	uc SearchUsecaseInterface
+	rs *http_response.ResponseSender
+}
+
+func NewSearchHandler(uc SearchUsecaseInterface) *SearchHandler {
+	return &SearchHandler{
+		uc: uc,
+		rs: http_response.NewResponseSender(logger.Global()),
+	}
+}
+
+func NewSearchRouter(mux *http.ServeMux, db repository.PgxIface, apiPrefix string) {
+	searchRepo := repository.NewSearchRepoPostgres(db)
+	searchUC := usecase.NewSearchUsecase(searchRepo)
+	searchHandler := NewSearchHandler(searchUC)
+
+	mux.HandleFunc("GET "+apiPrefix+"search", searchHandler.Search)
+}
+
+// Search godoc
+// @Summary Поиск по магазинам и товарам
+// @Description Ищет по названиям и описаниям магазинов и товаров с учетом русской морфологии, опечаток и транслитерации ("pizza" находит "Пицца"). Результаты сгруппированы по магазинам, лучшие первыми; в каждом магазине до 5 найденных позиций.
+// @Tags search
+// @Produce json
+// @Param q query string true "Запрос, от 2 до 100 символов"
+// @Param city_id query string false "ID города"
+// @Param limit query int false "Число магазинов, по умолчанию 20, не больше 50"
+// @Success 200 {array} transport.SearchStore
+// @Failure 400 {object} http_response.ErrResponse "Ошибка входных данных"
+// @Failure 500 {object} http_response.ErrResponse "Внутренняя ошибка сервера"
+// @Router /search [get]
+func (h *SearchHandler) Search(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "handler Search start")
+
+	q := r.URL.Query()
+	filter := &domain.SearchFilter{
+		Query:  q.Get("q"),
+		CityID: q.Get("city_id"),
+	}
+	if limitStr := q.Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 {
+			log.WarnContext(ctx, "handler Search invalid limit", slog.String("limit", limitStr))
+			h.rs.Error(ctx, w, http.StatusBadRequest, "Search", domain.ErrRequestParams, errors.New("invalid limit"))
+			return
+		}
+		filter.Limit = limit
+	}
+
+	stores, err := h.uc.Search(ctx, filter)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrSearchQuery):
+			log.WarnContext(ctx, "handler Search invalid query", slog.Any("err", err))
+			h.rs.Error(ctx, w, http.StatusBadRequest, "Search", domain.ErrSearchQuery, nil)
+		case errors.Is(err, domain.ErrRequestParams):
+			log.WarnContext(ctx, "handler Search invalid params", slog.Any("err", err))
+			h.rs.Error(ctx, w, http.StatusBadRequest, "Search", domain.ErrRequestParams, nil)
+		default:
+			log.ErrorContext(ctx, "handler Search usecase failed", slog.Any("err", err))
+			h.rs.Error(ctx, w, http.StatusInternalServerError, "Search", domain.ErrInternalServer, err)
+		}
+		return
+	}
+
+	log.InfoContext(ctx, "handler Search success", slog.Int("stores_count", len(stores)))
+	h.rs.Send(ctx, w, http.StatusOK, transport.ToSearchResponse(stores))
+}
@@ -1,6 +1,7 @@
 package http
 
 import (
+	"apple_backend/pkg/geo"
 	"apple_backend/pkg/http_response"
 	"apple_backend/pkg/logger"
 	"apple_backend/store_service/internal/delivery/transport"
@@ -8,7 +9,6 @@ import (
 	"apple_backend/store_service/internal/repository"
 	"apple_backend/store_service/internal/usecase"
 	"context"
-	"encoding/json"
 	"errors"
 	"log/slog"
 	"net/http"
@@ -20,7 +20,6 @@ import (
 type StoreUsecaseInterface interface {
 	GetStore(ctx context.Context, id string) (*domain.StoreAgg, error)
 	GetStores(ctx context.Context, filter *domain.StoreFilter) ([]*domain.StoreAgg, error)
-	CreateStore(ctx context.Context, name, description, cityID, address, cardImg, openAt, closedAt string, rating float64) error
 	GetStoreReview(ctx context.Context, id string) ([]*domain.StoreReview, error)
 	GetCities(ctx context.Context) ([]*domain.City, error)
 	GetTags(ctx context.Context) ([]*domain.StoreTag, error)
@@ -50,40 +49,6 @@ func NewStoreRouter(mux *http.ServeMux, db repository.PgxIface, apiPrefix string
 	mux.HandleFunc(apiPrefix+"stores/tags", storeHandler.GetTags)
 }
 
-func (h *StoreHandler) CreateStore(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-	log := logger.FromContext(ctx)
-	log.InfoContext(ctx, "handler CreateStore start")
-
-	if r.Method != http.MethodPost {
-		log.WarnContext(ctx, "handler CreateStore wrong method")
-		h.rs.Error(ctx, w, http.StatusMethodNotAllowed, "CreateStore", domain.ErrHTTPMethod, nil)
-		return
-	}
-
-	req := &domain.Store{}
-	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
-		log.ErrorContext(ctx, "handler CreateStore decode failed", slog.Any("err", err))
-		h.rs.Error(ctx, w, http.StatusBadRequest, "CreateStore", domain.ErrRequestParams, err)
-		return
-	}
-
-	err := h.uc.CreateStore(ctx, req.Name, req.Description, req.CityID, req.Address, req.CardImg, req.OpenAt,
-		req.ClosedAt, req.Rating)
-	if err != nil {
-		log.ErrorContext(ctx, "handler CreateStore usecase failed", slog.Any("err", err))
-		if errors.Is(err, domain.ErrStoreExist) {
-			h.rs.Error(ctx, w, http.StatusBadRequest, "CreateStore", domain.ErrStoreExist, nil)
-			return
-		}
-		h.rs.Error(ctx, w, http.StatusInternalServerError, "CreateStore", domain.ErrInternalServer, err)
-		return
-	}
-
-	log.InfoContext(ctx, "handler CreateStore success")
-	w.WriteHeader(http.StatusCreated)
-}
-
 func (h *StoreHandler) GetStore(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	log := logger.FromContext(ctx)
@@ -152,6 +117,18 @@ func (h *StoreHandler) GetStores(w http.ResponseWriter, r *http.Request) {
 		Desc:   q.Has("desc") && q.Get("desc") == "true",
 	}
 
+	// lat и lon задаются вместе: точка, куда нужна доставка
+	if latStr, lonStr := q.Get("lat"), q.Get("lon"); latStr != "" || lonStr != "" {
+		lat, latErr := strconv.ParseFloat(latStr, 64)
+		lon, lonErr := strconv.ParseFloat(lonStr, 64)
+		if latErr != nil || lonErr != nil {
+			log.WarnContext(ctx, "handler GetStores invalid point", slog.String("lat", latStr), slog.String("lon", lonStr))
+			h.rs.Error(ctx, w, http.StatusBadRequest, "GetStores", domain.ErrRequestParams, errors.New("invalid lat/lon"))
+			return
+		}
+		filter.Point = &geo.Point{Lat: lat, Lon: lon}
+	}
+
 	stores, err := h.uc.GetStores(ctx, filter)
 	if err != nil {
 		log.ErrorContext(ctx, "handler GetStores usecase failed", slog.Any("err", err))
@@ -1,8 +1,8 @@
 package http
 
 import (
+	"apple_backend/pkg/geo"
 	"apple_backend/pkg/http_response"
-	"apple_backend/pkg/logger"
 	"apple_backend/store_service/internal/delivery/mock"
 	"apple_backend/store_service/internal/delivery/transport"
 	"apple_backend/store_service/internal/domain"
@@ -112,7 +112,7 @@ func TestStoreHandler_GetStore(t *testing.T) {
 	defer ctrl.Finish()
 
 	uc := mock.NewMockStoreUsecaseInterface(ctrl)
-	handler := NewStoreHandler(uc, logger.NewNilLogger())
+	handler := NewStoreHandler(uc)
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -142,7 +142,7 @@ func TestStoreHandler_GetStores(t *testing.T) {
 	type testCase struct {
 		name              string
 		method            string
-		body              string
+		query             string
 		mockSetup         func(uc *mock.MockStoreUsecaseInterface)
 		expectedCode      int
 		expectedResult    []*transport.StoreResponse
@@ -165,6 +165,8 @@ func TestStoreHandler_GetStores(t *testing.T) {
 	openAt2 := "open_at2"
 	closedAt1 := "closed_at1"
 	closedAt2 := "closed_at2"
+	distance := 1234.4
+	roundedDistance := 1234.
 
 	store1 := &domain.StoreAgg{
 		ID:          uid1,
@@ -192,13 +194,24 @@ func TestStoreHandler_GetStores(t *testing.T) {
 		ClosedAt:    closedAt2,
 	}
 
+	// обработчик дописывает путь к картинке в магазины из usecase,
+	// поэтому каждый вызов получает свои копии
+	copyStores := func(stores ...*domain.StoreAgg) []*domain.StoreAgg {
+		res := make([]*domain.StoreAgg, 0, len(stores))
+		for _, s := range stores {
+			c := *s
+			res = append(res, &c)
+		}
+		return res
+	}
+
 	storeResp1 := &transport.StoreResponse{
 		ID:          store1.ID,
 		Name:        store1.Name,
 		Description: store1.Description,
 		CityID:      store1.CityID,
 		Address:     store1.Address,
-		CardImg:     store1.CardImg,
+		CardImg:     "/images/stores/" + store1.CardImg,
 		Rating:      store1.Rating,
 		TagsID:      []string{uid1},
 		OpenAt:      store1.OpenAt,
@@ -211,7 +224,7 @@ func TestStoreHandler_GetStores(t *testing.T) {
 		Description: description2,
 		CityID:      uid2,
 		Address:     address2,
-		CardImg:     cardImg2,
+		CardImg:     "/images/stores/" + cardImg2,
 		Rating:      rating2,
 		TagsID:      []string{uid2},
 		OpenAt:      openAt2,
@@ -221,12 +234,12 @@ func TestStoreHandler_GetStores(t *testing.T) {
 	tests := []testCase{
 		{
 			name:   "GetStores успешный вызов без фильтров",
-			method: http.MethodPost,
-			body:   parseJSON(&domain.StoreFilter{Limit: 10}),
+			method: http.MethodGet,
+			query:  "limit=10",
 			mockSetup: func(uc *mock.MockStoreUsecaseInterface) {
 				uc.EXPECT().
 					GetStores(context.Background(), &domain.StoreFilter{Limit: 10}).
-					Return([]*domain.StoreAgg{store1, store2}, nil)
+					Return(copyStores(store1, store2), nil)
 			},
 			expectedCode: http.StatusOK,
 			expectedResult: []*transport.StoreResponse{
@@ -236,12 +249,12 @@ func TestStoreHandler_GetStores(t *testing.T) {
 		},
 		{
 			name:   "GetStores успешный вызов с фильтром по тегу",
-			method: http.MethodPost,
-			body:   parseJSON(&domain.StoreFilter{Limit: 10, TagID: uid1}),
+			method: http.MethodGet,
+			query:  "limit=10&tag_id=" + uid1,
 			mockSetup: func(uc *mock.MockStoreUsecaseInterface) {
 				uc.EXPECT().
 					GetStores(context.Background(), &domain.StoreFilter{Limit: 10, TagID: uid1}).
-					Return([]*domain.StoreAgg{store1}, nil)
+					Return(copyStores(store1), nil)
 			},
 			expectedCode: http.StatusOK,
 			expectedResult: []*transport.StoreResponse{
@@ -250,12 +263,12 @@ func TestStoreHandler_GetStores(t *testing.T) {
 		},
 		{
 			name:   "GetStores успешный вызов с фильтром по городу",
-			method: http.MethodPost,
-			body:   parseJSON(&domain.StoreFilter{Limit: 10, CityID: uid2}),
+			method: http.MethodGet,
+			query:  "limit=10&city_id=" + uid2,
 			mockSetup: func(uc *mock.MockStoreUsecaseInterface) {
 				uc.EXPECT().
 					GetStores(context.Background(), &domain.StoreFilter{Limit: 10, CityID: uid2}).
-					Return([]*domain.StoreAgg{store2}, nil)
+					Return(copyStores(store2), nil)
 			},
 			expectedCode: http.StatusOK,
 			expectedResult: []*transport.StoreResponse{
@@ -264,12 +277,12 @@ func TestStoreHandler_GetStores(t *testing.T) {
 		},
 		{
 			name:   "GetStores успешный вызов с сортировкой",
-			method: http.MethodPost,
-			body:   parseJSON(&domain.StoreFilter{Limit: 5, Sorted: "rating"}),
+			method: http.MethodGet,
+			query:  "limit=5&sorted=rating",
 			mockSetup: func(uc *mock.MockStoreUsecaseInterface) {
 				uc.EXPECT().
 					GetStores(context.Background(), &domain.StoreFilter{Limit: 5, Sorted: "rating"}).
-					Return([]*domain.StoreAgg{store2, store1}, nil)
+					Return(copyStores(store2, store1), nil)
 			},
 			expectedCode: http.StatusOK,
 			expectedResult: []*transport.StoreResponse{
@@ -277,163 +290,129 @@ func TestStoreHandler_GetStores(t *testing.T) {
 				storeResp1,
 			},
 		},
+		{
+			name:   "GetStores успешный вызов с сортировкой по убыванию",
+			method: http.MethodGet,
+			query:  "limit=5&sorted=rating&desc=true",
+			mockSetup: func(uc *mock.MockStoreUsecaseInterface) {
+				uc.EXPECT().
+					GetStores(context.Background(), &domain.StoreFilter{Limit: 5, Sorted: "rating", Desc: true}).
+					Return(copyStores(store2, store1), nil)
+			},
+			expectedCode: http.StatusOK,
+			expectedResult: []*transport.StoreResponse{
+				storeResp2,
+				storeResp1,
+			},
+		},
+		{
+			name:   "GetStores успешный вызов с точкой доставки",
+			method: http.MethodGet,
+			query:  "limit=10&lat=55.75&lon=37.61",
+			mockSetup: func(uc *mock.MockStoreUsecaseInterface) {
+				stores := copyStores(store1)
+				stores[0].Distance = &distance
+				uc.EXPECT().
+					GetStores(context.Background(), &domain.StoreFilter{Limit: 10, Point: &geo.Point{Lat: 55.75, Lon: 37.61}}).
+					Return(stores, nil)
+			},
+			expectedCode: http.StatusOK,
+			expectedResult: []*transport.StoreResponse{
+				{
+					ID:          storeResp1.ID,
+					Name:        storeResp1.Name,
+					Description: storeResp1.Description,
+					CityID:      storeResp1.CityID,
+					Address:     storeResp1.Address,
+					CardImg:     storeResp1.CardImg,
+					Rating:      storeResp1.Rating,
+					TagsID:      storeResp1.TagsID,
+					OpenAt:      storeResp1.OpenAt,
+					ClosedAt:    storeResp1.ClosedAt,
+					DistanceM:   &roundedDistance,
+				},
+			},
+		},
 		{
 			name:              "GetStores метод не разрешен",
-			method:            http.MethodGet,
-			body:              parseJSON(&domain.StoreFilter{Limit: 10}),
+			method:            http.MethodPost,
+			query:             "limit=10",
 			mockSetup:         func(uc *mock.MockStoreUsecaseInterface) {},
 			expectedCode:      http.StatusMethodNotAllowed,
 			expectedErrResult: &http_response.ErrResponse{Err: domain.ErrHTTPMethod.Error()},
 		},
 		{
-			name:              "GetStores неверный формат json",
-			method:            http.MethodPost,
-			body:              "запрос",
+			name:              "GetStores неверный формат limit",
+			method:            http.MethodGet,
+			query:             "limit=запрос",
 			mockSetup:         func(uc *mock.MockStoreUsecaseInterface) {},
 			expectedCode:      http.StatusBadRequest,
 			expectedErrResult: &http_response.ErrResponse{Err: domain.ErrRequestParams.Error()},
 		},
 		{
-			name:   "GetStores не найдено данных",
-			method: http.MethodPost,
-			body:   parseJSON(&domain.StoreFilter{Limit: 10}),
-			mockSetup: func(uc *mock.MockStoreUsecaseInterface) {
-				uc.EXPECT().
-					GetStores(context.Background(), &domain.StoreFilter{Limit: 10}).
-					Return(nil, domain.ErrRowsNotFound)
-			},
-			expectedCode:      http.StatusNotFound,
-			expectedErrResult: &http_response.ErrResponse{Err: domain.ErrRowsNotFound.Error()},
+			name:              "GetStores без limit",
+			method:            http.MethodGet,
+			query:             "",
+			mockSetup:         func(uc *mock.MockStoreUsecaseInterface) {},
+			expectedCode:      http.StatusBadRequest,
+			expectedErrResult: &http_response.ErrResponse{Err: domain.ErrRequestParams.Error()},
 		},
 		{
-			name:   "GetStores некорректные данные фильтра",
-			method: http.MethodPost,
-			body:   parseJSON(&domain.StoreFilter{Limit: 0}),
-			mockSetup: func(uc *mock.MockStoreUsecaseInterface) {
-				uc.EXPECT().
-					GetStores(context.Background(), &domain.StoreFilter{Limit: 0}).
-					Return(nil, domain.ErrRequestParams)
-			},
+			name:              "GetStores limit больше 100",
+			method:            http.MethodGet,
+			query:             "limit=1000",
+			mockSetup:         func(uc *mock.MockStoreUsecaseInterface) {},
 			expectedCode:      http.StatusBadRequest,
 			expectedErrResult: &http_response.ErrResponse{Err: domain.ErrRequestParams.Error()},
 		},
 		{
-			name:   "GetStores внутренняя ошибка",
-			method: http.MethodPost,
-			body:   parseJSON(&domain.StoreFilter{Limit: 10}),
-			mockSetup: func(uc *mock.MockStoreUsecaseInterface) {
-				uc.EXPECT().
-					GetStores(context.Background(), &domain.StoreFilter{Limit: 10}).
-					Return(nil, domain.ErrInternalServer)
-			},
-			expectedCode:      http.StatusInternalServerError,
-			expectedErrResult: &http_response.ErrResponse{Err: domain.ErrInternalServer.Error()},
+			name:              "GetStores точка без долготы",
+			method:            http.MethodGet,
+			query:             "limit=10&lat=55.75",
+			mockSetup:         func(uc *mock.MockStoreUsecaseInterface) {},
+			expectedCode:      http.StatusBadRequest,
+			expectedErrResult: &http_response.ErrResponse{Err: domain.ErrRequestParams.Error()},
 		},
-	}
-
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
-
-	uc := mock.NewMockStoreUsecaseInterface(ctrl)
-	handler := NewStoreHandler(uc, logger.NewNilLogger())
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			tt.mockSetup(uc)
-
-			req := httptest.NewRequest(tt.method, url, bytes.NewBuffer([]byte(tt.body)))
-			req = req.WithContext(context.Background())
-
-			w := httptest.NewRecorder()
-
-			handler.GetStores(w, req)
-
-			require.Equal(t, tt.expectedCode, w.Code)
-			if tt.expectedResult != nil {
-				require.JSONEq(t, w.Body.String(), parseJSON(tt.expectedResult))
-			}
-			if tt.expectedErrResult != nil {
-				require.JSONEq(t, w.Body.String(), parseJSON(tt.expectedErrResult))
-			}
-		})
-	}
-}
-
-func TestStoreHandler_CreateStore(t *testing.T) {
-	url := "/stores"
-	type testCase struct {
-		name              string
-		method            string
-		body              string
-		mockSetup         func(uc *mock.MockStoreUsecaseInterface)
-		expectedCode      int
-		expectedResult    []*transport.StoreResponse
-		expectedErrResult *http_response.ErrResponse
-	}
-
-	store := &domain.Store{
-		Name:        "name",
-		Description: "description",
-		CityID:      "00000000-0000-0000-0000-000000000001",
-		Address:     "address",
-		CardImg:     "card_img",
-		Rating:      5,
-		OpenAt:      "open_at",
-		ClosedAt:    "closed_at",
-	}
-
-	tests := []testCase{
 		{
-			name:   "CreateStore успешный вызов",
-			method: http.MethodPost,
-			body:   parseJSON(store),
+			name:   "GetStores не найдено данных",
+			method: http.MethodGet,
+			query:  "limit=10",
 			mockSetup: func(uc *mock.MockStoreUsecaseInterface) {
 				uc.EXPECT().
-					CreateStore(context.Background(), store.Name, store.Description, store.CityID,
-						store.Address, store.CardImg, store.OpenAt, store.ClosedAt, store.Rating).
-					Return(nil)
+					GetStores(context.Background(), &domain.StoreFilter{Limit: 10}).
+					Return([]*domain.StoreAgg{}, nil)
 			},
-			expectedCode:   http.StatusCreated,
-			expectedResult: nil,
-		},
-		{
-			name:              "CreateStore метод не разрешен",
-			method:            http.MethodPut,
-			body:              parseJSON(store),
-			mockSetup:         func(uc *mock.MockStoreUsecaseInterface) {},
-			expectedCode:      http.StatusMethodNotAllowed,
-			expectedErrResult: &http_response.ErrResponse{Err: domain.ErrHTTPMethod.Error()},
+			expectedCode:   http.StatusOK,
+			expectedResult: []*transport.StoreResponse{},
 		},
 		{
-			name:              "CreateStore неверный формат json",
-			method:            http.MethodPost,
-			body:              "запрос",
+			name:              "GetStores некорректные данные фильтра",
+			method:            http.MethodGet,
+			query:             "limit=0",
 			mockSetup:         func(uc *mock.MockStoreUsecaseInterface) {},
 			expectedCode:      http.StatusBadRequest,
 			expectedErrResult: &http_response.ErrResponse{Err: domain.ErrRequestParams.Error()},
 		},
 		{
-			name:   "CreateStore уже существующий магазин",
-			method: http.MethodPost,
-			body:   parseJSON(store),
+			name:   "GetStores некорректная сортировка",
+			method: http.MethodGet,
+			query:  "limit=10&sorted=name",
 			mockSetup: func(uc *mock.MockStoreUsecaseInterface) {
 				uc.EXPECT().
-					CreateStore(context.Background(), store.Name, store.Description, store.CityID,
-						store.Address, store.CardImg, store.OpenAt, store.ClosedAt, store.Rating).
-					Return(domain.ErrStoreExist)
+					GetStores(context.Background(), &domain.StoreFilter{Limit: 10, Sorted: "name"}).
+					Return(nil, domain.ErrRequestParams)
 			},
 			expectedCode:      http.StatusBadRequest,
-			expectedErrResult: &http_response.ErrResponse{Err: domain.ErrStoreExist.Error()},
+			expectedErrResult: &http_response.ErrResponse{Err: domain.ErrRequestParams.Error()},
 		},
 		{
-			name:   "CreateStore внутренняя ошибка",
-			method: http.MethodPost,
-			body:   parseJSON(store),
+			name:   "GetStores внутренняя ошибка",
+			method: http.MethodGet,
+			query:  "limit=10",
 			mockSetup: func(uc *mock.MockStoreUsecaseInterface) {
 				uc.EXPECT().
-					CreateStore(context.Background(), store.Name, store.Description, store.CityID,
-						store.Address, store.CardImg, store.OpenAt, store.ClosedAt, store.Rating).
-					Return(domain.ErrInternalServer)
+					GetStores(context.Background(), &domain.StoreFilter{Limit: 10}).
+					Return(nil, domain.ErrInternalServer)
 			},
 			expectedCode:      http.StatusInternalServerError,
 			expectedErrResult: &http_response.ErrResponse{Err: domain.ErrInternalServer.Error()},
@@ -444,18 +423,18 @@ func TestStoreHandler_CreateStore(t *testing.T) {
 	defer ctrl.Finish()
 
 	uc := mock.NewMockStoreUsecaseInterface(ctrl)
-	handler := NewStoreHandler(uc, logger.NewNilLogger())
+	handler := NewStoreHandler(uc)
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockSetup(uc)
 
-			req := httptest.NewRequest(tt.method, url, bytes.NewBuffer([]byte(tt.body)))
+			req := httptest.NewRequest(tt.method, url+"?"+tt.query, bytes.NewBuffer(nil))
 			req = req.WithContext(context.Background())
 
 			w := httptest.NewRecorder()
 
-			handler.CreateStore(w, req)
+			handler.GetStores(w, req)
 
 			require.Equal(t, tt.expectedCode, w.Code)
 			if tt.expectedResult != nil {
@@ -579,7 +558,7 @@ func TestStoreHandler_GetStoreReview(t *testing.T) {
 			defer ctrl.Finish()
 
 			uc := mock.NewMockStoreUsecaseInterface(ctrl)
-			handler := NewStoreHandler(uc, logger.NewNilLogger())
+			handler := NewStoreHandler(uc)
 			tt.mockSetup(uc)
 
 			req := httptest.NewRequest(tt.method, fmt.Sprintf(url, tt.id), bytes.NewBuffer(nil))
@@ -682,7 +661,7 @@ func TestStoreHandler_GetCities(t *testing.T) {
 	defer ctrl.Finish()
 
 	uc := mock.NewMockStoreUsecaseInterface(ctrl)
-	handler := NewStoreHandler(uc, logger.NewNilLogger())
+	handler := NewStoreHandler(uc)
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -787,7 +766,7 @@ func TestStoreHandler_GetTags(t *testing.T) {
 	defer ctrl.Finish()
 
 	uc := mock.NewMockStoreUsecaseInterface(ctrl)
-	handler := NewStoreHandler(uc, logger.NewNilLogger())
+	handler := NewStoreHandler(uc)
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,312 @@
+package http
+
+import (
+	"apple_backend/pkg/http_response"
+	"apple_backend/pkg/logger"
+	"apple_backend/pkg/rbac"
+	"apple_backend/store_service/internal/delivery/middlewares"
+	"apple_backend/store_service/internal/delivery/transport"
+	"apple_backend/store_service/internal/domain"
+	"apple_backend/store_service/internal/repository"
+	"apple_backend/store_service/internal/usecase"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+)
+
+type StoreManageUsecaseInterface interface {
+	CreateStore(ctx context.Context, actor domain.Actor, in *domain.StoreInput) (*domain.StoreAgg, error)
+	UpdateStore(ctx context.Context, actor domain.Actor, id string, in *domain.StoreInput) (*domain.StoreAgg, error)
+	DeleteStore(ctx context.Context, actor domain.Actor, id string) error
+	UploadStoreImage(ctx context.Context, actor domain.Actor, id string, file io.Reader, filename string) (string, error)
+	DeleteStoreImage(ctx context.Context, actor domain.Actor, id string) error
+}
+
+type StoreManageHandler struct {
+	uc StoreManageUsecaseInterface
+	rs *http_response.ResponseSender
+}
+
+func NewStoreManageHandler(uc StoreManageUsecaseInterface) *StoreManageHandler {
+	return &StoreManageHandler{
+		uc: uc,
+		rs: http_response.NewResponseSender(logger.Global()),
+	}
+}
+
+// NewStoreManageRouter регистрирует изменение магазинов; mux должен стоять
+// за AuthMiddleware
+func NewStoreManageRouter(mux *http.ServeMux, db repository.PgxIface, apiPrefix, uploadDir string) {
+	storeRepo := repository.NewStoreRepoPostgres(db)
+	storeUC := usecase.NewStoreManageUsecase(storeRepo, uploadDir)
+	h := NewStoreManageHandler(storeUC)
+
+	manage := middlewares.RequirePermission(rbac.PermStoresManage)
+	mux.Handle("POST "+apiPrefix+"stores", manage(http.HandlerFunc(h.CreateStore)))
+	mux.Handle("PATCH "+apiPrefix+"stores/{id}", manage(http.HandlerFunc(h.UpdateStore)))
+	mux.Handle("DELETE "+apiPrefix+"stores/{id}", manage(http.HandlerFunc(h.DeleteStore)))
+	mux.Handle("PUT "+apiPrefix+"stores/{id}/image", manage(http.HandlerFunc(h.UploadStoreImage)))
+	mux.Handle("DELETE "+apiPrefix+"stores/{id}/image", manage(http.HandlerFunc(h.DeleteStoreImage)))
+}
+
+func actorFromContext(ctx context.Context) (domain.Actor, bool) {
+	userID, ok := middlewares.UserIDFromContext(ctx)
+	if !ok || userID == "" {
+		return domain.Actor{}, false
+	}
+	return domain.Actor{
+		UserID: userID,
+		Admin:  rbac.HasRole(middlewares.RolesFromContext(ctx), rbac.RoleAdmin),
+	}, true
+}
+
+// storeManageError отвечает на ошибку изменения магазина
+func (h *StoreManageHandler) storeManageError(ctx context.Context, w http.ResponseWriter, op string, err error) {
+	switch {
+	case errors.Is(err, domain.ErrStoreName),
+		errors.Is(err, domain.ErrStoreDescription),
+		errors.Is(err, domain.ErrStoreAddress),
+		errors.Is(err, domain.ErrStoreHours),
+		errors.Is(err, domain.ErrStoreLocation),
+		errors.Is(err, domain.ErrDeliveryZone),
+		errors.Is(err, domain.ErrZoneAntimeridian),
+		errors.Is(err, domain.ErrStoreImage),
+		errors.Is(err, domain.ErrCityNotFound),
+		errors.Is(err, domain.ErrTagNotFound),
+		errors.Is(err, domain.ErrCategoryNotFound):
+		h.rs.Error(ctx, w, http.StatusBadRequest, op, err, nil)
+	case errors.Is(err, domain.ErrRequestParams):
+		h.rs.Error(ctx, w, http.StatusBadRequest, op, domain.ErrRequestParams, err)
+	case errors.Is(err, domain.ErrStoreImageSize):
+		h.rs.Error(ctx, w, http.StatusRequestEntityTooLarge, op, err, nil)
+	case errors.Is(err, domain.ErrForbidden):
+		h.rs.Error(ctx, w, http.StatusForbidden, op, err, nil)
+	case errors.Is(err, domain.ErrRowsNotFound):
+		h.rs.Error(ctx, w, http.StatusNotFound, op, err, nil)
+	case errors.Is(err, domain.ErrStoreExist), errors.Is(err, domain.ErrStoreHasOrders):
+		h.rs.Error(ctx, w, http.StatusConflict, op, err, nil)
+	default:
+		h.rs.Error(ctx, w, http.StatusInternalServerError, op, domain.ErrInternalServer, err)
+	}
+}
+
+func toManagedStoreResponse(store *domain.StoreAgg) *transport.StoreResponse {
+	if store.CardImg != "" {
+		store.CardImg = "/images/stores/" + store.CardImg
+	}
+	return transport.ToStoreResponse(store)
+}
+
+// CreateStore godoc
+// @Summary Создать магазин
+// @Description Создает магазин; создатель становится его владельцем. Нужно разрешение stores:manage.
+// @Tags stores
+// @Accept json
+// @Produce json
+// @Param store body transport.StoreRequest true "Магазин, обязательны все поля кроме tags_id и categories_id"
+// @Success 201 {object} transport.StoreResponse
+// @Failure 400 {object} http_response.ErrResponse "Ошибка входных данных"
+// @Failure 401 {object} http_response.ErrResponse "Не авторизован"
+// @Failure 403 {object} http_response.ErrResponse "Нет разрешения stores:manage"
+// @Failure 409 {object} http_response.ErrResponse "Магазин с таким названием и адресом уже есть в городе"
+// @Failure 500 {object} http_response.ErrResponse "Внутренняя ошибка сервера"
+// @Router /stores [post]
+func (h *StoreManageHandler) CreateStore(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+	log.InfoContext(ctx, "handler CreateStore start")
+
+	actor, ok := actorFromContext(ctx)
+	if !ok {
+		log.WarnContext(ctx, "handler CreateStore unauthorized")
+		h.rs.Error(ctx, w, http.StatusUnauthorized, "CreateStore", domain.ErrUnauthorized, nil)
+		return
+	}
+
+	req := &transport.StoreRequest{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		log.WarnContext(ctx, "handler CreateStore decode failed", slog.Any("err", err))
+		h.rs.Error(ctx, w, http.StatusBadRequest, "CreateStore", domain.ErrRequestParams, err)
+		return
+	}
+
+	store, err := h.uc.CreateStore(ctx, actor, transport.FromStoreRequest(req))
+	if err != nil {
+		log.WarnContext(ctx, "handler CreateStore usecase failed", slog.Any("err", err), slog.String("user_id", actor.UserID))
+		h.storeManageError(ctx, w, "CreateStore", err)
+		return
+	}
+
+	log.InfoContext(ctx, "handler CreateStore success", slog.String("store_id", store.ID), slog.String("user_id", actor.UserID))
+	h.rs.Send(ctx, w, http.StatusCreated, toManagedStoreResponse(store))
+}
+
+// UpdateStore godoc
+// @Summary Изменить магазин
+// @Description Меняет переданные поля магазина: название, описание, адрес, город, часы работы, теги и категории. Координаты и зона доставки меняются вместе: lat, lon и delivery_radius_m или delivery_area; без зоны магазин не находится по точке доставки. Доступно владельцу магазина и admin.
+// @Tags stores
+// @Accept json
+// @Produce json
+// @Param id path string true "ID магазина"
+// @Param store body transport.StoreRequest true "Изменяемые поля"
+// @Success 200 {object} transport.StoreResponse
+// @Failure 400 {object} http_response.ErrResponse "Ошибка входных данных"
+// @Failure 403 {object} http_response.ErrResponse "Магазин принадлежит другому владельцу"
+// @Failure 404 {object} http_response.ErrResponse "Магазин не найден"
+// @Failure 409 {object} http_response.ErrResponse "Магазин с таким названием и адресом уже есть в городе"
+// @Failure 500 {object} http_response.ErrResponse "Внутренняя ошибка сервера"
+// @Router /stores/{id} [patch]
+func (h *StoreManageHandler) UpdateStore(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+	id := r.PathValue("id")
+	log.InfoContext(ctx, "handler UpdateStore start", slog.String("store_id", id))
+
+	actor, ok := actorFromContext(ctx)
+	if !ok {
+		log.WarnContext(ctx, "handler UpdateStore unauthorized")
+		h.rs.Error(ctx, w, http.StatusUnauthorized, "UpdateStore", domain.ErrUnauthorized, nil)
+		return
+	}
+
+	req := &transport.StoreRequest{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		log.WarnContext(ctx, "handler UpdateStore decode failed", slog.Any("err", err))
+		h.rs.Error(ctx, w, http.StatusBadRequest, "UpdateStore", domain.ErrRequestParams, err)
+		return
+	}
+
+	store, err := h.uc.UpdateStore(ctx, actor, id, transport.FromStoreRequest(req))
+	if err != nil {
+		log.WarnContext(ctx, "handler UpdateStore usecase failed", slog.Any("err", err), slog.String("store_id", id))
+		h.storeManageError(ctx, w, "UpdateStore", err)
+		return
+	}
+
+	log.InfoContext(ctx, "handler UpdateStore success", slog.String("store_id", id), slog.String("user_id", actor.UserID))
+	h.rs.Send(ctx, w, http.StatusOK, toManagedStoreResponse(store))
+}
+
+// DeleteStore godoc
+// @Summary Удалить магазин
+// @Description Удаляет магазин вместе с меню. Магазин, по которому были заказы, удалить нельзя.
+// @Tags stores
+// @Param id path string true "ID магазина"
+// @Success 204 "Магазин удален"
+// @Failure 400 {object} http_response.ErrResponse "Неверный ID"
+// @Failure 403 {object} http_response.ErrResponse "Магазин принадлежит другому владельцу"
+// @Failure 404 {object} http_response.ErrResponse "Магазин не найден"
+// @Failure 409 {object} http_response.ErrResponse "По магазину есть заказы"
+// @Failure 500 {object} http_response.ErrResponse "Внутренняя ошибка сервера"
+// @Router /stores/{id} [delete]
+func (h *StoreManageHandler) DeleteStore(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+	id := r.PathValue("id")
+	log.InfoContext(ctx, "handler DeleteStore start", slog.String("store_id", id))
+
+	actor, ok := actorFromContext(ctx)
+	if !ok {
+		log.WarnContext(ctx, "handler DeleteStore unauthorized")
+		h.rs.Error(ctx, w, http.StatusUnauthorized, "DeleteStore", domain.ErrUnauthorized, nil)
+		return
+	}
+
+	if err := h.uc.DeleteStore(ctx, actor, id); err != nil {
+		log.WarnContext(ctx, "handler DeleteStore usecase failed", slog.Any("err", err), slog.String("store_id", id))
+		h.storeManageError(ctx, w, "DeleteStore", err)
+		return
+	}
+
+	log.InfoContext(ctx, "handler DeleteStore success", slog.String("store_id", id), slog.String("user_id", actor.UserID))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// UploadStoreImage godoc
+// @Summary Загрузить карточку магазина
+// @Description Заменяет изображение карточки магазина. Форматы png, jpg, jpeg, webp, gif, не больше 5 МБ.
+// @Tags stores
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "ID магазина"
+// @Param image formData file true "Изображение"
+// @Success 200 {object} transport.StoreImageResponse
+// @Failure 400 {object} http_response.ErrResponse "Неверный формат файла"
+// @Failure 403 {object} http_response.ErrResponse "Магазин принадлежит другому владельцу"
+// @Failure 404 {object} http_response.ErrResponse "Магазин не найден"
+// @Failure 413 {object} http_response.ErrResponse "Файл слишком большой"
+// @Failure 500 {object} http_response.ErrResponse "Внутренняя ошибка сервера"
+// @Router /stores/{id}/image [put]
+func (h *StoreManageHandler) UploadStoreImage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+	id := r.PathValue("id")
+	log.InfoContext(ctx, "handler UploadStoreImage start", slog.String("store_id", id))
+
+	actor, ok := actorFromContext(ctx)
+	if !ok {
+		log.WarnContext(ctx, "handler UploadStoreImage unauthorized")
+		h.rs.Error(ctx, w, http.StatusUnauthorized, "UploadStoreImage", domain.ErrUnauthorized, nil)
+		return
+	}
+
+	// запас на заголовки multipart сверх предела самого файла
+	r.Body = http.MaxBytesReader(w, r.Body, 6<<20)
+	file, header, err := r.FormFile("image")
+	if err != nil {
+		log.WarnContext(ctx, "handler UploadStoreImage form failed", slog.Any("err", err))
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			h.rs.Error(ctx, w, http.StatusRequestEntityTooLarge, "UploadStoreImage", domain.ErrStoreImageSize, nil)
+			return
+		}
+		h.rs.Error(ctx, w, http.StatusBadRequest, "UploadStoreImage", domain.ErrRequestParams, err)
+		return
+	}
+	defer file.Close()
+
+	name, err := h.uc.UploadStoreImage(ctx, actor, id, file, header.Filename)
+	if err != nil {
+		log.WarnContext(ctx, "handler UploadStoreImage usecase failed", slog.Any("err", err), slog.String("store_id", id))
+		h.storeManageError(ctx, w, "UploadStoreImage", err)
+		return
+	}
+
+	log.InfoContext(ctx, "handler UploadStoreImage success", slog.String("store_id", id), slog.String("file", name))
+	h.rs.Send(ctx, w, http.StatusOK, &transport.StoreImageResponse{CardImg: "/images/stores/" + name})
+}
+
+// DeleteStoreImage godoc
+// @Summary Удалить карточку магазина
+// @Tags stores
+// @Param id path string true "ID магазина"
+// @Success 204 "Изображение удалено"
+// @Failure 403 {object} http_response.ErrResponse "Магазин принадлежит другому владельцу"
+// @Failure 404 {object} http_response.ErrResponse "Магазин не найден"
+// @Failure 500 {object} http_response.ErrResponse "Внутренняя ошибка сервера"
+// @Router /stores/{id}/image [delete]
+func (h *StoreManageHandler) DeleteStoreImage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+	id := r.PathValue("id")
+	log.InfoContext(ctx, "handler DeleteStoreImage start", slog.String("store_id", id))
+
+	actor, ok := actorFromContext(ctx)
+	if !ok {
+		log.WarnContext(ctx, "handler DeleteStoreImage unauthorized")
+		h.rs.Error(ctx, w, http.StatusUnauthorized, "DeleteStoreImage", domain.ErrUnauthorized, nil)
+		return
+	}
+
+	if err := h.uc.DeleteStoreImage(ctx, actor, id); err != nil {
+		log.WarnContext(ctx, "handler DeleteStoreImage usecase failed", slog.Any("err", err), slog.String("store_id", id))
+		h.storeManageError(ctx, w, "DeleteStoreImage", err)
+		return
+	}
+
+	log.InfoContext(ctx, "handler DeleteStoreImage success", slog.String("store_id", id))
+	w.WriteHeader(http.StatusNoContent)
+}
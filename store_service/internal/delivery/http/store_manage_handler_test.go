@@ -0,0 +1,331 @@
+package http
+
+import (
+	"apple_backend/pkg/http_response"
+	"apple_backend/pkg/rbac"
+	"apple_backend/store_service/internal/delivery/middlewares"
+	"apple_backend/store_service/internal/delivery/mock"
+	"apple_backend/store_service/internal/delivery/transport"
+	"apple_backend/store_service/internal/domain"
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	manageStoreID = "00000000-0000-0000-0000-000000000001"
+	manageUserID  = "00000000-0000-0000-0000-000000000002"
+)
+
+// manageRequest — запрос пользователя с ролями roles к магазину manageStoreID
+func manageRequest(method, body string, roles ...string) *http.Request {
+	req := httptest.NewRequest(method, "/stores/"+manageStoreID, bytes.NewBufferString(body))
+	req.SetPathValue("id", manageStoreID)
+	ctx := middlewares.WithUserID(context.Background(), manageUserID)
+	ctx = middlewares.WithRoles(ctx, roles)
+	return req.WithContext(ctx)
+}
+
+func TestStoreManageHandler_CreateStore(t *testing.T) {
+	type testCase struct {
+		name              string
+		request           func() *http.Request
+		mockSetup         func(uc *mock.MockStoreManageUsecaseInterface)
+		expectedCode      int
+		expectedResult    *transport.StoreResponse
+		expectedErrResult *http_response.ErrResponse
+	}
+
+	body := `{"name":"Store","description":"достаточно длинное описание магазина","city_id":"` +
+		manageStoreID + `","address":"Address","open_at":"09:00","closed_at":"21:00"}`
+	owner := domain.Actor{UserID: manageUserID}
+	store := &domain.StoreAgg{ID: manageStoreID, Name: "Store", CardImg: "card.png"}
+
+	tests := []testCase{
+		{
+			name:    "успешный вызов",
+			request: func() *http.Request { return manageRequest(http.MethodPost, body) },
+			mockSetup: func(uc *mock.MockStoreManageUsecaseInterface) {
+				uc.EXPECT().
+					CreateStore(gomock.Any(), owner, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ domain.Actor, in *domain.StoreInput) (*domain.StoreAgg, error) {
+						require.Equal(t, "Store", *in.Name)
+						require.Equal(t, "09:00", *in.OpenAt)
+						return store, nil
+					})
+			},
+			expectedCode: http.StatusCreated,
+			expectedResult: &transport.StoreResponse{
+				ID:      manageStoreID,
+				Name:    "Store",
+				CardImg: "/images/stores/card.png",
+			},
+		},
+		{
+			name: "не авторизован",
+			request: func() *http.Request {
+				return httptest.NewRequest(http.MethodPost, "/stores", bytes.NewBufferString(body))
+			},
+			mockSetup:         func(uc *mock.MockStoreManageUsecaseInterface) {},
+			expectedCode:      http.StatusUnauthorized,
+			expectedErrResult: &http_response.ErrResponse{Err: domain.ErrUnauthorized.Error()},
+		},
+		{
+			name:              "неверный формат json",
+			request:           func() *http.Request { return manageRequest(http.MethodPost, "запрос") },
+			mockSetup:         func(uc *mock.MockStoreManageUsecaseInterface) {},
+			expectedCode:      http.StatusBadRequest,
+			expectedErrResult: &http_response.ErrResponse{Err: domain.ErrRequestParams.Error()},
+		},
+		{
+			name:    "описание не проходит ограничения таблицы",
+			request: func() *http.Request { return manageRequest(http.MethodPost, body) },
+			mockSetup: func(uc *mock.MockStoreManageUsecaseInterface) {
+				uc.EXPECT().CreateStore(gomock.Any(), owner, gomock.Any()).Return(nil, domain.ErrStoreDescription)
+			},
+			expectedCode:      http.StatusBadRequest,
+			expectedErrResult: &http_response.ErrResponse{Err: domain.ErrStoreDescription.Error()},
+		},
+		{
+			name:    "уже существующий магазин",
+			request: func() *http.Request { return manageRequest(http.MethodPost, body) },
+			mockSetup: func(uc *mock.MockStoreManageUsecaseInterface) {
+				uc.EXPECT().CreateStore(gomock.Any(), owner, gomock.Any()).Return(nil, domain.ErrStoreExist)
+			},
+			expectedCode:      http.StatusConflict,
+			expectedErrResult: &http_response.ErrResponse{Err: domain.ErrStoreExist.Error()},
+		},
+		{
+			name:    "внутренняя ошибка",
+			request: func() *http.Request { return manageRequest(http.MethodPost, body) },
+			mockSetup: func(uc *mock.MockStoreManageUsecaseInterface) {
+				uc.EXPECT().CreateStore(gomock.Any(), owner, gomock.Any()).Return(nil, context.DeadlineExceeded)
+			},
+			expectedCode:      http.StatusInternalServerError,
+			expectedErrResult: &http_response.ErrResponse{Err: domain.ErrInternalServer.Error()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uc := mock.NewMockStoreManageUsecaseInterface(ctrl)
+			tt.mockSetup(uc)
+			handler := NewStoreManageHandler(uc)
+
+			w := httptest.NewRecorder()
+			handler.CreateStore(w, tt.request())
+
+			require.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedResult != nil {
+				require.JSONEq(t, parseJSON(tt.expectedResult), w.Body.String())
+			}
+			if tt.expectedErrResult != nil {
+				require.JSONEq(t, parseJSON(tt.expectedErrResult), w.Body.String())
+			}
+		})
+	}
+}
+
+func TestStoreManageHandler_UpdateStore(t *testing.T) {
+	type testCase struct {
+		name              string
+		roles             []string
+		mockSetup         func(uc *mock.MockStoreManageUsecaseInterface)
+		expectedCode      int
+		expectedErrResult *http_response.ErrResponse
+	}
+
+	body := `{"name":"New"}`
+	owner := domain.Actor{UserID: manageUserID}
+	admin := domain.Actor{UserID: manageUserID, Admin: true}
+	store := &domain.StoreAgg{ID: manageStoreID, Name: "New"}
+
+	tests := []testCase{
+		{
+			name: "успешный вызов владельцем",
+			mockSetup: func(uc *mock.MockStoreManageUsecaseInterface) {
+				uc.EXPECT().UpdateStore(gomock.Any(), owner, manageStoreID, gomock.Any()).Return(store, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:  "администратор меняет чужой магазин",
+			roles: []string{rbac.RoleAdmin},
+			mockSetup: func(uc *mock.MockStoreManageUsecaseInterface) {
+				uc.EXPECT().UpdateStore(gomock.Any(), admin, manageStoreID, gomock.Any()).Return(store, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name: "магазин другого владельца",
+			mockSetup: func(uc *mock.MockStoreManageUsecaseInterface) {
+				uc.EXPECT().UpdateStore(gomock.Any(), owner, manageStoreID, gomock.Any()).Return(nil, domain.ErrForbidden)
+			},
+			expectedCode:      http.StatusForbidden,
+			expectedErrResult: &http_response.ErrResponse{Err: domain.ErrForbidden.Error()},
+		},
+		{
+			name: "магазин не найден",
+			mockSetup: func(uc *mock.MockStoreManageUsecaseInterface) {
+				uc.EXPECT().UpdateStore(gomock.Any(), owner, manageStoreID, gomock.Any()).Return(nil, domain.ErrRowsNotFound)
+			},
+			expectedCode:      http.StatusNotFound,
+			expectedErrResult: &http_response.ErrResponse{Err: domain.ErrRowsNotFound.Error()},
+		},
+		{
+			name: "некорректное время работы",
+			mockSetup: func(uc *mock.MockStoreManageUsecaseInterface) {
+				uc.EXPECT().UpdateStore(gomock.Any(), owner, manageStoreID, gomock.Any()).Return(nil, domain.ErrStoreHours)
+			},
+			expectedCode:      http.StatusBadRequest,
+			expectedErrResult: &http_response.ErrResponse{Err: domain.ErrStoreHours.Error()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uc := mock.NewMockStoreManageUsecaseInterface(ctrl)
+			tt.mockSetup(uc)
+			handler := NewStoreManageHandler(uc)
+
+			w := httptest.NewRecorder()
+			handler.UpdateStore(w, manageRequest(http.MethodPatch, body, tt.roles...))
+
+			require.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedErrResult != nil {
+				require.JSONEq(t, parseJSON(tt.expectedErrResult), w.Body.String())
+			}
+		})
+	}
+}
+
+// imageRequest — multipart-запрос с файлом filename в поле image
+func imageRequest(t *testing.T, filename string, data []byte) *http.Request {
+	t.Helper()
+	body := &bytes.Buffer{}
+	mw := multipart.NewWriter(body)
+	part, err := mw.CreateFormFile("image", filename)
+	require.NoError(t, err)
+	_, err = part.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, mw.Close())
+
+	req := manageRequest(http.MethodPut, "")
+	req.Body = io.NopCloser(body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	return req
+}
+
+func TestStoreManageHandler_UploadStoreImage(t *testing.T) {
+	type testCase struct {
+		name              string
+		request           func(t *testing.T) *http.Request
+		mockSetup         func(uc *mock.MockStoreManageUsecaseInterface)
+		expectedCode      int
+		expectedResult    *transport.StoreImageResponse
+		expectedErrResult *http_response.ErrResponse
+	}
+
+	owner := domain.Actor{UserID: manageUserID}
+	png := func(t *testing.T) *http.Request { return imageRequest(t, "card.png", []byte("png")) }
+
+	tests := []testCase{
+		{
+			name:    "успешный вызов",
+			request: png,
+			mockSetup: func(uc *mock.MockStoreManageUsecaseInterface) {
+				uc.EXPECT().
+					UploadStoreImage(gomock.Any(), owner, manageStoreID, gomock.Any(), "card.png").
+					Return(manageStoreID+"_1.png", nil)
+			},
+			expectedCode:   http.StatusOK,
+			expectedResult: &transport.StoreImageResponse{CardImg: "/images/stores/" + manageStoreID + "_1.png"},
+		},
+		{
+			name:    "недопустимый тип файла",
+			request: func(t *testing.T) *http.Request { return imageRequest(t, "card.exe", []byte("MZ")) },
+			mockSetup: func(uc *mock.MockStoreManageUsecaseInterface) {
+				uc.EXPECT().
+					UploadStoreImage(gomock.Any(), owner, manageStoreID, gomock.Any(), "card.exe").
+					Return("", domain.ErrStoreImage)
+			},
+			expectedCode:      http.StatusBadRequest,
+			expectedErrResult: &http_response.ErrResponse{Err: domain.ErrStoreImage.Error()},
+		},
+		{
+			name:    "файл больше 5 МБ по проверке usecase",
+			request: png,
+			mockSetup: func(uc *mock.MockStoreManageUsecaseInterface) {
+				uc.EXPECT().
+					UploadStoreImage(gomock.Any(), owner, manageStoreID, gomock.Any(), "card.png").
+					Return("", domain.ErrStoreImageSize)
+			},
+			expectedCode:      http.StatusRequestEntityTooLarge,
+			expectedErrResult: &http_response.ErrResponse{Err: domain.ErrStoreImageSize.Error()},
+		},
+		{
+			name: "тело запроса больше предела",
+			request: func(t *testing.T) *http.Request {
+				return imageRequest(t, "card.png", make([]byte, 7<<20))
+			},
+			mockSetup:         func(uc *mock.MockStoreManageUsecaseInterface) {},
+			expectedCode:      http.StatusRequestEntityTooLarge,
+			expectedErrResult: &http_response.ErrResponse{Err: domain.ErrStoreImageSize.Error()},
+		},
+		{
+			name: "нет файла",
+			request: func(t *testing.T) *http.Request {
+				return manageRequest(http.MethodPut, "")
+			},
+			mockSetup:         func(uc *mock.MockStoreManageUsecaseInterface) {},
+			expectedCode:      http.StatusBadRequest,
+			expectedErrResult: &http_response.ErrResponse{Err: domain.ErrRequestParams.Error()},
+		},
+		{
+			name:    "магазин другого владельца",
+			request: png,
+			mockSetup: func(uc *mock.MockStoreManageUsecaseInterface) {
+				uc.EXPECT().
+					UploadStoreImage(gomock.Any(), owner, manageStoreID, gomock.Any(), "card.png").
+					Return("", domain.ErrForbidden)
+			},
+			expectedCode:      http.StatusForbidden,
+			expectedErrResult: &http_response.ErrResponse{Err: domain.ErrForbidden.Error()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uc := mock.NewMockStoreManageUsecaseInterface(ctrl)
+			tt.mockSetup(uc)
+			handler := NewStoreManageHandler(uc)
+
+			w := httptest.NewRecorder()
+			handler.UploadStoreImage(w, tt.request(t))
+
+			require.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedResult != nil {
+				require.JSONEq(t, parseJSON(tt.expectedResult), w.Body.String())
+			}
+			if tt.expectedErrResult != nil {
+				require.JSONEq(t, parseJSON(tt.expectedErrResult), w.Body.String())
+			}
+		})
+	}
+}
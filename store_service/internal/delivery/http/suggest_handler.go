@@ -0,0 +1,78 @@
+package http
+
+import (
+	"apple_backend/pkg/http_response"
+	"apple_backend/pkg/logger"
+	"apple_backend/store_service/internal/delivery/transport"
+	"apple_backend/store_service/internal/domain"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type SuggestUsecaseInterface interface {
+	Suggest(ctx context.Context, prefix, cityID string, limit int) (*domain.Suggestions, error)
+}
+
+type SuggestHandler struct {
+	uc SuggestUsecaseInterface
+	rs *http_response.ResponseSender
+}
+
+func NewSuggestHandler(uc SuggestUsecaseInterface) *SuggestHandler {
+	return &SuggestHandler{
+		uc: uc,
+		rs: http_response.NewResponseSender(logger.Global()),
+	}
+}
+
+// NewSuggestRouter принимает готовый usecase: его индекс перестраивает
+// фоновая задача в app.go
+func NewSuggestRouter(mux *http.ServeMux, uc SuggestUsecaseInterface, apiPrefix string) {
+	suggestHandler := NewSuggestHandler(uc)
+
+	mux.HandleFunc("GET "+apiPrefix+"search/suggest", suggestHandler.Suggest)
+}
+
+// Suggest godoc
+// @Summary Подсказки при вводе запроса
+// @Description Названия магазинов, товаров, теги и типы кухни, в которых есть слово с началом q, в том числе в транслитерации. Выше популярные и высоко оцененные. Отвечает из индекса в памяти, который обновляется при изменении каталога.
+// @Tags search
+// @Produce json
+// @Param q query string true "Начало запроса"
+// @Param city_id query string false "ID города"
+// @Param limit query int false "Подсказок каждого вида, по умолчанию 5, не больше 10"
+// @Success 200 {object} transport.Suggestions
+// @Failure 400 {object} http_response.ErrResponse "Ошибка входных данных"
+// @Router /search/suggest [get]
+func (h *SuggestHandler) Suggest(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.FromContext(ctx)
+
+	q := r.URL.Query()
+	limit := 0
+	if limitStr := q.Get("limit"); limitStr != "" {
+		var err error
+		if limit, err = strconv.Atoi(limitStr); err != nil || limit <= 0 {
+			log.WarnContext(ctx, "handler Suggest invalid limit", slog.String("limit", limitStr))
+			h.rs.Error(ctx, w, http.StatusBadRequest, "Suggest", domain.ErrRequestParams, errors.New("invalid limit"))
+			return
+		}
+	}
+
+	suggestions, err := h.uc.Suggest(ctx, q.Get("q"), q.Get("city_id"), limit)
+	if err != nil {
+		if errors.Is(err, domain.ErrRequestParams) {
+			log.WarnContext(ctx, "handler Suggest invalid params", slog.Any("err", err))
+			h.rs.Error(ctx, w, http.StatusBadRequest, "Suggest", domain.ErrRequestParams, nil)
+			return
+		}
+		log.ErrorContext(ctx, "handler Suggest usecase failed", slog.Any("err", err))
+		h.rs.Error(ctx, w, http.StatusInternalServerError, "Suggest", domain.ErrInternalServer, err)
+		return
+	}
+
+	h.rs.Send(ctx, w, http.StatusOK, transport.ToSuggestionsResponse(suggestions))
+}
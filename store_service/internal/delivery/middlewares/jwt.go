@@ -1,7 +1,12 @@
 package middlewares
 
 import (
+	"apple_backend/pkg/apikey"
+	"apple_backend/pkg/logger"
+	"apple_backend/pkg/session"
 	"context"
+	"errors"
+	"log/slog"
 	"net/http"
 
 	"github.com/golang-jwt/jwt/v4"
@@ -13,6 +18,8 @@ type ctxKey string
 
 var UserIDKey ctxKey = "user_id"
 
+var RolesKey ctxKey = "roles"
+
 func WithUserID(ctx context.Context, userID string) context.Context {
 	return context.WithValue(ctx, UserIDKey, userID)
 }
@@ -23,31 +30,95 @@ func UserIDFromContext(ctx context.Context) (string, bool) {
 	return id, ok
 }
 
-func AuthMiddleware(next http.Handler, jwtSecret string) http.Handler {
-	secret := []byte(jwtSecret)
+func WithRoles(ctx context.Context, roles []string) context.Context {
+	return context.WithValue(ctx, RolesKey, roles)
+}
+
+func RolesFromContext(ctx context.Context) []string {
+	roles, _ := ctx.Value(RolesKey).([]string)
+	return roles
+}
 
+// AuthMiddleware проверяет подпись токена публичными ключами auth_service
+// (keyfunc выбирает ключ по kid, см. jwtkeys.RemoteKeySet) и что сессия не отозвана.
+// Интеграции вместо cookie передают API-ключ в Authorization: Bearer; такие
+// запросы пропускает apiKeys только на разрешенные им маршруты. apiKeys
+// может быть nil — тогда ключи не принимаются.
+func AuthMiddleware(next http.Handler, keyfunc jwt.Keyfunc, sessions session.Checker, apiKeys *apikey.Guard) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token, ok := apikey.BearerToken(r); ok && apiKeys != nil {
+			apiKeyAuth(w, r, next, apiKeys, token)
+			return
+		}
 		c, err := r.Cookie(JwtCookieName)
 		if err != nil {
 			http.Error(w, `{"error":"unauthorized"}`, http.StatusUnauthorized)
 			return
 		}
 		type claims struct {
-			UserID string `json:"user_id"`
+			UserID    string   `json:"user_id"`
+			SessionID string   `json:"sid"`
+			Roles     []string `json:"roles"`
 			jwt.RegisteredClaims
 		}
 		cl := &claims{}
-		tkn, err := jwt.ParseWithClaims(c.Value, cl, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, jwt.ErrSignatureInvalid
-			}
-			return secret, nil
-		})
-		if err != nil || !tkn.Valid || cl.UserID == "" {
+		tkn, err := jwt.ParseWithClaims(c.Value, cl, keyfunc)
+		if err != nil || !tkn.Valid || cl.UserID == "" || cl.SessionID == "" {
+			http.Error(w, `{"error":"unauthorized"}`, http.StatusUnauthorized)
+			return
+		}
+		active, err := sessions.IsActive(r.Context(), cl.SessionID)
+		if err != nil {
+			logger.FromContext(r.Context()).ErrorContext(r.Context(), "AuthMiddleware session check failed",
+				slog.Any("err", err), slog.String("session_id", cl.SessionID))
+			http.Error(w, `{"error":"internal server error"}`, http.StatusInternalServerError)
+			return
+		}
+		if !active {
 			http.Error(w, `{"error":"unauthorized"}`, http.StatusUnauthorized)
 			return
 		}
 		ctx := WithUserID(r.Context(), cl.UserID)
+		ctx = WithRoles(ctx, cl.Roles)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+func apiKeyAuth(w http.ResponseWriter, r *http.Request, next http.Handler, apiKeys *apikey.Guard, token string) {
+	key, err := apiKeys.Authenticate(r.Context(), r, token)
+	switch {
+	case errors.Is(err, apikey.ErrInvalidKey):
+		http.Error(w, `{"error":"unauthorized"}`, http.StatusUnauthorized)
+		return
+	case errors.Is(err, apikey.ErrForbidden):
+		http.Error(w, `{"error":"forbidden"}`, http.StatusForbidden)
+		return
+	case err != nil:
+		logger.FromContext(r.Context()).ErrorContext(r.Context(), "AuthMiddleware api key check failed", slog.Any("err", err))
+		http.Error(w, `{"error":"internal server error"}`, http.StatusInternalServerError)
+		return
+	}
+	logger.FromContext(r.Context()).InfoContext(r.Context(), "AuthMiddleware api key accepted",
+		slog.String("key_id", key.ID), slog.String("user_id", key.UserID))
+	ctx := WithUserID(r.Context(), key.UserID)
+	ctx = WithRoles(ctx, key.Roles)
+	next.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GuestOrAuth отправляет запрос в authed, если в нем есть учетные данные
+// (cookie сессии или API-ключ), и в guest — если их нет. Просроченный токен
+// не превращает пользователя в гостя: authed ответит 401, и клиент обновит
+// сессию.
+func GuestOrAuth(authed, guest http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := apikey.BearerToken(r); ok {
+			authed.ServeHTTP(w, r)
+			return
+		}
+		if _, err := r.Cookie(JwtCookieName); err == nil {
+			authed.ServeHTTP(w, r)
+			return
+		}
+		guest.ServeHTTP(w, r)
+	})
+}
@@ -1,6 +1,7 @@
 package middlewares
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -10,13 +11,24 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+type testClaims struct {
+	UserID    string `json:"user_id"`
+	SessionID string `json:"sid"`
+	jwt.RegisteredClaims
+}
+
+type activeSessions struct{}
+
+func (activeSessions) IsActive(context.Context, string) (bool, error) { return true, nil }
+
 func TestAuthMiddleware(t *testing.T) {
 	secret := "secret"
+	keyfunc := func(*jwt.Token) (interface{}, error) { return []byte(secret), nil }
 
 	validToken := func() string {
-		claims := &Claims{
-			UserID: "user123",
-			Email:  "test@example.com",
+		claims := &testClaims{
+			UserID:    "user123",
+			SessionID: "sid1",
 			RegisteredClaims: jwt.RegisteredClaims{
 				ExpiresAt: jwt.NewNumericDate(time.Now().Add(1 * time.Hour)),
 				IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -28,8 +40,9 @@ func TestAuthMiddleware(t *testing.T) {
 	}
 
 	expiredToken := func() string {
-		claims := &Claims{
-			UserID: "user123",
+		claims := &testClaims{
+			UserID:    "user123",
+			SessionID: "sid1",
 			RegisteredClaims: jwt.RegisteredClaims{
 				ExpiresAt: jwt.NewNumericDate(time.Now().Add(-1 * time.Hour)),
 				IssuedAt:  jwt.NewNumericDate(time.Now().Add(-2 * time.Hour)),
@@ -87,7 +100,7 @@ func TestAuthMiddleware(t *testing.T) {
 				w.WriteHeader(http.StatusOK)
 			})
 
-			handler := AuthMiddleware(next, secret)
+			handler := AuthMiddleware(next, keyfunc, activeSessions{}, nil)
 
 			req := httptest.NewRequest(http.MethodGet, "/protected", nil)
 			if tt.setCookie {
@@ -106,3 +119,23 @@ func TestAuthMiddleware(t *testing.T) {
 		})
 	}
 }
+
+func TestCorsMiddlewarePreflight(t *testing.T) {
+	t.Setenv("ALLOWED_ORIGINS", "http://localhost:3000")
+	handler := CorsMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("preflight must not reach the handler")
+	}))
+
+	for _, path := range []string{"/api/v0/stores/s1", "/api/v0/stores/s1/menu/i1"} {
+		req := httptest.NewRequest(http.MethodOptions, path, nil)
+		req.Header.Set("Origin", "http://localhost:3000")
+		req.Header.Set("Access-Control-Request-Method", http.MethodPatch)
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Equal(t, "http://localhost:3000", rec.Header().Get("Access-Control-Allow-Origin"))
+		require.Contains(t, rec.Header().Get("Access-Control-Allow-Methods"), http.MethodPatch)
+	}
+}
@@ -0,0 +1,36 @@
+package middlewares
+
+import (
+	"apple_backend/pkg/account"
+	"apple_backend/pkg/logger"
+	"log/slog"
+	"net/http"
+)
+
+// RequireVerifiedEmail пропускает запрос, только если пользователь из
+// контекста подтвердил email. Должен стоять после AuthMiddleware.
+func RequireVerifiedEmail(next http.HandlerFunc, checker account.VerificationChecker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		userID, ok := UserIDFromContext(ctx)
+		if !ok || userID == "" {
+			http.Error(w, `{"error":"unauthorized"}`, http.StatusUnauthorized)
+			return
+		}
+
+		verified, err := checker.IsEmailVerified(ctx, userID)
+		if err != nil {
+			logger.FromContext(ctx).ErrorContext(ctx, "RequireVerifiedEmail check failed",
+				slog.Any("err", err), slog.String("user_id", userID))
+			http.Error(w, `{"error":"internal server error"}`, http.StatusInternalServerError)
+			return
+		}
+		if !verified {
+			logger.FromContext(ctx).WarnContext(ctx, "RequireVerifiedEmail email not verified", slog.String("user_id", userID))
+			http.Error(w, `{"error":"email_not_verified"}`, http.StatusForbidden)
+			return
+		}
+
+		next(w, r)
+	}
+}
@@ -66,18 +66,33 @@ func (mr *MockOrderUsecaseInterfaceMockRecorder) GetOrder(ctx, orderID, userID i
 }
 
 // GetOrdersUser mocks base method.
-func (m *MockOrderUsecaseInterface) GetOrdersUser(ctx context.Context, userID string) ([]*domain.Order, error) {
+func (m *MockOrderUsecaseInterface) GetOrdersUser(ctx context.Context, filter *domain.OrderFilter) ([]*domain.Order, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetOrdersUser", ctx, userID)
+	ret := m.ctrl.Call(m, "GetOrdersUser", ctx, filter)
 	ret0, _ := ret[0].([]*domain.Order)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetOrdersUser indicates an expected call of GetOrdersUser.
-func (mr *MockOrderUsecaseInterfaceMockRecorder) GetOrdersUser(ctx, userID interface{}) *gomock.Call {
+func (mr *MockOrderUsecaseInterfaceMockRecorder) GetOrdersUser(ctx, filter interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrdersUser", reflect.TypeOf((*MockOrderUsecaseInterface)(nil).GetOrdersUser), ctx, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrdersUser", reflect.TypeOf((*MockOrderUsecaseInterface)(nil).GetOrdersUser), ctx, filter)
+}
+
+// GetStoreOrders mocks base method.
+func (m *MockOrderUsecaseInterface) GetStoreOrders(ctx context.Context, actor domain.Actor, filter *domain.OrderFilter) ([]*domain.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStoreOrders", ctx, actor, filter)
+	ret0, _ := ret[0].([]*domain.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStoreOrders indicates an expected call of GetStoreOrders.
+func (mr *MockOrderUsecaseInterfaceMockRecorder) GetStoreOrders(ctx, actor, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStoreOrders", reflect.TypeOf((*MockOrderUsecaseInterface)(nil).GetStoreOrders), ctx, actor, filter)
 }
 
 // UpdateOrderStatus mocks base method.
@@ -35,20 +35,6 @@ func (m *MockStoreUsecaseInterface) EXPECT() *MockStoreUsecaseInterfaceMockRecor
 	return m.recorder
 }
 
-// CreateStore mocks base method.
-func (m *MockStoreUsecaseInterface) CreateStore(ctx context.Context, name, description, cityID, address, cardImg, openAt, closedAt string, rating float64) error {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateStore", ctx, name, description, cityID, address, cardImg, openAt, closedAt, rating)
-	ret0, _ := ret[0].(error)
-	return ret0
-}
-
-// CreateStore indicates an expected call of CreateStore.
-func (mr *MockStoreUsecaseInterfaceMockRecorder) CreateStore(ctx, name, description, cityID, address, cardImg, openAt, closedAt, rating interface{}) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateStore", reflect.TypeOf((*MockStoreUsecaseInterface)(nil).CreateStore), ctx, name, description, cityID, address, cardImg, openAt, closedAt, rating)
-}
-
 // GetCities mocks base method.
 func (m *MockStoreUsecaseInterface) GetCities(ctx context.Context) ([]*domain.City, error) {
 	m.ctrl.T.Helper()
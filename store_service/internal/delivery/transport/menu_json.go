@@ -0,0 +1,68 @@
+package transport
+
+import (
+	"apple_backend/store_service/internal/domain"
+	"time"
+)
+
+type MenuItem struct {
+	// ID из таблицы store_item
+	ID          string    `json:"id"`
+	ItemID      string    `json:"item_id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	CardImg     string    `json:"card_img"`
+	Price       float64   `json:"price"`
+	TypesID     []string  `json:"types_id"`
+	Archived    bool      `json:"archived"`
+	UpdatedAt   time.Time `json:"updated_at"`
+} // @name MenuItem
+
+// MenuItemRequest — тело POST и PATCH меню. В POST задается либо item_id
+// существующего товара, либо name и types_id нового; в PATCH обязателен
+// updated_at из последнего ответа, отсутствующие поля не меняются.
+type MenuItemRequest struct {
+	ItemID      *string   `json:"item_id"`
+	Name        *string   `json:"name"`
+	Description *string   `json:"description"`
+	Price       *float64  `json:"price"`
+	TypesID     []string  `json:"types_id"`
+	UpdatedAt   time.Time `json:"updated_at"`
+} // @name MenuItemRequest
+
+func ToMenuItemResponse(item *domain.MenuItem) *MenuItem {
+	cardImg := ""
+	if item.CardImg != "" {
+		cardImg = "/images/items/" + item.CardImg
+	}
+	return &MenuItem{
+		ID:          item.ID,
+		ItemID:      item.ItemID,
+		Name:        item.Name,
+		Description: item.Description,
+		CardImg:     cardImg,
+		Price:       item.Price,
+		TypesID:     item.TypesID,
+		Archived:    item.Archived,
+		UpdatedAt:   item.UpdatedAt,
+	}
+}
+
+func ToMenuResponse(items []*domain.MenuItem) []*MenuItem {
+	responses := make([]*MenuItem, 0, len(items))
+	for _, item := range items {
+		responses = append(responses, ToMenuItemResponse(item))
+	}
+	return responses
+}
+
+func FromMenuItemRequest(req *MenuItemRequest) *domain.MenuItemInput {
+	return &domain.MenuItemInput{
+		ItemID:      req.ItemID,
+		Name:        req.Name,
+		Description: req.Description,
+		Price:       req.Price,
+		TypeIDs:     req.TypesID,
+		UpdatedAt:   req.UpdatedAt,
+	}
+}
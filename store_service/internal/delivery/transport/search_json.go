@@ -0,0 +1,88 @@
+package transport
+
+import "apple_backend/store_service/internal/domain"
+
+type SearchItem struct {
+	// ID из таблицы store_item
+	ID      string  `json:"id"`
+	Name    string  `json:"name"`
+	CardImg string  `json:"card_img"`
+	Price   float64 `json:"price"`
+} // @name SearchItem
+
+// SearchStore — магазин в выдаче поиска и найденные в нем позиции
+type SearchStore struct {
+	ID          string        `json:"id"`
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+	CityID      string        `json:"city_id"`
+	Address     string        `json:"address"`
+	CardImg     string        `json:"card_img"`
+	Rating      float64       `json:"rating"`
+	Items       []*SearchItem `json:"items"`
+} // @name SearchStore
+
+func toSearchStoreResponse(store *domain.SearchStore) *SearchStore {
+	resp := &SearchStore{
+		ID:          store.ID,
+		Name:        store.Name,
+		Description: store.Description,
+		CityID:      store.CityID,
+		Address:     store.Address,
+		Rating:      store.Rating,
+		Items:       make([]*SearchItem, 0, len(store.Items)),
+	}
+	if store.CardImg != "" {
+		resp.CardImg = "/images/stores/" + store.CardImg
+	}
+	for _, item := range store.Items {
+		searchItem := &SearchItem{
+			ID:    item.ID,
+			Name:  item.Name,
+			Price: item.Price,
+		}
+		if item.CardImg != "" {
+			searchItem.CardImg = "/images/items/" + item.CardImg
+		}
+		resp.Items = append(resp.Items, searchItem)
+	}
+	return resp
+}
+
+func ToSearchResponse(stores []*domain.SearchStore) []*SearchStore {
+	responses := make([]*SearchStore, 0, len(stores))
+	for _, store := range stores {
+		responses = append(responses, toSearchStoreResponse(store))
+	}
+	return responses
+}
+
+type Suggestion struct {
+	ID   string `json:"id"`
+	Text string `json:"text"`
+} // @name Suggestion
+
+// Suggestions — подсказки по видам, лучшие первыми
+type Suggestions struct {
+	Stores []*Suggestion `json:"stores"`
+	Items  []*Suggestion `json:"items"`
+	Tags   []*Suggestion `json:"tags"`
+	Types  []*Suggestion `json:"types"`
+} // @name Suggestions
+
+func toSuggestionsResponse(suggestions []*domain.Suggestion) []*Suggestion {
+	responses := make([]*Suggestion, 0, len(suggestions))
+	for _, s := range suggestions {
+		responses = append(responses, &Suggestion{ID: s.ID, Text: s.Text})
+	}
+	return responses
+}
+
+func ToSuggestionsResponse(s *domain.Suggestions) *Suggestions {
+	return &Suggestions{
+		Stores: toSuggestionsResponse(s.Stores),
+		Items:  toSuggestionsResponse(s.Items),
+		Tags:   toSuggestionsResponse(s.Tags),
+		Types:  toSuggestionsResponse(s.Types),
+	}
+}
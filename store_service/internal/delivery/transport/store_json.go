@@ -1,20 +1,55 @@
 package transport
 
-import "apple_backend/store_service/internal/domain"
+import (
+	"apple_backend/store_service/internal/domain"
+	"encoding/json"
+	"math"
+)
 
 type StoreResponse struct {
-	ID          string   `json:"id"`
-	Name        string   `json:"name"`
-	Description string   `json:"description"`
-	CityID      string   `json:"city_id"`
-	Address     string   `json:"address"`
-	CardImg     string   `json:"card_img"`
-	Rating      float64  `json:"rating"`
-	TagsID      []string `json:"tags_id"`
-	OpenAt      string   `json:"open_at"`
-	ClosedAt    string   `json:"closed_at"`
+	ID           string   `json:"id"`
+	Name         string   `json:"name"`
+	Description  string   `json:"description"`
+	CityID       string   `json:"city_id"`
+	Address      string   `json:"address"`
+	CardImg      string   `json:"card_img"`
+	Rating       float64  `json:"rating"`
+	TagsID       []string `json:"tags_id"`
+	CategoriesID []string `json:"categories_id"`
+	OpenAt       string   `json:"open_at"`
+	ClosedAt     string   `json:"closed_at"`
+	Lat          *float64 `json:"lat"`
+	Lon          *float64 `json:"lon"`
+	// DeliveryRadiusM или DeliveryArea — зона доставки магазина
+	DeliveryRadiusM *int            `json:"delivery_radius_m,omitempty"`
+	DeliveryArea    json.RawMessage `json:"delivery_area,omitempty" swaggertype:"object"`
+	// DistanceM — расстояние в метрах до точки lat/lon из запроса
+	DistanceM *float64 `json:"distance_m,omitempty"`
 } // @name StoreResponse
 
+// StoreRequest — тело POST и PATCH /stores. В PATCH отсутствующее поле не
+// меняется, а tags_id и categories_id заменяют списки целиком. Координаты и
+// зона доставки передаются вместе: lat, lon и delivery_radius_m или
+// delivery_area (GeoJSON Polygon или MultiPolygon)
+type StoreRequest struct {
+	Name            *string         `json:"name"`
+	Description     *string         `json:"description"`
+	CityID          *string         `json:"city_id"`
+	Address         *string         `json:"address"`
+	OpenAt          *string         `json:"open_at"`
+	ClosedAt        *string         `json:"closed_at"`
+	TagsID          []string        `json:"tags_id"`
+	CategoriesID    []string        `json:"categories_id"`
+	Lat             *float64        `json:"lat"`
+	Lon             *float64        `json:"lon"`
+	DeliveryRadiusM *int            `json:"delivery_radius_m"`
+	DeliveryArea    json.RawMessage `json:"delivery_area" swaggertype:"object"`
+} // @name StoreRequest
+
+type StoreImageResponse struct {
+	CardImg string `json:"card_img"`
+} // @name StoreImageResponse
+
 type CityResponse struct {
 	ID   string `json:"id"`
 	Name string `json:"name"`
@@ -37,17 +72,29 @@ func ToStoreResponse(store *domain.StoreAgg) *StoreResponse {
 		return nil
 	}
 
+	var distance *float64
+	if store.Distance != nil {
+		d := math.Round(*store.Distance)
+		distance = &d
+	}
+
 	return &StoreResponse{
-		ID:          store.ID,
-		Name:        store.Name,
-		Description: store.Description,
-		CityID:      store.CityID,
-		Address:     store.Address,
-		CardImg:     store.CardImg,
-		Rating:      store.Rating,
-		TagsID:      store.TagsID,
-		OpenAt:      store.OpenAt,
-		ClosedAt:    store.ClosedAt,
+		ID:              store.ID,
+		Name:            store.Name,
+		Description:     store.Description,
+		CityID:          store.CityID,
+		Address:         store.Address,
+		CardImg:         store.CardImg,
+		Rating:          store.Rating,
+		TagsID:          store.TagsID,
+		CategoriesID:    store.CategoriesID,
+		OpenAt:          store.OpenAt,
+		ClosedAt:        store.ClosedAt,
+		Lat:             store.Lat,
+		Lon:             store.Lon,
+		DeliveryRadiusM: store.DeliveryRadius,
+		DeliveryArea:    store.DeliveryArea,
+		DistanceM:       distance,
 	}
 }
 
@@ -117,3 +164,32 @@ func ToTagResponses(tags []*domain.StoreTag) []*TagResponse {
 	}
 	return responses
 }
+
+func FromStoreRequest(req *StoreRequest) *domain.StoreInput {
+	// null в delivery_area — то же, что ее отсутствие
+	area := []byte(req.DeliveryArea)
+	if string(area) == "null" {
+		area = nil
+	}
+	var location *domain.StoreLocation
+	if req.Lat != nil || req.Lon != nil || req.DeliveryRadiusM != nil || area != nil {
+		location = &domain.StoreLocation{
+			Lat:            req.Lat,
+			Lon:            req.Lon,
+			DeliveryRadius: req.DeliveryRadiusM,
+			DeliveryArea:   area,
+		}
+	}
+
+	return &domain.StoreInput{
+		Name:        req.Name,
+		Description: req.Description,
+		CityID:      req.CityID,
+		Address:     req.Address,
+		OpenAt:      req.OpenAt,
+		ClosedAt:    req.ClosedAt,
+		TagIDs:      req.TagsID,
+		CategoryIDs: req.CategoriesID,
+		Location:    location,
+	}
+}
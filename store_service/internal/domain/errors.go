@@ -14,4 +14,29 @@ var (
 	ErrCartEmpty        = errors.New("карточка пустая")
 	ErrCartItemNotFound = errors.New("товар в корзине не найден")
 	ErrInvalidQuantity  = errors.New("неверное количество товара")
+
+	// ошибки проверки магазина повторяют ограничения таблицы store
+	ErrStoreName        = errors.New("название магазина должно быть от 1 до 50 символов")
+	ErrStoreDescription = errors.New("описание магазина должно быть от 30 до 2000 символов")
+	ErrStoreAddress     = errors.New("адрес магазина должен быть от 1 до 200 символов")
+	ErrStoreHours       = errors.New("время работы должно быть в формате ЧЧ:ММ")
+	ErrStoreImage       = errors.New("изображение должно быть в формате png, jpg, jpeg, webp или gif")
+	ErrStoreImageSize   = errors.New("изображение должно быть не больше 5 МБ")
+	ErrCityNotFound     = errors.New("город не найден")
+	ErrTagNotFound      = errors.New("тег не найден")
+	ErrCategoryNotFound = errors.New("категория не найдена")
+	ErrStoreHasOrders   = errors.New("у магазина есть заказы, его нельзя удалить")
+	ErrStoreLocation    = errors.New("нужны обе координаты: широта от -90 до 90 и долгота от -180 до 180")
+	ErrDeliveryZone     = errors.New("зона доставки — радиус от 1 до 100000 м или многоугольник GeoJSON, не оба сразу")
+	ErrZoneAntimeridian = errors.New("зона доставки не может пересекать меридиан 180°")
+
+	// ошибки меню повторяют ограничения таблиц item и store_item
+	ErrItemName        = errors.New("название товара должно быть от 1 до 50 символов")
+	ErrItemDescription = errors.New("описание товара должно быть не длиннее 200 символов")
+	ErrItemPrice       = errors.New("цена должна быть больше 0 и меньше 1000000")
+	ErrTypeNotFound    = errors.New("тип товара не найден")
+	ErrMenuItemExist   = errors.New("товар уже есть в меню магазина")
+	ErrMenuConflict    = errors.New("позиция меню изменена другим запросом, обновите данные")
+
+	ErrSearchQuery = errors.New("поисковый запрос должен быть от 2 до 100 символов")
 )
@@ -1,5 +1,7 @@
 package domain
 
+import "time"
+
 type Item struct {
 	//Это ID из таблицы store_item
 	ID          string
@@ -24,3 +26,32 @@ type ItemType struct {
 	ID   string
 	Name string
 }
+
+// MenuItem — позиция меню магазина в том виде, в каком ее видит владелец,
+// вместе с архивными
+type MenuItem struct {
+	// ID из таблицы store_item
+	ID          string
+	ItemID      string
+	Name        string
+	Description string
+	CardImg     string
+	Price       float64
+	TypesID     []string
+	Archived    bool
+	// UpdatedAt — версия позиции: наибольшее из updated_at товара и
+	// store_item. Изменение принимается, только если клиент прислал
+	// текущую версию.
+	UpdatedAt time.Time
+}
+
+// MenuItemInput — изменение позиции меню; nil-поля не меняются
+type MenuItemInput struct {
+	// ItemID привязывает к магазину существующий товар вместо создания нового
+	ItemID      *string
+	Name        *string
+	Description *string
+	Price       *float64
+	TypeIDs     []string
+	UpdatedAt   time.Time
+}
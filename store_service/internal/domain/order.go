@@ -28,8 +28,10 @@ type Order struct {
 
 type OrderFilter struct {
 	UserID string
-	Limit  int
-	LastID string
-	Status string
-	Desc   bool // сортировка по убыванию (новые сначала)
+	// StoreID — заказы с позициями магазина, для его владельца
+	StoreID string
+	Limit   int
+	LastID  string
+	Status  string
+	Desc    bool // сортировка по убыванию (новые сначала)
 }
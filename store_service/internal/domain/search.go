@@ -0,0 +1,39 @@
+package domain
+
+// SearchFilter — параметры поиска по магазинам и товарам
+type SearchFilter struct {
+	Query  string
+	CityID string
+	Limit  int
+}
+
+// SearchItem — найденная позиция меню
+type SearchItem struct {
+	// ID из таблицы store_item
+	ID      string
+	Name    string
+	CardImg string
+	Price   float64
+	Rank    float64
+}
+
+// SearchStore — магазин в выдаче поиска вместе с найденными в нем
+// позициями. Rank — совпадение с самим магазином, без учета позиций.
+type SearchStore struct {
+	ID          string
+	Name        string
+	Description string
+	CityID      string
+	Address     string
+	CardImg     string
+	Rating      float64
+	Rank        float64
+	Items       []*SearchItem
+}
+
+// SearchHit — строка выдачи поиска до группировки по магазинам: магазин и
+// одна найденная позиция, если совпали позиции
+type SearchHit struct {
+	Store *SearchStore
+	Item  *SearchItem
+}
@@ -1,5 +1,7 @@
 package domain
 
+import "apple_backend/pkg/geo"
+
 type Store struct {
 	ID          string
 	Name        string
@@ -14,16 +16,77 @@ type Store struct {
 }
 
 type StoreAgg struct {
-	ID          string
-	Name        string
-	Description string
-	CityID      string
-	Address     string
-	CardImg     string
-	Rating      float64
-	TagsID      []string
-	OpenAt      string
-	ClosedAt    string
+	ID           string
+	Name         string
+	Description  string
+	CityID       string
+	Address      string
+	CardImg      string
+	Rating       float64
+	TagsID       []string
+	CategoriesID []string
+	OpenAt       string
+	ClosedAt     string
+
+	Lat            *float64
+	Lon            *float64
+	DeliveryRadius *int
+	// DeliveryArea — зона доставки в GeoJSON
+	DeliveryArea []byte
+	// Distance — расстояние в метрах до точки из фильтра
+	Distance *float64
+}
+
+// Zone возвращает зону доставки магазина; false, если зона не задана
+func (s *StoreAgg) Zone() (geo.Zone, bool, error) {
+	if s.DeliveryArea != nil {
+		area, err := geo.ParseArea(s.DeliveryArea)
+		if err != nil {
+			return geo.Zone{}, false, err
+		}
+		return geo.Zone{Area: area}, true, nil
+	}
+	if s.DeliveryRadius != nil && s.Lat != nil && s.Lon != nil {
+		return geo.Zone{
+			Center: geo.Point{Lat: *s.Lat, Lon: *s.Lon},
+			Radius: float64(*s.DeliveryRadius),
+		}, true, nil
+	}
+	return geo.Zone{}, false, nil
+}
+
+// Actor — пользователь, который меняет каталог. Admin может менять любой
+// магазин, остальные — только свои
+type Actor struct {
+	UserID string
+	Admin  bool
+}
+
+// StoreInput — поля магазина при создании и изменении. nil — поле не
+// меняется; TagIDs и CategoryIDs, если не nil, заменяют список целиком
+type StoreInput struct {
+	Name        *string
+	Description *string
+	CityID      *string
+	Address     *string
+	OpenAt      *string
+	ClosedAt    *string
+	TagIDs      []string
+	CategoryIDs []string
+	Location    *StoreLocation
+}
+
+// StoreLocation — координаты и зона доставки магазина, меняются только
+// вместе. Зона — радиус в метрах вокруг магазина или многоугольник GeoJSON;
+// без зоны магазин не находится при поиске по точке.
+type StoreLocation struct {
+	Lat            *float64
+	Lon            *float64
+	DeliveryRadius *int
+	DeliveryArea   []byte
+
+	// Bounds заполняет usecase после проверки зоны, nil — зоны нет
+	Bounds *geo.Box
 }
 
 type StoreTag struct {
@@ -43,6 +106,9 @@ type StoreFilter struct {
 	CityID string
 	Sorted string
 	Desc   bool
+	// Point — точка доставки: остаются магазины, в зону которых она
+	// попадает, ближние первыми
+	Point *geo.Point
 }
 
 type StoreReview struct {
@@ -0,0 +1,56 @@
+package domain
+
+// виды подсказок
+const (
+	SuggestStore = "store"
+	SuggestItem  = "item"
+	SuggestTag   = "tag"
+	SuggestType  = "type"
+)
+
+type Suggestion struct {
+	ID   string
+	Text string
+}
+
+// Suggestions — подсказки по префиксу, лучшие первыми в каждом виде
+type Suggestions struct {
+	Stores []*Suggestion
+	Items  []*Suggestion
+	Tags   []*Suggestion
+	Types  []*Suggestion
+}
+
+// Catalog — снимок каталога для индекса подсказок
+type Catalog struct {
+	Version int64
+	Stores  []*CatalogStore
+	Items   []*CatalogItem
+	Tags    []*CatalogLink
+	Types   []*CatalogLink
+}
+
+type CatalogStore struct {
+	ID     string
+	Name   string
+	CityID string
+	Rating float64
+	// Orders — число заказов с позициями магазина
+	Orders int
+}
+
+// CatalogItem — товар в одном магазине
+type CatalogItem struct {
+	ItemID  string
+	Name    string
+	StoreID string
+	// Ordered — сколько штук заказано в этом магазине
+	Ordered int
+}
+
+// CatalogLink — тег или тип товара, который есть у магазина
+type CatalogLink struct {
+	ID      string
+	Name    string
+	StoreID string
+}
@@ -5,10 +5,13 @@ import (
 	"apple_backend/store_service/internal/domain"
 	"context"
 	_ "embed"
+	"errors"
 	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 )
 
 //go:embed sql/cart/get_items.sql
@@ -20,6 +23,15 @@ var deleteCartItems string
 //go:embed sql/cart/insert_item.sql
 var insertCartItems string
 
+//go:embed sql/cart/get_guest_items.sql
+var getGuestCartItems string
+
+//go:embed sql/cart/touch_guest_cart.sql
+var touchGuestCart string
+
+//go:embed sql/cart/delete_stale_guest_carts.sql
+var deleteStaleGuestCarts string
+
 type CartRepoPostgres struct {
 	db PgxIface
 }
@@ -34,42 +46,66 @@ func (r *CartRepoPostgres) GetCartItems(ctx context.Context, userID string) ([]*
 	log := logger.FromContext(ctx)
 	log.DebugContext(ctx, "GetCartItems начало обработки", slog.String("user_id", userID))
 
-	rows, err := r.db.Query(ctx, getCartItems, userID)
+	items, err := r.queryCartItems(ctx, "GetCartItems", getCartItems, userID)
 	if err != nil {
-		log.ErrorContext(ctx, "GetCartItems query failed",
+		return nil, err
+	}
+
+	log.DebugContext(ctx, "GetCartItems завершено успешно",
+		slog.String("user_id", userID),
+		slog.Int("items_count", len(items)))
+	return items, nil
+}
+
+// GetGuestCartItems возвращает позиции гостевой корзины; корзина
+// пользователя по этому id не отдается
+func (r *CartRepoPostgres) GetGuestCartItems(ctx context.Context, cartID string) ([]*domain.CartItem, error) {
+	log := logger.FromContext(ctx)
+	log.DebugContext(ctx, "GetGuestCartItems начало обработки", slog.String("cart_id", cartID))
+
+	items, err := r.queryCartItems(ctx, "GetGuestCartItems", getGuestCartItems, cartID)
+	if err != nil {
+		return nil, err
+	}
+
+	log.DebugContext(ctx, "GetGuestCartItems завершено успешно",
+		slog.String("cart_id", cartID),
+		slog.Int("items_count", len(items)))
+	return items, nil
+}
+
+// queryCartItems выполняет запрос позиций корзины по ключу key (user_id или
+// id гостевой корзины)
+func (r *CartRepoPostgres) queryCartItems(ctx context.Context, op, query, key string) ([]*domain.CartItem, error) {
+	log := logger.FromContext(ctx)
+
+	rows, err := r.db.Query(ctx, query, key)
+	if err != nil {
+		log.ErrorContext(ctx, op+" query failed",
 			slog.Any("err", err),
-			slog.String("user_id", userID))
+			slog.String("key", key))
 		return nil, domain.ErrInternalServer
 	}
 	defer rows.Close()
 
-	var items []*domain.CartItem
+	items := []*domain.CartItem{}
 	for rows.Next() {
 		var item domain.CartItem
 		if err := rows.Scan(&item.ID, &item.Name, &item.CardImg, &item.Price, &item.Quantity); err != nil {
-			log.ErrorContext(ctx, "GetCartItems scan failed",
+			log.ErrorContext(ctx, op+" scan failed",
 				slog.Any("err", err),
-				slog.String("user_id", userID))
+				slog.String("key", key))
 			return nil, domain.ErrInternalServer
 		}
 		items = append(items, &item)
 	}
 
 	if err = rows.Err(); err != nil {
-		log.ErrorContext(ctx, "GetCartItems rows iteration error",
+		log.ErrorContext(ctx, op+" rows iteration error",
 			slog.Any("err", err),
-			slog.String("user_id", userID))
+			slog.String("key", key))
 		return nil, domain.ErrInternalServer
 	}
-
-	if len(items) == 0 {
-		log.DebugContext(ctx, "GetCartItems cart is empty", slog.String("user_id", userID))
-		return []*domain.CartItem{}, nil
-	}
-
-	log.DebugContext(ctx, "GetCartItems завершено успешно",
-		slog.String("user_id", userID),
-		slog.Int("items_count", len(items)))
 	return items, nil
 }
 
@@ -101,24 +137,8 @@ func (r *CartRepoPostgres) UpdateCartItems(ctx context.Context, userID string, n
 		slog.String("user_id", userID),
 		slog.Int("items_count", itemsCount))
 
-	if newItems != nil && len(newItems.Items) > 0 {
-		for i, item := range newItems.Items {
-			var exists bool
-			checkQuery := `SELECT EXISTS(SELECT 1 FROM store_item WHERE id = $1)`
-			err := r.db.QueryRow(ctx, checkQuery, item.ID).Scan(&exists)
-			if err != nil {
-				log.ErrorContext(ctx, "UpdateCartItems ошибка проверки store_item",
-					slog.Any("err", err),
-					slog.String("item_id", item.ID))
-				return err
-			}
-			if !exists {
-				log.WarnContext(ctx, "UpdateCartItems store_item не найден",
-					slog.String("item_id", item.ID),
-					slog.Int("item_index", i))
-				return domain.ErrRowsNotFound
-			}
-		}
+	if err := r.checkStoreItems(ctx, "UpdateCartItems", newItems); err != nil {
+		return err
 	}
 
 	tx, err := r.db.Begin(ctx)
@@ -156,55 +176,162 @@ func (r *CartRepoPostgres) UpdateCartItems(ctx context.Context, userID string, n
 			slog.String("user_id", userID))
 	}
 
-	_, err = tx.Exec(ctx, "DELETE FROM cart_item WHERE cart_id = $1", cartID)
-	if err != nil {
-		log.ErrorContext(ctx, "UpdateCartItems delete old items failed",
+	if err := replaceCartItems(ctx, tx, "UpdateCartItems", cartID, newItems); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		log.ErrorContext(ctx, "UpdateCartItems transaction commit failed",
 			slog.Any("err", err),
 			slog.String("cart_id", cartID),
 			slog.String("user_id", userID))
 		return err
 	}
 
-	if newItems == nil || len(newItems.Items) == 0 {
-		log.DebugContext(ctx, "UpdateCartItems no items to insert",
-			slog.String("cart_id", cartID),
-			slog.String("user_id", userID))
-	} else {
-		for i, item := range newItems.Items {
-			if item.ID == "" {
-				log.ErrorContext(ctx, "UpdateCartItems empty item ID",
-					slog.Int("item_index", i),
-					slog.String("cart_id", cartID))
-				return fmt.Errorf("item[%d]: ID is empty", i)
-			}
+	log.DebugContext(ctx, "UpdateCartItems завершено успешно",
+		slog.String("cart_id", cartID),
+		slog.String("user_id", userID),
+		slog.Int("items_count", itemsCount))
+	return nil
+}
 
-			_, err := tx.Exec(ctx, insertCartItems, uuid.New().String(), cartID, item.ID, item.Quantity)
-			if err != nil {
-				log.ErrorContext(ctx, "UpdateCartItems insert item failed",
-					slog.Any("err", err),
-					slog.Int("item_index", i),
-					slog.String("item_id", item.ID),
-					slog.String("cart_id", cartID),
-					slog.Int("quantity", item.Quantity))
-				return err
-			}
+// UpdateGuestCartItems заменяет позиции гостевой корзины и возвращает ее id.
+// Если корзины cartID нет (пустой id, истекла или уже слита с корзиной
+// пользователя), создается новая.
+func (r *CartRepoPostgres) UpdateGuestCartItems(ctx context.Context, cartID string, newItems *domain.CartUpdate) (string, error) {
+	log := logger.FromContext(ctx)
+	log.DebugContext(ctx, "UpdateGuestCartItems начало обработки", slog.String("cart_id", cartID))
+
+	if err := r.checkStoreItems(ctx, "UpdateGuestCartItems", newItems); err != nil {
+		return "", err
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		log.ErrorContext(ctx, "UpdateGuestCartItems transaction begin failed",
+			slog.Any("err", err),
+			slog.String("cart_id", cartID))
+		return "", err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if cartID != "" {
+		err = tx.QueryRow(ctx, touchGuestCart, cartID).Scan(&cartID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			cartID = ""
+		} else if err != nil {
+			log.ErrorContext(ctx, "UpdateGuestCartItems touch cart failed",
+				slog.Any("err", err),
+				slog.String("cart_id", cartID))
+			return "", err
 		}
-		log.DebugContext(ctx, "UpdateCartItems items inserted successfully",
-			slog.String("cart_id", cartID),
-			slog.Int("items_count", len(newItems.Items)))
+	}
+	if cartID == "" {
+		cartID = uuid.New().String()
+		if _, err := tx.Exec(ctx, "INSERT INTO cart (id) VALUES ($1)", cartID); err != nil {
+			log.ErrorContext(ctx, "UpdateGuestCartItems create cart failed",
+				slog.Any("err", err),
+				slog.String("cart_id", cartID))
+			return "", err
+		}
+		log.DebugContext(ctx, "UpdateGuestCartItems created new cart", slog.String("cart_id", cartID))
+	}
+
+	if err := replaceCartItems(ctx, tx, "UpdateGuestCartItems", cartID, newItems); err != nil {
+		return "", err
 	}
 
 	if err := tx.Commit(ctx); err != nil {
-		log.ErrorContext(ctx, "UpdateCartItems transaction commit failed",
+		log.ErrorContext(ctx, "UpdateGuestCartItems transaction commit failed",
 			slog.Any("err", err),
-			slog.String("cart_id", cartID),
-			slog.String("user_id", userID))
+			slog.String("cart_id", cartID))
+		return "", err
+	}
+
+	log.DebugContext(ctx, "UpdateGuestCartItems завершено успешно", slog.String("cart_id", cartID))
+	return cartID, nil
+}
+
+// DeleteStaleGuestCarts удаляет гостевые корзины, не менявшиеся с before
+func (r *CartRepoPostgres) DeleteStaleGuestCarts(ctx context.Context, before time.Time) (int64, error) {
+	log := logger.FromContext(ctx)
+
+	tag, err := r.db.Exec(ctx, deleteStaleGuestCarts, before)
+	if err != nil {
+		log.ErrorContext(ctx, "DeleteStaleGuestCarts failed", slog.Any("err", err))
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// checkStoreItems проверяет, что все позиции обновления есть в продаже
+func (r *CartRepoPostgres) checkStoreItems(ctx context.Context, op string, newItems *domain.CartUpdate) error {
+	if newItems == nil {
+		return nil
+	}
+	log := logger.FromContext(ctx)
+
+	for i, item := range newItems.Items {
+		var exists bool
+		// архивные позиции сняты с продажи и в корзину не попадают
+		checkQuery := `SELECT EXISTS(
+			SELECT 1 FROM store_item si JOIN item i ON i.id = si.item_id
+			WHERE si.id = $1 AND si.archived_at IS NULL AND i.archived_at IS NULL)`
+		err := r.db.QueryRow(ctx, checkQuery, item.ID).Scan(&exists)
+		if err != nil {
+			log.ErrorContext(ctx, op+" ошибка проверки store_item",
+				slog.Any("err", err),
+				slog.String("item_id", item.ID))
+			return err
+		}
+		if !exists {
+			log.WarnContext(ctx, op+" store_item не найден",
+				slog.String("item_id", item.ID),
+				slog.Int("item_index", i))
+			return domain.ErrRowsNotFound
+		}
+	}
+	return nil
+}
+
+// replaceCartItems заменяет позиции корзины cartID в транзакции tx
+func replaceCartItems(ctx context.Context, tx pgx.Tx, op, cartID string, newItems *domain.CartUpdate) error {
+	log := logger.FromContext(ctx)
+
+	_, err := tx.Exec(ctx, "DELETE FROM cart_item WHERE cart_id = $1", cartID)
+	if err != nil {
+		log.ErrorContext(ctx, op+" delete old items failed",
+			slog.Any("err", err),
+			slog.String("cart_id", cartID))
 		return err
 	}
 
-	log.DebugContext(ctx, "UpdateCartItems завершено успешно",
+	if newItems == nil || len(newItems.Items) == 0 {
+		log.DebugContext(ctx, op+" no items to insert", slog.String("cart_id", cartID))
+		return nil
+	}
+
+	for i, item := range newItems.Items {
+		if item.ID == "" {
+			log.ErrorContext(ctx, op+" empty item ID",
+				slog.Int("item_index", i),
+				slog.String("cart_id", cartID))
+			return fmt.Errorf("item[%d]: ID is empty", i)
+		}
+
+		_, err := tx.Exec(ctx, insertCartItems, uuid.New().String(), cartID, item.ID, item.Quantity)
+		if err != nil {
+			log.ErrorContext(ctx, op+" insert item failed",
+				slog.Any("err", err),
+				slog.Int("item_index", i),
+				slog.String("item_id", item.ID),
+				slog.String("cart_id", cartID),
+				slog.Int("quantity", item.Quantity))
+			return err
+		}
+	}
+	log.DebugContext(ctx, op+" items inserted successfully",
 		slog.String("cart_id", cartID),
-		slog.String("user_id", userID),
-		slog.Int("items_count", itemsCount))
+		slog.Int("items_count", len(newItems.Items)))
 	return nil
 }
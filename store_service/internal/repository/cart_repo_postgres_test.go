@@ -1,11 +1,11 @@
 package repository
 
 import (
-	"apple_backend/pkg/logger"
 	"apple_backend/store_service/internal/domain"
 	"context"
 	"testing"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/pashagolub/pgxmock/v2"
 	"github.com/stretchr/testify/require"
 )
@@ -19,21 +19,7 @@ func TestCartRepoPostgres_GetCartItems(t *testing.T) {
 		expectedError error
 	}
 
-	query := `
-		select
-		    c.id as cart_id,
-		    si.id as id,
-			it.name as name,
-			it.card_img as card_img,
-			si.price as price,
-			ci.quantity as quantity
-		from cart c
-		join cart_item ci on ci.cart_id = c.id
-		join store_item si on si.id = ci.store_item_id
-		join item it on it.id = si.item_id
-		where c.user_id = \$1
-		order by ci.created_at;
-	`
+	query := `from\s+cart c\s+join cart_item ci on ci.cart_id = c.id\s+join store_item si on si.id = ci.store_item_id\s+join item it on it.id = si.item_id\s+where\s+c.user_id = \$1\s+order by\s+ci.created_at`
 
 	uid1 := "00000000-0000-0000-0000-000000000001"
 	uid2 := "00000000-0000-0000-0000-000000000002"
@@ -66,9 +52,9 @@ func TestCartRepoPostgres_GetCartItems(t *testing.T) {
 			name: "успешный запрос больше 1 элемента",
 			id:   uid1,
 			mockSetup: func(mock pgxmock.PgxPoolIface) {
-				rows := pgxmock.NewRows([]string{"cart_id", "id", "name", "card_img", "price", "quantity"}).
-					AddRow(uid1, uid1, name1, cartImg1, price1, quantity1).
-					AddRow(uid1, uid2, name2, cartImg2, price2, quantity2)
+				rows := pgxmock.NewRows([]string{"id", "name", "card_img", "price", "quantity"}).
+					AddRow(uid1, name1, cartImg1, price1, quantity1).
+					AddRow(uid2, name2, cartImg2, price2, quantity2)
 				mock.ExpectQuery(query).
 					WithArgs(uid1).
 					WillReturnRows(rows)
@@ -80,8 +66,8 @@ func TestCartRepoPostgres_GetCartItems(t *testing.T) {
 			name: "успешный запрос 1 элемент",
 			id:   uid1,
 			mockSetup: func(mock pgxmock.PgxPoolIface) {
-				rows := pgxmock.NewRows([]string{"cart_id", "id", "name", "card_img", "price", "quantity"}).
-					AddRow(uid1, uid1, name1, cartImg1, price1, quantity1)
+				rows := pgxmock.NewRows([]string{"id", "name", "card_img", "price", "quantity"}).
+					AddRow(uid1, name1, cartImg1, price1, quantity1)
 				mock.ExpectQuery(query).
 					WithArgs(uid1).
 					WillReturnRows(rows)
@@ -93,13 +79,13 @@ func TestCartRepoPostgres_GetCartItems(t *testing.T) {
 			name: "пустой ответ",
 			id:   uid1,
 			mockSetup: func(mock pgxmock.PgxPoolIface) {
-				rows := pgxmock.NewRows([]string{"cart_id", "id", "name", "card_img", "price", "quantity"})
+				rows := pgxmock.NewRows([]string{"id", "name", "card_img", "price", "quantity"})
 				mock.ExpectQuery(query).
 					WithArgs(uid1).
 					WillReturnRows(rows)
 			},
-			expectedRes:   nil,
-			expectedError: domain.ErrRowsNotFound,
+			expectedRes:   []*domain.CartItem{},
+			expectedError: nil,
 		},
 		{
 			name: "ошибка запроса",
@@ -116,8 +102,8 @@ func TestCartRepoPostgres_GetCartItems(t *testing.T) {
 			name: "ошибка при чтении строки",
 			id:   uid1,
 			mockSetup: func(mock pgxmock.PgxPoolIface) {
-				rows := pgxmock.NewRows([]string{"cart_id", "id", "name", "card_img", "price", "quantity"}).
-					AddRow(uid1, uid1, name1, cartImg1, price1, quantity1).
+				rows := pgxmock.NewRows([]string{"id", "name", "card_img", "price", "quantity"}).
+					AddRow(uid1, name1, cartImg1, price1, quantity1).
 					RowError(0, domain.ErrInternalServer)
 
 				mock.ExpectQuery(query).
@@ -137,8 +123,7 @@ func TestCartRepoPostgres_GetCartItems(t *testing.T) {
 			require.NoError(t, err)
 			defer mockPool.Close()
 
-			log := logger.NewNilLogger()
-			repo := NewCartRepoPostgres(mockPool, log)
+			repo := NewCartRepoPostgres(mockPool)
 
 			tt.mockSetup(mockPool)
 
@@ -197,8 +182,7 @@ func TestCartRepoPostgres_DeleteCartItems(t *testing.T) {
 			require.NoError(t, err)
 			defer mockPool.Close()
 
-			log := logger.NewNilLogger()
-			repo := NewCartRepoPostgres(mockPool, log)
+			repo := NewCartRepoPostgres(mockPool)
 
 			tt.mockSetup(mockPool)
 
@@ -218,15 +202,11 @@ func TestCartRepoPostgres_UpdateCartItems(t *testing.T) {
 		expectedError error
 	}
 
-	deleteQuery := `
-		delete from cart_item
-		where cart_id = \(select id from cart where user_id = \$1\)
-		returning cart_id
-	`
-	insertQuery := `
-		insert into cart_item \(id, cart_id, store_item_id, quantity\)
-		values \(\$1, \$2, \$3, \$4\);
-	`
+	checkQuery := `SELECT EXISTS\( SELECT 1 FROM store_item si JOIN item i ON i.id = si.item_id WHERE si.id = \$1 AND si.archived_at IS NULL AND i.archived_at IS NULL\)`
+	cartQuery := `SELECT id FROM cart WHERE user_id = \$1`
+	createCartQuery := `INSERT INTO cart \(id, user_id\) VALUES \(\$1, \$2\)`
+	deleteQuery := `DELETE FROM cart_item WHERE cart_id = \$1`
+	insertQuery := `insert into cart_item \(id, cart_id, store_item_id, quantity\) values \(\$1, \$2, \$3, \$4\);`
 
 	userID := "00000000-0000-0000-0000-000000000111"
 	cartID := "00000000-0000-0000-0000-000000000222"
@@ -244,17 +224,29 @@ func TestCartRepoPostgres_UpdateCartItems(t *testing.T) {
 		Items: []*domain.ItemUpdate{item1, item2},
 	}
 
+	// обе позиции в продаже
+	expectChecks := func(mock pgxmock.PgxPoolIface) {
+		for _, item := range update.Items {
+			mock.ExpectQuery(checkQuery).
+				WithArgs(item.ID).
+				WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(true))
+		}
+	}
+
 	tests := []testCase{
 		{
 			name:     "успешное обновление корзины",
 			id:       userID,
 			newItems: update,
 			mockSetup: func(mock pgxmock.PgxPoolIface) {
+				expectChecks(mock)
 				mock.ExpectBegin()
-
-				mock.ExpectQuery(deleteQuery).
+				mock.ExpectQuery(cartQuery).
 					WithArgs(userID).
-					WillReturnRows(pgxmock.NewRows([]string{"cart_id"}).AddRow(cartID))
+					WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(cartID))
+				mock.ExpectExec(deleteQuery).
+					WithArgs(cartID).
+					WillReturnResult(pgxmock.NewResult("DELETE", 2))
 
 				mock.ExpectExec(insertQuery).
 					WithArgs(pgxmock.AnyArg(), cartID, item1.ID, item1.Quantity).
@@ -267,14 +259,54 @@ func TestCartRepoPostgres_UpdateCartItems(t *testing.T) {
 			},
 			expectedError: nil,
 		},
+		{
+			name:     "создание новой корзины",
+			id:       userID,
+			newItems: &domain.CartUpdate{Items: []*domain.ItemUpdate{item1}},
+			mockSetup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery(checkQuery).
+					WithArgs(item1.ID).
+					WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(true))
+				mock.ExpectBegin()
+				mock.ExpectQuery(cartQuery).
+					WithArgs(userID).
+					WillReturnError(pgx.ErrNoRows)
+				mock.ExpectExec(createCartQuery).
+					WithArgs(pgxmock.AnyArg(), userID).
+					WillReturnResult(pgxmock.NewResult("INSERT", 1))
+				mock.ExpectExec(deleteQuery).
+					WithArgs(pgxmock.AnyArg()).
+					WillReturnResult(pgxmock.NewResult("DELETE", 0))
+				mock.ExpectExec(insertQuery).
+					WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), item1.ID, item1.Quantity).
+					WillReturnResult(pgxmock.NewResult("INSERT", 1))
+				mock.ExpectCommit()
+			},
+			expectedError: nil,
+		},
+		{
+			name:     "позиция снята с продажи",
+			id:       userID,
+			newItems: update,
+			mockSetup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery(checkQuery).
+					WithArgs(item1.ID).
+					WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(false))
+			},
+			expectedError: domain.ErrRowsNotFound,
+		},
 		{
 			name:     "ошибка при удалении",
 			id:       userID,
 			newItems: update,
 			mockSetup: func(mock pgxmock.PgxPoolIface) {
+				expectChecks(mock)
 				mock.ExpectBegin()
-				mock.ExpectQuery(deleteQuery).
+				mock.ExpectQuery(cartQuery).
 					WithArgs(userID).
+					WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(cartID))
+				mock.ExpectExec(deleteQuery).
+					WithArgs(cartID).
 					WillReturnError(domain.ErrInternalServer)
 				mock.ExpectRollback()
 			},
@@ -285,10 +317,14 @@ func TestCartRepoPostgres_UpdateCartItems(t *testing.T) {
 			id:       userID,
 			newItems: update,
 			mockSetup: func(mock pgxmock.PgxPoolIface) {
+				expectChecks(mock)
 				mock.ExpectBegin()
-				mock.ExpectQuery(deleteQuery).
+				mock.ExpectQuery(cartQuery).
 					WithArgs(userID).
-					WillReturnRows(pgxmock.NewRows([]string{"cart_id"}).AddRow(cartID))
+					WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(cartID))
+				mock.ExpectExec(deleteQuery).
+					WithArgs(cartID).
+					WillReturnResult(pgxmock.NewResult("DELETE", 2))
 
 				mock.ExpectExec(insertQuery).
 					WithArgs(pgxmock.AnyArg(), cartID, item1.ID, item1.Quantity).
@@ -303,9 +339,10 @@ func TestCartRepoPostgres_UpdateCartItems(t *testing.T) {
 		},
 		{
 			name:     "ошибка при начале транзакции",
-			id:       cartID,
+			id:       userID,
 			newItems: update,
 			mockSetup: func(mock pgxmock.PgxPoolIface) {
+				expectChecks(mock)
 				mock.ExpectBegin().WillReturnError(domain.ErrInternalServer)
 			},
 			expectedError: domain.ErrInternalServer,
@@ -320,14 +357,14 @@ func TestCartRepoPostgres_UpdateCartItems(t *testing.T) {
 			require.NoError(t, err)
 			defer mockPool.Close()
 
-			log := logger.NewNilLogger()
-			repo := NewCartRepoPostgres(mockPool, log)
+			repo := NewCartRepoPostgres(mockPool)
 
 			tt.mockSetup(mockPool)
 
 			err = repo.UpdateCartItems(context.Background(), tt.id, tt.newItems)
 
 			require.Equal(t, tt.expectedError, err)
+			require.NoError(t, mockPool.ExpectationsWereMet())
 		})
 	}
 }
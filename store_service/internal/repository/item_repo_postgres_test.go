@@ -1,7 +1,6 @@
 package repository
 
 import (
-	"apple_backend/pkg/logger"
 	"apple_backend/store_service/internal/domain"
 
 	"context"
@@ -33,10 +32,15 @@ func TestItemRepoPostgres_GetItemTypes(t *testing.T) {
 					AddRow(uid1, name1).
 					AddRow(uid2, name2)
 
-				mock.ExpectQuery(`select type.id, type.name
-		from store_item join item_type on store_item.item_id = item_type.item_id
-		join type on store_item.type_id = type.id
-		where store_item.store_id = \$1`).
+				mock.ExpectQuery(`SELECT DISTINCT type.id, type.name
+		FROM store_item
+		JOIN item ON store_item.item_id = item.id
+		JOIN item_type ON store_item.item_id = item_type.item_id
+		JOIN type ON item_type.type_id = type.id
+		WHERE store_item.store_id = \$1
+		AND store_item.archived_at IS NULL
+		AND item.archived_at IS NULL
+		ORDER BY type.name`).
 					WithArgs(uid1).
 					WillReturnRows(rows)
 			},
@@ -49,10 +53,15 @@ func TestItemRepoPostgres_GetItemTypes(t *testing.T) {
 		{
 			name: "ошибка при запросе",
 			mockSetup: func(mock pgxmock.PgxPoolIface) {
-				mock.ExpectQuery(`select type.id, type.name
-		from store_item join item_type on store_item.item_id = item_type.item_id
-		join type on store_item.type_id = type.id
-		where store_item.store_id = \$1`).
+				mock.ExpectQuery(`SELECT DISTINCT type.id, type.name
+		FROM store_item
+		JOIN item ON store_item.item_id = item.id
+		JOIN item_type ON store_item.item_id = item_type.item_id
+		JOIN type ON item_type.type_id = type.id
+		WHERE store_item.store_id = \$1
+		AND store_item.archived_at IS NULL
+		AND item.archived_at IS NULL
+		ORDER BY type.name`).
 					WithArgs(uid1).
 					WillReturnError(domain.ErrInternalServer)
 			},
@@ -63,15 +72,20 @@ func TestItemRepoPostgres_GetItemTypes(t *testing.T) {
 			name: "пустой результат",
 			mockSetup: func(mock pgxmock.PgxPoolIface) {
 				rows := pgxmock.NewRows([]string{"id", "name"})
-				mock.ExpectQuery(`select type.id, type.name
-		from store_item join item_type on store_item.item_id = item_type.item_id
-		join type on store_item.type_id = type.id
-		where store_item.store_id = \$1`).
+				mock.ExpectQuery(`SELECT DISTINCT type.id, type.name
+		FROM store_item
+		JOIN item ON store_item.item_id = item.id
+		JOIN item_type ON store_item.item_id = item_type.item_id
+		JOIN type ON item_type.type_id = type.id
+		WHERE store_item.store_id = \$1
+		AND store_item.archived_at IS NULL
+		AND item.archived_at IS NULL
+		ORDER BY type.name`).
 					WithArgs(uid1).
 					WillReturnRows(rows)
 			},
-			expectedRes:   nil,
-			expectedError: domain.ErrRowsNotFound,
+			expectedRes:   []*domain.ItemType{},
+			expectedError: nil,
 		},
 		{
 			name: "ошибка при чтении",
@@ -80,10 +94,15 @@ func TestItemRepoPostgres_GetItemTypes(t *testing.T) {
 					AddRow(uid1, name1).
 					RowError(0, domain.ErrInternalServer)
 
-				mock.ExpectQuery(`select type.id, type.name
-		from store_item join item_type on store_item.item_id = item_type.item_id
-		join type on store_item.type_id = type.id
-		where store_item.store_id = \$1`).
+				mock.ExpectQuery(`SELECT DISTINCT type.id, type.name
+		FROM store_item
+		JOIN item ON store_item.item_id = item.id
+		JOIN item_type ON store_item.item_id = item_type.item_id
+		JOIN type ON item_type.type_id = type.id
+		WHERE store_item.store_id = \$1
+		AND store_item.archived_at IS NULL
+		AND item.archived_at IS NULL
+		ORDER BY type.name`).
 					WithArgs(uid1).
 					WillReturnRows(rows)
 			},
@@ -100,8 +119,7 @@ func TestItemRepoPostgres_GetItemTypes(t *testing.T) {
 			}
 			defer mockPool.Close()
 
-			log := logger.NewNilLogger()
-			repo := NewItemRepoPostgres(mockPool, log)
+			repo := NewItemRepoPostgres(mockPool)
 
 			tt.mockSetup(mockPool)
 
@@ -142,10 +160,12 @@ func TestItemRepoPostgres_GetItems(t *testing.T) {
 					AddRow(uid1, name1, price1, description1, cardImg1, uid1).
 					AddRow(uid2, name2, price2, description2, cardImg2, uid2)
 
-				mock.ExpectQuery(`select store_item.id, item.name, store_item.price, item.description, item.card_img, item_type.type_id
+				mock.ExpectQuery(`select store_item.id, item.name, store_item.price, item.description, coalesce\(item.card_img, ''\), item_type.type_id
 		from store_item join item on store_item.item_id = item.id
 		join item_type on item.id = item_type.item_id
-		where store_item.store_id = \$1`).
+		where store_item.store_id = \$1
+		and store_item.archived_at is null
+		and item.archived_at is null`).
 					WithArgs(uid1).
 					WillReturnRows(rows)
 			},
@@ -178,10 +198,12 @@ func TestItemRepoPostgres_GetItems(t *testing.T) {
 					AddRow(uid2, name2, price2, description2, cardImg2, uid1).
 					AddRow(uid2, name2, price2, description2, cardImg2, uid2)
 
-				mock.ExpectQuery(`select store_item.id, item.name, store_item.price, item.description, item.card_img, item_type.type_id
+				mock.ExpectQuery(`select store_item.id, item.name, store_item.price, item.description, coalesce\(item.card_img, ''\), item_type.type_id
 		from store_item join item on store_item.item_id = item.id
 		join item_type on item.id = item_type.item_id
-		where store_item.store_id = \$1`).
+		where store_item.store_id = \$1
+		and store_item.archived_at is null
+		and item.archived_at is null`).
 					WithArgs(uid1).
 					WillReturnRows(rows)
 			},
@@ -224,10 +246,12 @@ func TestItemRepoPostgres_GetItems(t *testing.T) {
 		{
 			name: "ошибка при запросе",
 			mockSetup: func(mock pgxmock.PgxPoolIface) {
-				mock.ExpectQuery(`select store_item.id, item.name, store_item.price, item.description, item.card_img, item_type.type_id
+				mock.ExpectQuery(`select store_item.id, item.name, store_item.price, item.description, coalesce\(item.card_img, ''\), item_type.type_id
 		from store_item join item on store_item.item_id = item.id
 		join item_type on item.id = item_type.item_id
-		where store_item.store_id = \$1`).
+		where store_item.store_id = \$1
+		and store_item.archived_at is null
+		and item.archived_at is null`).
 					WithArgs(uid1).
 					WillReturnError(domain.ErrInternalServer)
 			},
@@ -238,10 +262,12 @@ func TestItemRepoPostgres_GetItems(t *testing.T) {
 			name: "пустой результат",
 			mockSetup: func(mock pgxmock.PgxPoolIface) {
 				rows := pgxmock.NewRows([]string{"id", "name", "price", "description", "card_img", "type_id"})
-				mock.ExpectQuery(`select store_item.id, item.name, store_item.price, item.description, item.card_img, item_type.type_id
+				mock.ExpectQuery(`select store_item.id, item.name, store_item.price, item.description, coalesce\(item.card_img, ''\), item_type.type_id
 		from store_item join item on store_item.item_id = item.id
 		join item_type on item.id = item_type.item_id
-		where store_item.store_id = \$1`).
+		where store_item.store_id = \$1
+		and store_item.archived_at is null
+		and item.archived_at is null`).
 					WithArgs(uid1).
 					WillReturnRows(rows)
 			},
@@ -255,10 +281,12 @@ func TestItemRepoPostgres_GetItems(t *testing.T) {
 					AddRow(uid1, name1, price1, description1, cardImg1, uid1).
 					RowError(0, domain.ErrInternalServer)
 
-				mock.ExpectQuery(`select store_item.id, item.name, store_item.price, item.description, item.card_img, item_type.type_id
+				mock.ExpectQuery(`select store_item.id, item.name, store_item.price, item.description, coalesce\(item.card_img, ''\), item_type.type_id
 		from store_item join item on store_item.item_id = item.id
 		join item_type on item.id = item_type.item_id
-		where store_item.store_id = \$1`).
+		where store_item.store_id = \$1
+		and store_item.archived_at is null
+		and item.archived_at is null`).
 					WithArgs(uid1).
 					WillReturnRows(rows)
 			},
@@ -275,8 +303,7 @@ func TestItemRepoPostgres_GetItems(t *testing.T) {
 			}
 			defer mockPool.Close()
 
-			log := logger.NewNilLogger()
-			repo := NewItemRepoPostgres(mockPool, log)
+			repo := NewItemRepoPostgres(mockPool)
 
 			tt.mockSetup(mockPool)
 
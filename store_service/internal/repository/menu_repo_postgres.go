@@ -0,0 +1,335 @@
+package repository
+
+import (
+	"apple_backend/pkg/logger"
+	"apple_backend/store_service/internal/domain"
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+//go:embed sql/menu/get_menu.sql
+var getMenu string
+
+//go:embed sql/menu/get_menu_item.sql
+var getMenuItem string
+
+//go:embed sql/menu/lock_menu_item.sql
+var lockMenuItem string
+
+//go:embed sql/menu/item_in_foreign_stores.sql
+var itemInForeignStores string
+
+//go:embed sql/menu/insert_item.sql
+var insertMenuItem string
+
+//go:embed sql/menu/update_item.sql
+var updateMenuItem string
+
+//go:embed sql/menu/unarchive_item.sql
+var unarchiveItem string
+
+//go:embed sql/menu/attach_item.sql
+var attachItem string
+
+//go:embed sql/menu/update_price.sql
+var updateMenuPrice string
+
+//go:embed sql/menu/delete_item_types.sql
+var deleteItemTypes string
+
+//go:embed sql/menu/insert_item_types.sql
+var insertItemTypes string
+
+//go:embed sql/menu/archive_menu_item.sql
+var archiveMenuItem string
+
+//go:embed sql/menu/archive_orphan_item.sql
+var archiveOrphanItem string
+
+//go:embed sql/menu/delete_cart_items.sql
+var deleteMenuCartItems string
+
+// menuError переводит нарушения ограничений таблиц меню в ошибки домена
+func menuError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+	switch pgErr.Code {
+	case "23503":
+		switch pgErr.ConstraintName {
+		case "item_type_type_id_fkey":
+			return domain.ErrTypeNotFound
+		case "store_item_item_id_fkey":
+			return domain.ErrRowsNotFound
+		}
+	case "23514":
+		// usecase проверяет те же ограничения, сюда попадают только расхождения
+		return fmt.Errorf("%w: %s", domain.ErrRequestParams, pgErr.ConstraintName)
+	}
+	return err
+}
+
+// MenuRepoPostgres — изменение меню магазинов
+type MenuRepoPostgres struct {
+	db PgxIface
+}
+
+func NewMenuRepoPostgres(db PgxIface) *MenuRepoPostgres {
+	return &MenuRepoPostgres{
+		db: db,
+	}
+}
+
+func scanMenuItem(row pgx.Row) (*domain.MenuItem, error) {
+	item := &domain.MenuItem{}
+	err := row.Scan(
+		&item.ID,
+		&item.ItemID,
+		&item.Name,
+		&item.Description,
+		&item.CardImg,
+		&item.Price,
+		&item.TypesID,
+		&item.Archived,
+		&item.UpdatedAt,
+	)
+	return item, err
+}
+
+// GetMenu возвращает все позиции магазина, включая архивные
+func (r *MenuRepoPostgres) GetMenu(ctx context.Context, storeID string) ([]*domain.MenuItem, error) {
+	log := logger.FromContext(ctx)
+	log.DebugContext(ctx, "GetMenu начало обработки", slog.String("store_id", storeID))
+
+	rows, err := r.db.Query(ctx, getMenu, storeID)
+	if err != nil {
+		log.ErrorContext(ctx, "GetMenu ошибка бд", slog.Any("err", err), slog.String("store_id", storeID))
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []*domain.MenuItem{}
+	for rows.Next() {
+		item, err := scanMenuItem(rows)
+		if err != nil {
+			log.ErrorContext(ctx, "GetMenu ошибка при декодировании данных", slog.Any("err", err))
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if err = rows.Err(); err != nil {
+		log.ErrorContext(ctx, "GetMenu ошибка после чтения строк", slog.Any("err", err), slog.String("store_id", storeID))
+		return nil, err
+	}
+
+	log.DebugContext(ctx, "GetMenu завершено успешно",
+		slog.String("store_id", storeID),
+		slog.Int("items_count", len(items)))
+	return items, nil
+}
+
+func (r *MenuRepoPostgres) GetMenuItem(ctx context.Context, storeID, id string) (*domain.MenuItem, error) {
+	log := logger.FromContext(ctx)
+
+	item, err := scanMenuItem(r.db.QueryRow(ctx, getMenuItem, storeID, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrRowsNotFound
+	}
+	if err != nil {
+		log.ErrorContext(ctx, "GetMenuItem ошибка бд", slog.Any("err", err), slog.String("store_item_id", id))
+		return nil, err
+	}
+	return item, nil
+}
+
+// ItemInForeignStores сообщает, продается ли товар в магазинах, которыми
+// userID не владеет
+func (r *MenuRepoPostgres) ItemInForeignStores(ctx context.Context, itemID, userID string) (bool, error) {
+	var foreign bool
+	if err := r.db.QueryRow(ctx, itemInForeignStores, itemID, userID).Scan(&foreign); err != nil {
+		logger.FromContext(ctx).ErrorContext(ctx, "ItemInForeignStores ошибка бд", slog.Any("err", err), slog.String("item_id", itemID))
+		return false, err
+	}
+	return foreign, nil
+}
+
+// CreateMenuItem добавляет позицию в меню магазина: создает товар или, если
+// задан in.ItemID, привязывает существующий. Возвращает id store_item.
+func (r *MenuRepoPostgres) CreateMenuItem(ctx context.Context, storeID string, in *domain.MenuItemInput) (string, error) {
+	log := logger.FromContext(ctx)
+	log.DebugContext(ctx, "CreateMenuItem начало обработки", slog.String("store_id", storeID))
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		log.ErrorContext(ctx, "CreateMenuItem begin failed", slog.Any("err", err))
+		return "", err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var itemID string
+	if in.ItemID != nil {
+		itemID = *in.ItemID
+		if _, err := tx.Exec(ctx, unarchiveItem, itemID); err != nil {
+			log.ErrorContext(ctx, "CreateMenuItem unarchive failed", slog.Any("err", err), slog.String("item_id", itemID))
+			return "", err
+		}
+	} else {
+		itemID = uuid.New().String()
+		if _, err := tx.Exec(ctx, insertMenuItem, itemID, *in.Name, *in.Description); err != nil {
+			log.WarnContext(ctx, "CreateMenuItem insert item failed", slog.Any("err", err))
+			return "", menuError(err)
+		}
+		if err := replaceItemTypes(ctx, tx, itemID, in.TypeIDs); err != nil {
+			return "", err
+		}
+	}
+
+	var id string
+	err = tx.QueryRow(ctx, attachItem, uuid.New().String(), storeID, itemID, *in.Price).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", domain.ErrMenuItemExist
+	}
+	if err != nil {
+		log.WarnContext(ctx, "CreateMenuItem attach failed", slog.Any("err", err), slog.String("item_id", itemID))
+		return "", menuError(err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		log.ErrorContext(ctx, "CreateMenuItem commit failed", slog.Any("err", err), slog.String("store_id", storeID))
+		return "", err
+	}
+
+	log.DebugContext(ctx, "CreateMenuItem завершено успешно",
+		slog.String("store_id", storeID),
+		slog.String("store_item_id", id))
+	return id, nil
+}
+
+// lockMenuItemVersion блокирует позицию и проверяет, что клиент видел ее текущую
+// версию. Возвращает id товара.
+func lockMenuItemVersion(ctx context.Context, tx pgx.Tx, storeID, id string, version time.Time) (string, error) {
+	var itemID string
+	var current time.Time
+	err := tx.QueryRow(ctx, lockMenuItem, storeID, id).Scan(&itemID, &current)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", domain.ErrRowsNotFound
+	}
+	if err != nil {
+		logger.FromContext(ctx).ErrorContext(ctx, "lockMenuItemVersion failed", slog.Any("err", err), slog.String("store_item_id", id))
+		return "", err
+	}
+	// postgres хранит микросекунды
+	if !current.Equal(version.Truncate(time.Microsecond)) {
+		return "", domain.ErrMenuConflict
+	}
+	return itemID, nil
+}
+
+// UpdateMenuItem меняет товар, его типы и цену в магазине, если позиция не
+// менялась с версии in.UpdatedAt
+func (r *MenuRepoPostgres) UpdateMenuItem(ctx context.Context, storeID, id string, in *domain.MenuItemInput) error {
+	log := logger.FromContext(ctx)
+	log.DebugContext(ctx, "UpdateMenuItem начало обработки", slog.String("store_item_id", id))
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		log.ErrorContext(ctx, "UpdateMenuItem begin failed", slog.Any("err", err))
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	itemID, err := lockMenuItemVersion(ctx, tx, storeID, id, in.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	if in.Name != nil || in.Description != nil || in.TypeIDs != nil {
+		if _, err := tx.Exec(ctx, updateMenuItem, itemID, in.Name, in.Description); err != nil {
+			log.WarnContext(ctx, "UpdateMenuItem update item failed", slog.Any("err", err), slog.String("item_id", itemID))
+			return menuError(err)
+		}
+	}
+	if in.TypeIDs != nil {
+		if err := replaceItemTypes(ctx, tx, itemID, in.TypeIDs); err != nil {
+			return err
+		}
+	}
+	if in.Price != nil {
+		if _, err := tx.Exec(ctx, updateMenuPrice, id, *in.Price); err != nil {
+			log.WarnContext(ctx, "UpdateMenuItem update price failed", slog.Any("err", err), slog.String("store_item_id", id))
+			return menuError(err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		log.ErrorContext(ctx, "UpdateMenuItem commit failed", slog.Any("err", err), slog.String("store_item_id", id))
+		return err
+	}
+
+	log.DebugContext(ctx, "UpdateMenuItem завершено успешно", slog.String("store_item_id", id))
+	return nil
+}
+
+// ArchiveMenuItem снимает позицию с продажи и убирает ее из корзин. Товар,
+// который больше нигде не продается, тоже архивируется.
+func (r *MenuRepoPostgres) ArchiveMenuItem(ctx context.Context, storeID, id string, version time.Time) error {
+	log := logger.FromContext(ctx)
+	log.DebugContext(ctx, "ArchiveMenuItem начало обработки", slog.String("store_item_id", id))
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		log.ErrorContext(ctx, "ArchiveMenuItem begin failed", slog.Any("err", err))
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	itemID, err := lockMenuItemVersion(ctx, tx, storeID, id, version)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, archiveMenuItem, id); err != nil {
+		log.ErrorContext(ctx, "ArchiveMenuItem archive failed", slog.Any("err", err), slog.String("store_item_id", id))
+		return err
+	}
+	if _, err := tx.Exec(ctx, deleteMenuCartItems, id); err != nil {
+		log.ErrorContext(ctx, "ArchiveMenuItem delete cart items failed", slog.Any("err", err), slog.String("store_item_id", id))
+		return err
+	}
+	if _, err := tx.Exec(ctx, archiveOrphanItem, itemID); err != nil {
+		log.ErrorContext(ctx, "ArchiveMenuItem archive item failed", slog.Any("err", err), slog.String("item_id", itemID))
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		log.ErrorContext(ctx, "ArchiveMenuItem commit failed", slog.Any("err", err), slog.String("store_item_id", id))
+		return err
+	}
+
+	log.DebugContext(ctx, "ArchiveMenuItem завершено успешно", slog.String("store_item_id", id))
+	return nil
+}
+
+// replaceItemTypes заменяет типы товара
+func replaceItemTypes(ctx context.Context, tx pgx.Tx, itemID string, typeIDs []string) error {
+	log := logger.FromContext(ctx)
+
+	if _, err := tx.Exec(ctx, deleteItemTypes, itemID); err != nil {
+		log.ErrorContext(ctx, "replaceItemTypes delete failed", slog.Any("err", err), slog.String("item_id", itemID))
+		return err
+	}
+	if _, err := tx.Exec(ctx, insertItemTypes, itemID, typeIDs); err != nil {
+		log.WarnContext(ctx, "replaceItemTypes insert failed", slog.Any("err", err), slog.String("item_id", itemID))
+		return menuError(err)
+	}
+	return nil
+}
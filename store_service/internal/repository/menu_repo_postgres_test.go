@@ -0,0 +1,195 @@
+package repository
+
+import (
+	"apple_backend/store_service/internal/domain"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pashagolub/pgxmock/v2"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	menuStoreID = "00000000-0000-0000-0000-000000000001"
+	menuID      = "00000000-0000-0000-0000-000000000002"
+	menuItemID  = "00000000-0000-0000-0000-000000000003"
+)
+
+// menuVersion — версия позиции, которую видел клиент
+var menuVersion = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// expectLockMenuItem ждет блокировку позиции, текущая версия которой current
+func expectLockMenuItem(mock pgxmock.PgxPoolIface, current time.Time) {
+	mock.ExpectQuery(`from store_item si\s+join item i on i.id = si.item_id\s+where si.store_id = \$1\s+and si.id = \$2\s+for update`).
+		WithArgs(menuStoreID, menuID).
+		WillReturnRows(pgxmock.NewRows([]string{"item_id", "updated_at"}).AddRow(menuItemID, current))
+}
+
+func TestMenuRepoPostgres_UpdateMenuItem(t *testing.T) {
+	type testCase struct {
+		name          string
+		mockSetup     func(mock pgxmock.PgxPoolIface)
+		expectedError error
+	}
+
+	price := 150.0
+
+	tests := []testCase{
+		{
+			name: "успешное изменение цены",
+			mockSetup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectBegin()
+				expectLockMenuItem(mock, menuVersion)
+				mock.ExpectExec(`update store_item`).
+					WithArgs(menuID, price).
+					WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+				mock.ExpectCommit()
+			},
+			expectedError: nil,
+		},
+		{
+			name: "позицию изменили после того, как ее прочитал клиент",
+			mockSetup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectBegin()
+				expectLockMenuItem(mock, menuVersion.Add(time.Second))
+				mock.ExpectRollback()
+			},
+			expectedError: domain.ErrMenuConflict,
+		},
+		{
+			name: "позиции нет в магазине",
+			mockSetup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`for update`).
+					WithArgs(menuStoreID, menuID).
+					WillReturnRows(pgxmock.NewRows([]string{"item_id", "updated_at"}))
+				mock.ExpectRollback()
+			},
+			expectedError: domain.ErrRowsNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPool, err := pgxmock.NewPool()
+			require.NoError(t, err)
+			defer mockPool.Close()
+
+			repo := NewMenuRepoPostgres(mockPool)
+
+			tt.mockSetup(mockPool)
+
+			err = repo.UpdateMenuItem(context.Background(), menuStoreID, menuID,
+				&domain.MenuItemInput{Price: &price, UpdatedAt: menuVersion})
+			require.Equal(t, tt.expectedError, err)
+			require.NoError(t, mockPool.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMenuRepoPostgres_ArchiveMenuItem(t *testing.T) {
+	type testCase struct {
+		name          string
+		mockSetup     func(mock pgxmock.PgxPoolIface)
+		expectedError error
+	}
+
+	tests := []testCase{
+		{
+			name: "позиция архивируется и убирается из корзин",
+			mockSetup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectBegin()
+				expectLockMenuItem(mock, menuVersion)
+				mock.ExpectExec(`update store_item\s+set archived_at = current_timestamp`).
+					WithArgs(menuID).
+					WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+				mock.ExpectExec(`delete\s+from cart_item\s+where store_item_id = \$1`).
+					WithArgs(menuID).
+					WillReturnResult(pgxmock.NewResult("DELETE", 3))
+				mock.ExpectExec(`update item\s+set archived_at = current_timestamp`).
+					WithArgs(menuItemID).
+					WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+				mock.ExpectCommit()
+			},
+			expectedError: nil,
+		},
+		{
+			name: "устаревшая версия не трогает позицию и корзины",
+			mockSetup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectBegin()
+				expectLockMenuItem(mock, menuVersion.Add(time.Millisecond))
+				mock.ExpectRollback()
+			},
+			expectedError: domain.ErrMenuConflict,
+		},
+		{
+			name: "ошибка удаления из корзин откатывает архивацию",
+			mockSetup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectBegin()
+				expectLockMenuItem(mock, menuVersion)
+				mock.ExpectExec(`update store_item\s+set archived_at = current_timestamp`).
+					WithArgs(menuID).
+					WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+				mock.ExpectExec(`delete\s+from cart_item`).
+					WithArgs(menuID).
+					WillReturnError(domain.ErrInternalServer)
+				mock.ExpectRollback()
+			},
+			expectedError: domain.ErrInternalServer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPool, err := pgxmock.NewPool()
+			require.NoError(t, err)
+			defer mockPool.Close()
+
+			repo := NewMenuRepoPostgres(mockPool)
+
+			tt.mockSetup(mockPool)
+
+			err = repo.ArchiveMenuItem(context.Background(), menuStoreID, menuID, menuVersion)
+			require.Equal(t, tt.expectedError, err)
+			require.NoError(t, mockPool.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestItemRepoPostgres_GetItemsSkipsArchived(t *testing.T) {
+	mockPool, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mockPool.Close()
+
+	// публичное меню читает только позиции и товары, которые не в архиве
+	mockPool.ExpectQuery(`where store_item.store_id = \$1\s+and store_item.archived_at is null\s+and item.archived_at is null`).
+		WithArgs(menuStoreID).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "name", "price", "description", "card_img", "type_id"}))
+
+	repo := NewItemRepoPostgres(mockPool)
+	items, err := repo.GetItems(context.Background(), menuStoreID)
+
+	require.Equal(t, domain.ErrRowsNotFound, err)
+	require.Nil(t, items)
+	require.NoError(t, mockPool.ExpectationsWereMet())
+}
+
+func TestCartRepoPostgres_UpdateCartItemsRejectsArchived(t *testing.T) {
+	mockPool, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mockPool.Close()
+
+	// архивная позиция не находится проверкой и в корзину не попадает
+	mockPool.ExpectQuery(`WHERE si.id = \$1 AND si.archived_at IS NULL AND i.archived_at IS NULL`).
+		WithArgs(menuID).
+		WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(false))
+
+	repo := NewCartRepoPostgres(mockPool)
+	err = repo.UpdateCartItems(context.Background(), menuStoreID, &domain.CartUpdate{
+		Items: []*domain.ItemUpdate{{ID: menuID, Quantity: 1}},
+	})
+
+	require.Equal(t, domain.ErrRowsNotFound, err)
+	require.NoError(t, mockPool.ExpectationsWereMet())
+}
@@ -33,6 +33,9 @@ var getOrder string
 //go:embed sql/order/get_user_orders.sql
 var getUserOrders string
 
+//go:embed sql/order/get_store_orders.sql
+var getStoreOrders string
+
 //go:embed sql/cart/delete_items.sql
 var deleteCartItemsForOrder string
 
@@ -201,15 +204,54 @@ func (r *OrderRepoPostgres) GetOrdersUser(ctx context.Context, filter *domain.Or
 		slog.String("last_id", filter.LastID),
 		slog.String("status", filter.Status))
 
-	rows, err := r.db.Query(ctx, getUserOrders, filter.UserID, filter.LastID, filter.Limit)
+	orders, err := r.queryOrders(ctx, "GetOrdersUser", getUserOrders, filter.UserID, filter.LastID, filter.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(orders) == 0 {
+		log.DebugContext(ctx, "repo GetOrdersUser no orders found", slog.String("user_id", filter.UserID))
+		return nil, domain.ErrRowsNotFound
+	}
+
+	log.DebugContext(ctx, "repo GetOrdersUser success",
+		slog.String("user_id", filter.UserID),
+		slog.Int("orders_count", len(orders)))
+	return orders, nil
+}
+
+// GetStoreOrders возвращает заказы, в которых есть позиции магазина
+// filter.StoreID, новые сначала
+func (r *OrderRepoPostgres) GetStoreOrders(ctx context.Context, filter *domain.OrderFilter) ([]*domain.Order, error) {
+	log := logger.FromContext(ctx)
+	log.DebugContext(ctx, "repo GetStoreOrders params",
+		slog.String("store_id", filter.StoreID),
+		slog.Int("limit", filter.Limit),
+		slog.String("last_id", filter.LastID))
+
+	orders, err := r.queryOrders(ctx, "GetStoreOrders", getStoreOrders, filter.StoreID, filter.LastID, filter.Limit)
 	if err != nil {
-		log.ErrorContext(ctx, "repo GetOrdersUser query failed",
-			slog.String("user_id", filter.UserID), slog.Any("err", err))
+		return nil, err
+	}
+
+	log.DebugContext(ctx, "repo GetStoreOrders success",
+		slog.String("store_id", filter.StoreID),
+		slog.Int("orders_count", len(orders)))
+	return orders, nil
+}
+
+// queryOrders выполняет запрос списка заказов
+func (r *OrderRepoPostgres) queryOrders(ctx context.Context, op, query string, args ...any) ([]*domain.Order, error) {
+	log := logger.FromContext(ctx)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		log.ErrorContext(ctx, "repo "+op+" query failed", slog.Any("err", err))
 		return nil, domain.ErrInternalServer
 	}
 	defer rows.Close()
 
-	var orders []*domain.Order
+	orders := []*domain.Order{}
 	for rows.Next() {
 		var order domain.Order
 		err = rows.Scan(
@@ -219,26 +261,15 @@ func (r *OrderRepoPostgres) GetOrdersUser(ctx context.Context, filter *domain.Or
 			&order.CreatedAt,
 		)
 		if err != nil {
-			log.ErrorContext(ctx, "repo GetOrdersUser scan failed",
-				slog.String("user_id", filter.UserID), slog.Any("err", err))
+			log.ErrorContext(ctx, "repo "+op+" scan failed", slog.Any("err", err))
 			return nil, domain.ErrInternalServer
 		}
 		orders = append(orders, &order)
 	}
 
 	if err = rows.Err(); err != nil {
-		log.ErrorContext(ctx, "repo GetOrdersUser rows error",
-			slog.String("user_id", filter.UserID), slog.Any("err", err))
+		log.ErrorContext(ctx, "repo "+op+" rows error", slog.Any("err", err))
 		return nil, domain.ErrInternalServer
 	}
-
-	if len(orders) == 0 {
-		log.DebugContext(ctx, "repo GetOrdersUser no orders found", slog.String("user_id", filter.UserID))
-		return nil, domain.ErrRowsNotFound
-	}
-
-	log.DebugContext(ctx, "repo GetOrdersUser success",
-		slog.String("user_id", filter.UserID),
-		slog.Int("orders_count", len(orders)))
 	return orders, nil
 }
@@ -1,11 +1,12 @@
 package repository
 
 import (
-	"apple_backend/pkg/logger"
 	"apple_backend/store_service/internal/domain"
 	"context"
+	"errors"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/pashagolub/pgxmock/v2"
@@ -22,22 +23,12 @@ func TestOrderRepoPostgres_GetOrder(t *testing.T) {
 	}
 
 	query := `
-		select
-			o.id as order_id,
-			o.total_price as total,
-			o.status as status,
-			o.created_at as created_at,
-			si.id as store_item_id,
-			i.name as name,
-			i.card_img as card_img,
-			oi.price as price,
-			oi.quantity as quantity
-		from "order" o
-		join order_item oi on oi.order_id = o.id
-		join store_item si on si.id = oi.store_item_id
-		join item i on i.id = si.item_id
-		where o.id = \$1
-		order by oi.created_at;
+		FROM orders o
+		JOIN order_item oi on oi.order_id = o.id
+		JOIN store_item si on si.id = oi.store_item_id
+		JOIN item i on i.id = si.item_id
+		WHERE o.id = \$1
+		ORDER BY oi.created_at;
 	`
 
 	orderID := "00000000-0000-0000-0000-000000000001"
@@ -63,7 +54,7 @@ func TestOrderRepoPostgres_GetOrder(t *testing.T) {
 		ID:        orderID,
 		Total:     41.0,
 		Status:    "paid",
-		CreatedAt: "2024-01-01",
+		CreatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
 		Items:     []*domain.OrderItemInfo{item1, item2},
 	}
 
@@ -160,8 +151,7 @@ func TestOrderRepoPostgres_GetOrder(t *testing.T) {
 			require.NoError(t, err)
 			defer mockPool.Close()
 
-			log := logger.NewNilLogger()
-			repo := NewOrderRepoPostgres(mockPool, log)
+			repo := NewOrderRepoPostgres(mockPool)
 
 			tt.mockSetup(mockPool)
 
@@ -183,14 +173,16 @@ func TestOrderRepoPostgres_GetOrdersUser(t *testing.T) {
 	}
 
 	query := `
-		select
-			o.id as id,
-			o.status as status,
-			o.total_price as total,
-			o.created_at as created_at
-		from "order" o
-		where o.user_id = \$1
-		order by o.created_at;
+		FROM orders o
+		WHERE o.user_id = \$1
+		AND \(
+		    \$2 = ''
+		    OR o.created_at < \(
+		        SELECT created_at FROM orders WHERE id = \$2
+		    \)
+		\)
+		ORDER BY o.created_at DESC
+		LIMIT \$3;
 	`
 
 	userID := "00000000-0000-0000-0000-000000000123"
@@ -199,13 +191,13 @@ func TestOrderRepoPostgres_GetOrdersUser(t *testing.T) {
 		ID:        "11111111-1111-1111-1111-111111111111",
 		Status:    "paid",
 		Total:     50.0,
-		CreatedAt: "2024-01-01",
+		CreatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
 	}
 	order2 := &domain.Order{
 		ID:        "22222222-2222-2222-2222-222222222222",
 		Status:    "shipped",
 		Total:     100.0,
-		CreatedAt: "2024-01-01",
+		CreatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
 	}
 
 	tests := []testCase{
@@ -218,7 +210,7 @@ func TestOrderRepoPostgres_GetOrdersUser(t *testing.T) {
 					AddRow(order2.ID, order2.Status, order2.Total, order2.CreatedAt)
 
 				mock.ExpectQuery(query).
-					WithArgs(userID).
+					WithArgs(userID, "", 10).
 					WillReturnRows(rows)
 			},
 			expectedRes:   []*domain.Order{order1, order2},
@@ -229,7 +221,7 @@ func TestOrderRepoPostgres_GetOrdersUser(t *testing.T) {
 			userID: userID,
 			mockSetup: func(mock pgxmock.PgxPoolIface) {
 				mock.ExpectQuery(query).
-					WithArgs(userID).
+					WithArgs(userID, "", 10).
 					WillReturnError(domain.ErrInternalServer)
 			},
 			expectedRes:   nil,
@@ -244,7 +236,7 @@ func TestOrderRepoPostgres_GetOrdersUser(t *testing.T) {
 					RowError(0, domain.ErrInternalServer)
 
 				mock.ExpectQuery(query).
-					WithArgs(userID).
+					WithArgs(userID, "", 10).
 					WillReturnRows(rows)
 			},
 			expectedRes:   nil,
@@ -256,7 +248,7 @@ func TestOrderRepoPostgres_GetOrdersUser(t *testing.T) {
 			mockSetup: func(mock pgxmock.PgxPoolIface) {
 				rows := pgxmock.NewRows([]string{"id", "status", "total", "created_at"})
 				mock.ExpectQuery(query).
-					WithArgs(userID).
+					WithArgs(userID, "", 10).
 					WillReturnRows(rows)
 			},
 			expectedRes:   nil,
@@ -272,12 +264,93 @@ func TestOrderRepoPostgres_GetOrdersUser(t *testing.T) {
 			require.NoError(t, err)
 			defer mockPool.Close()
 
-			log := logger.NewNilLogger()
-			repo := NewOrderRepoPostgres(mockPool, log)
+			repo := NewOrderRepoPostgres(mockPool)
+
+			tt.mockSetup(mockPool)
+
+			res, err := repo.GetOrdersUser(context.Background(), &domain.OrderFilter{UserID: tt.userID, Limit: 10})
+
+			require.Equal(t, tt.expectedError, err)
+			require.Equal(t, tt.expectedRes, res)
+		})
+	}
+}
+
+func TestOrderRepoPostgres_GetStoreOrders(t *testing.T) {
+	type testCase struct {
+		name          string
+		mockSetup     func(mock pgxmock.PgxPoolIface)
+		expectedRes   []*domain.Order
+		expectedError error
+	}
+
+	query := `WHERE EXISTS \(
+		SELECT 1
+		FROM order_item oi
+		JOIN store_item si on si.id = oi.store_item_id
+		WHERE oi.order_id = o.id
+		AND si.store_id = \$1
+		\)`
+
+	storeID := "00000000-0000-0000-0000-000000000010"
+	lastID := "11111111-1111-1111-1111-111111111111"
+	filter := &domain.OrderFilter{StoreID: storeID, LastID: lastID, Limit: 10}
+
+	order := &domain.Order{
+		ID:        "22222222-2222-2222-2222-222222222222",
+		Status:    "paid",
+		Total:     50.0,
+		CreatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	tests := []testCase{
+		{
+			name: "успешный запрос",
+			mockSetup: func(mock pgxmock.PgxPoolIface) {
+				rows := pgxmock.NewRows([]string{"id", "status", "total", "created_at"}).
+					AddRow(order.ID, order.Status, order.Total, order.CreatedAt)
+				mock.ExpectQuery(query).
+					WithArgs(storeID, lastID, 10).
+					WillReturnRows(rows)
+			},
+			expectedRes:   []*domain.Order{order},
+			expectedError: nil,
+		},
+		{
+			name: "пустой результат",
+			mockSetup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery(query).
+					WithArgs(storeID, lastID, 10).
+					WillReturnRows(pgxmock.NewRows([]string{"id", "status", "total", "created_at"}))
+			},
+			expectedRes:   []*domain.Order{},
+			expectedError: nil,
+		},
+		{
+			name: "ошибка запроса к БД",
+			mockSetup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery(query).
+					WithArgs(storeID, lastID, 10).
+					WillReturnError(errors.New("db error"))
+			},
+			expectedRes:   nil,
+			expectedError: domain.ErrInternalServer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockPool, err := pgxmock.NewPool()
+			require.NoError(t, err)
+			defer mockPool.Close()
+
+			repo := NewOrderRepoPostgres(mockPool)
 
 			tt.mockSetup(mockPool)
 
-			res, err := repo.GetOrdersUser(context.Background(), tt.userID)
+			res, err := repo.GetStoreOrders(context.Background(), filter)
 
 			require.Equal(t, tt.expectedError, err)
 			require.Equal(t, tt.expectedRes, res)
@@ -295,10 +368,10 @@ func TestOrderRepoPostgres_UpdateOrderStatus(t *testing.T) {
 	}
 
 	query := `
-		update "order"
-		set status = \$2
-		where id = \$1
-		returning id
+		UPDATE orders
+		SET status = \$2
+		WHERE id = \$1
+		RETURNING id
 	`
 
 	orderID := "00000000-0000-0000-0000-000000000123"
@@ -348,8 +421,7 @@ func TestOrderRepoPostgres_UpdateOrderStatus(t *testing.T) {
 			require.NoError(t, err)
 			defer mockPool.Close()
 
-			log := logger.NewNilLogger()
-			repo := NewOrderRepoPostgres(mockPool, log)
+			repo := NewOrderRepoPostgres(mockPool)
 
 			tt.mockSetup(mockPool)
 
@@ -370,26 +442,24 @@ func TestOrderRepoPostgres_CreateOrder(t *testing.T) {
 		expectID      bool
 	}
 
+	countQuery := `SELECT COUNT\(\*\) FROM cart_item ci JOIN cart c ON c.id = ci.cart_id WHERE c.user_id = \$1`
 	insertOrderQuery := `
-		insert into "order" \(id, user_id, total_price\)
-		values \(\$1, \$2, 0\);
+		INSERT INTO orders \(id, user_id, total_price\) VALUES \(\$1, \$2, 0\);
 	`
 	insertItemsQuery := `
-		insert into order_item \(id, order_id, store_item_id, price, quantity\)
-		select gen_random_uuid\(\), \$1, si.id, si.price, ci.quantity
-		from cart_item ci
-		join cart c on c.id = ci.cart_id
-		join store_item si on si.id = ci.store_item_id
-		where c.user_id = \$2;
+		INSERT INTO order_item \(id, order_id, store_item_id, price, quantity\)
+		SELECT gen_random_uuid\(\), \$1, si.id, si.price, ci.quantity
+		FROM cart_item ci
+		JOIN cart c on c.id = ci.cart_id
+		JOIN store_item si on si.id = ci.store_item_id
+		WHERE c.user_id = \$2;
 	`
 	updateTotalQuery := regexp.QuoteMeta(`
-		update "order"
-		set total_price = (select sum(si.price * ci.quantity)
-						   from cart_item ci
-									join cart c on c.id = ci.cart_id
-									join store_item si on si.id = ci.store_item_id
-						   where c.user_id = $1)
-		where id = $2;`)
+		UPDATE orders
+		SET total_price = (
+			SELECT COALESCE(SUM(oi.price * oi.quantity), 0) FROM order_item oi WHERE oi.order_id = $1
+		)
+		WHERE id = $1;`)
 	deleteCartQuery := `
 		delete from cart_item
 		where cart_id = \(select id from cart where user_id = \$1\)
@@ -398,11 +468,18 @@ func TestOrderRepoPostgres_CreateOrder(t *testing.T) {
 
 	userID := "00000000-0000-0000-0000-000000000123"
 
+	expectCartCount := func(mock pgxmock.PgxPoolIface, cnt int) {
+		mock.ExpectQuery(countQuery).
+			WithArgs(userID).
+			WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(cnt))
+	}
+
 	tests := []testCase{
 		{
 			name:   "успешное создание заказа",
 			userID: userID,
 			mockSetup: func(mock pgxmock.PgxPoolIface) {
+				expectCartCount(mock, 2)
 				mock.ExpectBegin()
 
 				mock.ExpectExec(insertOrderQuery).
@@ -414,7 +491,7 @@ func TestOrderRepoPostgres_CreateOrder(t *testing.T) {
 					WillReturnResult(pgxmock.NewResult("INSERT", 2))
 
 				mock.ExpectExec(updateTotalQuery).
-					WithArgs(userID).
+					WithArgs(pgxmock.AnyArg()).
 					WillReturnResult(pgxmock.NewResult("UPDATE", 1))
 
 				mock.ExpectExec(deleteCartQuery).
@@ -426,10 +503,20 @@ func TestOrderRepoPostgres_CreateOrder(t *testing.T) {
 			expectedError: nil,
 			expectID:      true,
 		},
+		{
+			name:   "пустая корзина",
+			userID: userID,
+			mockSetup: func(mock pgxmock.PgxPoolIface) {
+				expectCartCount(mock, 0)
+			},
+			expectedError: domain.ErrCartEmpty,
+			expectID:      false,
+		},
 		{
 			name:   "ошибка при начале транзакции",
 			userID: userID,
 			mockSetup: func(mock pgxmock.PgxPoolIface) {
+				expectCartCount(mock, 2)
 				mock.ExpectBegin().WillReturnError(domain.ErrInternalServer)
 			},
 			expectedError: domain.ErrInternalServer,
@@ -439,6 +526,7 @@ func TestOrderRepoPostgres_CreateOrder(t *testing.T) {
 			name:   "ошибка при insert order",
 			userID: userID,
 			mockSetup: func(mock pgxmock.PgxPoolIface) {
+				expectCartCount(mock, 2)
 				mock.ExpectBegin()
 				mock.ExpectExec(insertOrderQuery).
 					WithArgs(pgxmock.AnyArg(), userID).
@@ -452,6 +540,7 @@ func TestOrderRepoPostgres_CreateOrder(t *testing.T) {
 			name:   "ошибка при insert order_item",
 			userID: userID,
 			mockSetup: func(mock pgxmock.PgxPoolIface) {
+				expectCartCount(mock, 2)
 				mock.ExpectBegin()
 				mock.ExpectExec(insertOrderQuery).
 					WithArgs(pgxmock.AnyArg(), userID).
@@ -468,6 +557,7 @@ func TestOrderRepoPostgres_CreateOrder(t *testing.T) {
 			name:   "ошибка при обновлении суммы заказа",
 			userID: userID,
 			mockSetup: func(mock pgxmock.PgxPoolIface) {
+				expectCartCount(mock, 2)
 				mock.ExpectBegin()
 				mock.ExpectExec(insertOrderQuery).
 					WithArgs(pgxmock.AnyArg(), userID).
@@ -476,7 +566,7 @@ func TestOrderRepoPostgres_CreateOrder(t *testing.T) {
 					WithArgs(pgxmock.AnyArg(), userID).
 					WillReturnResult(pgxmock.NewResult("INSERT", 2))
 				mock.ExpectExec(updateTotalQuery).
-					WithArgs(userID).
+					WithArgs(pgxmock.AnyArg()).
 					WillReturnError(domain.ErrInternalServer)
 				mock.ExpectRollback()
 			},
@@ -487,6 +577,7 @@ func TestOrderRepoPostgres_CreateOrder(t *testing.T) {
 			name:   "ошибка при удалении корзины",
 			userID: userID,
 			mockSetup: func(mock pgxmock.PgxPoolIface) {
+				expectCartCount(mock, 2)
 				mock.ExpectBegin()
 				mock.ExpectExec(insertOrderQuery).
 					WithArgs(pgxmock.AnyArg(), userID).
@@ -495,7 +586,7 @@ func TestOrderRepoPostgres_CreateOrder(t *testing.T) {
 					WithArgs(pgxmock.AnyArg(), userID).
 					WillReturnResult(pgxmock.NewResult("INSERT", 2))
 				mock.ExpectExec(updateTotalQuery).
-					WithArgs(userID).
+					WithArgs(pgxmock.AnyArg()).
 					WillReturnResult(pgxmock.NewResult("UPDATE", 1))
 				mock.ExpectExec(deleteCartQuery).
 					WithArgs(userID).
@@ -509,6 +600,7 @@ func TestOrderRepoPostgres_CreateOrder(t *testing.T) {
 			name:   "ошибка при завершении транзакции",
 			userID: userID,
 			mockSetup: func(mock pgxmock.PgxPoolIface) {
+				expectCartCount(mock, 2)
 				mock.ExpectBegin()
 				mock.ExpectExec(insertOrderQuery).
 					WithArgs(pgxmock.AnyArg(), userID).
@@ -517,7 +609,7 @@ func TestOrderRepoPostgres_CreateOrder(t *testing.T) {
 					WithArgs(pgxmock.AnyArg(), userID).
 					WillReturnResult(pgxmock.NewResult("INSERT", 2))
 				mock.ExpectExec(updateTotalQuery).
-					WithArgs(userID).
+					WithArgs(pgxmock.AnyArg()).
 					WillReturnResult(pgxmock.NewResult("UPDATE", 1))
 				mock.ExpectExec(deleteCartQuery).
 					WithArgs(userID).
@@ -537,8 +629,7 @@ func TestOrderRepoPostgres_CreateOrder(t *testing.T) {
 			require.NoError(t, err)
 			defer mockPool.Close()
 
-			log := logger.NewNilLogger()
-			repo := NewOrderRepoPostgres(mockPool, log)
+			repo := NewOrderRepoPostgres(mockPool)
 
 			tt.mockSetup(mockPool)
 
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"apple_backend/pkg/logger"
+	"apple_backend/store_service/internal/domain"
+	"context"
+	_ "embed"
+	"log/slog"
+)
+
+//go:embed sql/search/search.sql
+var searchCatalog string
+
+//go:embed sql/search/set_threshold.sql
+var setSearchThreshold string
+
+// searchThreshold — порог word_similarity для поиска с опечатками
+const searchThreshold = "0.4"
+
+type SearchRepoPostgres struct {
+	db PgxIface
+}
+
+func NewSearchRepoPostgres(db PgxIface) *SearchRepoPostgres {
+	return &SearchRepoPostgres{
+		db: db,
+	}
+}
+
+// Search ищет магазины и позиции по каждому из вариантов запроса и
+// возвращает не больше limit строк, лучшие первыми
+func (r *SearchRepoPostgres) Search(ctx context.Context, variants []string, cityID string, limit int) ([]*domain.SearchHit, error) {
+	log := logger.FromContext(ctx)
+	log.DebugContext(ctx, "Search начало обработки",
+		slog.Any("variants", variants),
+		slog.String("city_id", cityID))
+
+	// порог задается на транзакцию, чтобы не менять его для всего соединения
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		log.ErrorContext(ctx, "Search begin failed", slog.Any("err", err))
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, setSearchThreshold, searchThreshold); err != nil {
+		log.ErrorContext(ctx, "Search set threshold failed", slog.Any("err", err))
+		return nil, err
+	}
+
+	rows, err := tx.Query(ctx, searchCatalog, variants, cityID, limit)
+	if err != nil {
+		log.ErrorContext(ctx, "Search ошибка бд", slog.Any("err", err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	hits := []*domain.SearchHit{}
+	for rows.Next() {
+		store := &domain.SearchStore{}
+		var itemID, itemName, itemImg *string
+		var itemPrice, itemRank *float64
+		err = rows.Scan(
+			&store.ID,
+			&store.Name,
+			&store.Description,
+			&store.CityID,
+			&store.Address,
+			&store.CardImg,
+			&store.Rating,
+			&store.Rank,
+			&itemID,
+			&itemName,
+			&itemImg,
+			&itemPrice,
+			&itemRank,
+		)
+		if err != nil {
+			log.ErrorContext(ctx, "Search ошибка при декодировании данных", slog.Any("err", err))
+			return nil, err
+		}
+
+		hit := &domain.SearchHit{Store: store}
+		if itemID != nil {
+			hit.Item = &domain.SearchItem{
+				ID:      *itemID,
+				Name:    *itemName,
+				CardImg: *itemImg,
+				Price:   *itemPrice,
+				Rank:    *itemRank,
+			}
+		}
+		hits = append(hits, hit)
+	}
+	if err = rows.Err(); err != nil {
+		log.ErrorContext(ctx, "Search ошибка после чтения строк", slog.Any("err", err))
+		return nil, err
+	}
+
+	log.DebugContext(ctx, "Search завершено успешно", slog.Int("rows_count", len(hits)))
+	return hits, nil
+}
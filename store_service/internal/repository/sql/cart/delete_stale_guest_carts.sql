@@ -0,0 +1,4 @@
+delete
+from cart
+where user_id is null
+  and updated_at < $1;
\ No newline at end of file
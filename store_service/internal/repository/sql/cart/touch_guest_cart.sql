@@ -0,0 +1,6 @@
+-- продлевает жизнь гостевой корзины: срок считается от updated_at
+update cart
+set updated_at = current_timestamp
+where id = $1
+  and user_id is null
+returning id;
\ No newline at end of file
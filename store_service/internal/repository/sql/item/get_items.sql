@@ -1,5 +1,7 @@
-select store_item.id, item.name, store_item.price, item.description, item.card_img, item_type.type_id
+select store_item.id, item.name, store_item.price, item.description, coalesce(item.card_img, ''), item_type.type_id
 from store_item
          join item on store_item.item_id = item.id
          join item_type on item.id = item_type.item_id
-where store_item.store_id = $1
\ No newline at end of file
+where store_item.store_id = $1
+  and store_item.archived_at is null
+  and item.archived_at is null
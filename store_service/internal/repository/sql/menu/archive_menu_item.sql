@@ -0,0 +1,4 @@
+update store_item
+set archived_at = current_timestamp
+where id = $1
+  and archived_at is null
@@ -0,0 +1,9 @@
+-- товар архивируется, когда его больше не продает ни один магазин
+update item
+set archived_at = current_timestamp
+where id = $1
+  and archived_at is null
+  and not exists(select 1
+                 from store_item
+                 where item_id = $1
+                   and archived_at is null)
@@ -0,0 +1,9 @@
+-- повторная привязка архивной позиции возвращает ее в продажу с новой ценой;
+-- активная позиция не меняется, и запрос не возвращает строк
+insert into store_item (id, store_id, item_id, price)
+values ($1, $2, $3, $4)
+on conflict (store_id, item_id) do update
+    set price       = excluded.price,
+        archived_at = null
+    where store_item.archived_at is not null
+returning id
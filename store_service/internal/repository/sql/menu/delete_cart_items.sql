@@ -0,0 +1,4 @@
+-- снятая с продажи позиция убирается из корзин, чтобы ее нельзя было заказать
+delete
+from cart_item
+where store_item_id = $1
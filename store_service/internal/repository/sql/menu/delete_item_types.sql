@@ -0,0 +1,3 @@
+delete
+from item_type
+where item_id = $1
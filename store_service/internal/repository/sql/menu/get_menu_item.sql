@@ -0,0 +1,15 @@
+select si.id,
+       i.id,
+       i.name,
+       i.description,
+       coalesce(i.card_img, ''),
+       si.price,
+       coalesce(array_agg(it.type_id::text order by it.type_id) filter (where it.type_id is not null), '{}'),
+       si.archived_at is not null or i.archived_at is not null,
+       greatest(si.updated_at, i.updated_at)
+from store_item si
+         join item i on i.id = si.item_id
+         left join item_type it on it.item_id = i.id
+where si.store_id = $1
+  and si.id = $2
+group by si.id, i.id
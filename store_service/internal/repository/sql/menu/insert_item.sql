@@ -0,0 +1,2 @@
+insert into item (id, name, description)
+values ($1, $2, $3)
@@ -0,0 +1,3 @@
+insert into item_type (id, item_id, type_id)
+select gen_random_uuid(), $1, type_id
+from unnest($2::uuid[]) as type_id
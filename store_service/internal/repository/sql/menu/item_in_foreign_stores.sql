@@ -0,0 +1,9 @@
+-- товар общий для магазинов: менять его может только тот, кто владеет
+-- всеми магазинами, где он продается
+select exists(select 1
+              from store_item si
+              where si.item_id = $1
+                and not exists(select 1
+                               from store_owner so
+                               where so.store_id = si.store_id
+                                 and so.user_id = $2))
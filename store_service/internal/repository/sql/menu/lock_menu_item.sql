@@ -0,0 +1,8 @@
+-- блокирует позицию и товар до конца транзакции и возвращает текущую версию
+select si.item_id,
+       greatest(si.updated_at, i.updated_at)
+from store_item si
+         join item i on i.id = si.item_id
+where si.store_id = $1
+  and si.id = $2
+for update of si, i
@@ -0,0 +1,4 @@
+update item
+set archived_at = null
+where id = $1
+  and archived_at is not null
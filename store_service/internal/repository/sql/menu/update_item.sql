@@ -0,0 +1,6 @@
+-- updated_at меняется и тогда, когда поменялись только типы товара
+update item
+set name        = coalesce($2, name),
+    description = coalesce($3, description),
+    updated_at  = current_timestamp
+where id = $1
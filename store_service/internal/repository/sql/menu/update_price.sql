@@ -0,0 +1,4 @@
+-- цены в order_item зафиксированы при оформлении и не меняются
+update store_item
+set price = $2
+where id = $1
@@ -0,0 +1,20 @@
+SELECT o.id          as id,
+       o.status      as status,
+       o.total_price as total,
+       o.created_at  as created_at
+FROM orders o
+WHERE EXISTS (
+    SELECT 1
+    FROM order_item oi
+    JOIN store_item si on si.id = oi.store_item_id
+    WHERE oi.order_id = o.id
+      AND si.store_id = $1
+)
+AND (
+    $2 = ''
+    OR o.created_at < (
+        SELECT created_at FROM orders WHERE id::text = $2
+    )
+)
+ORDER BY o.created_at DESC
+LIMIT $3;
@@ -1,3 +1,3 @@
-SELECT user_id
+SELECT coalesce(user_id::text, '')
 FROM orders
 WHERE id = $1
\ No newline at end of file
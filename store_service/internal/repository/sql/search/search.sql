@@ -0,0 +1,55 @@
+-- $1 — варианты запроса (исходный и транслитерации), $2 — id города или '',
+-- $3 — предел числа строк.
+-- Полнотекстовое совпадение всегда выше триграммного: его ранг 1 + ts_rank,
+-- а word_similarity не больше 1.
+with q as (select plainto_tsquery('russian', v) as tsq,
+                  lower(v)                      as v
+           from unnest($1::text[]) as v),
+     store_match as (select s.id,
+                            max(case
+                                    when s.search_vector @@ q.tsq then 1 + ts_rank(s.search_vector, q.tsq)
+                                    else word_similarity(q.v, lower(s.name))
+                                end) as rank
+                     from store s
+                              cross join q
+                     where ($2 = '' or s.city_id::text = $2)
+                       and (s.search_vector @@ q.tsq or q.v <% lower(s.name))
+                     group by s.id),
+     item_match as (select si.id,
+                           si.store_id,
+                           i.name,
+                           coalesce(i.card_img, '') as card_img,
+                           si.price,
+                           max(case
+                                   when i.search_vector @@ q.tsq then 1 + ts_rank(i.search_vector, q.tsq)
+                                   else word_similarity(q.v, lower(i.name))
+                               end)                 as rank
+                    from store_item si
+                             join item i on i.id = si.item_id
+                             join store s on s.id = si.store_id
+                             cross join q
+                    where si.archived_at is null
+                      and i.archived_at is null
+                      and ($2 = '' or s.city_id::text = $2)
+                      and (i.search_vector @@ q.tsq or q.v <% lower(i.name))
+                    group by si.id, i.id)
+select s.id,
+       s.name,
+       s.description,
+       coalesce(s.city_id::text, ''),
+       s.address,
+       coalesce(s.card_img, ''),
+       coalesce(s.rating, 0),
+       coalesce(sm.rank, 0),
+       im.id,
+       im.name,
+       im.card_img,
+       im.price,
+       im.rank
+from store s
+         left join store_match sm on sm.id = s.id
+         left join item_match im on im.store_id = s.id
+where sm.id is not null
+   or im.id is not null
+order by greatest(coalesce(sm.rank, 0), coalesce(im.rank, 0)) desc, s.id, im.id
+limit $3
@@ -0,0 +1,3 @@
+-- порог триграммного совпадения только для текущей транзакции; по умолчанию
+-- 0.6, и слово с одной опечаткой не находится
+select set_config('pg_trgm.word_similarity_threshold', $1, true)
@@ -0,0 +1,2 @@
+select exists(select 1 from store where id = $1),
+       exists(select 1 from store_owner where store_id = $1 and user_id = $2)
\ No newline at end of file
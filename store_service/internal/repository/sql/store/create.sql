@@ -1,2 +1,4 @@
-insert into store (id, name, description, city_id, address, card_img, rating, open_at, closed_at)
-values ($1, $2, $3, $4, $5, $6, $7, $8, $9)
\ No newline at end of file
+insert into store (id, name, description, city_id, address, open_at, closed_at, rating,
+                   lat, lon, delivery_radius_m, delivery_area,
+                   zone_min_lat, zone_min_lon, zone_max_lat, zone_max_lon)
+values ($1, $2, $3, $4, $5, $6, $7, 0, $8, $9, $10, $11, $12, $13, $14, $15)
\ No newline at end of file
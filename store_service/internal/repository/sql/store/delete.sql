@@ -0,0 +1,4 @@
+delete
+from store
+where id = $1
+returning coalesce(card_img, '')
\ No newline at end of file
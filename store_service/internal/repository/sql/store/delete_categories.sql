@@ -0,0 +1,3 @@
+delete
+from store_category
+where store_id = $1
\ No newline at end of file
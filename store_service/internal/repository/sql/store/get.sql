@@ -2,10 +2,10 @@ SELECT
     s.id,
     s.name,
     s.description,
-    s.city_id,
+    COALESCE(s.city_id::text, '') AS city_id,
     s.address,
-    s.card_img,
-    s.rating,
+    COALESCE(s.card_img, '') AS card_img,
+    COALESCE(s.rating, 0) AS rating,
     s.open_at,
     s.closed_at,
     COALESCE(
@@ -14,7 +14,22 @@ SELECT
                 st.tag_id IS NOT NULL
         ),
         '{}'
-    ) AS tag_ids
+    ) AS tag_ids,
+    COALESCE(
+        (
+            SELECT
+                array_agg(sc.category_id)
+            FROM
+                store_category sc
+            WHERE
+                sc.store_id = s.id
+        ),
+        '{}'
+    ) AS category_ids,
+    s.lat,
+    s.lon,
+    s.delivery_radius_m,
+    s.delivery_area
 FROM
     store s
     LEFT JOIN store_tag st ON st.store_id = s.id
@@ -29,4 +44,8 @@ GROUP BY
     s.card_img,
     s.rating,
     s.open_at,
-    s.closed_at
\ No newline at end of file
+    s.closed_at,
+    s.lat,
+    s.lon,
+    s.delivery_radius_m,
+    s.delivery_area
\ No newline at end of file
@@ -0,0 +1,5 @@
+-- order_item ссылается на store_item каскадно: удаление магазина стерло бы историю заказов
+select exists(select 1
+              from order_item oi
+                       join store_item si on si.id = oi.store_item_id
+              where si.store_id = $1)
\ No newline at end of file
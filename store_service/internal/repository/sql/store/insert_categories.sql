@@ -0,0 +1,3 @@
+insert into store_category (id, store_id, category_id)
+select gen_random_uuid(), $1, category_id
+from unnest($2::uuid[]) as category_id
\ No newline at end of file
@@ -0,0 +1,2 @@
+insert into store_owner (store_id, user_id)
+values ($1, $2)
\ No newline at end of file
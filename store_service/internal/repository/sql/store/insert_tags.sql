@@ -0,0 +1,3 @@
+insert into store_tag (id, store_id, tag_id)
+select gen_random_uuid(), $1, tag_id
+from unnest($2::uuid[]) as tag_id
\ No newline at end of file
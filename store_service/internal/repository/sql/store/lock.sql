@@ -0,0 +1,4 @@
+select id
+from store
+where id = $1
+for update
\ No newline at end of file
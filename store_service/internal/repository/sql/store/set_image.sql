@@ -0,0 +1,5 @@
+update store s
+set card_img = $2
+from (select id, card_img from store where id = $1 for update) old
+where s.id = old.id
+returning coalesce(old.card_img, '')
\ No newline at end of file
@@ -0,0 +1,11 @@
+-- популярность позиции — сколько штук заказано
+select i.id,
+       i.name,
+       si.store_id,
+       coalesce(sum(oi.quantity), 0)
+from store_item si
+         join item i on i.id = si.item_id
+         left join order_item oi on oi.store_item_id = si.id
+where si.archived_at is null
+  and i.archived_at is null
+group by si.id, i.id
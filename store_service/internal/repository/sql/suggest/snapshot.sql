@@ -0,0 +1,2 @@
+-- все запросы снимка видят каталог на один момент
+set transaction isolation level repeatable read read only
@@ -0,0 +1,10 @@
+-- популярность магазина — число заказов с его позициями
+select s.id,
+       s.name,
+       coalesce(s.city_id::text, ''),
+       coalesce(s.rating, 0),
+       count(distinct oi.order_id)
+from store s
+         left join store_item si on si.store_id = s.id
+         left join order_item oi on oi.store_item_id = si.id
+group by s.id
@@ -0,0 +1,5 @@
+select t.id,
+       t.name,
+       st.store_id
+from store_tag st
+         join tag t on t.id = st.tag_id
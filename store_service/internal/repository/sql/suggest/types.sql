@@ -0,0 +1,9 @@
+select distinct ty.id,
+                ty.name,
+                si.store_id
+from store_item si
+         join item i on i.id = si.item_id
+         join item_type it on it.item_id = i.id
+         join type ty on ty.id = it.type_id
+where si.archived_at is null
+  and i.archived_at is null
@@ -0,0 +1,2 @@
+select version
+from catalog_version
@@ -0,0 +1,310 @@
+package repository
+
+import (
+	"apple_backend/pkg/logger"
+	"apple_backend/store_service/internal/domain"
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+//go:embed sql/store/create.sql
+var createStore string
+
+//go:embed sql/store/insert_owner.sql
+var insertStoreOwner string
+
+//go:embed sql/store/check_owner.sql
+var checkStoreOwner string
+
+//go:embed sql/store/lock.sql
+var lockStore string
+
+//go:embed sql/store/delete_tags.sql
+var deleteStoreTags string
+
+//go:embed sql/store/insert_tags.sql
+var insertStoreTags string
+
+//go:embed sql/store/delete_categories.sql
+var deleteStoreCategories string
+
+//go:embed sql/store/insert_categories.sql
+var insertStoreCategories string
+
+//go:embed sql/store/has_orders.sql
+var storeHasOrders string
+
+//go:embed sql/store/delete.sql
+var deleteStore string
+
+//go:embed sql/store/set_image.sql
+var setStoreImage string
+
+// storeError переводит нарушения ограничений таблиц магазина в ошибки домена
+func storeError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+	switch pgErr.Code {
+	case "23505":
+		return domain.ErrStoreExist
+	case "23503":
+		switch pgErr.ConstraintName {
+		case "store_city_id_fkey":
+			return domain.ErrCityNotFound
+		case "store_tag_tag_id_fkey":
+			return domain.ErrTagNotFound
+		case "store_category_category_id_fkey":
+			return domain.ErrCategoryNotFound
+		}
+	case "23514":
+		// usecase проверяет те же ограничения, сюда попадают только расхождения
+		return fmt.Errorf("%w: %s", domain.ErrRequestParams, pgErr.ConstraintName)
+	}
+	return err
+}
+
+// CreateStore создает магазин вместе с тегами и категориями и делает
+// ownerID его владельцем
+func (r *StoreRepoPostgres) CreateStore(ctx context.Context, ownerID string, in *domain.StoreInput) (string, error) {
+	log := logger.FromContext(ctx)
+	log.DebugContext(ctx, "CreateStore начало обработки", slog.String("owner_id", ownerID))
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		log.ErrorContext(ctx, "CreateStore begin failed", slog.Any("err", err))
+		return "", err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	id := uuid.New().String()
+	args := append([]any{id, *in.Name, *in.Description, *in.CityID, *in.Address, *in.OpenAt, *in.ClosedAt},
+		locationArgs(in.Location)...)
+	_, err = tx.Exec(ctx, createStore, args...)
+	if err != nil {
+		log.WarnContext(ctx, "CreateStore insert failed", slog.Any("err", err))
+		return "", storeError(err)
+	}
+	if _, err := tx.Exec(ctx, insertStoreOwner, id, ownerID); err != nil {
+		log.ErrorContext(ctx, "CreateStore insert owner failed", slog.Any("err", err), slog.String("store_id", id))
+		return "", err
+	}
+	if err := replaceStoreLinks(ctx, tx, id, in); err != nil {
+		return "", err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		log.ErrorContext(ctx, "CreateStore commit failed", slog.Any("err", err), slog.String("store_id", id))
+		return "", err
+	}
+
+	log.DebugContext(ctx, "CreateStore завершено успешно", slog.String("store_id", id))
+	return id, nil
+}
+
+// CheckStoreOwner возвращает domain.ErrRowsNotFound, если магазина нет, и
+// domain.ErrForbidden, если userID им не владеет
+func (r *StoreRepoPostgres) CheckStoreOwner(ctx context.Context, storeID, userID string) error {
+	log := logger.FromContext(ctx)
+
+	var exists, owner bool
+	if err := r.db.QueryRow(ctx, checkStoreOwner, storeID, userID).Scan(&exists, &owner); err != nil {
+		log.ErrorContext(ctx, "CheckStoreOwner ошибка бд", slog.Any("err", err), slog.String("store_id", storeID))
+		return err
+	}
+	if !exists {
+		return domain.ErrRowsNotFound
+	}
+	if !owner {
+		return domain.ErrForbidden
+	}
+	return nil
+}
+
+// UpdateStore меняет только заданные поля магазина
+func (r *StoreRepoPostgres) UpdateStore(ctx context.Context, id string, in *domain.StoreInput) error {
+	log := logger.FromContext(ctx)
+	log.DebugContext(ctx, "UpdateStore начало обработки", slog.String("store_id", id))
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		log.ErrorContext(ctx, "UpdateStore begin failed", slog.Any("err", err))
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var locked string
+	err = tx.QueryRow(ctx, lockStore, id).Scan(&locked)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.ErrRowsNotFound
+	}
+	if err != nil {
+		log.ErrorContext(ctx, "UpdateStore lock failed", slog.Any("err", err), slog.String("store_id", id))
+		return err
+	}
+
+	if query, args := generateUpdateQuery(id, in); query != "" {
+		if _, err := tx.Exec(ctx, query, args...); err != nil {
+			log.WarnContext(ctx, "UpdateStore update failed", slog.Any("err", err), slog.String("store_id", id))
+			return storeError(err)
+		}
+	}
+	if err := replaceStoreLinks(ctx, tx, id, in); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		log.ErrorContext(ctx, "UpdateStore commit failed", slog.Any("err", err), slog.String("store_id", id))
+		return err
+	}
+
+	log.DebugContext(ctx, "UpdateStore завершено успешно", slog.String("store_id", id))
+	return nil
+}
+
+func generateUpdateQuery(id string, in *domain.StoreInput) (string, []any) {
+	args := []any{id}
+	set := []string{}
+	add := func(column string, v *string) {
+		if v == nil {
+			return
+		}
+		args = append(args, *v)
+		set = append(set, fmt.Sprintf("%s = $%d", column, len(args)))
+	}
+	add("name", in.Name)
+	add("description", in.Description)
+	add("city_id", in.CityID)
+	add("address", in.Address)
+	add("open_at", in.OpenAt)
+	add("closed_at", in.ClosedAt)
+
+	if in.Location != nil {
+		for i, v := range locationArgs(in.Location) {
+			args = append(args, v)
+			set = append(set, fmt.Sprintf("%s = $%d", locationColumns[i], len(args)))
+		}
+	}
+
+	if len(set) == 0 {
+		return "", nil
+	}
+	return "UPDATE store SET " + strings.Join(set, ", ") + " WHERE id = $1", args
+}
+
+// locationColumns — колонки координат и зоны в порядке locationArgs
+var locationColumns = []string{
+	"lat", "lon", "delivery_radius_m", "delivery_area",
+	"zone_min_lat", "zone_min_lon", "zone_max_lat", "zone_max_lon",
+}
+
+// locationArgs раскладывает координаты и зону по колонкам; nil обнуляет их
+func locationArgs(loc *domain.StoreLocation) []any {
+	if loc == nil {
+		return make([]any, len(locationColumns))
+	}
+	args := []any{loc.Lat, loc.Lon, loc.DeliveryRadius, loc.DeliveryArea, nil, nil, nil, nil}
+	if b := loc.Bounds; b != nil {
+		args[4], args[5], args[6], args[7] = b.MinLat, b.MinLon, b.MaxLat, b.MaxLon
+	}
+	return args
+}
+
+// replaceStoreLinks заменяет теги и категории магазина, если они заданы
+func replaceStoreLinks(ctx context.Context, tx pgx.Tx, id string, in *domain.StoreInput) error {
+	log := logger.FromContext(ctx)
+
+	if in.TagIDs != nil {
+		if _, err := tx.Exec(ctx, deleteStoreTags, id); err != nil {
+			log.ErrorContext(ctx, "replaceStoreLinks delete tags failed", slog.Any("err", err), slog.String("store_id", id))
+			return err
+		}
+		if _, err := tx.Exec(ctx, insertStoreTags, id, in.TagIDs); err != nil {
+			log.WarnContext(ctx, "replaceStoreLinks insert tags failed", slog.Any("err", err), slog.String("store_id", id))
+			return storeError(err)
+		}
+	}
+	if in.CategoryIDs != nil {
+		if _, err := tx.Exec(ctx, deleteStoreCategories, id); err != nil {
+			log.ErrorContext(ctx, "replaceStoreLinks delete categories failed", slog.Any("err", err), slog.String("store_id", id))
+			return err
+		}
+		if _, err := tx.Exec(ctx, insertStoreCategories, id, in.CategoryIDs); err != nil {
+			log.WarnContext(ctx, "replaceStoreLinks insert categories failed", slog.Any("err", err), slog.String("store_id", id))
+			return storeError(err)
+		}
+	}
+	return nil
+}
+
+// DeleteStore удаляет магазин, если по нему не было заказов, и возвращает
+// имя файла его карточки
+func (r *StoreRepoPostgres) DeleteStore(ctx context.Context, id string) (string, error) {
+	log := logger.FromContext(ctx)
+	log.DebugContext(ctx, "DeleteStore начало обработки", slog.String("store_id", id))
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		log.ErrorContext(ctx, "DeleteStore begin failed", slog.Any("err", err))
+		return "", err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var locked string
+	err = tx.QueryRow(ctx, lockStore, id).Scan(&locked)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", domain.ErrRowsNotFound
+	}
+	if err != nil {
+		log.ErrorContext(ctx, "DeleteStore lock failed", slog.Any("err", err), slog.String("store_id", id))
+		return "", err
+	}
+
+	var hasOrders bool
+	if err := tx.QueryRow(ctx, storeHasOrders, id).Scan(&hasOrders); err != nil {
+		log.ErrorContext(ctx, "DeleteStore orders check failed", slog.Any("err", err), slog.String("store_id", id))
+		return "", err
+	}
+	if hasOrders {
+		return "", domain.ErrStoreHasOrders
+	}
+
+	var cardImg string
+	if err := tx.QueryRow(ctx, deleteStore, id).Scan(&cardImg); err != nil {
+		log.ErrorContext(ctx, "DeleteStore delete failed", slog.Any("err", err), slog.String("store_id", id))
+		return "", err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		log.ErrorContext(ctx, "DeleteStore commit failed", slog.Any("err", err), slog.String("store_id", id))
+		return "", err
+	}
+
+	log.DebugContext(ctx, "DeleteStore завершено успешно", slog.String("store_id", id))
+	return cardImg, nil
+}
+
+// SetStoreImage записывает имя файла карточки (nil — убрать картинку) и
+// возвращает прежнее имя, чтобы удалить старый файл
+func (r *StoreRepoPostgres) SetStoreImage(ctx context.Context, id string, cardImg *string) (string, error) {
+	log := logger.FromContext(ctx)
+
+	var old string
+	err := r.db.QueryRow(ctx, setStoreImage, id, cardImg).Scan(&old)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", domain.ErrRowsNotFound
+	}
+	if err != nil {
+		log.ErrorContext(ctx, "SetStoreImage ошибка бд", slog.Any("err", err), slog.String("store_id", id))
+		return "", storeError(err)
+	}
+	return old, nil
+}
@@ -5,14 +5,10 @@ import (
 	"apple_backend/store_service/internal/domain"
 	"context"
 	_ "embed"
-	"errors"
 	"fmt"
 	"log/slog"
 	"strings"
 	"time"
-
-	"github.com/google/uuid"
-	"github.com/jackc/pgx/v5/pgconn"
 )
 
 type StoreRepoPostgres struct {
@@ -28,9 +24,11 @@ func NewStoreRepoPostgres(db PgxIface) *StoreRepoPostgres {
 func generateQuery(filter *domain.StoreFilter) (string, []any) {
 	query := `
         SELECT 
-            s.id, s.name, s.description, s.city_id, s.address, 
-            s.card_img, s.rating, s.open_at, s.closed_at,
-            COALESCE(array_agg(st.tag_id) FILTER (WHERE st.tag_id IS NOT NULL), '{}') AS tag_ids
+            s.id, s.name, s.description, COALESCE(s.city_id::text, ''), s.address, 
+            COALESCE(s.card_img, ''), COALESCE(s.rating, 0), s.open_at, s.closed_at,
+            COALESCE(array_agg(st.tag_id) FILTER (WHERE st.tag_id IS NOT NULL), '{}') AS tag_ids,
+            COALESCE((SELECT array_agg(sc.category_id) FROM store_category sc WHERE sc.store_id = s.id), '{}') AS category_ids,
+            s.lat, s.lon, s.delivery_radius_m, s.delivery_area
         FROM store s
         LEFT JOIN store_tag st ON s.id = st.store_id
     `
@@ -49,11 +47,26 @@ func generateQuery(filter *domain.StoreFilter) (string, []any) {
 		args = append(args, filter.CityID)
 	}
 
+	// отбор по точке: только магазины, в прямоугольник зоны которых она
+	// попадает. Точную проверку, сортировку по расстоянию и пагинацию
+	// делает usecase
+	if filter.Point != nil {
+		where = append(where, fmt.Sprintf(
+			"s.zone_min_lat <= $%d AND s.zone_max_lat >= $%d AND s.zone_min_lon <= $%d AND s.zone_max_lon >= $%d",
+			len(args)+1, len(args)+1, len(args)+2, len(args)+2))
+		args = append(args, filter.Point.Lat, filter.Point.Lon)
+	}
+
 	if len(where) > 0 {
 		query += " WHERE " + strings.Join(where, " AND ")
 	}
 
-	query += " GROUP BY s.id, s.name, s.description, s.city_id, s.address, s.card_img, s.rating, s.open_at, s.closed_at"
+	query += " GROUP BY s.id, s.name, s.description, s.city_id, s.address, s.card_img, s.rating, s.open_at, s.closed_at," +
+		" s.lat, s.lon, s.delivery_radius_m, s.delivery_area"
+
+	if filter.Point != nil {
+		return query, args
+	}
 
 	// пагинация
 	if filter.LastID != "" {
@@ -123,6 +136,11 @@ func (r *StoreRepoPostgres) GetStores(ctx context.Context, filter *domain.StoreF
 			&store.OpenAt,
 			&store.ClosedAt,
 			&tagIDs,
+			&store.CategoriesID,
+			&store.Lat,
+			&store.Lon,
+			&store.DeliveryRadius,
+			&store.DeliveryArea,
 		)
 		if err != nil {
 			log.ErrorContext(ctx, "GetStores ошибка при декодировании данных", slog.Any("err", err))
@@ -182,6 +200,11 @@ func (r *StoreRepoPostgres) GetStore(ctx context.Context, id string) (*domain.St
 		&store.OpenAt,
 		&store.ClosedAt,
 		&tagIDs,
+		&store.CategoriesID,
+		&store.Lat,
+		&store.Lon,
+		&store.DeliveryRadius,
+		&store.DeliveryArea,
 	)
 	if err != nil {
 		log.ErrorContext(ctx, "GetStore ошибка при декодировании данных", slog.Any("err", err))
@@ -241,30 +264,6 @@ func (r *StoreRepoPostgres) GetStoreReview(ctx context.Context, id string) ([]*d
 	return reviews, nil
 }
 
-//go:embed sql/store/create.sql
-var createStore string
-
-func (r *StoreRepoPostgres) CreateStore(ctx context.Context, store *domain.Store) error {
-	log := logger.FromContext(ctx)
-	log.DebugContext(ctx, "CreateStore начало обработки")
-
-	store.ID = uuid.New().String()
-	_, err := r.db.Exec(ctx, createStore, store.ID, store.Name, store.Description,
-		store.CityID, store.Address, store.CardImg, store.Rating, store.OpenAt, store.ClosedAt)
-	if err != nil {
-		var pgErr *pgconn.PgError
-		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
-			log.WarnContext(ctx, "CreateStore unique ограничение", slog.Any("err", err))
-			return domain.ErrStoreExist
-		}
-		log.ErrorContext(ctx, "CreateStore ошибка бд", slog.Any("err", err))
-		return err
-	}
-
-	log.DebugContext(ctx, "CreateStore завершено успешно")
-	return nil
-}
-
 //go:embed sql/store/get_tag.sql
 var getTags string
 
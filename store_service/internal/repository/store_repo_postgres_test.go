@@ -1,63 +1,77 @@
 package repository
 
 import (
-	"apple_backend/pkg/logger"
+	"apple_backend/pkg/geo"
 	"apple_backend/store_service/internal/domain"
 	"context"
 	"errors"
 	"testing"
+	"time"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/pashagolub/pgxmock/v2"
 	"github.com/stretchr/testify/require"
 )
 
+// storeColumns — колонки, которые сканируют GetStore и GetStores
+var storeColumns = []string{"id", "name", "description", "city_id", "address", "card_img", "rating", "open_at", "closed_at",
+	"tag_ids", "category_ids", "lat", "lon", "delivery_radius_m", "delivery_area"}
+
+func storeRow(s *domain.StoreAgg) []any {
+	return []any{s.ID, s.Name, s.Description, s.CityID, s.Address, s.CardImg, s.Rating, s.OpenAt, s.ClosedAt,
+		s.TagsID, s.CategoriesID, s.Lat, s.Lon, s.DeliveryRadius, s.DeliveryArea}
+}
+
 func TestStoreRepoPostgres_GetStore(t *testing.T) {
 	type testCase struct {
 		name          string
 		mockSetup     func(mock pgxmock.PgxPoolIface)
 		storeID       string
-		expectedRes   []*domain.Store
+		expectedRes   *domain.StoreAgg
 		expectedError error
 	}
 
 	storeID := "00000000-0000-0000-0000-000000000001"
-	stores := []*domain.Store{
+	lat, lon, radius := 55.75, 37.62, 3000
+	stores := []*domain.StoreAgg{
 		{
-			ID:          storeID,
-			Name:        "Store1",
-			Description: "Description1",
-			CityID:      "City1",
-			Address:     "Address1",
-			CardImg:     "img1",
-			Rating:      4.5,
-			TagID:       storeID,
-			OpenAt:      "08:00",
-			ClosedAt:    "22:00",
+			ID:             storeID,
+			Name:           "Store1",
+			Description:    "Description1",
+			CityID:         "City1",
+			Address:        "Address1",
+			CardImg:        "img1",
+			Rating:         4.5,
+			TagsID:         []string{storeID},
+			CategoriesID:   []string{},
+			OpenAt:         "08:00",
+			ClosedAt:       "22:00",
+			Lat:            &lat,
+			Lon:            &lon,
+			DeliveryRadius: &radius,
 		},
 	}
+	query := `s.lat,\s+s.lon,\s+s.delivery_radius_m,\s+s.delivery_area\s+FROM\s+store s\s+LEFT JOIN store_tag st ON st.store_id = s.id\s+WHERE\s+s.id = \$1`
 
 	tests := []testCase{
 		{
 			name:    "успешный запрос",
 			storeID: storeID,
 			mockSetup: func(mock pgxmock.PgxPoolIface) {
-				rows := pgxmock.NewRows([]string{"id", "name", "description", "city_id", "address", "card_img", "rating", "open_at", "closed_at", "tag_id"}).
-					AddRow(stores[0].ID, stores[0].Name, stores[0].Description, stores[0].CityID, stores[0].Address, stores[0].CardImg, stores[0].Rating, stores[0].OpenAt, stores[0].ClosedAt, stores[0].TagID)
-				mock.ExpectQuery(`select s.id, s.name, s.description, s.city_id, s.address, s.card_img, s.rating, s.open_at, s.closed_at, st.tag_id
-		from store s left join store_tag st on st.store_id = s.id where id = \$1`).
+				rows := pgxmock.NewRows(storeColumns).AddRow(storeRow(stores[0])...)
+				mock.ExpectQuery(query).
 					WithArgs(storeID).
 					WillReturnRows(rows)
 			},
-			expectedRes:   stores,
+			expectedRes:   stores[0],
 			expectedError: nil,
 		},
 		{
 			name:    "пустой результат",
 			storeID: storeID,
 			mockSetup: func(mock pgxmock.PgxPoolIface) {
-				rows := pgxmock.NewRows([]string{"id", "name", "description", "city_id", "address", "card_img", "rating", "open_at", "closed_at", "tag_id"})
-				mock.ExpectQuery(`select s.id, s.name, s.description, s.city_id, s.address, s.card_img, s.rating, s.open_at, s.closed_at, st.tag_id
-		from store s left join store_tag st on st.store_id = s.id where id = \$1`).
+				rows := pgxmock.NewRows(storeColumns)
+				mock.ExpectQuery(query).
 					WithArgs(storeID).
 					WillReturnRows(rows)
 			},
@@ -68,12 +82,11 @@ func TestStoreRepoPostgres_GetStore(t *testing.T) {
 			name:    "ошибка при чтении",
 			storeID: storeID,
 			mockSetup: func(mock pgxmock.PgxPoolIface) {
-				rows := pgxmock.NewRows([]string{"id", "name", "description", "city_id", "address", "card_img", "rating", "open_at", "closed_at", "tag_id"}).
-					AddRow(stores[0].ID, stores[0].Name, stores[0].Description, stores[0].CityID, stores[0].Address, stores[0].CardImg, stores[0].Rating, stores[0].OpenAt, stores[0].ClosedAt, stores[0].TagID).
+				rows := pgxmock.NewRows(storeColumns).
+					AddRow(storeRow(stores[0])...).
 					RowError(0, domain.ErrInternalServer)
 
-				mock.ExpectQuery(`select s.id, s.name, s.description, s.city_id, s.address, s.card_img, s.rating, s.open_at, s.closed_at, st.tag_id
-		from store s left join store_tag st on st.store_id = s.id where id = \$1`).
+				mock.ExpectQuery(query).
 					WithArgs(storeID).
 					WillReturnRows(rows)
 			},
@@ -84,8 +97,7 @@ func TestStoreRepoPostgres_GetStore(t *testing.T) {
 			name:    "ошибка запроса",
 			storeID: storeID,
 			mockSetup: func(mock pgxmock.PgxPoolIface) {
-				mock.ExpectQuery(`select s.id, s.name, s.description, s.city_id, s.address, s.card_img, s.rating, s.open_at, s.closed_at, st.tag_id
-		from store s left join store_tag st on st.store_id = s.id where id = \$1`).
+				mock.ExpectQuery(query).
 					WithArgs(storeID).
 					WillReturnError(domain.ErrInternalServer)
 			},
@@ -102,8 +114,7 @@ func TestStoreRepoPostgres_GetStore(t *testing.T) {
 			require.NoError(t, err)
 			defer mockPool.Close()
 
-			log := logger.NewNilLogger()
-			repo := NewStoreRepoPostgres(mockPool, log)
+			repo := NewStoreRepoPostgres(mockPool)
 
 			tt.mockSetup(mockPool)
 
@@ -119,19 +130,26 @@ func TestStoreRepoPostgres_CreateStore(t *testing.T) {
 	type testCase struct {
 		name          string
 		mockSetup     func(mock pgxmock.PgxPoolIface)
-		inputStore    *domain.Store
+		inputStore    *domain.StoreInput
 		expectedError error
 	}
 
-	store := &domain.Store{
-		Name:        "Store1",
-		Description: "Description1",
-		CityID:      "City1",
-		Address:     "Address1",
-		CardImg:     "img1",
-		Rating:      4.5,
-		OpenAt:      "08:00",
-		ClosedAt:    "22:00",
+	ownerID := "00000000-0000-0000-0000-000000000002"
+	name, description, cityID := "Store1", "Description1", "00000000-0000-0000-0000-000000000003"
+	address, openAt, closedAt := "Address1", "08:00", "22:00"
+	store := &domain.StoreInput{
+		Name:        &name,
+		Description: &description,
+		CityID:      &cityID,
+		Address:     &address,
+		OpenAt:      &openAt,
+		ClosedAt:    &closedAt,
+	}
+
+	insertStore := func(mock pgxmock.PgxPoolIface) *pgxmock.ExpectedExec {
+		return mock.ExpectExec(`insert into store \(id, name, description, city_id, address, open_at, closed_at, rating`).
+			WithArgs(pgxmock.AnyArg(), name, description, cityID, address, openAt, closedAt,
+				nil, nil, nil, nil, nil, nil, nil, nil)
 	}
 
 	tests := []testCase{
@@ -139,10 +157,12 @@ func TestStoreRepoPostgres_CreateStore(t *testing.T) {
 			name:       "успешное создание",
 			inputStore: store,
 			mockSetup: func(mock pgxmock.PgxPoolIface) {
-				mock.ExpectExec(`insert into store \(id, name, description, city_id, address, card_img, rating, open_at, closed_at\)
-		values \(\$1, \$2, \$3, \$4, \$5, \$6, \$7, \$8, \$9\)`).
-					WithArgs(pgxmock.AnyArg(), store.Name, store.Description, store.CityID, store.Address, store.CardImg, store.Rating, store.OpenAt, store.ClosedAt).
+				mock.ExpectBegin()
+				insertStore(mock).WillReturnResult(pgxmock.NewResult("INSERT", 1))
+				mock.ExpectExec(`insert into store_owner`).
+					WithArgs(pgxmock.AnyArg(), ownerID).
 					WillReturnResult(pgxmock.NewResult("INSERT", 1))
+				mock.ExpectCommit()
 			},
 			expectedError: nil,
 		},
@@ -150,10 +170,9 @@ func TestStoreRepoPostgres_CreateStore(t *testing.T) {
 			name:       "уникальный конфликт",
 			inputStore: store,
 			mockSetup: func(mock pgxmock.PgxPoolIface) {
-				mock.ExpectExec(`insert into store \(id, name, description, city_id, address, card_img, rating, open_at, closed_at\)
-		values \(\$1, \$2, \$3, \$4, \$5, \$6, \$7, \$8, \$9\)`).
-					WithArgs(pgxmock.AnyArg(), store.Name, store.Description, store.CityID, store.Address, store.CardImg, store.Rating, store.OpenAt, store.ClosedAt).
-					WillReturnError(errors.New("SQLSTATE 23505"))
+				mock.ExpectBegin()
+				insertStore(mock).WillReturnError(&pgconn.PgError{Code: "23505"})
+				mock.ExpectRollback()
 			},
 			expectedError: domain.ErrStoreExist,
 		},
@@ -161,10 +180,9 @@ func TestStoreRepoPostgres_CreateStore(t *testing.T) {
 			name:       "другая ошибка бд",
 			inputStore: store,
 			mockSetup: func(mock pgxmock.PgxPoolIface) {
-				mock.ExpectExec(`insert into store \(id, name, description, city_id, address, card_img, rating, open_at, closed_at\)
-		values \(\$1, \$2, \$3, \$4, \$5, \$6, \$7, \$8, \$9\)`).
-					WithArgs(pgxmock.AnyArg(), store.Name, store.Description, store.CityID, store.Address, store.CardImg, store.Rating, store.OpenAt, store.ClosedAt).
-					WillReturnError(domain.ErrInternalServer)
+				mock.ExpectBegin()
+				insertStore(mock).WillReturnError(domain.ErrInternalServer)
+				mock.ExpectRollback()
 			},
 			expectedError: domain.ErrInternalServer,
 		},
@@ -178,13 +196,13 @@ func TestStoreRepoPostgres_CreateStore(t *testing.T) {
 			require.NoError(t, err)
 			defer mockPool.Close()
 
-			log := logger.NewNilLogger()
-			repo := NewStoreRepoPostgres(mockPool, log)
+			repo := NewStoreRepoPostgres(mockPool)
 
 			tt.mockSetup(mockPool)
 
-			err = repo.CreateStore(context.Background(), tt.inputStore)
+			_, err = repo.CreateStore(context.Background(), ownerID, tt.inputStore)
 			require.Equal(t, tt.expectedError, err)
+			require.NoError(t, mockPool.ExpectationsWereMet())
 		})
 	}
 }
@@ -194,7 +212,7 @@ func TestStoreRepoPostgres_GetStores(t *testing.T) {
 		name          string
 		filter        *domain.StoreFilter
 		mockSetup     func(mock pgxmock.PgxPoolIface)
-		expectedRes   []*domain.Store
+		expectedRes   []*domain.StoreAgg
 		expectedError error
 	}
 
@@ -216,107 +234,132 @@ func TestStoreRepoPostgres_GetStores(t *testing.T) {
 	openAt2 := "open_at2"
 	closeAt2 := "close_at2"
 
-	store1 := &domain.Store{
-		ID:          uid1,
-		Name:        name1,
-		Description: description1,
-		CityID:      uid1,
-		Address:     address1,
-		CardImg:     cardImg1,
-		Rating:      rating1,
-		TagID:       uid1,
-		OpenAt:      openAt1,
-		ClosedAt:    closeAt1,
+	lat, lon, radius := 55.75, 37.62, 3000
+
+	store1 := &domain.StoreAgg{
+		ID:             uid1,
+		Name:           name1,
+		Description:    description1,
+		CityID:         uid1,
+		Address:        address1,
+		CardImg:        cardImg1,
+		Rating:         rating1,
+		TagsID:         []string{uid1},
+		CategoriesID:   []string{uid1},
+		OpenAt:         openAt1,
+		ClosedAt:       closeAt1,
+		Lat:            &lat,
+		Lon:            &lon,
+		DeliveryRadius: &radius,
 	}
 
-	store2 := &domain.Store{
-		ID:          uid2,
-		Name:        name2,
-		Description: description2,
-		CityID:      uid2,
-		Address:     address2,
-		CardImg:     cardImg2,
-		Rating:      rating2,
-		TagID:       uid2,
-		OpenAt:      openAt2,
-		ClosedAt:    closeAt2,
+	// у второго магазина зона доставки не задана
+	store2 := &domain.StoreAgg{
+		ID:           uid2,
+		Name:         name2,
+		Description:  description2,
+		CityID:       uid2,
+		Address:      address2,
+		CardImg:      cardImg2,
+		Rating:       rating2,
+		TagsID:       []string{},
+		CategoriesID: []string{},
+		OpenAt:       openAt2,
+		ClosedAt:     closeAt2,
 	}
 
+	selectFrom := `s.lat, s.lon, s.delivery_radius_m, s.delivery_area FROM store s LEFT JOIN store_tag st ON s.id = st.store_id`
+	groupBy := ` GROUP BY s.id, s.name, s.description, s.city_id, s.address, s.card_img, s.rating, s.open_at, s.closed_at, s.lat, s.lon, s.delivery_radius_m, s.delivery_area`
+
 	tests := []testCase{
 		{
 			name:   "без фильтров",
 			filter: &domain.StoreFilter{Limit: 10},
 			mockSetup: func(mock pgxmock.PgxPoolIface) {
-				rows := pgxmock.NewRows([]string{"id", "name", "description", "city_id", "address", "card_img", "rating", "open_at", "closed_at", "tag_id"}).
-					AddRow(store1.ID, store1.Name, store1.Description, store1.CityID, store1.Address, store1.CardImg, store1.Rating, store1.OpenAt, store1.ClosedAt, store1.TagID).
-					AddRow(store2.ID, store2.Name, store2.Description, store2.CityID, store2.Address, store2.CardImg, store2.Rating, store2.OpenAt, store2.ClosedAt, store2.TagID)
-				mock.ExpectQuery(`select s.id, s.name, s.description, s.city_id, s.address, s.card_img, s.rating, s.open_at, s.closed_at, st.tag_id from store s left join store_tag st on st.store_id = s.id order by s.id limit \$1`).
+				rows := pgxmock.NewRows(storeColumns).
+					AddRow(storeRow(store1)...).
+					AddRow(storeRow(store2)...)
+				mock.ExpectQuery(selectFrom + groupBy + ` ORDER BY s.id LIMIT \$1$`).
 					WithArgs(10).
 					WillReturnRows(rows)
 			},
-			expectedRes:   []*domain.Store{store1, store2},
+			expectedRes:   []*domain.StoreAgg{store1, store2},
 			expectedError: nil,
 		},
 		{
 			name:   "с фильтром по id",
 			filter: &domain.StoreFilter{Limit: 5, LastID: uid1},
 			mockSetup: func(mock pgxmock.PgxPoolIface) {
-				rows := pgxmock.NewRows([]string{"id", "name", "description", "city_id", "address", "card_img", "rating", "open_at", "closed_at", "tag_id"}).
-					AddRow(store2.ID, store2.Name, store2.Description, store2.CityID, store2.Address, store2.CardImg, store2.Rating, store2.OpenAt, store2.ClosedAt, store2.TagID)
+				rows := pgxmock.NewRows(storeColumns).AddRow(storeRow(store2)...)
 
-				mock.ExpectQuery(`select s.id, s.name, s.description, s.city_id, s.address, s.card_img, s.rating, s.open_at, s.closed_at, st.tag_id from store s left join store_tag st on st.store_id = s.id where s.id > \$1 order by s.id limit \$2`).
+				mock.ExpectQuery(selectFrom+groupBy+` HAVING s.id > \$1 ORDER BY s.id LIMIT \$2$`).
 					WithArgs(uid1, 5).
 					WillReturnRows(rows)
 			},
-			expectedRes:   []*domain.Store{store2},
+			expectedRes:   []*domain.StoreAgg{store2},
 			expectedError: nil,
 		},
 		{
 			name:   "с фильтром по тегу и городу",
 			filter: &domain.StoreFilter{Limit: 10, TagID: uid1, CityID: uid1},
 			mockSetup: func(mock pgxmock.PgxPoolIface) {
-				rows := pgxmock.NewRows([]string{"id", "name", "description", "city_id", "address", "card_img", "rating", "open_at", "closed_at", "tag_id"}).
-					AddRow(store1.ID, store1.Name, store1.Description, store1.CityID, store1.Address, store1.CardImg, store1.Rating, store1.OpenAt, store1.ClosedAt, store1.TagID)
+				rows := pgxmock.NewRows(storeColumns).AddRow(storeRow(store1)...)
 
-				mock.ExpectQuery(`select s.id, s.name, s.description, s.city_id, s.address, s.card_img, s.rating, s.open_at, s.closed_at, st.tag_id from store s left join store_tag st on st.store_id = s.id where exists \(select 1 from store_tag st2 where st2.store_id = s.id and st2.tag_id = \$1\) and s.city_id = \$2 order by s.id limit \$3`).
+				mock.ExpectQuery(selectFrom+` WHERE EXISTS \(SELECT 1 FROM store_tag st2 WHERE st2.store_id = s.id AND st2.tag_id = \$1\) AND s.city_id = \$2`+
+					groupBy+` ORDER BY s.id LIMIT \$3$`).
 					WithArgs(uid1, uid1, 10).
 					WillReturnRows(rows)
 			},
-			expectedRes:   []*domain.Store{store1},
+			expectedRes:   []*domain.StoreAgg{store1},
 			expectedError: nil,
 		},
 		{
 			name:   "с сортировкой по рейтингу desc",
 			filter: &domain.StoreFilter{Limit: 10, Sorted: "rating", Desc: true},
 			mockSetup: func(mock pgxmock.PgxPoolIface) {
-				rows := pgxmock.NewRows([]string{"id", "name", "description", "city_id", "address", "card_img", "rating", "open_at", "closed_at", "tag_id"}).
-					AddRow(store2.ID, store2.Name, store2.Description, store2.CityID, store2.Address, store2.CardImg, store2.Rating, store2.OpenAt, store2.ClosedAt, store2.TagID).
-					AddRow(store1.ID, store1.Name, store1.Description, store1.CityID, store1.Address, store1.CardImg, store1.Rating, store1.OpenAt, store1.ClosedAt, store1.TagID)
+				rows := pgxmock.NewRows(storeColumns).
+					AddRow(storeRow(store2)...).
+					AddRow(storeRow(store1)...)
 
-				mock.ExpectQuery(`select s.id, s.name, s.description, s.city_id, s.address, s.card_img, s.rating, s.open_at, s.closed_at, st.tag_id from store s left join store_tag st on st.store_id = s.id order by s.rating desc, s.id limit \$1`).
+				mock.ExpectQuery(selectFrom + groupBy + ` ORDER BY s.rating DESC, s.id LIMIT \$1$`).
 					WithArgs(10).
 					WillReturnRows(rows)
 			},
-			expectedRes:   []*domain.Store{store2, store1},
+			expectedRes:   []*domain.StoreAgg{store2, store1},
+			expectedError: nil,
+		},
+		{
+			name:   "по точке без пагинации",
+			filter: &domain.StoreFilter{Limit: 10, LastID: uid1, Point: &geo.Point{Lat: lat, Lon: lon}},
+			mockSetup: func(mock pgxmock.PgxPoolIface) {
+				rows := pgxmock.NewRows(storeColumns).AddRow(storeRow(store1)...)
+
+				// пагинацию и сортировку по расстоянию делает usecase
+				mock.ExpectQuery(selectFrom+` WHERE s.zone_min_lat <= \$1 AND s.zone_max_lat >= \$1 AND s.zone_min_lon <= \$2 AND s.zone_max_lon >= \$2`+
+					groupBy+`$`).
+					WithArgs(lat, lon).
+					WillReturnRows(rows)
+			},
+			expectedRes:   []*domain.StoreAgg{store1},
 			expectedError: nil,
 		},
 		{
 			name:   "пустой результат",
 			filter: &domain.StoreFilter{Limit: 10},
 			mockSetup: func(mock pgxmock.PgxPoolIface) {
-				rows := pgxmock.NewRows([]string{"id", "name", "description", "city_id", "address", "card_img", "rating", "open_at", "closed_at", "tag_id"})
-				mock.ExpectQuery(`select s.id, s.name, s.description, s.city_id, s.address, s.card_img, s.rating, s.open_at, s.closed_at, st.tag_id from store s left join store_tag st on st.store_id = s.id order by s.id limit \$1`).
+				rows := pgxmock.NewRows(storeColumns)
+				mock.ExpectQuery(selectFrom).
 					WithArgs(10).
 					WillReturnRows(rows)
 			},
-			expectedRes:   nil,
-			expectedError: domain.ErrRowsNotFound,
+			expectedRes:   []*domain.StoreAgg{},
+			expectedError: nil,
 		},
 		{
 			name:   "ошибка запроса",
 			filter: &domain.StoreFilter{Limit: 10},
 			mockSetup: func(mock pgxmock.PgxPoolIface) {
-				mock.ExpectQuery(`select s.id, s.name, s.description, s.city_id, s.address, s.card_img, s.rating, s.open_at, s.closed_at, st.tag_id from store s left join store_tag st on st.store_id = s.id order by s.id limit \$1`).
+				mock.ExpectQuery(selectFrom).
 					WithArgs(10).
 					WillReturnError(errors.New("db error"))
 			},
@@ -327,11 +370,11 @@ func TestStoreRepoPostgres_GetStores(t *testing.T) {
 			name:   "ошибка при чтении",
 			filter: &domain.StoreFilter{Limit: 10},
 			mockSetup: func(mock pgxmock.PgxPoolIface) {
-				rows := pgxmock.NewRows([]string{"id", "name", "description", "city_id", "address", "card_img", "rating", "open_at", "closed_at", "tag_id"}).
-					AddRow(store2.ID, store2.Name, store2.Description, store2.CityID, store2.Address, store2.CardImg, store2.Rating, store2.OpenAt, store2.ClosedAt, store2.TagID).
+				rows := pgxmock.NewRows(storeColumns).
+					AddRow(storeRow(store2)...).
 					RowError(0, domain.ErrInternalServer)
 
-				mock.ExpectQuery(`select s.id, s.name, s.description, s.city_id, s.address, s.card_img, s.rating, s.open_at, s.closed_at, st.tag_id from store s left join store_tag st on st.store_id = s.id order by s.id limit \$1`).
+				mock.ExpectQuery(selectFrom).
 					WithArgs(10).
 					WillReturnRows(rows)
 			},
@@ -348,8 +391,7 @@ func TestStoreRepoPostgres_GetStores(t *testing.T) {
 			require.NoError(t, err)
 			defer mockPool.Close()
 
-			log := logger.NewNilLogger()
-			repo := NewStoreRepoPostgres(mockPool, log)
+			repo := NewStoreRepoPostgres(mockPool)
 
 			tt.mockSetup(mockPool)
 
@@ -433,8 +475,7 @@ func TestStoreRepoPostgres_GetCities(t *testing.T) {
 			require.NoError(t, err)
 			defer mockPool.Close()
 
-			log := logger.NewNilLogger()
-			repo := NewStoreRepoPostgres(mockPool, log)
+			repo := NewStoreRepoPostgres(mockPool)
 
 			tt.mockSetup(mockPool)
 
@@ -518,8 +559,7 @@ func TestStoreRepoPostgres_GetTags(t *testing.T) {
 			require.NoError(t, err)
 			defer mockPool.Close()
 
-			log := logger.NewNilLogger()
-			repo := NewStoreRepoPostgres(mockPool, log)
+			repo := NewStoreRepoPostgres(mockPool)
 
 			tt.mockSetup(mockPool)
 
@@ -544,18 +584,20 @@ func TestStoreRepoPostgres_GetStoreReview(t *testing.T) {
 		where r.store_id = \$1
 		order by r.created_at desc
 	`
+	createdAt1 := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	createdAt2 := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
 
 	review1 := &domain.StoreReview{
 		UserName:  "пользователь1",
 		Rating:    5,
 		Comment:   "хороший магазин",
-		CreatedAt: "2024-01-01",
+		CreatedAt: "2024-01-01T12:00:00Z",
 	}
 	review2 := &domain.StoreReview{
 		UserName:  "пользователь2",
 		Rating:    5,
 		Comment:   "хороший магазин",
-		CreatedAt: "2024-01-02",
+		CreatedAt: "2024-01-02T12:00:00Z",
 	}
 
 	tests := []testCase{
@@ -563,8 +605,8 @@ func TestStoreRepoPostgres_GetStoreReview(t *testing.T) {
 			name: "успешный запрос",
 			mockSetup: func(mock pgxmock.PgxPoolIface) {
 				rows := pgxmock.NewRows([]string{"name", "rating", "comment", "created_at"}).
-					AddRow(review1.UserName, review1.Rating, review1.Comment, review1.CreatedAt).
-					AddRow(review2.UserName, review2.Rating, review2.Comment, review2.CreatedAt)
+					AddRow(review1.UserName, review1.Rating, review1.Comment, createdAt1).
+					AddRow(review2.UserName, review2.Rating, review2.Comment, createdAt2)
 
 				mock.ExpectQuery(query).
 					WithArgs(storeID).
@@ -581,8 +623,8 @@ func TestStoreRepoPostgres_GetStoreReview(t *testing.T) {
 					WithArgs(storeID).
 					WillReturnRows(rows)
 			},
-			expectedRes:   nil,
-			expectedError: domain.ErrRowsNotFound,
+			expectedRes:   []*domain.StoreReview{},
+			expectedError: nil,
 		},
 		{
 			name: "ошибка запроса",
@@ -598,7 +640,7 @@ func TestStoreRepoPostgres_GetStoreReview(t *testing.T) {
 			name: "ошибка при чтении строки",
 			mockSetup: func(mock pgxmock.PgxPoolIface) {
 				rows := pgxmock.NewRows([]string{"name", "rating", "comment", "created_at"}).
-					AddRow(review1.UserName, review1.Rating, review1.Comment, review1.CreatedAt).
+					AddRow(review1.UserName, review1.Rating, review1.Comment, createdAt1).
 					RowError(0, domain.ErrInternalServer)
 
 				mock.ExpectQuery(query).
@@ -618,8 +660,7 @@ func TestStoreRepoPostgres_GetStoreReview(t *testing.T) {
 			require.NoError(t, err)
 			defer mockPool.Close()
 
-			log := logger.NewNilLogger()
-			repo := NewStoreRepoPostgres(mockPool, log)
+			repo := NewStoreRepoPostgres(mockPool)
 
 			tt.mockSetup(mockPool)
 
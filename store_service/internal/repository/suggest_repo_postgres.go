@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"apple_backend/pkg/logger"
+	"apple_backend/store_service/internal/domain"
+	"context"
+	_ "embed"
+	"log/slog"
+
+	"github.com/jackc/pgx/v5"
+)
+
+//go:embed sql/suggest/snapshot.sql
+var catalogSnapshot string
+
+//go:embed sql/suggest/version.sql
+var getCatalogVersion string
+
+//go:embed sql/suggest/stores.sql
+var getCatalogStores string
+
+//go:embed sql/suggest/items.sql
+var getCatalogItems string
+
+//go:embed sql/suggest/tags.sql
+var getCatalogTags string
+
+//go:embed sql/suggest/types.sql
+var getCatalogTypes string
+
+type SuggestRepoPostgres struct {
+	db PgxIface
+}
+
+func NewSuggestRepoPostgres(db PgxIface) *SuggestRepoPostgres {
+	return &SuggestRepoPostgres{
+		db: db,
+	}
+}
+
+// GetCatalogVersion возвращает версию каталога; она растет при каждом его
+// изменении
+func (r *SuggestRepoPostgres) GetCatalogVersion(ctx context.Context) (int64, error) {
+	var version int64
+	if err := r.db.QueryRow(ctx, getCatalogVersion).Scan(&version); err != nil {
+		logger.FromContext(ctx).ErrorContext(ctx, "GetCatalogVersion ошибка бд", slog.Any("err", err))
+		return 0, err
+	}
+	return version, nil
+}
+
+// GetCatalog читает снимок каталога для индекса подсказок
+func (r *SuggestRepoPostgres) GetCatalog(ctx context.Context) (*domain.Catalog, error) {
+	log := logger.FromContext(ctx)
+	log.DebugContext(ctx, "GetCatalog начало обработки")
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		log.ErrorContext(ctx, "GetCatalog begin failed", slog.Any("err", err))
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, catalogSnapshot); err != nil {
+		log.ErrorContext(ctx, "GetCatalog snapshot failed", slog.Any("err", err))
+		return nil, err
+	}
+
+	catalog := &domain.Catalog{}
+	if err := tx.QueryRow(ctx, getCatalogVersion).Scan(&catalog.Version); err != nil {
+		log.ErrorContext(ctx, "GetCatalog version failed", slog.Any("err", err))
+		return nil, err
+	}
+
+	catalog.Stores, err = collectCatalog(ctx, tx, "stores", getCatalogStores, func(row pgx.Rows) (*domain.CatalogStore, error) {
+		s := &domain.CatalogStore{}
+		return s, row.Scan(&s.ID, &s.Name, &s.CityID, &s.Rating, &s.Orders)
+	})
+	if err != nil {
+		return nil, err
+	}
+	catalog.Items, err = collectCatalog(ctx, tx, "items", getCatalogItems, func(row pgx.Rows) (*domain.CatalogItem, error) {
+		i := &domain.CatalogItem{}
+		return i, row.Scan(&i.ItemID, &i.Name, &i.StoreID, &i.Ordered)
+	})
+	if err != nil {
+		return nil, err
+	}
+	scanLink := func(row pgx.Rows) (*domain.CatalogLink, error) {
+		l := &domain.CatalogLink{}
+		return l, row.Scan(&l.ID, &l.Name, &l.StoreID)
+	}
+	if catalog.Tags, err = collectCatalog(ctx, tx, "tags", getCatalogTags, scanLink); err != nil {
+		return nil, err
+	}
+	if catalog.Types, err = collectCatalog(ctx, tx, "types", getCatalogTypes, scanLink); err != nil {
+		return nil, err
+	}
+
+	log.DebugContext(ctx, "GetCatalog завершено успешно",
+		slog.Int64("version", catalog.Version),
+		slog.Int("stores_count", len(catalog.Stores)),
+		slog.Int("items_count", len(catalog.Items)))
+	return catalog, nil
+}
+
+func collectCatalog[T any](ctx context.Context, tx pgx.Tx, name, query string, scan func(pgx.Rows) (T, error)) ([]T, error) {
+	log := logger.FromContext(ctx)
+
+	rows, err := tx.Query(ctx, query)
+	if err != nil {
+		log.ErrorContext(ctx, "GetCatalog ошибка бд", slog.Any("err", err), slog.String("part", name))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []T
+	for rows.Next() {
+		v, err := scan(rows)
+		if err != nil {
+			log.ErrorContext(ctx, "GetCatalog ошибка при декодировании данных", slog.Any("err", err), slog.String("part", name))
+			return nil, err
+		}
+		out = append(out, v)
+	}
+	if err := rows.Err(); err != nil {
+		log.ErrorContext(ctx, "GetCatalog ошибка после чтения строк", slog.Any("err", err), slog.String("part", name))
+		return nil, err
+	}
+	return out, nil
+}
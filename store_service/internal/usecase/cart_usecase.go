@@ -1,9 +1,11 @@
 package usecase
 
 import (
+	"apple_backend/pkg/guestcart"
 	"apple_backend/store_service/internal/domain"
 	"context"
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -12,6 +14,9 @@ type CartRepository interface {
 	GetCartItems(ctx context.Context, userID string) ([]*domain.CartItem, error)
 	UpdateCartItems(ctx context.Context, userID string, newItems *domain.CartUpdate) error
 	DeleteCartItems(ctx context.Context, userID string) error
+	GetGuestCartItems(ctx context.Context, cartID string) ([]*domain.CartItem, error)
+	UpdateGuestCartItems(ctx context.Context, cartID string, newItems *domain.CartUpdate) (string, error)
+	DeleteStaleGuestCarts(ctx context.Context, before time.Time) (int64, error)
 }
 
 type CartUsecase struct {
@@ -33,7 +38,7 @@ func (uc *CartUsecase) GetCart(ctx context.Context, userID string) (*domain.Cart
 	return &domain.Cart{Items: items}, nil
 }
 
-func (uc *CartUsecase) UpdateCart(ctx context.Context, userID string, cartUpdate *domain.CartUpdate) error {
+func validateCartUpdate(cartUpdate *domain.CartUpdate) error {
 	for _, item := range cartUpdate.Items {
 		if _, err := uuid.Parse(item.ID); err != nil {
 			return domain.ErrRequestParams // невалидный UUID
@@ -43,6 +48,13 @@ func (uc *CartUsecase) UpdateCart(ctx context.Context, userID string, cartUpdate
 			return domain.ErrInvalidQuantity
 		}
 	}
+	return nil
+}
+
+func (uc *CartUsecase) UpdateCart(ctx context.Context, userID string, cartUpdate *domain.CartUpdate) error {
+	if err := validateCartUpdate(cartUpdate); err != nil {
+		return err
+	}
 
 	err := uc.repo.UpdateCartItems(ctx, userID, cartUpdate)
 	if err != nil {
@@ -55,6 +67,32 @@ func (uc *CartUsecase) DeleteCart(ctx context.Context, userID string) error {
 	return uc.repo.DeleteCartItems(ctx, userID)
 }
 
+// GetGuestCart возвращает гостевую корзину; без cookie корзина пустая
+func (uc *CartUsecase) GetGuestCart(ctx context.Context, cartID string) (*domain.Cart, error) {
+	if cartID == "" {
+		return &domain.Cart{Items: []*domain.CartItem{}}, nil
+	}
+	items, err := uc.repo.GetGuestCartItems(ctx, cartID)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.Cart{Items: items}, nil
+}
+
+// UpdateGuestCart заменяет содержимое гостевой корзины и возвращает ее id,
+// который нужно записать в cookie: корзина могла быть создана заново
+func (uc *CartUsecase) UpdateGuestCart(ctx context.Context, cartID string, cartUpdate *domain.CartUpdate) (string, error) {
+	if err := validateCartUpdate(cartUpdate); err != nil {
+		return "", err
+	}
+	return uc.repo.UpdateGuestCartItems(ctx, cartID, cartUpdate)
+}
+
+// ExpireGuestCarts удаляет гостевые корзины, не менявшиеся дольше guestcart.TTL
+func (uc *CartUsecase) ExpireGuestCarts(ctx context.Context) (int64, error) {
+	return uc.repo.DeleteStaleGuestCarts(ctx, time.Now().Add(-guestcart.TTL))
+}
+
 // TODO: Пофиксить валидацию UUID
 // Сейчас если отправить кривой UUID типа "invalid-uuid", то будет 500 ошибка
 // Надо сделать чтобы возвращалась 400 ошибка
@@ -0,0 +1,177 @@
+package usecase
+
+import (
+	"apple_backend/store_service/internal/domain"
+	"context"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type MenuRepository interface {
+	GetMenu(ctx context.Context, storeID string) ([]*domain.MenuItem, error)
+	GetMenuItem(ctx context.Context, storeID, id string) (*domain.MenuItem, error)
+	ItemInForeignStores(ctx context.Context, itemID, userID string) (bool, error)
+	CreateMenuItem(ctx context.Context, storeID string, in *domain.MenuItemInput) (string, error)
+	UpdateMenuItem(ctx context.Context, storeID, id string, in *domain.MenuItemInput) error
+	ArchiveMenuItem(ctx context.Context, storeID, id string, version time.Time) error
+}
+
+type StoreOwnerRepository interface {
+	CheckStoreOwner(ctx context.Context, storeID, userID string) error
+}
+
+// maxItemPrice — предел numeric(8, 2) в store_item.price
+const maxItemPrice = 1_000_000
+
+// MenuUsecase — изменение меню магазина его владельцем
+type MenuUsecase struct {
+	repo   MenuRepository
+	owners StoreOwnerRepository
+}
+
+func NewMenuUsecase(repo MenuRepository, owners StoreOwnerRepository) *MenuUsecase {
+	return &MenuUsecase{repo: repo, owners: owners}
+}
+
+// GetMenu возвращает меню магазина вместе с архивными позициями
+func (uc *MenuUsecase) GetMenu(ctx context.Context, actor domain.Actor, storeID string) ([]*domain.MenuItem, error) {
+	if err := uc.checkOwner(ctx, actor, storeID); err != nil {
+		return nil, err
+	}
+	return uc.repo.GetMenu(ctx, storeID)
+}
+
+// CreateMenuItem создает товар и добавляет его в меню или, если задан
+// in.ItemID, добавляет в меню существующий товар со своей ценой
+func (uc *MenuUsecase) CreateMenuItem(ctx context.Context, actor domain.Actor, storeID string, in *domain.MenuItemInput) (*domain.MenuItem, error) {
+	if in.Price == nil {
+		return nil, domain.ErrItemPrice
+	}
+	if in.ItemID != nil {
+		// общий товар меняется через PATCH, здесь задается только цена
+		if in.Name != nil || in.Description != nil || in.TypeIDs != nil {
+			return nil, domain.ErrRequestParams
+		}
+		if _, err := uuid.Parse(*in.ItemID); err != nil {
+			return nil, domain.ErrRowsNotFound
+		}
+	} else {
+		if in.Name == nil || in.TypeIDs == nil {
+			return nil, domain.ErrRequestParams
+		}
+		if in.Description == nil {
+			in.Description = new(string)
+		}
+	}
+	if err := validateMenuItemInput(in); err != nil {
+		return nil, err
+	}
+	if err := uc.checkOwner(ctx, actor, storeID); err != nil {
+		return nil, err
+	}
+
+	id, err := uc.repo.CreateMenuItem(ctx, storeID, in)
+	if err != nil {
+		return nil, err
+	}
+	return uc.repo.GetMenuItem(ctx, storeID, id)
+}
+
+// UpdateMenuItem меняет позицию меню. Название, описание и типы принадлежат
+// товару и меняются во всех магазинах, поэтому их может менять только тот,
+// кто владеет всеми этими магазинами; цена своя у каждого магазина.
+func (uc *MenuUsecase) UpdateMenuItem(ctx context.Context, actor domain.Actor, storeID, id string, in *domain.MenuItemInput) (*domain.MenuItem, error) {
+	if in.ItemID != nil || in.UpdatedAt.IsZero() {
+		return nil, domain.ErrRequestParams
+	}
+	if err := validateMenuItemInput(in); err != nil {
+		return nil, err
+	}
+	if err := uc.checkOwner(ctx, actor, storeID); err != nil {
+		return nil, err
+	}
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, domain.ErrRowsNotFound
+	}
+
+	if !actor.Admin && (in.Name != nil || in.Description != nil || in.TypeIDs != nil) {
+		item, err := uc.repo.GetMenuItem(ctx, storeID, id)
+		if err != nil {
+			return nil, err
+		}
+		foreign, err := uc.repo.ItemInForeignStores(ctx, item.ItemID, actor.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if foreign {
+			return nil, domain.ErrForbidden
+		}
+	}
+
+	if err := uc.repo.UpdateMenuItem(ctx, storeID, id, in); err != nil {
+		return nil, err
+	}
+	return uc.repo.GetMenuItem(ctx, storeID, id)
+}
+
+// ArchiveMenuItem снимает позицию с продажи; заказы с ней остаются как были
+func (uc *MenuUsecase) ArchiveMenuItem(ctx context.Context, actor domain.Actor, storeID, id string, version time.Time) error {
+	if version.IsZero() {
+		return domain.ErrRequestParams
+	}
+	if err := uc.checkOwner(ctx, actor, storeID); err != nil {
+		return err
+	}
+	if _, err := uuid.Parse(id); err != nil {
+		return domain.ErrRowsNotFound
+	}
+	return uc.repo.ArchiveMenuItem(ctx, storeID, id, version)
+}
+
+func (uc *MenuUsecase) checkOwner(ctx context.Context, actor domain.Actor, storeID string) error {
+	if _, err := uuid.Parse(storeID); err != nil {
+		return domain.ErrRequestParams
+	}
+	if actor.Admin {
+		return nil
+	}
+	return uc.owners.CheckStoreOwner(ctx, storeID, actor.UserID)
+}
+
+// validateMenuItemInput повторяет ограничения таблиц item и store_item
+func validateMenuItemInput(in *domain.MenuItemInput) error {
+	if in.Name != nil {
+		*in.Name = strings.TrimSpace(*in.Name)
+		if !runeLenBetween(*in.Name, 1, 50) {
+			return domain.ErrItemName
+		}
+	}
+	if in.Description != nil {
+		*in.Description = strings.TrimSpace(*in.Description)
+		if !runeLenBetween(*in.Description, 0, 200) {
+			return domain.ErrItemDescription
+		}
+	}
+	if in.Price != nil {
+		// цена хранится с копейками, лишние знаки округляются
+		price := math.Round(*in.Price*100) / 100
+		if math.IsNaN(price) || price <= 0 || price >= maxItemPrice {
+			return domain.ErrItemPrice
+		}
+		in.Price = &price
+	}
+	if in.TypeIDs != nil {
+		// товар без типа не попадает в публичное меню
+		if len(in.TypeIDs) == 0 {
+			return domain.ErrTypeNotFound
+		}
+		var err error
+		if in.TypeIDs, err = uniqueIDs(in.TypeIDs, domain.ErrTypeNotFound); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	domain "apple_backend/store_service/internal/domain"
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -49,6 +50,21 @@ func (mr *MockCartRepositoryMockRecorder) DeleteCartItems(ctx, userID interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCartItems", reflect.TypeOf((*MockCartRepository)(nil).DeleteCartItems), ctx, userID)
 }
 
+// DeleteStaleGuestCarts mocks base method.
+func (m *MockCartRepository) DeleteStaleGuestCarts(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteStaleGuestCarts", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteStaleGuestCarts indicates an expected call of DeleteStaleGuestCarts.
+func (mr *MockCartRepositoryMockRecorder) DeleteStaleGuestCarts(ctx, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteStaleGuestCarts", reflect.TypeOf((*MockCartRepository)(nil).DeleteStaleGuestCarts), ctx, before)
+}
+
 // GetCartItems mocks base method.
 func (m *MockCartRepository) GetCartItems(ctx context.Context, userID string) ([]*domain.CartItem, error) {
 	m.ctrl.T.Helper()
@@ -64,6 +80,21 @@ func (mr *MockCartRepositoryMockRecorder) GetCartItems(ctx, userID interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCartItems", reflect.TypeOf((*MockCartRepository)(nil).GetCartItems), ctx, userID)
 }
 
+// GetGuestCartItems mocks base method.
+func (m *MockCartRepository) GetGuestCartItems(ctx context.Context, cartID string) ([]*domain.CartItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGuestCartItems", ctx, cartID)
+	ret0, _ := ret[0].([]*domain.CartItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGuestCartItems indicates an expected call of GetGuestCartItems.
+func (mr *MockCartRepositoryMockRecorder) GetGuestCartItems(ctx, cartID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGuestCartItems", reflect.TypeOf((*MockCartRepository)(nil).GetGuestCartItems), ctx, cartID)
+}
+
 // UpdateCartItems mocks base method.
 func (m *MockCartRepository) UpdateCartItems(ctx context.Context, userID string, newItems *domain.CartUpdate) error {
 	m.ctrl.T.Helper()
@@ -77,3 +108,18 @@ func (mr *MockCartRepositoryMockRecorder) UpdateCartItems(ctx, userID, newItems
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCartItems", reflect.TypeOf((*MockCartRepository)(nil).UpdateCartItems), ctx, userID, newItems)
 }
+
+// UpdateGuestCartItems mocks base method.
+func (m *MockCartRepository) UpdateGuestCartItems(ctx context.Context, cartID string, newItems *domain.CartUpdate) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateGuestCartItems", ctx, cartID, newItems)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateGuestCartItems indicates an expected call of UpdateGuestCartItems.
+func (mr *MockCartRepositoryMockRecorder) UpdateGuestCartItems(ctx, cartID, newItems interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateGuestCartItems", reflect.TypeOf((*MockCartRepository)(nil).UpdateGuestCartItems), ctx, cartID, newItems)
+}
@@ -81,18 +81,33 @@ func (mr *MockOrderRepositoryMockRecorder) GetOrderUserID(ctx, orderID interface
 }
 
 // GetOrdersUser mocks base method.
-func (m *MockOrderRepository) GetOrdersUser(ctx context.Context, userID string) ([]*domain.Order, error) {
+func (m *MockOrderRepository) GetOrdersUser(ctx context.Context, filter *domain.OrderFilter) ([]*domain.Order, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetOrdersUser", ctx, userID)
+	ret := m.ctrl.Call(m, "GetOrdersUser", ctx, filter)
 	ret0, _ := ret[0].([]*domain.Order)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetOrdersUser indicates an expected call of GetOrdersUser.
-func (mr *MockOrderRepositoryMockRecorder) GetOrdersUser(ctx, userID interface{}) *gomock.Call {
+func (mr *MockOrderRepositoryMockRecorder) GetOrdersUser(ctx, filter interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrdersUser", reflect.TypeOf((*MockOrderRepository)(nil).GetOrdersUser), ctx, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrdersUser", reflect.TypeOf((*MockOrderRepository)(nil).GetOrdersUser), ctx, filter)
+}
+
+// GetStoreOrders mocks base method.
+func (m *MockOrderRepository) GetStoreOrders(ctx context.Context, filter *domain.OrderFilter) ([]*domain.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStoreOrders", ctx, filter)
+	ret0, _ := ret[0].([]*domain.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStoreOrders indicates an expected call of GetStoreOrders.
+func (mr *MockOrderRepositoryMockRecorder) GetStoreOrders(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStoreOrders", reflect.TypeOf((*MockOrderRepository)(nil).GetStoreOrders), ctx, filter)
 }
 
 // UpdateOrderStatus mocks base method.
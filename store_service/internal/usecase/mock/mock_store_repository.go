@@ -35,20 +35,6 @@ func (m *MockStoreRepository) EXPECT() *MockStoreRepositoryMockRecorder {
 	return m.recorder
 }
 
-// CreateStore mocks base method.
-func (m *MockStoreRepository) CreateStore(ctx context.Context, store *domain.Store) error {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateStore", ctx, store)
-	ret0, _ := ret[0].(error)
-	return ret0
-}
-
-// CreateStore indicates an expected call of CreateStore.
-func (mr *MockStoreRepositoryMockRecorder) CreateStore(ctx, store interface{}) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateStore", reflect.TypeOf((*MockStoreRepository)(nil).CreateStore), ctx, store)
-}
-
 // GetCities mocks base method.
 func (m *MockStoreRepository) GetCities(ctx context.Context) ([]*domain.City, error) {
 	m.ctrl.T.Helper()
@@ -65,10 +51,10 @@ func (mr *MockStoreRepositoryMockRecorder) GetCities(ctx interface{}) *gomock.Ca
 }
 
 // GetStore mocks base method.
-func (m *MockStoreRepository) GetStore(ctx context.Context, id string) ([]*domain.Store, error) {
+func (m *MockStoreRepository) GetStore(ctx context.Context, id string) (*domain.StoreAgg, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetStore", ctx, id)
-	ret0, _ := ret[0].([]*domain.Store)
+	ret0, _ := ret[0].(*domain.StoreAgg)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -95,10 +81,10 @@ func (mr *MockStoreRepositoryMockRecorder) GetStoreReview(ctx, id interface{}) *
 }
 
 // GetStores mocks base method.
-func (m *MockStoreRepository) GetStores(ctx context.Context, filter *domain.StoreFilter) ([]*domain.Store, error) {
+func (m *MockStoreRepository) GetStores(ctx context.Context, filter *domain.StoreFilter) ([]*domain.StoreAgg, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetStores", ctx, filter)
-	ret0, _ := ret[0].([]*domain.Store)
+	ret0, _ := ret[0].([]*domain.StoreAgg)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
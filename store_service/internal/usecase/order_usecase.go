@@ -5,6 +5,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+
+	"github.com/google/uuid"
 )
 
 type OrderRepository interface {
@@ -13,14 +15,16 @@ type OrderRepository interface {
 	UpdateOrderStatus(ctx context.Context, orderID, status string) error
 	GetOrder(ctx context.Context, orderID string) (*domain.OrderInfo, error)
 	GetOrdersUser(ctx context.Context, filter *domain.OrderFilter) ([]*domain.Order, error)
+	GetStoreOrders(ctx context.Context, filter *domain.OrderFilter) ([]*domain.Order, error)
 }
 
 type OrderUsecase struct {
-	repo OrderRepository
+	repo   OrderRepository
+	owners StoreOwnerRepository
 }
 
-func NewOrderUsecase(repo OrderRepository) *OrderUsecase {
-	return &OrderUsecase{repo: repo}
+func NewOrderUsecase(repo OrderRepository, owners StoreOwnerRepository) *OrderUsecase {
+	return &OrderUsecase{repo: repo, owners: owners}
 }
 
 func (uc *OrderUsecase) CreateOrder(ctx context.Context, userID string) (*domain.OrderInfo, error) {
@@ -112,6 +116,31 @@ func (uc *OrderUsecase) GetOrdersUser(ctx context.Context, filter *domain.OrderF
 	return orders, nil
 }
 
+// GetStoreOrders возвращает заказы покупателей с позициями магазина. Список
+// видит только владелец магазина (и admin)
+func (uc *OrderUsecase) GetStoreOrders(ctx context.Context, actor domain.Actor, filter *domain.OrderFilter) ([]*domain.Order, error) {
+	if filter == nil || filter.Limit <= 0 || filter.Limit > 100 {
+		return nil, domain.ErrRequestParams
+	}
+	if _, err := uuid.Parse(filter.StoreID); err != nil {
+		return nil, domain.ErrRequestParams
+	}
+	if !actor.Admin {
+		if err := uc.owners.CheckStoreOwner(ctx, filter.StoreID, actor.UserID); err != nil {
+			if errors.Is(err, domain.ErrRowsNotFound) || errors.Is(err, domain.ErrForbidden) {
+				return nil, err
+			}
+			return nil, domain.ErrInternalServer
+		}
+	}
+
+	orders, err := uc.repo.GetStoreOrders(ctx, filter)
+	if err != nil {
+		return nil, domain.ErrInternalServer
+	}
+	return orders, nil
+}
+
 // TODO: Пофиксить валидацию UUID
 // Сейчас если отправить кривой UUID типа "invalid-uuid", то будет 500 ошибка
 // Надо сделать чтобы возвращалась 400 ошибка
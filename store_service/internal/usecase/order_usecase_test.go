@@ -4,7 +4,9 @@ import (
 	"apple_backend/store_service/internal/domain"
 	"apple_backend/store_service/internal/usecase/mock"
 	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
@@ -12,8 +14,8 @@ import (
 
 func TestOrderUsecase_GetOrdersUser(t *testing.T) {
 	type args struct {
-		ctx context.Context
-		id  string
+		ctx    context.Context
+		filter *domain.OrderFilter
 	}
 
 	type testCase struct {
@@ -25,23 +27,24 @@ func TestOrderUsecase_GetOrdersUser(t *testing.T) {
 	}
 
 	uid := "00000000-0000-0000-0000-000000000001"
+	filter := &domain.OrderFilter{UserID: uid, Limit: 10}
 	order := &domain.Order{
 		ID:        uid,
 		Status:    "on the way",
 		Total:     105.5,
-		CreatedAt: "2024-01-01",
+		CreatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
 	}
 
 	tests := []testCase{
 		{
 			name: "успешный вызов",
 			input: args{
-				ctx: context.Background(),
-				id:  uid,
+				ctx:    context.Background(),
+				filter: filter,
 			},
 			mockSetup: func(repo *mock.MockOrderRepository) {
 				repo.EXPECT().
-					GetOrdersUser(context.Background(), uid).
+					GetOrdersUser(context.Background(), filter).
 					Return([]*domain.Order{order}, nil)
 			},
 			expectedResult: []*domain.Order{order},
@@ -50,12 +53,12 @@ func TestOrderUsecase_GetOrdersUser(t *testing.T) {
 		{
 			name: "ошбика выполнения",
 			input: args{
-				ctx: context.Background(),
-				id:  uid,
+				ctx:    context.Background(),
+				filter: filter,
 			},
 			mockSetup: func(repo *mock.MockOrderRepository) {
 				repo.EXPECT().
-					GetOrdersUser(context.Background(), uid).
+					GetOrdersUser(context.Background(), filter).
 					Return(nil, domain.ErrInternalServer)
 			},
 			expectedResult: nil,
@@ -73,9 +76,9 @@ func TestOrderUsecase_GetOrdersUser(t *testing.T) {
 			mockRepo := mock.NewMockOrderRepository(ctrl)
 			tt.mockSetup(mockRepo)
 
-			uc := NewOrderUsecase(mockRepo)
+			uc := NewOrderUsecase(mockRepo, nil)
 
-			orders, err := uc.GetOrdersUser(tt.input.ctx, tt.input.id)
+			orders, err := uc.GetOrdersUser(tt.input.ctx, tt.input.filter)
 
 			require.Equal(t, tt.expectedError, err)
 			require.Equal(t, tt.expectedResult, orders)
@@ -83,6 +86,110 @@ func TestOrderUsecase_GetOrdersUser(t *testing.T) {
 	}
 }
 
+func TestOrderUsecase_GetStoreOrders(t *testing.T) {
+	type testCase struct {
+		name           string
+		actor          domain.Actor
+		filter         *domain.OrderFilter
+		mockSetup      func(repo *mock.MockOrderRepository, owners *mock.MockStoreOwnerRepository)
+		expectedResult []*domain.Order
+		expectedError  error
+	}
+
+	storeID := "00000000-0000-0000-0000-000000000010"
+	partner := domain.Actor{UserID: "00000000-0000-0000-0000-000000000020"}
+	filter := &domain.OrderFilter{StoreID: storeID, Limit: 10}
+	// заказ оформил покупатель, а не владелец магазина
+	order := &domain.Order{
+		ID:        "00000000-0000-0000-0000-000000000030",
+		Status:    "paid",
+		Total:     99.9,
+		CreatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	tests := []testCase{
+		{
+			name:   "владелец магазина",
+			actor:  partner,
+			filter: filter,
+			mockSetup: func(repo *mock.MockOrderRepository, owners *mock.MockStoreOwnerRepository) {
+				owners.EXPECT().CheckStoreOwner(gomock.Any(), storeID, partner.UserID).Return(nil)
+				repo.EXPECT().GetStoreOrders(gomock.Any(), filter).Return([]*domain.Order{order}, nil)
+			},
+			expectedResult: []*domain.Order{order},
+		},
+		{
+			name:   "чужой магазин",
+			actor:  partner,
+			filter: filter,
+			mockSetup: func(repo *mock.MockOrderRepository, owners *mock.MockStoreOwnerRepository) {
+				owners.EXPECT().CheckStoreOwner(gomock.Any(), storeID, partner.UserID).Return(domain.ErrForbidden)
+			},
+			expectedError: domain.ErrForbidden,
+		},
+		{
+			name:   "магазин не найден",
+			actor:  partner,
+			filter: filter,
+			mockSetup: func(repo *mock.MockOrderRepository, owners *mock.MockStoreOwnerRepository) {
+				owners.EXPECT().CheckStoreOwner(gomock.Any(), storeID, partner.UserID).Return(domain.ErrRowsNotFound)
+			},
+			expectedError: domain.ErrRowsNotFound,
+		},
+		{
+			name:   "admin без проверки владельца",
+			actor:  domain.Actor{UserID: "admin", Admin: true},
+			filter: filter,
+			mockSetup: func(repo *mock.MockOrderRepository, owners *mock.MockStoreOwnerRepository) {
+				repo.EXPECT().GetStoreOrders(gomock.Any(), filter).Return([]*domain.Order{}, nil)
+			},
+			expectedResult: []*domain.Order{},
+		},
+		{
+			name:          "неверный id магазина",
+			actor:         partner,
+			filter:        &domain.OrderFilter{StoreID: "store", Limit: 10},
+			mockSetup:     func(repo *mock.MockOrderRepository, owners *mock.MockStoreOwnerRepository) {},
+			expectedError: domain.ErrRequestParams,
+		},
+		{
+			name:          "неверный limit",
+			actor:         partner,
+			filter:        &domain.OrderFilter{StoreID: storeID, Limit: 0},
+			mockSetup:     func(repo *mock.MockOrderRepository, owners *mock.MockStoreOwnerRepository) {},
+			expectedError: domain.ErrRequestParams,
+		},
+		{
+			name:   "ошибка бд",
+			actor:  partner,
+			filter: filter,
+			mockSetup: func(repo *mock.MockOrderRepository, owners *mock.MockStoreOwnerRepository) {
+				owners.EXPECT().CheckStoreOwner(gomock.Any(), storeID, partner.UserID).Return(nil)
+				repo.EXPECT().GetStoreOrders(gomock.Any(), filter).Return(nil, errors.New("db error"))
+			},
+			expectedError: domain.ErrInternalServer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock.NewMockOrderRepository(ctrl)
+			mockOwners := mock.NewMockStoreOwnerRepository(ctrl)
+			tt.mockSetup(mockRepo, mockOwners)
+
+			uc := NewOrderUsecase(mockRepo, mockOwners)
+			res, err := uc.GetStoreOrders(context.Background(), tt.actor, tt.filter)
+
+			require.Equal(t, tt.expectedError, err)
+			require.Equal(t, tt.expectedResult, res)
+		})
+	}
+}
 func TestOrderUsecase_GetOrder(t *testing.T) {
 	type args struct {
 		ctx     context.Context
@@ -113,7 +220,7 @@ func TestOrderUsecase_GetOrder(t *testing.T) {
 		Items:     []*domain.OrderItemInfo{item},
 		Status:    "on the way",
 		Total:     105.5,
-		CreatedAt: "2024-01-01",
+		CreatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
 	}
 
 	tests := []testCase{
@@ -197,7 +304,7 @@ func TestOrderUsecase_GetOrder(t *testing.T) {
 			mockRepo := mock.NewMockOrderRepository(ctrl)
 			tt.mockSetup(mockRepo, tt.input.orderID, tt.input.userID)
 
-			uc := NewOrderUsecase(mockRepo)
+			uc := NewOrderUsecase(mockRepo, nil)
 
 			orders, err := uc.GetOrder(tt.input.ctx, tt.input.orderID, tt.input.userID)
 
@@ -236,7 +343,7 @@ func TestOrderUsecase_CreateOrder(t *testing.T) {
 		Items:     []*domain.OrderItemInfo{item},
 		Status:    "on the way",
 		Total:     105.5,
-		CreatedAt: "2024-01-01",
+		CreatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
 	}
 
 	tests := []testCase{
@@ -300,7 +407,7 @@ func TestOrderUsecase_CreateOrder(t *testing.T) {
 			mockRepo := mock.NewMockOrderRepository(ctrl)
 			tt.mockSetup(mockRepo)
 
-			uc := NewOrderUsecase(mockRepo)
+			uc := NewOrderUsecase(mockRepo, nil)
 
 			orders, err := uc.CreateOrder(tt.input.ctx, tt.input.id)
 
@@ -329,130 +436,103 @@ func TestOrderUsecase_UpdateOrderStatus(t *testing.T) {
 	uid2 := "00000000-0000-0000-0000-000000000002"
 	pending := "pending"
 	paid := "paid"
-	delivered := "delivered"
 	cancelled := "cancelled"
-	onTheWay := "on the way"
+
+	// владелец заказа, который находится в статусе status
+	orderIn := func(status string) func(repo *mock.MockOrderRepository, orderID, userID string) {
+		return func(repo *mock.MockOrderRepository, orderID, userID string) {
+			repo.EXPECT().
+				GetOrderUserID(context.Background(), orderID).
+				Return(userID, nil)
+
+			repo.EXPECT().
+				GetOrder(context.Background(), orderID).
+				Return(&domain.OrderInfo{ID: orderID, Status: status}, nil)
+		}
+	}
 
 	tests := []testCase{
 		{
-			name: "успешный вызов pending",
+			name: "успешная отмена pending",
 			input: args{
 				ctx:     context.Background(),
 				orderID: uid,
 				userID:  uid2,
-				status:  pending,
+				status:  cancelled,
 			},
 			mockSetup: func(repo *mock.MockOrderRepository, orderID, userID string) {
-				repo.EXPECT().
-					GetOrderUserID(context.Background(), orderID).
-					Return(userID, nil)
+				orderIn(pending)(repo, orderID, userID)
 
 				repo.EXPECT().
-					UpdateOrderStatus(context.Background(), orderID, pending).
+					UpdateOrderStatus(context.Background(), orderID, cancelled).
 					Return(nil)
 			},
 			expectedError: nil,
 		},
 		{
-			name: "успешный вызов paid",
+			name: "отмена оплаченного заказа",
 			input: args{
 				ctx:     context.Background(),
 				orderID: uid,
 				userID:  uid2,
-				status:  paid,
-			},
-			mockSetup: func(repo *mock.MockOrderRepository, orderID, userID string) {
-				repo.EXPECT().
-					GetOrderUserID(context.Background(), orderID).
-					Return(userID, nil)
-
-				repo.EXPECT().
-					UpdateOrderStatus(context.Background(), orderID, paid).
-					Return(nil)
+				status:  cancelled,
 			},
-			expectedError: nil,
+			mockSetup:     orderIn(paid),
+			expectedError: errors.New("cannot cancel order in status 'paid'"),
 		},
 		{
-			name: "успешный вызов delivered",
+			name: "пользователь не меняет статус кроме отмены",
 			input: args{
 				ctx:     context.Background(),
 				orderID: uid,
 				userID:  uid2,
-				status:  delivered,
-			},
-			mockSetup: func(repo *mock.MockOrderRepository, orderID, userID string) {
-				repo.EXPECT().
-					GetOrderUserID(context.Background(), orderID).
-					Return(userID, nil)
-
-				repo.EXPECT().
-					UpdateOrderStatus(context.Background(), orderID, delivered).
-					Return(nil)
+				status:  paid,
 			},
-			expectedError: nil,
+			mockSetup:     orderIn(pending),
+			expectedError: domain.ErrForbidden,
 		},
 		{
-			name: "успешный вызов cancelled",
+			name: "некорректный статус",
 			input: args{
 				ctx:     context.Background(),
 				orderID: uid,
 				userID:  uid2,
-				status:  cancelled,
-			},
-			mockSetup: func(repo *mock.MockOrderRepository, orderID, userID string) {
-				repo.EXPECT().
-					GetOrderUserID(context.Background(), orderID).
-					Return(userID, nil)
-
-				repo.EXPECT().
-					UpdateOrderStatus(context.Background(), orderID, cancelled).
-					Return(nil)
+				status:  "on the way",
 			},
-			expectedError: nil,
+			mockSetup:     func(repo *mock.MockOrderRepository, orderID, userID string) {},
+			expectedError: domain.ErrRequestParams,
 		},
-
 		{
-			name: "успешный вызов onTheWay",
+			name: "ошибка получения ид",
 			input: args{
 				ctx:     context.Background(),
 				orderID: uid,
 				userID:  uid2,
-				status:  onTheWay,
+				status:  cancelled,
 			},
 			mockSetup: func(repo *mock.MockOrderRepository, orderID, userID string) {
 				repo.EXPECT().
 					GetOrderUserID(context.Background(), orderID).
-					Return(userID, nil)
-
-				repo.EXPECT().
-					UpdateOrderStatus(context.Background(), orderID, onTheWay).
-					Return(nil)
-			},
-			expectedError: nil,
-		},
-		{
-			name: "некорректный статус",
-			input: args{
-				ctx:     context.Background(),
-				orderID: uid,
-				userID:  uid2,
-				status:  "оплачено",
+					Return("", domain.ErrInternalServer)
 			},
-			mockSetup:     func(repo *mock.MockOrderRepository, orderID, userID string) {},
-			expectedError: domain.ErrRequestParams,
+			expectedError: domain.ErrInternalServer,
 		},
 		{
-			name: "ошибка получения ид",
+			name: "ошибка получения заказа",
 			input: args{
 				ctx:     context.Background(),
 				orderID: uid,
 				userID:  uid2,
-				status:  pending,
+				status:  cancelled,
 			},
 			mockSetup: func(repo *mock.MockOrderRepository, orderID, userID string) {
 				repo.EXPECT().
 					GetOrderUserID(context.Background(), orderID).
-					Return("", domain.ErrInternalServer)
+					Return(userID, nil)
+
+				repo.EXPECT().
+					GetOrder(context.Background(), orderID).
+					Return(nil, errors.New("db"))
 			},
 			expectedError: domain.ErrInternalServer,
 		},
@@ -462,15 +542,13 @@ func TestOrderUsecase_UpdateOrderStatus(t *testing.T) {
 				ctx:     context.Background(),
 				orderID: uid,
 				userID:  uid2,
-				status:  pending,
+				status:  cancelled,
 			},
 			mockSetup: func(repo *mock.MockOrderRepository, orderID, userID string) {
-				repo.EXPECT().
-					GetOrderUserID(context.Background(), orderID).
-					Return(userID, nil)
+				orderIn(pending)(repo, orderID, userID)
 
 				repo.EXPECT().
-					UpdateOrderStatus(context.Background(), orderID, pending).
+					UpdateOrderStatus(context.Background(), orderID, cancelled).
 					Return(domain.ErrInternalServer)
 			},
 			expectedError: domain.ErrInternalServer,
@@ -481,7 +559,7 @@ func TestOrderUsecase_UpdateOrderStatus(t *testing.T) {
 				ctx:     context.Background(),
 				orderID: uid,
 				userID:  uid2,
-				status:  pending,
+				status:  cancelled,
 			},
 			mockSetup: func(repo *mock.MockOrderRepository, orderID, userID string) {
 				repo.EXPECT().
@@ -502,7 +580,7 @@ func TestOrderUsecase_UpdateOrderStatus(t *testing.T) {
 			mockRepo := mock.NewMockOrderRepository(ctrl)
 			tt.mockSetup(mockRepo, tt.input.orderID, tt.input.userID)
 
-			uc := NewOrderUsecase(mockRepo)
+			uc := NewOrderUsecase(mockRepo, nil)
 
 			err := uc.UpdateOrderStatus(tt.input.ctx, tt.input.orderID, tt.input.userID, tt.input.status)
 
@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"apple_backend/pkg/translit"
+	"apple_backend/store_service/internal/domain"
+	"context"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+type SearchRepository interface {
+	Search(ctx context.Context, variants []string, cityID string, limit int) ([]*domain.SearchHit, error)
+}
+
+const (
+	// searchRowsLimit — сколько строк выдачи читать из бд до группировки
+	searchRowsLimit = 500
+	// searchItemsPerStore — сколько найденных позиций показывать в магазине
+	searchItemsPerStore = 5
+	// DefaultSearchLimit и MaxSearchLimit ограничивают число магазинов
+	DefaultSearchLimit = 20
+	MaxSearchLimit     = 50
+)
+
+type SearchUsecase struct {
+	repo SearchRepository
+}
+
+func NewSearchUsecase(repo SearchRepository) *SearchUsecase {
+	return &SearchUsecase{repo: repo}
+}
+
+// Search ищет по запросу и его транслитерации и группирует найденное по
+// магазинам. Магазин ранжируется по лучшему совпадению: с ним самим или с
+// одной из его позиций.
+func (uc *SearchUsecase) Search(ctx context.Context, filter *domain.SearchFilter) ([]*domain.SearchStore, error) {
+	query := strings.TrimSpace(filter.Query)
+	if n := utf8.RuneCountInString(query); n < 2 || n > 100 {
+		return nil, domain.ErrSearchQuery
+	}
+	if filter.CityID != "" {
+		if _, err := uuid.Parse(filter.CityID); err != nil {
+			return nil, domain.ErrRequestParams
+		}
+	}
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = DefaultSearchLimit
+	}
+	if limit > MaxSearchLimit {
+		return nil, domain.ErrRequestParams
+	}
+
+	hits, err := uc.repo.Search(ctx, translit.Variants(query), filter.CityID, searchRowsLimit)
+	if err != nil {
+		return nil, err
+	}
+	return groupSearchHits(hits, limit), nil
+}
+
+// groupSearchHits собирает строки выдачи в магазины, сортирует магазины и
+// их позиции по рангу и обрезает по limit и searchItemsPerStore
+func groupSearchHits(hits []*domain.SearchHit, limit int) []*domain.SearchStore {
+	stores := make(map[string]*domain.SearchStore)
+	score := make(map[string]float64)
+	order := []string{}
+
+	for _, hit := range hits {
+		store, ok := stores[hit.Store.ID]
+		if !ok {
+			store = hit.Store
+			store.Items = []*domain.SearchItem{}
+			stores[store.ID] = store
+			score[store.ID] = store.Rank
+			order = append(order, store.ID)
+		}
+		if hit.Item != nil {
+			store.Items = append(store.Items, hit.Item)
+			score[store.ID] = max(score[store.ID], hit.Item.Rank)
+		}
+	}
+
+	// при равном ранге выше магазин с большим рейтингом
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := order[i], order[j]
+		if score[a] != score[b] {
+			return score[a] > score[b]
+		}
+		return stores[a].Rating > stores[b].Rating
+	})
+	if len(order) > limit {
+		order = order[:limit]
+	}
+
+	result := make([]*domain.SearchStore, 0, len(order))
+	for _, id := range order {
+		store := stores[id]
+		sort.SliceStable(store.Items, func(i, j int) bool {
+			return store.Items[i].Rank > store.Items[j].Rank
+		})
+		if len(store.Items) > searchItemsPerStore {
+			store.Items = store.Items[:searchItemsPerStore]
+		}
+		result = append(result, store)
+	}
+	return result
+}
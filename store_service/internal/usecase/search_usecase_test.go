@@ -0,0 +1,191 @@
+package usecase
+
+import (
+	"apple_backend/store_service/internal/domain"
+	"apple_backend/store_service/internal/usecase/mock"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchUsecase_Search(t *testing.T) {
+	type testCase struct {
+		name           string
+		filter         *domain.SearchFilter
+		mockSetup      func(repo *mock.MockSearchRepository)
+		expectedResult []*domain.SearchStore
+		expectedError  error
+	}
+
+	cityID := "00000000-0000-0000-0000-000000000001"
+
+	tests := []testCase{
+		{
+			name:   "полнотекстовое совпадение выше опечатки",
+			filter: &domain.SearchFilter{Query: "  Пицца ", CityID: cityID},
+			mockSetup: func(repo *mock.MockSearchRepository) {
+				repo.EXPECT().
+					Search(gomock.Any(), []string{"пицца", "pizza"}, cityID, searchRowsLimit).
+					Return([]*domain.SearchHit{
+						// магазин найден по названию с опечаткой
+						{Store: &domain.SearchStore{ID: "s1", Name: "Пиццерия", Rating: 5, Rank: 0.5}},
+						// в магазине нашлась позиция со словом из запроса
+						{
+							Store: &domain.SearchStore{ID: "s2", Name: "Кафе", Rating: 3},
+							Item:  &domain.SearchItem{ID: "i1", Name: "Пицца Маргарита", Rank: 1.06},
+						},
+					}, nil)
+			},
+			expectedResult: []*domain.SearchStore{
+				{
+					ID: "s2", Name: "Кафе", Rating: 3,
+					Items: []*domain.SearchItem{{ID: "i1", Name: "Пицца Маргарита", Rank: 1.06}},
+				},
+				{ID: "s1", Name: "Пиццерия", Rating: 5, Rank: 0.5, Items: []*domain.SearchItem{}},
+			},
+			expectedError: nil,
+		},
+		{
+			name:   "латиница ищется вместе с кириллической транслитерацией",
+			filter: &domain.SearchFilter{Query: "pizza"},
+			mockSetup: func(repo *mock.MockSearchRepository) {
+				repo.EXPECT().
+					Search(gomock.Any(), []string{"pizza", "пицца"}, "", searchRowsLimit).
+					Return([]*domain.SearchHit{
+						{
+							Store: &domain.SearchStore{ID: "s1", Name: "Кафе", Rating: 4},
+							Item:  &domain.SearchItem{ID: "i1", Name: "Пицца", Rank: 0.45},
+						},
+						{
+							Store: &domain.SearchStore{ID: "s1", Name: "Кафе", Rating: 4},
+							Item:  &domain.SearchItem{ID: "i2", Name: "Пицца с грибами", Rank: 0.8},
+						},
+					}, nil)
+			},
+			expectedResult: []*domain.SearchStore{
+				{
+					ID: "s1", Name: "Кафе", Rating: 4,
+					Items: []*domain.SearchItem{
+						{ID: "i2", Name: "Пицца с грибами", Rank: 0.8},
+						{ID: "i1", Name: "Пицца", Rank: 0.45},
+					},
+				},
+			},
+			expectedError: nil,
+		},
+		{
+			name:   "при равном ранге выше магазин с большим рейтингом",
+			filter: &domain.SearchFilter{Query: "суши"},
+			mockSetup: func(repo *mock.MockSearchRepository) {
+				repo.EXPECT().
+					Search(gomock.Any(), []string{"суши", "sushi"}, "", searchRowsLimit).
+					Return([]*domain.SearchHit{
+						{Store: &domain.SearchStore{ID: "s1", Rating: 3, Rank: 1.1}},
+						{Store: &domain.SearchStore{ID: "s2", Rating: 4.5, Rank: 1.1}},
+					}, nil)
+			},
+			expectedResult: []*domain.SearchStore{
+				{ID: "s2", Rating: 4.5, Rank: 1.1, Items: []*domain.SearchItem{}},
+				{ID: "s1", Rating: 3, Rank: 1.1, Items: []*domain.SearchItem{}},
+			},
+			expectedError: nil,
+		},
+		{
+			name:   "ничего не найдено",
+			filter: &domain.SearchFilter{Query: "абвгд"},
+			mockSetup: func(repo *mock.MockSearchRepository) {
+				repo.EXPECT().
+					Search(gomock.Any(), gomock.Any(), "", searchRowsLimit).
+					Return([]*domain.SearchHit{}, nil)
+			},
+			expectedResult: []*domain.SearchStore{},
+			expectedError:  nil,
+		},
+		{
+			name:   "ошибка репозитория",
+			filter: &domain.SearchFilter{Query: "пицца"},
+			mockSetup: func(repo *mock.MockSearchRepository) {
+				repo.EXPECT().
+					Search(gomock.Any(), gomock.Any(), "", searchRowsLimit).
+					Return(nil, errors.New("db error"))
+			},
+			expectedResult: nil,
+			expectedError:  errors.New("db error"),
+		},
+		{
+			name:           "слишком короткий запрос",
+			filter:         &domain.SearchFilter{Query: " п "},
+			mockSetup:      func(repo *mock.MockSearchRepository) {},
+			expectedResult: nil,
+			expectedError:  domain.ErrSearchQuery,
+		},
+		{
+			name:           "некорректный город",
+			filter:         &domain.SearchFilter{Query: "пицца", CityID: "moscow"},
+			mockSetup:      func(repo *mock.MockSearchRepository) {},
+			expectedResult: nil,
+			expectedError:  domain.ErrRequestParams,
+		},
+		{
+			name:           "лимит больше максимального",
+			filter:         &domain.SearchFilter{Query: "пицца", Limit: MaxSearchLimit + 1},
+			mockSetup:      func(repo *mock.MockSearchRepository) {},
+			expectedResult: nil,
+			expectedError:  domain.ErrRequestParams,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mock.NewMockSearchRepository(ctrl)
+			tt.mockSetup(repo)
+
+			uc := NewSearchUsecase(repo)
+			result, err := uc.Search(context.Background(), tt.filter)
+
+			require.Equal(t, tt.expectedError, err)
+			require.Equal(t, tt.expectedResult, result)
+		})
+	}
+}
+
+func TestSearchUsecase_SearchLimits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// в каждом магазине больше позиций, чем показывается, и магазинов
+	// больше лимита
+	hits := []*domain.SearchHit{}
+	for s := 0; s < 3; s++ {
+		for i := 0; i < searchItemsPerStore+2; i++ {
+			hits = append(hits, &domain.SearchHit{
+				Store: &domain.SearchStore{ID: fmt.Sprintf("s%d", s)},
+				Item:  &domain.SearchItem{ID: fmt.Sprintf("s%d-i%d", s, i), Rank: float64(s*10 + i)},
+			})
+		}
+	}
+
+	repo := mock.NewMockSearchRepository(ctrl)
+	repo.EXPECT().
+		Search(gomock.Any(), gomock.Any(), "", searchRowsLimit).
+		Return(hits, nil)
+
+	uc := NewSearchUsecase(repo)
+	result, err := uc.Search(context.Background(), &domain.SearchFilter{Query: "пицца", Limit: 2})
+
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+	require.Equal(t, "s2", result[0].ID)
+	require.Equal(t, "s1", result[1].ID)
+	for _, store := range result {
+		require.Len(t, store.Items, searchItemsPerStore)
+	}
+	require.Equal(t, "s2-i6", result[0].Items[0].ID)
+}
@@ -0,0 +1,320 @@
+package usecase
+
+import (
+	"apple_backend/pkg/geo"
+	"apple_backend/pkg/logger"
+	"apple_backend/store_service/internal/domain"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+type StoreManageRepository interface {
+	CreateStore(ctx context.Context, ownerID string, in *domain.StoreInput) (string, error)
+	CheckStoreOwner(ctx context.Context, storeID, userID string) error
+	UpdateStore(ctx context.Context, id string, in *domain.StoreInput) error
+	DeleteStore(ctx context.Context, id string) (string, error)
+	SetStoreImage(ctx context.Context, id string, cardImg *string) (string, error)
+	GetStore(ctx context.Context, id string) (*domain.StoreAgg, error)
+}
+
+// maxStoreImageSize — предел размера карточки магазина
+const maxStoreImageSize = 5 << 20
+
+// storeImageExt — растровые расширения из check-ограничения store.card_img.
+// svg от владельцев не принимается: картинки отдаются с origin API, и
+// скрипт внутри svg выполнился бы у каждого, кто откроет файл
+var storeImageExt = map[string]bool{
+	".png": true, ".jpg": true, ".jpeg": true, ".webp": true, ".gif": true,
+}
+
+// StoreManageUsecase — изменение магазинов их владельцами
+type StoreManageUsecase struct {
+	repo      StoreManageRepository
+	uploadDir string
+}
+
+func NewStoreManageUsecase(repo StoreManageRepository, uploadDir string) *StoreManageUsecase {
+	return &StoreManageUsecase{repo: repo, uploadDir: uploadDir}
+}
+
+// CreateStore создает магазин; создатель становится его владельцем
+func (uc *StoreManageUsecase) CreateStore(ctx context.Context, actor domain.Actor, in *domain.StoreInput) (*domain.StoreAgg, error) {
+	if in.Name == nil || in.Description == nil || in.CityID == nil || in.Address == nil ||
+		in.OpenAt == nil || in.ClosedAt == nil {
+		return nil, domain.ErrRequestParams
+	}
+	if err := validateStoreInput(in); err != nil {
+		return nil, err
+	}
+
+	id, err := uc.repo.CreateStore(ctx, actor.UserID, in)
+	if err != nil {
+		return nil, err
+	}
+	return uc.repo.GetStore(ctx, id)
+}
+
+// UpdateStore меняет заданные поля магазина
+func (uc *StoreManageUsecase) UpdateStore(ctx context.Context, actor domain.Actor, id string, in *domain.StoreInput) (*domain.StoreAgg, error) {
+	if err := validateStoreInput(in); err != nil {
+		return nil, err
+	}
+	if err := uc.checkOwner(ctx, actor, id); err != nil {
+		return nil, err
+	}
+	if err := uc.repo.UpdateStore(ctx, id, in); err != nil {
+		return nil, err
+	}
+	return uc.repo.GetStore(ctx, id)
+}
+
+// DeleteStore удаляет магазин и файл его карточки
+func (uc *StoreManageUsecase) DeleteStore(ctx context.Context, actor domain.Actor, id string) error {
+	if err := uc.checkOwner(ctx, actor, id); err != nil {
+		return err
+	}
+	cardImg, err := uc.repo.DeleteStore(ctx, id)
+	if err != nil {
+		return err
+	}
+	uc.removeImage(ctx, cardImg)
+	return nil
+}
+
+// UploadStoreImage сохраняет новую карточку магазина и возвращает имя файла
+func (uc *StoreManageUsecase) UploadStoreImage(ctx context.Context, actor domain.Actor, id string, file io.Reader, filename string) (string, error) {
+	ext := strings.ToLower(filepath.Ext(filename))
+	if !storeImageExt[ext] {
+		return "", domain.ErrStoreImage
+	}
+	if err := uc.checkOwner(ctx, actor, id); err != nil {
+		return "", err
+	}
+
+	data, err := io.ReadAll(io.LimitReader(file, maxStoreImageSize+1))
+	if err != nil {
+		return "", err
+	}
+	if len(data) > maxStoreImageSize {
+		return "", domain.ErrStoreImageSize
+	}
+	if !strings.HasPrefix(http.DetectContentType(data), "image/") {
+		return "", domain.ErrStoreImage
+	}
+
+	name := fmt.Sprintf("%s_%d%s", id, time.Now().UnixNano(), ext)
+	if err := uc.writeImage(name, data); err != nil {
+		return "", err
+	}
+
+	old, err := uc.repo.SetStoreImage(ctx, id, &name)
+	if err != nil {
+		uc.removeImage(ctx, name)
+		return "", err
+	}
+	uc.removeImage(ctx, old)
+	return name, nil
+}
+
+// DeleteStoreImage убирает карточку магазина
+func (uc *StoreManageUsecase) DeleteStoreImage(ctx context.Context, actor domain.Actor, id string) error {
+	if err := uc.checkOwner(ctx, actor, id); err != nil {
+		return err
+	}
+	old, err := uc.repo.SetStoreImage(ctx, id, nil)
+	if err != nil {
+		return err
+	}
+	uc.removeImage(ctx, old)
+	return nil
+}
+
+func (uc *StoreManageUsecase) checkOwner(ctx context.Context, actor domain.Actor, id string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return domain.ErrRequestParams
+	}
+	if actor.Admin {
+		return nil
+	}
+	return uc.repo.CheckStoreOwner(ctx, id, actor.UserID)
+}
+
+// writeImage пишет файл через временный, чтобы статика не отдала его недописанным
+func (uc *StoreManageUsecase) writeImage(name string, data []byte) error {
+	if err := os.MkdirAll(uc.uploadDir, 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(uc.uploadDir, ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, bytes.NewReader(data)); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(uc.uploadDir, name))
+}
+
+// removeImage удаляет файл карточки; картинки сидов лежат там же, поэтому
+// удаляются только загруженные через API файлы вида <store_id>_<время>.<ext>
+func (uc *StoreManageUsecase) removeImage(ctx context.Context, name string) {
+	if name == "" || filepath.Base(name) != name {
+		return
+	}
+	prefix, _, ok := strings.Cut(name, "_")
+	if _, err := uuid.Parse(prefix); !ok || err != nil {
+		return
+	}
+	if err := os.Remove(filepath.Join(uc.uploadDir, name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		logger.FromContext(ctx).WarnContext(ctx, "usecase removeImage failed", slog.Any("err", err), slog.String("file", name))
+	}
+}
+
+// validateStoreInput повторяет ограничения таблицы store, чтобы вернуть
+// понятную ошибку вместо нарушения constraint
+func validateStoreInput(in *domain.StoreInput) error {
+	trim := func(v *string) {
+		if v != nil {
+			*v = strings.TrimSpace(*v)
+		}
+	}
+	trim(in.Name)
+	trim(in.Description)
+	trim(in.Address)
+	trim(in.CityID)
+	trim(in.OpenAt)
+	trim(in.ClosedAt)
+
+	if in.Name != nil && !runeLenBetween(*in.Name, 1, 50) {
+		return domain.ErrStoreName
+	}
+	if in.Description != nil && !runeLenBetween(*in.Description, 30, 2000) {
+		return domain.ErrStoreDescription
+	}
+	if in.Address != nil && !runeLenBetween(*in.Address, 1, 200) {
+		return domain.ErrStoreAddress
+	}
+	if in.CityID != nil {
+		if _, err := uuid.Parse(*in.CityID); err != nil {
+			return domain.ErrCityNotFound
+		}
+	}
+	for _, v := range []*string{in.OpenAt, in.ClosedAt} {
+		if v != nil && !validStoreTime(*v) {
+			return domain.ErrStoreHours
+		}
+	}
+
+	if err := validateStoreLocation(in.Location); err != nil {
+		return err
+	}
+
+	var err error
+	if in.TagIDs, err = uniqueIDs(in.TagIDs, domain.ErrTagNotFound); err != nil {
+		return err
+	}
+	if in.CategoryIDs, err = uniqueIDs(in.CategoryIDs, domain.ErrCategoryNotFound); err != nil {
+		return err
+	}
+	return nil
+}
+
+// maxDeliveryRadius — предел радиуса зоны доставки в метрах
+const maxDeliveryRadius = 100000
+
+// validateStoreLocation проверяет координаты и зону доставки и считает
+// прямоугольник зоны, по которому база отбирает магазины для точки
+func validateStoreLocation(loc *domain.StoreLocation) error {
+	if loc == nil {
+		return nil
+	}
+	if loc.Lat == nil || loc.Lon == nil {
+		return domain.ErrStoreLocation
+	}
+	center := geo.Point{Lat: *loc.Lat, Lon: *loc.Lon}
+	if !center.Valid() {
+		return domain.ErrStoreLocation
+	}
+
+	var zone geo.Zone
+	switch {
+	case loc.DeliveryRadius != nil && loc.DeliveryArea != nil:
+		return domain.ErrDeliveryZone
+	case loc.DeliveryRadius != nil:
+		if *loc.DeliveryRadius < 1 || *loc.DeliveryRadius > maxDeliveryRadius {
+			return domain.ErrDeliveryZone
+		}
+		zone = geo.Zone{Center: center, Radius: float64(*loc.DeliveryRadius)}
+	case loc.DeliveryArea != nil:
+		area, err := geo.ParseArea(loc.DeliveryArea)
+		if err != nil {
+			return fmt.Errorf("%w: %v", domain.ErrDeliveryZone, err)
+		}
+		zone = geo.Zone{Area: area}
+	default:
+		loc.Bounds = nil
+		return nil
+	}
+
+	// магазины отбираются по прямоугольнику без разрыва по долготе, и зона
+	// за меридианом 180° выпала бы из поиска
+	if zone.CrossesAntimeridian() {
+		return domain.ErrZoneAntimeridian
+	}
+	bounds := zone.Bounds()
+	loc.Bounds = &bounds
+	return nil
+}
+
+func runeLenBetween(s string, min, max int) bool {
+	n := utf8.RuneCountInString(s)
+	return n >= min && n <= max
+}
+
+func validStoreTime(s string) bool {
+	for _, layout := range []string{"15:04", "15:04:05"} {
+		if _, err := time.Parse(layout, s); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// uniqueIDs проверяет формат id и убирает повторы, сохраняя nil
+func uniqueIDs(ids []string, invalid error) ([]string, error) {
+	if ids == nil {
+		return nil, nil
+	}
+	seen := make(map[string]bool, len(ids))
+	out := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if _, err := uuid.Parse(id); err != nil {
+			return nil, invalid
+		}
+		if !seen[id] {
+			seen[id] = true
+			out = append(out, id)
+		}
+	}
+	return out, nil
+}
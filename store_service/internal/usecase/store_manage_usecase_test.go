@@ -0,0 +1,396 @@
+package usecase
+
+import (
+	"apple_backend/store_service/internal/domain"
+	"apple_backend/store_service/internal/usecase/mock"
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	manageStoreID = "00000000-0000-0000-0000-000000000001"
+	manageOwnerID = "00000000-0000-0000-0000-000000000002"
+	manageCityID  = "00000000-0000-0000-0000-000000000003"
+)
+
+func strPtr(s string) *string { return &s }
+
+func validStoreInput() *domain.StoreInput {
+	return &domain.StoreInput{
+		Name:        strPtr("Store"),
+		Description: strPtr(strings.Repeat("описание ", 4)),
+		CityID:      strPtr(manageCityID),
+		Address:     strPtr("Address"),
+		OpenAt:      strPtr("09:00"),
+		ClosedAt:    strPtr("21:00"),
+	}
+}
+
+func TestStoreManageUsecase_CreateStore(t *testing.T) {
+	type testCase struct {
+		name          string
+		input         func() *domain.StoreInput
+		mockSetup     func(repo *mock.MockStoreManageRepository)
+		expectedError error
+	}
+
+	store := &domain.StoreAgg{ID: manageStoreID, Name: "Store"}
+	actor := domain.Actor{UserID: manageOwnerID}
+
+	tests := []testCase{
+		{
+			name:  "успешный вызов",
+			input: validStoreInput,
+			mockSetup: func(repo *mock.MockStoreManageRepository) {
+				repo.EXPECT().CreateStore(gomock.Any(), manageOwnerID, gomock.Any()).Return(manageStoreID, nil)
+				repo.EXPECT().GetStore(gomock.Any(), manageStoreID).Return(store, nil)
+			},
+		},
+		{
+			name: "нет обязательного поля",
+			input: func() *domain.StoreInput {
+				in := validStoreInput()
+				in.Address = nil
+				return in
+			},
+			expectedError: domain.ErrRequestParams,
+		},
+		{
+			name: "название длиннее 50 символов",
+			input: func() *domain.StoreInput {
+				in := validStoreInput()
+				in.Name = strPtr(strings.Repeat("я", 51))
+				return in
+			},
+			expectedError: domain.ErrStoreName,
+		},
+		{
+			name: "описание короче 30 символов",
+			input: func() *domain.StoreInput {
+				in := validStoreInput()
+				in.Description = strPtr("   коротко   ")
+				return in
+			},
+			expectedError: domain.ErrStoreDescription,
+		},
+		{
+			name: "адрес длиннее 200 символов",
+			input: func() *domain.StoreInput {
+				in := validStoreInput()
+				in.Address = strPtr(strings.Repeat("a", 201))
+				return in
+			},
+			expectedError: domain.ErrStoreAddress,
+		},
+		{
+			name: "некорректный город",
+			input: func() *domain.StoreInput {
+				in := validStoreInput()
+				in.CityID = strPtr("moscow")
+				return in
+			},
+			expectedError: domain.ErrCityNotFound,
+		},
+		{
+			name: "некорректное время работы",
+			input: func() *domain.StoreInput {
+				in := validStoreInput()
+				in.ClosedAt = strPtr("25:00")
+				return in
+			},
+			expectedError: domain.ErrStoreHours,
+		},
+		{
+			name: "некорректный тег",
+			input: func() *domain.StoreInput {
+				in := validStoreInput()
+				in.TagIDs = []string{"tag"}
+				return in
+			},
+			expectedError: domain.ErrTagNotFound,
+		},
+		{
+			name: "радиус заходит за меридиан 180°",
+			input: func() *domain.StoreInput {
+				in := validStoreInput()
+				lat, lon, radius := 64.73, 178.5, 100000
+				in.Location = &domain.StoreLocation{Lat: &lat, Lon: &lon, DeliveryRadius: &radius}
+				return in
+			},
+			expectedError: domain.ErrZoneAntimeridian,
+		},
+		{
+			name: "многоугольник пересекает меридиан 180°",
+			input: func() *domain.StoreInput {
+				in := validStoreInput()
+				lat, lon := 64.73, 179.5
+				in.Location = &domain.StoreLocation{
+					Lat:          &lat,
+					Lon:          &lon,
+					DeliveryArea: []byte(`{"type": "Polygon", "coordinates": [[[179, 64], [-179, 64], [-179, 65], [179, 65], [179, 64]]]}`),
+				}
+				return in
+			},
+			expectedError: domain.ErrZoneAntimeridian,
+		},
+		{
+			name:  "ошибка выполнения",
+			input: validStoreInput,
+			mockSetup: func(repo *mock.MockStoreManageRepository) {
+				repo.EXPECT().CreateStore(gomock.Any(), manageOwnerID, gomock.Any()).Return("", domain.ErrInternalServer)
+			},
+			expectedError: domain.ErrInternalServer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock.NewMockStoreManageRepository(ctrl)
+			if tt.mockSetup != nil {
+				tt.mockSetup(mockRepo)
+			}
+
+			uc := NewStoreManageUsecase(mockRepo, t.TempDir())
+
+			result, err := uc.CreateStore(context.Background(), actor, tt.input())
+
+			require.Equal(t, tt.expectedError, err)
+			if tt.expectedError == nil {
+				require.Equal(t, store, result)
+			}
+		})
+	}
+}
+
+func TestStoreManageUsecase_CreateStore_TrimsInput(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockStoreManageRepository(ctrl)
+	uc := NewStoreManageUsecase(mockRepo, t.TempDir())
+
+	in := validStoreInput()
+	in.Name = strPtr("  Store  ")
+	in.TagIDs = []string{manageCityID, manageCityID}
+
+	mockRepo.EXPECT().
+		CreateStore(gomock.Any(), manageOwnerID, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, in *domain.StoreInput) (string, error) {
+			require.Equal(t, "Store", *in.Name)
+			require.Equal(t, []string{manageCityID}, in.TagIDs)
+			return manageStoreID, nil
+		})
+	mockRepo.EXPECT().GetStore(gomock.Any(), manageStoreID).Return(&domain.StoreAgg{ID: manageStoreID}, nil)
+
+	_, err := uc.CreateStore(context.Background(), domain.Actor{UserID: manageOwnerID}, in)
+	require.NoError(t, err)
+}
+
+func TestStoreManageUsecase_UpdateStore(t *testing.T) {
+	type testCase struct {
+		name          string
+		actor         domain.Actor
+		id            string
+		input         *domain.StoreInput
+		mockSetup     func(repo *mock.MockStoreManageRepository)
+		expectedError error
+	}
+
+	store := &domain.StoreAgg{ID: manageStoreID, Name: "New"}
+
+	tests := []testCase{
+		{
+			name:  "успешный вызов владельцем",
+			actor: domain.Actor{UserID: manageOwnerID},
+			id:    manageStoreID,
+			input: &domain.StoreInput{Name: strPtr("New")},
+			mockSetup: func(repo *mock.MockStoreManageRepository) {
+				repo.EXPECT().CheckStoreOwner(gomock.Any(), manageStoreID, manageOwnerID).Return(nil)
+				repo.EXPECT().UpdateStore(gomock.Any(), manageStoreID, gomock.Any()).Return(nil)
+				repo.EXPECT().GetStore(gomock.Any(), manageStoreID).Return(store, nil)
+			},
+		},
+		{
+			name:  "не владелец",
+			actor: domain.Actor{UserID: manageOwnerID},
+			id:    manageStoreID,
+			input: &domain.StoreInput{Name: strPtr("New")},
+			mockSetup: func(repo *mock.MockStoreManageRepository) {
+				repo.EXPECT().CheckStoreOwner(gomock.Any(), manageStoreID, manageOwnerID).Return(domain.ErrForbidden)
+			},
+			expectedError: domain.ErrForbidden,
+		},
+		{
+			name:  "администратор без проверки владельца",
+			actor: domain.Actor{UserID: manageOwnerID, Admin: true},
+			id:    manageStoreID,
+			input: &domain.StoreInput{Name: strPtr("New")},
+			mockSetup: func(repo *mock.MockStoreManageRepository) {
+				repo.EXPECT().UpdateStore(gomock.Any(), manageStoreID, gomock.Any()).Return(nil)
+				repo.EXPECT().GetStore(gomock.Any(), manageStoreID).Return(store, nil)
+			},
+		},
+		{
+			name:          "некорректный id",
+			actor:         domain.Actor{UserID: manageOwnerID},
+			id:            "store",
+			input:         &domain.StoreInput{Name: strPtr("New")},
+			expectedError: domain.ErrRequestParams,
+		},
+		{
+			name:          "пустое название",
+			actor:         domain.Actor{UserID: manageOwnerID},
+			id:            manageStoreID,
+			input:         &domain.StoreInput{Name: strPtr("   ")},
+			expectedError: domain.ErrStoreName,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock.NewMockStoreManageRepository(ctrl)
+			if tt.mockSetup != nil {
+				tt.mockSetup(mockRepo)
+			}
+
+			uc := NewStoreManageUsecase(mockRepo, t.TempDir())
+
+			result, err := uc.UpdateStore(context.Background(), tt.actor, tt.id, tt.input)
+
+			require.Equal(t, tt.expectedError, err)
+			if tt.expectedError == nil {
+				require.Equal(t, store, result)
+			}
+		})
+	}
+}
+
+func TestStoreManageUsecase_DeleteStore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockStoreManageRepository(ctrl)
+	dir := t.TempDir()
+	uc := NewStoreManageUsecase(mockRepo, dir)
+	actor := domain.Actor{UserID: manageOwnerID}
+
+	// чужой магазин не удаляется
+	mockRepo.EXPECT().CheckStoreOwner(gomock.Any(), manageStoreID, manageOwnerID).Return(domain.ErrForbidden)
+	require.Equal(t, domain.ErrForbidden, uc.DeleteStore(context.Background(), actor, manageStoreID))
+
+	// у своего удаляется и загруженная карточка
+	card := manageStoreID + "_1.png"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, card), []byte("png"), 0o644))
+	mockRepo.EXPECT().CheckStoreOwner(gomock.Any(), manageStoreID, manageOwnerID).Return(nil)
+	mockRepo.EXPECT().DeleteStore(gomock.Any(), manageStoreID).Return(card, nil)
+
+	require.NoError(t, uc.DeleteStore(context.Background(), actor, manageStoreID))
+	_, err := os.Stat(filepath.Join(dir, card))
+	require.True(t, os.IsNotExist(err))
+}
+
+// pngHeader — сигнатура png, по которой http.DetectContentType узнает картинку
+var pngHeader = []byte("\x89PNG\r\n\x1a\n")
+
+func TestStoreManageUsecase_UploadStoreImage(t *testing.T) {
+	type testCase struct {
+		name          string
+		filename      string
+		data          []byte
+		mockSetup     func(repo *mock.MockStoreManageRepository)
+		expectedError error
+	}
+
+	owner := func(repo *mock.MockStoreManageRepository) {
+		repo.EXPECT().CheckStoreOwner(gomock.Any(), manageStoreID, manageOwnerID).Return(nil)
+	}
+
+	tests := []testCase{
+		{
+			name:     "успешный вызов",
+			filename: "card.PNG",
+			data:     pngHeader,
+			mockSetup: func(repo *mock.MockStoreManageRepository) {
+				owner(repo)
+				repo.EXPECT().SetStoreImage(gomock.Any(), manageStoreID, gomock.Any()).Return("", nil)
+			},
+		},
+		{
+			name:          "недопустимое расширение",
+			filename:      "card.bmp",
+			data:          pngHeader,
+			expectedError: domain.ErrStoreImage,
+		},
+		{
+			name:          "svg не принимается",
+			filename:      "card.svg",
+			data:          []byte(`<svg xmlns="http://www.w3.org/2000/svg"><script>alert(1)</script></svg>`),
+			expectedError: domain.ErrStoreImage,
+		},
+		{
+			name:          "содержимое не картинка",
+			filename:      "card.png",
+			data:          []byte("<html></html>"),
+			mockSetup:     owner,
+			expectedError: domain.ErrStoreImage,
+		},
+		{
+			name:          "файл больше 5 МБ",
+			filename:      "card.png",
+			data:          append(append([]byte{}, pngHeader...), make([]byte, maxStoreImageSize)...),
+			mockSetup:     owner,
+			expectedError: domain.ErrStoreImageSize,
+		},
+		{
+			name:     "не владелец",
+			filename: "card.png",
+			data:     pngHeader,
+			mockSetup: func(repo *mock.MockStoreManageRepository) {
+				repo.EXPECT().CheckStoreOwner(gomock.Any(), manageStoreID, manageOwnerID).Return(domain.ErrForbidden)
+			},
+			expectedError: domain.ErrForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock.NewMockStoreManageRepository(ctrl)
+			if tt.mockSetup != nil {
+				tt.mockSetup(mockRepo)
+			}
+
+			dir := t.TempDir()
+			uc := NewStoreManageUsecase(mockRepo, dir)
+
+			name, err := uc.UploadStoreImage(context.Background(), domain.Actor{UserID: manageOwnerID},
+				manageStoreID, bytes.NewReader(tt.data), tt.filename)
+
+			require.Equal(t, tt.expectedError, err)
+			if tt.expectedError != nil {
+				entries, _ := os.ReadDir(dir)
+				require.Empty(t, entries)
+				return
+			}
+			require.True(t, strings.HasPrefix(name, manageStoreID+"_"))
+			require.True(t, strings.HasSuffix(name, ".png"))
+			_, err = os.Stat(filepath.Join(dir, name))
+			require.NoError(t, err)
+		})
+	}
+}
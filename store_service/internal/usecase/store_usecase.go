@@ -1,15 +1,18 @@
 package usecase
 
 import (
+	"apple_backend/pkg/geo"
+	"apple_backend/pkg/logger"
 	"apple_backend/store_service/internal/domain"
 	"context"
+	"log/slog"
+	"sort"
 )
 
 type StoreRepository interface {
 	GetStores(ctx context.Context, filter *domain.StoreFilter) ([]*domain.StoreAgg, error)
 	GetStore(ctx context.Context, id string) (*domain.StoreAgg, error)
 	GetStoreReview(ctx context.Context, id string) ([]*domain.StoreReview, error)
-	CreateStore(ctx context.Context, store *domain.Store) error
 	GetCities(ctx context.Context) ([]*domain.City, error)
 	GetTags(ctx context.Context) ([]*domain.StoreTag, error)
 }
@@ -22,21 +25,6 @@ func NewStoreUsecase(repo StoreRepository) *StoreUsecase {
 	return &StoreUsecase{repo: repo}
 }
 
-func (uc *StoreUsecase) CreateStore(ctx context.Context,
-	name, description, cityID, address, cardImg, openAt, closedAt string, rating float64) error {
-	store := &domain.Store{
-		Name:        name,
-		Description: description,
-		CityID:      cityID,
-		Address:     address,
-		CardImg:     cardImg,
-		OpenAt:      openAt,
-		ClosedAt:    closedAt,
-		Rating:      rating,
-	}
-	return uc.repo.CreateStore(ctx, store)
-}
-
 func (uc *StoreUsecase) GetStore(ctx context.Context, id string) (*domain.StoreAgg, error) {
 	return uc.repo.GetStore(ctx, id)
 }
@@ -53,15 +41,64 @@ func (uc *StoreUsecase) GetStores(ctx context.Context, filter *domain.StoreFilte
 	if filter.Sorted != "" && !sortable[filter.Sorted] {
 		return nil, domain.ErrRequestParams
 	}
+	// по точке магазины всегда сортируются по расстоянию
+	if filter.Point != nil && (!filter.Point.Valid() || filter.Sorted != "") {
+		return nil, domain.ErrRequestParams
+	}
 
 	stores, err := uc.repo.GetStores(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
 
+	if filter.Point != nil {
+		return nearestStores(ctx, stores, filter), nil
+	}
 	return stores, nil
 }
 
+// nearestStores оставляет магазины, в зону доставки которых попадает точка
+// фильтра, сортирует их по расстоянию и возвращает страницу после LastID
+func nearestStores(ctx context.Context, stores []*domain.StoreAgg, filter *domain.StoreFilter) []*domain.StoreAgg {
+	point := *filter.Point
+
+	found := make([]*domain.StoreAgg, 0, len(stores))
+	for _, s := range stores {
+		zone, ok, err := s.Zone()
+		if err != nil {
+			// зона проверяется при сохранении, сюда попадают только правки в обход API
+			logger.FromContext(ctx).WarnContext(ctx, "usecase GetStores invalid delivery zone",
+				slog.Any("err", err), slog.String("store_id", s.ID))
+			continue
+		}
+		if !ok || s.Lat == nil || s.Lon == nil || !zone.Contains(point) {
+			continue
+		}
+		distance := geo.Distance(point, geo.Point{Lat: *s.Lat, Lon: *s.Lon})
+		s.Distance = &distance
+		found = append(found, s)
+	}
+	sort.Slice(found, func(i, j int) bool {
+		if *found[i].Distance != *found[j].Distance {
+			return *found[i].Distance < *found[j].Distance
+		}
+		return found[i].ID < found[j].ID
+	})
+
+	start := 0
+	if filter.LastID != "" {
+		// магазина со страницы уже нет в выдаче — дальше листать нечего
+		start = len(found)
+		for i, s := range found {
+			if s.ID == filter.LastID {
+				start = i + 1
+				break
+			}
+		}
+	}
+	return found[start:min(start+filter.Limit, len(found))]
+}
+
 func (uc *StoreUsecase) GetCities(ctx context.Context) ([]*domain.City, error) {
 	return uc.repo.GetCities(ctx)
 }
@@ -19,7 +19,6 @@ func TestStoreUsecase_GetStore(t *testing.T) {
 	type testCase struct {
 		name           string
 		input          args
-		repoOutput     []*domain.Store
 		expectedResult *domain.StoreAgg
 		expectedError  error
 	}
@@ -31,20 +30,6 @@ func TestStoreUsecase_GetStore(t *testing.T) {
 				ctx: context.Background(),
 				id:  "00000000-0000-0000-0000-000000000001",
 			},
-			repoOutput: []*domain.Store{
-				{
-					ID:          "00000000-0000-0000-0000-000000000001",
-					Name:        "Store",
-					Description: "Description",
-					CityID:      "10000000-0000-0000-0000-000000000001",
-					Address:     "Address",
-					CardImg:     "CardImg",
-					Rating:      3,
-					TagID:       "10000000-0000-0000-0000-000000000001",
-					OpenAt:      "OpenAt",
-					ClosedAt:    "ClosedAt",
-				},
-			},
 			expectedResult: &domain.StoreAgg{
 				ID:          "00000000-0000-0000-0000-000000000001",
 				Name:        "Store",
@@ -65,32 +50,6 @@ func TestStoreUsecase_GetStore(t *testing.T) {
 				ctx: context.Background(),
 				id:  "00000000-0000-0000-0000-000000000001",
 			},
-			repoOutput: []*domain.Store{
-				{
-					ID:          "00000000-0000-0000-0000-000000000001",
-					Name:        "Store",
-					Description: "Description",
-					CityID:      "10000000-0000-0000-0000-000000000001",
-					Address:     "Address",
-					CardImg:     "CardImg",
-					Rating:      3,
-					TagID:       "10000000-0000-0000-0000-000000000001",
-					OpenAt:      "OpenAt",
-					ClosedAt:    "ClosedAt",
-				},
-				{
-					ID:          "00000000-0000-0000-0000-000000000001",
-					Name:        "Store",
-					Description: "Description",
-					CityID:      "10000000-0000-0000-0000-000000000001",
-					Address:     "Address",
-					CardImg:     "CardImg",
-					Rating:      3,
-					TagID:       "10000000-0000-0000-0000-000000000002",
-					OpenAt:      "OpenAt",
-					ClosedAt:    "ClosedAt",
-				},
-			},
 			expectedResult: &domain.StoreAgg{
 				ID:          "00000000-0000-0000-0000-000000000001",
 				Name:        "Store",
@@ -128,7 +87,7 @@ func TestStoreUsecase_GetStore(t *testing.T) {
 
 			mockRepo.EXPECT().
 				GetStore(tt.input.ctx, tt.input.id).
-				Return(tt.repoOutput, tt.expectedError)
+				Return(tt.expectedResult, tt.expectedError)
 
 			store, err := uc.GetStore(tt.input.ctx, tt.input.id)
 
@@ -147,8 +106,7 @@ func TestStoreUsecase_GetStores(t *testing.T) {
 	type testCase struct {
 		name           string
 		input          args
-		mockSetup      func(mock *mock.MockStoreRepository, out []*domain.Store, err error)
-		repoOutput     []*domain.Store
+		mockSetup      func(mock *mock.MockStoreRepository, out []*domain.StoreAgg, err error)
 		expectedResult []*domain.StoreAgg
 		expectedError  error
 	}
@@ -162,37 +120,11 @@ func TestStoreUsecase_GetStores(t *testing.T) {
 					Limit: 2,
 				},
 			},
-			mockSetup: func(mock *mock.MockStoreRepository, out []*domain.Store, err error) {
+			mockSetup: func(mock *mock.MockStoreRepository, out []*domain.StoreAgg, err error) {
 				mock.EXPECT().
 					GetStores(context.Background(), &domain.StoreFilter{Limit: 2}).
 					Return(out, err)
 			},
-			repoOutput: []*domain.Store{
-				{
-					ID:          "00000000-0000-0000-0000-000000000001",
-					Name:        "Store",
-					Description: "Description",
-					CityID:      "10000000-0000-0000-0000-000000000001",
-					Address:     "Address",
-					CardImg:     "CardImg",
-					Rating:      3,
-					TagID:       "00000000-0000-0000-0000-000000000001",
-					OpenAt:      "OpenAt",
-					ClosedAt:    "ClosedAt",
-				},
-				{
-					ID:          "00000000-0000-0000-0000-000000000002",
-					Name:        "Store",
-					Description: "Description",
-					CityID:      "10000000-0000-0000-0000-000000000001",
-					Address:     "Address",
-					CardImg:     "CardImg",
-					Rating:      3,
-					TagID:       "00000000-0000-0000-0000-000000000001",
-					OpenAt:      "OpenAt",
-					ClosedAt:    "ClosedAt",
-				},
-			},
 			expectedResult: []*domain.StoreAgg{
 				{
 					ID:          "00000000-0000-0000-0000-000000000001",
@@ -229,37 +161,11 @@ func TestStoreUsecase_GetStores(t *testing.T) {
 					Limit: 2,
 				},
 			},
-			mockSetup: func(mock *mock.MockStoreRepository, out []*domain.Store, err error) {
+			mockSetup: func(mock *mock.MockStoreRepository, out []*domain.StoreAgg, err error) {
 				mock.EXPECT().
 					GetStores(context.Background(), &domain.StoreFilter{Limit: 2}).
 					Return(out, err)
 			},
-			repoOutput: []*domain.Store{
-				{
-					ID:          "00000000-0000-0000-0000-000000000001",
-					Name:        "Store",
-					Description: "Description",
-					CityID:      "10000000-0000-0000-0000-000000000001",
-					Address:     "Address",
-					CardImg:     "CardImg",
-					Rating:      3,
-					TagID:       "00000000-0000-0000-0000-000000000001",
-					OpenAt:      "OpenAt",
-					ClosedAt:    "ClosedAt",
-				},
-				{
-					ID:          "00000000-0000-0000-0000-000000000001",
-					Name:        "Store",
-					Description: "Description",
-					CityID:      "10000000-0000-0000-0000-000000000001",
-					Address:     "Address",
-					CardImg:     "CardImg",
-					Rating:      3,
-					TagID:       "00000000-0000-0000-0000-000000000002",
-					OpenAt:      "OpenAt",
-					ClosedAt:    "ClosedAt",
-				},
-			},
 			expectedResult: []*domain.StoreAgg{
 				{
 					ID:          "00000000-0000-0000-0000-000000000001",
@@ -288,8 +194,7 @@ func TestStoreUsecase_GetStores(t *testing.T) {
 					Limit:  2,
 				},
 			},
-			mockSetup:      func(mock *mock.MockStoreRepository, out []*domain.Store, err error) {},
-			repoOutput:     nil,
+			mockSetup:      func(mock *mock.MockStoreRepository, out []*domain.StoreAgg, err error) {},
 			expectedResult: nil,
 			expectedError:  domain.ErrRequestParams,
 		},
@@ -301,8 +206,7 @@ func TestStoreUsecase_GetStores(t *testing.T) {
 					Limit: -10,
 				},
 			},
-			mockSetup:      func(mock *mock.MockStoreRepository, out []*domain.Store, err error) {},
-			repoOutput:     nil,
+			mockSetup:      func(mock *mock.MockStoreRepository, out []*domain.StoreAgg, err error) {},
 			expectedResult: nil,
 			expectedError:  domain.ErrRequestParams,
 		},
@@ -314,12 +218,11 @@ func TestStoreUsecase_GetStores(t *testing.T) {
 					Limit: 2,
 				},
 			},
-			mockSetup: func(mock *mock.MockStoreRepository, out []*domain.Store, err error) {
+			mockSetup: func(mock *mock.MockStoreRepository, out []*domain.StoreAgg, err error) {
 				mock.EXPECT().
 					GetStores(context.Background(), &domain.StoreFilter{Limit: 2}).
 					Return(out, err)
 			},
-			repoOutput:     nil,
 			expectedResult: nil,
 			expectedError:  domain.ErrInternalServer,
 		},
@@ -333,7 +236,7 @@ func TestStoreUsecase_GetStores(t *testing.T) {
 			defer ctrl.Finish()
 
 			mockRepo := mock.NewMockStoreRepository(ctrl)
-			tt.mockSetup(mockRepo, tt.repoOutput, tt.expectedError)
+			tt.mockSetup(mockRepo, tt.expectedResult, tt.expectedError)
 			uc := NewStoreUsecase(mockRepo)
 
 			store, err := uc.GetStores(tt.input.ctx, tt.input.filter)
@@ -344,81 +247,6 @@ func TestStoreUsecase_GetStores(t *testing.T) {
 	}
 }
 
-func TestStoreUsecase_CreateStore(t *testing.T) {
-	type args struct {
-		ctx         context.Context
-		name        string
-		description string
-		cityID      string
-		address     string
-		cardImg     string
-		openAt      string
-		closedAt    string
-		rating      float64
-	}
-
-	type testCase struct {
-		name          string
-		input         args
-		expectedError error
-	}
-	tests := []testCase{
-		{
-			name: "CreateStore успешный вызов",
-			input: args{
-				ctx:         context.Background(),
-				name:        "Store",
-				description: "Description",
-				cityID:      "10000000-0000-0000-0000-000000000001",
-				address:     "Address",
-				cardImg:     "CardImg",
-				openAt:      "OpenAt",
-				closedAt:    "ClosedAt",
-				rating:      3,
-			},
-			expectedError: nil,
-		},
-		{
-			name: "CreateStore ошибка выполнения",
-			input: args{
-				ctx:         context.Background(),
-				name:        "Store",
-				description: "Description",
-				cityID:      "10000000-0000-0000-0000-000000000001",
-				address:     "Address",
-				cardImg:     "CardImg",
-				openAt:      "OpenAt",
-				closedAt:    "ClosedAt",
-				rating:      3,
-			},
-			expectedError: domain.ErrInternalServer,
-		},
-	}
-
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
-
-	mockRepo := mock.NewMockStoreRepository(ctrl)
-
-	uc := NewStoreUsecase(mockRepo)
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-
-			mockRepo.EXPECT().
-				CreateStore(tt.input.ctx, &domain.Store{Name: tt.input.name, Description: tt.input.description,
-					CityID: tt.input.cityID, Address: tt.input.address, CardImg: tt.input.cardImg, Rating: tt.input.rating,
-					OpenAt: tt.input.closedAt, ClosedAt: tt.input.openAt}).
-				Return(tt.expectedError)
-
-			err := uc.CreateStore(tt.input.ctx, tt.input.name, tt.input.description, tt.input.cityID,
-				tt.input.address, tt.input.cardImg, tt.input.closedAt, tt.input.openAt, tt.input.rating)
-
-			require.Equal(t, tt.expectedError, err)
-		})
-	}
-}
-
 func TestStoreUsecase_GetCities(t *testing.T) {
 	type testCase struct {
 		name           string
@@ -0,0 +1,169 @@
+package usecase
+
+import (
+	"apple_backend/pkg/logger"
+	"apple_backend/pkg/suggest"
+	"apple_backend/store_service/internal/domain"
+	"context"
+	"log/slog"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SuggestRepository interface {
+	GetCatalogVersion(ctx context.Context) (int64, error)
+	GetCatalog(ctx context.Context) (*domain.Catalog, error)
+}
+
+const (
+	// suggestTopK — сколько подсказок каждого вида хранит узел индекса
+	suggestTopK = 10
+	// DefaultSuggestLimit и MaxSuggestLimit ограничивают подсказки одного вида
+	DefaultSuggestLimit = 5
+	MaxSuggestLimit     = suggestTopK
+	// suggestMaxAge — индекс перестраивается и без изменений каталога, чтобы
+	// подтянуть популярность по новым заказам
+	suggestMaxAge = 15 * time.Minute
+)
+
+// SuggestUsecase отвечает на подсказки из индекса в памяти. Индекс
+// подменяется целиком, запросы во время перестройки читают прежний.
+type SuggestUsecase struct {
+	repo SuggestRepository
+
+	index atomic.Pointer[suggest.Index]
+
+	// mu не дает двум перестройкам идти одновременно
+	mu      sync.Mutex
+	version int64
+	builtAt time.Time
+}
+
+func NewSuggestUsecase(repo SuggestRepository) *SuggestUsecase {
+	return &SuggestUsecase{repo: repo}
+}
+
+// Suggest возвращает подсказки для начала запроса prefix. cityID
+// ограничивает подсказки городом, пустой — все города.
+func (uc *SuggestUsecase) Suggest(ctx context.Context, prefix, cityID string, limit int) (*domain.Suggestions, error) {
+	if cityID != "" {
+		if _, err := uuid.Parse(cityID); err != nil {
+			return nil, domain.ErrRequestParams
+		}
+	}
+	if limit <= 0 {
+		limit = DefaultSuggestLimit
+	}
+	if limit > MaxSuggestLimit || len(prefix) > 200 {
+		return nil, domain.ErrRequestParams
+	}
+
+	// до первой сборки индекса подсказок просто нет
+	ix := uc.index.Load()
+	lookup := func(kind string) []*domain.Suggestion {
+		entries := ix.Lookup(cityID, kind, prefix, limit)
+		out := make([]*domain.Suggestion, 0, len(entries))
+		for _, e := range entries {
+			out = append(out, &domain.Suggestion{ID: e.ID, Text: e.Text})
+		}
+		return out
+	}
+	return &domain.Suggestions{
+		Stores: lookup(domain.SuggestStore),
+		Items:  lookup(domain.SuggestItem),
+		Tags:   lookup(domain.SuggestTag),
+		Types:  lookup(domain.SuggestType),
+	}, nil
+}
+
+// Refresh перестраивает индекс, если каталог изменился или индекс устарел.
+// Возвращает true, если индекс перестроен.
+func (uc *SuggestUsecase) Refresh(ctx context.Context) (bool, error) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	if uc.index.Load() != nil && time.Since(uc.builtAt) < suggestMaxAge {
+		version, err := uc.repo.GetCatalogVersion(ctx)
+		if err != nil {
+			return false, err
+		}
+		if version == uc.version {
+			return false, nil
+		}
+	}
+
+	start := time.Now()
+	catalog, err := uc.repo.GetCatalog(ctx)
+	if err != nil {
+		return false, err
+	}
+	uc.index.Store(buildSuggestIndex(catalog))
+	uc.version = catalog.Version
+	uc.builtAt = time.Now()
+
+	logger.FromContext(ctx).InfoContext(ctx, "suggest index rebuilt",
+		slog.Int64("version", catalog.Version),
+		slog.Int("stores_count", len(catalog.Stores)),
+		slog.Int("items_count", len(catalog.Items)),
+		slog.Duration("took", time.Since(start)))
+	return true, nil
+}
+
+// storeWeight — вес магазина в подсказках: рейтинг и число заказов.
+// Логарифм не дает нескольким самым заказываемым магазинам забрать все
+// подсказки.
+func storeWeight(rating float64, orders int) float64 {
+	return (1 + rating) * math.Log(2+float64(orders))
+}
+
+func buildSuggestIndex(catalog *domain.Catalog) *suggest.Index {
+	b := suggest.NewBuilder(suggestTopK)
+
+	stores := make(map[string]*domain.CatalogStore, len(catalog.Stores))
+	for _, s := range catalog.Stores {
+		stores[s.ID] = s
+		b.Add(s.CityID, suggest.Entry{
+			Kind:   domain.SuggestStore,
+			ID:     s.ID,
+			Text:   s.Name,
+			Weight: storeWeight(s.Rating, s.Orders),
+		})
+	}
+
+	// товар, тег и тип весят столько, сколько в сумме магазины, где они есть,
+	// в пределах города
+	for _, i := range catalog.Items {
+		s, ok := stores[i.StoreID]
+		if !ok {
+			continue
+		}
+		b.Add(s.CityID, suggest.Entry{
+			Kind:   domain.SuggestItem,
+			ID:     i.ItemID,
+			Text:   i.Name,
+			Weight: storeWeight(s.Rating, i.Ordered),
+		})
+	}
+	addLinks := func(kind string, links []*domain.CatalogLink) {
+		for _, l := range links {
+			s, ok := stores[l.StoreID]
+			if !ok {
+				continue
+			}
+			b.Add(s.CityID, suggest.Entry{
+				Kind:   kind,
+				ID:     l.ID,
+				Text:   l.Name,
+				Weight: storeWeight(s.Rating, s.Orders),
+			})
+		}
+	}
+	addLinks(domain.SuggestTag, catalog.Tags)
+	addLinks(domain.SuggestType, catalog.Types)
+
+	return b.Build()
+}
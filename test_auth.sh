@@ -4,7 +4,7 @@
 BASE_URL="http://localhost:8082/api/v0"
 COOKIES_FILE="/tmp/test_cookies.txt"
 EMAIL="testuser@example.com"
-PASSWORD="Password123!"
+PASSWORD="Harb0r-Lantern#7"
 
 # Colors for output
 RED='\033[0;31m'
@@ -9,7 +9,7 @@ COOKIES_FILE="/tmp/order_complete_cookies.txt"
 
 # Test credentials
 EMAIL="testuser@example.com"
-PASSWORD="Password123!"
+PASSWORD="Harb0r-Lantern#7"
 
 # Test store_item_id for cart operations
 STORE_ITEM_1="5482f1c6-8028-4e0b-8a77-bad73e89c692"  # Ролл "Филадельфия" 420.00
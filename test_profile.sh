@@ -5,7 +5,7 @@ AUTH_URL="http://localhost:8082/api/v0"    # Auth service
 PROFILE_URL="http://localhost:8081/api/v0" # Profile service
 COOKIES_FILE="/tmp/profile_cookies.txt"
 EMAIL="testuser@example.com"
-PASSWORD="Password123!"
+PASSWORD="Harb0r-Lantern#7"
 AVATAR_FILE="./img.jpg"
 
 # Colors